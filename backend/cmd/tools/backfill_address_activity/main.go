@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"flowscan-clone/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Backfills the transfer-participant roles (FT_/NFT_ SENDER and RECEIVER) of
+// app.address_transactions from app.ft_transfers and app.nft_transfers, for
+// history indexed before the token worker wrote them. With --bench-address it
+// instead times the single-table read against the legacy UNION for one
+// address and reports whether both return the same transactions.
+func main() {
+	var (
+		startHeight  uint64
+		endHeight    uint64
+		batchBlocks  uint64
+		benchAddress string
+		benchLimit   int
+	)
+
+	flag.Uint64Var(&startHeight, "start", getEnvUint("BACKFILL_START", 0), "start block height (inclusive)")
+	flag.Uint64Var(&endHeight, "end", getEnvUint("BACKFILL_END", 0), "end block height (inclusive)")
+	flag.Uint64Var(&batchBlocks, "batch", getEnvUint("BACKFILL_BATCH_BLOCKS", 100000), "blocks per batch")
+	flag.StringVar(&benchAddress, "bench-address", os.Getenv("BENCH_ADDRESS"), "compare UNION vs address_transactions for this address and exit")
+	flag.IntVar(&benchLimit, "bench-limit", 200, "rows to read per query in bench mode")
+	flag.Parse()
+
+	dbURL := os.Getenv("DB_URL")
+	if dbURL == "" {
+		log.Fatal("DB_URL is required")
+	}
+	ctx := context.Background()
+
+	if benchAddress != "" {
+		conn, err := pgx.Connect(ctx, dbURL)
+		if err != nil {
+			log.Fatalf("connect: %v", err)
+		}
+		defer conn.Close(ctx)
+		runBench(ctx, conn, benchAddress, benchLimit)
+		return
+	}
+
+	if startHeight == 0 || endHeight == 0 || endHeight < startHeight {
+		log.Fatal("BACKFILL_START and BACKFILL_END are required (start <= end)")
+	}
+	if batchBlocks == 0 {
+		batchBlocks = 100000
+	}
+
+	repo, err := repository.NewRepository(dbURL)
+	if err != nil {
+		log.Fatalf("failed to connect to db: %v", err)
+	}
+	defer repo.Close()
+
+	log.Printf("address activity backfill start=%d end=%d batch=%d", startHeight, endHeight, batchBlocks)
+	started := time.Now()
+	var total int64
+	for from := startHeight; from <= endHeight; from += batchBlocks {
+		to := from + batchBlocks // exclusive
+		if to > endHeight+1 {
+			to = endHeight + 1
+		}
+		n, err := repo.BackfillAddressTransferRolesRange(ctx, from, to)
+		if err != nil {
+			log.Fatalf("range %d-%d: %v", from, to-1, err)
+		}
+		total += n
+		log.Printf("range %d-%d inserted=%d elapsed=%s", from, to-1, n, time.Since(started).Truncate(time.Second))
+	}
+	log.Printf("done inserted=%d elapsed=%s", total, time.Since(started).Truncate(time.Second))
+}
+
+const benchUnionSQL = `
+	SELECT block_height, encode(transaction_id, 'hex') FROM (
+		SELECT block_height, transaction_id FROM app.address_transactions
+		WHERE address = $1 AND role IN ('PAYER', 'PROPOSER', 'AUTHORIZER')
+		UNION
+		SELECT block_height, transaction_id FROM app.ft_transfers
+		WHERE from_address = $1 OR to_address = $1
+		UNION
+		SELECT block_height, transaction_id FROM app.nft_transfers
+		WHERE from_address = $1 OR to_address = $1
+	) u
+	ORDER BY block_height DESC, transaction_id DESC
+	LIMIT $2`
+
+const benchSingleSQL = `
+	SELECT DISTINCT block_height, encode(transaction_id, 'hex') FROM app.address_transactions
+	WHERE address = $1
+	ORDER BY 1 DESC, 2 DESC
+	LIMIT $2`
+
+func runBench(ctx context.Context, conn *pgx.Conn, address string, limit int) {
+	addr, err := hexAddress(address)
+	if err != nil {
+		log.Fatalf("bad address: %v", err)
+	}
+	union, unionTook := timedKeys(ctx, conn, benchUnionSQL, addr, limit)
+	single, singleTook := timedKeys(ctx, conn, benchSingleSQL, addr, limit)
+
+	same := len(union) == len(single)
+	for i := 0; same && i < len(union); i++ {
+		same = union[i] == single[i]
+	}
+	log.Printf("union: rows=%d took=%s", len(union), unionTook)
+	log.Printf("address_transactions: rows=%d took=%s", len(single), singleTook)
+	log.Printf("same result set: %v", same)
+}
+
+func timedKeys(ctx context.Context, conn *pgx.Conn, sql string, addr []byte, limit int) ([]string, time.Duration) {
+	start := time.Now()
+	rows, err := conn.Query(ctx, sql, addr, limit)
+	if err != nil {
+		log.Fatalf("query: %v", err)
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var h int64
+		var id string
+		if err := rows.Scan(&h, &id); err != nil {
+			log.Fatalf("scan: %v", err)
+		}
+		out = append(out, strconv.FormatInt(h, 10)+"/"+id)
+	}
+	if err := rows.Err(); err != nil {
+		log.Fatalf("rows: %v", err)
+	}
+	return out, time.Since(start)
+}
+
+func hexAddress(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "0x"))
+}
+
+func getEnvUint(key string, def uint64) uint64 {
+	if v := os.Getenv(key); v != "" {
+		if parsed, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return parsed
+		}
+	}
+	return def
+}
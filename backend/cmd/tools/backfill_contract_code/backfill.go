@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// accountFetcher is the part of the Flow client the backfill needs. The real
+// client applies its own rate limiter and circuit breaker to every call, so
+// the worker count only bounds how many requests wait on them at once.
+type accountFetcher interface {
+	GetAccountAtBlockHeight(ctx context.Context, address flow.Address, blockHeight uint64) (*flow.Account, error)
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type codeUpdate struct {
+	Address string
+	Name    string
+	Code    string
+}
+
+// updateCodeSQL fills in code for a batch of contracts, still only touching
+// rows whose code is empty so a concurrent writer is never overwritten.
+const updateCodeSQL = `
+	UPDATE app.smart_contracts AS c
+	SET code = u.code, updated_at = NOW()
+	FROM unnest($1::bytea[], $2::text[], $3::text[]) AS u(address, name, code)
+	WHERE c.address = u.address AND c.name = u.name AND COALESCE(c.code, '') = ''`
+
+// accountAt groups the targets served by one GetAccountAtBlockHeight call.
+type accountAt struct {
+	Address string
+	Height  uint64
+	Names   []string
+}
+
+func groupTargets(targets []row) []accountAt {
+	type key struct {
+		address string
+		height  uint64
+	}
+	idx := make(map[key]int)
+	var out []accountAt
+	for _, t := range targets {
+		k := key{t.Address, t.Height}
+		i, ok := idx[k]
+		if !ok {
+			i = len(out)
+			idx[k] = i
+			out = append(out, accountAt{Address: t.Address, Height: t.Height})
+		}
+		out[i].Names = append(out[i].Names, t.Name)
+	}
+	return out
+}
+
+// backfill fetches contract code with up to workers concurrent account
+// lookups and writes it back in batches of batchSize. Contracts the account
+// does not (or no longer) hold are skipped. It returns the number of targets
+// looked up and the number of rows updated.
+func backfill(ctx context.Context, client accountFetcher, db execer, targets []row, workers, batchSize int) (processed, updated int) {
+	if workers < 1 {
+		workers = 1
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	jobs := make(chan accountAt)
+	type result struct {
+		names   int
+		updates []codeUpdate
+	}
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				res := result{names: len(job.Names)}
+				acc, err := client.GetAccountAtBlockHeight(ctx, flow.HexToAddress(job.Address), job.Height)
+				if err != nil || acc == nil {
+					if err != nil {
+						log.Printf("fetch failed addr=%s height=%d: %v", job.Address, job.Height, err)
+					}
+					results <- res
+					continue
+				}
+				for _, name := range job.Names {
+					if code := acc.Contracts[name]; len(code) > 0 {
+						res.updates = append(res.updates, codeUpdate{Address: job.Address, Name: name, Code: string(code)})
+					}
+				}
+				results <- res
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for _, job := range groupTargets(targets) {
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var pending []codeUpdate
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		n, err := writeCodes(ctx, db, pending)
+		if err != nil {
+			log.Printf("update batch of %d failed: %v", len(pending), err)
+		}
+		updated += n
+		pending = pending[:0]
+	}
+	lastLogged := 0
+	for res := range results {
+		processed += res.names
+		pending = append(pending, res.updates...)
+		if len(pending) >= batchSize {
+			flush()
+		}
+		if processed-lastLogged >= 50 {
+			lastLogged = processed
+			log.Printf("progress %d/%d updated=%d", processed, len(targets), updated)
+		}
+	}
+	flush()
+	return processed, updated
+}
+
+func writeCodes(ctx context.Context, db execer, updates []codeUpdate) (int, error) {
+	addrs := make([][]byte, len(updates))
+	names := make([]string, len(updates))
+	codes := make([]string, len(updates))
+	for i, u := range updates {
+		addrs[i] = hexToBytes(u.Address)
+		names[i] = u.Name
+		codes[i] = u.Code
+	}
+	tag, err := db.Exec(ctx, updateCodeSQL, addrs, names, codes)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/onflow/flow-go-sdk"
+)
+
+type fakeAccounts struct {
+	contracts map[string]map[string][]byte // address -> name -> code
+	calls     int32
+	inFlight  int32
+	maxFlight int32
+}
+
+func (f *fakeAccounts) GetAccountAtBlockHeight(_ context.Context, address flow.Address, _ uint64) (*flow.Account, error) {
+	atomic.AddInt32(&f.calls, 1)
+	n := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	for {
+		m := atomic.LoadInt32(&f.maxFlight)
+		if n <= m || atomic.CompareAndSwapInt32(&f.maxFlight, m, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	contracts, ok := f.contracts[address.Hex()]
+	if !ok {
+		return nil, errors.New("account not found")
+	}
+	return &flow.Account{Address: address, Contracts: contracts}, nil
+}
+
+type recordingDB struct {
+	mu      sync.Mutex
+	batches [][]string // "address.name=code" per Exec
+	sql     []string
+}
+
+func (d *recordingDB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	addrs, names, codes := args[0].([][]byte), args[1].([]string), args[2].([]string)
+	var batch []string
+	for i := range names {
+		batch = append(batch, flow.BytesToAddress(addrs[i]).Hex()+"."+names[i]+"="+codes[i])
+	}
+	d.batches = append(d.batches, batch)
+	d.sql = append(d.sql, sql)
+	return pgconn.NewCommandTag("UPDATE " + strconv.Itoa(len(batch))), nil
+}
+
+func TestBackfillFetchesConcurrentlyAndGatesUpdates(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeAccounts{contracts: map[string]map[string][]byte{}}
+	var targets []row
+	for i := 1; i <= 8; i++ {
+		addr := flow.HexToAddress(strconv.Itoa(i)).Hex()
+		client.contracts[addr] = map[string][]byte{"A": []byte("access(all) contract A {}"), "Empty": nil}
+		targets = append(targets, row{Address: addr, Name: "A", Height: 100})
+	}
+	first := targets[0].Address
+	// Same account and height: served by the lookup above.
+	targets = append(targets,
+		row{Address: first, Name: "Empty", Height: 100},                        // held but empty: skipped
+		row{Address: first, Name: "Removed", Height: 100},                      // not on the account: skipped
+		row{Address: flow.HexToAddress("ff").Hex(), Name: "Gone", Height: 100}, // lookup fails: skipped
+	)
+
+	db := &recordingDB{}
+	processed, updated := backfill(context.Background(), client, db, targets, 4, 3)
+
+	if processed != len(targets) || updated != 8 {
+		t.Fatalf("processed=%d updated=%d, want %d and 8", processed, updated, len(targets))
+	}
+	if calls := atomic.LoadInt32(&client.calls); calls != 9 {
+		t.Fatalf("account lookups = %d, want 9 (one per address/height)", calls)
+	}
+	if max := atomic.LoadInt32(&client.maxFlight); max < 2 || max > 4 {
+		t.Fatalf("max concurrent lookups = %d, want between 2 and 4", max)
+	}
+
+	var written []string
+	for i, b := range db.batches {
+		if len(b) > 3 {
+			t.Fatalf("batch %d has %d updates, want <= 3", i, len(b))
+		}
+		if !strings.Contains(db.sql[i], "COALESCE(c.code, '') = ''") {
+			t.Fatalf("update lost the empty-code guard:\n%s", db.sql[i])
+		}
+		written = append(written, b...)
+	}
+	if len(written) != 8 {
+		t.Fatalf("written = %v, want 8 updates", written)
+	}
+	for _, w := range written {
+		if !strings.HasSuffix(w, ".A=access(all) contract A {}") {
+			t.Fatalf("unexpected update %q", w)
+		}
+	}
+}
@@ -13,7 +13,6 @@ import (
 	flowclient "flowscan-clone/internal/flow"
 
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/onflow/flow-go-sdk"
 )
 
 type row struct {
@@ -36,8 +35,10 @@ func hexToBytes(s string) []byte {
 
 func main() {
 	var (
-		limit  = flag.Int("limit", 0, "max rows to backfill (0 = no limit)")
-		offset = flag.Int("offset", 0, "offset for scanning rows")
+		limit   = flag.Int("limit", 0, "max rows to backfill (0 = no limit)")
+		offset  = flag.Int("offset", 0, "offset for scanning rows")
+		workers = flag.Int("workers", 8, "concurrent account lookups")
+		batch   = flag.Int("batch", 100, "contracts per UPDATE statement")
 	)
 	flag.Parse()
 
@@ -117,33 +118,11 @@ func main() {
 		return
 	}
 
-	log.Printf("backfilling %d contracts (offset=%d limit=%d)", len(targets), *offset, *limit)
+	log.Printf("backfilling %d contracts (offset=%d limit=%d workers=%d batch=%d)", len(targets), *offset, *limit, *workers, *batch)
 
-	updated := 0
-	for i, t := range targets {
-		acc, err := client.GetAccountAtBlockHeight(ctx, flow.HexToAddress(t.Address), t.Height)
-		if err != nil || acc == nil {
-			continue
-		}
-		codeBytes := acc.Contracts[t.Name]
-		if len(codeBytes) == 0 {
-			continue
-		}
-		code := string(codeBytes)
-		if _, err := pool.Exec(ctx, `
-			UPDATE app.smart_contracts
-			SET code = $1, updated_at = NOW()
-			WHERE address = $2 AND name = $3 AND COALESCE(code,'') = ''`,
-			code, hexToBytes(t.Address), t.Name,
-		); err != nil {
-			log.Printf("update failed addr=%s name=%s: %v", t.Address, t.Name, err)
-			continue
-		}
-		updated++
-		if (i+1)%50 == 0 {
-			log.Printf("progress %d/%d updated=%d", i+1, len(targets), updated)
-		}
-	}
+	processed, updated := backfill(ctx, client, pool, targets, *workers, *batch)
 
-	fmt.Printf("done updated=%d\n", updated)
+	// Updated rows drop out of the scan; skipped ones still match it, so a
+	// follow-up run continues past them with this offset.
+	fmt.Printf("done processed=%d updated=%d next_offset=%d\n", processed, updated, *offset+processed-updated)
 }
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// checkpointStore is the subset of the repository used to persist run
+// progress: the highest height below which every batch has been applied.
+type checkpointStore interface {
+	GetLastIndexedHeight(ctx context.Context, serviceName string) (uint64, error)
+	UpdateCheckpoint(ctx context.Context, serviceName string, height uint64) error
+}
+
+// heightRange is an inclusive block range with From <= To.
+type heightRange struct {
+	From uint64
+	To   uint64
+}
+
+// planBatches splits start..end into batches of size heights.
+func planBatches(start, end, size uint64) []heightRange {
+	var out []heightRange
+	for from := start; from <= end; from += size {
+		to := from + size - 1
+		if to > end || to < from {
+			to = end
+		}
+		out = append(out, heightRange{From: from, To: to})
+		if to == end {
+			break
+		}
+	}
+	return out
+}
+
+// resumeStart applies a stored checkpoint to start..end; 0 means no
+// checkpoint. A checkpoint below start belongs to a different run and is
+// ignored; done reports that the whole range was already applied.
+func resumeStart(start, end, checkpoint uint64) (from uint64, done bool) {
+	switch {
+	case checkpoint == 0 || checkpoint < start:
+		return start, false
+	case checkpoint >= end:
+		return start, true
+	}
+	return checkpoint + 1, false
+}
+
+// watermark tracks completed batches that finish out of order and reports
+// the end of the longest completed prefix, which is what the checkpoint may
+// safely record.
+type watermark struct {
+	batches []heightRange
+	done    []bool
+	next    int // first batch not yet completed
+}
+
+func newWatermark(batches []heightRange) *watermark {
+	return &watermark{batches: batches, done: make([]bool, len(batches))}
+}
+
+// complete marks batch i done. It returns the new watermark height and true
+// when the completed prefix grew.
+func (w *watermark) complete(i int) (uint64, bool) {
+	w.done[i] = true
+	start := w.next
+	for w.next < len(w.done) && w.done[w.next] {
+		w.next++
+	}
+	if w.next == start {
+		return 0, false
+	}
+	return w.batches[w.next-1].To, true
+}
+
+// runStats are the totals of a run, for throughput reporting.
+type runStats struct {
+	Batches int
+	Heights uint64
+	Logs    int
+}
+
+func (s runStats) String(elapsed time.Duration) string {
+	secs := elapsed.Seconds()
+	if secs <= 0 {
+		secs = 1
+	}
+	return fmt.Sprintf("batches=%d heights=%d logs=%d elapsed=%s (%.0f heights/s, %.0f logs/s)",
+		s.Batches, s.Heights, s.Logs, elapsed.Truncate(time.Second), float64(s.Heights)/secs, float64(s.Logs)/secs)
+}
+
+// runParallel processes start..end in batches of size heights with workers
+// goroutines; worker w owns the stripe of batches w, w+workers, .... With a
+// non-empty name the run resumes from the checkpoint stored under it, which
+// advances to the end of the completed prefix after every batch, so a
+// restart only redoes batches that finished out of order. The first error
+// stops all workers.
+func runParallel(ctx context.Context, store checkpointStore, name string, start, end, size uint64, workers int,
+	process func(ctx context.Context, worker int, b heightRange) (int, error)) (runStats, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	if name != "" {
+		cp, err := store.GetLastIndexedHeight(ctx, name)
+		if err != nil {
+			return runStats{}, fmt.Errorf("load checkpoint %s: %w", name, err)
+		}
+		from, done := resumeStart(start, end, cp)
+		if done {
+			log.Printf("checkpoint %s=%d already covers %d-%d", name, cp, start, end)
+			return runStats{}, nil
+		}
+		if from != start {
+			log.Printf("resuming %s from %d (checkpoint=%d)", name, from, cp)
+		}
+		start = from
+	}
+	batches := planBatches(start, end, size)
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		index int
+		logs  int
+		err   error
+	}
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(batches); i += workers {
+				if ctx.Err() != nil {
+					return
+				}
+				n, err := process(ctx, w, batches[i])
+				results <- result{index: i, logs: n, err: err}
+				if err != nil {
+					return
+				}
+			}
+		}(w)
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var stats runStats
+	var firstErr error
+	mark := newWatermark(batches)
+	// Results are drained until every worker has stopped, so batches that
+	// finish after a failure still count towards the checkpoint.
+	for res := range results {
+		b := batches[res.index]
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("batch %d-%d: %w", b.From, b.To, res.err)
+			}
+			cancel()
+			continue
+		}
+		stats.Batches++
+		stats.Heights += b.To - b.From + 1
+		stats.Logs += res.logs
+		if h, advanced := mark.complete(res.index); advanced && name != "" {
+			if err := store.UpdateCheckpoint(parent, name, h); err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("save checkpoint %d: %w", h, err)
+				}
+				cancel()
+			}
+		}
+	}
+	if firstErr == nil {
+		firstErr = parent.Err()
+	}
+	return stats, firstErr
+}
@@ -0,0 +1,126 @@
+// Command backfill_evm_logs decodes the logs of EVM.TransactionExecuted events in
+// raw.events into app.evm_logs for heights the evm_worker processed before it
+// started writing them. Inserts are keyed by (evm_hash, log_index), so
+// overlapping or repeated runs are safe.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"flowscan-clone/internal/ingester"
+	"flowscan-clone/internal/repository"
+)
+
+func main() {
+	var (
+		startHeight uint64
+		endHeight   uint64
+		batchSize   uint64
+		workers     int
+		checkpoint  string
+		dryRun      bool
+	)
+
+	flag.Uint64Var(&startHeight, "start", getEnvUint("BACKFILL_START_HEIGHT", 0), "start block height (inclusive)")
+	flag.Uint64Var(&endHeight, "end", getEnvUint("BACKFILL_END_HEIGHT", 0), "end block height (inclusive), default the evm_worker checkpoint")
+	flag.Uint64Var(&batchSize, "batch", getEnvUint("BACKFILL_BATCH_HEIGHTS", 2000), "heights per batch")
+	flag.IntVar(&workers, "workers", getEnvInt("BACKFILL_WORKERS", 1), "concurrent workers")
+	flag.StringVar(&checkpoint, "checkpoint", os.Getenv("BACKFILL_CHECKPOINT"), "app.indexing_checkpoints name to resume from and save progress to (default backfill_evm_logs; \"none\" disables)")
+	flag.BoolVar(&dryRun, "dry_run", getEnvBool("BACKFILL_DRY_RUN", false), "dry run (no writes)")
+	flag.Parse()
+
+	dbURL := os.Getenv("DB_URL")
+	if dbURL == "" {
+		log.Fatal("DB_URL is required")
+	}
+
+	repo, err := repository.NewRepository(dbURL)
+	if err != nil {
+		log.Fatalf("failed to connect to db: %v", err)
+	}
+	defer repo.Close()
+
+	ctx := context.Background()
+	if endHeight == 0 {
+		// The live evm_worker writes logs for everything after its checkpoint.
+		if endHeight, err = repo.GetLastIndexedHeight(ctx, "evm_worker"); err != nil {
+			log.Fatalf("evm_worker checkpoint: %v", err)
+		}
+	}
+	if endHeight < startHeight {
+		log.Fatalf("end height %d is below start height %d", endHeight, startHeight)
+	}
+	if batchSize == 0 {
+		batchSize = 2000
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if checkpoint == "" {
+		checkpoint = "backfill_evm_logs"
+	}
+	name := checkpoint
+	if name == "none" || dryRun {
+		name = ""
+	}
+
+	log.Printf("backfill evm logs start=%d end=%d batch=%d workers=%d checkpoint=%q dry_run=%v",
+		startHeight, endHeight, batchSize, workers, name, dryRun)
+	started := time.Now()
+
+	stats, err := runParallel(ctx, repo, name, startHeight, endHeight, batchSize, workers, func(ctx context.Context, worker int, b heightRange) (int, error) {
+		events, err := repo.GetEVMEventsInRange(ctx, b.From, b.To+1)
+		if err != nil {
+			return 0, fmt.Errorf("fetch evm events: %w", err)
+		}
+		logs := ingester.ParseEVMLogs(events)
+		if len(logs) == 0 {
+			return 0, nil
+		}
+		if dryRun {
+			log.Printf("[w%d] range %d-%d events=%d logs=%d (dry)", worker, b.From, b.To, len(events), len(logs))
+			return len(logs), nil
+		}
+		if err := repo.UpsertEVMLogs(ctx, logs); err != nil {
+			return 0, fmt.Errorf("upsert evm logs: %w", err)
+		}
+		log.Printf("[w%d] range %d-%d events=%d logs=%d elapsed=%s", worker, b.From, b.To, len(events), len(logs), time.Since(started).Truncate(time.Second))
+		return len(logs), nil
+	})
+	if err != nil {
+		log.Fatalf("backfill stopped: %v (%s)", err, stats.String(time.Since(started)))
+	}
+
+	log.Printf("done %s", stats.String(time.Since(started)))
+}
+
+func getEnvInt(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			return parsed
+		}
+	}
+	return def
+}
+
+func getEnvUint(key string, def uint64) uint64 {
+	if v := os.Getenv(key); v != "" {
+		if parsed, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return parsed
+		}
+	}
+	return def
+}
+
+func getEnvBool(key string, def bool) bool {
+	if v := os.Getenv(key); v != "" {
+		return v == "true" || v == "1"
+	}
+	return def
+}
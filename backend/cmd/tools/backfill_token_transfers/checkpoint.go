@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// checkpointPrefix namespaces backfill runs in app.indexing_checkpoints so they
+// never collide with the live workers.
+const checkpointPrefix = "backfill_token_transfers:"
+
+// checkpointStore is the subset of the repository used to persist run progress.
+type checkpointStore interface {
+	GetLastIndexedHeight(ctx context.Context, serviceName string) (uint64, error)
+	UpdateCheckpoint(ctx context.Context, serviceName string, height uint64) error
+	UpdateCheckpointDown(ctx context.Context, serviceName string, height uint64) error
+}
+
+// heightRange is an inclusive block range with From <= To.
+type heightRange struct {
+	From uint64
+	To   uint64
+}
+
+// planBatches splits start..end into batches of size blocks, in processing
+// order. When start > end the range is walked backward.
+func planBatches(start, end uint64, size int) []heightRange {
+	step := uint64(size)
+	var out []heightRange
+	if start <= end {
+		for from := start; ; from += step {
+			to := from + step - 1
+			if to > end || to < from {
+				to = end
+			}
+			out = append(out, heightRange{From: from, To: to})
+			if to == end {
+				return out
+			}
+		}
+	}
+	for to := start; ; to -= step {
+		var from uint64
+		if to >= step {
+			from = to - step + 1
+		}
+		if from < end {
+			from = end
+		}
+		out = append(out, heightRange{From: from, To: to})
+		if from == end {
+			return out
+		}
+	}
+}
+
+// resumeHeight applies a stored checkpoint to the requested range. The
+// checkpoint holds the far edge of the last committed batch (highest height
+// going forward, lowest going backward); 0 means no checkpoint. A checkpoint
+// outside the range belongs to a different run and is ignored. done reports
+// that the whole range was already committed.
+func resumeHeight(start, end, checkpoint uint64) (from uint64, done bool) {
+	if checkpoint == 0 {
+		return start, false
+	}
+	if start <= end {
+		switch {
+		case checkpoint >= end:
+			return start, true
+		case checkpoint >= start:
+			return checkpoint + 1, false
+		}
+		return start, false
+	}
+	switch {
+	case checkpoint <= end:
+		return start, true
+	case checkpoint <= start:
+		return checkpoint - 1, false
+	}
+	return start, false
+}
+
+// runBatches processes start..end batch by batch. With a non-empty name it
+// resumes from the checkpoint stored under that name and persists progress
+// after every successful batch. A failed batch is logged and skipped, as
+// before, but the checkpoint stops advancing from then on so a re-run
+// revisits it. Cancelling ctx stops the run before the next batch.
+func runBatches(ctx context.Context, store checkpointStore, name string, start, end uint64, size int, process func(context.Context, heightRange) error) (int, error) {
+	forward := start <= end
+	service := checkpointPrefix + name
+	if name != "" {
+		cp, err := store.GetLastIndexedHeight(ctx, service)
+		if err != nil {
+			return 0, fmt.Errorf("load checkpoint %s: %w", service, err)
+		}
+		from, done := resumeHeight(start, end, cp)
+		if done {
+			log.Printf("checkpoint %s=%d already covers %d -> %d", service, cp, start, end)
+			return 0, nil
+		}
+		if from != start {
+			log.Printf("resuming %s from %d (checkpoint=%d)", service, from, cp)
+		}
+		start = from
+	}
+
+	processed := 0
+	contiguous := name != ""
+	for _, b := range planBatches(start, end, size) {
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+		if err := process(ctx, b); err != nil {
+			log.Printf("batch %d -> %d failed: %v", b.From, b.To, err)
+			if contiguous {
+				log.Printf("checkpoint %s frozen; re-run to retry %d -> %d", service, b.From, b.To)
+				contiguous = false
+			}
+			continue
+		}
+		processed++
+		if !contiguous {
+			continue
+		}
+		var err error
+		if forward {
+			err = store.UpdateCheckpoint(ctx, service, b.To)
+		} else {
+			err = store.UpdateCheckpointDown(ctx, service, b.From)
+		}
+		if err != nil {
+			return processed, fmt.Errorf("save checkpoint %s: %w", service, err)
+		}
+	}
+	return processed, nil
+}
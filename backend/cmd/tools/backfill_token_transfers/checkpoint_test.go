@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type memCheckpoints map[string]uint64
+
+func (m memCheckpoints) GetLastIndexedHeight(_ context.Context, name string) (uint64, error) {
+	return m[name], nil
+}
+
+func (m memCheckpoints) UpdateCheckpoint(_ context.Context, name string, h uint64) error {
+	m[name] = max(m[name], h)
+	return nil
+}
+
+func (m memCheckpoints) UpdateCheckpointDown(_ context.Context, name string, h uint64) error {
+	if cur, ok := m[name]; !ok || h < cur {
+		m[name] = h
+	}
+	return nil
+}
+
+func TestPlanBatches(t *testing.T) {
+	t.Parallel()
+
+	if got, want := planBatches(100, 125, 10), []heightRange{{100, 109}, {110, 119}, {120, 125}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("forward = %v, want %v", got, want)
+	}
+	if got, want := planBatches(125, 100, 10), []heightRange{{116, 125}, {106, 115}, {100, 105}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("backward = %v, want %v", got, want)
+	}
+}
+
+func TestRunBatchesResumesAfterInterruption(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name       string
+		start, end uint64
+		resumed    []heightRange
+	}{
+		{"forward", 100, 149, []heightRange{{120, 129}, {130, 139}, {140, 149}}},
+		{"backward", 149, 100, []heightRange{{120, 129}, {110, 119}, {100, 109}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			store := memCheckpoints{}
+
+			// First run: the third batch is interrupted mid-write.
+			ctx, cancel := context.WithCancel(context.Background())
+			calls := 0
+			_, err := runBatches(ctx, store, "test", tc.start, tc.end, 10, func(context.Context, heightRange) error {
+				calls++
+				if calls == 3 {
+					cancel()
+					return context.Canceled
+				}
+				return nil
+			})
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("first run err = %v, want context.Canceled", err)
+			}
+
+			var seen []heightRange
+			n, err := runBatches(context.Background(), store, "test", tc.start, tc.end, 10, func(_ context.Context, b heightRange) error {
+				seen = append(seen, b)
+				return nil
+			})
+			if err != nil || n != len(tc.resumed) {
+				t.Fatalf("resume = (%d, %v), want (%d, nil)", n, err, len(tc.resumed))
+			}
+			if !reflect.DeepEqual(seen, tc.resumed) {
+				t.Fatalf("resumed batches = %v, want %v", seen, tc.resumed)
+			}
+
+			// Everything is committed now; a third run has nothing to do.
+			n, err = runBatches(context.Background(), store, "test", tc.start, tc.end, 10, func(context.Context, heightRange) error {
+				t.Fatal("unexpected batch after completion")
+				return nil
+			})
+			if err != nil || n != 0 {
+				t.Fatalf("completed run = (%d, %v), want (0, nil)", n, err)
+			}
+		})
+	}
+}
+
+func TestRunBatchesFreezesCheckpointAfterFailure(t *testing.T) {
+	t.Parallel()
+
+	store := memCheckpoints{}
+	calls := 0
+	n, err := runBatches(context.Background(), store, "test", 100, 139, 10, func(context.Context, heightRange) error {
+		calls++
+		if calls == 2 {
+			return errors.New("access node unavailable")
+		}
+		return nil
+	})
+	if err != nil || n != 3 {
+		t.Fatalf("run = (%d, %v), want (3, nil)", n, err)
+	}
+	if got := store[checkpointPrefix+"test"]; got != 109 {
+		t.Fatalf("checkpoint = %d, want 109 (before the failed batch)", got)
+	}
+}
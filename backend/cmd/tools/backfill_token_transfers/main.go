@@ -8,11 +8,11 @@ import (
 	"log"
 	"os"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
 	"flowscan-clone/internal/flow"
+	"flowscan-clone/internal/ingester"
 	"flowscan-clone/internal/models"
 	"flowscan-clone/internal/repository"
 
@@ -27,6 +27,7 @@ func main() {
 		batchBlocks int
 		workers     int
 		dryRun      bool
+		runName     string
 	)
 
 	flag.Uint64Var(&startHeight, "start", getEnvUint("BACKFILL_START", 0), "start block height (inclusive)")
@@ -34,6 +35,7 @@ func main() {
 	flag.IntVar(&batchBlocks, "batch", getEnvInt("BACKFILL_BATCH_BLOCKS", 1000), "blocks per batch")
 	flag.IntVar(&workers, "workers", getEnvInt("BACKFILL_WORKERS", 50), "concurrent tx workers")
 	flag.BoolVar(&dryRun, "dry-run", getEnvBool("BACKFILL_DRY_RUN", false), "dry run (no writes)")
+	flag.StringVar(&runName, "name", os.Getenv("BACKFILL_NAME"), "run name; progress is checkpointed under it and a re-run with the same name resumes")
 	flag.Parse()
 
 	if startHeight == 0 || endHeight == 0 {
@@ -67,161 +69,69 @@ func main() {
 	}
 	defer client.Close()
 
-	log.Printf("token backfill start=%d end=%d batch=%d workers=%d dry_run=%v", startHeight, endHeight, batchBlocks, workers, dryRun)
+	if dryRun && runName != "" {
+		log.Printf("dry run: ignoring --name, no checkpoint is written")
+		runName = ""
+	}
+
+	log.Printf("token backfill start=%d end=%d batch=%d workers=%d dry_run=%v name=%q", startHeight, endHeight, batchBlocks, workers, dryRun, runName)
 
 	ctx := context.Background()
 	startTime := time.Now()
-	processed := 0
-	if startHeight <= endHeight {
-		for current := startHeight; current <= endHeight; {
-			batchFrom := current
-			batchTo := current + uint64(batchBlocks) - 1
-			if batchTo > endHeight {
-				batchTo = endHeight
-			}
-
-			log.Printf("backfill range %d -> %d", batchFrom, batchTo)
-			start := time.Now()
-
-			txs, err := repo.GetRawTransactionsInRange(ctx, batchFrom, batchTo+1)
-			if err != nil {
-				log.Printf("failed to fetch txs: %v", err)
-				current = batchTo + 1
-				continue
-			}
-			if len(txs) == 0 {
-				processed++
-				current = batchTo + 1
-				continue
-			}
-
-			transfers, err := fetchTokenTransfers(ctx, client, txs, workers)
-			if err != nil {
-				log.Printf("failed to fetch token transfers: %v", err)
-				current = batchTo + 1
-				continue
-			}
-
-			if !dryRun && len(transfers) > 0 {
-				minH, maxH := transfers[0].BlockHeight, transfers[0].BlockHeight
-				for _, t := range transfers[1:] {
-					if t.BlockHeight < minH {
-						minH = t.BlockHeight
-					}
-					if t.BlockHeight > maxH {
-						maxH = t.BlockHeight
-					}
-				}
-				if err := repo.EnsureAppPartitions(ctx, minH, maxH); err != nil {
-					log.Printf("ensure partitions failed: %v", err)
-					current = batchTo + 1
-					continue
-				}
-				if err := repo.UpsertTokenTransfers(ctx, transfers); err != nil {
-					log.Printf("upsert token transfers failed: %v", err)
-					current = batchTo + 1
-					continue
-				}
-			}
+	processed, err := runBatches(ctx, repo, runName, startHeight, endHeight, batchBlocks, func(ctx context.Context, b heightRange) error {
+		log.Printf("backfill range %d -> %d", b.From, b.To)
+		start := time.Now()
 
-			processed++
-			log.Printf("range done: transfers=%d elapsed=%s", len(transfers), time.Since(start).Truncate(time.Millisecond))
-
-			if batchTo == endHeight {
-				break
-			}
-			current = batchTo + 1
+		txs, err := repo.GetRawTransactionsInRange(ctx, b.From, b.To+1)
+		if err != nil {
+			return fmt.Errorf("fetch txs: %w", err)
+		}
+		if len(txs) == 0 {
+			return nil
 		}
-	} else {
-		for current := startHeight; ; {
-			batchFrom := current
-			var batchTo uint64
-			if current >= uint64(batchBlocks) {
-				batchTo = current - uint64(batchBlocks) + 1
-			} else {
-				batchTo = 0
-			}
-			if batchTo < endHeight {
-				batchTo = endHeight
-			}
 
-			log.Printf("backfill range %d -> %d", batchTo, batchFrom)
-			start := time.Now()
+		transfers, err := fetchTokenTransfers(ctx, client, txs, workers)
+		if err != nil {
+			return fmt.Errorf("fetch token transfers: %w", err)
+		}
 
-			txs, err := repo.GetRawTransactionsInRange(ctx, batchTo, batchFrom+1)
-			if err != nil {
-				log.Printf("failed to fetch txs: %v", err)
-				if batchTo == endHeight || batchTo == 0 {
-					break
+		if !dryRun && len(transfers) > 0 {
+			minH, maxH := transfers[0].BlockHeight, transfers[0].BlockHeight
+			for _, t := range transfers[1:] {
+				if t.BlockHeight < minH {
+					minH = t.BlockHeight
 				}
-				current = batchTo - 1
-				continue
-			}
-			if len(txs) == 0 {
-				processed++
-				if batchTo == endHeight || batchTo == 0 {
-					break
+				if t.BlockHeight > maxH {
+					maxH = t.BlockHeight
 				}
-				current = batchTo - 1
-				continue
 			}
-
-			transfers, err := fetchTokenTransfers(ctx, client, txs, workers)
-			if err != nil {
-				log.Printf("failed to fetch token transfers: %v", err)
-				if batchTo == endHeight || batchTo == 0 {
-					break
-				}
-				current = batchTo - 1
-				continue
+			if err := repo.EnsureAppPartitions(ctx, minH, maxH); err != nil {
+				return fmt.Errorf("ensure partitions: %w", err)
 			}
-
-			if !dryRun && len(transfers) > 0 {
-				minH, maxH := transfers[0].BlockHeight, transfers[0].BlockHeight
-				for _, t := range transfers[1:] {
-					if t.BlockHeight < minH {
-						minH = t.BlockHeight
-					}
-					if t.BlockHeight > maxH {
-						maxH = t.BlockHeight
-					}
-				}
-				if err := repo.EnsureAppPartitions(ctx, minH, maxH); err != nil {
-					log.Printf("ensure partitions failed: %v", err)
-					if batchTo == endHeight || batchTo == 0 {
-						break
-					}
-					current = batchTo - 1
-					continue
-				}
-				if err := repo.UpsertTokenTransfers(ctx, transfers); err != nil {
-					log.Printf("upsert token transfers failed: %v", err)
-					if batchTo == endHeight || batchTo == 0 {
-						break
-					}
-					current = batchTo - 1
-					continue
-				}
+			if err := repo.UpsertTokenTransfers(ctx, transfers); err != nil {
+				return fmt.Errorf("upsert token transfers: %w", err)
 			}
-
-			processed++
-			log.Printf("range done: transfers=%d elapsed=%s", len(transfers), time.Since(start).Truncate(time.Millisecond))
-
-			if batchTo == endHeight || batchTo == 0 {
-				break
-			}
-			current = batchTo - 1
 		}
+
+		log.Printf("range done: transfers=%d elapsed=%s", len(transfers), time.Since(start).Truncate(time.Millisecond))
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("backfill stopped after %d batches: %v", processed, err)
 	}
 
 	log.Printf("backfill finished batches=%d total_elapsed=%s", processed, time.Since(startTime).Truncate(time.Millisecond))
 }
 
+// fetchTokenTransfers loads the events of txs from the access node and parses
+// them with the same logic as the live token_worker. The result is sorted by
+// (block_height, transaction_id, event_index) and deduplicated, so it does not
+// depend on the order in which the concurrent workers finish.
 func fetchTokenTransfers(ctx context.Context, client *flow.Client, txs []models.Transaction, workers int) ([]models.TokenTransfer, error) {
 	jobs := make(chan models.Transaction, len(txs))
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	transfers := make([]models.TokenTransfer, 0)
+	events := make([]models.Event, 0)
 
 	worker := func() {
 		defer wg.Done()
@@ -231,16 +141,10 @@ func fetchTokenTransfers(ctx context.Context, client *flow.Client, txs []models.
 				log.Printf("tx result error %s: %v", tx.ID, err)
 				continue
 			}
+			txEvents := make([]models.Event, 0, len(res.Events))
 			for _, evt := range res.Events {
-				isToken, isNFT := classifyTokenEvent(evt.Type)
-				if !isToken {
-					continue
-				}
-
-				payload := flattenCadenceValue(evt.Value)
-				payloadJSON, _ := json.Marshal(payload)
-
-				m := models.Event{
+				payloadJSON, _ := json.Marshal(flattenCadenceValue(evt.Value))
+				txEvents = append(txEvents, models.Event{
 					TransactionID:    tx.ID,
 					BlockHeight:      tx.BlockHeight,
 					TransactionIndex: tx.TransactionIndex,
@@ -248,16 +152,11 @@ func fetchTokenTransfers(ctx context.Context, client *flow.Client, txs []models.
 					Type:             evt.Type,
 					Payload:          payloadJSON,
 					Timestamp:        tx.Timestamp,
-				}
-
-				transfer := parseTokenEvent(m, isNFT)
-				if transfer == nil {
-					continue
-				}
-				mu.Lock()
-				transfers = append(transfers, *transfer)
-				mu.Unlock()
+				})
 			}
+			mu.Lock()
+			events = append(events, txEvents...)
+			mu.Unlock()
 		}
 	}
 
@@ -275,177 +174,7 @@ func fetchTokenTransfers(ctx context.Context, client *flow.Client, txs []models.
 	close(jobs)
 	wg.Wait()
 
-	return transfers, nil
-}
-
-func classifyTokenEvent(eventType string) (bool, bool) {
-	if strings.Contains(eventType, "NonFungibleToken.") &&
-		(strings.Contains(eventType, ".Deposited") || strings.Contains(eventType, ".Withdrawn")) {
-		return true, true
-	}
-	if strings.Contains(eventType, "FungibleToken.") &&
-		(strings.Contains(eventType, ".Deposited") || strings.Contains(eventType, ".Withdrawn")) {
-		return true, false
-	}
-	if strings.Contains(eventType, ".TokensDeposited") || strings.Contains(eventType, ".TokensWithdrawn") {
-		return true, false
-	}
-	return false, false
-}
-
-func parseTokenEvent(evt models.Event, isNFT bool) *models.TokenTransfer {
-	fields, ok := parseCadenceEventFields(evt.Payload)
-	if !ok {
-		return nil
-	}
-
-	amount := extractString(fields["amount"])
-	toAddr := extractAddress(fields["to"])
-	fromAddr := extractAddress(fields["from"])
-	tokenID := extractString(fields["id"])
-	if tokenID == "" {
-		tokenID = extractString(fields["tokenId"])
-	}
-
-	contractAddr := normalizeTokenAddress(evt.ContractAddress)
-	if contractAddr == "" {
-		contractAddr = parseContractAddress(evt.Type)
-	}
-
-	if isNFT {
-		if amount == "" {
-			amount = "1"
-		}
-	} else if amount == "" {
-		return nil
-	}
-
-	if toAddr == "" && fromAddr == "" {
-		return nil
-	}
-
-	return &models.TokenTransfer{
-		TransactionID:        evt.TransactionID,
-		BlockHeight:          evt.BlockHeight,
-		EventIndex:           evt.EventIndex,
-		TokenContractAddress: contractAddr,
-		FromAddress:          fromAddr,
-		ToAddress:            toAddr,
-		Amount:               amount,
-		TokenID:              tokenID,
-		IsNFT:                isNFT,
-		Timestamp:            evt.Timestamp,
-	}
-}
-
-func parseCadenceEventFields(payload []byte) (map[string]interface{}, bool) {
-	var root map[string]interface{}
-	if err := json.Unmarshal(payload, &root); err != nil {
-		return nil, false
-	}
-
-	if _, ok := root["amount"]; ok {
-		return root, true
-	}
-
-	val, ok := root["value"].(map[string]interface{})
-	if !ok {
-		return root, true
-	}
-
-	fields, ok := val["fields"].([]interface{})
-	if !ok {
-		return root, true
-	}
-
-	out := make(map[string]interface{}, len(fields))
-	for _, f := range fields {
-		field, ok := f.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		name, _ := field["name"].(string)
-		if name == "" {
-			continue
-		}
-		out[name] = parseCadenceValue(field["value"])
-	}
-	return out, true
-}
-
-func parseCadenceValue(v interface{}) interface{} {
-	switch val := v.(type) {
-	case map[string]interface{}:
-		typeName, _ := val["type"].(string)
-		raw := val["value"]
-
-		switch typeName {
-		case "Optional":
-			if raw == nil {
-				return nil
-			}
-			return parseCadenceValue(raw)
-		case "Address":
-			if s, ok := raw.(string); ok {
-				return s
-			}
-			return raw
-		case "UFix64", "UInt64", "UInt32", "UInt16", "UInt8", "Int", "Int64", "Int32", "Int16", "Int8", "Fix64":
-			if s, ok := raw.(string); ok {
-				return s
-			}
-			return raw
-		case "String", "Bool":
-			return raw
-		case "Array":
-			if arr, ok := raw.([]interface{}); ok {
-				out := make([]interface{}, 0, len(arr))
-				for _, item := range arr {
-					out = append(out, parseCadenceValue(item))
-				}
-				return out
-			}
-			return raw
-		case "Dictionary":
-			if arr, ok := raw.([]interface{}); ok {
-				out := make(map[string]interface{}, len(arr))
-				for _, item := range arr {
-					entry, ok := item.(map[string]interface{})
-					if !ok {
-						continue
-					}
-					k := parseCadenceValue(entry["key"])
-					v := parseCadenceValue(entry["value"])
-					out[fmt.Sprintf("%v", k)] = v
-				}
-				return out
-			}
-			return raw
-		case "Struct", "Resource", "Event":
-			if obj, ok := raw.(map[string]interface{}); ok {
-				if fields, ok := obj["fields"].([]interface{}); ok {
-					out := make(map[string]interface{}, len(fields))
-					for _, f := range fields {
-						field, ok := f.(map[string]interface{})
-						if !ok {
-							continue
-						}
-						name, _ := field["name"].(string)
-						if name == "" {
-							continue
-						}
-						out[name] = parseCadenceValue(field["value"])
-					}
-					return out
-				}
-			}
-			return raw
-		default:
-			return raw
-		}
-	default:
-		return val
-	}
+	return ingester.ParseTokenTransfers(events), nil
 }
 
 func flattenCadenceValue(v cadence.Value) interface{} {
@@ -520,43 +249,6 @@ func flattenCadenceValue(v cadence.Value) interface{} {
 	}
 }
 
-func extractString(v interface{}) string {
-	switch val := v.(type) {
-	case string:
-		return val
-	case json.Number:
-		return val.String()
-	case float64:
-		return fmt.Sprintf("%f", val)
-	default:
-		return ""
-	}
-}
-
-func extractAddress(v interface{}) string {
-	switch val := v.(type) {
-	case string:
-		return normalizeTokenAddress(val)
-	case map[string]interface{}:
-		if addr, ok := val["address"]; ok {
-			return normalizeTokenAddress(extractString(addr))
-		}
-	}
-	return normalizeTokenAddress(extractString(v))
-}
-
-func normalizeTokenAddress(addr string) string {
-	return strings.TrimPrefix(strings.ToLower(addr), "0x")
-}
-
-func parseContractAddress(eventType string) string {
-	parts := strings.Split(eventType, ".")
-	if len(parts) >= 3 && parts[0] == "A" {
-		return strings.TrimPrefix(strings.ToLower(parts[1]), "0x")
-	}
-	return ""
-}
-
 func getEnvInt(key string, def int) int {
 	if v := os.Getenv(key); v != "" {
 		if parsed, err := strconv.Atoi(v); err == nil {
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// checkpointStore persists the highest height below which every batch of a
+// run has been applied.
+type checkpointStore interface {
+	Load(ctx context.Context) (int64, bool, error)
+	Save(ctx context.Context, height int64) error
+}
+
+// pgCheckpoints stores the checkpoint in app.indexing_checkpoints under name.
+type pgCheckpoints struct {
+	conn *pgx.Conn
+	name string
+}
+
+func (c pgCheckpoints) Load(ctx context.Context) (int64, bool, error) {
+	var h int64
+	err := c.conn.QueryRow(ctx, `SELECT last_height FROM app.indexing_checkpoints WHERE service_name = $1`, c.name).Scan(&h)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	return h, err == nil, err
+}
+
+func (c pgCheckpoints) Save(ctx context.Context, height int64) error {
+	_, err := c.conn.Exec(ctx, `
+		INSERT INTO app.indexing_checkpoints (service_name, last_height, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (service_name) DO UPDATE SET
+			last_height = EXCLUDED.last_height,
+			updated_at = NOW()`, c.name, height)
+	return err
+}
+
+// heightRange is an inclusive block range with From <= To.
+type heightRange struct {
+	From int64
+	To   int64
+}
+
+// planBatches splits start..end into batches of size heights.
+func planBatches(start, end, size int64) []heightRange {
+	var out []heightRange
+	for from := start; from <= end; from += size {
+		to := from + size - 1
+		if to > end {
+			to = end
+		}
+		out = append(out, heightRange{From: from, To: to})
+	}
+	return out
+}
+
+// resumeStart applies a stored checkpoint to start..end. A checkpoint below
+// start belongs to a different run and is ignored; done reports that the
+// whole range was already applied.
+func resumeStart(start, end, checkpoint int64, ok bool) (from int64, done bool) {
+	switch {
+	case !ok || checkpoint < start:
+		return start, false
+	case checkpoint >= end:
+		return start, true
+	}
+	return checkpoint + 1, false
+}
+
+// watermark tracks completed batches that finish out of order and reports
+// the end of the longest completed prefix, which is what the checkpoint may
+// safely record.
+type watermark struct {
+	batches []heightRange
+	done    []bool
+	next    int // first batch not yet completed
+}
+
+func newWatermark(batches []heightRange) *watermark {
+	return &watermark{batches: batches, done: make([]bool, len(batches))}
+}
+
+// complete marks batch i done. It returns the new watermark height and true
+// when the completed prefix grew.
+func (w *watermark) complete(i int) (int64, bool) {
+	w.done[i] = true
+	start := w.next
+	for w.next < len(w.done) && w.done[w.next] {
+		w.next++
+	}
+	if w.next == start {
+		return 0, false
+	}
+	return w.batches[w.next-1].To, true
+}
+
+// runStats are the totals of a run, for throughput reporting.
+type runStats struct {
+	Batches int
+	Heights int64
+	Metrics int
+}
+
+func (s runStats) String(elapsed time.Duration) string {
+	secs := elapsed.Seconds()
+	if secs <= 0 {
+		secs = 1
+	}
+	return fmt.Sprintf("batches=%d heights=%d metrics=%d elapsed=%s (%.0f heights/s, %.0f metrics/s)",
+		s.Batches, s.Heights, s.Metrics, elapsed.Truncate(time.Second), float64(s.Heights)/secs, float64(s.Metrics)/secs)
+}
+
+// runParallel processes start..end in batches of size heights with workers
+// goroutines. Worker w owns the stripe of batches w, w+workers, ...; process
+// is called with the worker index so each worker can use its own connection.
+// After every batch the checkpoint (when store is non-nil) advances to the
+// end of the completed prefix, so a restart resumes after it and only redoes
+// batches that finished out of order. The first error stops all workers.
+func runParallel(ctx context.Context, store checkpointStore, start, end, size int64, workers int,
+	process func(ctx context.Context, worker int, b heightRange) (int, error)) (runStats, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	if store != nil {
+		cp, ok, err := store.Load(ctx)
+		if err != nil {
+			return runStats{}, fmt.Errorf("load checkpoint: %w", err)
+		}
+		from, done := resumeStart(start, end, cp, ok)
+		if done {
+			log.Printf("checkpoint %d already covers %d-%d", cp, start, end)
+			return runStats{}, nil
+		}
+		if from != start {
+			log.Printf("resuming from %d (checkpoint=%d)", from, cp)
+		}
+		start = from
+	}
+	batches := planBatches(start, end, size)
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		index   int
+		metrics int
+		err     error
+	}
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(batches); i += workers {
+				if ctx.Err() != nil {
+					return
+				}
+				n, err := process(ctx, w, batches[i])
+				results <- result{index: i, metrics: n, err: err}
+				if err != nil {
+					return
+				}
+			}
+		}(w)
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var stats runStats
+	var firstErr error
+	mark := newWatermark(batches)
+	// Results are drained until every worker has stopped, so batches that
+	// finish after a failure still count towards the checkpoint.
+	for res := range results {
+		b := batches[res.index]
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("batch %d-%d: %w", b.From, b.To, res.err)
+			}
+			cancel()
+			continue
+		}
+		stats.Batches++
+		stats.Heights += b.To - b.From + 1
+		stats.Metrics += res.metrics
+		if h, advanced := mark.complete(res.index); advanced && store != nil {
+			if err := store.Save(parent, h); err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("save checkpoint %d: %w", h, err)
+				}
+				cancel()
+			}
+		}
+	}
+	if firstErr == nil {
+		firstErr = parent.Err()
+	}
+	return stats, firstErr
+}
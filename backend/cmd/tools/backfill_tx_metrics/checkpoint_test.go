@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+type memCheckpoint struct {
+	mu     sync.Mutex
+	height int64
+	ok     bool
+	saves  []int64
+}
+
+func (m *memCheckpoint) Load(context.Context) (int64, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.height, m.ok, nil
+}
+
+func (m *memCheckpoint) Save(_ context.Context, h int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.height, m.ok = h, true
+	m.saves = append(m.saves, h)
+	return nil
+}
+
+// recorder collects processed batches per worker.
+type recorder struct {
+	mu      sync.Mutex
+	batches []heightRange
+	worker  map[heightRange]int
+}
+
+func (r *recorder) process(_ context.Context, worker int, b heightRange) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.worker == nil {
+		r.worker = map[heightRange]int{}
+	}
+	r.batches = append(r.batches, b)
+	r.worker[b] = worker
+	return int(b.To - b.From + 1), nil
+}
+
+func (r *recorder) sorted() []heightRange {
+	out := append([]heightRange(nil), r.batches...)
+	sort.Slice(out, func(i, j int) bool { return out[i].From < out[j].From })
+	return out
+}
+
+func TestRunParallelWorkersDoNotCollide(t *testing.T) {
+	t.Parallel()
+
+	store := &memCheckpoint{}
+	rec := &recorder{}
+	stats, err := runParallel(context.Background(), store, 0, 99, 10, 3, rec.process)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rec.sorted(), planBatches(0, 99, 10); !reflect.DeepEqual(got, want) {
+		t.Fatalf("batches = %v; want each of %v exactly once", got, want)
+	}
+	for i, b := range planBatches(0, 99, 10) {
+		if w := rec.worker[b]; w != i%3 {
+			t.Fatalf("batch %v ran on worker %d; want stripe owner %d", b, w, i%3)
+		}
+	}
+	if stats.Batches != 10 || stats.Heights != 100 || stats.Metrics != 100 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if store.height != 99 {
+		t.Fatalf("checkpoint = %d; want 99", store.height)
+	}
+	for i := 1; i < len(store.saves); i++ {
+		if store.saves[i] <= store.saves[i-1] {
+			t.Fatalf("checkpoint went backward: %v", store.saves)
+		}
+	}
+}
+
+func TestRunParallelResumeSkipsCompletedRanges(t *testing.T) {
+	t.Parallel()
+
+	store := &memCheckpoint{}
+	// The fourth batch (30-39) fails once the third is done; the other
+	// worker may run ahead, but the checkpoint stays below the failure.
+	failing := heightRange{30, 39}
+	thirdDone := make(chan struct{})
+	rec := &recorder{}
+	_, err := runParallel(context.Background(), store, 0, 79, 10, 2, func(ctx context.Context, w int, b heightRange) (int, error) {
+		if b == failing {
+			<-thirdDone
+			return 0, errors.New("connection reset")
+		}
+		n, err := rec.process(ctx, w, b)
+		if b == (heightRange{20, 29}) {
+			close(thirdDone)
+		}
+		return n, err
+	})
+	if err == nil {
+		t.Fatal("expected the failed batch to stop the run")
+	}
+	if store.height != 29 {
+		t.Fatalf("checkpoint = %d; want 29 (end of the completed prefix)", store.height)
+	}
+
+	rec = &recorder{}
+	if _, err := runParallel(context.Background(), store, 0, 79, 10, 2, rec.process); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rec.sorted(), planBatches(30, 79, 10); !reflect.DeepEqual(got, want) {
+		t.Fatalf("resumed batches = %v; want %v", got, want)
+	}
+
+	rec = &recorder{}
+	if _, err := runParallel(context.Background(), store, 0, 79, 10, 2, rec.process); err != nil || len(rec.batches) != 0 {
+		t.Fatalf("completed run processed %v (err %v)", rec.batches, err)
+	}
+}
+
+func TestWatermarkOutOfOrder(t *testing.T) {
+	t.Parallel()
+
+	w := newWatermark(planBatches(0, 39, 10))
+	if _, ok := w.complete(1); ok {
+		t.Fatal("watermark advanced past an incomplete batch")
+	}
+	if h, ok := w.complete(0); !ok || h != 19 {
+		t.Fatalf("complete(0) = %d, %v; want 19, true", h, ok)
+	}
+	if h, ok := w.complete(3); ok {
+		t.Fatalf("complete(3) advanced to %d", h)
+	}
+	if h, ok := w.complete(2); !ok || h != 39 {
+		t.Fatalf("complete(2) = %d, %v; want 39, true", h, ok)
+	}
+}
+
+func TestResumeStart(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		cp       int64
+		ok       bool
+		from     int64
+		finished bool
+	}{
+		{0, false, 100, false},
+		{50, true, 100, false}, // another run's checkpoint
+		{149, true, 150, false},
+		{199, true, 100, true},
+		{250, true, 100, true},
+	} {
+		from, done := resumeStart(100, 199, tc.cp, tc.ok)
+		if from != tc.from || done != tc.finished {
+			t.Fatalf("resumeStart(100, 199, %d, %v) = %d, %v; want %d, %v", tc.cp, tc.ok, from, done, tc.from, tc.finished)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"strconv"
@@ -29,12 +30,16 @@ func main() {
 		startHeight int64
 		endHeight   int64
 		batchSize   int64
+		workers     int
+		checkpoint  string
 		dryRun      bool
 	)
 
 	flag.Int64Var(&startHeight, "start", getEnvInt64("BACKFILL_START_HEIGHT", 0), "start block height (inclusive)")
 	flag.Int64Var(&endHeight, "end", getEnvInt64("BACKFILL_END_HEIGHT", 0), "end block height (inclusive), default auto-detect")
 	flag.Int64Var(&batchSize, "batch", getEnvInt64("BACKFILL_BATCH_HEIGHTS", 2000), "heights per batch")
+	flag.IntVar(&workers, "workers", int(getEnvInt64("BACKFILL_WORKERS", 1)), "concurrent workers, each with its own connection")
+	flag.StringVar(&checkpoint, "checkpoint", os.Getenv("BACKFILL_CHECKPOINT"), "app.indexing_checkpoints name to resume from and save progress to (default backfill_tx_metrics; \"none\" disables)")
 	flag.BoolVar(&dryRun, "dry_run", getEnvBool("BACKFILL_DRY_RUN", false), "dry run")
 	flag.Parse()
 
@@ -58,34 +63,58 @@ func main() {
 	if batchSize <= 0 {
 		batchSize = 2000
 	}
+	if workers < 1 {
+		workers = 1
+	}
 
-	log.Printf("backfill tx metrics start=%d end=%d batch=%d dry_run=%v", startHeight, endHeight, batchSize, dryRun)
-	started := time.Now()
-
-	for height := startHeight; height <= endHeight; height += batchSize {
-		to := height + batchSize - 1
-		if to > endHeight {
-			to = endHeight
+	// tmp_tx_metrics is a per-session temp table, so every worker gets its
+	// own connection; conn is kept for the checkpoint.
+	workerConns := make([]*pgx.Conn, workers)
+	for i := range workerConns {
+		c, err := pgx.Connect(ctx, dsn)
+		if err != nil {
+			log.Fatalf("connect worker %d: %v", i, err)
 		}
+		defer c.Close(ctx)
+		workerConns[i] = c
+	}
 
-		metrics, err := loadMetrics(ctx, conn, height, to)
+	if checkpoint == "" {
+		checkpoint = "backfill_tx_metrics"
+	}
+	var store checkpointStore
+	if checkpoint != "none" && !dryRun {
+		store = pgCheckpoints{conn: conn, name: checkpoint}
+	}
+
+	log.Printf("backfill tx metrics start=%d end=%d batch=%d workers=%d checkpoint=%q dry_run=%v",
+		startHeight, endHeight, batchSize, workers, checkpoint, dryRun)
+	started := time.Now()
+
+	stats, err := runParallel(ctx, store, startHeight, endHeight, batchSize, workers, func(ctx context.Context, worker int, b heightRange) (int, error) {
+		c := workerConns[worker]
+		metrics, err := loadMetrics(ctx, c, b.From, b.To)
 		if err != nil {
-			log.Fatalf("load metrics %d-%d: %v", height, to, err)
+			return 0, fmt.Errorf("load metrics: %w", err)
 		}
 		if len(metrics) == 0 {
-			continue
+			return 0, nil
 		}
 		if dryRun {
-			log.Printf("range %d-%d metrics=%d (dry)", height, to, len(metrics))
-			continue
+			log.Printf("[w%d] range %d-%d metrics=%d (dry)", worker, b.From, b.To, len(metrics))
+			return len(metrics), nil
 		}
-		if err := applyMetrics(ctx, conn, metrics); err != nil {
-			log.Fatalf("apply metrics %d-%d: %v", height, to, err)
+		if err := applyMetrics(ctx, c, metrics); err != nil {
+			return 0, fmt.Errorf("apply metrics: %w", err)
 		}
-		log.Printf("range %d-%d metrics=%d elapsed=%s", height, to, len(metrics), time.Since(started).Truncate(time.Second))
+		log.Printf("[w%d] range %d-%d metrics=%d elapsed=%s", worker, b.From, b.To, len(metrics), time.Since(started).Truncate(time.Second))
+		return len(metrics), nil
+	})
+	if err != nil {
+		log.Fatalf("backfill stopped: %v (%s)", err, stats.String(time.Since(started)))
 	}
 
-	log.Printf("done elapsed=%s", time.Since(started).Truncate(time.Second))
+	log.Printf("done %s", stats.String(time.Since(started)))
 }
 
 func loadMetrics(ctx context.Context, conn *pgx.Conn, from, to int64) (map[txKey]txMetric, error) {
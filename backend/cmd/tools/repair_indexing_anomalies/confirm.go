@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+
+	"flowscan-clone/internal/ingester"
+)
+
+type blockFetcher interface {
+	FetchBlockData(ctx context.Context, height uint64) *ingester.FetchResult
+}
+
+// looksEmpty reports a block whose header lists collections but which came
+// back without transactions. That is either a node serving incomplete data
+// (transient) or what the chain really holds (confirmed once several nodes agree).
+func looksEmpty(res *ingester.FetchResult) bool {
+	return res.Error == nil && res.Block != nil && res.Block.CollectionCount > 0 && len(res.Transactions) == 0
+}
+
+// fetchConfirmed fetches height and, while the result looks empty, re-queries
+// the pool (which round-robins across nodes) up to maxFetches times. It returns
+// as soon as a fetch has transactions or fails, and reports confirmed once
+// confirmNodes distinct nodes returned the same empty block.
+func fetchConfirmed(ctx context.Context, f blockFetcher, height uint64, confirmNodes, maxFetches int) (res *ingester.FetchResult, confirmed bool, nodes []string) {
+	if confirmNodes < 1 {
+		confirmNodes = 1
+	}
+	if maxFetches < confirmNodes {
+		maxFetches = confirmNodes
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < maxFetches; i++ {
+		res = f.FetchBlockData(ctx, height)
+		if !looksEmpty(res) {
+			return res, false, nodes
+		}
+		if !seen[res.Node] {
+			seen[res.Node] = true
+			nodes = append(nodes, res.Node)
+		}
+		if len(nodes) >= confirmNodes {
+			return res, true, nodes
+		}
+	}
+	return res, false, nodes
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"flowscan-clone/internal/ingester"
+	"flowscan-clone/internal/models"
+)
+
+// fakePool answers each fetch from the next node in nodes, round-robin.
+type fakePool struct {
+	nodes []string
+	txsOn map[string]int // node -> transactions it returns
+	errOn map[string]error
+	calls int
+}
+
+func (f *fakePool) FetchBlockData(_ context.Context, height uint64) *ingester.FetchResult {
+	node := f.nodes[f.calls%len(f.nodes)]
+	f.calls++
+	if err := f.errOn[node]; err != nil {
+		return &ingester.FetchResult{Height: height, Error: err}
+	}
+	res := &ingester.FetchResult{
+		Height: height,
+		Block:  &models.Block{Height: height, CollectionCount: 2},
+		Node:   node,
+	}
+	res.Transactions = make([]models.Transaction, f.txsOn[node])
+	return res
+}
+
+func TestFetchConfirmedEmptyFromAllNodes(t *testing.T) {
+	t.Parallel()
+
+	pool := &fakePool{nodes: []string{"an-1", "an-2", "an-3"}}
+	res, confirmed, nodes := fetchConfirmed(context.Background(), pool, 100, 2, 4)
+	if !confirmed {
+		t.Fatal("expected the empty block to be confirmed")
+	}
+	if !looksEmpty(res) || len(nodes) != 2 || pool.calls != 2 {
+		t.Fatalf("nodes=%v calls=%d", nodes, pool.calls)
+	}
+}
+
+func TestFetchConfirmedNeedsDistinctNodes(t *testing.T) {
+	t.Parallel()
+
+	// A single node repeating itself is not a confirmation.
+	pool := &fakePool{nodes: []string{"an-1"}}
+	_, confirmed, nodes := fetchConfirmed(context.Background(), pool, 100, 2, 4)
+	if confirmed {
+		t.Fatal("one node must not confirm an empty block")
+	}
+	if len(nodes) != 1 || pool.calls != 4 {
+		t.Fatalf("nodes=%v calls=%d, want 1 node after 4 fetches", nodes, pool.calls)
+	}
+}
+
+func TestFetchConfirmedTransientEmpty(t *testing.T) {
+	t.Parallel()
+
+	pool := &fakePool{nodes: []string{"an-1", "an-2"}, txsOn: map[string]int{"an-2": 3}}
+	res, confirmed, _ := fetchConfirmed(context.Background(), pool, 100, 2, 4)
+	if confirmed || looksEmpty(res) || len(res.Transactions) != 3 {
+		t.Fatalf("confirmed=%v txs=%d, want the second node's transactions", confirmed, len(res.Transactions))
+	}
+}
+
+func TestFetchConfirmedStopsOnError(t *testing.T) {
+	t.Parallel()
+
+	pool := &fakePool{nodes: []string{"an-1", "an-2"}, errOn: map[string]error{"an-2": errors.New("unavailable")}}
+	res, confirmed, _ := fetchConfirmed(context.Background(), pool, 100, 2, 4)
+	if confirmed || res.Error == nil {
+		t.Fatalf("confirmed=%v err=%v, want the fetch error", confirmed, res.Error)
+	}
+}
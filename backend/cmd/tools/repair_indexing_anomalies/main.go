@@ -21,6 +21,7 @@ type repairTarget struct {
 	WorkerName  string
 	BlockHeight uint64
 	ErrorIDs    []int64
+	Attempts    int
 }
 
 func main() {
@@ -35,6 +36,8 @@ func main() {
 	}
 
 	limit := getEnvInt("REPAIR_LIMIT", 100)
+	// Give up on a block after this many failed repair runs.
+	maxAttempts := getEnvInt("REPAIR_MAX_ATTEMPTS", 5)
 	timeoutSec := getEnvInt("REPAIR_TIMEOUT_SEC", 120)
 	if timeoutSec < 10 {
 		timeoutSec = 10
@@ -64,6 +67,13 @@ func main() {
 
 	worker := ingester.NewWorker(client)
 
+	// An empty block counts as confirmed once this many distinct nodes return it.
+	confirmNodes := getEnvInt("REPAIR_CONFIRM_NODES", 2)
+	if n := client.NodeCount(); confirmNodes > n {
+		confirmNodes = n
+	}
+	maxFetches := getEnvInt("REPAIR_MAX_FETCHES", 2*confirmNodes)
+
 	ctx := context.Background()
 	targets, err := loadTargets(ctx, pool, limit)
 	if err != nil {
@@ -77,38 +87,64 @@ func main() {
 	log.Printf("loaded %d repair targets", len(targets))
 	success := 0
 	stillEmpty := 0
+	confirmedEmpty := 0
+	gaveUp := 0
 	failed := 0
 
 	for i, t := range targets {
+		serviceName := "repair_" + t.WorkerName
+		if maxAttempts > 0 && t.Attempts >= maxAttempts {
+			gaveUp++
+			log.Printf("[%d/%d] %s height=%d giving up after %d attempts", i+1, len(targets), t.WorkerName, t.BlockHeight, t.Attempts)
+			msg := fmt.Sprintf("gave up after %d repair attempts", t.Attempts)
+			if err := resolveWithOutcome(ctx, pool, t, "repair_max_attempts", msg, false); err != nil {
+				log.Printf("[%d/%d] %s height=%d give-up update failed: %v", i+1, len(targets), t.WorkerName, t.BlockHeight, err)
+			}
+			continue
+		}
+
 		runCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSec)*time.Second)
-		res := worker.FetchBlockData(runCtx, t.BlockHeight)
+		res, confirmed, nodes := fetchConfirmed(runCtx, worker, t.BlockHeight, confirmNodes, maxFetches)
 		cancel()
 
 		if res.Error != nil {
 			failed++
 			log.Printf("[%d/%d] %s height=%d fetch failed: %v", i+1, len(targets), t.WorkerName, t.BlockHeight, res.Error)
-			_ = repo.LogIndexingError(ctx, "repair_"+t.WorkerName, t.BlockHeight, "", "repair_fetch_failed", res.Error.Error(), nil)
+			_ = repo.LogIndexingError(ctx, serviceName, t.BlockHeight, "", "repair_fetch_failed", res.Error.Error(), nil)
+			_ = bumpAttempts(ctx, pool, t.ErrorIDs)
 			continue
 		}
 		if res.Block == nil {
 			failed++
 			log.Printf("[%d/%d] %s height=%d fetch returned nil block", i+1, len(targets), t.WorkerName, t.BlockHeight)
-			_ = repo.LogIndexingError(ctx, "repair_"+t.WorkerName, t.BlockHeight, "", "repair_nil_block", "repair fetch returned nil block", nil)
+			_ = repo.LogIndexingError(ctx, serviceName, t.BlockHeight, "", "repair_nil_block", "repair fetch returned nil block", nil)
+			_ = bumpAttempts(ctx, pool, t.ErrorIDs)
 			continue
 		}
 
-		serviceName := "repair_" + t.WorkerName
 		if err := repo.SaveBatch(ctx, []*models.Block{res.Block}, res.Transactions, res.Events, serviceName, t.BlockHeight); err != nil {
 			failed++
 			log.Printf("[%d/%d] %s height=%d save failed: %v", i+1, len(targets), t.WorkerName, t.BlockHeight, err)
 			_ = repo.LogIndexingError(ctx, serviceName, t.BlockHeight, "", "repair_save_failed", err.Error(), nil)
+			_ = bumpAttempts(ctx, pool, t.ErrorIDs)
 			continue
 		}
 
-		if res.Block.CollectionCount > 0 && len(res.Transactions) == 0 {
+		if looksEmpty(res) && confirmed {
+			confirmedEmpty++
+			log.Printf("[%d/%d] %s height=%d confirmed empty by %d node(s) %v", i+1, len(targets), t.WorkerName, t.BlockHeight, len(nodes), nodes)
+			msg := fmt.Sprintf("collection_count=%d tx_count=0 confirmed by nodes %s", res.Block.CollectionCount, strings.Join(nodes, ","))
+			if err := resolveWithOutcome(ctx, pool, t, "repair_confirmed_empty", msg, true); err != nil {
+				failed++
+				log.Printf("[%d/%d] %s height=%d confirmed empty but resolve update failed: %v", i+1, len(targets), t.WorkerName, t.BlockHeight, err)
+			}
+			continue
+		}
+		if looksEmpty(res) {
 			stillEmpty++
-			log.Printf("[%d/%d] %s height=%d still empty after repair (collection_count=%d tx=0)", i+1, len(targets), t.WorkerName, t.BlockHeight, res.Block.CollectionCount)
+			log.Printf("[%d/%d] %s height=%d still empty after repair (collection_count=%d tx=0 nodes=%v)", i+1, len(targets), t.WorkerName, t.BlockHeight, res.Block.CollectionCount, nodes)
 			_ = repo.LogIndexingError(ctx, serviceName, t.BlockHeight, "", "repair_still_empty", fmt.Sprintf("collection_count=%d tx_count=0", res.Block.CollectionCount), nil)
+			_ = bumpAttempts(ctx, pool, t.ErrorIDs)
 			continue
 		}
 
@@ -121,7 +157,8 @@ func main() {
 		log.Printf("[%d/%d] %s height=%d repaired (tx=%d events=%d)", i+1, len(targets), t.WorkerName, t.BlockHeight, len(res.Transactions), len(res.Events))
 	}
 
-	log.Printf("repair done: total=%d success=%d still_empty=%d failed=%d", len(targets), success, stillEmpty, failed)
+	log.Printf("repair done: total=%d success=%d confirmed_empty=%d still_empty=%d gave_up=%d failed=%d",
+		len(targets), success, confirmedEmpty, stillEmpty, gaveUp, failed)
 }
 
 func loadTargets(ctx context.Context, pool *pgxpool.Pool, limit int) ([]repairTarget, error) {
@@ -131,6 +168,7 @@ func loadTargets(ctx context.Context, pool *pgxpool.Pool, limit int) ([]repairTa
 				worker_name,
 				block_height,
 				array_agg(id ORDER BY id) AS error_ids,
+				max(repair_attempts) AS attempts,
 				min(created_at) AS first_seen
 				FROM raw.indexing_errors
 				WHERE resolved = FALSE
@@ -156,7 +194,7 @@ func loadTargets(ctx context.Context, pool *pgxpool.Pool, limit int) ([]repairTa
 				  )
 			GROUP BY worker_name, block_height
 		)
-		SELECT worker_name, block_height, error_ids
+		SELECT worker_name, block_height, error_ids, attempts
 		FROM todo
 		ORDER BY first_seen
 		LIMIT $1
@@ -169,7 +207,7 @@ func loadTargets(ctx context.Context, pool *pgxpool.Pool, limit int) ([]repairTa
 	var out []repairTarget
 	for rows.Next() {
 		var t repairTarget
-		if err := rows.Scan(&t.WorkerName, &t.BlockHeight, &t.ErrorIDs); err != nil {
+		if err := rows.Scan(&t.WorkerName, &t.BlockHeight, &t.ErrorIDs, &t.Attempts); err != nil {
 			return nil, err
 		}
 		out = append(out, t)
@@ -189,6 +227,48 @@ func markResolved(ctx context.Context, pool *pgxpool.Pool, ids []int64) error {
 	return err
 }
 
+// bumpAttempts counts a failed repair run against the target's errors.
+func bumpAttempts(ctx context.Context, pool *pgxpool.Pool, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := pool.Exec(ctx, `
+		UPDATE raw.indexing_errors
+		SET repair_attempts = repair_attempts + 1
+		WHERE id = ANY($1::bigint[])
+	`, ids)
+	return err
+}
+
+// resolveWithOutcome resolves the target's errors and records why under a
+// distinct error hash, so the target is not picked up again. The outcome row
+// itself is left unresolved when it needs a human to look at it.
+func resolveWithOutcome(ctx context.Context, pool *pgxpool.Pool, t repairTarget, outcome, msg string, resolved bool) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO raw.indexing_errors (worker_name, block_height, transaction_id, error_hash, error_message, resolved)
+		VALUES ($1, $2, '', $3, $4, $5)
+		ON CONFLICT (worker_name, block_height, transaction_id, error_hash) DO UPDATE SET
+			error_message = EXCLUDED.error_message,
+			resolved = EXCLUDED.resolved
+	`, "repair_"+t.WorkerName, t.BlockHeight, outcome, msg, resolved); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE raw.indexing_errors
+		SET resolved = TRUE
+		WHERE id = ANY($1::bigint[])
+	`, t.ErrorIDs); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
 func getEnvInt(key string, fallback int) int {
 	v := strings.TrimSpace(os.Getenv(key))
 	if v == "" {
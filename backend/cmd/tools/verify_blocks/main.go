@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	flowclient "flowscan-clone/internal/flow"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// verify_blocks compares raw.blocks.id against the access node over a height
+// range and prints a summary. It never writes: the DB session is opened with
+// default_transaction_read_only.
+func main() {
+	var (
+		from    = flag.Uint64("from", 0, "start height (inclusive)")
+		to      = flag.Uint64("to", 0, "end height (inclusive)")
+		sample  = flag.Int("sample", 0, "check this many random heights in the range (0 = every height)")
+		seed    = flag.Int64("seed", 0, "random seed for --sample (0 = time based)")
+		workers = flag.Int("workers", 8, "concurrent access node requests")
+	)
+	flag.Parse()
+
+	if *from == 0 || *to == 0 || *to < *from {
+		log.Fatal("--from and --to are required (from <= to)")
+	}
+
+	dbURL := strings.TrimSpace(os.Getenv("DATABASE_PUBLIC_URL"))
+	if dbURL == "" {
+		dbURL = strings.TrimSpace(os.Getenv("DATABASE_URL"))
+	}
+	if dbURL == "" {
+		log.Fatal("missing DATABASE_PUBLIC_URL or DATABASE_URL")
+	}
+	flowURL := strings.TrimSpace(os.Getenv("FLOW_URL"))
+	if flowURL == "" {
+		flowURL = "access-001.mainnet28.nodes.onflow.org:9000"
+	}
+
+	cfg, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		log.Fatalf("db config: %v", err)
+	}
+	cfg.ConnConfig.RuntimeParams["default_transaction_read_only"] = "on"
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("db: %v", err)
+	}
+	defer pool.Close()
+
+	client, err := flowclient.NewClientFromEnv("FLOW_ACCESS_NODES", flowURL)
+	if err != nil {
+		log.Fatalf("flow: %v", err)
+	}
+	defer client.Close()
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	heights := pickHeights(*from, *to, *sample, rand.New(rand.NewSource(*seed)))
+	log.Printf("verifying %d heights in [%d, %d] (sample=%d seed=%d workers=%d)", len(heights), *from, *to, *sample, *seed, *workers)
+
+	started := time.Now()
+	sum, err := verify(context.Background(), accessNode{client}, dbBlocks{pool}, heights, *workers, 1000)
+	out, _ := json.MarshalIndent(sum, "", "  ")
+	fmt.Println(string(out))
+	log.Printf("checked=%d matched=%d mismatched=%d missing=%d errors=%d elapsed=%s",
+		sum.Checked, sum.Matched, sum.Mismatched, sum.Missing, sum.Errors, time.Since(started).Truncate(time.Second))
+	if err != nil {
+		log.Fatalf("verify stopped: %v", err)
+	}
+	if sum.Mismatched > 0 || sum.Missing > 0 {
+		os.Exit(1)
+	}
+}
+
+// accessNode adapts the Flow client, pinning each height to the spork node
+// that serves it.
+type accessNode struct{ c *flowclient.Client }
+
+func (a accessNode) BlockIDAtHeight(ctx context.Context, height uint64) (string, error) {
+	pin, err := a.c.PinByHeight(height)
+	if err != nil {
+		return "", err
+	}
+	block, err := pin.GetBlockHeaderByHeight(ctx, height)
+	if err != nil {
+		return "", err
+	}
+	return block.ID.Hex(), nil
+}
+
+type dbBlocks struct{ pool *pgxpool.Pool }
+
+func (d dbBlocks) StoredBlockIDs(ctx context.Context, heights []uint64) (map[uint64]string, error) {
+	hs := make([]int64, len(heights))
+	for i, h := range heights {
+		hs[i] = int64(h)
+	}
+	rows, err := d.pool.Query(ctx, `SELECT height, encode(id, 'hex') FROM raw.blocks WHERE height = ANY($1::bigint[])`, hs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[uint64]string, len(heights))
+	for rows.Next() {
+		var h int64
+		var id string
+		if err := rows.Scan(&h, &id); err != nil {
+			return nil, err
+		}
+		out[uint64(h)] = id
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// chainSource returns the canonical block id at a height from an access node.
+type chainSource interface {
+	BlockIDAtHeight(ctx context.Context, height uint64) (string, error)
+}
+
+// storedSource returns the block ids stored in raw.blocks for heights. Heights
+// with no stored row are absent from the map.
+type storedSource interface {
+	StoredBlockIDs(ctx context.Context, heights []uint64) (map[uint64]string, error)
+}
+
+type mismatch struct {
+	Height uint64 `json:"height"`
+	Stored string `json:"stored"`
+	Chain  string `json:"chain"`
+}
+
+type summary struct {
+	Checked    int        `json:"checked"`
+	Matched    int        `json:"matched"`
+	Mismatched int        `json:"mismatched"`
+	Missing    int        `json:"missing"`
+	Errors     int        `json:"errors"`
+	Mismatches []mismatch `json:"mismatches,omitempty"`
+	Missed     []uint64   `json:"missing_heights,omitempty"`
+}
+
+// maxReported caps how many mismatched/missing heights are listed individually.
+const maxReported = 100
+
+// pickHeights returns every height in [from, to] when sample <= 0 or covers the
+// range, otherwise sample distinct heights drawn with rng, sorted ascending.
+func pickHeights(from, to uint64, sample int, rng *rand.Rand) []uint64 {
+	if to < from {
+		return nil
+	}
+	span := to - from + 1
+	if sample <= 0 || uint64(sample) >= span {
+		out := make([]uint64, 0, span)
+		for h := from; h <= to; h++ {
+			out = append(out, h)
+		}
+		return out
+	}
+	seen := make(map[uint64]bool, sample)
+	out := make([]uint64, 0, sample)
+	for len(out) < sample {
+		h := from + uint64(rng.Int63n(int64(span)))
+		if !seen[h] {
+			seen[h] = true
+			out = append(out, h)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+func normalizeID(id string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(id)), "0x")
+}
+
+// verify compares stored and chain block ids for heights, fetching from the
+// chain with up to workers concurrent requests. Heights are processed in
+// chunks of chunkSize so the stored-id lookup stays one query per chunk. It
+// only reads from both sources.
+func verify(ctx context.Context, chain chainSource, stored storedSource, heights []uint64, workers, chunkSize int) (summary, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	if chunkSize < 1 {
+		chunkSize = 1000
+	}
+	var sum summary
+	for start := 0; start < len(heights); start += chunkSize {
+		end := start + chunkSize
+		if end > len(heights) {
+			end = len(heights)
+		}
+		chunk := heights[start:end]
+
+		storedIDs, err := stored.StoredBlockIDs(ctx, chunk)
+		if err != nil {
+			return sum, err
+		}
+
+		chainIDs := make([]string, len(chunk))
+		chainErrs := make([]error, len(chunk))
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					chainIDs[i], chainErrs[i] = chain.BlockIDAtHeight(ctx, chunk[i])
+				}
+			}()
+		}
+		for i := range chunk {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+
+		for i, h := range chunk {
+			if chainErrs[i] != nil {
+				sum.Errors++
+				continue
+			}
+			sum.Checked++
+			s, ok := storedIDs[h]
+			switch {
+			case !ok:
+				sum.Missing++
+				if len(sum.Missed) < maxReported {
+					sum.Missed = append(sum.Missed, h)
+				}
+			case normalizeID(s) == normalizeID(chainIDs[i]):
+				sum.Matched++
+			default:
+				sum.Mismatched++
+				if len(sum.Mismatches) < maxReported {
+					sum.Mismatches = append(sum.Mismatches, mismatch{Height: h, Stored: normalizeID(s), Chain: normalizeID(chainIDs[i])})
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return sum, err
+		}
+	}
+	return sum, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+type fakeChain map[uint64]string
+
+func (f fakeChain) BlockIDAtHeight(_ context.Context, h uint64) (string, error) {
+	id, ok := f[h]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return id, nil
+}
+
+type fakeStore map[uint64]string
+
+func (f fakeStore) StoredBlockIDs(_ context.Context, heights []uint64) (map[uint64]string, error) {
+	out := map[uint64]string{}
+	for _, h := range heights {
+		if id, ok := f[h]; ok {
+			out[h] = id
+		}
+	}
+	return out, nil
+}
+
+func TestVerifyReportsMismatch(t *testing.T) {
+	t.Parallel()
+
+	chain := fakeChain{}
+	store := fakeStore{}
+	for h := uint64(100); h <= 120; h++ {
+		id := fmt.Sprintf("%064x", h)
+		chain[h] = id
+		store[h] = "0x" + id // prefix is ignored
+	}
+	store[107] = fmt.Sprintf("%064x", 9999) // deliberate mismatch
+	delete(store, 115)                      // never written
+	delete(chain, 120)                      // access node error
+
+	sum, err := verify(context.Background(), chain, store, pickHeights(100, 120, 0, nil), 4, 5)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if sum.Checked != 20 || sum.Matched != 18 || sum.Mismatched != 1 || sum.Missing != 1 || sum.Errors != 1 {
+		t.Fatalf("summary = %+v", sum)
+	}
+	if len(sum.Mismatches) != 1 || sum.Mismatches[0].Height != 107 || sum.Mismatches[0].Chain != chain[107] {
+		t.Fatalf("mismatches = %+v", sum.Mismatches)
+	}
+	if len(sum.Missed) != 1 || sum.Missed[0] != 115 {
+		t.Fatalf("missing = %v", sum.Missed)
+	}
+}
+
+func TestPickHeights(t *testing.T) {
+	t.Parallel()
+
+	if got := pickHeights(5, 9, 0, nil); len(got) != 5 || got[0] != 5 || got[4] != 9 {
+		t.Fatalf("exhaustive = %v", got)
+	}
+	got := pickHeights(1, 1000, 10, rand.New(rand.NewSource(1)))
+	if len(got) != 10 {
+		t.Fatalf("sample len = %d", len(got))
+	}
+	for i, h := range got {
+		if h < 1 || h > 1000 || (i > 0 && h <= got[i-1]) {
+			t.Fatalf("sample not sorted/distinct/in range: %v", got)
+		}
+	}
+}
@@ -13,6 +13,7 @@ require (
 	github.com/onflow/flow-go-sdk v1.9.0
 	github.com/onflow/flow/protobuf/go/flow v0.4.19
 	github.com/svix/svix-webhooks v1.86.0
+	golang.org/x/net v0.48.0
 	golang.org/x/sync v0.19.0
 	golang.org/x/time v0.12.0
 	google.golang.org/grpc v1.78.0
@@ -61,7 +62,6 @@ require (
 	go.opentelemetry.io/otel v1.38.0 // indirect
 	golang.org/x/crypto v0.46.0 // indirect
 	golang.org/x/exp v0.0.0-20240119083558-1b970713d09a // indirect
-	golang.org/x/net v0.48.0 // indirect
 	golang.org/x/sys v0.39.0 // indirect
 	golang.org/x/text v0.32.0 // indirect
 	golang.org/x/xerrors v0.0.0-20240903120638-7835f813f4da // indirect
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+func TestDeployedContractsOutputTwoContracts(t *testing.T) {
+	t.Parallel()
+
+	updated := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	out := deployedContractsOutput(context.Background(), []models.SmartContract{
+		{Address: "1654653399040a61", Name: "FlowToken", Version: 3, Kind: "FT", BlockHeight: 900, UpdatedAt: updated, DependentCount: 12},
+		{Address: "1654653399040a61", Name: "FlowTokenHelper", Version: 1, BlockHeight: 120},
+	})
+	if len(out) != 2 {
+		t.Fatalf("got %d contracts; want 2", len(out))
+	}
+
+	first := out[0].(map[string]interface{})
+	if first["identifier"] != "A.1654653399040a61.FlowToken" || first["address"] != "0x1654653399040a61" {
+		t.Fatalf("first = %v", first)
+	}
+	if first["version"] != 3 || first["last_updated_height"] != uint64(900) || first["last_updated_at"] != "2026-03-01T12:00:00Z" {
+		t.Fatalf("first version/update = %v, %v, %v", first["version"], first["last_updated_height"], first["last_updated_at"])
+	}
+	if first["kind"] != "FT" || first["imported_count"] != 12 {
+		t.Fatalf("first kind/imported_count = %v, %v", first["kind"], first["imported_count"])
+	}
+
+	second := out[1].(map[string]interface{})
+	if second["name"] != "FlowTokenHelper" || second["version"] != 1 || second["last_updated_height"] != uint64(120) {
+		t.Fatalf("second = %v", second)
+	}
+	if _, ok := second["kind"]; ok {
+		t.Fatalf("second has kind %v; want it omitted", second["kind"])
+	}
+	if second["last_updated_at"] != "" {
+		t.Fatalf("second last_updated_at = %v; want empty", second["last_updated_at"])
+	}
+}
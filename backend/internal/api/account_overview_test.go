@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	flowsdk "github.com/onflow/flow-go-sdk"
+)
+
+type failingAccountClient struct{ mockFlowClient }
+
+func (*failingAccountClient) GetAccount(ctx context.Context, address flowsdk.Address) (*flowsdk.Account, error) {
+	return nil, errors.New("access node unavailable")
+}
+
+func TestFetchSectionsIsolatesFailures(t *testing.T) {
+	t.Parallel()
+
+	out, failed := fetchSections(context.Background(), 50*time.Millisecond, map[string]overviewSection{
+		"ok":     func(context.Context) (interface{}, error) { return 1, nil },
+		"broken": func(context.Context) (interface{}, error) { return nil, errors.New("boom") },
+		"slow": func(ctx context.Context) (interface{}, error) {
+			time.Sleep(time.Second) // ignores ctx; the timeout must still apply
+			return 2, nil
+		},
+	})
+	if out["ok"] != 1 || out["broken"] != nil || out["slow"] != nil || len(out) != 3 {
+		t.Fatalf("out = %v", out)
+	}
+	if !reflect.DeepEqual(failed, []string{"broken", "slow"}) {
+		t.Fatalf("failed = %v", failed)
+	}
+}
+
+func accountOverview(t *testing.T, s *Server, address string) (int, map[string]interface{}, []interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/flow/account/"+address+"/overview", nil)
+	req = mux.SetURLVars(req, map[string]string{"address": address})
+	rec := httptest.NewRecorder()
+	s.handleFlowAccountOverview(rec, req)
+
+	var resp struct {
+		Data []map[string]interface{} `json:"data"`
+		Meta map[string]interface{}   `json:"_meta"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body.String(), err)
+	}
+	if rec.Code != http.StatusOK {
+		return rec.Code, nil, nil
+	}
+	if len(resp.Data) != 1 {
+		t.Fatalf("data = %v", resp.Data)
+	}
+	failed, _ := resp.Meta["failed_sections"].([]interface{})
+	return rec.Code, resp.Data[0], failed
+}
+
+func TestHandleFlowAccountOverviewPartialFailure(t *testing.T) {
+	t.Parallel()
+
+	// No repository: every indexed section fails, the balance still comes back.
+	s := &Server{client: &mockFlowClient{account: &flowsdk.Account{Balance: 123000000}}}
+	code, data, failed := accountOverview(t, s, "0x01")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d", code)
+	}
+	for _, section := range []string{"address", "balance", "transactions", "nfts", "tokens", "creation"} {
+		if _, ok := data[section]; !ok {
+			t.Errorf("missing section %q in %v", section, data)
+		}
+	}
+	if data["address"] != "0x0000000000000001" {
+		t.Errorf("address = %v", data["address"])
+	}
+	if bal, _ := data["balance"].(map[string]interface{}); bal["flow"] != "1.23" {
+		t.Errorf("balance = %v", data["balance"])
+	}
+	if len(failed) != 4 || data["transactions"] != nil {
+		t.Errorf("failed = %v, transactions = %v", failed, data["transactions"])
+	}
+
+	// A failing access node only nulls the balance.
+	s = &Server{client: &failingAccountClient{}}
+	_, data, failed = accountOverview(t, s, "0x01")
+	if data["balance"] != nil || len(failed) != 5 {
+		t.Errorf("balance = %v failed = %v", data["balance"], failed)
+	}
+
+	if code, _, _ := accountOverview(t, s, "not-an-address"); code != http.StatusBadRequest {
+		t.Errorf("invalid address status = %d", code)
+	}
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"log"
 	"net/http"
 	"regexp"
 	"strings"
@@ -42,6 +44,52 @@ func (s *Server) handleGetAccountContractCode(w http.ResponseWriter, r *http.Req
 	})
 }
 
+const (
+	// storageOverviewTimeout bounds the storage-overview script run while
+	// serving an account; it walks every storage path and can stall on
+	// accounts with very large storage.
+	storageOverviewTimeout = 3 * time.Second
+	// storageOverviewRetryAfter is how long a failed account is skipped.
+	storageOverviewRetryAfter = time.Minute
+)
+
+// fetchStorageOverview runs the storage-overview script for addr with a short
+// timeout. ok is false when the script failed, timed out, or failed for the
+// same account within storageOverviewRetryAfter.
+func (s *Server) fetchStorageOverview(ctx context.Context, addr flowsdk.Address) (used, capacity uint64, ok bool) {
+	key := addr.Hex()
+	now := time.Now()
+	s.storageOverviewFailures.mu.Lock()
+	skip := now.Before(s.storageOverviewFailures.until[key])
+	s.storageOverviewFailures.mu.Unlock()
+	if skip {
+		return 0, 0, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, storageOverviewTimeout)
+	defer cancel()
+	raw, err := s.executeCadenceScript(ctx, cadenceStorageOverviewScript(), []cadence.Value{
+		cadence.NewAddress([8]byte(addr)),
+	})
+	if err != nil {
+		log.Printf("[WARN] storage overview for %s failed, retrying after %s: %v", key, storageOverviewRetryAfter, err)
+		s.storageOverviewFailures.mu.Lock()
+		if s.storageOverviewFailures.until == nil {
+			s.storageOverviewFailures.until = make(map[string]time.Time)
+		}
+		for k, t := range s.storageOverviewFailures.until {
+			if now.After(t) {
+				delete(s.storageOverviewFailures.until, k)
+			}
+		}
+		s.storageOverviewFailures.until[key] = now.Add(storageOverviewRetryAfter)
+		s.storageOverviewFailures.mu.Unlock()
+		return 0, 0, false
+	}
+	used, capacity = parseStorageOverview(raw)
+	return used, capacity, true
+}
+
 func (s *Server) handleGetAccountStorage(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	address := flowsdk.HexToAddress(vars["address"])
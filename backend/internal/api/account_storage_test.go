@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/onflow/cadence"
+	flowsdk "github.com/onflow/flow-go-sdk"
+)
+
+// timeoutScriptClient fails every script with a deadline error, recording
+// the deadline it was given.
+type timeoutScriptClient struct {
+	mockFlowClient
+	scripts   int
+	remaining time.Duration
+}
+
+func (c *timeoutScriptClient) ExecuteScriptAtLatestBlock(ctx context.Context, script []byte, args []cadence.Value) (cadence.Value, error) {
+	c.scripts++
+	if deadline, ok := ctx.Deadline(); ok {
+		c.remaining = time.Until(deadline)
+	}
+	return nil, context.DeadlineExceeded
+}
+
+func TestHandleFlowGetAccountStorageScriptTimeout(t *testing.T) {
+	t.Parallel()
+
+	client := &timeoutScriptClient{mockFlowClient: mockFlowClient{account: &flowsdk.Account{
+		Address: flowsdk.HexToAddress("0x01"),
+		Balance: 100000000,
+	}}}
+	s := &Server{client: client}
+
+	get := func() map[string]interface{} {
+		req := httptest.NewRequest("GET", "/flow/account/0x01", nil)
+		req = mux.SetURLVars(req, map[string]string{"address": "0x01"})
+		rec := httptest.NewRecorder()
+		s.handleFlowGetAccount(rec, req)
+		if rec.Code != 200 {
+			t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+		}
+		var resp struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Data) != 1 {
+			t.Fatalf("bad response %s: %v", rec.Body.String(), err)
+		}
+		return resp.Data[0]
+	}
+
+	row := get()
+	if row["flowBalance"] != 1.0 {
+		t.Fatalf("flowBalance = %v", row["flowBalance"])
+	}
+	for _, k := range []string{"flowStorage", "storageUsed", "storageAvailable"} {
+		if _, ok := row[k]; ok {
+			t.Fatalf("%s present after the storage script failed", k)
+		}
+	}
+	if client.scripts != 1 {
+		t.Fatalf("storage script ran %d times; want 1", client.scripts)
+	}
+	if client.remaining <= 0 || client.remaining > storageOverviewTimeout {
+		t.Fatalf("script deadline %v not bounded by %v", client.remaining, storageOverviewTimeout)
+	}
+
+	// The failure is cached, so a second request does not re-run the script.
+	get()
+	if client.scripts != 1 {
+		t.Fatalf("storage script re-ran %d times within the retry window", client.scripts)
+	}
+}
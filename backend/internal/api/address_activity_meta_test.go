@@ -0,0 +1,22 @@
+package api
+
+import "testing"
+
+func TestAddAddressActivityTotalsCompacted(t *testing.T) {
+	t.Parallel()
+
+	meta := map[string]interface{}{}
+	addAddressActivityTotals(meta, 2500000, 0)
+	if meta["total"] != int64(2500000) || meta["compacted"] != nil {
+		t.Fatalf("uncompacted meta = %#v", meta)
+	}
+
+	meta = map[string]interface{}{}
+	addAddressActivityTotals(meta, 2500000, 88000000)
+	if meta["total"] != int64(2500000) {
+		t.Errorf("compaction must keep the aggregate total, got %#v", meta["total"])
+	}
+	if meta["compacted"] != true || meta["compacted_below_height"] != uint64(88000000) {
+		t.Errorf("compacted meta = %#v", meta)
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"net/http"
+)
+
+// addressFormatParam selects how formatAddressV1 renders addresses: "0x" (the
+// default) or "bare", without the prefix.
+const addressFormatParam = "address_format"
+
+type addressFormat int
+
+const (
+	addressFormat0x addressFormat = iota
+	addressFormatBare
+)
+
+func (f addressFormat) prefix() string {
+	if f == addressFormatBare {
+		return ""
+	}
+	return "0x"
+}
+
+type addressFormatKey struct{}
+
+// parseAddressFormat reads ?address_format=. ok is false for an unknown value.
+func parseAddressFormat(r *http.Request) (f addressFormat, ok bool) {
+	switch r.URL.Query().Get(addressFormatParam) {
+	case "", "0x":
+		return addressFormat0x, true
+	case "bare":
+		return addressFormatBare, true
+	}
+	return addressFormat0x, false
+}
+
+func withAddressFormat(ctx context.Context, f addressFormat) context.Context {
+	return context.WithValue(ctx, addressFormatKey{}, f)
+}
+
+// addressFormatFrom returns the address format carried by ctx, 0x by default.
+func addressFormatFrom(ctx context.Context) addressFormat {
+	f, _ := ctx.Value(addressFormatKey{}).(addressFormat)
+	return f
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+func TestAddressFormatAcrossOutputs(t *testing.T) {
+	t.Parallel()
+
+	const (
+		payer    = "1654653399040a61"
+		auth     = "e467b9dd11fa00df"
+		receiver = "f233dcee88fe0abe"
+		coa      = "0000000000000000000000021234567890abcdef"
+		blockID  = "6a5d3e2f1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e"
+		txID     = "5e1f0c9ddbed2b5e5d0e7f2b6a3a4d0c9f1e2b3c4d5e6f708192a3b4c5d6e7f8"
+	)
+	ts := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tx := models.Transaction{
+		ID:              txID,
+		BlockHeight:     100,
+		Timestamp:       ts,
+		PayerAddress:    payer,
+		ProposerAddress: payer,
+		Authorizers:     []string{auth},
+		IsEVM:           true,
+		EVMFrom:         coa,
+		Signers:         json.RawMessage(`[{"address":"` + payer + `","key_index":0,"roles":["payer"]}]`),
+	}
+	events := []models.Event{{Type: "A." + payer + ".FlowToken.TokensDeposited", TransactionID: txID, Timestamp: ts}}
+	transfer := models.TokenTransfer{
+		TransactionID:        txID,
+		BlockHeight:          100,
+		Timestamp:            ts,
+		Amount:               "1.5",
+		FromAddress:          auth,
+		ToAddress:            receiver,
+		TokenContractAddress: payer,
+	}
+	h := commonMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var bytes8 [8]byte
+		copy(bytes8[:], []byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0})
+		data := map[string]interface{}{
+			"block":       toFlowBlockOutput(models.Block{ID: blockID, ParentID: blockID, Height: 100, Timestamp: ts}),
+			"transaction": toFlowTransactionOutput(ctx, tx, events, nil, nil, 0),
+			"transfer":    toFTTransferOutput(ctx, transfer, "FlowToken", receiver, nil, 0),
+			// A bytes8 ABI value has the shape of a Flow address but is not one.
+			"abi_bytes8": normalizeABIValue(ctx, bytes8),
+		}
+		writeAPIResponse(w, data, nil, nil)
+	}))
+	get := func(query string) (int, map[string]interface{}) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/flow/test"+query, nil))
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", query, err)
+			}
+		}
+		return rec.Code, body.Data
+	}
+
+	_, prefixed := get("")
+	_, explicit := get("?address_format=0x")
+	if !reflect.DeepEqual(prefixed, explicit) {
+		t.Fatalf("address_format=0x differs from the default")
+	}
+	code, bare := get("?address_format=bare")
+	if code != http.StatusOK {
+		t.Fatalf("address_format=bare status = %d", code)
+	}
+
+	field := func(doc map[string]interface{}, path ...interface{}) interface{} {
+		var v interface{} = doc
+		for _, p := range path {
+			switch k := p.(type) {
+			case string:
+				v = v.(map[string]interface{})[k]
+			case int:
+				v = v.([]interface{})[k]
+			}
+		}
+		return v
+	}
+	addresses := []struct {
+		path []interface{}
+		want string
+	}{
+		{[]interface{}{"transaction", "payer"}, payer},
+		{[]interface{}{"transaction", "proposer"}, payer},
+		{[]interface{}{"transaction", "authorizers", 0}, auth},
+		{[]interface{}{"transaction", "signers", 0, "address"}, payer},
+		{[]interface{}{"transaction", "events", 0, "contract_address"}, payer},
+		{[]interface{}{"transaction", "evm_from"}, coa},
+		{[]interface{}{"transfer", "address"}, receiver},
+		{[]interface{}{"transfer", "sender"}, auth},
+		{[]interface{}{"transfer", "receiver"}, receiver},
+	}
+	for _, a := range addresses {
+		if got := field(prefixed, a.path...); got != "0x"+a.want {
+			t.Errorf("default %v = %v; want 0x%s", a.path, got, a.want)
+		}
+		if got := field(bare, a.path...); got != a.want {
+			t.Errorf("bare %v = %v; want %s", a.path, got, a.want)
+		}
+	}
+	// Hashes, ids and token identifiers are not addresses and stay as they are.
+	for _, path := range [][]interface{}{
+		{"block", "id"},
+		{"transaction", "id"},
+		{"transfer", "transaction_hash"},
+		{"transfer", "token", "token"},
+		{"transaction", "events", 0, "type"},
+		{"abi_bytes8"},
+	} {
+		if got, want := field(bare, path...), field(prefixed, path...); got != want {
+			t.Errorf("bare %v = %v; want %v", path, got, want)
+		}
+	}
+
+	if code, _ := get("?address_format=short"); code != http.StatusBadRequest {
+		t.Fatalf("unknown address_format status = %d; want 400", code)
+	}
+}
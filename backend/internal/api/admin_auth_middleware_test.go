@@ -32,6 +32,43 @@ func TestAdminAuthMiddleware_RejectsWithoutConfiguredAuth(t *testing.T) {
 	}
 }
 
+func TestAdminAuthMiddleware_StaticTokenScopes(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "ops-a, ops-b")
+	t.Setenv("ADMIN_TOKEN_READONLY", "viewer")
+	t.Setenv("ADMIN_JWT_SECRET", "")
+	t.Setenv("SUPABASE_JWT_SECRET", "")
+
+	for _, tc := range []struct {
+		method, bearer string
+		want           int
+	}{
+		{http.MethodGet, "ops-a", http.StatusNoContent},
+		{http.MethodPost, "ops-b", http.StatusNoContent},
+		{http.MethodGet, "viewer", http.StatusNoContent},
+		{http.MethodHead, "viewer", http.StatusNoContent},
+		{http.MethodPost, "viewer", http.StatusForbidden},
+		{http.MethodPut, "viewer", http.StatusForbidden},
+		{http.MethodGet, "OPS-A", http.StatusForbidden},
+		{http.MethodGet, "ops-a, ops-b", http.StatusForbidden},
+		{http.MethodPost, "", http.StatusUnauthorized},
+	} {
+		if got := runAdminAuthRequestMethod(t, tc.method, tc.bearer); got != tc.want {
+			t.Errorf("%s with %q: status %d; want %d", tc.method, tc.bearer, got, tc.want)
+		}
+	}
+}
+
+func TestAdminAuthMiddleware_ExplicitlyDisabled(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "")
+	t.Setenv("ADMIN_JWT_SECRET", "")
+	t.Setenv("SUPABASE_JWT_SECRET", "")
+	t.Setenv("ADMIN_AUTH_DISABLED", "true")
+
+	if status := runAdminAuthRequestMethod(t, http.MethodPost, ""); status != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, status)
+	}
+}
+
 func TestAdminAuthMiddleware_AllowsJWTWithRoleAndTeam(t *testing.T) {
 	t.Setenv("ADMIN_TOKEN", "")
 	t.Setenv("ADMIN_JWT_SECRET", "secret-123")
@@ -97,12 +134,17 @@ func TestAdminAuthMiddleware_RejectsJWTWithoutRequiredTeam(t *testing.T) {
 
 func runAdminAuthRequest(t *testing.T, bearer string) int {
 	t.Helper()
+	return runAdminAuthRequestMethod(t, http.MethodGet, bearer)
+}
+
+func runAdminAuthRequestMethod(t *testing.T, method, bearer string) int {
+	t.Helper()
 
 	handler := adminAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusNoContent)
 	}))
 
-	req := httptest.NewRequest(http.MethodGet, "/admin/ft", nil)
+	req := httptest.NewRequest(method, "/admin/ft", nil)
 	if stringsTrimmed := strings.TrimSpace(bearer); stringsTrimmed != "" {
 		req.Header.Set("Authorization", "Bearer "+stringsTrimmed)
 	}
@@ -5,6 +5,7 @@ import (
 	"crypto/subtle"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
 	"slices"
@@ -12,6 +13,7 @@ import (
 	"sync"
 
 	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
 
 	jwtlib "github.com/golang-jwt/jwt/v5"
 	"github.com/gorilla/mux"
@@ -25,9 +27,13 @@ var (
 )
 
 // adminAuthMiddleware supports two auth methods:
-// 1) Legacy static ADMIN_TOKEN (kept for emergency fallback)
+// 1) Static tokens: ADMIN_TOKEN (csv, full access), ADMIN_TOKEN_READONLY (csv, GET/HEAD only)
 // 2) JWT (ADMIN_JWT_SECRET or SUPABASE_JWT_SECRET) with role/team claims
 //
+// With nothing configured the admin API is disabled (403). For local
+// development ADMIN_AUTH_DISABLED=true lets every request through; NewServer
+// logs a warning whenever that is set (see logAdminAuthConfig).
+//
 // JWT authorization rules (table-first):
 // - If SUPABASE_DB_URL is configured, /admin auth checks table-driven RBAC:
 //   - public.user_platform_roles.role
@@ -47,7 +53,7 @@ var (
 // - team_member: regular team member (no /admin access by default)
 func adminAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == "OPTIONS" {
+		if r.Method == "OPTIONS" || adminAuthDisabled() {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -58,12 +64,18 @@ func adminAuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// Legacy static admin token path (fallback).
-		if staticToken := strings.TrimSpace(os.Getenv("ADMIN_TOKEN")); staticToken != "" {
-			if subtle.ConstantTimeCompare([]byte(bearer), []byte(staticToken)) == 1 {
-				next.ServeHTTP(w, r)
+		// Static admin token path (fallback).
+		if matchAdminToken(bearer, parseCSVList(os.Getenv("ADMIN_TOKEN"))) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if matchAdminToken(bearer, parseCSVList(os.Getenv("ADMIN_TOKEN_READONLY"))) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				writeAPIError(w, http.StatusForbidden, "admin access denied: read-only token")
 				return
 			}
+			next.ServeHTTP(w, r)
+			return
 		}
 
 		jwtSecret := strings.TrimSpace(os.Getenv("ADMIN_JWT_SECRET"))
@@ -126,6 +138,37 @@ func adminAuthMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// matchAdminToken reports whether bearer equals one of tokens. Every token is
+// compared in constant time, so neither the position of a match nor a
+// mismatching prefix shows up in the response time.
+func matchAdminToken(bearer string, tokens []string) bool {
+	matched := 0
+	for _, tok := range tokens {
+		matched |= subtle.ConstantTimeCompare([]byte(bearer), []byte(tok))
+	}
+	return matched == 1
+}
+
+func adminAuthDisabled() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv("ADMIN_AUTH_DISABLED")), "true")
+}
+
+// logAdminAuthConfig warns at startup when /admin is open to anyone, or
+// closed to everyone because no credentials are configured.
+func logAdminAuthConfig() {
+	if adminAuthDisabled() {
+		log.Println("WARNING: ADMIN_AUTH_DISABLED=true — /admin endpoints (reprocess, checkpoint resets, ...) are UNAUTHENTICATED. Never set this outside local development.")
+		return
+	}
+	configured := len(parseCSVList(os.Getenv("ADMIN_TOKEN"))) > 0 ||
+		len(parseCSVList(os.Getenv("ADMIN_TOKEN_READONLY"))) > 0 ||
+		strings.TrimSpace(os.Getenv("ADMIN_JWT_SECRET")) != "" ||
+		strings.TrimSpace(os.Getenv("SUPABASE_JWT_SECRET")) != ""
+	if !configured {
+		log.Println("WARNING: no admin credentials configured (ADMIN_TOKEN, ADMIN_TOKEN_READONLY, ADMIN_JWT_SECRET); /admin endpoints will reject every request")
+	}
+}
+
 func getAdminAuthzDBPool() (*pgxpool.Pool, error) {
 	adminAuthzDBOnce.Do(func() {
 		dbURL := strings.TrimSpace(os.Getenv("SUPABASE_DB_URL"))
@@ -229,6 +272,18 @@ func parseCSVSet(raw string) map[string]struct{} {
 	return out
 }
 
+// parseCSVList splits raw on commas, trimming blanks but keeping case (unlike
+// parseCSVSet), for secrets such as ADMIN_TOKEN.
+func parseCSVList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if v := strings.TrimSpace(part); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 func extractRoleClaims(claims jwtlib.MapClaims) []string {
 	return extractClaimValues(claims,
 		[]string{"role"},
@@ -336,7 +391,7 @@ func (s *Server) handleAdminListFTTokens(w http.ResponseWriter, r *http.Request)
 	}
 	out := make([]map[string]interface{}, 0, len(tokens))
 	for _, t := range tokens {
-		out = append(out, ftTokenToAdmin(t))
+		out = append(out, ftTokenToAdmin(r.Context(), t))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out)}, nil)
 }
@@ -397,6 +452,80 @@ func (s *Server) handleAdminUpdateFTToken(w http.ResponseWriter, r *http.Request
 	writeAPIResponse(w, map[string]interface{}{"updated": true, "identifier": identifier}, nil, nil)
 }
 
+func ftDuplicateGroupToAdmin(ctx context.Context, g repository.FTTokenDuplicateGroup) map[string]interface{} {
+	records := make([]map[string]interface{}, 0, len(g.Records))
+	for _, t := range g.Records {
+		records = append(records, ftTokenToAdmin(ctx, t))
+	}
+	return map[string]interface{}{
+		"identifier": formatTokenIdentifier(g.ContractAddress, g.ContractName),
+		"keep":       records[0],
+		"records":    records,
+	}
+}
+
+// handleAdminListFTDuplicates lists ft_tokens rows that normalize to the same
+// contract, with the record a merge would keep first.
+func (s *Server) handleAdminListFTDuplicates(w http.ResponseWriter, r *http.Request) {
+	groups, err := s.repo.FindDuplicateFTTokens(r.Context())
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]map[string]interface{}, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, ftDuplicateGroupToAdmin(r.Context(), g))
+	}
+	writeAPIResponse(w, out, map[string]interface{}{"count": len(out)}, nil)
+}
+
+// handleAdminMergeFTTokens merges the duplicate group for the given identifier,
+// or every duplicate group when no identifier is sent.
+func (s *Server) handleAdminMergeFTTokens(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Identifier string `json:"identifier"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+	}
+	var address, name string
+	if strings.TrimSpace(body.Identifier) != "" {
+		address, name, _ = splitContractIdentifier(body.Identifier)
+		if address == "" || name == "" {
+			writeAPIError(w, http.StatusBadRequest, "invalid identifier (use A.address.ContractName)")
+			return
+		}
+	}
+
+	groups, err := s.repo.FindDuplicateFTTokens(r.Context())
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	merged := make([]map[string]interface{}, 0, len(groups))
+	for _, g := range groups {
+		if address != "" && (g.ContractAddress != address || g.ContractName != name) {
+			continue
+		}
+		token, err := s.repo.MergeDuplicateFTTokens(r.Context(), g)
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		out := ftTokenToAdmin(r.Context(), token)
+		out["merged_records"] = len(g.Records)
+		merged = append(merged, out)
+	}
+	if address != "" && len(merged) == 0 {
+		writeAPIError(w, http.StatusNotFound, "no duplicate records for "+body.Identifier)
+		return
+	}
+	writeAPIResponse(w, merged, map[string]interface{}{"count": len(merged)}, nil)
+}
+
 // --- NFT Collection CRUD ---
 
 func (s *Server) handleAdminListNFTCollections(w http.ResponseWriter, r *http.Request) {
@@ -411,7 +540,7 @@ func (s *Server) handleAdminListNFTCollections(w http.ResponseWriter, r *http.Re
 	}
 	out := make([]map[string]interface{}, 0, len(collections))
 	for _, c := range collections {
-		out = append(out, nftCollectionToAdmin(c))
+		out = append(out, nftCollectionToAdmin(r.Context(), c))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out)}, nil)
 }
@@ -543,10 +672,10 @@ func (s *Server) handleAdminDeleteAccountLabel(w http.ResponseWriter, r *http.Re
 
 // --- output helpers ---
 
-func ftTokenToAdmin(t models.FTToken) map[string]interface{} {
+func ftTokenToAdmin(ctx context.Context, t models.FTToken) map[string]interface{} {
 	return map[string]interface{}{
 		"identifier":       formatTokenIdentifier(t.ContractAddress, t.ContractName),
-		"contract_address": formatAddressV1(t.ContractAddress),
+		"contract_address": formatAddressV1(ctx, t.ContractAddress),
 		"contract_name":    t.ContractName,
 		"name":             t.Name,
 		"symbol":           t.Symbol,
@@ -562,10 +691,10 @@ func ftTokenToAdmin(t models.FTToken) map[string]interface{} {
 	}
 }
 
-func nftCollectionToAdmin(c models.NFTCollection) map[string]interface{} {
+func nftCollectionToAdmin(ctx context.Context, c models.NFTCollection) map[string]interface{} {
 	return map[string]interface{}{
 		"identifier":       formatTokenIdentifier(c.ContractAddress, c.ContractName),
-		"contract_address": formatAddressV1(c.ContractAddress),
+		"contract_address": formatAddressV1(ctx, c.ContractAddress),
 		"contract_name":    c.ContractName,
 		"name":             c.Name,
 		"symbol":           c.Symbol,
@@ -594,7 +723,7 @@ func (s *Server) handleAdminListContracts(w http.ResponseWriter, r *http.Request
 	for _, c := range contracts {
 		out = append(out, map[string]interface{}{
 			"identifier":      formatTokenIdentifier(c.Address, c.Name),
-			"address":         formatAddressV1(c.Address),
+			"address":         formatAddressV1(r.Context(), c.Address),
 			"name":            c.Name,
 			"kind":            c.Kind,
 			"is_verified":     c.IsVerified,
@@ -657,3 +786,12 @@ func (s *Server) handleAdminRefreshDependentCounts(w http.ResponseWriter, r *htt
 	}
 	writeAPIResponse(w, map[string]interface{}{"ok": true, "updated": updated}, nil, nil)
 }
+
+func (s *Server) handleAdminRefreshNFTMintBurnCounts(w http.ResponseWriter, r *http.Request) {
+	updated, err := s.repo.RefreshNFTMintBurnCounts(r.Context())
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeAPIResponse(w, map[string]interface{}{"ok": true, "updated": updated}, nil, nil)
+}
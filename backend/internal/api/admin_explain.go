@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"flowscan-clone/internal/repository"
+)
+
+// handleAdminExplain runs EXPLAIN (ANALYZE, BUFFERS) on a whitelisted
+// repository query and returns its JSON plan:
+//
+//	POST /admin/explain {"query_name": "GetTransactionsByAddress", "params": {"address": "0x..."}}
+//
+// Params left out fall back to sample values.
+func (s *Server) handleAdminExplain(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		QueryName string                   `json:"query_name"`
+		Params    repository.ExplainParams `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	name := strings.TrimSpace(req.QueryName)
+	if !repository.IsExplainQuery(name) {
+		writeAPIError(w, http.StatusBadRequest, "unknown query_name; expected one of: "+strings.Join(repository.ExplainQueryNames(), ", "))
+		return
+	}
+	if s.repo == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "database not configured")
+		return
+	}
+
+	plan, err := s.repo.ExplainQuery(r.Context(), name, req.Params)
+	if err != nil {
+		writeRepoError(w, err, "")
+		return
+	}
+	writeAPIResponse(w, map[string]interface{}{
+		"query_name": name,
+		"plan":       plan,
+	}, nil, nil)
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleAdminExplainRejectsUnknownQuery(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{}
+	for _, body := range []string{
+		`{"query_name": "SELECT * FROM raw.transactions"}`,
+		`{"query_name": ""}`,
+		`not json`,
+	} {
+		rec := httptest.NewRecorder()
+		s.handleAdminExplain(rec, httptest.NewRequest("POST", "/admin/explain", strings.NewReader(body)))
+		if rec.Code != 400 {
+			t.Fatalf("%s: status = %d; want 400", body, rec.Code)
+		}
+	}
+}
+
+func TestHandleAdminExplainKnownQueryReachesRepository(t *testing.T) {
+	t.Parallel()
+
+	// Without a repository a whitelisted name gets past validation and
+	// fails on the missing database instead.
+	rec := httptest.NewRecorder()
+	(&Server{}).handleAdminExplain(rec, httptest.NewRequest("POST", "/admin/explain",
+		strings.NewReader(`{"query_name": "GetTransactionsByAddress", "params": {"limit": 5}}`)))
+	if rec.Code != 503 {
+		t.Fatalf("status = %d; want 503", rec.Code)
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"flowscan-clone/internal/contractid"
+	"flowscan-clone/internal/repository"
+)
+
+// handleAdminRebuildFTHoldings rebuilds one token's app.ft_holdings from its
+// transfers in the background, without resetting ft_holdings_worker:
+//
+//	POST /admin/rebuild-ft-holdings {"token": "A.1654653399040a61.FlowToken"}
+//
+// Progress is reported through /admin/jobs.
+func (s *Server) handleAdminRebuildFTHoldings(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	id := contractid.Parse(strings.TrimSpace(req.Token))
+	if id.Address == "" || id.Name == "" {
+		writeAPIError(w, http.StatusBadRequest, "token must be a contract identifier like A.1654653399040a61.FlowToken")
+		return
+	}
+	if s.repo == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "database not configured")
+		return
+	}
+	token := id.String()
+
+	job := s.reserveAdminJob(w, "rebuild_ft_holdings", map[string]interface{}{"token": token})
+	if job == nil {
+		return
+	}
+	log.Printf("[admin] Rebuilding ft holdings for %s (job %s)", token, job.ID)
+	s.adminJobs.run(job, func(ctx context.Context, j *adminJob) error {
+		res, err := s.repo.RebuildFTHoldingsForToken(ctx, token, func(done, total int) {
+			j.setTotal(total)
+			j.advance(true)
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("[admin] Rebuilt ft holdings for %s up to height %d: %d holders, %d zeroed", res.Token, res.UpToHeight, res.Holders, res.Zeroed)
+		return nil
+	})
+
+	writeAPIResponse(w, map[string]interface{}{
+		"message": "FT holdings rebuild started in background",
+		"job_id":  job.ID,
+		"token":   token,
+	}, nil, nil)
+}
+
+// maxReconcileDriftPage caps the drift rows returned per request; drift_total
+// always counts all of them.
+const maxReconcileDriftPage = 1000
+
+// handleAdminReconcileBalances compares one token's app.ft_holdings with a
+// replay of its transfers and reports the holders that drifted:
+//
+//	GET  /admin/reconcile-balances?token=A.1654653399040a61.FlowToken
+//	POST /admin/reconcile-balances?token=A.1654653399040a61.FlowToken
+//
+// GET is a dry run. POST also corrects the drifted rows unless dry_run=true;
+// corrections need POST so read-only admin tokens cannot make them. The drift
+// list is paged with limit/offset.
+func (s *Server) handleAdminReconcileBalances(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id := contractid.Parse(strings.TrimSpace(q.Get("token")))
+	if id.Address == "" || id.Name == "" {
+		writeAPIError(w, http.StatusBadRequest, "token must be a contract identifier like A.1654653399040a61.FlowToken")
+		return
+	}
+	dryRun := r.Method == http.MethodGet
+	if v := q.Get("dry_run"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "dry_run must be true or false")
+			return
+		}
+		if !b && r.Method == http.MethodGet {
+			writeAPIError(w, http.StatusBadRequest, "dry_run=false requires POST")
+			return
+		}
+		dryRun = b
+	}
+	if s.repo == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "database not configured")
+		return
+	}
+	limit, offset := parseLimitOffsetWithMax(r, maxReconcileDriftPage)
+
+	res, err := s.repo.ReconcileFTHoldingsForToken(r.Context(), id.String(), dryRun)
+	if err != nil {
+		writeRepoError(w, err, "")
+		return
+	}
+	if !dryRun {
+		for _, d := range res.Drift {
+			log.Printf("[admin] Reconciled ft holding %s %s: stored=%s expected=%s delta=%s", res.Token, d.Address, d.Stored, d.Expected, d.Delta)
+		}
+	}
+
+	drift := res.Drift
+	if offset >= len(drift) {
+		drift = nil
+	} else {
+		drift = drift[offset:min(offset+limit, len(drift))]
+	}
+	if drift == nil {
+		drift = []repository.FTBalanceDrift{}
+	}
+	writeAPIResponse(w, map[string]interface{}{
+		"token":        res.Token,
+		"up_to_height": res.UpToHeight,
+		"dry_run":      res.DryRun,
+		"checked":      res.Checked,
+		"skipped":      res.Skipped,
+		"drift_total":  len(res.Drift),
+		"corrected":    res.Corrected,
+		"drift":        drift,
+	}, map[string]interface{}{"limit": limit, "offset": offset}, nil)
+}
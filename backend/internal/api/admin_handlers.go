@@ -6,14 +6,18 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"flowscan-clone/internal/config"
+	"flowscan-clone/internal/flow"
 	"flowscan-clone/internal/ingester"
 	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
 
 	"github.com/onflow/cadence"
 	flowsdk "github.com/onflow/flow-go-sdk"
@@ -84,7 +88,8 @@ func (s *Server) handleAdminRefetchTokenMetadata(w http.ResponseWriter, r *http.
 }
 
 // handleAdminBatchFetchMetadata fetches metadata for all FT tokens and NFT collections
-// that are missing metadata, using up to 20 concurrent goroutines.
+// that are missing metadata, using up to 20 concurrent goroutines by default.
+// Override with ?concurrency=N (max 50).
 func (s *Server) handleAdminBatchFetchMetadata(w http.ResponseWriter, r *http.Request) {
 	if s.client == nil {
 		writeAPIError(w, http.StatusServiceUnavailable, "no Flow client configured")
@@ -92,7 +97,15 @@ func (s *Server) handleAdminBatchFetchMetadata(w http.ResponseWriter, r *http.Re
 	}
 
 	ctx := r.Context()
-	const maxConcurrency = 20
+	maxConcurrency := 20
+	if v := r.URL.Query().Get("concurrency"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 50 {
+			writeAPIError(w, http.StatusBadRequest, "concurrency must be between 1 and 50")
+			return
+		}
+		maxConcurrency = n
+	}
 
 	ftMissing, err := s.repo.ListFTTokensMissingMetadata(ctx, 500)
 	if err != nil {
@@ -113,92 +126,112 @@ func (s *Server) handleAdminBatchFetchMetadata(w http.ResponseWriter, r *http.Re
 		Error      string `json:"error,omitempty"`
 	}
 
-	// Process FT tokens concurrently.
-	var ftMu sync.Mutex
-	ftUpdated := 0
-	ftFailed := 0
-	var details []detail
-	sem := make(chan struct{}, maxConcurrency)
-	var wg sync.WaitGroup
-
+	// Dedupe targets so each (type, identifier) is fetched once per request.
+	var targets []metadataTarget
+	seen := make(map[string]bool)
 	for _, t := range ftMissing {
-		wg.Add(1)
-		go func(t models.FTToken) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
+		mt := metadataTarget{Kind: "ft", Address: t.ContractAddress, Name: t.ContractName}
+		if !seen[mt.key()] {
+			seen[mt.key()] = true
+			targets = append(targets, mt)
+		}
+	}
+	for _, c := range nftMissing {
+		mt := metadataTarget{Kind: "nft", Address: c.ContractAddress, Name: c.ContractName}
+		if !seen[mt.key()] {
+			seen[mt.key()] = true
+			targets = append(targets, mt)
+		}
+	}
 
-			id := fmt.Sprintf("A.%s.%s", t.ContractAddress, t.ContractName)
-			md, ok := fetchFTMetadataViaClient(ctx, s.client, t.ContractAddress, t.ContractName)
-			if !ok {
-				ftMu.Lock()
-				ftFailed++
-				details = append(details, detail{Identifier: id, Type: "ft", Status: "failed", Error: "metadata not available"})
-				ftMu.Unlock()
-				return
-			}
-			if err := s.repo.UpsertFTTokens(ctx, []models.FTToken{md}); err != nil {
-				log.Printf("[admin] ft upsert error %s: %v", id, err)
-				ftMu.Lock()
-				ftFailed++
-				details = append(details, detail{Identifier: id, Type: "ft", Status: "failed", Error: err.Error()})
-				ftMu.Unlock()
-				return
-			}
-			ftMu.Lock()
-			ftUpdated++
-			details = append(details, detail{Identifier: id, Type: "ft", Status: "updated"})
-			ftMu.Unlock()
-		}(t)
+	// All counters and details are guarded by mu.
+	var mu sync.Mutex
+	counts := map[string]int{}
+	var details []detail
+	record := func(mt metadataTarget, status, errMsg string) {
+		mu.Lock()
+		defer mu.Unlock()
+		counts[mt.Kind+"_"+status]++
+		details = append(details, detail{Identifier: mt.identifier(), Type: mt.Kind, Status: status, Error: errMsg})
 	}
-	wg.Wait()
 
-	// Process NFT collections concurrently.
-	nftUpdated := 0
-	nftFailed := 0
-	for _, c := range nftMissing {
+	sem := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+	for _, mt := range targets {
 		wg.Add(1)
-		go func(c models.NFTCollection) {
+		go func(mt metadataTarget) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			id := fmt.Sprintf("A.%s.%s", c.ContractAddress, c.ContractName)
-			md, ok := fetchNFTCollectionMetadataViaClient(ctx, s.client, c.ContractAddress, c.ContractName)
+			md, ok := s.fetchMetadataDeduped(ctx, mt)
 			if !ok {
-				ftMu.Lock()
-				nftFailed++
-				details = append(details, detail{Identifier: id, Type: "nft", Status: "failed", Error: "metadata not available"})
-				ftMu.Unlock()
+				record(mt, "failed", "metadata not available")
 				return
 			}
-			if err := s.repo.UpsertNFTCollections(ctx, []models.NFTCollection{md}); err != nil {
-				log.Printf("[admin] nft upsert error %s: %v", id, err)
-				ftMu.Lock()
-				nftFailed++
-				details = append(details, detail{Identifier: id, Type: "nft", Status: "failed", Error: err.Error()})
-				ftMu.Unlock()
+			var upsertErr error
+			switch v := md.(type) {
+			case models.FTToken:
+				upsertErr = s.repo.UpsertFTTokens(ctx, []models.FTToken{v})
+			case models.NFTCollection:
+				upsertErr = s.repo.UpsertNFTCollections(ctx, []models.NFTCollection{v})
+			}
+			if upsertErr != nil {
+				log.Printf("[admin] %s upsert error %s: %v", mt.Kind, mt.identifier(), upsertErr)
+				record(mt, "failed", upsertErr.Error())
 				return
 			}
-			ftMu.Lock()
-			nftUpdated++
-			details = append(details, detail{Identifier: id, Type: "nft", Status: "updated"})
-			ftMu.Unlock()
-		}(c)
+			record(mt, "updated", "")
+		}(mt)
 	}
 	wg.Wait()
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"ft_total":    len(ftMissing),
-		"ft_updated":  ftUpdated,
-		"ft_failed":   ftFailed,
+		"ft_updated":  counts["ft_updated"],
+		"ft_failed":   counts["ft_failed"],
 		"nft_total":   len(nftMissing),
-		"nft_updated": nftUpdated,
-		"nft_failed":  nftFailed,
+		"nft_updated": counts["nft_updated"],
+		"nft_failed":  counts["nft_failed"],
 		"details":     details,
 	})
 }
 
+// metadataTarget identifies an FT or NFT contract whose metadata should be fetched.
+type metadataTarget struct {
+	Kind    string // "ft" or "nft"
+	Address string
+	Name    string
+}
+
+func (t metadataTarget) identifier() string {
+	return fmt.Sprintf("A.%s.%s", t.Address, t.Name)
+}
+
+func (t metadataTarget) key() string {
+	return t.Kind + ":" + t.identifier()
+}
+
+// fetchMetadataDeduped runs the Cadence metadata script for t, collapsing
+// concurrent requests for the same target (e.g. overlapping batch runs) into a
+// single call. Returns a models.FTToken or models.NFTCollection.
+func (s *Server) fetchMetadataDeduped(ctx context.Context, t metadataTarget) (interface{}, bool) {
+	type fetched struct {
+		md interface{}
+		ok bool
+	}
+	v, _, _ := s.metadataFlight.Do(t.key(), func() (interface{}, error) {
+		if t.Kind == "nft" {
+			md, ok := fetchNFTCollectionMetadataViaClient(ctx, s.client, t.Address, t.Name)
+			return fetched{md, ok}, nil
+		}
+		md, ok := fetchFTMetadataViaClient(ctx, s.client, t.Address, t.Name)
+		return fetched{md, ok}, nil
+	})
+	f := v.(fetched)
+	return f.md, f.ok
+}
+
 // handleAdminRefetchBridge re-checks EVM bridge addresses for all tokens/collections
 // that have metadata but no evm_address.
 func (s *Server) handleAdminRefetchBridge(w http.ResponseWriter, r *http.Request) {
@@ -990,17 +1023,20 @@ func adminExtractSocials(v cadence.Value) []byte {
 // handleAdminRefreshDailyStats triggers a full re-aggregation of daily_stats.
 // POST /admin/refresh-daily-stats
 func (s *Server) handleAdminRefreshDailyStats(w http.ResponseWriter, r *http.Request) {
-	log.Printf("[admin] Triggering full daily stats refresh")
-	go func() {
-		ctx := context.Background()
-		if err := s.repo.RefreshDailyStats(ctx, true); err != nil {
-			log.Printf("[admin] daily stats refresh error: %v", err)
-		} else {
-			log.Printf("[admin] daily stats refresh complete")
-		}
-	}()
+	job := s.reserveAdminJob(w, "refresh_daily_stats", nil)
+	if job == nil {
+		return
+	}
+	log.Printf("[admin] Triggering full daily stats refresh (job %s)", job.ID)
+	s.adminJobs.run(job, func(ctx context.Context, j *adminJob) error {
+		j.setTotal(1)
+		err := s.repo.RefreshDailyStats(ctx, true)
+		j.advance(err == nil)
+		return err
+	})
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message": "Daily stats refresh started in background",
+		"job_id":  job.ID,
 	})
 }
 
@@ -1020,19 +1056,28 @@ func (s *Server) handleAdminBackfillAnalytics(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	log.Printf("[admin] Triggering analytics backfill range [%d, %d)", req.FromHeight, req.ToHeight)
-	go func() {
-		ctx := context.Background()
+	job := s.reserveAdminJob(w, "backfill_analytics", map[string]interface{}{
+		"from_height": req.FromHeight,
+		"to_height":   req.ToHeight,
+	})
+	if job == nil {
+		return
+	}
+	log.Printf("[admin] Triggering analytics backfill range [%d, %d) (job %s)", req.FromHeight, req.ToHeight, job.ID)
+	s.adminJobs.run(job, func(ctx context.Context, j *adminJob) error {
+		j.setTotal(1)
 		worker := ingester.NewAnalyticsDeriverWorker(s.repo)
-		if err := worker.ProcessRange(ctx, req.FromHeight, req.ToHeight); err != nil {
-			log.Printf("[admin] analytics backfill error [%d,%d): %v", req.FromHeight, req.ToHeight, err)
-			return
+		err := worker.ProcessRange(ctx, req.FromHeight, req.ToHeight)
+		j.advance(err == nil)
+		if err != nil {
+			return fmt.Errorf("analytics backfill [%d,%d): %w", req.FromHeight, req.ToHeight, err)
 		}
-		log.Printf("[admin] analytics backfill complete [%d, %d)", req.FromHeight, req.ToHeight)
-	}()
+		return nil
+	})
 
 	writeAPIResponse(w, map[string]interface{}{
 		"message":     "Analytics backfill started in background",
+		"job_id":      job.ID,
 		"from_height": req.FromHeight,
 		"to_height":   req.ToHeight,
 	}, nil, nil)
@@ -1107,6 +1152,12 @@ func (s *Server) handleAdminResetHistoryDeriver(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Per-processor UP cursors must follow, or they would skip the range again.
+	if _, err := s.repo.SetCheckpointGroup(ctx, "history_deriver", req.Height); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "reset history_deriver processors: "+err.Error())
+		return
+	}
+
 	log.Printf("[admin] Reset history_deriver UP and DOWN checkpoints to %d", req.Height)
 
 	writeAPIResponse(w, map[string]interface{}{
@@ -1115,6 +1166,21 @@ func (s *Server) handleAdminResetHistoryDeriver(w http.ResponseWriter, r *http.R
 	}, nil, nil)
 }
 
+// handleAdminHistoryDeriverProgress returns the per-processor UP cursors of
+// the running history derivers.
+// GET /admin/history-deriver/progress
+func (s *Server) handleAdminHistoryDeriverProgress(w http.ResponseWriter, r *http.Request) {
+	s.historyDeriversMu.Lock()
+	derivers := append([]*ingester.HistoryDeriver(nil), s.historyDerivers...)
+	s.historyDeriversMu.Unlock()
+
+	out := make([]ingester.HistoryDeriverProgress, 0, len(derivers))
+	for _, hd := range derivers {
+		out = append(out, hd.Progress())
+	}
+	writeAPIResponse(w, out, nil, nil)
+}
+
 // handleAdminResolveErrors marks indexing errors as resolved for a given worker.
 // POST /admin/resolve-errors  {"worker": "accounts_worker"}
 func (s *Server) handleAdminResolveErrors(w http.ResponseWriter, r *http.Request) {
@@ -1162,6 +1228,22 @@ func (s *Server) handleAdminListSkippedRanges(w http.ResponseWriter, r *http.Req
 	}, nil, nil)
 }
 
+// handleAdminListReorgs returns recently detected block reorgs (stored block id
+// replaced at a height), newest first.
+// GET /admin/reorgs?limit=100
+func (s *Server) handleAdminListReorgs(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	rows, err := s.repo.ListBlockReorgs(r.Context(), limit)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeAPIResponse(w, map[string]interface{}{
+		"reorgs": rows,
+		"count":  len(rows),
+	}, nil, nil)
+}
+
 // handleAdminRedirectHistoryIngester resets the history_ingester checkpoint to a
 // specific height so it starts backfilling downward from there. This is used to
 // fill raw block gaps between the history ingester's current position and the
@@ -1200,38 +1282,93 @@ func (s *Server) handleAdminRedirectHistoryIngester(w http.ResponseWriter, r *ht
 	}, nil, nil)
 }
 
-// handleAdminListErrors returns unresolved indexing errors with optional worker filter.
-// GET /admin/errors?worker=accounts_worker&limit=100
+// handleAdminListErrors returns indexing errors plus per-error_hash counts.
+// GET /admin/errors?worker=accounts_worker&error_hash=...&resolved=false&height_from=&height_to=&cursor=&limit=100
 func (s *Server) handleAdminListErrors(w http.ResponseWriter, r *http.Request) {
-	worker := r.URL.Query().Get("worker")
+	q := r.URL.Query()
 	limit := 100
-	if l := r.URL.Query().Get("limit"); l != "" {
+	if l := q.Get("limit"); l != "" {
 		if v, err := fmt.Sscanf(l, "%d", &limit); err != nil || v != 1 {
 			limit = 100
 		}
 	}
 
-	errors, err := s.repo.ListIndexingErrors(r.Context(), worker, limit)
+	filter, err := parseIndexingErrorFilter(q)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	errors, err := s.repo.ListIndexingErrors(r.Context(), filter, limit)
 	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
 	}
 
-	// Also get top error messages grouped
-	counts, err := s.repo.GetErrorMessageCounts(r.Context(), worker, 20)
+	// Also get top error groups (by error_hash) under the same filter
+	counts, err := s.repo.GetErrorMessageCounts(r.Context(), filter, 20)
 	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
 	}
 
+	var nextCursor string
+	if len(errors) > 0 && len(errors) == limit {
+		nextCursor = strconv.FormatInt(errors[len(errors)-1].ID, 10)
+	}
+
 	writeAPIResponse(w, map[string]interface{}{
 		"errors":         errors,
 		"message_counts": counts,
 		"total":          len(errors),
-		"filter_worker":  worker,
+		"filter_worker":  filter.Worker,
+		"next_cursor":    nextCursor,
 	}, nil, nil)
 }
 
+// parseIndexingErrorFilter reads worker, error_hash, resolved (true|false|all,
+// default false), height_from, height_to and cursor query params.
+func parseIndexingErrorFilter(q url.Values) (repository.IndexingErrorFilter, error) {
+	f := repository.IndexingErrorFilter{
+		Worker:    q.Get("worker"),
+		ErrorHash: q.Get("error_hash"),
+	}
+	switch strings.ToLower(q.Get("resolved")) {
+	case "", "false":
+		resolved := false
+		f.Resolved = &resolved
+	case "true":
+		resolved := true
+		f.Resolved = &resolved
+	case "all":
+	default:
+		return f, fmt.Errorf("resolved must be one of true, false, all")
+	}
+	for _, p := range []struct {
+		name string
+		dst  *uint64
+	}{{"height_from", &f.HeightFrom}, {"height_to", &f.HeightTo}} {
+		if v := q.Get(p.name); v != "" {
+			h, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s", p.name)
+			}
+			*p.dst = h
+		}
+	}
+	if f.HeightTo > 0 && f.HeightFrom > f.HeightTo {
+		return f, fmt.Errorf("height_from must be <= height_to")
+	}
+	if v := q.Get("cursor"); v != "" {
+		c, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || c < 0 {
+			return f, fmt.Errorf("invalid cursor")
+		}
+		f.Cursor = c
+	}
+	return f, nil
+}
+
 // handleAdminBackfillStakingBlocks runs staking_worker on specific block heights.
 // POST /admin/backfill-staking  {"heights": [142651980, 141896604, ...]}
 func (s *Server) handleAdminBackfillStakingBlocks(w http.ResponseWriter, r *http.Request) {
@@ -1295,6 +1432,7 @@ func (s *Server) handleAdminReprocessWorker(w http.ResponseWriter, r *http.Reque
 		Concurrency             int    `json:"concurrency"`
 		DeleteStakingTransfers  bool   `json:"delete_staking_transfers"`
 		Resume                  bool   `json:"resume"`
+		DryRun                  bool   `json:"dry_run"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeAPIError(w, http.StatusBadRequest, "invalid JSON body")
@@ -1363,6 +1501,41 @@ func (s *Server) handleAdminReprocessWorker(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Dry run: diff what the processor would write, synchronously, with no
+	// deletes, writes or checkpoint updates.
+	if req.DryRun {
+		dr, ok := proc.(ingester.DryRunner)
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("dry_run is not supported for %s (supported: token_worker)", req.Worker))
+			return
+		}
+		chunks := (req.ToHeight - req.FromHeight + req.ChunkSize - 1) / req.ChunkSize
+		if chunks > reprocessDryRunMaxChunks {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("dry_run covers at most %d chunks (got %d); narrow the range or raise chunk_size", reprocessDryRunMaxChunks, chunks))
+			return
+		}
+		out, err := runReprocessDryRun(r.Context(), dr, req.FromHeight, req.ToHeight, req.ChunkSize, req.Concurrency)
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		out["worker"] = req.Worker
+		writeAPIResponse(w, out, nil, nil)
+		return
+	}
+
+	// Claim a job slot before any side effects so a saturated registry rejects cleanly.
+	job := s.reserveAdminJob(w, "reprocess_"+req.Worker, map[string]interface{}{
+		"worker":      req.Worker,
+		"from_height": req.FromHeight,
+		"to_height":   req.ToHeight,
+		"chunk_size":  req.ChunkSize,
+		"concurrency": req.Concurrency,
+	})
+	if job == nil {
+		return
+	}
+
 	// Optional: delete staking FT transfers before re-processing
 	if req.DeleteStakingTransfers && req.Worker == "token_worker" {
 		stakingContracts := []string{"FlowIDTableStaking", "FlowStakingCollection", "LockedTokens", "FlowEpoch", "FlowDKG", "FlowClusterQC"}
@@ -1403,9 +1576,11 @@ func (s *Server) handleAdminReprocessWorker(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Process in parallel with bounded concurrency.
-	// Run in background goroutine so the HTTP response returns immediately.
+	// Run as a registered admin job so the HTTP response returns immediately
+	// and the job can be listed/cancelled via /admin/jobs.
 	// Track completed chunks to maintain a contiguous checkpoint.
-	go func() {
+	job.setTotal(totalChunks)
+	s.adminJobs.run(job, func(jobCtx context.Context, j *adminJob) error {
 		sem := make(chan struct{}, req.Concurrency)
 		var mu sync.Mutex
 		processed := 0
@@ -1435,20 +1610,26 @@ func (s *Server) handleAdminReprocessWorker(w http.ResponseWriter, r *http.Reque
 
 		for i, c := range chunks {
 			sem <- struct{}{}
+			if jobCtx.Err() != nil {
+				<-sem
+				break
+			}
 			go func(idx int, ch chunk) {
 				defer func() { <-sem }()
 				chunkTimeout := 2 * time.Minute
 				if req.Worker == "proposer_key_backfill" {
 					chunkTimeout = 30 * time.Minute
 				}
-				ctx, cancel := context.WithTimeout(context.Background(), chunkTimeout)
+				ctx, cancel := context.WithTimeout(jobCtx, chunkTimeout)
 				defer cancel()
 				if err := proc.ProcessRange(ctx, ch.from, ch.to); err != nil {
+					j.advance(false)
 					mu.Lock()
 					errored++
 					mu.Unlock()
 					log.Printf("[admin] reprocess-worker: %s chunk [%d,%d) FAILED: %v", req.Worker, ch.from, ch.to, err)
 				} else {
+					j.advance(true)
 					mu.Lock()
 					processed++
 					chunkDone[idx] = true
@@ -1474,6 +1655,11 @@ func (s *Server) handleAdminReprocessWorker(w http.ResponseWriter, r *http.Reque
 		mu.Unlock()
 
 		elapsed := time.Since(startTime).Round(time.Second)
+		if jobCtx.Err() != nil {
+			log.Printf("[admin] reprocess-worker: %s CANCELLED processed=%d errored=%d total=%d elapsed=%s (resume:true continues from checkpoint)",
+				req.Worker, processed, errored, totalChunks, elapsed)
+			return jobCtx.Err()
+		}
 		log.Printf("[admin] reprocess-worker: %s DONE processed=%d errored=%d total=%d elapsed=%s",
 			req.Worker, processed, errored, totalChunks, elapsed)
 
@@ -1487,9 +1673,11 @@ func (s *Server) handleAdminReprocessWorker(w http.ResponseWriter, r *http.Reque
 				log.Printf("[admin] reprocess-worker: %s job config cleared (complete)", req.Worker)
 			}
 		}
-	}()
+		return nil
+	})
 
 	writeAPIResponse(w, map[string]interface{}{
+		"job_id":       job.ID,
 		"worker":       req.Worker,
 		"from_height":  req.FromHeight,
 		"to_height":    req.ToHeight,
@@ -1502,6 +1690,78 @@ func (s *Server) handleAdminReprocessWorker(w http.ResponseWriter, r *http.Reque
 	}, nil, nil)
 }
 
+const (
+	reprocessDryRunMaxChunks   = 100
+	reprocessDryRunSampleLimit = 20
+)
+
+// runReprocessDryRun runs DryRunRange over [from, to) in chunks and returns
+// per-chunk counts plus per-table totals with a sample of changed rows.
+func runReprocessDryRun(ctx context.Context, dr ingester.DryRunner, from, to, chunkSize uint64, concurrency int) (map[string]interface{}, error) {
+	type chunkResult struct {
+		From   uint64                 `json:"from_height"`
+		To     uint64                 `json:"to_height"`
+		Tables []repository.TableDiff `json:"tables"`
+	}
+	var chunks []chunkResult
+	for h := from; h < to; h += chunkSize {
+		end := h + chunkSize
+		if end > to {
+			end = to
+		}
+		chunks = append(chunks, chunkResult{From: h, To: end})
+	}
+
+	sem := make(chan struct{}, concurrency)
+	errs := make([]error, len(chunks))
+	var wg sync.WaitGroup
+	for i := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			tables, err := dr.DryRunRange(ctx, chunks[idx].From, chunks[idx].To, reprocessDryRunSampleLimit)
+			if err != nil {
+				errs[idx] = fmt.Errorf("chunk [%d,%d): %w", chunks[idx].From, chunks[idx].To, err)
+				return
+			}
+			chunks[idx].Tables = tables
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Totals keep the samples; per-chunk entries carry counts only.
+	var totals []repository.TableDiff
+	index := map[string]int{}
+	for ci := range chunks {
+		for ti, t := range chunks[ci].Tables {
+			idx, ok := index[t.Table]
+			if !ok {
+				idx = len(totals)
+				index[t.Table] = idx
+				totals = append(totals, repository.TableDiff{Table: t.Table})
+			}
+			totals[idx].Add(t, reprocessDryRunSampleLimit)
+			chunks[ci].Tables[ti].Samples = nil
+		}
+	}
+
+	return map[string]interface{}{
+		"dry_run":     true,
+		"from_height": from,
+		"to_height":   to,
+		"chunk_size":  chunkSize,
+		"totals":      totals,
+		"chunks":      chunks,
+	}, nil
+}
+
 // handleAdminBackfillContracts fetches ALL contracts from one or more Flow addresses
 // via RPC and upserts them into app.smart_contracts with code and kind classification.
 // This is useful for backfilling contracts that were deployed before the deriver covered
@@ -1523,9 +1783,9 @@ func (s *Server) handleAdminBackfillContracts(w http.ResponseWriter, r *http.Req
 		_ = json.NewDecoder(r.Body).Decode(&req)
 	}
 
-	// Default: EVM bridge address
+	// Default: EVM bridge address for the configured network
 	if len(req.Addresses) == 0 {
-		req.Addresses = []string{"1e4aa0b87d10b141"}
+		req.Addresses = []string{config.Addr().FlowEVMBridge}
 	}
 
 	type result struct {
@@ -1595,3 +1855,79 @@ func (s *Server) handleAdminBackfillContracts(w http.ResponseWriter, r *http.Req
 
 	writeAPIResponse(w, results, nil, nil)
 }
+
+// maxRangeChecksumSpan bounds a single checksum request; larger ranges should be
+// split by the caller (checksums of sub-ranges can be compared independently).
+const maxRangeChecksumSpan = 100000
+
+// handleAdminRangeChecksum returns an order-independent checksum over the
+// (height, tx_count, event_count) tuples in [from, to] so operators can verify
+// two indexer replicas agree on a range.
+// GET /admin/range-checksum?from=1000&to=2000
+func (s *Server) handleAdminRangeChecksum(w http.ResponseWriter, r *http.Request) {
+	from, err := parseHeightParam(r.URL.Query().Get("from"))
+	if err != nil || from == nil {
+		writeAPIError(w, http.StatusBadRequest, "from is required and must be a block height")
+		return
+	}
+	to, err := parseHeightParam(r.URL.Query().Get("to"))
+	if err != nil || to == nil {
+		writeAPIError(w, http.StatusBadRequest, "to is required and must be a block height")
+		return
+	}
+	if *to < *from {
+		writeAPIError(w, http.StatusBadRequest, "to must be >= from")
+		return
+	}
+	if *to-*from >= maxRangeChecksumSpan {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("range too large (max %d blocks)", maxRangeChecksumSpan))
+		return
+	}
+
+	sum, err := s.repo.ComputeRangeChecksum(r.Context(), *from, *to)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeAPIResponse(w, sum, nil, nil)
+}
+
+// handleAdminReindexBlock re-fetches one block from the access node and
+// overwrites the stored block, transactions and events, returning the stored
+// counts before and after. Use it for a block whose tx_count mismatches or that
+// looks corrupt; repair_indexing_anomalies handles the batch case.
+// POST /admin/reindex-block  {"height": 140000000}
+func (s *Server) handleAdminReindexBlock(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Height uint64 `json:"height"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Height == 0 {
+		writeAPIError(w, http.StatusBadRequest, "height is required and must be > 0")
+		return
+	}
+
+	// Prefer the history client: it has every spork's nodes, so any height can be served.
+	var flowCli *flow.Client
+	if c, ok := s.historyClient.(*flow.Client); ok && c != nil {
+		flowCli = c
+	} else if c, ok := s.client.(*flow.Client); ok && c != nil {
+		flowCli = c
+	}
+	if flowCli == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "no Flow client configured")
+		return
+	}
+
+	res, err := ingester.ReindexBlock(r.Context(), ingester.NewWorker(flowCli), s.repo, req.Height)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("[admin] Reindexed block %d from %s: tx %d -> %d, events %d -> %d",
+		res.Height, res.Node, res.Before.TxCount, res.After.TxCount, res.Before.EventCount, res.After.EventCount)
+	writeAPIResponse(w, res, nil, nil)
+}
@@ -2,7 +2,10 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"sync"
 	"testing"
 	"time"
 
@@ -217,3 +220,92 @@ func TestFetchNFTCollectionMetadata_TopShot(t *testing.T) {
 		t.Error("expected non-empty name")
 	}
 }
+
+// countingFlowClient counts script executions per (script, contract name) and
+// holds each call until release is closed so concurrent callers overlap.
+type countingFlowClient struct {
+	realFlowClient
+	mu      sync.Mutex
+	calls   map[string]int
+	release chan struct{}
+}
+
+func (c *countingFlowClient) ExecuteScriptAtLatestBlock(ctx context.Context, script []byte, args []cadence.Value) (cadence.Value, error) {
+	c.mu.Lock()
+	c.calls[fmt.Sprintf("%d:%s", len(script), args[1])]++
+	c.mu.Unlock()
+	<-c.release
+	return nil, errors.New("no metadata")
+}
+
+func TestFetchMetadataDedupedCollapsesConcurrentCalls(t *testing.T) {
+	client := &countingFlowClient{calls: map[string]int{}, release: make(chan struct{})}
+	s := &Server{client: client}
+
+	targets := []metadataTarget{
+		{Kind: "ft", Address: "1654653399040a61", Name: "FlowToken"},
+		{Kind: "ft", Address: "b19436aae4d94622", Name: "FiatToken"},
+		{Kind: "nft", Address: "0b2a3299cc857e29", Name: "TopShot"},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, mt := range targets {
+			wg.Add(1)
+			go func(mt metadataTarget) {
+				defer wg.Done()
+				if _, ok := s.fetchMetadataDeduped(context.Background(), mt); ok {
+					t.Errorf("expected fetch failure for %s", mt.key())
+				}
+			}(mt)
+		}
+	}
+	// Let every caller join its in-flight call before the fake returns.
+	time.Sleep(100 * time.Millisecond)
+	close(client.release)
+	wg.Wait()
+
+	if len(client.calls) != len(targets) {
+		t.Fatalf("expected %d distinct script calls, got %v", len(targets), client.calls)
+	}
+	for k, n := range client.calls {
+		if n != 1 {
+			t.Fatalf("expected one call for %s, got %d", k, n)
+		}
+	}
+}
+
+func TestParseIndexingErrorFilter(t *testing.T) {
+	t.Parallel()
+
+	f, err := parseIndexingErrorFilter(url.Values{})
+	if err != nil || f.Resolved == nil || *f.Resolved {
+		t.Fatalf("default should be unresolved only: %+v, %v", f, err)
+	}
+
+	f, err = parseIndexingErrorFilter(url.Values{
+		"worker":      {"evm_worker"},
+		"error_hash":  {"ab12"},
+		"resolved":    {"all"},
+		"height_from": {"100"},
+		"height_to":   {"200"},
+		"cursor":      {"55"},
+	})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if f.Worker != "evm_worker" || f.ErrorHash != "ab12" || f.Resolved != nil || f.HeightFrom != 100 || f.HeightTo != 200 || f.Cursor != 55 {
+		t.Fatalf("unexpected filter: %+v", f)
+	}
+
+	for _, bad := range []url.Values{
+		{"resolved": {"maybe"}},
+		{"height_from": {"x"}},
+		{"height_from": {"300"}, "height_to": {"200"}},
+		{"cursor": {"-1"}},
+	} {
+		if _, err := parseIndexingErrorFilter(bad); err == nil {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
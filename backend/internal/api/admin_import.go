@@ -0,0 +1,286 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"flowscan-clone/internal/models"
+)
+
+// --- Bulk CSV import ---
+
+const (
+	csvImportMaxBytes = 5 << 20 // 5 MiB
+	csvImportMaxRows  = 10000
+)
+
+var (
+	cadenceIdentifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	evmAddressRe        = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+	// errCSVSkipRow marks a valid row that is intentionally not imported (e.g. a
+	// duplicate key already seen earlier in the file).
+	errCSVSkipRow = errors.New("skip row")
+)
+
+type csvImportError struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+type csvImportResult struct {
+	Imported int              `json:"imported"`
+	Skipped  int              `json:"skipped"`
+	Errors   []csvImportError `json:"errors"`
+}
+
+// parseImportCSV reads a CSV with a header row and calls fn for every data row,
+// keyed by lower-cased header name. Rows for which fn returns an error are
+// reported in the result (errCSVSkipRow counts as skipped) and the remaining
+// rows are still processed. The returned error is only set for problems with
+// the file as a whole: missing columns, too many rows, or unreadable input.
+func parseImportCSV(r io.Reader, required []string, maxRows int, fn func(row map[string]string) error) (csvImportResult, error) {
+	res := csvImportResult{Errors: []csvImportError{}}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return res, fmt.Errorf("empty CSV")
+	}
+	if err != nil {
+		return res, fmt.Errorf("read CSV header: %w", err)
+	}
+	cols := make([]string, len(header))
+	present := make(map[string]bool, len(header))
+	for i, h := range header {
+		cols[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		present[cols[i]] = true
+	}
+	for _, c := range required {
+		if !present[c] {
+			return res, fmt.Errorf("missing required column %q", c)
+		}
+	}
+
+	rows := 0
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		rows++
+		if rows > maxRows {
+			return res, fmt.Errorf("too many rows (max %d)", maxRows)
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			res.Errors = append(res.Errors, csvImportError{Line: perr.StartLine, Reason: perr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return res, fmt.Errorf("read CSV: %w", err)
+		}
+
+		line, _ := cr.FieldPos(0)
+		if len(record) != len(cols) {
+			res.Errors = append(res.Errors, csvImportError{
+				Line:   line,
+				Reason: fmt.Sprintf("expected %d columns, got %d", len(cols), len(record)),
+			})
+			continue
+		}
+		row := make(map[string]string, len(cols))
+		for i, c := range cols {
+			row[c] = strings.TrimSpace(record[i])
+		}
+
+		switch err := fn(row); {
+		case err == nil:
+			res.Imported++
+		case errors.Is(err, errCSVSkipRow):
+			res.Skipped++
+		default:
+			res.Errors = append(res.Errors, csvImportError{Line: line, Reason: err.Error()})
+		}
+	}
+	return res, nil
+}
+
+// parseLabelsCSV parses address label rows (address, tag[, label, category]).
+// Addresses are stored 0x-prefixed, matching GetLabelsByAddresses lookups.
+func parseLabelsCSV(r io.Reader, maxRows int) ([]models.AccountLabel, csvImportResult, error) {
+	var labels []models.AccountLabel
+	seen := map[string]bool{}
+	res, err := parseImportCSV(r, []string{"address", "tag"}, maxRows, func(row map[string]string) error {
+		addr := normalizeFlowAddr(row["address"])
+		if addr == "" {
+			return fmt.Errorf("invalid address %q", row["address"])
+		}
+		if row["tag"] == "" {
+			return fmt.Errorf("tag is required")
+		}
+		category := row["category"]
+		if category == "" {
+			category = "custom"
+		}
+		key := addr + "/" + row["tag"]
+		if seen[key] {
+			return errCSVSkipRow
+		}
+		seen[key] = true
+		labels = append(labels, models.AccountLabel{
+			Address:  "0x" + addr,
+			Tag:      row["tag"],
+			Label:    row["label"],
+			Category: category,
+		})
+		return nil
+	})
+	return labels, res, err
+}
+
+// parseTokensCSV parses token metadata rows. The type column selects "ft"
+// (default) or "nft"; logo maps to square_image for NFT collections.
+func parseTokensCSV(r io.Reader, maxRows int) ([]models.FTToken, []models.NFTCollection, csvImportResult, error) {
+	var (
+		tokens      []models.FTToken
+		collections []models.NFTCollection
+	)
+	seen := map[string]bool{}
+	res, err := parseImportCSV(r, []string{"contract_address", "contract_name"}, maxRows, func(row map[string]string) error {
+		kind := strings.ToLower(row["type"])
+		if kind == "" {
+			kind = "ft"
+		}
+		if kind != "ft" && kind != "nft" {
+			return fmt.Errorf("type must be 'ft' or 'nft', got %q", row["type"])
+		}
+		addr := normalizeFlowAddr(row["contract_address"])
+		if addr == "" {
+			return fmt.Errorf("invalid contract_address %q", row["contract_address"])
+		}
+		name := row["contract_name"]
+		if !cadenceIdentifierRe.MatchString(name) {
+			return fmt.Errorf("invalid contract_name %q", name)
+		}
+		if evm := row["evm_address"]; evm != "" && !evmAddressRe.MatchString(evm) {
+			return fmt.Errorf("invalid evm_address %q", evm)
+		}
+
+		key := kind + "/A." + addr + "." + name
+		if seen[key] {
+			return errCSVSkipRow
+		}
+
+		if kind == "nft" {
+			square := row["square_image"]
+			if square == "" {
+				square = row["logo"]
+			}
+			seen[key] = true
+			collections = append(collections, models.NFTCollection{
+				ContractAddress: addr,
+				ContractName:    name,
+				Name:            row["name"],
+				Symbol:          row["symbol"],
+				Description:     row["description"],
+				ExternalURL:     row["external_url"],
+				SquareImage:     square,
+				BannerImage:     row["banner_image"],
+				EVMAddress:      strings.ToLower(row["evm_address"]),
+			})
+			return nil
+		}
+
+		decimals := 0
+		if v := row["decimals"]; v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 || n > 36 {
+				return fmt.Errorf("invalid decimals %q", v)
+			}
+			decimals = n
+		}
+		seen[key] = true
+		tokens = append(tokens, models.FTToken{
+			ContractAddress: addr,
+			ContractName:    name,
+			Name:            row["name"],
+			Symbol:          row["symbol"],
+			Decimals:        decimals,
+			Description:     row["description"],
+			ExternalURL:     row["external_url"],
+			Logo:            row["logo"],
+			EVMAddress:      strings.ToLower(row["evm_address"]),
+		})
+		return nil
+	})
+	return tokens, collections, res, err
+}
+
+// readImportUpload returns the "file" part of a multipart upload, enforcing
+// csvImportMaxBytes on the whole request body.
+func readImportUpload(w http.ResponseWriter, r *http.Request) (io.ReadCloser, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, csvImportMaxBytes)
+	if err := r.ParseMultipartForm(csvImportMaxBytes); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeAPIError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("file too large (max %d bytes)", csvImportMaxBytes))
+			return nil, false
+		}
+		writeAPIError(w, http.StatusBadRequest, "expected multipart/form-data with a 'file' field")
+		return nil, false
+	}
+	f, _, err := r.FormFile("file")
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "missing 'file' field")
+		return nil, false
+	}
+	return f, true
+}
+
+func (s *Server) handleAdminImportLabels(w http.ResponseWriter, r *http.Request) {
+	f, ok := readImportUpload(w, r)
+	if !ok {
+		return
+	}
+	defer f.Close()
+
+	labels, res, err := parseLabelsCSV(f, csvImportMaxRows)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.repo.ImportAccountLabels(r.Context(), labels); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeAPIResponse(w, res, nil, nil)
+}
+
+func (s *Server) handleAdminImportTokens(w http.ResponseWriter, r *http.Request) {
+	f, ok := readImportUpload(w, r)
+	if !ok {
+		return
+	}
+	defer f.Close()
+
+	tokens, collections, res, err := parseTokensCSV(f, csvImportMaxRows)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.repo.ImportTokenMetadata(r.Context(), tokens, collections); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeAPIResponse(w, res, nil, nil)
+}
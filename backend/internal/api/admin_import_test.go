@@ -0,0 +1,87 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLabelsCSVReportsMalformedRows(t *testing.T) {
+	t.Parallel()
+
+	in := strings.Join([]string{
+		"address,tag,label,category",
+		"0x1654653399040a61,flow,Flow Token,token",
+		"not-an-address,bad,,",
+		"1654653399040a61,flow,Duplicate,",
+		"0xe467b9dd11fa00df,,missing tag,",
+		"0xe467b9dd11fa00df,service",
+		`0xf233dcee88fe0abe,"unterminated,,`,
+	}, "\n")
+
+	labels, res, err := parseLabelsCSV(strings.NewReader(in), 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(labels) != 1 || labels[0].Address != "0x1654653399040a61" || labels[0].Tag != "flow" || labels[0].Category != "token" {
+		t.Fatalf("unexpected labels: %+v", labels)
+	}
+	if res.Imported != 1 || res.Skipped != 1 {
+		t.Fatalf("imported=%d skipped=%d, want 1/1", res.Imported, res.Skipped)
+	}
+	var lines []int
+	for _, e := range res.Errors {
+		lines = append(lines, e.Line)
+	}
+	if !reflect.DeepEqual(lines, []int{3, 5, 6, 7}) {
+		t.Fatalf("error lines = %v (%+v)", lines, res.Errors)
+	}
+}
+
+func TestParseLabelsCSVRequiresColumns(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := parseLabelsCSV(strings.NewReader("address,label\n0x01,foo\n"), 100); err == nil {
+		t.Fatal("expected missing tag column to be rejected")
+	}
+}
+
+func TestParseLabelsCSVRowLimit(t *testing.T) {
+	t.Parallel()
+
+	in := "address,tag\n0x01,a\n0x02,b\n0x03,c\n"
+	if _, _, err := parseLabelsCSV(strings.NewReader(in), 2); err == nil {
+		t.Fatal("expected row limit to be enforced")
+	}
+}
+
+func TestParseTokensCSV(t *testing.T) {
+	t.Parallel()
+
+	in := strings.Join([]string{
+		"type,contract_address,contract_name,name,symbol,decimals,logo,evm_address",
+		",0x1654653399040a61,FlowToken,Flow,FLOW,8,https://example.com/flow.svg,",
+		"nft,0x0b2a3299cc857e29,TopShot,NBA Top Shot,,,https://example.com/ts.png,",
+		"ft,0x1654653399040a61,Flow-Token,,,,,",
+		"ft,0xb19436aae4d94622,FiatToken,USDC,USDC,abc,,",
+		"ft,0xb19436aae4d94622,FiatToken,USDC,USDC,6,,0xF1815bd50389c46847f0Bda824eC8da914045D14",
+		"sft,0xb19436aae4d94622,FiatToken,,,,,",
+	}, "\n")
+
+	tokens, collections, res, err := parseTokensCSV(strings.NewReader(in), 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tokens) != 2 || tokens[0].ContractName != "FlowToken" || tokens[0].Decimals != 8 {
+		t.Fatalf("unexpected tokens: %+v", tokens)
+	}
+	if tokens[1].EVMAddress != "0xf1815bd50389c46847f0bda824ec8da914045d14" {
+		t.Fatalf("evm address not normalized: %q", tokens[1].EVMAddress)
+	}
+	if len(collections) != 1 || collections[0].SquareImage != "https://example.com/ts.png" {
+		t.Fatalf("unexpected collections: %+v", collections)
+	}
+	if res.Imported != 3 || len(res.Errors) != 3 {
+		t.Fatalf("imported=%d errors=%+v", res.Imported, res.Errors)
+	}
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// defaultAdminMaxJobs is the number of admin background jobs (reprocess,
+// analytics backfill, daily stats refresh) allowed to run at once. Override
+// with ADMIN_MAX_JOBS.
+const defaultAdminMaxJobs = 2
+
+var errAdminJobsSaturated = errors.New("too many admin jobs running")
+
+// adminJob is one running admin background job. Progress is reported by the
+// job body through setTotal/advance.
+type adminJob struct {
+	ID        string
+	Kind      string
+	Params    map[string]interface{}
+	StartedAt time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	total  atomic.Int64
+	done   atomic.Int64
+	failed atomic.Int64
+}
+
+func (j *adminJob) setTotal(n int) { j.total.Store(int64(n)) }
+
+// advance records one finished unit of work.
+func (j *adminJob) advance(ok bool) {
+	if ok {
+		j.done.Add(1)
+	} else {
+		j.failed.Add(1)
+	}
+}
+
+func (j *adminJob) info() map[string]interface{} {
+	return map[string]interface{}{
+		"id":         j.ID,
+		"kind":       j.Kind,
+		"params":     j.Params,
+		"started_at": j.StartedAt.UTC().Format(time.RFC3339),
+		"total":      j.total.Load(),
+		"done":       j.done.Load(),
+		"failed":     j.failed.Load(),
+		"cancelled":  j.ctx.Err() != nil,
+	}
+}
+
+// adminJobRegistry caps and tracks running admin background jobs.
+type adminJobRegistry struct {
+	mu   sync.Mutex
+	max  int
+	seq  uint64
+	jobs map[string]*adminJob
+}
+
+func newAdminJobRegistry(max int) *adminJobRegistry {
+	if max <= 0 {
+		max = defaultAdminMaxJobs
+	}
+	return &adminJobRegistry{max: max, jobs: make(map[string]*adminJob)}
+}
+
+// reserve claims a slot for a new job, or returns errAdminJobsSaturated. The
+// caller must either run the job or release it.
+func (r *adminJobRegistry) reserve(kind string, params map[string]interface{}) (*adminJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.jobs) >= r.max {
+		return nil, errAdminJobsSaturated
+	}
+	r.seq++
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &adminJob{
+		ID:        fmt.Sprintf("%s-%d", kind, r.seq),
+		Kind:      kind,
+		Params:    params,
+		StartedAt: time.Now(),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	r.jobs[j.ID] = j
+	return j, nil
+}
+
+// release frees the job's slot and cancels its context.
+func (r *adminJobRegistry) release(j *adminJob) {
+	j.cancel()
+	r.mu.Lock()
+	delete(r.jobs, j.ID)
+	r.mu.Unlock()
+}
+
+// run executes fn in the background with the job's context and releases the
+// slot when it returns.
+func (r *adminJobRegistry) run(j *adminJob, fn func(ctx context.Context, j *adminJob) error) {
+	go func() {
+		defer r.release(j)
+		if err := fn(j.ctx, j); err != nil {
+			log.Printf("[admin] job %s failed: %v", j.ID, err)
+			return
+		}
+		log.Printf("[admin] job %s finished (done=%d failed=%d)", j.ID, j.done.Load(), j.failed.Load())
+	}()
+}
+
+// cancelJob cancels a running job; it reports false if the id is unknown.
+func (r *adminJobRegistry) cancelJob(id string) bool {
+	r.mu.Lock()
+	j, ok := r.jobs[id]
+	r.mu.Unlock()
+	if ok {
+		j.cancel()
+	}
+	return ok
+}
+
+func (r *adminJobRegistry) list() []map[string]interface{} {
+	r.mu.Lock()
+	jobs := make([]*adminJob, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		jobs = append(jobs, j)
+	}
+	r.mu.Unlock()
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].StartedAt.Before(jobs[k].StartedAt) })
+	out := make([]map[string]interface{}, 0, len(jobs))
+	for _, j := range jobs {
+		out = append(out, j.info())
+	}
+	return out
+}
+
+// reserveAdminJob claims a job slot, writing a 429 and returning nil when the
+// registry is saturated.
+func (s *Server) reserveAdminJob(w http.ResponseWriter, kind string, params map[string]interface{}) *adminJob {
+	j, err := s.adminJobs.reserve(kind, params)
+	if err != nil {
+		writeAPIError(w, http.StatusTooManyRequests, fmt.Sprintf("%v (max %d); retry later or cancel one via /admin/jobs", err, s.adminJobs.max))
+		return nil
+	}
+	return j
+}
+
+// handleAdminListJobs lists running admin background jobs with progress.
+// GET /admin/jobs
+func (s *Server) handleAdminListJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := s.adminJobs.list()
+	writeAPIResponse(w, map[string]interface{}{
+		"jobs":     jobs,
+		"count":    len(jobs),
+		"max_jobs": s.adminJobs.max,
+	}, nil, nil)
+}
+
+// handleAdminCancelJob cancels a running admin job via its context. The job
+// stops at its next cancellation check and then frees its slot.
+// POST /admin/jobs/{id}/cancel
+func (s *Server) handleAdminCancelJob(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !s.adminJobs.cancelJob(id) {
+		writeAPIError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	log.Printf("[admin] job %s cancel requested", id)
+	writeAPIResponse(w, map[string]interface{}{"id": id, "cancelled": true}, nil, nil)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// blockingJob reports progress, signals started, then runs until its context
+// is cancelled and reports that on stopped.
+func blockingJob(started chan<- struct{}, stopped chan<- string) func(ctx context.Context, j *adminJob) error {
+	return func(ctx context.Context, j *adminJob) error {
+		j.setTotal(10)
+		j.advance(true)
+		started <- struct{}{}
+		<-ctx.Done()
+		stopped <- j.ID
+		return ctx.Err()
+	}
+}
+
+func TestAdminJobsLimitAndCancel(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{adminJobs: newAdminJobRegistry(2)}
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/jobs", s.handleAdminListJobs).Methods("GET")
+	router.HandleFunc("/admin/jobs/{id}/cancel", s.handleAdminCancelJob).Methods("POST")
+
+	started := make(chan struct{}, 2)
+	stopped := make(chan string, 3)
+	var ids []string
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		job := s.reserveAdminJob(rec, "test", map[string]interface{}{"n": i})
+		if job == nil {
+			t.Fatalf("job %d rejected: %s", i, rec.Body.String())
+		}
+		s.adminJobs.run(job, blockingJob(started, stopped))
+		ids = append(ids, job.ID)
+	}
+	<-started
+	<-started
+
+	// Past the limit: rejected with 429.
+	rec := httptest.NewRecorder()
+	if job := s.reserveAdminJob(rec, "test", nil); job != nil {
+		t.Fatalf("third job admitted past limit: %s", job.ID)
+	}
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("saturated status = %d, want 429", rec.Code)
+	}
+
+	// Both jobs are listed with progress.
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/jobs", nil))
+	var env struct {
+		Data struct {
+			Jobs  []map[string]interface{} `json:"jobs"`
+			Count int                      `json:"count"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&env); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if env.Data.Count != 2 || env.Data.Jobs[0]["total"] != float64(10) || env.Data.Jobs[0]["done"] != float64(1) {
+		t.Fatalf("jobs = %+v", env.Data)
+	}
+
+	// Cancel the first job via the endpoint; its context is cancelled and the slot frees.
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/jobs/"+ids[0]+"/cancel", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("cancel status = %d: %s", rec.Code, rec.Body.String())
+	}
+	select {
+	case id := <-stopped:
+		if id != ids[0] {
+			t.Fatalf("stopped %s, want %s", id, ids[0])
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("cancelled job did not stop")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	var job *adminJob
+	for job == nil && time.Now().Before(deadline) {
+		job = s.reserveAdminJob(httptest.NewRecorder(), "test", nil)
+		if job == nil {
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	if job == nil {
+		t.Fatal("slot not freed after cancellation")
+	}
+	s.adminJobs.release(job)
+	s.adminJobs.cancelJob(ids[1])
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/jobs/nope/cancel", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown job cancel status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+)
+
+// PriceCacheHooks connects the price cache admin endpoints to the DB loader and
+// the historical price backfill, both of which live in main.
+type PriceCacheHooks struct {
+	Reload   func(ctx context.Context) error
+	Backfill func(ctx context.Context)
+}
+
+// SetPriceCacheHooks enables POST /admin/price-cache/reload and /backfill.
+func (s *Server) SetPriceCacheHooks(h PriceCacheHooks) {
+	s.priceCacheHooks = h
+}
+
+func (s *Server) priceCacheStats() []map[string]interface{} {
+	stats := s.priceCache.Stats()
+	out := make([]map[string]interface{}, 0, len(stats))
+	for _, st := range stats {
+		row := map[string]interface{}{
+			"asset":        st.Asset,
+			"points":       st.Points,
+			"latest_price": st.LatestPrice,
+			"latest_date":  nil,
+			"last_updated": nil,
+		}
+		if !st.LatestDate.IsZero() {
+			row["latest_date"] = st.LatestDate.UTC().Format("2006-01-02")
+		}
+		if !st.UpdatedAt.IsZero() {
+			row["last_updated"] = st.UpdatedAt.UTC().Format(time.RFC3339)
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+// handleAdminPriceCache lists the assets held in the in-memory price cache.
+// GET /admin/price-cache
+func (s *Server) handleAdminPriceCache(w http.ResponseWriter, r *http.Request) {
+	stats := s.priceCacheStats()
+	writeAPIResponse(w, stats, map[string]interface{}{"count": len(stats)}, nil)
+}
+
+// handleAdminReloadPriceCache reloads the price cache from app.market_prices
+// and returns the refreshed summary.
+// POST /admin/price-cache/reload
+func (s *Server) handleAdminReloadPriceCache(w http.ResponseWriter, r *http.Request) {
+	if s.priceCacheHooks.Reload == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "price cache reload not configured")
+		return
+	}
+	if err := s.priceCacheHooks.Reload(r.Context()); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("[admin] Price cache reloaded")
+	stats := s.priceCacheStats()
+	writeAPIResponse(w, stats, map[string]interface{}{"count": len(stats)}, nil)
+}
+
+// handleAdminBackfillPriceCache starts the one-shot historical price backfill
+// in the background. Only one backfill runs at a time.
+// POST /admin/price-cache/backfill
+func (s *Server) handleAdminBackfillPriceCache(w http.ResponseWriter, r *http.Request) {
+	if s.priceCacheHooks.Backfill == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "price backfill not configured")
+		return
+	}
+	if !s.priceBackfillRunning.CompareAndSwap(false, true) {
+		writeAPIError(w, http.StatusConflict, "price backfill already running")
+		return
+	}
+
+	log.Printf("[admin] Triggering historical price backfill")
+	go func() {
+		defer s.priceBackfillRunning.Store(false)
+		s.priceCacheHooks.Backfill(context.Background())
+		log.Printf("[admin] Historical price backfill complete")
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+	writeAPIResponse(w, map[string]interface{}{
+		"message": "Price backfill started in background",
+	}, nil, nil)
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/market"
+)
+
+func decodePriceCacheRows(t *testing.T, rec *httptest.ResponseRecorder) []map[string]interface{} {
+	t.Helper()
+	var env struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&env); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return env.Data
+}
+
+func TestAdminPriceCacheListsAssets(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{priceCache: market.NewPriceCache()}
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	s.priceCache.Load("flow", []market.DailyPrice{{Date: day, Price: 0.7}, {Date: day.AddDate(0, 0, 1), Price: 0.75}})
+	s.priceCache.Load("USDC", []market.DailyPrice{{Date: day, Price: 1}})
+
+	rec := httptest.NewRecorder()
+	s.handleAdminPriceCache(rec, httptest.NewRequest(http.MethodGet, "/admin/price-cache", nil))
+	rows := decodePriceCacheRows(t, rec)
+	if len(rows) != 2 {
+		t.Fatalf("rows = %v, want 2 assets", rows)
+	}
+	flow := rows[0]
+	if flow["asset"] != "FLOW" || flow["points"] != float64(2) || flow["latest_price"] != 0.75 || flow["latest_date"] != "2026-03-02" {
+		t.Fatalf("FLOW row = %v", flow)
+	}
+	if flow["last_updated"] == nil {
+		t.Fatalf("FLOW row missing last_updated: %v", flow)
+	}
+	if rows[1]["asset"] != "USDC" || rows[1]["points"] != float64(1) {
+		t.Fatalf("USDC row = %v", rows[1])
+	}
+}
+
+func TestAdminPriceCacheReload(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{priceCache: market.NewPriceCache()}
+	rec := httptest.NewRecorder()
+	s.handleAdminReloadPriceCache(rec, httptest.NewRequest(http.MethodPost, "/admin/price-cache/reload", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unconfigured reload status = %d, want 503", rec.Code)
+	}
+
+	s.SetPriceCacheHooks(PriceCacheHooks{Reload: func(context.Context) error {
+		s.priceCache.Load("FLOW", []market.DailyPrice{{Date: time.Now().UTC().Truncate(24 * time.Hour), Price: 0.8}})
+		return nil
+	}})
+	rec = httptest.NewRecorder()
+	s.handleAdminReloadPriceCache(rec, httptest.NewRequest(http.MethodPost, "/admin/price-cache/reload", nil))
+	if rows := decodePriceCacheRows(t, rec); rec.Code != http.StatusOK || len(rows) != 1 || rows[0]["latest_price"] != 0.8 {
+		t.Fatalf("reload = %d %v", rec.Code, rows)
+	}
+
+	s.SetPriceCacheHooks(PriceCacheHooks{Reload: func(context.Context) error { return errors.New("db down") }})
+	rec = httptest.NewRecorder()
+	s.handleAdminReloadPriceCache(rec, httptest.NewRequest(http.MethodPost, "/admin/price-cache/reload", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("failing reload status = %d, want 500", rec.Code)
+	}
+}
+
+func TestAdminPriceCacheBackfillRunsOnce(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	s := &Server{priceCache: market.NewPriceCache()}
+	s.SetPriceCacheHooks(PriceCacheHooks{Backfill: func(context.Context) {
+		<-release
+		close(done)
+	}})
+
+	rec := httptest.NewRecorder()
+	s.handleAdminBackfillPriceCache(rec, httptest.NewRequest(http.MethodPost, "/admin/price-cache/backfill", nil))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("first backfill status = %d, want 202", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	s.handleAdminBackfillPriceCache(rec, httptest.NewRequest(http.MethodPost, "/admin/price-cache/backfill", nil))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("concurrent backfill status = %d, want 409", rec.Code)
+	}
+
+	close(release)
+	<-done
+}
@@ -364,6 +364,8 @@ func TestIntegration_Base(t *testing.T) {
 		{"status", "/status", 200, false, true, []string{"latest_height", "chain_id"}},
 		{"openapi_yaml", "/openapi.yaml", 200, false, false, nil},
 		{"openapi_json", "/openapi.json", 200, false, false, nil},
+		{"daily_metric_tx_count", "/insights/daily/metric/tx_count?from=2026-01-01&to=2026-01-07", 200, true, false, []string{"date", "value"}},
+		{"top_event_types", "/insights/events/top?limit=5", 200, true, false, []string{"type", "count", "share"}},
 	})
 }
 
@@ -410,6 +412,7 @@ func TestIntegration_FlowAccounts(t *testing.T) {
 		{"account_ft_holdings", sub("/flow/v1/account/{address}/ft/holding"), 200, true, false, nil},
 		{"account_ft_list", sub("/flow/v1/account/{address}/ft"), 200, true, false, nil},
 		{"account_nft_list", sub("/flow/v1/account/{address}/nft"), 200, true, false, nil},
+		{"account_overview", sub("/flow/account/{address}/overview"), 200, true, false, []string{"address", "balance", "transactions", "nfts", "tokens", "creation"}},
 	})
 }
 
@@ -430,6 +433,7 @@ func TestIntegration_FlowNFT(t *testing.T) {
 func TestIntegration_FlowContracts(t *testing.T) {
 	runEndpointTests(t, []endpointTest{
 		{"contract_list", "/flow/v1/contract?limit=5", 200, true, false, nil},
+		{"script_popular", "/flow/script/popular?limit=5", 200, true, false, []string{"script_hash", "tx_count"}},
 	})
 }
 
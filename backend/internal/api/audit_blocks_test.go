@@ -109,3 +109,23 @@ func TestAudit_BlockListPagination(t *testing.T) {
 		}
 	}
 }
+
+func TestAudit_BlockListRecomputedEventCounts(t *testing.T) {
+	// The batch count for a page of blocks must match each block's own count.
+	list := fetchEnvelopeList(t, "/flow/block?limit=10&recompute_counts=true")
+	if len(list) == 0 {
+		t.Skip("no blocks returned")
+	}
+	for _, b := range list {
+		height := formatHeight(b["height"])
+		single := fetchEnvelopeList(t, "/flow/block?height="+height+"&recompute_counts=true")
+		if len(single) != 1 {
+			t.Fatalf("block %s: got %d results", height, len(single))
+		}
+		batch := int(toFloat64(b["system_event_count"]))
+		perBlock := int(toFloat64(single[0]["system_event_count"]))
+		if batch != perBlock {
+			t.Errorf("block %s: batch event count %d != per-block %d", height, batch, perBlock)
+		}
+	}
+}
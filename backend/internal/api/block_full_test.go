@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
+)
+
+func TestToFlowBlockFullOutput(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	txID := func(i int) string { return fmt.Sprintf("%064x", 0xb10c00+i) }
+	full := &repository.BlockFull{
+		Block: models.Block{Height: 100, ID: "b10c", Timestamp: ts, CollectionCount: 2, TxCount: 4},
+		Collections: []models.Collection{
+			{ID: "c0a", BlockHeight: 100, TransactionIDs: []string{txID(0), txID(1)}},
+			{ID: "c0b", BlockHeight: 100, TransactionIDs: []string{txID(2)}},
+		},
+		TxTotal: 4,
+	}
+	for i := 0; i < 4; i++ {
+		full.Transactions = append(full.Transactions, models.Transaction{
+			ID: txID(i), BlockHeight: 100, TransactionIndex: i, Timestamp: ts, PayerAddress: "1654653399040a61",
+			Events: []models.Event{{TransactionID: txID(i), EventIndex: 0, Type: "A.1654653399040a61.FlowToken.TokensDeposited", Timestamp: ts}},
+		})
+	}
+	fees := map[string]float64{txID(1): 0.001}
+	tags := map[string][]string{txID(2): {"FT_TRANSFER"}}
+
+	out := toFlowBlockFullOutput(context.Background(), full, nil, tags, fees)
+	if out["id"] != "b10c" || out["height"] != uint64(100) || out["collection_count"] != 2 {
+		t.Fatalf("block fields = %v", out)
+	}
+
+	colls := out["collections"].([]map[string]interface{})
+	if len(colls) != 2 || colls[0]["id"] != "c0a" || colls[0]["transaction_count"] != 2 ||
+		!reflect.DeepEqual(colls[1]["transaction_ids"], []string{txID(2)}) {
+		t.Fatalf("collections = %v", colls)
+	}
+
+	txs := out["transactions"].([]map[string]interface{})
+	wantColl := []interface{}{"c0a", "c0a", "c0b", nil} // the last one is the system transaction
+	if len(txs) != len(wantColl) {
+		t.Fatalf("transactions = %d; want %d", len(txs), len(wantColl))
+	}
+	for i, tx := range txs {
+		if tx["id"] != txID(i) {
+			t.Errorf("tx %d id = %v", i, tx["id"])
+		}
+		if got := tx["collection_id"]; got != wantColl[i] {
+			t.Errorf("tx %d collection_id = %v; want %v", i, got, wantColl[i])
+		}
+		if evs := tx["events"].([]map[string]interface{}); len(evs) != 1 || evs[0]["transaction"] != txID(i) {
+			t.Errorf("tx %d events = %v", i, evs)
+		}
+	}
+	if txs[1]["fee"] != 0.001 || !reflect.DeepEqual(txs[2]["tags"], []string{"FT_TRANSFER"}) {
+		t.Fatalf("fee/tags not attached: %v / %v", txs[1]["fee"], txs[2]["tags"])
+	}
+}
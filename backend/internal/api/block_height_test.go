@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBlockHeightValidation(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(nil, nil, "0", 0)
+	// Pretend the forward ingester has indexed up to 1000 so far-future heights
+	// are rejected without a repository.
+	server.indexedHeightCache.height = 1000
+	server.indexedHeightCache.updatedAt = time.Now()
+
+	// A dedicated client address keeps these requests out of the shared
+	// anonymous rate-limit bucket used by other route tests.
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "198.51.100.60:4000"
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	cases := []struct {
+		path string
+		want int
+		msg  string
+	}{
+		{"/flow/block/abc", http.StatusBadRequest, "must be a non-negative integer"},
+		{"/flow/block/-5", http.StatusBadRequest, "must be a non-negative integer"},
+		{"/flow/block/18446744073709551616", http.StatusBadRequest, "out of range"},
+		{"/flow/block/99999999999999999999999/transaction", http.StatusBadRequest, "out of range"},
+		{"/flow/block?height=1e9", http.StatusBadRequest, "must be a non-negative integer"},
+		{"/flow/transaction?height=18446744073709551616", http.StatusBadRequest, "out of range"},
+		{"/flow/block/5000", http.StatusNotFound, "not indexed yet"},
+		{"/flow/block/18446744073709551615/service-event", http.StatusNotFound, "not indexed yet"},
+	}
+	for _, c := range cases {
+		rec := get(c.path)
+		if rec.Code != c.want || !strings.Contains(rec.Body.String(), c.msg) {
+			t.Errorf("GET %s = %d %s, want %d containing %q", c.path, rec.Code, rec.Body.String(), c.want, c.msg)
+		}
+	}
+
+	// A far-future ?height= lookup is an empty list, like any missing height.
+	rec := get("/flow/block?height=5000")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"data":[]`) {
+		t.Errorf("GET /flow/block?height=5000 = %d %s, want an empty list", rec.Code, rec.Body.String())
+	}
+}
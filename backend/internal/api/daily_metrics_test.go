@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDailyMetricRoute(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(nil, nil, "0", 0)
+	cases := []struct {
+		path string
+		want int
+	}{
+		{"/insights/daily/metric/not_a_metric", http.StatusBadRequest},
+		{"/insights/daily/metric/updated_at", http.StatusBadRequest},
+		// Valid metrics get past validation and reach the (missing) repository.
+		{"/insights/daily/metric/tx_count?from=2026-01-01&to=2026-01-07", http.StatusInternalServerError},
+		{"/insights/daily/metric/fees", http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if rec.Code != c.want {
+			t.Errorf("GET %s = %d, want %d (%s)", c.path, rec.Code, c.want, rec.Body.String())
+		}
+	}
+}
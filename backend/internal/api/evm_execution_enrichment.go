@@ -64,7 +64,7 @@ func deriveEVMEntityKind(label repository.EVMAddressLabelMetadata, contract repo
 	}
 }
 
-func buildEVMEntityMeta(addr string, labels map[string]repository.EVMAddressLabelMetadata, contracts map[string]repository.EVMContractMetadata, coaMap map[string]string) map[string]interface{} {
+func buildEVMEntityMeta(ctx context.Context, addr string, labels map[string]repository.EVMAddressLabelMetadata, contracts map[string]repository.EVMContractMetadata, coaMap map[string]string) map[string]interface{} {
 	addr = normalizeEVMAddress(addr)
 	if addr == "" {
 		return nil
@@ -84,11 +84,11 @@ func buildEVMEntityMeta(addr string, labels map[string]repository.EVMAddressLabe
 		}
 	}
 	out := map[string]interface{}{
-		"address": formatAddressV1(addr),
+		"address": formatAddressV1(ctx, addr),
 		"kind":    kind,
 	}
 	if flowAddr != "" {
-		out["flow_address"] = formatAddressV1(flowAddr)
+		out["flow_address"] = formatAddressV1(ctx, flowAddr)
 	}
 	if display := preferredEVMEntityLabel(label, contract); display != "" {
 		out["label"] = display
@@ -109,7 +109,7 @@ func buildEVMEntityMeta(addr string, labels map[string]repository.EVMAddressLabe
 		out["proxy_type"] = contract.ProxyType
 	}
 	if contract.ImplAddress != "" {
-		out["implementation_address"] = formatAddressV1(contract.ImplAddress)
+		out["implementation_address"] = formatAddressV1(ctx, contract.ImplAddress)
 	}
 	if label.IsVerified || contract.VerifiedAt != nil {
 		out["verified"] = true
@@ -120,7 +120,7 @@ func buildEVMEntityMeta(addr string, labels map[string]repository.EVMAddressLabe
 	return out
 }
 
-func normalizeABIValue(value interface{}) interface{} {
+func normalizeABIValue(ctx context.Context, value interface{}) interface{} {
 	switch v := value.(type) {
 	case nil:
 		return nil
@@ -129,7 +129,7 @@ func normalizeABIValue(value interface{}) interface{} {
 	case bool:
 		return v
 	case common.Address:
-		return formatAddressV1(v.Hex())
+		return formatAddressV1(ctx, v.Hex())
 	case *big.Int:
 		if v == nil {
 			return nil
@@ -149,7 +149,7 @@ func normalizeABIValue(value interface{}) interface{} {
 		if rv.IsNil() {
 			return nil
 		}
-		return normalizeABIValue(rv.Elem().Interface())
+		return normalizeABIValue(ctx, rv.Elem().Interface())
 	}
 
 	switch rv.Kind() {
@@ -167,7 +167,7 @@ func normalizeABIValue(value interface{}) interface{} {
 		}
 		out := make([]interface{}, 0, rv.Len())
 		for i := 0; i < rv.Len(); i++ {
-			out = append(out, normalizeABIValue(rv.Index(i).Interface()))
+			out = append(out, normalizeABIValue(ctx, rv.Index(i).Interface()))
 		}
 		return out
 	case reflect.Array:
@@ -180,7 +180,7 @@ func normalizeABIValue(value interface{}) interface{} {
 		}
 		out := make([]interface{}, 0, rv.Len())
 		for i := 0; i < rv.Len(); i++ {
-			out = append(out, normalizeABIValue(rv.Index(i).Interface()))
+			out = append(out, normalizeABIValue(ctx, rv.Index(i).Interface()))
 		}
 		return out
 	case reflect.Struct:
@@ -192,14 +192,14 @@ func normalizeABIValue(value interface{}) interface{} {
 			if !field.IsExported() {
 				continue
 			}
-			out[field.Name] = normalizeABIValue(rv.Field(i).Interface())
+			out[field.Name] = normalizeABIValue(ctx, rv.Field(i).Interface())
 		}
 		return out
 	case reflect.Map:
 		out := make(map[string]interface{}, rv.Len())
 		iter := rv.MapRange()
 		for iter.Next() {
-			out[fmt.Sprint(iter.Key().Interface())] = normalizeABIValue(iter.Value().Interface())
+			out[fmt.Sprint(iter.Key().Interface())] = normalizeABIValue(ctx, iter.Value().Interface())
 		}
 		return out
 	default:
@@ -207,7 +207,7 @@ func normalizeABIValue(value interface{}) interface{} {
 	}
 }
 
-func decodeABIWithContract(dataHex string, contract repository.EVMContractMetadata, impl repository.EVMContractMetadata) map[string]interface{} {
+func decodeABIWithContract(ctx context.Context, dataHex string, contract repository.EVMContractMetadata, impl repository.EVMContractMetadata) map[string]interface{} {
 	dataHex = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(dataHex)), "0x")
 	if len(dataHex) < 8 {
 		return nil
@@ -250,10 +250,10 @@ func decodeABIWithContract(dataHex string, contract repository.EVMContractMetada
 			out["proxy_type"] = contract.ProxyType
 		}
 		if contract.Address != "" {
-			out["proxy_address"] = formatAddressV1(contract.Address)
+			out["proxy_address"] = formatAddressV1(ctx, contract.Address)
 		}
 		if impl.Address != "" {
-			out["implementation_address"] = formatAddressV1(impl.Address)
+			out["implementation_address"] = formatAddressV1(ctx, impl.Address)
 		}
 		if impl.Name != "" {
 			out["implementation_name"] = impl.Name
@@ -272,7 +272,7 @@ func decodeABIWithContract(dataHex string, contract repository.EVMContractMetada
 	for i, input := range method.Inputs {
 		arg := map[string]interface{}{
 			"type":  input.Type.String(),
-			"value": normalizeABIValue(args[i]),
+			"value": normalizeABIValue(ctx, args[i]),
 		}
 		if input.Name != "" {
 			arg["name"] = input.Name
@@ -310,16 +310,16 @@ func (s *Server) buildEnrichedEVMExecutions(ctx context.Context, evmExecs []repo
 	out := make([]map[string]interface{}, 0, len(evmExecs))
 	for _, rec := range evmExecs {
 		item := toEVMTransactionOutput(rec)
-		if meta := buildEVMEntityMeta(rec.FromAddress, labelMap, contractMap, coaMap); meta != nil {
+		if meta := buildEVMEntityMeta(ctx, rec.FromAddress, labelMap, contractMap, coaMap); meta != nil {
 			item["from_meta"] = meta
 		}
-		if meta := buildEVMEntityMeta(rec.ToAddress, labelMap, contractMap, coaMap); meta != nil {
+		if meta := buildEVMEntityMeta(ctx, rec.ToAddress, labelMap, contractMap, coaMap); meta != nil {
 			item["to_meta"] = meta
 		}
 
 		contract := contractMap[normalizeEVMAddress(rec.ToAddress)]
 		impl := contractMap[contract.ImplAddress]
-		if decoded := decodeABIWithContract(rec.Data, contract, impl); decoded != nil {
+		if decoded := decodeABIWithContract(ctx, rec.Data, contract, impl); decoded != nil {
 			item["decoded_call"] = decoded
 		}
 		out = append(out, item)
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -21,7 +22,7 @@ func TestDecodeABIWithContract(t *testing.T) {
 	  }
 	]`)
 
-	decoded := decodeABIWithContract(
+	decoded := decodeABIWithContract(context.Background(),
 		"a9059cbb000000000000000000000000000000000000000000000002bd91ec0b3c1284fe000000000000000000000000000000000000000000000000716f1200055cb000",
 		repository.EVMContractMetadata{
 			Address: "d3bf53dac106a0290b0483ecbc89d40fcc961f3e",
@@ -59,7 +60,7 @@ func TestDecodeABIWithProxyImplementation(t *testing.T) {
 	  }
 	]`)
 
-	decoded := decodeABIWithContract(
+	decoded := decodeABIWithContract(context.Background(),
 		"d0e30db0",
 		repository.EVMContractMetadata{
 			Address:     "1111111111111111111111111111111111111111",
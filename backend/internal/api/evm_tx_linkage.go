@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
+)
+
+// primaryEVMExecution picks the EVM execution a Flow transaction's evm_hash
+// refers to, falling back to the first one.
+func primaryEVMExecution(t models.Transaction, execs []repository.EVMTransactionRecord) (repository.EVMTransactionRecord, bool) {
+	if len(execs) == 0 {
+		return repository.EVMTransactionRecord{}, false
+	}
+	if hash := normalizeEVMAddress(t.EVMHash); hash != "" {
+		for _, rec := range execs {
+			if normalizeEVMAddress(rec.EVMHash) == hash {
+				return rec, true
+			}
+		}
+	}
+	return execs[0], true
+}
+
+// classifyEVMAction names what an EVM execution does from its target and
+// calldata: contract_creation, transfer (no calldata, so a plain value
+// transfer), erc20_transfer / erc20_transferFrom, or contract_call.
+func classifyEVMAction(to, dataHex string) string {
+	data := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(dataHex)), "0x")
+	switch {
+	case normalizeEVMAddress(to) == "":
+		return "contract_creation"
+	case data == "":
+		return "transfer"
+	}
+	if decoded := decodeEVMRecipientFromCallData(data); decoded.CallType != "unknown" {
+		return decoded.CallType
+	}
+	return "contract_call"
+}
+
+// buildTxEVMSummary returns the nested "evm" object of a Flow transaction that
+// wraps EVM calls, or nil when it has none. The primary execution comes from
+// app.evm_transactions when available, else from the transaction's own EVM
+// columns. The owning Flow account is added later by linkEVMOwner.
+func buildTxEVMSummary(ctx context.Context, t models.Transaction, execs []repository.EVMTransactionRecord) map[string]interface{} {
+	rec, ok := primaryEVMExecution(t, execs)
+	if !ok {
+		if !t.IsEVM || t.EVMHash == "" {
+			return nil
+		}
+		rec = repository.EVMTransactionRecord{EVMHash: t.EVMHash, FromAddress: t.EVMFrom, ToAddress: t.EVMTo, Value: t.EVMValue}
+	}
+
+	value := rec.Value
+	if value == "" {
+		value = "0"
+	}
+	out := map[string]interface{}{
+		"hash":       "0x" + normalizeEVMAddress(rec.EVMHash),
+		"from":       formatAddressV1(ctx, rec.FromAddress),
+		"value":      value,
+		"value_flow": weiToFloat(value, 18),
+		"executions": len(execs),
+	}
+	if rec.ToAddress != "" {
+		out["to"] = formatAddressV1(ctx, rec.ToAddress)
+	}
+	if ok {
+		action := classifyEVMAction(rec.ToAddress, rec.Data)
+		out["action"] = action
+		if action == "erc20_transfer" || action == "erc20_transferFrom" {
+			if recipient := decodeEVMRecipientFromCallData(rec.Data).Recipient; recipient != "" {
+				out["token_recipient"] = formatAddressV1(ctx, recipient)
+			}
+		}
+		if data := strings.TrimPrefix(strings.ToLower(rec.Data), "0x"); len(data) >= 8 {
+			out["selector"] = "0x" + data[:8]
+		}
+		out["log_count"] = rec.LogCount
+		out["gas_used"] = rec.GasUsed
+		if rec.Status != "" {
+			out["status"] = rec.Status
+		}
+	}
+	return out
+}
+
+// linkEVMOwner adds the COA that sent the transaction's primary EVM call and
+// the Flow account owning it, when the sender is a known COA.
+func (s *Server) linkEVMOwner(ctx context.Context, out map[string]interface{}) {
+	evm, ok := out["evm"].(map[string]interface{})
+	if !ok || s.repo == nil {
+		return
+	}
+	from, _ := evm["from"].(string)
+	from = normalizeEVMAddress(from)
+	if from == "" {
+		return
+	}
+	coaMap, err := s.repo.CheckAddressesAreCOA(ctx, []string{from})
+	if err != nil {
+		return
+	}
+	applyEVMOwner(ctx, evm, coaMap)
+}
+
+// applyEVMOwner sets coa and flow_account on an evm summary from a COA to
+// Flow address map keyed by bare lowercase hex.
+func applyEVMOwner(ctx context.Context, evm map[string]interface{}, coaMap map[string]string) {
+	from, _ := evm["from"].(string)
+	from = normalizeEVMAddress(from)
+	if flowAddr, ok := coaMap[from]; ok && flowAddr != "" {
+		evm["coa"] = formatAddressV1(ctx, from)
+		evm["flow_account"] = formatAddressV1(ctx, flowAddr)
+	}
+}
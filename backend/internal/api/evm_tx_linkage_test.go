@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
+)
+
+const (
+	linkageCOA   = "0000000000000000000000021234567890abcdef"
+	linkageToken = "d3bf53dac106a0290b0483ecbc89d40fcc961f3e"
+	linkageDest  = "00000000000000000000000000000000000000aa"
+	linkageHash  = "5e1f0c9ddbed2b5e5d0e7f2b6a3a4d0c9f1e2b3c4d5e6f708192a3b4c5d6e7f8"
+)
+
+func TestToFlowTransactionOutputEVMSummary(t *testing.T) {
+	t.Parallel()
+
+	// transfer(0x..aa, 1e18)
+	calldata := "0xa9059cbb" +
+		"000000000000000000000000" + linkageDest +
+		"0000000000000000000000000000000000000000000000000de0b6b3a7640000"
+	tx := models.Transaction{ID: "ab", IsEVM: true, EVMHash: linkageHash}
+	execs := []repository.EVMTransactionRecord{
+		{EVMHash: "0000000000000000000000000000000000000000000000000000000000000001", FromAddress: linkageCOA, ToAddress: linkageDest, Value: "5"},
+		{
+			EVMHash:     linkageHash,
+			FromAddress: linkageCOA,
+			ToAddress:   linkageToken,
+			Value:       "2000000000000000000",
+			Data:        calldata,
+			LogCount:    1,
+			GasUsed:     51234,
+			Status:      "SEALED",
+		},
+	}
+
+	out := toFlowTransactionOutput(context.Background(), tx, nil, nil, nil, 0, execs)
+	evm, ok := out["evm"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("no evm object in %v", out)
+	}
+	want := map[string]interface{}{
+		"hash":            "0x" + linkageHash,
+		"from":            "0x" + linkageCOA,
+		"to":              "0x" + linkageToken,
+		"action":          "erc20_transfer",
+		"token_recipient": "0x" + linkageDest,
+		"selector":        "0xa9059cbb",
+		"value":           "2000000000000000000",
+		"value_flow":      2.0,
+		"log_count":       1,
+		"executions":      2,
+		"gas_used":        uint64(51234),
+		"status":          "SEALED",
+	}
+	for k, v := range want {
+		if evm[k] != v {
+			t.Errorf("evm[%q] = %#v; want %#v", k, evm[k], v)
+		}
+	}
+
+	applyEVMOwner(context.Background(), evm, map[string]string{linkageCOA: "1e3c78c6d580273b"})
+	if evm["coa"] != "0x"+linkageCOA || evm["flow_account"] != "0x1e3c78c6d580273b" {
+		t.Fatalf("owner not linked: %v", evm)
+	}
+}
+
+func TestToFlowTransactionOutputEVMSummaryFallbacks(t *testing.T) {
+	t.Parallel()
+
+	// No execution rows: the summary comes from the transaction's EVM columns.
+	tx := models.Transaction{IsEVM: true, EVMHash: linkageHash, EVMFrom: linkageCOA, EVMTo: linkageDest, EVMValue: "0"}
+	evm, ok := toFlowTransactionOutput(context.Background(), tx, nil, nil, nil, 0)["evm"].(map[string]interface{})
+	if !ok || evm["from"] != "0x"+linkageCOA || evm["action"] != nil {
+		t.Fatalf("evm = %v", evm)
+	}
+
+	if _, ok := toFlowTransactionOutput(context.Background(), models.Transaction{}, nil, nil, nil, 0)["evm"]; ok {
+		t.Fatal("evm object on a non-EVM transaction")
+	}
+
+	applyEVMOwner(context.Background(), evm, map[string]string{})
+	if _, ok := evm["flow_account"]; ok {
+		t.Fatal("flow_account set for an address that is not a COA")
+	}
+}
+
+func TestClassifyEVMAction(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][2]string{
+		"contract_creation": {"", "0x6080"},
+		"transfer":          {linkageDest, "0x"},
+		"contract_call":     {linkageToken, "0x12345678"},
+		"erc20_transfer":    {linkageToken, "0xa9059cbb000000000000000000000000" + linkageDest},
+	}
+	for want, in := range cases {
+		if got := classifyEVMAction(in[0], in[1]); got != want {
+			t.Errorf("classifyEVMAction(%q, %q) = %q; want %q", in[0], in[1], got, want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 
+	"flowscan-clone/internal/flow"
+
 	"github.com/onflow/cadence"
 	flowsdk "github.com/onflow/flow-go-sdk"
 )
@@ -16,3 +18,15 @@ type FlowClient interface {
 	GetAccount(ctx context.Context, address flowsdk.Address) (*flowsdk.Account, error)
 	ExecuteScriptAtLatestBlock(ctx context.Context, script []byte, args []cadence.Value) (cadence.Value, error)
 }
+
+// breakerStatsProvider is optionally implemented by FlowClient (e.g. *flow.Client)
+// to report access node circuit breaker state.
+type breakerStatsProvider interface {
+	BreakerStats() flow.BreakerStats
+}
+
+// accountBalancesProvider is optionally implemented by FlowClient (e.g. *flow.Client)
+// to fetch the FLOW balances of many accounts in one script call.
+type accountBalancesProvider interface {
+	GetAccountBalances(ctx context.Context, addresses []string) (map[string]uint64, error)
+}
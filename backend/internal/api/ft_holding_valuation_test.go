@@ -0,0 +1,62 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/market"
+	"flowscan-clone/internal/repository"
+)
+
+func TestAddFTHoldingValuationStalePrice(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	cache := market.NewPriceCache()
+	cache.SetStaleAfter("coingecko", 6*time.Hour)
+	cache.Observe("FLOW", market.PriceQuote{Price: 0.8, Source: "coingecko", AsOf: now.Add(-7 * time.Hour)})
+	q, found := cache.GetLatestQuote("FLOW", now)
+
+	out := map[string]interface{}{}
+	addFTHoldingValuation(out, 10, q, found, false)
+	if out["is_stale"] != true || out["usd_value"] != 8.0 || out["price_as_of"] != "2026-05-10T05:00:00Z" {
+		t.Fatalf("stale price should still value the holding and be flagged: %#v", out)
+	}
+
+	out = map[string]interface{}{}
+	addFTHoldingValuation(out, 10, q, found, true)
+	if out["is_stale"] != true || out["usd_value"] != nil || out["usd_price"] != nil {
+		t.Fatalf("stale price with staleAsNull should null the value: %#v", out)
+	}
+
+	// Within the threshold the price is fresh.
+	q, found = cache.GetLatestQuote("FLOW", now.Add(-2*time.Hour))
+	out = map[string]interface{}{}
+	addFTHoldingValuation(out, 10, q, found, true)
+	if out["is_stale"] != false || out["usd_value"] != 8.0 {
+		t.Fatalf("price within threshold should not be stale: %#v", out)
+	}
+
+	out = map[string]interface{}{}
+	addFTHoldingValuation(out, 10, market.LatestQuote{}, false, false)
+	if out["usd_value"] != nil || out["price_as_of"] != nil || out["is_stale"] != false {
+		t.Fatalf("unpriced holding should have null valuation: %#v", out)
+	}
+}
+
+func TestFTHoldingMarketSymbol(t *testing.T) {
+	t.Parallel()
+
+	meta := map[string]repository.TokenMetadataInfo{
+		"A.b19436aae4d94622.FiatToken": {MarketSymbol: "USDC"},
+	}
+	if got := ftHoldingMarketSymbol(meta, "0xb19436aae4d94622", "FiatToken"); got != "USDC" {
+		t.Errorf("FiatToken symbol = %q, want USDC", got)
+	}
+	if got := ftHoldingMarketSymbol(meta, "1654653399040a61", "FlowToken"); got != "FLOW" {
+		t.Errorf("FlowToken symbol = %q, want FLOW", got)
+	}
+	if got := ftHoldingMarketSymbol(meta, "0123", "Unknown"); got != "" {
+		t.Errorf("unknown token symbol = %q, want empty", got)
+	}
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// httpServerConfig holds the listener/timeouts tuning for the API server.
+type httpServerConfig struct {
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	MaxConns          int  // 0 = unlimited
+	H2C               bool // serve cleartext HTTP/2 (prior knowledge or Upgrade) alongside HTTP/1.1
+}
+
+func httpServerConfigFromEnv() httpServerConfig {
+	return httpServerConfig{
+		ReadHeaderTimeout: envSeconds("API_READ_HEADER_TIMEOUT_SEC", 10*time.Second),
+		ReadTimeout:       envSeconds("API_READ_TIMEOUT_SEC", 30*time.Second),
+		WriteTimeout:      envSeconds("API_WRITE_TIMEOUT_SEC", 120*time.Second),
+		IdleTimeout:       envSeconds("API_IDLE_TIMEOUT_SEC", 120*time.Second),
+		MaxHeaderBytes:    envInt("API_MAX_HEADER_BYTES", 1<<20),
+		MaxConns:          envInt("API_MAX_CONNS", 0),
+		H2C:               strings.ToLower(strings.TrimSpace(os.Getenv("API_H2C"))) != "false",
+	}
+}
+
+func envSeconds(key string, def time.Duration) time.Duration {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return time.Duration(n) * time.Second
+		}
+	}
+	return def
+}
+
+func envInt(key string, def int) int {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return def
+}
+
+// newHTTPServer builds the API http.Server from cfg.
+func newHTTPServer(addr string, handler http.Handler, cfg httpServerConfig) *http.Server {
+	if cfg.H2C {
+		handler = h2c.NewHandler(handler, &http2.Server{IdleTimeout: cfg.IdleTimeout})
+	}
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// clearConnDeadlines removes the server read/write deadlines for a long-lived
+// request (websocket, long admin jobs). Errors are ignored: the underlying
+// writer may not support deadlines (e.g. in tests).
+func clearConnDeadlines(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+}
+
+// noDeadlineMiddleware clears the server read/write deadlines for the routes it wraps.
+func noDeadlineMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clearConnDeadlines(w)
+		next.ServeHTTP(w, r)
+	})
+}
+
+const connLimitResponse = "HTTP/1.1 503 Service Unavailable\r\n" +
+	"Content-Type: text/plain\r\nContent-Length: 25\r\nConnection: close\r\n\r\n" +
+	"too many open connections"
+
+// connLimitListener caps the number of open connections. Connections accepted
+// past the cap get a minimal 503 and are closed immediately instead of queueing,
+// so a flood of idle sockets cannot starve the server.
+type connLimitListener struct {
+	net.Listener
+	max      int64
+	active   atomic.Int64
+	rejected atomic.Uint64
+}
+
+func newConnLimitListener(l net.Listener, max int) *connLimitListener {
+	return &connLimitListener{Listener: l, max: int64(max)}
+}
+
+func (l *connLimitListener) Accept() (net.Conn, error) {
+	for {
+		c, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.active.Add(1) > l.max {
+			l.active.Add(-1)
+			if n := l.rejected.Add(1); n == 1 || n%1000 == 0 {
+				log.Printf("[api] connection limit %d reached; rejected %d connections so far", l.max, n)
+			}
+			go rejectConn(c)
+			continue
+		}
+		return &limitedConn{Conn: c, release: l.release}, nil
+	}
+}
+
+func (l *connLimitListener) release() { l.active.Add(-1) }
+
+func rejectConn(c net.Conn) {
+	_ = c.SetWriteDeadline(time.Now().Add(time.Second))
+	_, _ = c.Write([]byte(connLimitResponse))
+	_ = c.Close()
+}
+
+type limitedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
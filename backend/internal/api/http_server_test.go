@@ -0,0 +1,98 @@
+package api
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConnLimitListenerRejectsPastCap(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})}
+	go srv.Serve(newConnLimitListener(ln, 1))
+	defer srv.Close()
+	addr := ln.Addr().String()
+
+	get := func(c net.Conn) (string, error) {
+		_ = c.SetDeadline(time.Now().Add(5 * time.Second))
+		if _, err := io.WriteString(c, "GET / HTTP/1.1\r\nHost: x\r\n\r\n"); err != nil {
+			return "", err
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(c), nil)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.Status + " " + string(body), nil
+	}
+
+	// First connection takes the only slot and keeps it (keep-alive).
+	first, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := get(first); err != nil || !strings.HasPrefix(got, "200") {
+		t.Fatalf("first conn: got %q, err %v", got, err)
+	}
+
+	second, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := get(second); err == nil && !strings.HasPrefix(got, "503") {
+		t.Fatalf("second conn past cap: got %q, want 503 or closed", got)
+	}
+	second.Close()
+
+	// Releasing the first connection frees the slot.
+	first.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		c, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := get(c)
+		c.Close()
+		if err == nil && strings.HasPrefix(got, "200") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("slot not released after close: got %q, err %v", got, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestHTTPServerConfigFromEnv(t *testing.T) {
+	t.Setenv("API_WRITE_TIMEOUT_SEC", "5")
+	t.Setenv("API_MAX_CONNS", "100")
+	t.Setenv("API_H2C", "false")
+	t.Setenv("API_IDLE_TIMEOUT_SEC", "bogus")
+
+	cfg := httpServerConfigFromEnv()
+	if cfg.WriteTimeout != 5*time.Second {
+		t.Errorf("WriteTimeout = %s, want 5s", cfg.WriteTimeout)
+	}
+	if cfg.MaxConns != 100 {
+		t.Errorf("MaxConns = %d, want 100", cfg.MaxConns)
+	}
+	if cfg.H2C {
+		t.Error("H2C should be disabled")
+	}
+	if cfg.IdleTimeout != 120*time.Second {
+		t.Errorf("IdleTimeout = %s, want default 120s", cfg.IdleTimeout)
+	}
+}
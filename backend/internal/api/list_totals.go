@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// exactCountTimeout bounds ?exact_count=true. A COUNT(*) over raw.transactions
+// or raw.events partitions is a full scan and can take minutes on mainnet.
+const exactCountTimeout = 5 * time.Second
+
+type countFunc func(ctx context.Context) (int64, error)
+
+func wantsExactCount(r *http.Request) bool {
+	return strings.ToLower(r.URL.Query().Get("exact_count")) == "true"
+}
+
+// addListTotals adds the size of an unfiltered list to meta: "total" is the
+// cheap planner estimate, and with ?exact_count=true "total_exact" is a real
+// count bounded by timeout. If the count times out or fails, total_exact is
+// null and total_exact_error says why; the page itself is still served.
+func addListTotals(r *http.Request, meta map[string]interface{}, timeout time.Duration, estimate, exact countFunc) {
+	if n, err := estimate(r.Context()); err != nil {
+		log.Printf("[list_totals] estimate %s: %v", r.URL.Path, err)
+	} else {
+		meta["total"] = n
+	}
+	if !wantsExactCount(r) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	n, err := exact(ctx)
+	switch {
+	case err == nil:
+		meta["total_exact"] = n
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		meta["total_exact"] = nil
+		meta["total_exact_error"] = "timed out after " + timeout.String()
+	default:
+		log.Printf("[list_totals] exact count %s: %v", r.URL.Path, err)
+		meta["total_exact"] = nil
+		meta["total_exact_error"] = "count failed"
+	}
+}
+
+// addRepoListTotals is addListTotals for one of the repository's list sources.
+func (s *Server) addRepoListTotals(r *http.Request, meta map[string]interface{}, list string) {
+	addListTotals(r, meta, exactCountTimeout,
+		func(ctx context.Context) (int64, error) { return s.repo.EstimateListTotal(ctx, list) },
+		func(ctx context.Context) (int64, error) { return s.repo.ExactListTotal(ctx, list) },
+	)
+}
+
+// rejectFilteredExactCount writes a 400 when ?exact_count=true is combined
+// with filters; only whole-list totals are supported.
+func rejectFilteredExactCount(w http.ResponseWriter, r *http.Request, filtered bool) bool {
+	if filtered && wantsExactCount(r) {
+		writeAPIError(w, http.StatusBadRequest, "exact_count is only supported on the unfiltered list")
+		return true
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// staleCounts mimics a table right after a bulk load: ANALYZE has not run, so
+// reltuples still reports 1000 rows while 1234 are present.
+func staleCounts() (estimate, exact countFunc) {
+	return func(context.Context) (int64, error) { return 1000, nil },
+		func(context.Context) (int64, error) { return 1234, nil }
+}
+
+func TestAddListTotalsEstimateByDefault(t *testing.T) {
+	t.Parallel()
+
+	estimate, exact := staleCounts()
+	meta := map[string]interface{}{}
+	addListTotals(httptest.NewRequest(http.MethodGet, "/flow/block", nil), meta, time.Second, estimate, exact)
+	if meta["total"] != int64(1000) {
+		t.Fatalf("total = %v, want the estimate 1000", meta["total"])
+	}
+	if _, ok := meta["total_exact"]; ok {
+		t.Fatalf("total_exact set without exact_count: %v", meta)
+	}
+}
+
+func TestAddListTotalsExactDiffersFromEstimate(t *testing.T) {
+	t.Parallel()
+
+	estimate, exact := staleCounts()
+	meta := map[string]interface{}{}
+	addListTotals(httptest.NewRequest(http.MethodGet, "/flow/block?exact_count=true", nil), meta, time.Second, estimate, exact)
+	if meta["total"] != int64(1000) || meta["total_exact"] != int64(1234) {
+		t.Fatalf("meta = %v, want total=1000 total_exact=1234", meta)
+	}
+}
+
+func TestAddListTotalsExactTimeout(t *testing.T) {
+	t.Parallel()
+
+	estimate, _ := staleCounts()
+	slow := func(ctx context.Context) (int64, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	meta := map[string]interface{}{}
+	addListTotals(httptest.NewRequest(http.MethodGet, "/flow/transaction?exact_count=true", nil), meta, 10*time.Millisecond, estimate, slow)
+	if v, ok := meta["total_exact"]; !ok || v != nil {
+		t.Fatalf("total_exact = %v (present=%v), want null", v, ok)
+	}
+	if meta["total_exact_error"] != "timed out after 10ms" || meta["total"] != int64(1000) {
+		t.Fatalf("meta = %v", meta)
+	}
+
+	failing := func(context.Context) (int64, error) { return 0, errors.New("permission denied") }
+	meta = map[string]interface{}{}
+	addListTotals(httptest.NewRequest(http.MethodGet, "/flow/transaction?exact_count=true", nil), meta, time.Second, estimate, failing)
+	if meta["total_exact_error"] != "count failed" {
+		t.Fatalf("meta = %v", meta)
+	}
+}
+
+func TestExactCountRejectedWithFilters(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(nil, nil, "0", 0)
+	for _, path := range []string{
+		"/flow/block?height=10&exact_count=true",
+		"/flow/transaction?payer=0x1654653399040a61&exact_count=true",
+		"/flow/ft/transfer?address=0x1654653399040a61&exact_count=true",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "198.51.100.61:4000"
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s status = %d, want 400 (body %s)", path, rec.Code, rec.Body.String())
+		}
+	}
+}
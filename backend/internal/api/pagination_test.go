@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestParseLimitOffsetWithMaxClampsToEndpointCap(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		query      string
+		maxLimit   int
+		wantLimit  int
+		wantOffset int
+	}{
+		{"", maxLimitEvents, 20, 0},
+		{"?limit=200&offset=40", maxLimitEvents, maxLimitEvents, 40},
+		{"?limit=150", maxLimitTransfers, maxLimitTransfers, 0},
+		{"?limit=350", maxLimitLight, 350, 0},
+		{"?limit=10000", maxLimitLight, maxLimitLight, 0},
+		{"?limit=0&offset=-1", maxLimitTransfers, 20, 0},
+		{"?limit=abc", maxLimitTransfers, 20, 0},
+		{"", 10, 10, 0},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/flow/ft/transfer"+tc.query, nil)
+		limit, offset := parseLimitOffsetWithMax(r, tc.maxLimit)
+		if limit != tc.wantLimit || offset != tc.wantOffset {
+			t.Errorf("%q (max %d): got limit=%d offset=%d, want %d/%d", tc.query, tc.maxLimit, limit, offset, tc.wantLimit, tc.wantOffset)
+		}
+	}
+}
+
+func TestParseLimitOffsetClampsOverCapLimit(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/flow/transaction?limit=1000&offset=5", nil)
+	if limit, offset := parseLimitOffset(r); limit != maxLimitDefault || offset != 5 {
+		t.Fatalf("got limit=%d offset=%d, want %d/5", limit, offset, maxLimitDefault)
+	}
+}
+
+func TestContractEventFeedReportsClampedLimit(t *testing.T) {
+	t.Parallel()
+
+	// A cursor below from_height leaves an empty window, so the handler answers
+	// without the repository and _meta shows the effective limit.
+	s := &Server{}
+	req := httptest.NewRequest(http.MethodGet, "/flow/contract/A.1654653399040a61.FlowToken/events/feed?limit=200&from_height=100&to_height=200&cursor=50:0:0", nil)
+	req = mux.SetURLVars(req, map[string]string{"identifier": "A.1654653399040a61.FlowToken"})
+	rec := httptest.NewRecorder()
+	s.handleContractEventFeed(rec, req)
+
+	var env struct {
+		Meta map[string]interface{} `json:"_meta"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&env); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if env.Meta["limit"] != float64(maxLimitEvents) {
+		t.Fatalf("_meta.limit = %v, want %d", env.Meta["limit"], maxLimitEvents)
+	}
+}
@@ -34,8 +34,18 @@ func newBucketMap(ttl time.Duration) *bucketMap {
 	return &bucketMap{entries: make(map[string]*limiterEntry), ttl: ttl}
 }
 
+// rateDecision is the outcome of one bucket check, used for the 429 response
+// and the RateLimit-* headers.
+type rateDecision struct {
+	allowed    bool
+	limit      int           // bucket capacity (burst)
+	remaining  int           // whole tokens left after this request
+	retryAfter time.Duration // until the next token when !allowed
+	reset      time.Duration // until the bucket is full again
+}
+
 // allow checks the token bucket for key, creating or updating it with the given rps/burst.
-func (bm *bucketMap) allow(key string, rps rate.Limit, burst int) bool {
+func (bm *bucketMap) allow(key string, rps rate.Limit, burst int) rateDecision {
 	now := time.Now()
 	bm.mu.Lock()
 	defer bm.mu.Unlock()
@@ -62,7 +72,25 @@ func (bm *bucketMap) allow(key string, rps rate.Limit, burst int) bool {
 			ent.limiter.SetBurst(burst)
 		}
 	}
-	return ent.limiter.Allow()
+
+	d := rateDecision{limit: burst, allowed: ent.limiter.AllowN(now, 1)}
+	tokens := ent.limiter.TokensAt(now)
+	if tokens > 0 {
+		d.remaining = int(tokens)
+	}
+	if !d.allowed {
+		d.retryAfter = tokenWait(1-tokens, rps)
+	}
+	d.reset = tokenWait(float64(burst)-tokens, rps)
+	return d
+}
+
+// tokenWait is how long a bucket refilling at rps takes to gain n tokens.
+func tokenWait(n float64, rps rate.Limit) time.Duration {
+	if n <= 0 || rps <= 0 {
+		return 0
+	}
+	return time.Duration(n / float64(rps) * float64(time.Second))
 }
 
 // ---------------------------------------------------------------------------
@@ -187,11 +215,12 @@ func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Exempt lightweight endpoints.
+		// Exempt lightweight endpoints, and admin routes (behind adminAuthMiddleware).
 		switch {
 		case r.URL.Path == "/health",
 			strings.HasPrefix(r.URL.Path, "/openapi."),
-			r.URL.Path == "/ws":
+			r.URL.Path == "/ws",
+			r.URL.Path == "/admin" || strings.HasPrefix(r.URL.Path, "/admin/"):
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -223,11 +252,7 @@ func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
 			if burst < 1 {
 				burst = 1
 			}
-			if !userBuckets.allow(userID, limit, burst) {
-				w.Header().Set("Content-Type", "application/json")
-				w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rps))
-				w.WriteHeader(http.StatusTooManyRequests)
-				w.Write([]byte(`{"error":"rate_limited","message":"too many requests"}`))
+			if !writeRateLimit(w, userBuckets.allow(userID, limit, burst), rps) {
 				return
 			}
 			next.ServeHTTP(w, r)
@@ -247,11 +272,7 @@ func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
 			rpsForIP, burstForIP = trustedOriginRPS, trustedOriginBurst
 		}
 
-		if !ipBuckets.allow(ip, rpsForIP, burstForIP) {
-			w.Header().Set("Content-Type", "application/json")
-			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(int(rpsForIP)))
-			w.WriteHeader(http.StatusTooManyRequests)
-			w.Write([]byte(`{"error":"rate_limited","message":"too many requests"}`))
+		if !writeRateLimit(w, ipBuckets.allow(ip, rpsForIP, burstForIP), int(rpsForIP)) {
 			return
 		}
 		next.ServeHTTP(w, r)
@@ -262,6 +283,29 @@ func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
 // Helpers
 // ---------------------------------------------------------------------------
 
+// writeRateLimit sets the RateLimit-* headers (IETF draft names, values in
+// requests and seconds) for d and, when the request is over the limit, writes
+// the 429 with Retry-After. It reports whether the request may proceed.
+func writeRateLimit(w http.ResponseWriter, d rateDecision, rps int) bool {
+	h := w.Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(d.limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(d.remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(d.reset)))
+	h.Set("X-RateLimit-Limit", strconv.Itoa(rps))
+	if d.allowed {
+		return true
+	}
+	h.Set("Retry-After", strconv.Itoa(max(ceilSeconds(d.retryAfter), 1)))
+	h.Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write([]byte(`{"error":"rate_limited","message":"too many requests"}`))
+	return false
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
 func isTrustedOrigin(r *http.Request) bool {
 	if origin := strings.TrimSpace(r.Header.Get("Origin")); origin != "" {
 		return trustedOrigins[origin]
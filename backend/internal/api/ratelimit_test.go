@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitMiddlewareReturns429WithHeaders(t *testing.T) {
+	prevRPS, prevBurst, prevBuckets := anonRPS, anonBurst, ipBuckets
+	anonRPS, anonBurst, ipBuckets = rate.Limit(1), 3, newBucketMap(time.Minute)
+	t.Cleanup(func() { anonRPS, anonBurst, ipBuckets = prevRPS, prevBurst, prevBuckets })
+
+	s := &Server{}
+	h := s.rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	do := func(path, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = ip + ":5000"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 3; i++ {
+		rec := do("/flow/block", "203.0.113.7")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d, want 200", i, rec.Code)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "3" {
+			t.Fatalf("request %d: RateLimit-Limit = %q", i, got)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != strconv.Itoa(2-i) {
+			t.Fatalf("request %d: RateLimit-Remaining = %q, want %d", i, got, 2-i)
+		}
+	}
+
+	rec := do("/flow/block", "203.0.113.7")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("over limit: status %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Fatalf("Retry-After = %q, want 1", got)
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Fatalf("RateLimit-Remaining = %q, want 0", got)
+	}
+	if reset, err := strconv.Atoi(rec.Header().Get("RateLimit-Reset")); err != nil || reset < 1 || reset > 3 {
+		t.Fatalf("RateLimit-Reset = %q", rec.Header().Get("RateLimit-Reset"))
+	}
+
+	// Other clients have their own bucket; admin and health are exempt.
+	if rec := do("/flow/block", "198.51.100.2"); rec.Code != http.StatusOK {
+		t.Fatalf("second client: status %d", rec.Code)
+	}
+	for _, path := range []string{"/admin/ft", "/health"} {
+		if rec := do(path, "203.0.113.7"); rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "" {
+			t.Fatalf("%s should be exempt: status %d", path, rec.Code)
+		}
+	}
+}
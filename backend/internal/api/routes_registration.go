@@ -17,6 +17,7 @@ func registerBaseRoutes(r *mux.Router, s *Server) {
 func registerAdminRoutes(r *mux.Router, s *Server) {
 	admin := r.PathPrefix("/admin").Subrouter()
 	admin.Use(adminAuthMiddleware)
+	admin.Use(noDeadlineMiddleware) // reprocess/backfill/import jobs can run longer than API_WRITE_TIMEOUT_SEC
 
 	// Webhook admin routes (registered first so /webhook/... paths match before other patterns)
 	if s.webhookAdminHandlers != nil {
@@ -28,6 +29,8 @@ func registerAdminRoutes(r *mux.Router, s *Server) {
 	admin.HandleFunc("/refetch-bridge", s.handleAdminRefetchBridge).Methods("POST", "OPTIONS")
 	admin.HandleFunc("/ft", s.handleAdminListFTTokens).Methods("GET", "OPTIONS")
 	admin.HandleFunc("/ft/{identifier}", s.handleAdminUpdateFTToken).Methods("PUT", "PATCH", "OPTIONS")
+	admin.HandleFunc("/tokens/duplicates", s.handleAdminListFTDuplicates).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/tokens/merge", s.handleAdminMergeFTTokens).Methods("POST", "OPTIONS")
 	admin.HandleFunc("/nft", s.handleAdminListNFTCollections).Methods("GET", "OPTIONS")
 	admin.HandleFunc("/nft/{identifier}", s.handleAdminUpdateNFTCollection).Methods("PUT", "PATCH", "OPTIONS")
 	admin.HandleFunc("/import-token/preview", s.handleAdminImportTokenPreview).Methods("POST", "OPTIONS")
@@ -41,20 +44,36 @@ func registerAdminRoutes(r *mux.Router, s *Server) {
 	admin.HandleFunc("/script-templates/{hash}/script", s.handleAdminGetScriptText).Methods("GET", "OPTIONS")
 	admin.HandleFunc("/refresh-daily-stats", s.handleAdminRefreshDailyStats).Methods("POST", "OPTIONS")
 	admin.HandleFunc("/backfill-analytics", s.handleAdminBackfillAnalytics).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/rebuild-ft-holdings", s.handleAdminRebuildFTHoldings).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/reconcile-balances", s.handleAdminReconcileBalances).Methods("GET", "POST", "OPTIONS")
 	admin.HandleFunc("/reset-token-worker", s.handleAdminResetTokenWorker).Methods("POST", "OPTIONS")
 	admin.HandleFunc("/reprocess-worker", s.handleAdminReprocessWorker).Methods("POST", "OPTIONS")
 	admin.HandleFunc("/reset-history-deriver", s.handleAdminResetHistoryDeriver).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/history-deriver/progress", s.handleAdminHistoryDeriverProgress).Methods("GET", "OPTIONS")
 	admin.HandleFunc("/redirect-history-ingester", s.handleAdminRedirectHistoryIngester).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/errors", s.handleAdminListErrors).Methods("GET", "OPTIONS")
 	admin.HandleFunc("/resolve-errors", s.handleAdminResolveErrors).Methods("POST", "OPTIONS")
 	admin.HandleFunc("/skipped-ranges", s.handleAdminListSkippedRanges).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/reorgs", s.handleAdminListReorgs).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/range-checksum", s.handleAdminRangeChecksum).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/explain", s.handleAdminExplain).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/reindex-block", s.handleAdminReindexBlock).Methods("POST", "OPTIONS")
 	admin.HandleFunc("/backfill-staking", s.handleAdminBackfillStakingBlocks).Methods("POST", "OPTIONS")
 	admin.HandleFunc("/account-labels", s.handleAdminListAccountLabels).Methods("GET", "OPTIONS")
 	admin.HandleFunc("/account-labels", s.handleAdminUpsertAccountLabel).Methods("POST", "PUT", "OPTIONS")
 	admin.HandleFunc("/account-labels/{address}/{tag}", s.handleAdminDeleteAccountLabel).Methods("DELETE", "OPTIONS")
+	admin.HandleFunc("/import/labels", s.handleAdminImportLabels).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/import/tokens", s.handleAdminImportTokens).Methods("POST", "OPTIONS")
 	admin.HandleFunc("/backfill-contracts", s.handleAdminBackfillContracts).Methods("POST", "OPTIONS")
 	admin.HandleFunc("/contracts", s.handleAdminListContracts).Methods("GET", "OPTIONS")
 	admin.HandleFunc("/contracts/refresh-dependent-counts", s.handleAdminRefreshDependentCounts).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/nft/refresh-mint-burn-counts", s.handleAdminRefreshNFTMintBurnCounts).Methods("POST", "OPTIONS")
 	admin.HandleFunc("/contracts/{identifier}", s.handleAdminUpdateContract).Methods("PUT", "PATCH", "OPTIONS")
+	admin.HandleFunc("/jobs", s.handleAdminListJobs).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/jobs/{id}/cancel", s.handleAdminCancelJob).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/price-cache", s.handleAdminPriceCache).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/price-cache/reload", s.handleAdminReloadPriceCache).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/price-cache/backfill", s.handleAdminBackfillPriceCache).Methods("POST", "OPTIONS")
 }
 
 func registerAPIRoutes(r *mux.Router, s *Server) {
@@ -125,21 +144,34 @@ func registerAIRoutes(r *mux.Router, s *Server) {
 
 func registerFlowRoutes(r *mux.Router, s *Server) {
 	r.HandleFunc("/flow/block", s.handleFlowListBlocks).Methods("GET", "OPTIONS")
+	// Registered before /flow/block/{height} so "at" is not parsed as a height.
+	r.HandleFunc("/flow/block/at", s.handleFlowBlockAtTimestamp).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/block/{height}", s.handleFlowGetBlock).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/block/{height}/full", s.handleFlowGetBlockFull).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/block/{height}/service-event", s.handleFlowBlockServiceEvents).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/block/{height}/transaction", s.handleFlowBlockTransactions).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/collection/{id}", s.handleFlowGetCollection).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/transaction", s.handleFlowListTransactions).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/transaction/failed", s.handleFlowListFailedTransactions).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/transaction/{id}", s.handleFlowGetTransaction).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/transaction/{id}/transfers", s.handleFlowTransactionTransfers).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/transaction/{id}/dependencies", s.handleFlowTransactionDependencies).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/stats/errors/top", cachedHandler(5*time.Minute, s.handleFlowTopTxErrors)).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/stats/contracts/top", cachedHandler(5*time.Minute, s.handleFlowTopContractsByActivity)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/account", s.handleFlowListAccounts).Methods("GET", "OPTIONS")
 	// Register all /flow/account/{address}/... routes with /flow/address/{address}/... aliases.
 	for _, prefix := range []string{"/flow/account", "/flow/address"} {
 		r.HandleFunc(prefix+"/{address}", s.handleFlowGetAccount).Methods("GET", "OPTIONS")
+		r.HandleFunc(prefix+"/{address}/overview", s.handleFlowAccountOverview).Methods("GET", "OPTIONS")
+		r.HandleFunc(prefix+"/{address}/contracts", s.handleFlowAccountContracts).Methods("GET", "OPTIONS")
 		r.HandleFunc(prefix+"/{address}/contract/{name}", s.handleGetAccountContractCode).Methods("GET", "OPTIONS")
 		r.HandleFunc(prefix+"/{address}/storage", s.handleGetAccountStorage).Methods("GET", "OPTIONS")
 		r.HandleFunc(prefix+"/{address}/storage/links", s.handleGetAccountStorageLinks).Methods("GET", "OPTIONS")
 		r.HandleFunc(prefix+"/{address}/storage/item", s.handleGetAccountStorageItem).Methods("GET", "OPTIONS")
 		r.HandleFunc(prefix+"/{address}/transaction", s.handleFlowAccountTransactions).Methods("GET", "OPTIONS")
 		r.HandleFunc(prefix+"/{address}/transfer", s.handleFlowAllTransfers).Methods("GET", "OPTIONS")
+		r.HandleFunc(prefix+"/{address}/counterparty", s.handleFlowAccountCounterparties).Methods("GET", "OPTIONS")
+		r.HandleFunc(prefix+"/{address}/coa", s.handleFlowAccountCOAs).Methods("GET", "OPTIONS")
 		r.HandleFunc(prefix+"/{address}/ft/transfer", s.handleFlowAccountFTTransfers).Methods("GET", "OPTIONS")
 		r.HandleFunc(prefix+"/{address}/nft/transfer", s.handleFlowAccountNFTTransfers).Methods("GET", "OPTIONS")
 		r.HandleFunc(prefix+"/{address}/ft/holding", s.handleFlowAccountFTHoldings).Methods("GET", "OPTIONS")
@@ -165,18 +197,26 @@ func registerFlowRoutes(r *mux.Router, s *Server) {
 	r.HandleFunc("/flow/nft/backfill", s.handleFlowNFTBackfill).Methods("POST", "OPTIONS")
 	r.HandleFunc("/flow/nft/{nft_type}", s.handleFlowGetNFTCollection).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/nft/{nft_type}/holding", s.handleFlowNFTHoldingsByCollection).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/nft/{nft_type}/mints", s.handleFlowNFTMints).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/nft/{nft_type}/burns", s.handleFlowNFTBurns).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/nft/{nft_type}/stats", s.handleFlowNFTSaleStats).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/nft/{nft_type}/sales", s.handleFlowNFTSales).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/nft/{nft_type}/top-account", s.handleFlowTopNFTAccounts).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/nft/{nft_type}/item", s.handleFlowNFTCollectionItems).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/nft/{nft_type}/item/{id}", s.handleFlowNFTItem).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/nft/{nft_type}/item/{id}/transfer", s.handleFlowNFTItemTransfers).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/nft/{nft_type}/item/{id}/history", s.handleFlowNFTItemHistory).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/coa/backfill", s.handleFlowCOABackfill).Methods("POST", "OPTIONS")
 	r.HandleFunc("/flow/events/search", s.handleSearchEvents).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/contract", s.handleFlowListContracts).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/contract/{identifier}", s.handleFlowGetContract).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/contract/{identifier}/transaction", s.handleContractTransactions).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/contract/{identifier}/events", s.handleContractEventTypes).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/contract/{identifier}/events/feed", s.handleContractEventFeed).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/contract/{identifier}/version", s.handleContractVersionList).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/contract/{identifier}/timeline", s.handleContractTimeline).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/contract/{identifier}/scripts", s.handleContractScripts).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/script/popular", s.handleListPopularScripts).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/script/{hash}", s.handleGetScriptText).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/contract/{identifier}/dependencies", s.handleContractDependencies).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/contract/{identifier}/version/{id}", s.handleFlowGetContractVersion).Methods("GET", "OPTIONS")
@@ -194,6 +234,7 @@ func registerFlowRoutes(r *mux.Router, s *Server) {
 	r.HandleFunc("/flow/evm/address/{address}/internal-transactions", s.handleFlowGetEVMAddressInternalTxs).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/evm/address/{address}/token-transfers", s.handleFlowGetEVMAddressTokenTransfers).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/evm/address/{address}/transfer", s.handleFlowEVMAddressAllTransfers).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/evm/address/{address}/flow-owner", s.handleGetCOAMapping).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/evm/address/{address}", s.handleFlowGetEVMAddress).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/evm/search", cachedHandler(30*time.Second, s.handleFlowEVMSearch)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/node", s.handleListNodes).Methods("GET", "OPTIONS")
@@ -210,6 +251,8 @@ func registerFlowRoutes(r *mux.Router, s *Server) {
 		r.HandleFunc(prefix+"/{address}/tax-report", s.handleTaxReport).Methods("GET", "OPTIONS")
 	}
 	r.HandleFunc("/flow/key/{publicKey}", s.handleFlowSearchByPublicKey).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/key/{publicKey}/address", s.handleFlowPublicKeyAddresses).Methods("GET", "OPTIONS")
+	r.HandleFunc("/flow/key/{publicKey}/controlled-accounts", s.handleFlowKeyControlledAccounts).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/coa/{address}", s.handleGetCOAMapping).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/account/{address}/labels", s.handleFlowAccountLabels).Methods("GET", "OPTIONS")
 	r.HandleFunc("/flow/address/{address}/labels", s.handleFlowAccountLabels).Methods("GET", "OPTIONS")
@@ -232,6 +275,7 @@ func registerAccountingRoutes(r *mux.Router, s *Server) {
 
 func registerStatusRoutes(r *mux.Router, s *Server) {
 	r.HandleFunc("/status/count", s.handleStatusCount).Methods("GET", "OPTIONS")
+	r.HandleFunc("/status/sync", s.handleStatusSync).Methods("GET", "OPTIONS")
 	r.HandleFunc("/status/stat", cachedHandler(5*time.Minute, s.handleStatusStat)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/status/stat/{timescale}/trend", cachedHandler(5*time.Minute, s.handleStatusStatTrend)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/status/flow/stat", cachedHandler(30*time.Second, s.handleStatusFlowStat)).Methods("GET", "OPTIONS")
@@ -243,6 +287,7 @@ func registerStatusRoutes(r *mux.Router, s *Server) {
 	r.HandleFunc("/status/price/history", cachedHandler(5*time.Minute, s.handleStatusPriceHistory)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/status/prices", cachedHandler(60*time.Second, s.handleStatusPrices)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/status/nodes", cachedHandler(60*time.Second, s.handleStatusNodes)).Methods("GET", "OPTIONS")
+	r.HandleFunc("/status/network/epoch", cachedHandler(30*time.Second, s.handleNetworkEpoch)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/status/gcp-vms", s.handleStatusGCPVMs).Methods("GET", "OPTIONS")
 
 	// Compatibility endpoints (find.xyz style)
@@ -254,8 +299,10 @@ func registerStatusRoutes(r *mux.Router, s *Server) {
 	// Analytics endpoints (cached — slow queries, data changes infrequently)
 	r.HandleFunc("/insights/daily", cachedHandler(5*time.Minute, s.handleAnalyticsDaily)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/insights/daily/module/{module}", cachedHandler(2*time.Minute, s.handleAnalyticsDailyModule)).Methods("GET", "OPTIONS")
+	r.HandleFunc("/insights/daily/metric/{metric}", cachedHandler(5*time.Minute, s.handleDailyMetric)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/insights/transfers/daily", cachedHandler(5*time.Minute, s.handleAnalyticsTransfersDaily)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/insights/big-transfers", cachedHandler(2*time.Minute, s.handleBigTransfers)).Methods("GET", "OPTIONS")
+	r.HandleFunc("/insights/accounts/growth", cachedHandler(5*time.Minute, s.handleAccountGrowth)).Methods("GET", "OPTIONS")
 	// Backwards-compat aliases (content blockers block "analytics" keyword)
 	r.HandleFunc("/analytics/daily", cachedHandler(5*time.Minute, s.handleAnalyticsDaily)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/analytics/daily/module/{module}", cachedHandler(2*time.Minute, s.handleAnalyticsDailyModule)).Methods("GET", "OPTIONS")
@@ -264,6 +311,8 @@ func registerStatusRoutes(r *mux.Router, s *Server) {
 	r.HandleFunc("/insights/top-contracts", cachedHandler(5*time.Minute, s.handleTopContracts)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/analytics/top-contracts", cachedHandler(5*time.Minute, s.handleTopContracts)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/insights/token-volume", cachedHandler(5*time.Minute, s.handleTokenVolume)).Methods("GET", "OPTIONS")
+	r.HandleFunc("/insights/events/top", cachedHandler(5*time.Minute, s.handleTopEventTypes)).Methods("GET", "OPTIONS")
+	r.HandleFunc("/insights/blocks/tx-distribution", cachedHandler(5*time.Minute, s.handleTxPerBlockDistribution)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/analytics/token-volume", cachedHandler(5*time.Minute, s.handleTokenVolume)).Methods("GET", "OPTIONS")
 }
 
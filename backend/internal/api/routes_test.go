@@ -62,7 +62,7 @@ func TestRoutesFromSpec(t *testing.T) {
 		t.Skip("openapi.json not found, skipping route spec test")
 	}
 	server := NewServer(nil, nil, "0", 0)
-	router := server.httpServer.Handler.(*mux.Router)
+	router := server.router
 	spec := loadSpec(t, specPath)
 	assertRoutesFromSpec(t, router, "", spec)
 }
@@ -132,7 +132,7 @@ func TestAllRoutesInSpec(t *testing.T) {
 
 	// Walk all registered routes (excluding admin, wallet, webhook subrouters)
 	server := NewServer(nil, nil, "0", 0)
-	router := server.httpServer.Handler.(*mux.Router)
+	router := server.router
 
 	var missing []string
 	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeScriptHash(t *testing.T) {
+	t.Parallel()
+
+	valid := strings.Repeat("ab", 32)
+	cases := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{valid, valid, true},
+		{"0x" + strings.ToUpper(valid), valid, true},
+		{" " + valid + " ", valid, true},
+		{valid[:63], "", false},
+		{strings.Repeat("zz", 32), "", false},
+		{"", "", false},
+	}
+	for _, c := range cases {
+		got, ok := normalizeScriptHash(c.in)
+		if got != c.want || ok != c.ok {
+			t.Errorf("normalizeScriptHash(%q) = %q, %v; want %q, %v", c.in, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestScriptRoutes(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(nil, nil, "0", 0)
+	cases := []struct {
+		path string
+		want int
+	}{
+		// "popular" must not be captured by the {hash} route.
+		{"/flow/script/popular", http.StatusInternalServerError},
+		{"/flow/script/not-a-hash", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if rec.Code != c.want {
+			t.Errorf("GET %s = %d, want %d (%s)", c.path, rec.Code, c.want, rec.Body.String())
+		}
+	}
+}
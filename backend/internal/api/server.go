@@ -220,6 +220,7 @@ func (s *Server) buildStatusPayload(ctx context.Context, includeRanges bool) ([]
 		"analytics_deriver_worker": os.Getenv("ENABLE_ANALYTICS_DERIVER_WORKER") != "false",
 		"staking_worker":           os.Getenv("ENABLE_STAKING_WORKER") != "false",
 		"defi_worker":              os.Getenv("ENABLE_DEFI_WORKER") != "false",
+		"nft_sales_worker":         os.Getenv("ENABLE_NFT_SALES_WORKER") != "false",
 		"daily_balance_worker":     os.Getenv("ENABLE_DAILY_BALANCE_WORKER") != "false",
 		"nft_item_metadata_worker": os.Getenv("ENABLE_NFT_ITEM_METADATA_WORKER") != "false",
 		"nft_ownership_reconciler": os.Getenv("ENABLE_NFT_OWNERSHIP_RECONCILER") != "false",
@@ -286,6 +287,10 @@ func (s *Server) buildStatusPayload(ctx context.Context, includeRanges bool) ([]
 			"concurrency": getEnvInt("DEFI_WORKER_CONCURRENCY", 1),
 			"range":       getEnvUint("DEFI_WORKER_RANGE", 1000),
 		},
+		"nft_sales_worker": {
+			"concurrency": getEnvInt("NFT_SALES_WORKER_CONCURRENCY", 1),
+			"range":       getEnvUint("NFT_SALES_WORKER_RANGE", 1000),
+		},
 		"daily_balance_worker": {
 			"concurrency": getEnvInt("DAILY_BALANCE_WORKER_CONCURRENCY", 1),
 			"range":       getEnvUint("DAILY_BALANCE_WORKER_RANGE", 1000),
@@ -3,10 +3,12 @@ package api
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"flowscan-clone/internal/ingester"
@@ -124,12 +126,24 @@ type Server struct {
 	client             FlowClient
 	historyClient      interface{} // flow client with historic spork nodes (for backfill workers)
 	httpServer         *http.Server
+	router             *mux.Router
+	maxConns           int // 0 = unlimited (API_MAX_CONNS)
 	startBlock         uint64
 	blockscoutURL      string // e.g. "https://evm.flowindex.dev"
 	blockscoutAPIKey   string // optional API key for Blockscout rate limit bypass
 	blockscoutDB       *repository.BlockscoutDB
 	backfillProgress   *BackfillProgress
+	historyDeriversMu  sync.Mutex
+	historyDerivers    []*ingester.HistoryDeriver
+	watchdog           *ingester.Watchdog
+	committer          *ingester.CheckpointCommitter
+	backpressure       *ingester.Backpressure
 	priceCache         *market.PriceCache
+	priceCacheHooks    PriceCacheHooks
+	stalePriceAsNull   bool // PRICE_STALE_AS_NULL: omit USD values priced from stale quotes
+	txVerifyTipBlocks  uint64 // TX_VERIFY_TIP_BLOCKS: ?verify=true re-checks txs this close to the tip
+	priceBackfillRunning atomic.Bool
+	adminJobs            *adminJobRegistry
 	webhookHandlers      WebhookRouteRegistrar
 	webhookAdminHandlers WebhookAdminRegistrar
 	apiKeyResolver       APIKeyResolver
@@ -145,11 +159,22 @@ type Server struct {
 		expiresAt time.Time
 	}
 	statusFlight singleflight.Group
+	metadataFlight singleflight.Group
 	latestHeightCache struct {
 		mu        sync.Mutex
 		height    uint64
 		updatedAt time.Time
 	}
+	indexedHeightCache struct {
+		mu        sync.Mutex
+		height    uint64
+		updatedAt time.Time
+		flight    singleflight.Group // one checkpoint read at a time
+	}
+	storageOverviewFailures struct {
+		mu    sync.Mutex
+		until map[string]time.Time // address -> skip the script until
+	}
 }
 
 func NewServer(repo *repository.Repository, client FlowClient, port string, startBlock uint64, opts ...func(*Server)) *Server {
@@ -180,6 +205,9 @@ func NewServer(repo *repository.Repository, client FlowClient, port string, star
 		blockscoutAPIKey: os.Getenv("BLOCKSCOUT_API_KEY"),
 		blockscoutDB:    bsDB,
 		priceCache:    market.NewPriceCache(),
+		stalePriceAsNull: os.Getenv("PRICE_STALE_AS_NULL") == "true",
+		txVerifyTipBlocks: uint64(envInt("TX_VERIFY_TIP_BLOCKS", defaultTxVerifyTipBlocks)),
+		adminJobs:     newAdminJobRegistry(envInt("ADMIN_MAX_JOBS", defaultAdminMaxJobs)),
 	}
 	for _, opt := range opts {
 		opt(s)
@@ -191,6 +219,8 @@ func NewServer(repo *repository.Repository, client FlowClient, port string, star
 	// Simulator logic has been moved to simulate/api/ (runs on simulator VM).
 	// Backend no longer handles /flow/v1/simulate.
 
+	logAdminAuthConfig()
+
 	r.Use(commonMiddleware)
 	r.Use(s.rateLimitMiddleware)
 
@@ -199,10 +229,10 @@ func NewServer(repo *repository.Repository, client FlowClient, port string, star
 	registerAPIRoutes(r, s)
 	registerWalletRoutes(r, s)
 
-	s.httpServer = &http.Server{
-		Addr:    ":" + port,
-		Handler: r,
-	}
+	cfg := httpServerConfigFromEnv()
+	s.router = r
+	s.maxConns = cfg.MaxConns
+	s.httpServer = newHTTPServer(":"+port, r, cfg)
 
 	return s
 }
@@ -225,6 +255,36 @@ func WithHistoryClient(c interface{}) func(*Server) {
 	}
 }
 
+// WithIngesterWatchdog exposes the ingester watchdog's stall state on /status/sync.
+func WithIngesterWatchdog(wd *ingester.Watchdog) func(*Server) {
+	return func(s *Server) {
+		s.watchdog = wd
+	}
+}
+
+// WithCheckpointCommitter exposes the oldest outstanding lease per worker type
+// on /status/sync.
+func WithCheckpointCommitter(c *ingester.CheckpointCommitter) func(*Server) {
+	return func(s *Server) {
+		s.committer = c
+	}
+}
+
+// WithDBBackpressure exposes ingester throttling on DB pool saturation on
+// /status/sync.
+func WithDBBackpressure(b *ingester.Backpressure) func(*Server) {
+	return func(s *Server) {
+		s.backpressure = b
+	}
+}
+
+// RegisterHistoryDeriver exposes hd's progress on /admin/history-deriver/progress.
+func (s *Server) RegisterHistoryDeriver(hd *ingester.HistoryDeriver) {
+	s.historyDeriversMu.Lock()
+	defer s.historyDeriversMu.Unlock()
+	s.historyDerivers = append(s.historyDerivers, hd)
+}
+
 // WithWebhookAdminHandlers returns a Server option that attaches webhook admin handlers.
 func WithWebhookAdminHandlers(wh WebhookAdminRegistrar) func(*Server) {
 	return func(s *Server) {
@@ -258,7 +318,14 @@ func (s *Server) Start() error {
 	// Auto-resume any unfinished reprocess jobs from before a restart.
 	go s.autoResumeReprocessJobs()
 
-	return s.httpServer.ListenAndServe()
+	ln, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	if s.maxConns > 0 {
+		ln = newConnLimitListener(ln, s.maxConns)
+	}
+	return s.httpServer.Serve(ln)
 }
 
 func (s *Server) refreshRangesCacheLoop() {
@@ -451,6 +518,15 @@ func commonMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		format, ok := parseAddressFormat(r)
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, "address_format must be 0x or bare")
+			return
+		}
+		if format != addressFormat0x {
+			r = r.WithContext(withAddressFormat(r.Context(), format))
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+// accountCreatedJSONCDC is a flow.AccountCreated payload as stored when the
+// SDK decoder fell back to raw JSON-CDC.
+const accountCreatedJSONCDC = `{
+	"type": "Event",
+	"value": {
+		"id": "flow.AccountCreated",
+		"fields": [
+			{"name": "address", "value": {"type": "Address", "value": "0x1e3c78c6d580273b"}}
+		]
+	}
+}`
+
+func TestServiceEventFieldsFlatten(t *testing.T) {
+	t.Parallel()
+
+	raw, ok := serviceEventFields([]byte(accountCreatedJSONCDC), false).(map[string]interface{})
+	if !ok {
+		t.Fatalf("raw fields should decode to an object")
+	}
+	if raw["type"] != "Event" {
+		t.Errorf("raw fields should keep the JSON-CDC wrapper, got %#v", raw)
+	}
+	if _, ok := raw["address"]; ok {
+		t.Errorf("raw fields should not expose address at the top level")
+	}
+
+	flat := serviceEventFields([]byte(accountCreatedJSONCDC), true)
+	want := map[string]interface{}{"address": "1e3c78c6d580273b"}
+	if !reflect.DeepEqual(flat, want) {
+		t.Errorf("flattened fields = %#v, want %#v", flat, want)
+	}
+
+	// Payloads flattened at ingest are returned unchanged.
+	stored := []byte(`{"address":"1e3c78c6d580273b"}`)
+	if got := serviceEventFields(stored, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("already-flat payload = %#v, want %#v", got, want)
+	}
+}
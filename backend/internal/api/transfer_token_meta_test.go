@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
+)
+
+func TestFTTransferOutputUsesTokenTableMetadata(t *testing.T) {
+	t.Parallel()
+
+	// A USDC transfer as ListTokenTransfersWithContractFiltered returns it,
+	// with the joined app.ft_tokens row.
+	tr := repository.TokenTransferWithContract{
+		TokenTransfer: models.TokenTransfer{
+			TransactionID:        "ab",
+			TokenContractAddress: "b19436aae4d94622",
+			FromAddress:          "0000000000000001",
+			ToAddress:            "0000000000000002",
+			Amount:               "12.5",
+		},
+		ContractName: "FiatToken",
+		Meta: &repository.TokenMetadataInfo{
+			Name:     "USD Coin",
+			Symbol:   "USDC",
+			Decimals: 8,
+			Logo:     "https://example.com/usdc.svg",
+		},
+	}
+
+	out := toFTTransferOutput(context.Background(), tr.TokenTransfer, tr.ContractName, "0000000000000002", tr.Meta, 1)
+	token := out["token"].(map[string]interface{})
+	if token["token"] != "A.b19436aae4d94622.FiatToken.Vault" || token["name"] != "USD Coin" ||
+		token["symbol"] != "USDC" || token["logo"] != "https://example.com/usdc.svg" || token["decimals"] != 8 {
+		t.Fatalf("token = %#v", token)
+	}
+
+	// Without a token row the contract name is the only label.
+	token = toFTTransferOutput(context.Background(), tr.TokenTransfer, tr.ContractName, "", nil, 0)["token"].(map[string]interface{})
+	if token["name"] != "FiatToken" || token["symbol"] != "" || token["logo"] != "" || token["decimals"] != nil {
+		t.Fatalf("token without metadata = %#v", token)
+	}
+}
+
+func TestNFTTransferOutputUsesCollectionImage(t *testing.T) {
+	t.Parallel()
+
+	tr := models.TokenTransfer{TokenContractAddress: "0b2a3299cc857e29", TokenID: "7"}
+	meta := &repository.TokenMetadataInfo{Name: "NBA Top Shot", Logo: "https://example.com/topshot.png"}
+	collection, ok := toNFTTransferOutput(context.Background(), tr, "TopShot", "", meta)["collection"].(map[string]interface{})
+	if !ok || collection["name"] != "NBA Top Shot" || collection["image"] != "https://example.com/topshot.png" {
+		t.Fatalf("collection = %#v", collection)
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"flowscan-clone/internal/contractid"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultTxDependencyDepth = 3
+	maxTxDependencyDepth     = 5
+)
+
+// txDependencyNode is one contract in a transaction's import tree.
+type txDependencyNode struct {
+	Identifier string              `json:"identifier"`
+	Address    string              `json:"address"`
+	Name       string              `json:"name"`
+	Imports    []*txDependencyNode `json:"imports"`
+	Repeated   bool                `json:"repeated,omitempty"`   // expanded elsewhere in the tree
+	Truncated  bool                `json:"truncated,omitempty"`  // has imports below the depth limit
+	Unresolved bool                `json:"unresolved,omitempty"` // code not indexed, imports unknown
+}
+
+// contractCodeLoader returns stored code keyed by A.<address>.<name>.
+type contractCodeLoader func(ctx context.Context, ids []string) (map[string]string, error)
+
+// buildTxDependencyTree expands roots into their import trees, breadth first,
+// loading each level's code in one call. Direct imports are depth 1 and
+// nothing below maxDepth is expanded. A contract is expanded once; later
+// occurrences are marked Repeated, which also stops import cycles. It returns
+// the trees and the number of distinct contracts in them.
+func buildTxDependencyTree(ctx context.Context, roots []string, maxDepth int, load contractCodeLoader) ([]*txDependencyNode, int, error) {
+	seen := make(map[string]bool)
+	newNode := func(id contractid.ID) *txDependencyNode {
+		return &txDependencyNode{
+			Identifier: id.String(),
+			Address:    formatAddressV1(ctx, id.Address),
+			Name:       id.Name,
+			Imports:    []*txDependencyNode{},
+		}
+	}
+
+	top := make([]*txDependencyNode, 0, len(roots))
+	for _, s := range roots {
+		id := contractid.Parse(s)
+		if id.Address == "" || id.Name == "" || seen[id.String()] {
+			continue
+		}
+		seen[id.String()] = true
+		top = append(top, newNode(id))
+	}
+	sort.Slice(top, func(i, j int) bool { return top[i].Identifier < top[j].Identifier })
+
+	level := top
+	for depth := 1; len(level) > 0; depth++ {
+		ids := make([]string, len(level))
+		for i, n := range level {
+			ids[i] = n.Identifier
+		}
+		codes, err := load(ctx, ids)
+		if err != nil {
+			return nil, 0, err
+		}
+		var next []*txDependencyNode
+		for _, n := range level {
+			code, ok := codes[n.Identifier]
+			if !ok {
+				n.Unresolved = true
+				continue
+			}
+			imports := parseContractImports(code)
+			if depth >= maxDepth {
+				n.Truncated = len(imports) > 0
+				continue
+			}
+			for _, imp := range imports {
+				child := newNode(contractid.FromParts(imp.Address, imp.Name))
+				if seen[child.Identifier] {
+					child.Repeated = true
+				} else {
+					seen[child.Identifier] = true
+					next = append(next, child)
+				}
+				n.Imports = append(n.Imports, child)
+			}
+		}
+		level = next
+	}
+	return top, len(seen), nil
+}
+
+// handleFlowTransactionDependencies returns the contracts a transaction
+// imported, from tx_contracts_worker's records and its script, each with the
+// contracts its stored code imports, down to ?depth= levels (default 3, max 5).
+// GET /flow/transaction/{id}/dependencies?depth=
+func (s *Server) handleFlowTransactionDependencies(w http.ResponseWriter, r *http.Request) {
+	depth := defaultTxDependencyDepth
+	if v := r.URL.Query().Get("depth"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxTxDependencyDepth {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("depth must be between 1 and %d", maxTxDependencyDepth))
+			return
+		}
+		depth = n
+	}
+	id := normalizeAddr(mux.Vars(r)["id"])
+	tx, err := s.repo.GetTransactionImports(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "transaction not found")
+		return
+	}
+
+	roots := append([]string{}, tx.Contracts...)
+	for _, imp := range parseContractImports(tx.Script) {
+		roots = append(roots, "A."+imp.Address+"."+imp.Name)
+	}
+	tree, count, err := buildTxDependencyTree(r.Context(), roots, depth, s.repo.GetContractCodes)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	meta := map[string]interface{}{
+		"transaction_id": id,
+		"block_height":   tx.BlockHeight,
+		"depth":          depth,
+		"contract_count": count,
+	}
+	writeAPIResponse(w, tree, meta, nil)
+}
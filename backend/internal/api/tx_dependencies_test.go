@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBuildTxDependencyTree(t *testing.T) {
+	t.Parallel()
+
+	const (
+		market = "A.0000000000000001.Market"
+		nft    = "A.0000000000000002.NonFungibleToken"
+		ft     = "A.0000000000000003.FungibleToken"
+		views  = "A.0000000000000004.ViewResolver"
+	)
+	// The transaction imports Market, which imports NonFungibleToken and
+	// FungibleToken. Both of those import ViewResolver; NonFungibleToken also
+	// imports Market back.
+	codes := map[string]string{
+		market: "import NonFungibleToken from 0x02\nimport FungibleToken from 0x0000000000000003\naccess(all) contract Market {}",
+		nft:    "import ViewResolver from 0x04\nimport Market from 0x01\naccess(all) contract interface NonFungibleToken {}",
+		ft:     "import ViewResolver from 0x04\naccess(all) contract interface FungibleToken {}",
+	}
+	var loads [][]string
+	load := func(_ context.Context, ids []string) (map[string]string, error) {
+		loads = append(loads, ids)
+		out := map[string]string{}
+		for _, id := range ids {
+			if c, ok := codes[id]; ok {
+				out[id] = c
+			}
+		}
+		return out, nil
+	}
+
+	tree, count, err := buildTxDependencyTree(context.Background(), []string{market, "A.1.Market", "FungibleToken"}, 5, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree) != 1 || tree[0].Identifier != market || tree[0].Address != "0x0000000000000001" {
+		t.Fatalf("roots = %+v", tree)
+	}
+	deps := tree[0].Imports
+	if len(deps) != 2 || deps[0].Identifier != nft || deps[1].Identifier != ft {
+		t.Fatalf("Market imports = %+v", deps)
+	}
+	nftImports := deps[0].Imports
+	if len(nftImports) != 2 || nftImports[0].Identifier != views || nftImports[0].Repeated ||
+		nftImports[1].Identifier != market || !nftImports[1].Repeated {
+		t.Fatalf("NonFungibleToken imports = %+v", nftImports)
+	}
+	if ftImports := deps[1].Imports; len(ftImports) != 1 || ftImports[0].Identifier != views || !ftImports[0].Repeated {
+		t.Fatalf("FungibleToken imports = %+v", ftImports)
+	}
+	if !nftImports[0].Unresolved {
+		t.Fatalf("ViewResolver has no stored code and should be unresolved")
+	}
+	if count != 4 {
+		t.Fatalf("contract count = %d; want 4", count)
+	}
+	// One load per level, each contract loaded once.
+	if len(loads) != 3 || len(loads[0]) != 1 || len(loads[1]) != 2 || len(loads[2]) != 1 {
+		t.Fatalf("loads = %v", loads)
+	}
+
+	tree, count, err = buildTxDependencyTree(context.Background(), []string{market}, 1, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree[0].Imports) != 0 || !tree[0].Truncated || count != 1 {
+		t.Fatalf("depth 1: %+v (count %d)", tree[0], count)
+	}
+}
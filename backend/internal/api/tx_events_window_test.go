@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"flowscan-clone/internal/repository"
+)
+
+func TestParseTxEventsWindow(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		query               string
+		wantLimit, wantSkip int
+	}{
+		{"", repository.DefaultTxEventsLimit, 0},
+		{"?events_limit=25&events_offset=50", 25, 50},
+		{"?events_limit=100000", maxTxEventsLimit, 0},
+		{"?events_limit=0&events_offset=-1", repository.DefaultTxEventsLimit, 0},
+		{"?events_limit=abc", repository.DefaultTxEventsLimit, 0},
+	} {
+		limit, offset := parseTxEventsWindow(httptest.NewRequest("GET", "/flow/transaction/abc"+tc.query, nil))
+		if limit != tc.wantLimit || offset != tc.wantSkip {
+			t.Errorf("%q: limit=%d offset=%d; want %d, %d", tc.query, limit, offset, tc.wantLimit, tc.wantSkip)
+		}
+	}
+
+	meta := txEventsMeta(&repository.TxEventsPage{Total: 2500, HasMore: true}, 100, 0)
+	if meta["events_total"] != 2500 || meta["events_has_more"] != true || meta["events_limit"] != 100 {
+		t.Fatalf("meta = %v", meta)
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"flowscan-clone/internal/models"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+)
+
+// tipResultClient reports a chain tip and a fixed transaction result.
+type tipResultClient struct {
+	mockFlowClient
+	tip    uint64
+	result *flowsdk.TransactionResult
+	calls  int
+}
+
+func (c *tipResultClient) GetLatestBlockHeight(ctx context.Context) (uint64, error) {
+	return c.tip, nil
+}
+
+func (c *tipResultClient) GetTransactionResult(ctx context.Context, txID flowsdk.Identifier) (*flowsdk.TransactionResult, error) {
+	c.calls++
+	return c.result, nil
+}
+
+const verifyTxID = "6f2c1e4a5b7d8c9e0f1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f70"
+
+func TestVerifyTipTransactionAppliesUpdatedResult(t *testing.T) {
+	t.Parallel()
+
+	client := &tipResultClient{tip: 1010, result: &flowsdk.TransactionResult{
+		Status:           flowsdk.TransactionStatusSealed,
+		Error:            errors.New("[Error Code: 1101] cadence runtime error"),
+		ComputationUsage: 42,
+	}}
+	s := &Server{client: client, txVerifyTipBlocks: 600}
+	tx := &models.Transaction{ID: verifyTxID, BlockHeight: 1000, Status: models.TxStatusExecuted, GasUsed: 17}
+
+	if !s.verifyTipTransaction(context.Background(), tx) {
+		t.Fatal("recent transaction was not verified")
+	}
+	if tx.Status != models.TxStatusFailed || tx.GasUsed != 42 || tx.ErrorMessage == "" {
+		t.Fatalf("tx not updated from verified result: %+v", tx)
+	}
+}
+
+func TestVerifyTipTransactionSkipsOldTransactions(t *testing.T) {
+	t.Parallel()
+
+	client := &tipResultClient{tip: 5000, result: &flowsdk.TransactionResult{Status: flowsdk.TransactionStatusSealed}}
+	s := &Server{client: client, txVerifyTipBlocks: 600}
+	tx := &models.Transaction{ID: verifyTxID, BlockHeight: 1000, Status: models.TxStatusSealed}
+
+	if s.verifyTipTransaction(context.Background(), tx) {
+		t.Fatal("transaction far below the tip was verified")
+	}
+	if client.calls != 0 {
+		t.Fatalf("access node queried %d times", client.calls)
+	}
+}
+
+func TestApplyVerifiedTxResult(t *testing.T) {
+	t.Parallel()
+
+	tx := &models.Transaction{Status: models.TxStatusSealed, GasUsed: 9}
+	if applyVerifiedTxResult(tx, &flowsdk.TransactionResult{Status: flowsdk.TransactionStatusSealed, ComputationUsage: 9}) {
+		t.Fatal("unchanged result reported as changed")
+	}
+	if applyVerifiedTxResult(tx, &flowsdk.TransactionResult{Status: flowsdk.TransactionStatusPending, ComputationUsage: 1}) {
+		t.Fatal("pending result applied")
+	}
+	if !applyVerifiedTxResult(tx, &flowsdk.TransactionResult{Status: flowsdk.TransactionStatusSealed, ComputationUsage: 11}) || tx.GasUsed != 11 {
+		t.Fatalf("gas change not applied: %+v", tx)
+	}
+}
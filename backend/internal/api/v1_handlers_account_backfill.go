@@ -42,7 +42,7 @@ func (s *Server) handleFlowCOABackfill(w http.ResponseWriter, r *http.Request) {
 	}
 
 	writeAPIResponse(w, []interface{}{map[string]interface{}{
-		"flow_address": formatAddressV1(flowAddr),
+		"flow_address": formatAddressV1(r.Context(), flowAddr),
 		"coa_address":  coaAddr,
 	}}, nil, nil)
 }
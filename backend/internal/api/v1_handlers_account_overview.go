@@ -0,0 +1,158 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	flowsdk "github.com/onflow/flow-go-sdk"
+)
+
+// accountOverviewSectionTimeout bounds each sub-fetch of the account overview,
+// so one slow source cannot hold up the whole response.
+const accountOverviewSectionTimeout = 3 * time.Second
+
+var errRepoUnavailable = errors.New("repository unavailable")
+
+// overviewSection fetches one section of a composite response.
+type overviewSection func(ctx context.Context) (interface{}, error)
+
+// fetchSections runs every section concurrently, each under its own timeout.
+// A section that fails or times out is reported as nil in the result and listed
+// in failed (sorted), without affecting the others.
+func fetchSections(ctx context.Context, timeout time.Duration, sections map[string]overviewSection) (map[string]interface{}, []string) {
+	type outcome struct {
+		name  string
+		value interface{}
+		err   error
+	}
+	results := make(chan outcome, len(sections))
+	var wg sync.WaitGroup
+	for name, fetch := range sections {
+		wg.Add(1)
+		go func(name string, fetch overviewSection) {
+			defer wg.Done()
+			sctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			done := make(chan outcome, 1)
+			go func() {
+				v, err := fetch(sctx)
+				done <- outcome{name: name, value: v, err: err}
+			}()
+			select {
+			case o := <-done:
+				results <- o
+			case <-sctx.Done():
+				results <- outcome{name: name, err: sctx.Err()}
+			}
+		}(name, fetch)
+	}
+	wg.Wait()
+	close(results)
+
+	out := make(map[string]interface{}, len(sections))
+	failed := make([]string, 0)
+	for o := range results {
+		if o.err != nil {
+			failed = append(failed, o.name)
+			out[o.name] = nil
+			continue
+		}
+		out[o.name] = o.value
+	}
+	sort.Strings(failed)
+	return out, failed
+}
+
+// handleFlowAccountOverview returns the data a wallet landing page needs in one
+// call: FLOW balance, transaction/NFT/token counts and creation info.
+func (s *Server) handleFlowAccountOverview(w http.ResponseWriter, r *http.Request) {
+	raw := normalizeAddr(mux.Vars(r)["address"])
+	if raw == "" || len(raw) > 16 || !hexPattern.MatchString(raw) {
+		writeAPIError(w, http.StatusBadRequest, "invalid address")
+		return
+	}
+	addr := normalizeAddr(flowsdk.HexToAddress(raw).Hex())
+
+	data, failed := fetchSections(r.Context(), accountOverviewSectionTimeout, s.accountOverviewSections(addr))
+	for _, name := range failed {
+		log.Printf("[account_overview] %s: section %s unavailable", addr, name)
+	}
+	data["address"] = formatAddressV1(r.Context(), addr)
+	writeAPIResponse(w, []interface{}{data}, map[string]interface{}{"failed_sections": failed}, nil)
+}
+
+func (s *Server) accountOverviewSections(addr string) map[string]overviewSection {
+	return map[string]overviewSection{
+		"balance": func(ctx context.Context) (interface{}, error) {
+			if s.client == nil {
+				return nil, errors.New("flow client unavailable")
+			}
+			acc, err := s.client.GetAccount(ctx, flowsdk.HexToAddress(addr))
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"flow": strconv.FormatFloat(float64(acc.Balance)/1e8, 'f', -1, 64),
+			}, nil
+		},
+		"transactions": func(ctx context.Context) (interface{}, error) {
+			if s.repo == nil {
+				return nil, errRepoUnavailable
+			}
+			n, err := s.repo.CountAddressTransactions(ctx, addr)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"count": n}, nil
+		},
+		"nfts": func(ctx context.Context) (interface{}, error) {
+			if s.repo == nil {
+				return nil, errRepoUnavailable
+			}
+			items, err := s.repo.CountNFTsByOwner(ctx, addr)
+			if err != nil {
+				return nil, err
+			}
+			collections, err := s.repo.CountNFTCollectionSummariesByOwner(ctx, addr)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"count": items, "collections": collections}, nil
+		},
+		"tokens": func(ctx context.Context) (interface{}, error) {
+			if s.repo == nil {
+				return nil, errRepoUnavailable
+			}
+			n, err := s.repo.CountFTHoldingsByAddress(ctx, addr)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"count": n}, nil
+		},
+		"creation": func(ctx context.Context) (interface{}, error) {
+			if s.repo == nil {
+				return nil, errRepoUnavailable
+			}
+			acc, err := s.repo.GetAccountCatalog(ctx, addr)
+			if err != nil {
+				return nil, err
+			}
+			if acc == nil {
+				// Not indexed yet; that is an answer, not a failure.
+				return map[string]interface{}{"first_seen_height": nil, "last_seen_height": nil}, nil
+			}
+			return map[string]interface{}{
+				"first_seen_height": acc.FirstSeenHeight,
+				"last_seen_height":  acc.LastSeenHeight,
+			}, nil
+		},
+	}
+}
@@ -20,6 +20,28 @@ import (
 	flowsdk "github.com/onflow/flow-go-sdk"
 )
 
+// accountListBalanceTimeout bounds the live balance lookup for a page of
+// accounts; the list is still served (with zero balances) if it is exceeded.
+const accountListBalanceTimeout = 3 * time.Second
+
+// flowBalances fetches FLOW balances (1e-8 units) for addrs in one batched
+// script when the client supports it. Failures are logged and yield an empty
+// map.
+func (s *Server) flowBalances(ctx context.Context, addrs []string) map[string]uint64 {
+	p, ok := s.client.(accountBalancesProvider)
+	if !ok || len(addrs) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, accountListBalanceTimeout)
+	defer cancel()
+	balances, err := p.GetAccountBalances(ctx, addrs)
+	if err != nil {
+		log.Printf("[WARN] GetAccountBalances(%d addresses): %v", len(addrs), err)
+		return nil
+	}
+	return balances
+}
+
 func (s *Server) handleFlowListAccounts(w http.ResponseWriter, r *http.Request) {
 	if s.repo == nil {
 		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
@@ -28,7 +50,7 @@ func (s *Server) handleFlowListAccounts(w http.ResponseWriter, r *http.Request)
 	limit, offset := parseLimitOffset(r)
 	height, err := parseHeightParam(r.URL.Query().Get("height"))
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+		writeAPIError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -65,15 +87,21 @@ func (s *Server) handleFlowListAccounts(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	addrs := make([]string, len(accounts))
+	for i, a := range accounts {
+		addrs[i] = a.Address
+	}
+	balances := s.flowBalances(r.Context(), addrs)
+
 	const bytesPerMB = 1024 * 1024
 	out := make([]map[string]interface{}, 0, len(accounts))
 	for _, a := range accounts {
 		out = append(out, map[string]interface{}{
-			"address":           formatAddressV1(a.Address),
+			"address":           formatAddressV1(r.Context(), a.Address),
 			"creator":           "",
 			"data":              map[string]interface{}{},
 			"find_name":         "",
-			"flow_balance":      0,
+			"flow_balance":      float64(balances[normalizeAddr(flowsdk.HexToAddress(a.Address).Hex())]) / 1e8,
 			"flow_storage":      float64(a.StorageCapacity) / bytesPerMB,
 			"storage_used":      float64(a.StorageUsed) / bytesPerMB,
 			"storage_available": float64(a.StorageAvailable) / bytesPerMB,
@@ -160,11 +188,9 @@ func (s *Server) handleFlowGetAccount(w http.ResponseWriter, r *http.Request) {
 			storageAvailable = snap.StorageAvailable
 		}
 	}
-	if storageCapacity == 0 {
-		if raw, err := s.executeCadenceScript(r.Context(), cadenceStorageOverviewScript(), []cadence.Value{
-			cadence.NewAddress([8]byte(acc.Address)),
-		}); err == nil {
-			storageUsed, storageCapacity = parseStorageOverview(raw)
+	storageKnown := storageCapacity > 0
+	if !storageKnown {
+		if storageUsed, storageCapacity, storageKnown = s.fetchStorageOverview(r.Context(), acc.Address); storageKnown {
 			if storageCapacity > storageUsed {
 				storageAvailable = storageCapacity - storageUsed
 			}
@@ -173,10 +199,6 @@ func (s *Server) handleFlowGetAccount(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
-	const bytesPerMB = 1024 * 1024
-	storageCapacityMB := float64(storageCapacity) / bytesPerMB
-	storageUsedMB := float64(storageUsed) / bytesPerMB
-	storageAvailableMB := float64(storageAvailable) / bytesPerMB
 
 	// Fetch account labels (DB stores with 0x prefix)
 	var accountLabels []map[string]interface{}
@@ -194,18 +216,45 @@ func (s *Server) handleFlowGetAccount(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := map[string]interface{}{
-		"address":          formatAddressV1(acc.Address.Hex()),
-		"flowBalance":      float64(acc.Balance) / 1e8,
-		"contracts":        contractNames,
-		"keys":             keys,
-		"flowStorage":      storageCapacityMB,
-		"storageUsed":      storageUsedMB,
-		"storageAvailable": storageAvailableMB,
-		"labels":           accountLabels,
+		"address":     formatAddressV1(r.Context(), acc.Address.Hex()),
+		"flowBalance": float64(acc.Balance) / 1e8,
+		"contracts":   contractNames,
+		"keys":        keys,
+		"labels":      accountLabels,
+	}
+	// Storage fields are omitted rather than reported as zero when neither the
+	// snapshot nor the script produced them.
+	if storageKnown {
+		const bytesPerMB = 1024 * 1024
+		data["flowStorage"] = float64(storageCapacity) / bytesPerMB
+		data["storageUsed"] = float64(storageUsed) / bytesPerMB
+		data["storageAvailable"] = float64(storageAvailable) / bytesPerMB
+	}
+	if roles := s.accountRoleCounts(r.Context(), addressNorm); roles != nil {
+		data["roleCounts"] = roles
 	}
 	writeAPIResponse(w, []interface{}{data}, nil, nil)
 }
 
+// accountRoleCounts returns how often the address took part in transactions in
+// each role, from app.address_stats. Returns nil when no stats are indexed.
+func (s *Server) accountRoleCounts(ctx context.Context, addressNorm string) map[string]int64 {
+	if s.repo == nil {
+		return nil
+	}
+	st, err := s.repo.GetAddressStats(ctx, addressNorm)
+	if err != nil || st == nil {
+		return nil
+	}
+	return map[string]int64{
+		"payer":            st.PayerCount,
+		"proposer":         st.ProposerCount,
+		"authorizer":       st.AuthorizerCount,
+		"transferSent":     st.TransferSentCount,
+		"transferReceived": st.TransferReceivedCount,
+	}
+}
+
 // buildAccountFallback returns a degraded account response from DB when RPC fails.
 // Always returns a response (never nil) so accounts with RPC errors (e.g. storage
 // limit exceeded) still render instead of showing 404.
@@ -259,8 +308,8 @@ func (s *Server) buildAccountFallback(ctx context.Context, addr flowsdk.Address)
 
 	log.Printf("[INFO] Serving fallback account data for %s (RPC unavailable, hasTxs=%v, keys=%d)", addr.Hex(), hasTxs, len(keys))
 
-	return map[string]interface{}{
-		"address":          formatAddressV1(addr.Hex()),
+	data := map[string]interface{}{
+		"address":          formatAddressV1(ctx, addr.Hex()),
 		"flowBalance":      float64(-1), // -1 signals "unavailable" to frontend
 		"contracts":        contractNames,
 		"keys":             keys,
@@ -269,6 +318,10 @@ func (s *Server) buildAccountFallback(ctx context.Context, addr flowsdk.Address)
 		"storageAvailable": float64(storageAvailable) / bytesPerMB,
 		"_rpcUnavailable":  true,
 	}
+	if roles := s.accountRoleCounts(ctx, addressNorm); roles != nil {
+		data["roleCounts"] = roles
+	}
+	return data
 }
 
 func (s *Server) handleFlowAccountLabels(w http.ResponseWriter, r *http.Request) {
@@ -294,18 +347,69 @@ func (s *Server) handleFlowAccountLabels(w http.ResponseWriter, r *http.Request)
 	writeAPIResponse(w, out, nil, nil)
 }
 
+// handleFlowAccountCounterparties lists the addresses an account most often
+// exchanges fungible tokens with.
+// GET /flow/account/{address}/counterparty?direction=both&days=30&limit=20
+func (s *Server) handleFlowAccountCounterparties(w http.ResponseWriter, r *http.Request) {
+	address := normalizeAddr(mux.Vars(r)["address"])
+	limit, _ := parseLimitOffset(r)
+	direction := strings.ToLower(r.URL.Query().Get("direction"))
+	if direction == "" {
+		direction = "both"
+	}
+	if direction != "sent" && direction != "received" && direction != "both" {
+		writeAPIError(w, http.StatusBadRequest, "direction must be one of sent, received, both")
+		return
+	}
+	var since time.Time
+	if v := r.URL.Query().Get("days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 || days > 3650 {
+			writeAPIError(w, http.StatusBadRequest, "days must be between 1 and 3650")
+			return
+		}
+		since = time.Now().AddDate(0, 0, -days)
+	}
+
+	counterparties, err := s.repo.GetTopCounterparties(r.Context(), address, direction, since, limit)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]map[string]interface{}, 0, len(counterparties))
+	for _, c := range counterparties {
+		tokens := make([]map[string]interface{}, 0, len(c.Tokens))
+		for _, t := range c.Tokens {
+			tokens = append(tokens, map[string]interface{}{
+				"token":           "A." + t.TokenAddress + "." + t.ContractName,
+				"sent_amount":     t.SentAmount,
+				"received_amount": t.ReceivedAmount,
+			})
+		}
+		out = append(out, map[string]interface{}{
+			"address":        formatAddressV1(r.Context(), c.Address),
+			"transfer_count": c.TransferCount,
+			"sent_count":     c.SentCount,
+			"received_count": c.ReceivedCount,
+			"tokens":         tokens,
+		})
+	}
+	writeAPIResponse(w, out, map[string]interface{}{"count": len(out), "direction": direction}, nil)
+}
+
 func (s *Server) handleFlowAccountTransactions(w http.ResponseWriter, r *http.Request) {
 	address := normalizeAddr(mux.Vars(r)["address"])
 	limit, offset := parseLimitOffset(r)
 	includeEvents := strings.ToLower(r.URL.Query().Get("include_events")) == "true"
 	height, err := parseHeightParam(r.URL.Query().Get("height"))
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+		writeAPIError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	txs, err := s.repo.GetTransactionsByAddress(r.Context(), address, limit, offset)
+	role := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("role")))
+	txs, err := s.repo.GetTransactionsByAddress(r.Context(), address, role, limit, offset)
 	if err != nil {
-		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		writeRepoError(w, err, "")
 		return
 	}
 	// The repo fetches limit+1 rows; trim to detect hasMore.
@@ -336,6 +440,7 @@ func (s *Server) handleFlowAccountTransactions(w http.ResponseWriter, r *http.Re
 		transferSummaries  map[string]repository.TransferSummary
 		canonicalSummaries map[string]repository.TransferSummary
 		totalCount         int64
+		compactedBelow     uint64
 	)
 
 	var wg sync.WaitGroup
@@ -365,9 +470,14 @@ func (s *Server) handleFlowAccountTransactions(w http.ResponseWriter, r *http.Re
 	}()
 	go func() {
 		defer wg.Done()
-		if t, err := s.repo.GetAddressTxCount(ctx, address); err == nil && t > 0 {
-			totalCount = t
+		// address_stats.tx_count covers every role, so it is no total for a
+		// role-filtered list.
+		if role == "" {
+			if t, err := s.repo.GetAddressTxCount(ctx, address); err == nil && t > 0 {
+				totalCount = t
+			}
 		}
+		compactedBelow, _ = s.repo.GetAddressCompaction(ctx, address)
 	}()
 	if includeEvents {
 		go func() {
@@ -396,25 +506,36 @@ func (s *Server) handleFlowAccountTransactions(w http.ResponseWriter, r *http.Re
 	for _, t := range txs {
 		ts := transferSummaries[t.ID]
 		ftPrices := s.buildFTPrices(ftMeta, t.Timestamp)
-		o := toFlowTransactionOutputWithTransfers(t, eventsByTx[t.ID], contracts[t.ID], tags[t.ID], feesByTx[t.ID], &ts, ftMeta, nftMeta, ftPrices)
+		o := toFlowTransactionOutputWithTransfers(ctx, t, eventsByTx[t.ID], contracts[t.ID], tags[t.ID], feesByTx[t.ID], &ts, ftMeta, nftMeta, ftPrices)
 		if canonical, ok := canonicalSummaries[t.ID]; ok && len(canonical.FT) > 0 {
-			o["canonical_transfer_summary"] = toTransferSummaryOutput(canonical, ftMeta, map[string]repository.TokenMetadataInfo{}, ftPrices)
+			o["canonical_transfer_summary"] = toTransferSummaryOutput(ctx, canonical, ftMeta, map[string]repository.TokenMetadataInfo{}, ftPrices)
 		}
 		out = append(out, o)
 	}
 	meta := map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}
-	if totalCount > 0 {
-		meta["total"] = totalCount
-	}
+	addAddressActivityTotals(meta, totalCount, compactedBelow)
 	writeAPIResponse(w, out, meta, nil)
 }
 
+// addAddressActivityTotals sets the total from app.address_stats and, for
+// compacted addresses, flags that the list stops at compacted_below_height
+// even though total still counts the trimmed history.
+func addAddressActivityTotals(meta map[string]interface{}, total int64, compactedBelow uint64) {
+	if total > 0 {
+		meta["total"] = total
+	}
+	if compactedBelow > 0 {
+		meta["compacted"] = true
+		meta["compacted_below_height"] = compactedBelow
+	}
+}
+
 func (s *Server) handleFlowAccountFTTransfers(w http.ResponseWriter, r *http.Request) {
 	address := normalizeAddr(mux.Vars(r)["address"])
-	limit, offset := parseLimitOffset(r)
+	limit, offset := parseLimitOffsetWithMax(r, maxLimitTransfers)
 	height, err := parseHeightParam(r.URL.Query().Get("height"))
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+		writeAPIError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	transfers, hasMore, err := s.repo.ListTokenTransfersWithContractFiltered(r.Context(), false, address, "", "", "", height, limit, offset)
@@ -422,33 +543,26 @@ func (s *Server) handleFlowAccountFTTransfers(w http.ResponseWriter, r *http.Req
 		writeAPIError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	// Batch lookup token metadata.
-	ftIDs := collectTransferTokenIDs(transfers, false)
-	ftMeta, _ := s.repo.GetFTTokenMetadataByIdentifiers(r.Context(), ftIDs)
 	out := make([]map[string]interface{}, 0, len(transfers))
 	for _, t := range transfers {
-		id := formatTokenVaultIdentifier(t.TokenContractAddress, t.ContractName)
-		var m *repository.TokenMetadataInfo
-		if meta, ok := ftMeta[id]; ok {
-			m = &meta
-		}
+		m := t.Meta
 		var usdPrice float64
 		if m != nil && m.MarketSymbol != "" {
 			usdPrice, _ = s.priceCache.GetPriceAt(m.MarketSymbol, t.TokenTransfer.Timestamp)
 		} else if t.ContractName == "FlowToken" {
 			usdPrice, _ = s.priceCache.GetPriceAt("FLOW", t.TokenTransfer.Timestamp)
 		}
-		out = append(out, toFTTransferOutput(t.TokenTransfer, t.ContractName, address, m, usdPrice))
+		out = append(out, toFTTransferOutput(r.Context(), t.TokenTransfer, t.ContractName, address, m, usdPrice))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}, nil)
 }
 
 func (s *Server) handleFlowAccountNFTTransfers(w http.ResponseWriter, r *http.Request) {
 	address := normalizeAddr(mux.Vars(r)["address"])
-	limit, offset := parseLimitOffset(r)
+	limit, offset := parseLimitOffsetWithMax(r, maxLimitTransfers)
 	height, err := parseHeightParam(r.URL.Query().Get("height"))
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+		writeAPIError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	transfers, hasMore, err := s.repo.ListTokenTransfersWithContractFiltered(r.Context(), true, address, "", "", "", height, limit, offset)
@@ -456,17 +570,9 @@ func (s *Server) handleFlowAccountNFTTransfers(w http.ResponseWriter, r *http.Re
 		writeAPIError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	// Batch lookup collection metadata.
-	nftIDs := collectTransferTokenIDs(transfers, true)
-	nftMeta, _ := s.repo.GetNFTCollectionMetadataByIdentifiers(r.Context(), nftIDs)
 	out := make([]map[string]interface{}, 0, len(transfers))
 	for _, t := range transfers {
-		id := formatTokenIdentifier(t.TokenContractAddress, t.ContractName)
-		var m *repository.TokenMetadataInfo
-		if meta, ok := nftMeta[id]; ok {
-			m = &meta
-		}
-		out = append(out, toNFTTransferOutput(t.TokenTransfer, t.ContractName, address, m))
+		out = append(out, toNFTTransferOutput(r.Context(), t.TokenTransfer, t.ContractName, address, t.Meta))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}, nil)
 }
@@ -492,13 +598,90 @@ func (s *Server) handleGetCOAMapping(w http.ResponseWriter, r *http.Request) {
 	}
 	out := map[string]interface{}{
 		"coa_address":    row.COAAddress,
-		"flow_address":   formatAddressV1(row.FlowAddress),
+		"flow_address":   formatAddressV1(r.Context(), row.FlowAddress),
 		"transaction_id": row.TransactionID,
 		"block_height":   row.BlockHeight,
 	}
 	writeAPIResponse(w, []interface{}{out}, nil, nil)
 }
 
+// handleFlowAccountCOAs lists every COA owned by a Flow account.
+// GET /flow/account/{address}/coa
+func (s *Server) handleFlowAccountCOAs(w http.ResponseWriter, r *http.Request) {
+	address := normalizeFlowAddr(mux.Vars(r)["address"])
+	if address == "" {
+		writeAPIError(w, http.StatusBadRequest, "invalid flow address")
+		return
+	}
+	if s.repo == nil {
+		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
+		return
+	}
+	rows, err := s.repo.ListCOAsByFlowAddress(r.Context(), address)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, map[string]interface{}{
+			"coa_address":    row.COAAddress,
+			"flow_address":   formatAddressV1(r.Context(), row.FlowAddress),
+			"transaction_id": row.TransactionID,
+			"block_height":   row.BlockHeight,
+		})
+	}
+	writeAPIResponse(w, out, map[string]interface{}{"count": len(out)}, nil)
+}
+
+// handleFlowAccountContracts lists the contracts deployed on an account. It
+// does not include contracts the account merely imports.
+func (s *Server) handleFlowAccountContracts(w http.ResponseWriter, r *http.Request) {
+	address := normalizeFlowAddr(mux.Vars(r)["address"])
+	if address == "" {
+		writeAPIError(w, http.StatusBadRequest, "invalid flow address")
+		return
+	}
+	if s.repo == nil {
+		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
+		return
+	}
+	contracts, err := s.repo.ListContractsByDeployer(r.Context(), address)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := deployedContractsOutput(r.Context(), contracts)
+	writeAPIResponse(w, out, map[string]interface{}{"count": len(out)}, nil)
+}
+
+// deployedContractsOutput formats the contracts deployed on one account, with
+// the current version and when each was last updated.
+func deployedContractsOutput(ctx context.Context, contracts []models.SmartContract) []interface{} {
+	out := make([]interface{}, 0, len(contracts))
+	for _, c := range contracts {
+		identifier := formatTokenIdentifier(c.Address, c.Name)
+		item := map[string]interface{}{
+			"id":                  identifier,
+			"identifier":          identifier,
+			"address":             formatAddressV1(ctx, c.Address),
+			"name":                c.Name,
+			"version":             c.Version,
+			"first_seen_height":   c.FirstSeenHeight,
+			"last_updated_height": c.BlockHeight,
+			"last_updated_at":     formatTime(c.UpdatedAt),
+			"created_at":          formatTime(c.CreatedAt),
+			"is_verified":         c.IsVerified,
+			"imported_count":      c.DependentCount,
+		}
+		if c.Kind != "" {
+			item["kind"] = c.Kind
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
 func (s *Server) handleFlowSearchByPublicKey(w http.ResponseWriter, r *http.Request) {
 	publicKey := mux.Vars(r)["publicKey"]
 	if publicKey == "" {
@@ -518,7 +701,7 @@ func (s *Server) handleFlowSearchByPublicKey(w http.ResponseWriter, r *http.Requ
 	out := make([]map[string]interface{}, 0, len(keys))
 	for _, k := range keys {
 		out = append(out, map[string]interface{}{
-			"address":           formatAddressV1(k.Address),
+			"address":           formatAddressV1(r.Context(), k.Address),
 			"key_index":         k.KeyIndex,
 			"public_key":        k.PublicKey,
 			"signing_algorithm": k.SigningAlgorithm,
@@ -530,6 +713,75 @@ func (s *Server) handleFlowSearchByPublicKey(w http.ResponseWriter, r *http.Requ
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}, nil)
 }
 
+// handleFlowPublicKeyAddresses lists the accounts holding a public key, one
+// entry per address. Accounts where the key was revoked are only included with
+// ?include_revoked=true.
+func (s *Server) handleFlowPublicKeyAddresses(w http.ResponseWriter, r *http.Request) {
+	publicKey := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(mux.Vars(r)["publicKey"])), "0x")
+	if publicKey == "" || !hexPattern.MatchString(publicKey) {
+		writeAPIError(w, http.StatusBadRequest, "publicKey must be hex encoded")
+		return
+	}
+	if s.repo == nil {
+		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
+		return
+	}
+	includeRevoked := strings.ToLower(r.URL.Query().Get("include_revoked")) == "true"
+	addrs, err := s.repo.ListAddressesByPublicKey(r.Context(), publicKey, includeRevoked)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]map[string]interface{}, 0, len(addrs))
+	for _, a := range addrs {
+		out = append(out, map[string]interface{}{
+			"address":             formatAddressV1(r.Context(), a.Address),
+			"key_indexes":         a.KeyIndexes,
+			"active":              a.Active,
+			"last_updated_height": a.LastUpdatedHeight,
+		})
+	}
+	writeAPIResponse(w, out, map[string]interface{}{"count": len(out), "include_revoked": includeRevoked}, nil)
+}
+
+// handleFlowKeyControlledAccounts lists the accounts where the given public key
+// alone carries at least min_weight (default 1000, i.e. full control) of
+// un-revoked key weight.
+func (s *Server) handleFlowKeyControlledAccounts(w http.ResponseWriter, r *http.Request) {
+	publicKey := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(mux.Vars(r)["publicKey"])), "0x")
+	if publicKey == "" || !hexPattern.MatchString(publicKey) {
+		writeAPIError(w, http.StatusBadRequest, "publicKey must be hex encoded")
+		return
+	}
+	minWeight := repository.FullKeyWeight
+	if v := strings.TrimSpace(r.URL.Query().Get("min_weight")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeAPIError(w, http.StatusBadRequest, "min_weight must be a non-negative integer")
+			return
+		}
+		minWeight = n
+	}
+	if s.repo == nil {
+		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
+		return
+	}
+	accounts, err := s.repo.GetAccountsControlledByKey(r.Context(), publicKey, minWeight)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]map[string]interface{}, 0, len(accounts))
+	for _, a := range accounts {
+		out = append(out, map[string]interface{}{
+			"address":     formatAddressV1(r.Context(), a.Address),
+			"key_indexes": a.KeyIndexes,
+			"weight":      a.Weight,
+		})
+	}
+	writeAPIResponse(w, out, map[string]interface{}{"count": len(out), "min_weight": minWeight}, nil)
+}
+
 func (s *Server) handleFlowAccountFTHoldings(w http.ResponseWriter, r *http.Request) {
 	address := normalizeAddr(mux.Vars(r)["address"])
 
@@ -543,15 +795,18 @@ func (s *Server) handleFlowAccountFTHoldings(w http.ResponseWriter, r *http.Requ
 	}
 
 	out := make([]map[string]interface{}, 0, len(holdings))
+	valued := make([]models.FTHolding, 0, len(holdings))
 	for _, h := range holdings {
 		token := "A." + h.ContractAddress + "." + h.ContractName
 		out = append(out, map[string]interface{}{
-			"address":    formatAddressV1(address),
+			"address":    formatAddressV1(r.Context(), address),
 			"token":      token,
 			"balance":    parseFloatOrZero(h.Balance),
 			"percentage": 0,
 		})
+		valued = append(valued, models.FTHolding{ContractAddress: h.ContractAddress, ContractName: h.ContractName, Balance: h.Balance})
 	}
+	s.valueFTHoldings(r.Context(), out, valued)
 	writeAPIResponse(w, out, map[string]interface{}{"count": len(out)}, nil)
 }
 
@@ -574,8 +829,9 @@ func (s *Server) handleFlowAccountFTHoldingsFromDB(w http.ResponseWriter, r *htt
 	}
 	out := make([]map[string]interface{}, 0, len(holdings))
 	for _, h := range holdings {
-		out = append(out, toFTHoldingOutput(h, 0))
+		out = append(out, toFTHoldingOutput(r.Context(), h, 0))
 	}
+	s.valueFTHoldings(r.Context(), out, holdings)
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": total}, nil)
 }
 
@@ -595,7 +851,7 @@ func (s *Server) handleFlowAccountFTVaults(w http.ResponseWriter, r *http.Reques
 			hasFlowToken = true
 		}
 		out = append(out, map[string]interface{}{
-			"address":  formatAddressV1(address),
+			"address":  formatAddressV1(r.Context(), address),
 			"balance":  row.Balance,
 			"path":     vaultPathForContract(contractName),
 			"token":    formatTokenVaultIdentifier(row.ContractAddress, contractName),
@@ -606,7 +862,7 @@ func (s *Server) handleFlowAccountFTVaults(w http.ResponseWriter, r *http.Reques
 		if acc, err := s.client.GetAccount(r.Context(), flowsdk.HexToAddress(address)); err == nil {
 			bal := strconv.FormatFloat(float64(acc.Balance)/1e8, 'f', -1, 64)
 			out = append(out, map[string]interface{}{
-				"address":  formatAddressV1(address),
+				"address":  formatAddressV1(r.Context(), address),
 				"balance":  bal,
 				"path":     "/storage/flowTokenVault",
 				"token":    formatTokenVaultIdentifier(config.Addr().FlowToken, "FlowToken"),
@@ -632,7 +888,7 @@ func (s *Server) handleFlowAccountNFTCollections(w http.ResponseWriter, r *http.
 	}
 	out := make([]map[string]interface{}, 0, len(collections))
 	for _, c := range collections {
-		out = append(out, toNFTCollectionOutput(c))
+		out = append(out, toNFTCollectionOutput(r.Context(), c))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": total}, nil)
 }
@@ -649,17 +905,17 @@ func (s *Server) handleFlowAccountFTToken(w http.ResponseWriter, r *http.Request
 		writeAPIResponse(w, []interface{}{}, nil, nil)
 		return
 	}
-	out := []interface{}{toVaultOutput(*holding)}
+	out := []interface{}{toVaultOutput(r.Context(), *holding)}
 	writeAPIResponse(w, out, nil, nil)
 }
 
 func (s *Server) handleFlowAccountFTTokenTransfers(w http.ResponseWriter, r *http.Request) {
 	address := normalizeAddr(mux.Vars(r)["address"])
 	tokenAddr, tokenName := parseTokenParam(mux.Vars(r)["token"])
-	limit, offset := parseLimitOffset(r)
+	limit, offset := parseLimitOffsetWithMax(r, maxLimitTransfers)
 	height, err := parseHeightParam(r.URL.Query().Get("height"))
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+		writeAPIError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	transfers, hasMore, err := s.repo.ListTokenTransfersWithContractFiltered(r.Context(), false, address, tokenAddr, tokenName, "", height, limit, offset)
@@ -667,22 +923,16 @@ func (s *Server) handleFlowAccountFTTokenTransfers(w http.ResponseWriter, r *htt
 		writeAPIError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	ftIDs3 := collectTransferTokenIDs(transfers, false)
-	ftMeta3, _ := s.repo.GetFTTokenMetadataByIdentifiers(r.Context(), ftIDs3)
 	out := make([]map[string]interface{}, 0, len(transfers))
 	for _, t := range transfers {
-		id := formatTokenVaultIdentifier(t.TokenContractAddress, t.ContractName)
-		var m *repository.TokenMetadataInfo
-		if meta, ok := ftMeta3[id]; ok {
-			m = &meta
-		}
+		m := t.Meta
 		var usdPrice float64
 		if m != nil && m.MarketSymbol != "" {
 			usdPrice, _ = s.priceCache.GetPriceAt(m.MarketSymbol, t.TokenTransfer.Timestamp)
 		} else if t.ContractName == "FlowToken" {
 			usdPrice, _ = s.priceCache.GetPriceAt("FLOW", t.TokenTransfer.Timestamp)
 		}
-		out = append(out, toFTTransferOutput(t.TokenTransfer, t.ContractName, address, m, usdPrice))
+		out = append(out, toFTTransferOutput(r.Context(), t.TokenTransfer, t.ContractName, address, m, usdPrice))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}, nil)
 }
@@ -697,7 +947,7 @@ func (s *Server) handleFlowAccountScheduledTransactions(w http.ResponseWriter, r
 	}
 	out := make([]map[string]interface{}, 0, len(items))
 	for _, st := range items {
-		out = append(out, toScheduledTransactionOutput(st))
+		out = append(out, toScheduledTransactionOutput(r.Context(), st))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "total": total}, nil)
 }
@@ -712,7 +962,7 @@ func (s *Server) handleFlowScheduledTransactions(w http.ResponseWriter, r *http.
 	}
 	out := make([]map[string]interface{}, 0, len(items))
 	for _, st := range items {
-		out = append(out, toScheduledTransactionOutput(st))
+		out = append(out, toScheduledTransactionOutput(r.Context(), st))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "total": total}, nil)
 }
@@ -729,7 +979,7 @@ func (s *Server) handleFlowScheduledTransactionByID(w http.ResponseWriter, r *ht
 		writeAPIError(w, http.StatusNotFound, "scheduled transaction not found")
 		return
 	}
-	out := toScheduledTransactionOutput(*st)
+	out := toScheduledTransactionOutput(r.Context(), *st)
 
 	// Include handler stats (total executions by status for this owner)
 	if stats, err := s.repo.GetScheduledHandlerStats(r.Context(), st.HandlerOwner); err == nil {
@@ -746,7 +996,7 @@ func (s *Server) handleFlowScheduledTransactionByID(w http.ResponseWriter, r *ht
 	writeAPIResponse(w, out, nil, nil)
 }
 
-func toScheduledTransactionOutput(st models.ScheduledTransaction) map[string]interface{} {
+func toScheduledTransactionOutput(ctx context.Context, st models.ScheduledTransaction) map[string]interface{} {
 	isIdle := st.Status == "EXECUTED" && !st.HasActivity
 	out := map[string]interface{}{
 		"scheduled_id":             st.ScheduledID,
@@ -755,7 +1005,7 @@ func toScheduledTransactionOutput(st models.ScheduledTransaction) map[string]int
 		"expected_at":              st.ExpectedTimestamp.Format(time.RFC3339),
 		"execution_effort":         st.ExecutionEffort,
 		"fees":                     st.Fees,
-		"handler_owner":            formatAddressV1(st.HandlerOwner),
+		"handler_owner":            formatAddressV1(ctx, st.HandlerOwner),
 		"handler_type":             st.HandlerType,
 		"handler_contract":         parseScheduledContractName(st.HandlerType),
 		"handler_contract_address": parseScheduledContractAddress(st.HandlerType),
@@ -807,7 +1057,7 @@ func (s *Server) handleFlowScheduledHandlers(w http.ResponseWriter, r *http.Requ
 	}
 	out := make([]map[string]interface{}, 0, len(items))
 	for _, h := range items {
-		out = append(out, toScheduledHandlerOutput(h))
+		out = append(out, toScheduledHandlerOutput(r.Context(), h))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "total": total}, nil)
 }
@@ -828,7 +1078,7 @@ func (s *Server) handleFlowScheduledHandlerHistory(w http.ResponseWriter, r *htt
 	}
 	out := make([]map[string]interface{}, 0, len(items))
 	for _, st := range items {
-		out = append(out, toScheduledTransactionOutput(st))
+		out = append(out, toScheduledTransactionOutput(r.Context(), st))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{
 		"limit":    limit,
@@ -861,7 +1111,7 @@ func (s *Server) handleFlowScheduledTransactionSearch(w http.ResponseWriter, r *
 	}
 	out := make([]map[string]interface{}, 0, len(results))
 	for _, sr := range results {
-		o := toScheduledTransactionOutput(sr.ScheduledTransaction)
+		o := toScheduledTransactionOutput(r.Context(), sr.ScheduledTransaction)
 		o["matched_event_type"] = sr.MatchedEventType
 		o["matched_event_name"] = sr.MatchedEventName
 		out = append(out, o)
@@ -869,7 +1119,7 @@ func (s *Server) handleFlowScheduledTransactionSearch(w http.ResponseWriter, r *
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "total": total}, nil)
 }
 
-func toScheduledHandlerOutput(h models.ScheduledHandler) map[string]interface{} {
+func toScheduledHandlerOutput(ctx context.Context, h models.ScheduledHandler) map[string]interface{} {
 	// A handler is "recurring" if avg interval < 5 min AND total > 10
 	isRecurring := false
 	if h.AvgIntervalSec != nil && *h.AvgIntervalSec < 300 && h.TotalCount > 10 {
@@ -877,7 +1127,7 @@ func toScheduledHandlerOutput(h models.ScheduledHandler) map[string]interface{}
 	}
 
 	out := map[string]interface{}{
-		"handler_owner":            formatAddressV1(h.HandlerOwner),
+		"handler_owner":            formatAddressV1(ctx, h.HandlerOwner),
 		"handler_type":             h.HandlerType,
 		"handler_contract":         parseScheduledContractName(h.HandlerType),
 		"handler_contract_address": parseScheduledContractAddress(h.HandlerType),
@@ -921,16 +1171,30 @@ func (s *Server) handleFlowAccountNFTByCollection(w http.ResponseWriter, r *http
 	address := normalizeAddr(mux.Vars(r)["address"])
 	collectionAddr, collectionName := parseTokenParam(mux.Vars(r)["nft_type"])
 	limit, offset := parseLimitOffset(r)
-	items, err := s.repo.ListNFTOwnershipByOwnerAndCollection(r.Context(), address, collectionAddr, collectionName, limit, offset)
+	var cursor *repository.NFTOwnershipCursor
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		parsed, err := repository.ParseNFTOwnershipCursor(c)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		cursor, offset = parsed, 0
+	}
+	items, err := s.repo.ListNFTOwnershipByOwnerAndCollection(r.Context(), address, collectionAddr, collectionName, limit, offset, cursor)
 	if err != nil {
 		writeAPIError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	out := make([]map[string]interface{}, 0, len(items))
 	for _, item := range items {
-		out = append(out, toCombinedNFTDetails(item))
+		out = append(out, toCombinedNFTDetails(r.Context(), item))
 	}
-	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out)}, nil)
+	meta := map[string]interface{}{"limit": limit, "offset": offset, "count": len(out)}
+	if len(items) == limit {
+		last := items[len(items)-1]
+		meta["next_cursor"] = repository.NFTOwnershipCursor{ContractName: last.ContractName, NFTID: last.NFTID}.String()
+	}
+	writeAPIResponse(w, out, meta, nil)
 }
 
 func (s *Server) executeCadenceScript(ctx context.Context, script string, args []cadence.Value) ([]byte, error) {
@@ -983,7 +1247,7 @@ func (s *Server) handleFlowAccountBalanceHistory(w http.ResponseWriter, r *http.
 
 	token := strings.TrimSpace(r.URL.Query().Get("token"))
 	if token == "" {
-		token = "A.1654653399040a61.FlowToken"
+		token = "A." + config.Addr().FlowToken + ".FlowToken"
 	}
 	tokenAddr, tokenName := parseTokenParam(token)
 
@@ -69,6 +69,49 @@ func (s *Server) handleAnalyticsDailyModule(w http.ResponseWriter, r *http.Reque
 	writeAPIResponse(w, stats, nil, nil)
 }
 
+// handleAccountGrowth returns new and cumulative accounts per day for the last
+// ?days= days (default 365, max 3650).
+func (s *Server) handleAccountGrowth(w http.ResponseWriter, r *http.Request) {
+	days := 365
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 3650 {
+			writeAPIError(w, http.StatusBadRequest, "days must be between 1 and 3650")
+			return
+		}
+		days = n
+	}
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -(days - 1))
+	rows, err := s.repo.GetAccountGrowth(r.Context(), from, to)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeAPIResponse(w, rows, map[string]interface{}{"count": len(rows), "days": days}, nil)
+}
+
+// handleDailyMetric returns a single whitelisted metric as a {date, value}
+// series over ?from=&to= (see parseAnalyticsDateRange).
+func (s *Server) handleDailyMetric(w http.ResponseWriter, r *http.Request) {
+	metric := mux.Vars(r)["metric"]
+	if !repository.IsDailyMetric(metric) {
+		writeAPIError(w, http.StatusBadRequest, "unsupported metric; use one of: "+strings.Join(repository.DailyMetricNames(), ", "))
+		return
+	}
+	if s.repo == nil {
+		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
+		return
+	}
+	from, to := parseAnalyticsDateRange(r)
+	points, err := s.repo.GetAnalyticsDailyMetrics(r.Context(), metric, from, to)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeAPIResponse(w, points, map[string]interface{}{"metric": metric, "count": len(points)}, nil)
+}
+
 // parseAnalyticsDateRange extracts ?from=YYYY-MM-DD&to=YYYY-MM-DD, defaulting to last 90 days.
 func parseAnalyticsDateRange(r *http.Request) (time.Time, time.Time) {
 	now := time.Now().UTC()
@@ -156,6 +199,112 @@ func (s *Server) handleTopContracts(w http.ResponseWriter, r *http.Request) {
 	writeAPIResponse(w, results, map[string]interface{}{"count": len(results)}, nil)
 }
 
+// handleTopEventTypes ranks event types by occurrence. ?from/?to bound the
+// window by block height (both or neither; default last 30 days) and
+// ?contract= scopes it to an address, a contract identifier or "flow".
+func (s *Server) handleTopEventTypes(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var from, to uint64
+	if q.Get("from") != "" || q.Get("to") != "" {
+		var err error
+		if from, err = parseBlockHeight(q.Get("from")); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if to, err = parseBlockHeight(q.Get("to")); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if to < from {
+			writeAPIError(w, http.StatusBadRequest, "to must be >= from")
+			return
+		}
+	}
+	limit := 20
+	if v := q.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+
+	top, err := s.repo.GetTopEventTypes(r.Context(), from, to, q.Get("contract"), limit)
+	if err != nil {
+		writeRepoError(w, err, "")
+		return
+	}
+	meta := map[string]interface{}{
+		"count": len(top.Types),
+		"total": top.Total,
+	}
+	if !top.DateFrom.IsZero() {
+		meta["date_from"] = top.DateFrom.Format("2006-01-02")
+		meta["date_to"] = top.DateTo.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	writeAPIResponse(w, top.Types, meta, nil)
+}
+
+const (
+	defaultTxDistributionBlocks = 10000
+	maxTxDistributionBlocks     = 1000000
+)
+
+// handleTxPerBlockDistribution returns a histogram of transactions per block.
+// ?from/?to bound the height range (both or neither; default the last 10,000
+// indexed blocks, at most 1,000,000) and ?buckets= overrides the bucket lower
+// bounds, e.g. "0,1,11,51".
+func (s *Server) handleTxPerBlockDistribution(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	bounds, err := repository.ParseTxDistributionBounds(q.Get("buckets"))
+	if err != nil {
+		writeRepoError(w, err, "")
+		return
+	}
+	var from, to uint64
+	if q.Get("from") != "" || q.Get("to") != "" {
+		if from, err = parseBlockHeight(q.Get("from")); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if to, err = parseBlockHeight(q.Get("to")); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if to < from {
+			writeAPIError(w, http.StatusBadRequest, "to must be >= from")
+			return
+		}
+		if to-from >= maxTxDistributionBlocks {
+			writeAPIError(w, http.StatusBadRequest, "range too large: at most 1000000 blocks")
+			return
+		}
+	} else {
+		tip, err := s.repo.GetIndexedTipHeight(r.Context())
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		to = tip
+		if tip >= defaultTxDistributionBlocks {
+			from = tip - defaultTxDistributionBlocks + 1
+		}
+	}
+
+	buckets, err := s.repo.GetTxPerBlockDistribution(r.Context(), from, to, bounds)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var blocks int64
+	for _, b := range buckets {
+		blocks += b.Blocks
+	}
+	writeAPIResponse(w, buckets, map[string]interface{}{
+		"from_height": from,
+		"to_height":   to,
+		"blocks":      blocks,
+	}, nil)
+}
+
 func (s *Server) handleTokenVolume(w http.ResponseWriter, r *http.Request) {
 	hours := 24
 	if v := r.URL.Query().Get("hours"); v != "" {
@@ -1,29 +1,57 @@
 package api
 
 import (
+	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
+	"time"
 
+	"flowscan-clone/internal/cadenceutil"
 	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
 
 	"github.com/gorilla/mux"
 )
 
 func (s *Server) handleFlowListBlocks(w http.ResponseWriter, r *http.Request) {
-	limit, offset := parseLimitOffset(r)
+	limit, offset := parseLimitOffsetWithMax(r, maxLimitLight)
+	// The stored event_count can lag for tip blocks; ?recompute_counts=true
+	// replaces it with a live count from raw.events (one grouped query per page).
+	recompute := strings.ToLower(r.URL.Query().Get("recompute_counts")) == "true"
 	if heightParam := r.URL.Query().Get("height"); heightParam != "" {
-		height, err := strconv.ParseUint(heightParam, 10, 64)
+		if rejectFilteredExactCount(w, r, true) {
+			return
+		}
+		height, err := parseBlockHeight(heightParam)
 		if err != nil {
-			writeAPIError(w, http.StatusBadRequest, "invalid height")
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if s.beyondIndexedHeight(r.Context(), height) {
+			writeAPIResponse(w, []interface{}{}, map[string]interface{}{"limit": limit, "offset": offset}, nil)
 			return
 		}
 		block, err := s.repo.GetBlockByHeight(r.Context(), height)
-		if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
 			writeAPIResponse(w, []interface{}{}, map[string]interface{}{"limit": limit, "offset": offset}, nil)
 			return
 		}
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if recompute {
+			blocks := []models.Block{*block}
+			if err := s.applyLiveEventCounts(r, blocks); err != nil {
+				writeAPIError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			block = &blocks[0]
+		}
 		writeAPIResponse(w, []interface{}{toFlowBlockOutput(*block)}, map[string]interface{}{"limit": 1, "offset": 0}, nil)
 		return
 	}
@@ -33,33 +61,250 @@ func (s *Server) handleFlowListBlocks(w http.ResponseWriter, r *http.Request) {
 		writeAPIError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	if recompute {
+		if err := s.applyLiveEventCounts(r, blocks); err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
 	out := make([]map[string]interface{}, 0, len(blocks))
 	for _, b := range blocks {
 		out = append(out, toFlowBlockOutput(b))
 	}
-	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out)}, nil)
+	meta := map[string]interface{}{"limit": limit, "offset": offset, "count": len(out)}
+	s.addRepoListTotals(r, meta, "blocks")
+	writeAPIResponse(w, out, meta, nil)
 }
 
-func (s *Server) handleFlowGetBlock(w http.ResponseWriter, r *http.Request) {
-	heightStr := mux.Vars(r)["height"]
-	height, err := strconv.ParseUint(heightStr, 10, 64)
+// applyLiveEventCounts overwrites each block's EventCount with its live count.
+func (s *Server) applyLiveEventCounts(r *http.Request, blocks []models.Block) error {
+	heights := make([]uint64, len(blocks))
+	for i, b := range blocks {
+		heights[i] = b.Height
+	}
+	counts, err := s.repo.GetEventCountsForHeights(r.Context(), heights)
+	if err != nil {
+		return err
+	}
+	for i := range blocks {
+		if n, ok := counts[blocks[i].Height]; ok {
+			blocks[i].EventCount = n
+		}
+	}
+	return nil
+}
+
+// blockHeightVar parses the {height} path variable, writing a 400 for a
+// malformed or overflowing value and a 404 for a height past the indexed tip.
+func (s *Server) blockHeightVar(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	height, err := parseBlockHeight(mux.Vars(r)["height"])
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return 0, false
+	}
+	if s.beyondIndexedHeight(r.Context(), height) {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("block %d not indexed yet", height))
+		return 0, false
+	}
+	return height, true
+}
+
+// beyondIndexedHeight reports whether height is above the forward ingester's
+// checkpoint, so far-future heights are rejected without querying raw tables.
+// The checkpoint is cached and re-read at most once per second, only when a
+// request asks for a height past the cached value. The read happens outside
+// the cache lock; concurrent requests share it through singleflight.
+func (s *Server) beyondIndexedHeight(ctx context.Context, height uint64) bool {
+	c := &s.indexedHeightCache
+	c.mu.Lock()
+	cached, updatedAt := c.height, c.updatedAt
+	c.mu.Unlock()
+	if height > cached && s.repo != nil && time.Since(updatedAt) > time.Second {
+		v, err, _ := c.flight.Do("main_ingester", func() (interface{}, error) {
+			h, err := s.repo.GetLastIndexedHeight(ctx, "main_ingester")
+			if err != nil {
+				return nil, err
+			}
+			c.mu.Lock()
+			c.height = h
+			c.updatedAt = time.Now()
+			c.mu.Unlock()
+			return h, nil
+		})
+		if err == nil {
+			cached, updatedAt = v.(uint64), time.Now()
+		}
+	}
+	// Without a checkpoint there is nothing to compare against.
+	return !updatedAt.IsZero() && cached > 0 && height > cached
+}
+
+func (s *Server) handleFlowGetBlock(w http.ResponseWriter, r *http.Request) {
+	height, ok := s.blockHeightVar(w, r)
+	if !ok {
 		return
 	}
 	block, err := s.repo.GetBlockByHeight(r.Context(), height)
 	if err != nil {
-		writeAPIError(w, http.StatusNotFound, "block not found")
+		writeRepoError(w, err, "block not found")
 		return
 	}
 	writeAPIResponse(w, []interface{}{toFlowBlockOutput(*block)}, nil, nil)
 }
 
-func (s *Server) handleFlowBlockTransactions(w http.ResponseWriter, r *http.Request) {
-	heightStr := mux.Vars(r)["height"]
-	height, err := strconv.ParseUint(heightStr, 10, 64)
+// handleFlowBlockAtTimestamp resolves ?timestamp= (RFC3339) to a block.
+// match=nearest (default) picks the closest block, match=after the first block
+// at or after the timestamp. Timestamps outside the indexed range resolve to
+// the first or latest block and are flagged in position.
+func (s *Server) handleFlowBlockAtTimestamp(w http.ResponseWriter, r *http.Request) {
+	tsStr := strings.TrimSpace(r.URL.Query().Get("timestamp"))
+	if tsStr == "" {
+		writeAPIError(w, http.StatusBadRequest, "timestamp parameter required")
+		return
+	}
+	ts, err := time.Parse(time.RFC3339Nano, tsStr)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid timestamp format, use RFC3339")
+		return
+	}
+	match := strings.ToLower(r.URL.Query().Get("match"))
+	if match == "" {
+		match = "nearest"
+	}
+	if match != "nearest" && match != "after" {
+		writeAPIError(w, http.StatusBadRequest, "match must be nearest or after")
+		return
+	}
+
+	res, err := s.repo.GetBlockByTimestamp(r.Context(), ts)
+	if err != nil {
+		writeRepoError(w, err, "no indexed blocks")
+		return
+	}
+	block := res.Nearest
+	if match == "after" {
+		if res.After == nil {
+			writeAPIError(w, http.StatusNotFound, "no indexed block at or after timestamp")
+			return
+		}
+		block = *res.After
+	}
+	writeAPIResponse(w, []interface{}{map[string]interface{}{
+		"height":    block.Height,
+		"id":        block.ID,
+		"timestamp": formatTime(block.Timestamp),
+		"match":     match,
+		"position":  res.Position,
+		"offset_ms": block.Timestamp.Sub(ts).Milliseconds(),
+	}}, map[string]interface{}{"requested_timestamp": formatTime(ts)}, nil)
+}
+
+// handleFlowGetCollection returns a collection (stored with STORE_COLLECTIONS)
+// and its transactions; partial is set when some are not indexed yet.
+func (s *Server) handleFlowGetCollection(w http.ResponseWriter, r *http.Request) {
+	id := strings.ToLower(strings.TrimPrefix(mux.Vars(r)["id"], "0x"))
+	if _, err := hex.DecodeString(id); err != nil || len(id) != 64 {
+		writeAPIError(w, http.StatusBadRequest, "invalid collection id")
+		return
+	}
+	coll, err := s.repo.GetCollectionByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "collection not found")
+		return
+	}
+	txs := make([]map[string]interface{}, 0, len(coll.Transactions))
+	for _, tx := range coll.Transactions {
+		out := map[string]interface{}{"id": tx.ID, "indexed": tx.Indexed}
+		if tx.Indexed {
+			out["block_height"] = tx.BlockHeight
+			out["transaction_index"] = tx.TransactionIndex
+			out["timestamp"] = formatTime(tx.Timestamp)
+		}
+		txs = append(txs, out)
+	}
+	writeAPIResponse(w, []interface{}{map[string]interface{}{
+		"id":                coll.ID,
+		"block_height":      coll.BlockHeight,
+		"transaction_count": len(coll.Transactions),
+		"transactions":      txs,
+		"partial":           coll.Partial,
+	}}, nil, nil)
+}
+
+// blockFullMaxTxs bounds the transactions in one /full response. Busier blocks
+// are paged with ?offset; meta.has_more tells the client to fetch the rest.
+const blockFullMaxTxs = 100
+
+// handleFlowGetBlockFull returns a block with its collections and its
+// transactions, each with its events, in one response for the block page.
+func (s *Server) handleFlowGetBlockFull(w http.ResponseWriter, r *http.Request) {
+	height, ok := s.blockHeightVar(w, r)
+	if !ok {
+		return
+	}
+	limit, offset := parseLimitOffsetWithMax(r, blockFullMaxTxs)
+	if r.URL.Query().Get("limit") == "" {
+		limit = blockFullMaxTxs
+	}
+	full, err := s.repo.GetBlockFull(r.Context(), height, limit, offset)
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+		writeRepoError(w, err, "block not found")
+		return
+	}
+	txIDs := collectTxIDs(full.Transactions)
+	contracts, _ := s.repo.GetTxContractsByTransactionIDs(r.Context(), txIDs)
+	tags, _ := s.repo.GetTxTagsByTransactionIDs(r.Context(), txIDs)
+	feesByTx, _ := s.repo.GetTransactionFeesByIDs(r.Context(), txIDs)
+
+	writeAPIResponse(w, []interface{}{toFlowBlockFullOutput(r.Context(), full, contracts, tags, feesByTx)}, map[string]interface{}{
+		"limit":    limit,
+		"offset":   offset,
+		"count":    len(full.Transactions),
+		"tx_total": full.TxTotal,
+		"has_more": offset+len(full.Transactions) < full.TxTotal,
+	}, nil)
+}
+
+// toFlowBlockFullOutput nests a block's collections and transactions under the
+// block. Each transaction carries the id of the collection it came in; the
+// system transaction belongs to none.
+func toFlowBlockFullOutput(ctx context.Context, full *repository.BlockFull, contracts, tags map[string][]string, fees map[string]float64) map[string]interface{} {
+	out := toFlowBlockOutput(full.Block)
+	out["collection_count"] = full.Block.CollectionCount
+
+	collectionOf := make(map[string]string)
+	collections := make([]map[string]interface{}, 0, len(full.Collections))
+	for _, c := range full.Collections {
+		for _, id := range c.TransactionIDs {
+			collectionOf[id] = c.ID
+		}
+		ids := c.TransactionIDs
+		if ids == nil {
+			ids = []string{}
+		}
+		collections = append(collections, map[string]interface{}{
+			"id":                c.ID,
+			"transaction_count": len(ids),
+			"transaction_ids":   ids,
+		})
+	}
+	out["collections"] = collections
+
+	txs := make([]map[string]interface{}, 0, len(full.Transactions))
+	for _, t := range full.Transactions {
+		tx := toFlowTransactionOutput(ctx, t, t.Events, contracts[t.ID], tags[t.ID], fees[t.ID])
+		if id, ok := collectionOf[t.ID]; ok {
+			tx["collection_id"] = id
+		}
+		txs = append(txs, tx)
+	}
+	out["transactions"] = txs
+	return out
+}
+
+func (s *Server) handleFlowBlockTransactions(w http.ResponseWriter, r *http.Request) {
+	height, ok := s.blockHeightVar(w, r)
+	if !ok {
 		return
 	}
 	includeEvents := strings.ToLower(r.URL.Query().Get("include_events")) == "true"
@@ -80,18 +325,19 @@ func (s *Server) handleFlowBlockTransactions(w http.ResponseWriter, r *http.Requ
 	}
 	out := make([]map[string]interface{}, 0, len(txs))
 	for _, t := range txs {
-		out = append(out, toFlowTransactionOutput(t, eventsByTx[t.ID], contracts[t.ID], tags[t.ID], feesByTx[t.ID]))
+		out = append(out, toFlowTransactionOutput(r.Context(), t, eventsByTx[t.ID], contracts[t.ID], tags[t.ID], feesByTx[t.ID]))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"count": len(out)}, nil)
 }
 
+// handleFlowBlockServiceEvents lists a block's protocol (flow.*) events.
+// ?flatten=true decodes JSON-CDC payloads into plain key/value fields.
 func (s *Server) handleFlowBlockServiceEvents(w http.ResponseWriter, r *http.Request) {
-	heightStr := mux.Vars(r)["height"]
-	height, err := strconv.ParseUint(heightStr, 10, 64)
-	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+	height, ok := s.blockHeightVar(w, r)
+	if !ok {
 		return
 	}
+	flatten := r.URL.Query().Get("flatten") == "true"
 	events, err := s.repo.GetEventsByBlockHeight(r.Context(), height)
 	if err != nil {
 		writeAPIError(w, http.StatusInternalServerError, err.Error())
@@ -109,14 +355,26 @@ func (s *Server) handleFlowBlockServiceEvents(w http.ResponseWriter, r *http.Req
 		if contractName != "flow" {
 			continue
 		}
-		var fields interface{}
-		_ = json.Unmarshal(e.Payload, &fields)
 		out = append(out, map[string]interface{}{
 			"block_height": e.BlockHeight,
 			"name":         e.EventName,
 			"timestamp":    formatTime(e.Timestamp),
-			"fields":       fields,
+			"fields":       serviceEventFields(e.Payload, flatten),
 		})
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"count": len(out)}, nil)
 }
+
+// serviceEventFields decodes a stored event payload. With flatten, payloads
+// still in JSON-CDC form are reduced to plain key/value pairs; payloads that
+// were flattened at ingest are returned as stored either way.
+func serviceEventFields(payload []byte, flatten bool) interface{} {
+	if flatten {
+		if fields := cadenceutil.ParseEventPayload(payload); fields != nil {
+			return fields
+		}
+	}
+	var fields interface{}
+	_ = json.Unmarshal(payload, &fields)
+	return fields
+}
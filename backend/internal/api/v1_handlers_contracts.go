@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -127,7 +128,7 @@ func (s *Server) handleFlowListContracts(w http.ResponseWriter, r *http.Request)
 	// dependent_count is now pre-computed in smart_contracts, no need to batch-fetch
 	out := make([]map[string]interface{}, 0, len(contracts))
 	for _, c := range contracts {
-		out = append(out, toContractOutput(c))
+		out = append(out, toContractOutput(r.Context(), c))
 	}
 	if total, err := s.repo.GetTotalContracts(r.Context()); err == nil && total > 0 {
 		meta["count"] = total
@@ -218,7 +219,7 @@ func (s *Server) handleFlowGetContract(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 		}
-		out = append(out, toContractOutput(c))
+		out = append(out, toContractOutput(r.Context(), c))
 	}
 	meta := map[string]interface{}{"limit": limit, "offset": offset, "count": len(out)}
 	if validFrom != nil {
@@ -268,7 +269,7 @@ func (s *Server) handleFlowGetContractVersion(w http.ResponseWriter, r *http.Req
 	}
 
 	out := map[string]interface{}{
-		"address":        formatAddressV1(v.Address),
+		"address":        formatAddressV1(r.Context(), v.Address),
 		"name":           v.Name,
 		"version":        v.Version,
 		"code":           v.Code,
@@ -306,7 +307,7 @@ func (s *Server) handleContractTransactions(w http.ResponseWriter, r *http.Reque
 
 	out := make([]map[string]interface{}, 0, len(txs))
 	for _, t := range txs {
-		out = append(out, toFlowTransactionOutput(t, nil, contracts[t.ID], tags[t.ID], feesByTx[t.ID]))
+		out = append(out, toFlowTransactionOutput(r.Context(), t, nil, contracts[t.ID], tags[t.ID], feesByTx[t.ID]))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out)}, nil)
 }
@@ -374,6 +375,103 @@ func (s *Server) handleContractEventTypes(w http.ResponseWriter, r *http.Request
 	writeAPIResponse(w, data, map[string]interface{}{"count": len(data)}, nil)
 }
 
+// maxContractEventSpan caps the height window scanned by the contract event
+// feed so each request touches a bounded number of raw.events partitions.
+const maxContractEventSpan = 1000000
+
+// handleContractEventFeed lists events emitted by a contract, newest first.
+// GET /flow/contract/{identifier}/events/feed?name=&from_height=&to_height=&cursor=
+func (s *Server) handleContractEventFeed(w http.ResponseWriter, r *http.Request) {
+	address, name, _ := splitContractIdentifier(mux.Vars(r)["identifier"])
+	if address == "" || name == "" {
+		writeAPIError(w, http.StatusBadRequest, "invalid contract identifier")
+		return
+	}
+	q := r.URL.Query()
+	limit, _ := parseLimitOffsetWithMax(r, maxLimitEvents)
+
+	var cursor *repository.EventCursor
+	if c := q.Get("cursor"); c != "" {
+		parsed, err := repository.ParseEventCursor(c)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		cursor = parsed
+	}
+
+	to, err := parseHeightParam(q.Get("to_height"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid to_height")
+		return
+	}
+	from, err := parseHeightParam(q.Get("from_height"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid from_height")
+		return
+	}
+	toHeight := uint64(0)
+	if to != nil {
+		toHeight = *to
+	} else {
+		tip, err := s.repo.GetIndexedTipHeight(r.Context())
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, "failed to resolve tip height")
+			return
+		}
+		toHeight = tip
+	}
+	fromHeight := uint64(0)
+	if toHeight >= maxContractEventSpan {
+		fromHeight = toHeight - maxContractEventSpan + 1
+	}
+	if from != nil {
+		if *from > toHeight {
+			writeAPIError(w, http.StatusBadRequest, "from_height must be <= to_height")
+			return
+		}
+		if toHeight-*from >= maxContractEventSpan {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("range too large (max %d blocks)", maxContractEventSpan))
+			return
+		}
+		fromHeight = *from
+	}
+	// The cursor already bounds the scan from above; narrowing to_height keeps
+	// later pages pruned to the partitions they can still reach.
+	if cursor != nil && cursor.BlockHeight < toHeight {
+		toHeight = cursor.BlockHeight
+	}
+	if toHeight < fromHeight {
+		writeAPIResponse(w, []map[string]interface{}{}, map[string]interface{}{"limit": limit, "count": 0}, nil)
+		return
+	}
+
+	events, err := s.repo.ListEventsByContract(r.Context(), address, name, q.Get("name"), fromHeight, toHeight, limit, cursor)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to query contract events")
+		return
+	}
+	data := make([]map[string]interface{}, 0, len(events))
+	for _, e := range events {
+		data = append(data, toFlowEventOutput(r.Context(), e))
+	}
+	meta := map[string]interface{}{
+		"limit":       limit,
+		"count":       len(data),
+		"from_height": fromHeight,
+		"to_height":   toHeight,
+	}
+	if len(events) == limit {
+		last := events[len(events)-1]
+		meta["next_cursor"] = repository.EventCursor{
+			BlockHeight: last.BlockHeight,
+			TxIndex:     last.TransactionIndex,
+			EventIndex:  last.EventIndex,
+		}.String()
+	}
+	writeAPIResponse(w, data, meta, nil)
+}
+
 func (s *Server) handleSearchEvents(w http.ResponseWriter, r *http.Request) {
 	name := r.URL.Query().Get("name")
 	if name == "" {
@@ -394,7 +492,7 @@ func (s *Server) handleSearchEvents(w http.ResponseWriter, r *http.Request) {
 	data := make([]map[string]interface{}, len(events))
 	for i, e := range events {
 		data[i] = map[string]interface{}{
-			"type": e.Type, "contract_address": formatAddressV1(e.ContractAddress),
+			"type": e.Type, "contract_address": formatAddressV1(r.Context(), e.ContractAddress),
 			"contract_name": e.ContractName, "event_name": e.EventName, "count": e.Count,
 		}
 	}
@@ -433,6 +531,42 @@ func (s *Server) handleContractVersionList(w http.ResponseWriter, r *http.Reques
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out)}, nil)
 }
 
+// handleContractTimeline lists every deploy, update and removal of a contract,
+// oldest first, with the transaction that caused it.
+// GET /flow/contract/{identifier}/timeline
+func (s *Server) handleContractTimeline(w http.ResponseWriter, r *http.Request) {
+	if s.repo == nil {
+		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
+		return
+	}
+	address, name, _ := splitContractIdentifier(mux.Vars(r)["identifier"])
+	if address == "" || name == "" {
+		writeAPIError(w, http.StatusBadRequest, "invalid contract identifier")
+		return
+	}
+
+	timeline, err := s.repo.GetContractTimeline(r.Context(), address, name)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	out := make([]map[string]interface{}, 0, len(timeline))
+	for _, e := range timeline {
+		entry := map[string]interface{}{
+			"kind":           e.Kind,
+			"block_height":   e.BlockHeight,
+			"transaction_id": e.TransactionID,
+			"timestamp":      formatTime(e.Timestamp),
+		}
+		if e.Version > 0 {
+			entry["version"] = e.Version
+		}
+		out = append(out, entry)
+	}
+	writeAPIResponse(w, out, map[string]interface{}{"count": len(out)}, nil)
+}
+
 func (s *Server) handleContractScripts(w http.ResponseWriter, r *http.Request) {
 	if s.repo == nil {
 		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
@@ -468,21 +602,53 @@ func (s *Server) handleContractScripts(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleGetScriptText(w http.ResponseWriter, r *http.Request) {
+	hash, ok := normalizeScriptHash(mux.Vars(r)["hash"])
+	if !ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid script hash")
+		return
+	}
 	if s.repo == nil {
 		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
 		return
 	}
-	hash := mux.Vars(r)["hash"]
-	if hash == "" {
-		writeAPIError(w, http.StatusBadRequest, "missing script hash")
+	script, err := s.repo.GetScriptByHash(r.Context(), hash)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if script == nil {
+		writeAPIError(w, http.StatusNotFound, "script not found")
 		return
 	}
-	text, err := s.repo.GetScriptTextByHash(r.Context(), hash)
+	writeAPIResponse(w, []repository.ScriptUsage{*script}, nil, nil)
+}
+
+func (s *Server) handleListPopularScripts(w http.ResponseWriter, r *http.Request) {
+	if s.repo == nil {
+		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
+		return
+	}
+	limit, offset := parseLimitOffset(r)
+	scripts, err := s.repo.ListPopularScripts(r.Context(), limit, offset)
 	if err != nil {
 		writeAPIError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	writeAPIResponse(w, []map[string]interface{}{{"script_hash": hash, "script_text": text}}, nil, nil)
+	writeAPIResponse(w, scripts, map[string]interface{}{"limit": limit, "offset": offset, "count": len(scripts)}, nil)
+}
+
+// normalizeScriptHash validates a raw.scripts hash (hex SHA-256) and lower-cases it.
+func normalizeScriptHash(hash string) (string, bool) {
+	hash = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(hash), "0x"))
+	if len(hash) != 64 {
+		return "", false
+	}
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return "", false
+		}
+	}
+	return hash, true
 }
 
 // parseContractImports extracts import statements from Cadence source code.
@@ -550,7 +716,7 @@ func (s *Server) handleContractDependencies(w http.ResponseWriter, r *http.Reque
 	}
 
 	rootID := "A." + address + "." + name
-	nodeSet := map[string]graphNode{rootID: {Identifier: rootID, Address: formatAddressV1(address), Name: name}}
+	nodeSet := map[string]graphNode{rootID: {Identifier: rootID, Address: formatAddressV1(r.Context(), address), Name: name}}
 	var edgeList []graphEdge
 	edgeSet := make(map[string]bool)
 
@@ -592,7 +758,7 @@ func (s *Server) handleContractDependencies(w http.ResponseWriter, r *http.Reque
 		for _, imp := range parseContractImports(contracts[0].Code) {
 			impID := "A." + imp.Address + "." + imp.Name
 			if _, exists := nodeSet[impID]; !exists {
-				nodeSet[impID] = graphNode{Identifier: impID, Address: formatAddressV1(imp.Address), Name: imp.Name}
+				nodeSet[impID] = graphNode{Identifier: impID, Address: formatAddressV1(r.Context(), imp.Address), Name: imp.Name}
 				queue = append(queue, struct {
 					addr, name string
 					depth      int
@@ -642,7 +808,7 @@ func (s *Server) handleContractDependencies(w http.ResponseWriter, r *http.Reque
 	for _, d := range depRefs {
 		dependents = append(dependents, map[string]interface{}{
 			"identifier": "A." + d.Address + "." + d.Name,
-			"address":    formatAddressV1(d.Address),
+			"address":    formatAddressV1(r.Context(), d.Address),
 			"name":       d.Name,
 		})
 	}
@@ -671,3 +837,47 @@ func (s *Server) handleContractDependencies(w http.ResponseWriter, r *http.Reque
 	}
 	writeAPIResponse(w, []interface{}{result}, nil, nil)
 }
+
+const (
+	defaultContractActivityDays = 30
+	maxContractActivityDays     = 365
+)
+
+// handleFlowTopContractsByActivity ranks contracts by how many transactions
+// imported them over the last ?window= UTC days (default 30, max 365),
+// including today.
+// GET /flow/stats/contracts/top?window=&limit=
+func (s *Server) handleFlowTopContractsByActivity(w http.ResponseWriter, r *http.Request) {
+	days := defaultContractActivityDays
+	if v := strings.TrimSuffix(strings.TrimSpace(r.URL.Query().Get("window")), "d"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxContractActivityDays {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("window must be between 1 and %d days", maxContractActivityDays))
+			return
+		}
+		days = n
+	}
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -days)
+	top, err := s.repo.GetTopContractsByActivity(r.Context(), from, to, limit)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if top == nil {
+		top = []repository.ContractActivity{}
+	}
+	writeAPIResponse(w, top, map[string]interface{}{
+		"count":     len(top),
+		"window":    days,
+		"date_from": from.Format("2006-01-02"),
+		"date_to":   to.AddDate(0, 0, -1).Format("2006-01-02"),
+	}, nil)
+}
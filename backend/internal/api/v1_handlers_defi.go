@@ -39,7 +39,7 @@ func (s *Server) handleDefiListPairs(w http.ResponseWriter, r *http.Request) {
 
 // handleDefiListEvents handles GET /defi/v1/events
 func (s *Server) handleDefiListEvents(w http.ResponseWriter, r *http.Request) {
-	limit, offset := parseLimitOffset(r)
+	limit, offset := parseLimitOffsetWithMax(r, maxLimitEvents)
 	pairID := strings.TrimSpace(r.URL.Query().Get("pair_id"))
 	eventType := strings.TrimSpace(r.URL.Query().Get("event_type"))
 
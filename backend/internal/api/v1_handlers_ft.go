@@ -12,37 +12,40 @@ import (
 )
 
 func (s *Server) handleFlowFTTransfers(w http.ResponseWriter, r *http.Request) {
-	limit, offset := parseLimitOffset(r)
+	limit, offset := parseLimitOffsetWithMax(r, maxLimitTransfers)
 	height, err := parseHeightParam(r.URL.Query().Get("height"))
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+		writeAPIError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	addrFilter := normalizeAddr(r.URL.Query().Get("address"))
 	tokenAddr, tokenName := parseTokenParam(r.URL.Query().Get("token"))
-	transfers, hasMore, err := s.repo.ListTokenTransfersWithContractFiltered(r.Context(), false, addrFilter, tokenAddr, tokenName, r.URL.Query().Get("transaction_hash"), height, limit, offset)
+	txHash := r.URL.Query().Get("transaction_hash")
+	filtered := height != nil || addrFilter != "" || tokenAddr != "" || tokenName != "" || txHash != ""
+	if rejectFilteredExactCount(w, r, filtered) {
+		return
+	}
+	transfers, hasMore, err := s.repo.ListTokenTransfersWithContractFiltered(r.Context(), false, addrFilter, tokenAddr, tokenName, txHash, height, limit, offset)
 	if err != nil {
 		writeAPIError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	ftIDs := collectTransferTokenIDs(transfers, false)
-	ftMeta, _ := s.repo.GetFTTokenMetadataByIdentifiers(r.Context(), ftIDs)
 	out := make([]map[string]interface{}, 0, len(transfers))
 	for _, t := range transfers {
-		id := formatTokenVaultIdentifier(t.TokenContractAddress, t.ContractName)
-		var m *repository.TokenMetadataInfo
-		if meta, ok := ftMeta[id]; ok {
-			m = &meta
-		}
+		m := t.Meta
 		var usdPrice float64
 		if m != nil && m.MarketSymbol != "" {
 			usdPrice, _ = s.priceCache.GetPriceAt(m.MarketSymbol, t.TokenTransfer.Timestamp)
 		} else if t.ContractName == "FlowToken" {
 			usdPrice, _ = s.priceCache.GetPriceAt("FLOW", t.TokenTransfer.Timestamp)
 		}
-		out = append(out, toFTTransferOutput(t.TokenTransfer, t.ContractName, addrFilter, m, usdPrice))
+		out = append(out, toFTTransferOutput(r.Context(), t.TokenTransfer, t.ContractName, addrFilter, m, usdPrice))
+	}
+	meta := map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}
+	if !filtered {
+		s.addRepoListTotals(r, meta, "ft_transfers")
 	}
-	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}, nil)
+	writeAPIResponse(w, out, meta, nil)
 }
 
 // handleFlowAllTransfers returns FT and NFT transfers merged in chronological order.
@@ -53,10 +56,10 @@ func (s *Server) handleFlowAllTransfers(w http.ResponseWriter, r *http.Request)
 	if address == "" {
 		address = normalizeAddr(r.URL.Query().Get("address"))
 	}
-	limit, offset := parseLimitOffset(r)
+	limit, offset := parseLimitOffsetWithMax(r, maxLimitTransfers)
 	height, err := parseHeightParam(r.URL.Query().Get("height"))
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+		writeAPIError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	txHash := r.URL.Query().Get("transaction_hash")
@@ -89,7 +92,7 @@ func (s *Server) handleFlowAllTransfers(w http.ResponseWriter, r *http.Request)
 			if meta, ok := nftMeta[id]; ok {
 				m = &meta
 			}
-			item := toNFTTransferOutput(t.TokenTransfer, t.ContractName, address, m)
+			item := toNFTTransferOutput(r.Context(), t.TokenTransfer, t.ContractName, address, m)
 			item["type"] = "nft"
 			out = append(out, item)
 		} else {
@@ -104,7 +107,7 @@ func (s *Server) handleFlowAllTransfers(w http.ResponseWriter, r *http.Request)
 			} else if t.ContractName == "FlowToken" {
 				usdPrice, _ = s.priceCache.GetPriceAt("FLOW", t.TokenTransfer.Timestamp)
 			}
-			item := toFTTransferOutput(t.TokenTransfer, t.ContractName, address, m, usdPrice)
+			item := toFTTransferOutput(r.Context(), t.TokenTransfer, t.ContractName, address, m, usdPrice)
 			item["type"] = "ft"
 			out = append(out, item)
 		}
@@ -125,7 +128,13 @@ func (s *Server) handleFlowListFTTokens(w http.ResponseWriter, r *http.Request)
 	limit, offset := parseLimitOffset(r)
 	sort := r.URL.Query().Get("sort")
 	search := strings.TrimSpace(r.URL.Query().Get("search"))
-	filter := strings.TrimSpace(r.URL.Query().Get("filter"))
+	filter := repository.FTTokenListFilter{
+		Preset:   strings.TrimSpace(r.URL.Query().Get("filter")),
+		Verified: strings.ToLower(r.URL.Query().Get("verified")) == "true",
+		Bridged:  strings.ToLower(r.URL.Query().Get("bridged")) == "true",
+		Query:    strings.TrimSpace(r.URL.Query().Get("q")),
+	}
+	filtered := filter.Verified || filter.Bridged || filter.Query != ""
 
 	var tokens []models.FTToken
 	var total int64
@@ -148,13 +157,13 @@ func (s *Server) handleFlowListFTTokens(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
-		total, err = s.repo.CountFTTokens(r.Context())
+		total, err = s.repo.CountFTTokens(r.Context(), filter)
 		if err != nil {
 			writeAPIError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
-		if len(tokens) == 0 {
+		if len(tokens) == 0 && !filtered {
 			contracts, err := s.repo.ListFTTokenContracts(r.Context(), limit, offset)
 			if err != nil {
 				writeAPIError(w, http.StatusInternalServerError, err.Error())
@@ -173,7 +182,7 @@ func (s *Server) handleFlowListFTTokens(w http.ResponseWriter, r *http.Request)
 
 	out := make([]map[string]interface{}, 0, len(tokens))
 	for _, t := range tokens {
-		m := toFTListOutput(t)
+		m := toFTListOutput(r.Context(), t)
 		if t.MarketSymbol != "" {
 			if price, change, ok := s.priceCache.GetLatestPriceWithChange(t.MarketSymbol); ok {
 				m["current_price"] = price
@@ -193,10 +202,10 @@ func (s *Server) handleFlowGetFTToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if t == nil {
-		writeAPIResponse(w, []interface{}{toFTListOutput(models.FTToken{ContractAddress: tokenAddr, ContractName: tokenName})}, nil, nil)
+		writeAPIResponse(w, []interface{}{toFTListOutput(r.Context(), models.FTToken{ContractAddress: tokenAddr, ContractName: tokenName})}, nil, nil)
 		return
 	}
-	out := toFTListOutput(*t)
+	out := toFTListOutput(r.Context(), *t)
 	writeAPIResponse(w, []interface{}{out}, nil, nil)
 }
 
@@ -215,7 +224,7 @@ func (s *Server) handleFlowFTHoldingsByToken(w http.ResponseWriter, r *http.Requ
 	}
 	out := make([]map[string]interface{}, 0, len(holdings))
 	for _, h := range holdings {
-		out = append(out, toFTHoldingOutput(h, 0))
+		out = append(out, toFTHoldingOutput(r.Context(), h, 0))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": total}, nil)
 }
@@ -235,7 +244,7 @@ func (s *Server) handleFlowTopFTAccounts(w http.ResponseWriter, r *http.Request)
 	}
 	out := make([]map[string]interface{}, 0, len(holdings))
 	for _, h := range holdings {
-		out = append(out, toFTHoldingOutput(h, 0))
+		out = append(out, toFTHoldingOutput(r.Context(), h, 0))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": total}, nil)
 }
@@ -288,5 +297,5 @@ func (s *Server) handleFlowAccountFTHoldingByToken(w http.ResponseWriter, r *htt
 		writeAPIResponse(w, []interface{}{}, nil, nil)
 		return
 	}
-	writeAPIResponse(w, []interface{}{toVaultOutput(*holding)}, nil, nil)
+	writeAPIResponse(w, []interface{}{toVaultOutput(r.Context(), *holding)}, nil, nil)
 }
@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"flowscan-clone/internal/models"
 	"flowscan-clone/internal/repository"
@@ -11,10 +12,10 @@ import (
 )
 
 func (s *Server) handleFlowNFTTransfers(w http.ResponseWriter, r *http.Request) {
-	limit, offset := parseLimitOffset(r)
+	limit, offset := parseLimitOffsetWithMax(r, maxLimitTransfers)
 	height, err := parseHeightParam(r.URL.Query().Get("height"))
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+		writeAPIError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	addrFilter := normalizeAddr(r.URL.Query().Get("address"))
@@ -24,16 +25,9 @@ func (s *Server) handleFlowNFTTransfers(w http.ResponseWriter, r *http.Request)
 		writeAPIError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	nftIDs := collectTransferTokenIDs(transfers, true)
-	nftMeta, _ := s.repo.GetNFTCollectionMetadataByIdentifiers(r.Context(), nftIDs)
 	out := make([]map[string]interface{}, 0, len(transfers))
 	for _, t := range transfers {
-		id := formatTokenIdentifier(t.TokenContractAddress, t.ContractName)
-		var m *repository.TokenMetadataInfo
-		if meta, ok := nftMeta[id]; ok {
-			m = &meta
-		}
-		out = append(out, toNFTTransferOutput(t.TokenTransfer, t.ContractName, addrFilter, m))
+		out = append(out, toNFTTransferOutput(r.Context(), t.TokenTransfer, t.ContractName, addrFilter, t.Meta))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}, nil)
 }
@@ -76,7 +70,7 @@ func (s *Server) handleFlowListNFTCollections(w http.ResponseWriter, r *http.Req
 	}
 	out := make([]map[string]interface{}, 0, len(collections))
 	for _, c := range collections {
-		out = append(out, toNFTCollectionOutput(c))
+		out = append(out, toNFTCollectionOutput(r.Context(), c))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": total}, nil)
 }
@@ -92,7 +86,119 @@ func (s *Server) handleFlowGetNFTCollection(w http.ResponseWriter, r *http.Reque
 		writeAPIResponse(w, []interface{}{}, nil, nil)
 		return
 	}
-	writeAPIResponse(w, []interface{}{toNFTCollectionOutput(*summary)}, nil, nil)
+	out := toNFTCollectionOutput(r.Context(), *summary)
+	out["minted_count"] = summary.MintedCount
+	out["burned_count"] = summary.BurnedCount
+	writeAPIResponse(w, []interface{}{out}, nil, nil)
+}
+
+func (s *Server) handleFlowNFTMints(w http.ResponseWriter, r *http.Request) {
+	s.handleFlowNFTMintBurnHistory(w, r, repository.NFTTransferMint)
+}
+
+func (s *Server) handleFlowNFTBurns(w http.ResponseWriter, r *http.Request) {
+	s.handleFlowNFTMintBurnHistory(w, r, repository.NFTTransferBurn)
+}
+
+// handleFlowNFTMintBurnHistory lists a collection's mints or burns, optionally
+// bounded by from_height / to_height (inclusive).
+func (s *Server) handleFlowNFTMintBurnHistory(w http.ResponseWriter, r *http.Request, kind string) {
+	collectionAddr, collectionName := parseTokenParam(mux.Vars(r)["nft_type"])
+	if collectionAddr == "" {
+		writeAPIError(w, http.StatusBadRequest, "invalid nft_type")
+		return
+	}
+	fromHeight, err := parseHeightParam(r.URL.Query().Get("from_height"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid from_height")
+		return
+	}
+	toHeight, err := parseHeightParam(r.URL.Query().Get("to_height"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid to_height")
+		return
+	}
+	if s.repo == nil {
+		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
+		return
+	}
+	limit, offset := parseLimitOffset(r)
+	transfers, hasMore, err := s.repo.GetNFTMintBurnHistory(r.Context(), collectionAddr, collectionName, kind, fromHeight, toHeight, limit, offset)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]map[string]interface{}, 0, len(transfers))
+	for _, t := range transfers {
+		item := toNFTTransferOutput(r.Context(), t.TokenTransfer, t.ContractName, "", nil)
+		item["type"] = kind
+		out = append(out, item)
+	}
+	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}, nil)
+}
+
+// handleFlowNFTSaleStats returns a collection's marketplace floor, 24h volume
+// and sale counts, one entry per payment token.
+func (s *Server) handleFlowNFTSaleStats(w http.ResponseWriter, r *http.Request) {
+	collectionAddr, collectionName := parseTokenParam(mux.Vars(r)["nft_type"])
+	if collectionAddr == "" {
+		writeAPIError(w, http.StatusBadRequest, "invalid nft_type")
+		return
+	}
+	if s.repo == nil {
+		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
+		return
+	}
+	stats, err := s.repo.GetNFTSaleStats(r.Context(), collectionAddr, collectionName, time.Now())
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if stats == nil {
+		stats = []repository.NFTSaleStats{}
+	}
+	writeAPIResponse(w, stats, map[string]interface{}{"nft_type": formatTokenIdentifier(collectionAddr, collectionName)}, nil)
+}
+
+// handleFlowNFTSales lists a collection's completed marketplace sales, newest first.
+func (s *Server) handleFlowNFTSales(w http.ResponseWriter, r *http.Request) {
+	collectionAddr, collectionName := parseTokenParam(mux.Vars(r)["nft_type"])
+	if collectionAddr == "" {
+		writeAPIError(w, http.StatusBadRequest, "invalid nft_type")
+		return
+	}
+	if s.repo == nil {
+		writeAPIError(w, http.StatusInternalServerError, "repository unavailable")
+		return
+	}
+	limit, offset := parseLimitOffsetWithMax(r, maxLimitTransfers)
+	sales, err := s.repo.ListNFTSales(r.Context(), collectionAddr, collectionName, limit+1, offset)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	hasMore := len(sales) > limit
+	if hasMore {
+		sales = sales[:limit]
+	}
+	out := make([]map[string]interface{}, 0, len(sales))
+	for _, sale := range sales {
+		out = append(out, map[string]interface{}{
+			"transaction_hash": sale.TransactionID,
+			"block_height":     sale.BlockHeight,
+			"event_index":      sale.EventIndex,
+			"timestamp":        formatTime(sale.Timestamp),
+			"marketplace":      sale.Marketplace,
+			"listing_id":       sale.ListingID,
+			"nft_type":         formatTokenIdentifier(sale.CollectionAddress, sale.CollectionName),
+			"nft_id":           sale.NFTID,
+			"price":            sale.Price,
+			"payment_token":    sale.PaymentToken,
+			"seller":           formatAddressV1(r.Context(), sale.Seller),
+			"buyer":            formatAddressV1(r.Context(), sale.Buyer),
+		})
+	}
+	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}, nil)
 }
 
 func (s *Server) handleFlowNFTHoldingsByCollection(w http.ResponseWriter, r *http.Request) {
@@ -114,7 +220,7 @@ func (s *Server) handleFlowNFTHoldingsByCollection(w http.ResponseWriter, r *htt
 		if totalNFTs > 0 {
 			percentage = float64(row.Count) / float64(totalNFTs)
 		}
-		out = append(out, toNFTHoldingOutput(row.Owner, row.Count, percentage, formatTokenIdentifier(collectionAddr, collectionName)))
+		out = append(out, toNFTHoldingOutput(r.Context(), row.Owner, row.Count, percentage, formatTokenIdentifier(collectionAddr, collectionName)))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore, "total_nfts": totalNFTs}, nil)
 }
@@ -138,7 +244,7 @@ func (s *Server) handleFlowTopNFTAccounts(w http.ResponseWriter, r *http.Request
 		if totalNFTs > 0 {
 			percentage = float64(row.Count) / float64(totalNFTs)
 		}
-		out = append(out, toNFTHoldingOutput(row.Owner, row.Count, percentage, formatTokenIdentifier(collectionAddr, collectionName)))
+		out = append(out, toNFTHoldingOutput(r.Context(), row.Owner, row.Count, percentage, formatTokenIdentifier(collectionAddr, collectionName)))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore, "total_nfts": totalNFTs}, nil)
 }
@@ -155,7 +261,7 @@ func (s *Server) handleFlowNFTItem(w http.ResponseWriter, r *http.Request) {
 		writeAPIResponse(w, []interface{}{}, nil, nil)
 		return
 	}
-	out := toCombinedNFTDetails(*item)
+	out := toCombinedNFTDetails(r.Context(), *item)
 	// Enrich with metadata from nft_items if available.
 	meta, _ := s.repo.GetNFTItem(r.Context(), collectionAddr, collectionName, id)
 	if meta != nil {
@@ -185,7 +291,7 @@ func (s *Server) handleFlowNFTCollectionItems(w http.ResponseWriter, r *http.Req
 	}
 	out := make([]map[string]interface{}, 0, len(items))
 	for _, item := range items {
-		out = append(out, toNFTItemOutput(item))
+		out = append(out, toNFTItemOutput(r.Context(), item))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}, nil)
 }
@@ -205,7 +311,7 @@ func (s *Server) handleFlowNFTSearch(w http.ResponseWriter, r *http.Request) {
 	}
 	out := make([]map[string]interface{}, 0, len(items))
 	for _, item := range items {
-		out = append(out, toNFTItemOutput(item))
+		out = append(out, toNFTItemOutput(r.Context(), item))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}, nil)
 }
@@ -213,7 +319,7 @@ func (s *Server) handleFlowNFTSearch(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleFlowNFTItemTransfers(w http.ResponseWriter, r *http.Request) {
 	collectionAddr, collectionName := parseTokenParam(mux.Vars(r)["nft_type"])
 	id := mux.Vars(r)["id"]
-	limit, offset := parseLimitOffset(r)
+	limit, offset := parseLimitOffsetWithMax(r, maxLimitTransfers)
 
 	transfers, hasMore, err := s.repo.ListNFTItemTransfers(r.Context(), collectionAddr, collectionName, id, limit, offset)
 	if err != nil {
@@ -229,7 +335,40 @@ func (s *Server) handleFlowNFTItemTransfers(w http.ResponseWriter, r *http.Reque
 		if meta, ok := nftMeta2[id]; ok {
 			m = &meta
 		}
-		out = append(out, toNFTTransferOutput(t.TokenTransfer, t.ContractName, "", m))
+		out = append(out, toNFTTransferOutput(r.Context(), t.TokenTransfer, t.ContractName, "", m))
 	}
 	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out), "has_more": hasMore}, nil)
 }
+
+// handleFlowNFTItemHistory returns the provenance chain of a single NFT, oldest
+// first, with each hop labelled as a mint, transfer or burn.
+func (s *Server) handleFlowNFTItemHistory(w http.ResponseWriter, r *http.Request) {
+	collectionAddr, collectionName := parseTokenParam(mux.Vars(r)["nft_type"])
+	id := mux.Vars(r)["id"]
+	if collectionAddr == "" || id == "" {
+		writeAPIError(w, http.StatusBadRequest, "nft_type must be A.<address>.<name> and id is required")
+		return
+	}
+
+	history, err := s.repo.GetNFTTransferHistory(r.Context(), collectionAddr, collectionName, id)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var meta *repository.TokenMetadataInfo
+	if len(history) > 0 {
+		ident := formatTokenIdentifier(history[0].TokenContractAddress, history[0].ContractName)
+		if m, err := s.repo.GetNFTCollectionMetadataByIdentifiers(r.Context(), []string{ident}); err == nil {
+			if v, ok := m[ident]; ok {
+				meta = &v
+			}
+		}
+	}
+	out := make([]map[string]interface{}, 0, len(history))
+	for _, h := range history {
+		item := toNFTTransferOutput(r.Context(), h.TokenTransfer, h.ContractName, "", meta)
+		item["kind"] = h.Kind
+		out = append(out, item)
+	}
+	writeAPIResponse(w, out, map[string]interface{}{"count": len(out)}, nil)
+}
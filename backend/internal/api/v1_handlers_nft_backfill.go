@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"flowscan-clone/internal/contractid"
 	"flowscan-clone/internal/models"
 )
 
@@ -79,11 +80,11 @@ func toInt64Ptr(v interface{}) *int64 {
 // parseCollectionID extracts contract address and name from a type identifier.
 // e.g. "A.0b2a3299cc857e29.TopShot.Collection" -> ("0b2a3299cc857e29", "TopShot")
 func parseCollectionID(id string) (contractAddr, contractName string, ok bool) {
-	parts := strings.Split(id, ".")
-	if len(parts) < 3 || parts[0] != "A" {
+	if !strings.HasPrefix(id, "A.") {
 		return "", "", false
 	}
-	return parts[1], parts[2], true
+	c := contractid.Parse(id)
+	return c.Address, c.Name, c.Address != "" && c.Name != ""
 }
 
 func (s *Server) handleFlowNFTBackfill(w http.ResponseWriter, r *http.Request) {
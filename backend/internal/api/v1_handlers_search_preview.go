@@ -255,7 +255,7 @@ func (s *Server) handleSearchPreviewTx(w http.ResponseWriter, r *http.Request, q
 			ScheduledID: st.ScheduledID,
 			Status:      st.Status,
 			Handler:     parseScheduledContractName(st.HandlerType),
-			Owner:       formatAddressV1(st.HandlerOwner),
+			Owner:       formatAddressV1(ctx, st.HandlerOwner),
 			MatchedBy:   matchedBy,
 		}
 		mu.Unlock()
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"strings"
@@ -36,7 +37,7 @@ func (s *Server) handleListNodes(w http.ResponseWriter, r *http.Request) {
 
 	out := make([]interface{}, 0, len(nodes))
 	for _, n := range nodes {
-		out = append(out, stakingNodeToMap(n))
+		out = append(out, stakingNodeToMap(r.Context(), n))
 	}
 
 	writeAPIResponse(w, out, map[string]interface{}{
@@ -60,7 +61,7 @@ func (s *Server) handleGetNode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeAPIResponse(w, []interface{}{stakingNodeToMap(*node)}, nil, nil)
+	writeAPIResponse(w, []interface{}{stakingNodeToMap(r.Context(), *node)}, nil, nil)
 }
 
 // handleGetNodeEvents handles GET /staking/v1/node/{node_id}/event — events for a node
@@ -72,7 +73,7 @@ func (s *Server) handleGetNodeEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit, offset := parseLimitOffset(r)
+	limit, offset := parseLimitOffsetWithMax(r, maxLimitEvents)
 
 	events, err := s.repo.ListStakingEventsByNode(r.Context(), nodeID, limit, offset)
 	if err != nil {
@@ -141,7 +142,7 @@ func (s *Server) handleListEpochNodes(w http.ResponseWriter, r *http.Request) {
 
 	out := make([]interface{}, 0, len(nodes))
 	for _, n := range nodes {
-		out = append(out, stakingNodeToMap(n))
+		out = append(out, stakingNodeToMap(r.Context(), n))
 	}
 
 	writeAPIResponse(w, out, map[string]interface{}{
@@ -151,11 +152,11 @@ func (s *Server) handleListEpochNodes(w http.ResponseWriter, r *http.Request) {
 	}, nil)
 }
 
-func stakingNodeToMap(n models.StakingNode) map[string]interface{} {
+func stakingNodeToMap(ctx context.Context, n models.StakingNode) map[string]interface{} {
 	return map[string]interface{}{
 		"node_id":            n.NodeID,
 		"epoch":              n.Epoch,
-		"address":            formatAddressV1(n.Address),
+		"address":            formatAddressV1(ctx, n.Address),
 		"role":               n.Role,
 		"networking_address": n.NetworkingAddress,
 		"tokens_staked":      parseFloatOrZero(n.TokensStaked),
@@ -199,7 +200,7 @@ func (s *Server) handleStakingDelegators(w http.ResponseWriter, r *http.Request)
 		out = append(out, map[string]interface{}{
 			"delegator_id":     d.DelegatorID,
 			"node_id":          d.NodeID,
-			"address":          formatAddressV1(d.Address),
+			"address":          formatAddressV1(r.Context(), d.Address),
 			"tokens_committed": parseFloatOrZero(d.TokensCommitted),
 			"tokens_staked":    parseFloatOrZero(d.TokensStaked),
 			"tokens_unstaking": parseFloatOrZero(d.TokensUnstaking),
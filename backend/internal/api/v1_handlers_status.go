@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"math"
@@ -11,6 +13,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"flowscan-clone/internal/ingester"
+	"flowscan-clone/internal/repository"
 )
 
 // Status endpoints
@@ -64,6 +69,41 @@ func (s *Server) handleStatusEpochStatus(w http.ResponseWriter, r *http.Request)
 	writeAPIResponse(w, []interface{}{payload}, nil, nil)
 }
 
+// handleNetworkEpoch returns the current epoch as last polled from the chain,
+// with its start/end heights and participating node count. It answers 503
+// until the NetworkPoller has stored its first epoch snapshot.
+func (s *Server) handleNetworkEpoch(w http.ResponseWriter, r *http.Request) {
+	epoch, err := s.repo.GetCurrentEpoch(r.Context())
+	if errors.Is(err, repository.ErrNotFound) {
+		writeAPIError(w, http.StatusServiceUnavailable, "epoch data not available yet")
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := map[string]interface{}{
+		"epoch":        epoch.Epoch,
+		"phase":        epoch.Phase,
+		"phase_name":   epoch.PhaseName,
+		"progress":     epoch.Progress,
+		"start_view":   epoch.StartView,
+		"end_view":     epoch.EndView,
+		"current_view": epoch.CurrentView,
+		"start_height": nil,
+		"end_height":   nil,
+		"node_count":   epoch.NodeCount,
+		"as_of":        formatTime(epoch.AsOf),
+	}
+	if epoch.StartHeight > 0 {
+		out["start_height"] = epoch.StartHeight
+	}
+	if epoch.EndHeight > 0 {
+		out["end_height"] = epoch.EndHeight
+	}
+	writeAPIResponse(w, []interface{}{out}, nil, nil)
+}
+
 func (s *Server) handleStatusEpochStat(w http.ResponseWriter, r *http.Request) {
 	snap, err := s.repo.GetStatusSnapshot(r.Context(), "epoch_stat")
 	if err != nil {
@@ -173,7 +213,7 @@ func (s *Server) handleStatusNodes(w http.ResponseWriter, r *http.Request) {
 		entry := map[string]interface{}{
 			"node_id":            n.NodeID,
 			"role":               n.Role,
-			"address":            formatAddressV1(n.Address),
+			"address":            formatAddressV1(r.Context(), n.Address),
 			"networking_address": n.NetworkingAddress,
 			"tokens_staked":      parseFloatOrZero(n.TokensStaked),
 			"tokens_committed":   parseFloatOrZero(n.TokensCommitted),
@@ -318,6 +358,163 @@ func (s *Server) handleStatusPrices(w http.ResponseWriter, r *http.Request) {
 	}, nil, nil)
 }
 
+// handleStatusSync reports every indexing checkpoint against the chain head so
+// dashboards can tell whether the indexer is caught up.
+// GET /status/sync
+func (s *Server) handleStatusSync(w http.ResponseWriter, r *http.Request) {
+	checkpoints, err := s.repo.ListCheckpoints(r.Context())
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var chainHead uint64
+	if s.client != nil {
+		flowCtx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+		if h, err := s.client.GetLatestBlockHeight(flowCtx); err == nil {
+			chainHead = h
+		}
+		cancel()
+	}
+
+	forwardName := os.Getenv("FORWARD_SERVICE_NAME")
+	if forwardName == "" {
+		forwardName = "main_ingester"
+	}
+	historyName := os.Getenv("HISTORY_SERVICE_NAME")
+	if historyName == "" {
+		historyName = "history_ingester"
+	}
+	stopHeight, _ := strconv.ParseUint(os.Getenv("HISTORY_STOP_HEIGHT"), 10, 64)
+	headLag, _ := strconv.ParseUint(os.Getenv("INGEST_HEAD_LAG"), 10, 64)
+
+	out := buildSyncStatus(checkpoints, chainHead, forwardName, historyName, stopHeight, time.Now())
+	addForwardTarget(out, chainHead, headLag)
+	if completions, err := s.repo.ListBackfillCompletions(r.Context()); err == nil {
+		addBackfillCompletions(out, completions, historyName)
+	}
+	if bp, ok := s.client.(breakerStatsProvider); ok {
+		out["access_node_breaker"] = bp.BreakerStats()
+	}
+	if s.watchdog != nil {
+		out["ingester_watchdog"] = s.watchdog.Stats()
+	}
+	if s.committer != nil {
+		out["worker_leases"] = s.committer.LeaseAges()
+	}
+	if s.backpressure != nil {
+		out["ingester_backpressure"] = s.backpressure.Stats()
+	}
+	out["db_pool"] = s.repo.PoolUsage()
+	out["db_queries"] = s.repo.QueryStats()
+	writeAPIResponse(w, out, nil, nil)
+}
+
+// buildSyncStatus computes per-service lag from checkpoints. When the access node
+// is unreachable (chainHead == 0) the forward ingester position stands in for the head.
+// Downward-moving checkpoints (the history ingester and *_down cursors) have no lag.
+func buildSyncStatus(checkpoints []repository.IndexingCheckpoint, chainHead uint64, forwardName, historyName string, stopHeight uint64, now time.Time) map[string]interface{} {
+	byName := make(map[string]repository.IndexingCheckpoint, len(checkpoints))
+	for _, c := range checkpoints {
+		byName[c.ServiceName] = c
+	}
+
+	headSource := "access_node"
+	if chainHead == 0 {
+		headSource = "forward_checkpoint"
+		chainHead = byName[forwardName].LastHeight
+	}
+	lagOf := func(h uint64) uint64 {
+		if chainHead > h {
+			return chainHead - h
+		}
+		return 0
+	}
+
+	services := make([]map[string]interface{}, 0, len(checkpoints))
+	for _, c := range checkpoints {
+		entry := map[string]interface{}{
+			"service_name":  c.ServiceName,
+			"last_height":   c.LastHeight,
+			"updated_at":    formatTime(c.UpdatedAt),
+			"stale_seconds": int64(now.Sub(c.UpdatedAt).Seconds()),
+		}
+		if c.ServiceName != historyName && !strings.HasSuffix(c.ServiceName, "_down") {
+			entry["lag"] = lagOf(c.LastHeight)
+		}
+		services = append(services, entry)
+	}
+
+	forward := map[string]interface{}{"service_name": forwardName}
+	if c, ok := byName[forwardName]; ok {
+		forward["last_height"] = c.LastHeight
+		forward["lag"] = lagOf(c.LastHeight)
+		forward["updated_at"] = formatTime(c.UpdatedAt)
+	}
+
+	// The backward ingester is done once it reaches genesis (height <= 1) or the
+	// configured HISTORY_STOP_HEIGHT floor; see ingester.Service backward mode.
+	history := map[string]interface{}{"service_name": historyName, "stop_height": stopHeight, "complete": false}
+	if c, ok := byName[historyName]; ok && c.LastHeight > 0 {
+		history["last_height"] = c.LastHeight
+		history["updated_at"] = formatTime(c.UpdatedAt)
+		history["complete"] = c.LastHeight <= 1 || (stopHeight > 0 && c.LastHeight <= stopHeight)
+	}
+
+	return map[string]interface{}{
+		"chain_head":        chainHead,
+		"chain_head_source": headSource,
+		"forward":           forward,
+		"history":           history,
+		"services":          services,
+	}
+}
+
+// addForwardTarget reports the height the forward ingester is aiming for: the
+// sealed chain head minus INGEST_HEAD_LAG. Lag against the target is what the
+// operator can expect to reach zero.
+func addForwardTarget(out map[string]interface{}, chainHead, headLag uint64) {
+	forward := out["forward"].(map[string]interface{})
+	forward["head_lag"] = headLag
+	if chainHead == 0 {
+		return
+	}
+	target := ingester.ForwardTarget(chainHead, headLag)
+	forward["target_height"] = target
+	if last, ok := forward["last_height"].(uint64); ok {
+		if target > last {
+			forward["lag_to_target"] = target - last
+		} else {
+			forward["lag_to_target"] = uint64(0)
+		}
+	}
+}
+
+// addBackfillCompletions attaches the backward ingester completion markers.
+// Each history instance (one per spork range) reports its own range; the
+// configured history service is complete once it has a marker.
+func addBackfillCompletions(out map[string]interface{}, completions []repository.BackfillCompletion, historyName string) {
+	ranges := make([]map[string]interface{}, 0, len(completions))
+	history := out["history"].(map[string]interface{})
+	for _, c := range completions {
+		ranges = append(ranges, map[string]interface{}{
+			"service_name": c.ServiceName,
+			"start_height": c.StartHeight,
+			"floor_height": c.FloorHeight,
+			"reason":       c.Reason,
+			"completed_at": formatTime(c.CompletedAt),
+		})
+		if c.ServiceName == historyName {
+			history["complete"] = true
+			if _, ok := history["completed_at"]; !ok {
+				history["completed_at"] = formatTime(c.CompletedAt)
+				history["completion_reason"] = c.Reason
+			}
+		}
+	}
+	out["backfill_ranges"] = ranges
+}
+
 func (s *Server) handleNotImplemented(w http.ResponseWriter, r *http.Request) {
 	writeAPIError(w, http.StatusNotImplemented, "endpoint not implemented yet; see /docs/api for status")
 }
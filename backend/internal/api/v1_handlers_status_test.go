@@ -0,0 +1,115 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/repository"
+)
+
+func TestBuildSyncStatus(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	checkpoints := []repository.IndexingCheckpoint{
+		{ServiceName: "main_ingester", LastHeight: 1000, UpdatedAt: now.Add(-5 * time.Second)},
+		{ServiceName: "history_ingester", LastHeight: 500, UpdatedAt: now.Add(-10 * time.Second)},
+		{ServiceName: "token_worker", LastHeight: 990, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ServiceName: "history_deriver_down", LastHeight: 600, UpdatedAt: now},
+	}
+
+	out := buildSyncStatus(checkpoints, 1010, "main_ingester", "history_ingester", 500, now)
+
+	if out["chain_head"] != uint64(1010) || out["chain_head_source"] != "access_node" {
+		t.Fatalf("unexpected chain head: %v (%v)", out["chain_head"], out["chain_head_source"])
+	}
+	forward := out["forward"].(map[string]interface{})
+	if forward["lag"] != uint64(10) {
+		t.Fatalf("forward lag = %v, want 10", forward["lag"])
+	}
+	history := out["history"].(map[string]interface{})
+	if history["complete"] != true {
+		t.Fatalf("history should be complete at stop height, got %v", history)
+	}
+
+	services := out["services"].([]map[string]interface{})
+	if len(services) != len(checkpoints) {
+		t.Fatalf("expected %d services, got %d", len(checkpoints), len(services))
+	}
+	byName := map[string]map[string]interface{}{}
+	for _, s := range services {
+		byName[s["service_name"].(string)] = s
+	}
+	if byName["token_worker"]["lag"] != uint64(20) {
+		t.Fatalf("token_worker lag = %v, want 20", byName["token_worker"]["lag"])
+	}
+	if byName["token_worker"]["stale_seconds"] != int64(7200) {
+		t.Fatalf("token_worker stale_seconds = %v, want 7200", byName["token_worker"]["stale_seconds"])
+	}
+	if byName["token_worker"]["updated_at"] != "2025-06-01T10:00:00Z" {
+		t.Fatalf("token_worker updated_at = %v", byName["token_worker"]["updated_at"])
+	}
+	for _, name := range []string{"history_ingester", "history_deriver_down"} {
+		if _, ok := byName[name]["lag"]; ok {
+			t.Fatalf("%s moves downward and should not report lag", name)
+		}
+	}
+}
+
+func TestBuildSyncStatusWithoutAccessNode(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	checkpoints := []repository.IndexingCheckpoint{
+		{ServiceName: "main_ingester", LastHeight: 1000, UpdatedAt: now},
+		{ServiceName: "history_ingester", LastHeight: 800, UpdatedAt: now},
+	}
+
+	out := buildSyncStatus(checkpoints, 0, "main_ingester", "history_ingester", 0, now)
+
+	if out["chain_head"] != uint64(1000) || out["chain_head_source"] != "forward_checkpoint" {
+		t.Fatalf("expected forward checkpoint as head, got %v (%v)", out["chain_head"], out["chain_head_source"])
+	}
+	if out["history"].(map[string]interface{})["complete"] != false {
+		t.Fatal("history backfill above genesis with no stop height should not be complete")
+	}
+}
+
+func TestBuildSyncStatusForwardTarget(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	checkpoints := []repository.IndexingCheckpoint{
+		{ServiceName: "main_ingester", LastHeight: 985, UpdatedAt: now},
+	}
+
+	out := buildSyncStatus(checkpoints, 1010, "main_ingester", "history_ingester", 0, now)
+	addForwardTarget(out, 1010, 20)
+
+	forward := out["forward"].(map[string]interface{})
+	if forward["head_lag"] != uint64(20) || forward["target_height"] != uint64(990) {
+		t.Fatalf("forward target = %v", forward)
+	}
+	if forward["lag_to_target"] != uint64(5) || forward["lag"] != uint64(25) {
+		t.Fatalf("forward lag = %v / %v", forward["lag"], forward["lag_to_target"])
+	}
+}
+
+func TestBuildSyncStatusBackfillCompletions(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	checkpoints := []repository.IndexingCheckpoint{
+		{ServiceName: "main_ingester", LastHeight: 1000, UpdatedAt: now},
+		{ServiceName: "history_ingester", LastHeight: 700, UpdatedAt: now},
+	}
+	out := buildSyncStatus(checkpoints, 1000, "main_ingester", "history_ingester", 0, now)
+	if out["history"].(map[string]interface{})["complete"] != false {
+		t.Fatal("history above genesis should not be complete before a marker")
+	}
+
+	addBackfillCompletions(out, []repository.BackfillCompletion{
+		{ServiceName: "history_ingester", StartHeight: 900, FloorHeight: 700, Reason: "spork_root", CompletedAt: now},
+		{ServiceName: "history_ingester_spork19", StartHeight: 699, FloorHeight: 400, Reason: "stop_height", CompletedAt: now},
+	}, "history_ingester")
+
+	history := out["history"].(map[string]interface{})
+	if history["complete"] != true || history["completion_reason"] != "spork_root" {
+		t.Fatalf("history = %v", history)
+	}
+	if ranges := out["backfill_ranges"].([]map[string]interface{}); len(ranges) != 2 || ranges[1]["floor_height"] != uint64(400) {
+		t.Fatalf("backfill_ranges = %v", ranges)
+	}
+}
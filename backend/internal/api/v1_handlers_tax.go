@@ -1,9 +1,12 @@
 package api
 
 import (
+	"math/big"
 	"net/http"
 	"time"
 
+	"flowscan-clone/internal/ufix64"
+
 	"github.com/gorilla/mux"
 )
 
@@ -64,11 +67,9 @@ func (s *Server) handleTaxReport(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build transfer list with per-transfer historical price info
-	ftIDs := collectTransferTokenIDs(ftTransfers, false)
-	ftMeta, _ := s.repo.GetFTTokenMetadataByIdentifiers(r.Context(), ftIDs)
-
 	var transfers []map[string]interface{}
-	var totalFTIn, totalFTOut float64
+	// Totals are summed exactly; float64 drifts on large UFix64 amounts.
+	totalFTIn, totalFTOut := new(big.Int), new(big.Int)
 
 	for _, t := range ftTransfers {
 		ts := t.TokenTransfer.Timestamp
@@ -81,18 +82,19 @@ func (s *Server) handleTaxReport(w http.ResponseWriter, r *http.Request) {
 
 		amount := parseFloatOrZero(t.TokenTransfer.Amount)
 		direction := transferDirection(address, t.TokenTransfer.FromAddress, t.TokenTransfer.ToAddress)
-		if direction == "deposit" {
-			totalFTIn += amount
-		} else {
-			totalFTOut += amount
+		if exact, err := ufix64.Parse(t.TokenTransfer.Amount); err == nil {
+			if direction == "deposit" {
+				totalFTIn.Add(totalFTIn, exact)
+			} else {
+				totalFTOut.Add(totalFTOut, exact)
+			}
 		}
 
 		tokenIdentifier := formatTokenVaultIdentifier(t.TokenTransfer.TokenContractAddress, t.ContractName)
 
 		// Historical price lookup
 		var usdPrice float64
-		id := formatTokenVaultIdentifier(t.TokenTransfer.TokenContractAddress, t.ContractName)
-		if m, ok := ftMeta[id]; ok && m.MarketSymbol != "" {
+		if m := t.Meta; m != nil && m.MarketSymbol != "" {
 			usdPrice, _ = s.priceCache.GetPriceAt(m.MarketSymbol, ts)
 		} else if t.ContractName == "FlowToken" {
 			usdPrice, _ = s.priceCache.GetPriceAt("FLOW", ts)
@@ -106,8 +108,8 @@ func (s *Server) handleTaxReport(w http.ResponseWriter, r *http.Request) {
 			"token":            tokenIdentifier,
 			"amount":           amount,
 			"direction":        direction,
-			"sender":           formatAddressV1(t.TokenTransfer.FromAddress),
-			"receiver":         formatAddressV1(t.TokenTransfer.ToAddress),
+			"sender":           formatAddressV1(r.Context(), t.TokenTransfer.FromAddress),
+			"receiver":         formatAddressV1(r.Context(), t.TokenTransfer.ToAddress),
 			"approx_usd_price": usdPrice,
 			"usd_value":        amount * usdPrice,
 		}
@@ -134,8 +136,8 @@ func (s *Server) handleTaxReport(w http.ResponseWriter, r *http.Request) {
 			"nft_type":         nftType,
 			"nft_id":           t.TokenTransfer.TokenID,
 			"direction":        direction,
-			"sender":           formatAddressV1(t.TokenTransfer.FromAddress),
-			"receiver":         formatAddressV1(t.TokenTransfer.ToAddress),
+			"sender":           formatAddressV1(r.Context(), t.TokenTransfer.FromAddress),
+			"receiver":         formatAddressV1(r.Context(), t.TokenTransfer.ToAddress),
 		}
 		transfers = append(transfers, entry)
 	}
@@ -151,10 +153,10 @@ func (s *Server) handleTaxReport(w http.ResponseWriter, r *http.Request) {
 	}
 
 	summary := map[string]interface{}{
-		"address":         formatAddressV1(address),
+		"address":         formatAddressV1(r.Context(), address),
 		"total_transfers": len(transfers),
-		"total_ft_in":     totalFTIn,
-		"total_ft_out":    totalFTOut,
+		"total_ft_in":     ufix64.Format(totalFTIn),
+		"total_ft_out":    ufix64.Format(totalFTOut),
 		"flow_price_usd":  latestFlowPrice,
 		"transfers":       transfers,
 	}
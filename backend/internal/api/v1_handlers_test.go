@@ -3,9 +3,14 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"flowscan-clone/internal/repository"
+
 	"github.com/gorilla/mux"
 	"github.com/onflow/cadence"
 	flowsdk "github.com/onflow/flow-go-sdk"
@@ -87,3 +92,45 @@ func TestTransferDirection(t *testing.T) {
 		t.Fatalf("expected withdraw when to empty, got %s", got)
 	}
 }
+
+func TestRepoErrorStatus(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: http.StatusOK},
+		{name: "not found", err: repository.ErrNotFound, want: http.StatusNotFound},
+		{name: "wrapped not found", err: fmt.Errorf("block 42: %w", repository.ErrNotFound), want: http.StatusNotFound},
+		{name: "conflict", err: fmt.Errorf("label: %w", repository.ErrConflict), want: http.StatusConflict},
+		{name: "other", err: errors.New("connection reset"), want: http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		if got := repoErrorStatus(tc.err); got != tc.want {
+			t.Errorf("%s: repoErrorStatus=%d want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestWriteRepoErrorUsesNotFoundMessage(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeRepoError(rec, fmt.Errorf("transaction abc: %w", repository.ErrNotFound), "transaction not found")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+	var resp struct {
+		Error map[string]string `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Error["message"] != "transaction not found" {
+		t.Fatalf("unexpected message %q", resp.Error["message"])
+	}
+
+	rec = httptest.NewRecorder()
+	writeRepoError(rec, errors.New("timeout"), "transaction not found")
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"flowscan-clone/internal/ingester"
 	"flowscan-clone/internal/models"
 	"flowscan-clone/internal/repository"
 
@@ -57,7 +61,7 @@ func (s *Server) handleFlowListTransactions(w http.ResponseWriter, r *http.Reque
 	limit, offset := parseLimitOffset(r)
 	height, err := parseHeightParam(r.URL.Query().Get("height"))
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, "invalid height")
+		writeAPIError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	includeEvents := strings.ToLower(r.URL.Query().Get("include_events")) == "true"
@@ -67,13 +71,18 @@ func (s *Server) handleFlowListTransactions(w http.ResponseWriter, r *http.Reque
 		Proposer:      normalizeAddr(r.URL.Query().Get("proposer")),
 		Authorizer:    normalizeAddr(r.URL.Query().Get("authorizers")),
 		Status:        strings.TrimSpace(r.URL.Query().Get("status")),
+		Kind:          strings.ToLower(strings.TrimSpace(r.URL.Query().Get("kind"))),
 		Limit:         limit,
 		Offset:        offset,
 		IncludeEvents: includeEvents,
 	}
+	filtered := f.Height != nil || f.Payer != "" || f.Proposer != "" || f.Authorizer != "" || f.Status != "" || f.Kind != ""
+	if rejectFilteredExactCount(w, r, filtered) {
+		return
+	}
 	txs, err := s.repo.ListTransactionsFiltered(r.Context(), f)
 	if err != nil {
-		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		writeRepoError(w, err, "")
 		return
 	}
 	txIDs := collectTxIDs(txs)
@@ -106,14 +115,14 @@ func (s *Server) handleFlowListTransactions(w http.ResponseWriter, r *http.Reque
 	for _, t := range txs {
 		ts := transferSummaries[t.ID]
 		ftPrices := s.buildFTPrices(ftMeta, t.Timestamp)
-		o := toFlowTransactionOutputWithTransfers(t, eventsByTx[t.ID], contracts[t.ID], tags[t.ID], feesByTx[t.ID], &ts, ftMeta, nftMeta, ftPrices)
+		o := toFlowTransactionOutputWithTransfers(r.Context(), t, eventsByTx[t.ID], contracts[t.ID], tags[t.ID], feesByTx[t.ID], &ts, ftMeta, nftMeta, ftPrices)
 		if fee := feesByTx[t.ID]; fee > 0 {
 			if p, ok := s.priceCache.GetPriceAt("FLOW", t.Timestamp); ok {
 				o["fee_usd"] = fee * p
 			}
 		}
 		if canonical, ok := canonicalTransferSummaries[t.ID]; ok && len(canonical.FT) > 0 {
-			o["canonical_transfer_summary"] = toTransferSummaryOutput(canonical, ftMeta, map[string]repository.TokenMetadataInfo{}, ftPrices)
+			o["canonical_transfer_summary"] = toTransferSummaryOutput(r.Context(), canonical, ftMeta, map[string]repository.TokenMetadataInfo{}, ftPrices)
 		}
 		out = append(out, o)
 	}
@@ -131,7 +140,11 @@ func (s *Server) handleFlowListTransactions(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
-	writeAPIResponse(w, out, map[string]interface{}{"limit": limit, "offset": offset, "count": len(out)}, nil)
+	meta := map[string]interface{}{"limit": limit, "offset": offset, "count": len(out)}
+	if !filtered {
+		s.addRepoListTotals(r, meta, "transactions")
+	}
+	writeAPIResponse(w, out, meta, nil)
 }
 
 func (s *Server) handleFlowGetTransaction(w http.ResponseWriter, r *http.Request) {
@@ -150,26 +163,51 @@ func (s *Server) handleFlowGetTransaction(w http.ResponseWriter, r *http.Request
 			writeAPIResponse(w, []interface{}{out}, nil, nil)
 			return
 		}
-		writeAPIError(w, http.StatusNotFound, "transaction not found")
+		if err == nil {
+			err = repository.ErrNotFound
+		}
+		writeRepoError(w, err, "transaction not found")
 		return
 	}
 
+	if tx.Script == "" && tx.ScriptHash != "" {
+		// Large scripts may be stored by hash only (TX_SCRIPT_STORE_MAX_BYTES).
+		if text, err := s.repo.FetchScriptFromChainIfMissing(r.Context(), tx.ScriptHash); err == nil {
+			tx.Script = text
+		} else {
+			log.Printf("[api] load script %s for tx %s: %v", tx.ScriptHash, tx.ID, err)
+		}
+	}
+
+	verified := false
+	if strings.ToLower(r.URL.Query().Get("verify")) == "true" {
+		verified = s.verifyTipTransaction(r.Context(), tx)
+	}
+
 	lite := strings.ToLower(r.URL.Query().Get("lite")) == "true"
 
-	// Always fetch: events + tags (fast, needed for header/activity type)
-	events, _ := s.repo.GetEventsByTransactionID(r.Context(), tx.ID)
+	// Always fetch: events + tags (fast, needed for header/activity type).
+	// Events come one page at a time; huge transactions emit thousands.
+	eventsLimit, eventsOffset := parseTxEventsWindow(r)
+	var events []models.Event
+	var meta map[string]interface{}
+	if page, err := s.repo.GetEventsByTransactionIDPage(r.Context(), tx.ID, eventsLimit, eventsOffset); err == nil {
+		events = page.Events
+		meta = txEventsMeta(page, eventsLimit, eventsOffset)
+	}
 	tags, _ := s.repo.GetTxTagsByTransactionIDs(r.Context(), []string{tx.ID})
 
 	if lite {
 		// Lite mode: only base tx + events + tags — skip all enrichments
-		out := toFlowTransactionOutput(*tx, events, nil, tags[tx.ID], 0)
+		out := toFlowTransactionOutput(r.Context(), *tx, events, nil, tags[tx.ID], 0)
 		out["ft_transfers"] = []interface{}{}
 		out["nft_transfers"] = []interface{}{}
 		out["defi_events"] = []interface{}{}
 		out["evm_executions"] = []interface{}{}
 		s.enrichWithScheduledTx(r.Context(), out, tx.ID)
 		out["lite"] = true
-		writeAPIResponse(w, []interface{}{out}, nil, nil)
+		out["verified"] = verified
+		writeAPIResponse(w, []interface{}{out}, meta, nil)
 		return
 	}
 
@@ -179,7 +217,7 @@ func (s *Server) handleFlowGetTransaction(w http.ResponseWriter, r *http.Request
 	// A Cadence transaction can emit embedded EVM executions even when tx.IsEVM is false.
 	// Always query by cadence tx id so detail pages can show enriched EVM metadata/ABI decode.
 	evmExecs, _ := s.repo.GetEVMTransactionsByCadenceTx(r.Context(), tx.ID, tx.BlockHeight)
-	out := toFlowTransactionOutput(*tx, events, contracts[tx.ID], tags[tx.ID], feesByTx[tx.ID], evmExecs)
+	out := toFlowTransactionOutput(r.Context(), *tx, events, contracts[tx.ID], tags[tx.ID], feesByTx[tx.ID], evmExecs)
 	if fee := feesByTx[tx.ID]; fee > 0 {
 		if p, ok := s.priceCache.GetPriceAt("FLOW", tx.Timestamp); ok {
 			out["fee_usd"] = fee * p
@@ -200,8 +238,95 @@ func (s *Server) handleFlowGetTransaction(w http.ResponseWriter, r *http.Request
 
 	// Check if this tx is related to any scheduled transactions
 	s.enrichWithScheduledTx(r.Context(), out, tx.ID)
+	out["verified"] = verified
+
+	writeAPIResponse(w, []interface{}{out}, meta, nil)
+}
+
+// maxTxEventsLimit caps ?events_limit= on the transaction detail.
+const maxTxEventsLimit = 1000
+
+// parseTxEventsWindow reads ?events_limit= (default
+// repository.DefaultTxEventsLimit, clamped to maxTxEventsLimit) and
+// ?events_offset=. Invalid values fall back to the defaults, as with
+// parseLimitOffset.
+func parseTxEventsWindow(r *http.Request) (int, int) {
+	limit, offset := repository.DefaultTxEventsLimit, 0
+	if v := r.URL.Query().Get("events_limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = min(n, maxTxEventsLimit)
+		}
+	}
+	if v := r.URL.Query().Get("events_offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+	return limit, offset
+}
+
+func txEventsMeta(page *repository.TxEventsPage, limit, offset int) map[string]interface{} {
+	return map[string]interface{}{
+		"events_limit":    limit,
+		"events_offset":   offset,
+		"events_total":    page.Total,
+		"events_has_more": page.HasMore,
+	}
+}
+
+// defaultTxVerifyTipBlocks is how close to the tip a transaction must be for
+// ?verify=true to re-check it (about ten minutes of blocks).
+const defaultTxVerifyTipBlocks = 600
+
+// verifyTipTransaction re-reads the result of a transaction within
+// txVerifyTipBlocks of the chain tip from the access node. Results indexed at
+// the unsealed tip can carry a status or gas that sealing later corrects; a
+// changed outcome is applied to tx and written back. It reports whether the
+// transaction was checked.
+func (s *Server) verifyTipTransaction(ctx context.Context, tx *models.Transaction) bool {
+	if s.client == nil || s.txVerifyTipBlocks == 0 {
+		return false
+	}
+	rpcCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
 
-	writeAPIResponse(w, []interface{}{out}, nil, nil)
+	tip, err := s.client.GetLatestBlockHeight(rpcCtx)
+	if err != nil || tip > tx.BlockHeight+s.txVerifyTipBlocks {
+		return false
+	}
+	res, err := s.client.GetTransactionResult(rpcCtx, flowsdk.HexToID(tx.ID))
+	if err != nil || res == nil {
+		log.Printf("[api] verify tx %s: %v", tx.ID, err)
+		return false
+	}
+	if !applyVerifiedTxResult(tx, res) {
+		return true
+	}
+	if s.repo != nil {
+		if err := s.repo.UpdateTransactionResult(ctx, tx.ID, tx.BlockHeight, tx.Status, tx.ErrorMessage, tx.GasUsed); err != nil {
+			log.Printf("[api] verify tx %s: %v", tx.ID, err)
+		}
+	}
+	return true
+}
+
+// applyVerifiedTxResult copies an executed or sealed result's status, error
+// and gas onto tx, normalized as the ingester stores them, and reports
+// whether anything changed. Results that are not final yet are ignored.
+func applyVerifiedTxResult(tx *models.Transaction, res *flowsdk.TransactionResult) bool {
+	if res.Status != flowsdk.TransactionStatusExecuted && res.Status != flowsdk.TransactionStatusSealed {
+		return false
+	}
+	errMsg := ""
+	if res.Error != nil {
+		errMsg = res.Error.Error()
+	}
+	status := models.NormalizeTxStatus(res.Status.String(), errMsg)
+	if status == tx.Status && errMsg == tx.ErrorMessage && res.ComputationUsage == tx.GasUsed {
+		return false
+	}
+	tx.Status, tx.ErrorMessage, tx.GasUsed = status, errMsg, res.ComputationUsage
+	return true
 }
 
 // enrichWithScheduledTx checks if a tx hash is related to scheduled transactions
@@ -213,7 +338,7 @@ func (s *Server) enrichWithScheduledTx(ctx context.Context, out map[string]inter
 	}
 	var scheduled []map[string]interface{}
 	for _, r := range results {
-		entry := toScheduledTransactionOutput(r.ST)
+		entry := toScheduledTransactionOutput(ctx, r.ST)
 		entry["matched_by"] = r.MatchedBy
 		scheduled = append(scheduled, entry)
 	}
@@ -277,14 +402,14 @@ func (s *Server) buildScheduledTxOutput(ctx context.Context, txHash string) (map
 		}
 	}
 
-	out := toFlowTransactionOutput(synth, events, nil, nil, 0)
+	out := toFlowTransactionOutput(ctx, synth, events, nil, nil, 0)
 
 	// Add scheduled tx metadata
 	out["is_scheduled"] = true
-	out["scheduled"] = toScheduledTransactionOutput(*st)
+	out["scheduled"] = toScheduledTransactionOutput(ctx, *st)
 	out["scheduled_txs"] = []map[string]interface{}{
 		func() map[string]interface{} {
-			e := toScheduledTransactionOutput(*st)
+			e := toScheduledTransactionOutput(ctx, *st)
 			e["matched_by"] = matchedBy
 			return e
 		}(),
@@ -336,19 +461,19 @@ func (s *Server) fetchTransactionFromRPC(ctx context.Context, txID string) (map[
 		result = nil // proceed with tx body only
 	}
 
-	out := rpcTransactionToOutput(tx, result)
+	out := rpcTransactionToOutput(ctx, tx, result)
 	return out, true
 }
 
 // rpcTransactionToOutput converts a Flow SDK Transaction + TransactionResult into the
 // same JSON shape as toFlowTransactionOutput, but without DB-derived enrichments.
-func rpcTransactionToOutput(tx *flowsdk.Transaction, result *flowsdk.TransactionResult) map[string]interface{} {
+func rpcTransactionToOutput(ctx context.Context, tx *flowsdk.Transaction, result *flowsdk.TransactionResult) map[string]interface{} {
 	txID := tx.ID().Hex()
 
 	// Build authorizers list
 	authorizers := make([]string, 0, len(tx.Authorizers))
 	for _, a := range tx.Authorizers {
-		authorizers = append(authorizers, formatAddressV1(a.Hex()))
+		authorizers = append(authorizers, formatAddressV1(ctx, a.Hex()))
 	}
 
 	// Build arguments as JSON array
@@ -401,11 +526,12 @@ func rpcTransactionToOutput(tx *flowsdk.Transaction, result *flowsdk.Transaction
 		"block_height":             blockHeight,
 		"transaction_index":        0,
 		"timestamp":                "",
-		"payer":                    formatAddressV1(tx.Payer.Hex()),
-		"proposer":                 formatAddressV1(tx.ProposalKey.Address.Hex()),
+		"payer":                    formatAddressV1(ctx, tx.Payer.Hex()),
+		"proposer":                 formatAddressV1(ctx, tx.ProposalKey.Address.Hex()),
 		"proposer_key_index":       tx.ProposalKey.KeyIndex,
 		"proposer_sequence_number": tx.ProposalKey.SequenceNumber,
 		"authorizers":              authorizers,
+		"signers":                  toTxSignersOutput(ctx, ingester.TxSigners(tx)),
 		"status":                   status,
 		"error":                    errorMsg,
 		"gas_used":                 gasUsed,
@@ -436,6 +562,7 @@ func (s *Server) enrichTransactionOutput(r *http.Request, out map[string]interfa
 	if len(evmExecs) > 0 {
 		out["evm_executions"] = s.buildEnrichedEVMExecutions(r.Context(), evmExecs)
 	}
+	s.linkEVMOwner(r.Context(), out)
 
 	// Enrich: FT transfers with token metadata
 	ftTransfers, _ := s.repo.GetFTTransfersByTransactionID(r.Context(), tx.ID)
@@ -473,8 +600,8 @@ func (s *Server) enrichTransactionOutput(r *http.Request, out map[string]interfa
 		for _, ft := range canonicalFTTransfers {
 			item := map[string]interface{}{
 				"token":         ft.Token,
-				"from_address":  formatAddressV1(ft.FromAddress),
-				"to_address":    formatAddressV1(ft.ToAddress),
+				"from_address":  formatAddressV1(r.Context(), ft.FromAddress),
+				"to_address":    formatAddressV1(r.Context(), ft.ToAddress),
 				"amount":        ft.Amount,
 				"event_index":   ft.EventIndex,
 				"transfer_type": ft.TransferType,
@@ -498,10 +625,10 @@ func (s *Server) enrichTransactionOutput(r *http.Request, out map[string]interfa
 				item["approx_usd_price"] = usdPrice
 			}
 			if ft.EVMToAddress != "" {
-				item["evm_to_address"] = formatAddressV1(ft.EVMToAddress)
+				item["evm_to_address"] = formatAddressV1(r.Context(), ft.EVMToAddress)
 			}
 			if ft.EVMFromAddress != "" {
-				item["evm_from_address"] = formatAddressV1(ft.EVMFromAddress)
+				item["evm_from_address"] = formatAddressV1(r.Context(), ft.EVMFromAddress)
 			}
 			if ft.IsCrossVM {
 				item["is_cross_vm"] = true
@@ -511,13 +638,13 @@ func (s *Server) enrichTransactionOutput(r *http.Request, out map[string]interfa
 			if ft.FromAddress != "" {
 				if flowAddr, ok := coaMap[ft.FromAddress]; ok {
 					fromIsCOA = true
-					item["from_coa_flow_address"] = formatAddressV1(flowAddr)
+					item["from_coa_flow_address"] = formatAddressV1(r.Context(), flowAddr)
 				}
 			}
 			if ft.ToAddress != "" {
 				if flowAddr, ok := coaMap[ft.ToAddress]; ok {
 					toIsCOA = true
-					item["to_coa_flow_address"] = formatAddressV1(flowAddr)
+					item["to_coa_flow_address"] = formatAddressV1(r.Context(), flowAddr)
 				}
 			}
 			if fromIsCOA || toIsCOA {
@@ -529,7 +656,7 @@ func (s *Server) enrichTransactionOutput(r *http.Request, out map[string]interfa
 
 		ftPrices := s.buildFTPrices(ftMeta, tx.Timestamp)
 		summary := buildCanonicalTransferSummary(canonicalFTTransfers)
-		summaryOutput := toTransferSummaryOutput(summary, ftMeta, map[string]repository.TokenMetadataInfo{}, ftPrices)
+		summaryOutput := toTransferSummaryOutput(r.Context(), summary, ftMeta, map[string]repository.TokenMetadataInfo{}, ftPrices)
 		out["transfer_summary"] = summaryOutput
 		out["canonical_transfer_summary"] = summaryOutput
 	}
@@ -567,8 +694,8 @@ func (s *Server) enrichTransactionOutput(r *http.Request, out map[string]interfa
 		for _, nt := range nftTransfers {
 			item := map[string]interface{}{
 				"token":        nt.Token,
-				"from_address": formatAddressV1(nt.FromAddress),
-				"to_address":   formatAddressV1(nt.ToAddress),
+				"from_address": formatAddressV1(r.Context(), nt.FromAddress),
+				"to_address":   formatAddressV1(r.Context(), nt.ToAddress),
 				"token_id":     nt.TokenID,
 				"event_index":  nt.EventIndex,
 			}
@@ -578,13 +705,13 @@ func (s *Server) enrichTransactionOutput(r *http.Request, out map[string]interfa
 			}
 			if nt.FromAddress != "" {
 				if flowAddr, ok := nftCOAMap[nt.FromAddress]; ok {
-					item["from_coa_flow_address"] = formatAddressV1(flowAddr)
+					item["from_coa_flow_address"] = formatAddressV1(r.Context(), flowAddr)
 					item["is_cross_vm"] = true
 				}
 			}
 			if nt.ToAddress != "" {
 				if flowAddr, ok := nftCOAMap[nt.ToAddress]; ok {
-					item["to_coa_flow_address"] = formatAddressV1(flowAddr)
+					item["to_coa_flow_address"] = formatAddressV1(r.Context(), flowAddr)
 					item["is_cross_vm"] = true
 				}
 			}
@@ -646,3 +773,160 @@ func (s *Server) enrichTransactionOutput(r *http.Request, out map[string]interfa
 		out["defi_events"] = swapEvents
 	}
 }
+
+// maxFailedTxSpan bounds the block range scanned by the failed-transaction
+// endpoints; without from_height they look back this far from to_height.
+const maxFailedTxSpan = 1000000
+
+// failedTxHeightWindow resolves from_height/to_height for the failed-transaction
+// endpoints, defaulting to_height to the indexed tip.
+func (s *Server) failedTxHeightWindow(r *http.Request) (uint64, uint64, error) {
+	q := r.URL.Query()
+	to, err := parseHeightParam(q.Get("to_height"))
+	if err != nil {
+		return 0, 0, errors.New("invalid to_height")
+	}
+	from, err := parseHeightParam(q.Get("from_height"))
+	if err != nil {
+		return 0, 0, errors.New("invalid from_height")
+	}
+	var toHeight uint64
+	if to != nil {
+		toHeight = *to
+	} else {
+		tip, err := s.repo.GetIndexedTipHeight(r.Context())
+		if err != nil {
+			return 0, 0, err
+		}
+		toHeight = tip
+	}
+	var fromHeight uint64
+	if toHeight >= maxFailedTxSpan {
+		fromHeight = toHeight - maxFailedTxSpan + 1
+	}
+	if from != nil {
+		if *from > toHeight {
+			return 0, 0, errors.New("from_height must be <= to_height")
+		}
+		if toHeight-*from >= maxFailedTxSpan {
+			return 0, 0, fmt.Errorf("range too large (max %d blocks)", maxFailedTxSpan)
+		}
+		fromHeight = *from
+	}
+	return fromHeight, toHeight, nil
+}
+
+// handleFlowListFailedTransactions lists transactions with an error message,
+// newest first.
+// GET /flow/transaction/failed?from_height=&to_height=&cursor=
+func (s *Server) handleFlowListFailedTransactions(w http.ResponseWriter, r *http.Request) {
+	limit, _ := parseLimitOffset(r)
+	var cursor *repository.TxCursor
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		parsed, err := repository.ParseTxCursor(c)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		cursor = parsed
+	}
+	fromHeight, toHeight, err := s.failedTxHeightWindow(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// The cursor bounds the scan from above; narrowing to_height keeps later
+	// pages pruned to the partitions they can still reach.
+	if cursor != nil && cursor.BlockHeight < toHeight {
+		toHeight = cursor.BlockHeight
+	}
+	if toHeight < fromHeight {
+		writeAPIResponse(w, []map[string]interface{}{}, map[string]interface{}{"limit": limit, "count": 0}, nil)
+		return
+	}
+
+	txs, err := s.repo.ListFailedTransactions(r.Context(), fromHeight, toHeight, limit, cursor)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to query failed transactions")
+		return
+	}
+	out := make([]map[string]interface{}, 0, len(txs))
+	for _, t := range txs {
+		o := toFlowTransactionOutput(r.Context(), t, nil, nil, nil, 0)
+		o["error_pattern"] = repository.NormalizeTxError(t.ErrorMessage)
+		out = append(out, o)
+	}
+	meta := map[string]interface{}{
+		"limit":       limit,
+		"count":       len(out),
+		"from_height": fromHeight,
+		"to_height":   toHeight,
+	}
+	if len(txs) == limit {
+		last := txs[len(txs)-1]
+		meta["next_cursor"] = repository.TxCursor{
+			BlockHeight: last.BlockHeight,
+			TxIndex:     last.TransactionIndex,
+			ID:          last.ID,
+		}.String()
+	}
+	writeAPIResponse(w, out, meta, nil)
+}
+
+// handleFlowTransactionTransfers lists the FT and NFT transfers in a single
+// transaction, in event order.
+// GET /flow/transaction/{id}/transfers
+func (s *Server) handleFlowTransactionTransfers(w http.ResponseWriter, r *http.Request) {
+	id := normalizeAddr(mux.Vars(r)["id"])
+	height, transfers, err := s.repo.GetTokenTransfersByTransaction(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "transaction not found")
+		return
+	}
+	out := make([]map[string]interface{}, 0, len(transfers))
+	for _, t := range transfers {
+		item := map[string]interface{}{
+			"type":          t.Type,
+			"token":         t.Token,
+			"contract_name": t.ContractName,
+			"from_address":  formatAddressV1(r.Context(), t.FromAddress),
+			"to_address":    formatAddressV1(r.Context(), t.ToAddress),
+			"event_index":   t.EventIndex,
+		}
+		if t.Type == "nft" {
+			item["token_id"] = t.TokenID
+		} else {
+			item["amount"] = t.Amount
+		}
+		out = append(out, item)
+	}
+	meta := map[string]interface{}{
+		"transaction_id": id,
+		"block_height":   height,
+		"count":          len(out),
+	}
+	writeAPIResponse(w, out, meta, nil)
+}
+
+// handleFlowTopTxErrors groups failed transactions by normalized error message
+// to surface the most common failure causes.
+// GET /flow/stats/errors/top?from_height=&to_height=&limit=
+func (s *Server) handleFlowTopTxErrors(w http.ResponseWriter, r *http.Request) {
+	limit, _ := parseLimitOffset(r)
+	fromHeight, toHeight, err := s.failedTxHeightWindow(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	groups, err := s.repo.GetTopTransactionErrors(r.Context(), fromHeight, toHeight, limit)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to group transaction errors")
+		return
+	}
+	writeAPIResponse(w, groups, map[string]interface{}{
+		"limit":       limit,
+		"count":       len(groups),
+		"from_height": fromHeight,
+		"to_height":   toHeight,
+	}, nil)
+}
@@ -1,15 +1,21 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"flowscan-clone/internal/config"
+	"flowscan-clone/internal/contractid"
+	"flowscan-clone/internal/market"
 	"flowscan-clone/internal/models"
 	"flowscan-clone/internal/repository"
+	"flowscan-clone/internal/ufix64"
 
 	"github.com/onflow/cadence"
 	cadjson "github.com/onflow/cadence/encoding/json"
@@ -51,12 +57,57 @@ func writeAPIError(w http.ResponseWriter, status int, message string) {
 	})
 }
 
+// repoErrorStatus maps repository sentinel errors to HTTP status codes so
+// handlers don't report a missing row or a rejected filter as a 500.
+func repoErrorStatus(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeRepoError writes a repository error with the status chosen by
+// repoErrorStatus. For 404s, notFoundMsg is used instead of the wrapped error text.
+func writeRepoError(w http.ResponseWriter, err error, notFoundMsg string) {
+	status := repoErrorStatus(err)
+	if status == http.StatusNotFound && notFoundMsg != "" {
+		writeAPIError(w, status, notFoundMsg)
+		return
+	}
+	writeAPIError(w, status, err.Error())
+}
+
+// parseLimitOffset reads ?limit= (default 20, clamped to maxLimitDefault) and
+// ?offset=.
 func parseLimitOffset(r *http.Request) (int, int) {
-	limit := 20
+	return parseLimitOffsetWithMax(r, maxLimitDefault)
+}
+
+// Per-endpoint-class caps for ?limit=, used with parseLimitOffsetWithMax.
+const (
+	maxLimitDefault   = 200 // parseLimitOffset
+	maxLimitEvents    = 50  // fully decoded event payloads
+	maxLimitTransfers = 100 // transfers enriched with token metadata
+	maxLimitLight     = 500 // small rows such as block headers
+)
+
+// parseLimitOffsetWithMax is parseLimitOffset with a per-endpoint cap. A limit
+// above maxLimit is clamped to maxLimit rather than ignored; handlers report the
+// returned (effective) limit in _meta.
+func parseLimitOffsetWithMax(r *http.Request, maxLimit int) (int, int) {
+	limit := min(20, maxLimit)
 	offset := 0
 	if v := r.URL.Query().Get("limit"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 200 {
-			limit = n
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = min(n, maxLimit)
 		}
 	}
 	if v := r.URL.Query().Get("offset"); v != "" {
@@ -71,13 +122,26 @@ func parseHeightParam(val string) (*uint64, error) {
 	if val == "" {
 		return nil, nil
 	}
-	n, err := strconv.ParseUint(val, 10, 64)
+	n, err := parseBlockHeight(val)
 	if err != nil {
 		return nil, err
 	}
 	return &n, nil
 }
 
+// parseBlockHeight parses a block height, with a message fit for a 400 that
+// tells a malformed value apart from one too large for a uint64.
+func parseBlockHeight(val string) (uint64, error) {
+	n, err := strconv.ParseUint(val, 10, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("invalid height %q: out of range", val)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid height %q: must be a non-negative integer", val)
+	}
+	return n, nil
+}
+
 func normalizeAddr(addr string) string {
 	addr = strings.TrimSpace(addr)
 	addr = strings.TrimPrefix(strings.ToLower(addr), "0x")
@@ -108,9 +172,12 @@ func normalizeFlowAddr(addr string) string {
 	return addr
 }
 
-func formatAddressV1(addr string) string {
+// formatAddressV1 renders a Flow or EVM address for output in the format the
+// request asked for (see addressFormatFrom): 0x-prefixed by default.
+func formatAddressV1(ctx context.Context, addr string) string {
+	prefix := addressFormatFrom(ctx).prefix()
 	if flow := normalizeFlowAddr(addr); flow != "" {
-		return "0x" + flow
+		return prefix + flow
 	}
 	// Try as EVM/COA address (up to 40 hex chars)
 	hex := normalizeAddr(addr)
@@ -121,18 +188,18 @@ func formatAddressV1(addr string) string {
 			}
 			return ""
 		}
-		return "0x" + hex
+		return prefix + hex
 	}
 	return ""
 }
 
-func formatAddressListV1(addrs []string) []string {
+func formatAddressListV1(ctx context.Context, addrs []string) []string {
 	if len(addrs) == 0 {
 		return addrs
 	}
 	out := make([]string, 0, len(addrs))
 	for _, a := range addrs {
-		out = append(out, formatAddressV1(a))
+		out = append(out, formatAddressV1(ctx, a))
 	}
 	return out
 }
@@ -183,50 +250,19 @@ func parseFloatOrZero(val string) float64 {
 	return f
 }
 
+// splitContractIdentifier parses any contract identifier shape (see package
+// contractid) into its normalized address, name and canonical identifier.
 func splitContractIdentifier(value string) (address, name, identifier string) {
-	value = strings.TrimSpace(value)
-	if value == "" {
-		return "", "", ""
-	}
-	identifier = value
-	parts := strings.Split(value, ".")
-	if len(parts) >= 3 && strings.EqualFold(parts[0], "A") {
-		address = normalizeFlowAddr(parts[1])
-		name = parts[2]
-		return address, name, identifier
-	}
-	if len(parts) == 2 {
-		address = normalizeFlowAddr(parts[0])
-		name = parts[1]
-		identifier = "A." + address + "." + name
-		return address, name, identifier
-	}
-	address = normalizeFlowAddr(value)
-	identifier = address
-	return address, "", identifier
+	id := contractid.Parse(value)
+	return id.Address, id.Name, id.String()
 }
 
 func formatTokenIdentifier(address, name string) string {
-	address = strings.TrimSpace(strings.TrimPrefix(strings.ToLower(address), "0x"))
-	name = strings.TrimSpace(name)
-	if address == "" {
-		return name
-	}
-	if name == "" {
-		return address
-	}
-	return "A." + address + "." + name
+	return contractid.FromParts(address, name).String()
 }
 
 func formatTokenVaultIdentifier(address, name string) string {
-	base := formatTokenIdentifier(address, name)
-	if base == "" {
-		return ""
-	}
-	if strings.Contains(base, ".") && !strings.HasSuffix(base, ".Vault") {
-		return base + ".Vault"
-	}
-	return base
+	return contractid.FromParts(address, name).Vault()
 }
 
 func vaultPathForContract(contractName string) string {
@@ -265,7 +301,7 @@ func toFlowBlockOutput(b models.Block) map[string]interface{} {
 	}
 }
 
-func toFlowEventOutput(e models.Event) map[string]interface{} {
+func toFlowEventOutput(ctx context.Context, e models.Event) map[string]interface{} {
 	out := map[string]interface{}{
 		"type":         e.Type,
 		"transaction":  e.TransactionID,
@@ -296,7 +332,7 @@ func toFlowEventOutput(e models.Event) map[string]interface{} {
 		}
 	}
 	if contractAddr != "" {
-		out["contract_address"] = formatAddressV1(contractAddr)
+		out["contract_address"] = formatAddressV1(ctx, contractAddr)
 	}
 	if contractName != "" {
 		out["contract_name"] = contractName
@@ -307,22 +343,53 @@ func toFlowEventOutput(e models.Event) map[string]interface{} {
 	return out
 }
 
-func toFlowTransactionOutput(t models.Transaction, events []models.Event, contracts []string, tags []string, fee float64, evmExecs ...[]repository.EVMTransactionRecord) map[string]interface{} {
+// decodeTxSigners parses raw.transactions.signers; rows indexed before the
+// column existed (or with malformed JSON) yield nil.
+func decodeTxSigners(raw json.RawMessage) []models.TxSigner {
+	if len(raw) == 0 {
+		return nil
+	}
+	var signers []models.TxSigner
+	if err := json.Unmarshal(raw, &signers); err != nil {
+		return nil
+	}
+	return signers
+}
+
+func toTxSignersOutput(ctx context.Context, signers []models.TxSigner) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(signers))
+	for _, s := range signers {
+		item := map[string]interface{}{
+			"address":   formatAddressV1(ctx, s.Address),
+			"key_index": s.KeyIndex,
+			"roles":     s.Roles,
+			"kind":      s.Kind,
+		}
+		if s.SequenceNumber != nil {
+			item["sequence_number"] = *s.SequenceNumber
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+func toFlowTransactionOutput(ctx context.Context, t models.Transaction, events []models.Event, contracts []string, tags []string, fee float64, evmExecs ...[]repository.EVMTransactionRecord) map[string]interface{} {
 	evOut := make([]map[string]interface{}, 0, len(events))
 	for _, e := range events {
-		evOut = append(evOut, toFlowEventOutput(e))
+		evOut = append(evOut, toFlowEventOutput(ctx, e))
 	}
 	out := map[string]interface{}{
 		"id":                       t.ID,
 		"block_height":             t.BlockHeight,
 		"transaction_index":        t.TransactionIndex,
 		"timestamp":                t.Timestamp.UTC().Format(time.RFC3339),
-		"payer":                    formatAddressV1(t.PayerAddress),
-		"proposer":                 formatAddressV1(t.ProposerAddress),
+		"payer":                    formatAddressV1(ctx, t.PayerAddress),
+		"proposer":                 formatAddressV1(ctx, t.ProposerAddress),
 		"proposer_key_index":       t.ProposerKeyIndex,
 		"proposer_sequence_number": t.ProposerSequenceNumber,
-		"authorizers":              formatAddressListV1(t.Authorizers),
-		"status":                   t.Status,
+		"authorizers":              formatAddressListV1(ctx, t.Authorizers),
+		"signers":                  toTxSignersOutput(ctx, decodeTxSigners(t.Signers)),
+		"status":                   models.NormalizeTxStatus(t.Status, t.ErrorMessage),
 		"error":                    t.ErrorMessage,
 		"gas_used":                 t.GasUsed,
 		"event_count":              t.EventCount,
@@ -346,28 +413,35 @@ func toFlowTransactionOutput(t models.Transaction, events []models.Event, contra
 		out["is_evm"] = true
 	}
 	if t.EVMHash != "" {
-		out["evm_hash"] = formatAddressV1(t.EVMHash)
+		out["evm_hash"] = formatAddressV1(ctx, t.EVMHash)
 	}
 	if t.EVMFrom != "" {
-		out["evm_from"] = formatAddressV1(t.EVMFrom)
+		out["evm_from"] = formatAddressV1(ctx, t.EVMFrom)
 	}
 	if t.EVMTo != "" {
-		out["evm_to"] = formatAddressV1(t.EVMTo)
+		out["evm_to"] = formatAddressV1(ctx, t.EVMTo)
 	}
 	if t.EVMValue != "" {
 		out["evm_value"] = t.EVMValue
 	}
-	if len(evmExecs) > 0 && len(evmExecs[0]) > 0 {
-		execs := make([]map[string]interface{}, 0, len(evmExecs[0]))
-		for _, rec := range evmExecs[0] {
+	var execRecs []repository.EVMTransactionRecord
+	if len(evmExecs) > 0 {
+		execRecs = evmExecs[0]
+	}
+	if len(execRecs) > 0 {
+		execs := make([]map[string]interface{}, 0, len(execRecs))
+		for _, rec := range execRecs {
 			execs = append(execs, toEVMTransactionOutput(rec))
 		}
 		out["evm_executions"] = execs
 	}
+	if evm := buildTxEVMSummary(ctx, t, execRecs); evm != nil {
+		out["evm"] = evm
+	}
 	return out
 }
 
-func toFTListOutput(token models.FTToken) map[string]interface{} {
+func toFTListOutput(ctx context.Context, token models.FTToken) map[string]interface{} {
 	address := token.ContractAddress
 	name := token.ContractName
 	if address == "" || name == "" {
@@ -385,7 +459,7 @@ func toFTListOutput(token models.FTToken) map[string]interface{} {
 	identifier := formatTokenIdentifier(address, name)
 	out := map[string]interface{}{
 		"id":             identifier,
-		"address":        formatAddressV1(address),
+		"address":        formatAddressV1(ctx, address),
 		"contract_name":  name,
 		"name":           token.Name,
 		"symbol":         token.Symbol,
@@ -419,7 +493,12 @@ func toFTListOutput(token models.FTToken) map[string]interface{} {
 		out["socials"] = json.RawMessage(token.Socials)
 	}
 	if token.TotalSupply != "" && token.TotalSupply != "0" {
-		out["total_supply"] = parseFloatOrZero(token.TotalSupply)
+		// Exact decimal string: supplies exceed what float64 represents.
+		supply, ok := ufix64.Normalize(token.TotalSupply)
+		if !ok {
+			supply = token.TotalSupply
+		}
+		out["total_supply"] = supply
 	}
 	if token.DeployedAt != nil {
 		out["deployed_at"] = token.DeployedAt.UTC().Format("2006-01-02T15:04:05Z")
@@ -427,21 +506,21 @@ func toFTListOutput(token models.FTToken) map[string]interface{} {
 	return out
 }
 
-func toFTHoldingOutput(holding models.FTHolding, percentage float64) map[string]interface{} {
+func toFTHoldingOutput(ctx context.Context, holding models.FTHolding, percentage float64) map[string]interface{} {
 	tokenIdentifier := formatTokenIdentifier(holding.ContractAddress, holding.ContractName)
 	return map[string]interface{}{
-		"address":    formatAddressV1(holding.Address),
+		"address":    formatAddressV1(ctx, holding.Address),
 		"token":      tokenIdentifier,
 		"balance":    parseFloatOrZero(holding.Balance),
 		"percentage": percentage,
 	}
 }
 
-func toVaultOutput(holding models.FTHolding) map[string]interface{} {
+func toVaultOutput(ctx context.Context, holding models.FTHolding) map[string]interface{} {
 	return map[string]interface{}{
 		"id":           holding.Address + ":" + formatTokenIdentifier(holding.ContractAddress, holding.ContractName),
 		"vault_id":     0,
-		"address":      formatAddressV1(holding.Address),
+		"address":      formatAddressV1(ctx, holding.Address),
 		"token":        formatTokenIdentifier(holding.ContractAddress, holding.ContractName),
 		"balance":      parseFloatOrZero(holding.Balance),
 		"block_height": holding.LastHeight,
@@ -449,7 +528,7 @@ func toVaultOutput(holding models.FTHolding) map[string]interface{} {
 	}
 }
 
-func toNFTCollectionOutput(summary repository.NFTCollectionSummary) map[string]interface{} {
+func toNFTCollectionOutput(ctx context.Context, summary repository.NFTCollectionSummary) map[string]interface{} {
 	address := summary.ContractAddress
 	name := summary.ContractName
 	if address == "" || name == "" {
@@ -467,7 +546,7 @@ func toNFTCollectionOutput(summary repository.NFTCollectionSummary) map[string]i
 	identifier := formatTokenIdentifier(address, name)
 	return map[string]interface{}{
 		"id":               identifier,
-		"address":          formatAddressV1(address),
+		"address":          formatAddressV1(ctx, address),
 		"contract_name":    name,
 		"name":             summary.Name,
 		"display_name":     summary.Name,
@@ -488,20 +567,20 @@ func toNFTCollectionOutput(summary repository.NFTCollectionSummary) map[string]i
 	}
 }
 
-func toNFTHoldingOutput(owner string, count int64, percentage float64, nftType string) map[string]interface{} {
+func toNFTHoldingOutput(ctx context.Context, owner string, count int64, percentage float64, nftType string) map[string]interface{} {
 	return map[string]interface{}{
-		"owner":      formatAddressV1(owner),
+		"owner":      formatAddressV1(ctx, owner),
 		"nft_type":   nftType,
 		"count":      count,
 		"percentage": percentage,
 	}
 }
 
-func toCombinedNFTDetails(ownership models.NFTOwnership) map[string]interface{} {
+func toCombinedNFTDetails(ctx context.Context, ownership models.NFTOwnership) map[string]interface{} {
 	return map[string]interface{}{
 		"id":           ownership.NFTID,
 		"nft_id":       ownership.NFTID,
-		"owner":        formatAddressV1(ownership.Owner),
+		"owner":        formatAddressV1(ctx, ownership.Owner),
 		"type":         ownership.ContractAddress,
 		"block_height": ownership.LastHeight,
 		"timestamp":    formatTime(ownership.UpdatedAt),
@@ -510,7 +589,7 @@ func toCombinedNFTDetails(ownership models.NFTOwnership) map[string]interface{}
 	}
 }
 
-func toContractOutput(contract models.SmartContract, dependentCounts ...map[string]int64) map[string]interface{} {
+func toContractOutput(ctx context.Context, contract models.SmartContract, dependentCounts ...map[string]int64) map[string]interface{} {
 	identifier := formatTokenIdentifier(contract.Address, contract.Name)
 	depCount := int64(contract.DependentCount)
 	if depCount == 0 && len(dependentCounts) > 0 && dependentCounts[0] != nil {
@@ -519,7 +598,7 @@ func toContractOutput(contract models.SmartContract, dependentCounts ...map[stri
 	out := map[string]interface{}{
 		"id":                 identifier,
 		"identifier":         identifier,
-		"address":            formatAddressV1(contract.Address),
+		"address":            formatAddressV1(ctx, contract.Address),
 		"name":               contract.Name,
 		"body":               contract.Code,
 		"created_at":         formatTime(contract.CreatedAt),
@@ -614,7 +693,11 @@ func toEVMTransactionOutput(rec repository.EVMTransactionRecord) map[string]inte
 	}
 	status := rec.Status
 	if status == "" {
+		// Rows indexed before status was derived still carry the errorCode.
 		status = "SEALED"
+		if rec.StatusCode != 0 {
+			status = "FAILED"
+		}
 	}
 	out := map[string]interface{}{
 		"block_number": rec.BlockHeight,
@@ -641,9 +724,9 @@ func toEVMTransactionOutput(rec repository.EVMTransactionRecord) map[string]inte
 	return out
 }
 
-func toEVMTokenOutput(rec repository.EVMTokenSummary) map[string]interface{} {
+func toEVMTokenOutput(ctx context.Context, rec repository.EVMTokenSummary) map[string]interface{} {
 	return map[string]interface{}{
-		"address":        formatAddressV1(rec.Address),
+		"address":        formatAddressV1(ctx, rec.Address),
 		"name":           rec.Name,
 		"symbol":         rec.Symbol,
 		"decimals":       rec.Decimals,
@@ -670,32 +753,49 @@ func transferDirection(addrFilter, from, to string) string {
 	return "deposit"
 }
 
-func toFTTransferOutput(t models.TokenTransfer, contractName, addrFilter string, meta *repository.TokenMetadataInfo, usdPrice float64) map[string]interface{} {
+func toFTTransferOutput(ctx context.Context, t models.TokenTransfer, contractName, addrFilter string, meta *repository.TokenMetadataInfo, usdPrice float64) map[string]interface{} {
 	tokenIdentifier := formatTokenVaultIdentifier(t.TokenContractAddress, contractName)
 	tokenName := ""
 	tokenSymbol := ""
 	var tokenLogo interface{} = ""
-	if meta != nil && meta.Name != "" {
-		tokenName = meta.Name
-		tokenSymbol = meta.Symbol
-		if meta.Logo != "" {
-			tokenLogo = meta.Logo
-		}
-	} else if contractName == "FlowToken" {
+	if contractName == "FlowToken" {
 		tokenName = "Flow"
 		tokenSymbol = "FLOW"
 		tokenLogo = "https://cdn.jsdelivr.net/gh/FlowFans/flow-token-list@main/token-registry/A." + config.Addr().FlowToken + ".FlowToken/logo.svg"
-	} else if contractName != "" {
+	}
+	// Fields the token table has win over the FlowToken defaults; a row whose
+	// metadata has not been fetched yet leaves them in place.
+	if meta != nil {
+		if meta.Name != "" {
+			tokenName = meta.Name
+		}
+		if meta.Symbol != "" {
+			tokenSymbol = meta.Symbol
+		}
+		if meta.Logo != "" {
+			tokenLogo = meta.Logo
+		}
+	}
+	if tokenName == "" {
 		tokenName = contractName
 	}
+	token := map[string]interface{}{
+		"token":  tokenIdentifier,
+		"name":   tokenName,
+		"symbol": tokenSymbol,
+		"logo":   tokenLogo,
+	}
+	if meta != nil && meta.Decimals > 0 {
+		token["decimals"] = meta.Decimals
+	}
 	return map[string]interface{}{
-		"address":          formatAddressV1(addrFilter),
+		"address":          formatAddressV1(ctx, addrFilter),
 		"transaction_hash": t.TransactionID,
 		"block_height":     t.BlockHeight,
 		"timestamp":        formatTime(t.Timestamp),
 		"amount":           parseFloatOrZero(t.Amount),
-		"sender":           formatAddressV1(t.FromAddress),
-		"receiver":         formatAddressV1(t.ToAddress),
+		"sender":           formatAddressV1(ctx, t.FromAddress),
+		"receiver":         formatAddressV1(ctx, t.ToAddress),
 		"direction":        transferDirection(addrFilter, t.FromAddress, t.ToAddress),
 		"verified":         false,
 		"is_primary":       false,
@@ -703,16 +803,11 @@ func toFTTransferOutput(t models.TokenTransfer, contractName, addrFilter string,
 		"approx_usd_price": usdPrice,
 		"usd_value":        parseFloatOrZero(t.Amount) * usdPrice,
 		"receiver_balance": 0,
-		"token": map[string]interface{}{
-			"token":  tokenIdentifier,
-			"name":   tokenName,
-			"symbol": tokenSymbol,
-			"logo":   tokenLogo,
-		},
+		"token":            token,
 	}
 }
 
-func toNFTTransferOutput(t models.TokenTransfer, contractName, addrFilter string, meta *repository.TokenMetadataInfo) map[string]interface{} {
+func toNFTTransferOutput(ctx context.Context, t models.TokenTransfer, contractName, addrFilter string, meta *repository.TokenMetadataInfo) map[string]interface{} {
 	nftType := formatTokenIdentifier(t.TokenContractAddress, contractName)
 	out := map[string]interface{}{
 		"transaction_hash": t.TransactionID,
@@ -720,9 +815,9 @@ func toNFTTransferOutput(t models.TokenTransfer, contractName, addrFilter string
 		"timestamp":        formatTime(t.Timestamp),
 		"nft_type":         nftType,
 		"nft_id":           t.TokenID,
-		"sender":           formatAddressV1(t.FromAddress),
-		"receiver":         formatAddressV1(t.ToAddress),
-		"current_owner":    formatAddressV1(t.ToAddress),
+		"sender":           formatAddressV1(ctx, t.FromAddress),
+		"receiver":         formatAddressV1(ctx, t.ToAddress),
+		"current_owner":    formatAddressV1(ctx, t.ToAddress),
 		"direction":        transferDirection(addrFilter, t.FromAddress, t.ToAddress),
 		"verified":         false,
 		"is_primary":       false,
@@ -746,7 +841,7 @@ func toNFTTransferOutput(t models.TokenTransfer, contractName, addrFilter string
 	return out
 }
 
-func toTransferSummaryOutput(s repository.TransferSummary, ftMeta, nftMeta map[string]repository.TokenMetadataInfo, ftPrices map[string]float64) map[string]interface{} {
+func toTransferSummaryOutput(ctx context.Context, s repository.TransferSummary, ftMeta, nftMeta map[string]repository.TokenMetadataInfo, ftPrices map[string]float64) map[string]interface{} {
 	ft := make([]map[string]interface{}, 0, len(s.FT))
 	for _, f := range s.FT {
 		item := map[string]interface{}{
@@ -755,7 +850,7 @@ func toTransferSummaryOutput(s repository.TransferSummary, ftMeta, nftMeta map[s
 			"direction": f.Direction,
 		}
 		if f.Counterparty != "" && (f.Direction == "in" || f.Direction == "out") && !strings.ContainsAny(f.Counterparty, ",>") {
-			item["counterparty"] = formatAddressV1(f.Counterparty)
+			item["counterparty"] = formatAddressV1(ctx, f.Counterparty)
 		}
 		if m, ok := ftMeta[f.Token]; ok {
 			if m.Symbol != "" {
@@ -781,7 +876,7 @@ func toTransferSummaryOutput(s repository.TransferSummary, ftMeta, nftMeta map[s
 			"direction":  n.Direction,
 		}
 		if n.Counterparty != "" && (n.Direction == "in" || n.Direction == "out") && !strings.ContainsAny(n.Counterparty, ",>") {
-			item["counterparty"] = formatAddressV1(n.Counterparty)
+			item["counterparty"] = formatAddressV1(ctx, n.Counterparty)
 		}
 		if m, ok := nftMeta[n.Collection]; ok {
 			if m.Name != "" {
@@ -802,10 +897,10 @@ func toTransferSummaryOutput(s repository.TransferSummary, ftMeta, nftMeta map[s
 	}
 }
 
-func toFlowTransactionOutputWithTransfers(t models.Transaction, events []models.Event, contracts []string, tags []string, fee float64, transfers *repository.TransferSummary, ftMeta, nftMeta map[string]repository.TokenMetadataInfo, ftPrices map[string]float64) map[string]interface{} {
-	out := toFlowTransactionOutput(t, events, contracts, tags, fee)
+func toFlowTransactionOutputWithTransfers(ctx context.Context, t models.Transaction, events []models.Event, contracts []string, tags []string, fee float64, transfers *repository.TransferSummary, ftMeta, nftMeta map[string]repository.TokenMetadataInfo, ftPrices map[string]float64) map[string]interface{} {
+	out := toFlowTransactionOutput(ctx, t, events, contracts, tags, fee)
 	if transfers != nil {
-		out["transfer_summary"] = toTransferSummaryOutput(*transfers, ftMeta, nftMeta, ftPrices)
+		out["transfer_summary"] = toTransferSummaryOutput(ctx, *transfers, ftMeta, nftMeta, ftPrices)
 	} else {
 		out["transfer_summary"] = map[string]interface{}{"ft": []interface{}{}, "nft": []interface{}{}}
 	}
@@ -833,6 +928,57 @@ func (s *Server) buildFTPrices(ftMeta map[string]repository.TokenMetadataInfo, t
 	return prices
 }
 
+// ftHoldingMarketSymbol returns the price symbol for a holding, falling back
+// to FLOW for FlowToken like the transfer endpoints do.
+func ftHoldingMarketSymbol(meta map[string]repository.TokenMetadataInfo, contractAddress, contractName string) string {
+	if m, ok := meta[formatTokenIdentifier(contractAddress, contractName)]; ok && m.MarketSymbol != "" {
+		return m.MarketSymbol
+	}
+	if contractName == "FlowToken" {
+		return "FLOW"
+	}
+	return ""
+}
+
+// addFTHoldingValuation sets usd_price, usd_value, price_as_of and is_stale on
+// a holding output. Values priced from a stale quote are null when
+// staleAsNull is set; unpriced holdings get nulls.
+func addFTHoldingValuation(out map[string]interface{}, balance float64, q market.LatestQuote, found, staleAsNull bool) {
+	out["usd_price"], out["usd_value"], out["price_as_of"], out["is_stale"] = nil, nil, nil, false
+	if !found {
+		return
+	}
+	out["price_as_of"] = formatTime(q.AsOf)
+	out["is_stale"] = q.Stale
+	if q.Stale && staleAsNull {
+		return
+	}
+	out["usd_price"] = q.Price
+	out["usd_value"] = balance * q.Price
+}
+
+// valueFTHoldings adds USD valuations to holding outputs, keyed by the same
+// index as holdings.
+func (s *Server) valueFTHoldings(ctx context.Context, out []map[string]interface{}, holdings []models.FTHolding) {
+	if s.priceCache == nil || len(holdings) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(holdings))
+	for _, h := range holdings {
+		ids = append(ids, formatTokenIdentifier(h.ContractAddress, h.ContractName))
+	}
+	meta, _ := s.repo.GetFTTokenMetadataByIdentifiers(ctx, ids)
+	now := time.Now()
+	for i, h := range holdings {
+		var q market.LatestQuote
+		var found bool
+		if sym := ftHoldingMarketSymbol(meta, h.ContractAddress, h.ContractName); sym != "" {
+			q, found = s.priceCache.GetLatestQuote(sym, now)
+		}
+		addFTHoldingValuation(out[i], parseFloatOrZero(h.Balance), q, found, s.stalePriceAsNull)
+	}
+}
+
 // collectTransferTokenIDs extracts unique token identifiers from a transfer list.
 func collectTransferTokenIDs(transfers []repository.TokenTransferWithContract, isNFT bool) []string {
 	seen := make(map[string]bool)
@@ -873,7 +1019,7 @@ func collectTokenIdentifiers(summaries map[string]repository.TransferSummary) (f
 	return
 }
 
-func toNFTItemOutput(item models.NFTItem) map[string]interface{} {
+func toNFTItemOutput(ctx context.Context, item models.NFTItem) map[string]interface{} {
 	nftType := formatTokenIdentifier(item.ContractAddress, item.ContractName)
 	out := map[string]interface{}{
 		"id":           item.NFTID,
@@ -907,7 +1053,7 @@ func toNFTItemOutput(item models.NFTItem) map[string]interface{} {
 		out["traits"] = json.RawMessage(item.Traits)
 	}
 	if item.Owner != "" {
-		out["current_owner"] = formatAddressV1(item.Owner)
+		out["current_owner"] = formatAddressV1(ctx, item.Owner)
 	}
 	return out
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -93,7 +94,27 @@ var upgrader = websocket.Upgrader{
 	WriteBufferSize: 1024,
 }
 
+// handleWebSocket streams new_block / new_transaction messages. With
+// ?since=<height> it first replays stored blocks from that height (see
+// replayBlocks) so reconnecting clients get a gap-free block stream.
+// Transactions are not replayed.
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	var since *uint64
+	if v := strings.TrimSpace(r.URL.Query().Get("since")); v != "" {
+		h, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "since must be a block height")
+			return
+		}
+		if s.repo == nil {
+			writeAPIError(w, http.StatusServiceUnavailable, "block replay unavailable")
+			return
+		}
+		since = &h
+	}
+
+	// The socket outlives the server's request timeouts; the WS loop manages its own deadlines.
+	clearConnDeadlines(w)
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Println("WebSocket upgrade error:", err)
@@ -106,19 +127,37 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		send: make(chan []byte, 256),
 	}
 
-	hub.register <- client
+	register := func() { hub.register <- client }
 
 	go func() {
 		defer func() {
 			hub.unregister <- client
 			conn.Close()
 		}()
+		var cursor wsBlockCursor
+		if since != nil {
+			// r's context stays live until the read loop below sees the
+			// client go away, so a disconnect cancels the replay query.
+			// replayBlocks registers the client once the stored blocks are
+			// written; the cursor then drops live blocks already replayed.
+			next, err := s.replayBlocks(r.Context(), conn, *since, register)
+			if err != nil {
+				writeWSMessage(conn, "error", map[string]interface{}{"message": err.Error()})
+				return
+			}
+			cursor = wsBlockCursor{next: next, active: true}
+		} else {
+			register()
+		}
 		for {
 			message, ok := <-client.send
 			if !ok {
 				conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
+			if cursor.skip(message) {
+				continue
+			}
 			w, err := conn.NextWriter(websocket.TextMessage)
 			if err != nil {
 				return
@@ -169,6 +208,12 @@ type WSTransaction struct {
 }
 
 func BroadcastNewBlock(block models.Block) {
+	data := newBlockMessage(block)
+	recentBlocks.add(block.Height, data)
+	hub.broadcast <- data
+}
+
+func newBlockMessage(block models.Block) []byte {
 	ts := block.Timestamp
 	if ts.IsZero() {
 		ts = block.CreatedAt
@@ -185,7 +230,7 @@ func BroadcastNewBlock(block models.Block) {
 	}
 	msg := BroadcastMessage{Type: "new_block", Payload: payload}
 	data, _ := json.Marshal(msg)
-	hub.broadcast <- data
+	return data
 }
 
 func BroadcastNewTransaction(tx models.Transaction) {
@@ -212,9 +257,16 @@ func BroadcastNewTransaction(tx models.Transaction) {
 
 // MakeBroadcastNewTransactions returns a batch broadcast callback that enriches
 // transactions with template_category, template_label, and tags derived from
-// events before broadcasting over WebSocket.
+// events before broadcasting over WebSocket. Transactions broadcast within
+// the last WS_TX_DEDUP_WINDOW ids are skipped.
 func MakeBroadcastNewTransactions(repo *repository.Repository) func([]models.Transaction, []models.Event) {
+	dedup := newTxDedupLRU(envInt("WS_TX_DEDUP_WINDOW", defaultWSTxDedupWindow))
+	return makeBroadcastNewTransactions(repo, dedup, func(data []byte) { hub.broadcast <- data })
+}
+
+func makeBroadcastNewTransactions(repo *repository.Repository, dedup *txDedupLRU, send func([]byte)) func([]models.Transaction, []models.Event) {
 	return func(txs []models.Transaction, events []models.Event) {
+		txs = dedup.filter(txs)
 		if len(txs) == 0 {
 			return
 		}
@@ -298,7 +350,7 @@ func MakeBroadcastNewTransactions(repo *repository.Repository) func([]models.Tra
 			}
 			msg := BroadcastMessage{Type: "new_transaction", Payload: payload}
 			data, _ := json.Marshal(msg)
-			hub.broadcast <- data
+			send(data)
 		}
 	}
 }
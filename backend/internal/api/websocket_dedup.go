@@ -0,0 +1,56 @@
+package api
+
+import (
+	"container/list"
+	"sync"
+
+	"flowscan-clone/internal/models"
+)
+
+// defaultWSTxDedupWindow is how many recently broadcast transaction ids are
+// remembered to suppress duplicate new_transaction messages. Forward ingestion
+// and the live deriver's head backfill can hand over the same blocks, which
+// made rows flicker or repeat in the UI. Override with WS_TX_DEDUP_WINDOW;
+// 0 disables deduplication.
+const defaultWSTxDedupWindow = 4096
+
+// txDedupLRU is a fixed-size LRU set of transaction ids. A nil or zero-size
+// LRU lets everything through.
+type txDedupLRU struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // front = most recently seen id
+	index map[string]*list.Element
+}
+
+func newTxDedupLRU(size int) *txDedupLRU {
+	if size <= 0 {
+		return nil
+	}
+	return &txDedupLRU{size: size, order: list.New(), index: make(map[string]*list.Element, size)}
+}
+
+// filter returns the transactions whose ids were not seen within the window,
+// including repeats inside txs itself, and records them as seen.
+func (d *txDedupLRU) filter(txs []models.Transaction) []models.Transaction {
+	if d == nil {
+		return txs
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := txs[:0:0]
+	for _, tx := range txs {
+		if el, ok := d.index[tx.ID]; ok {
+			d.order.MoveToFront(el)
+			continue
+		}
+		d.index[tx.ID] = d.order.PushFront(tx.ID)
+		if d.order.Len() > d.size {
+			oldest := d.order.Back()
+			d.order.Remove(oldest)
+			delete(d.index, oldest.Value.(string))
+		}
+		out = append(out, tx)
+	}
+	return out
+}
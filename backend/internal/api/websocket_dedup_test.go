@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"flowscan-clone/internal/models"
+)
+
+func TestBroadcastNewTransactionsSuppressesDuplicates(t *testing.T) {
+	t.Parallel()
+
+	var sent []string
+	broadcast := makeBroadcastNewTransactions(nil, newTxDedupLRU(8), func(data []byte) {
+		var msg struct {
+			Payload WSTransaction `json:"payload"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, msg.Payload.ID)
+	})
+
+	tx := models.Transaction{ID: "aa11", BlockHeight: 100}
+	// Forward ingestion and the head backfill both deliver block 100.
+	broadcast([]models.Transaction{tx}, nil)
+	broadcast([]models.Transaction{tx}, nil)
+	if len(sent) != 1 || sent[0] != "aa11" {
+		t.Fatalf("broadcasts = %v; want one for aa11", sent)
+	}
+
+	broadcast([]models.Transaction{tx, {ID: "bb22", BlockHeight: 101}, {ID: "bb22", BlockHeight: 101}}, nil)
+	if len(sent) != 2 || sent[1] != "bb22" {
+		t.Fatalf("broadcasts = %v; want aa11, bb22", sent)
+	}
+}
+
+func TestTxDedupLRUEvictsOldest(t *testing.T) {
+	t.Parallel()
+
+	d := newTxDedupLRU(2)
+	ids := func(txs []models.Transaction) []string {
+		var out []string
+		for _, tx := range txs {
+			out = append(out, tx.ID)
+		}
+		return out
+	}
+	d.filter([]models.Transaction{{ID: "a"}, {ID: "b"}})
+	d.filter([]models.Transaction{{ID: "a"}}) // refreshes a
+	if got := ids(d.filter([]models.Transaction{{ID: "c"}})); len(got) != 1 {
+		t.Fatalf("c filtered = %v", got)
+	}
+	// b was least recently seen and has been evicted; a is still remembered.
+	if got := ids(d.filter([]models.Transaction{{ID: "a"}, {ID: "b"}})); len(got) != 1 || got[0] != "b" {
+		t.Fatalf("after eviction = %v; want [b]", got)
+	}
+
+	if got := ids(newTxDedupLRU(0).filter([]models.Transaction{{ID: "x"}, {ID: "x"}})); len(got) != 2 {
+		t.Fatalf("disabled dedup = %v; want both", got)
+	}
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"flowscan-clone/internal/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// defaultWSMaxReplayBlocks bounds how many blocks a ?since= reconnect may
+// replay. Larger gaps are rejected and the client should resync over REST.
+// Override with WS_MAX_REPLAY_BLOCKS.
+const defaultWSMaxReplayBlocks = 1000
+
+// wsRecentBlockCount is how many recently broadcast blocks are remembered for
+// the replay handoff.
+const wsRecentBlockCount = 64
+
+// wsBlockMsg is an encoded new_block message and its height.
+type wsBlockMsg struct {
+	height uint64
+	data   []byte
+}
+
+// wsBlockSource loads stored blocks for replay; *repository.Repository
+// satisfies it.
+type wsBlockSource interface {
+	GetBlocksFromHeight(ctx context.Context, fromHeight uint64, limit int) ([]models.Block, error)
+}
+
+// recentBlockRing remembers the last broadcast blocks. The ingester broadcasts
+// a block before its batch commits, so a block broadcast just before a client
+// registers may not be visible to the replay query yet; the ring covers it.
+type recentBlockRing struct {
+	mu     sync.Mutex
+	blocks []wsBlockMsg
+}
+
+var recentBlocks = &recentBlockRing{}
+
+func (r *recentBlockRing) add(height uint64, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blocks = append(r.blocks, wsBlockMsg{height: height, data: data})
+	if len(r.blocks) > wsRecentBlockCount {
+		r.blocks = r.blocks[len(r.blocks)-wsRecentBlockCount:]
+	}
+}
+
+func (r *recentBlockRing) snapshot() []wsBlockMsg {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]wsBlockMsg(nil), r.blocks...)
+}
+
+// buildBlockReplay merges stored blocks from since onwards with recently
+// broadcast ones, ordered by height without duplicates. It fails when more
+// than max blocks would be replayed.
+func buildBlockReplay(ctx context.Context, src wsBlockSource, since uint64, recent []wsBlockMsg, max int) ([]wsBlockMsg, error) {
+	stored, err := src.GetBlocksFromHeight(ctx, since, max+1)
+	if err != nil {
+		return nil, fmt.Errorf("load replay blocks: %w", err)
+	}
+
+	byHeight := make(map[uint64]wsBlockMsg, len(stored)+len(recent))
+	for _, m := range recent {
+		if m.height >= since {
+			byHeight[m.height] = m
+		}
+	}
+	for _, b := range stored {
+		if _, ok := byHeight[b.Height]; !ok {
+			byHeight[b.Height] = wsBlockMsg{height: b.Height, data: newBlockMessage(b)}
+		}
+	}
+	if len(byHeight) > max {
+		return nil, fmt.Errorf("replay gap too large: more than %d blocks since height %d", max, since)
+	}
+
+	out := make([]wsBlockMsg, 0, len(byHeight))
+	for _, m := range byHeight {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].height < out[j].height })
+	return out, nil
+}
+
+// recentBlocksFrom returns the ring blocks at or above next, ordered by
+// height.
+func recentBlocksFrom(recent []wsBlockMsg, next uint64) []wsBlockMsg {
+	var out []wsBlockMsg
+	for _, m := range recent {
+		if m.height >= next {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].height < out[j].height })
+	return out
+}
+
+// replayBlocks writes the blocks from since to the current tip and a final
+// replay_complete message. It returns the next height the live stream must
+// deliver. Only new_block messages are replayed; transactions resume with the
+// live stream.
+//
+// The stored replay can be long (up to WS_MAX_REPLAY_BLOCKS plus the query),
+// so it runs before register adds the client to the hub: a registered client
+// whose send buffer fills is dropped. Blocks broadcast between the stored
+// replay and registration are then taken from the recent-block ring. ctx
+// should be the upgrade request's context so the replay query is cancelled
+// when the client disconnects.
+func (s *Server) replayBlocks(ctx context.Context, conn *websocket.Conn, since uint64, register func()) (uint64, error) {
+	recent := recentBlocks.snapshot()
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	replay, err := buildBlockReplay(ctx, s.repo, since, recent, envInt("WS_MAX_REPLAY_BLOCKS", defaultWSMaxReplayBlocks))
+	if err != nil {
+		return 0, err
+	}
+	next := since
+	for _, m := range replay {
+		if err := conn.WriteMessage(websocket.TextMessage, m.data); err != nil {
+			return 0, err
+		}
+		next = m.height + 1
+	}
+
+	register()
+	count := len(replay)
+	for _, m := range recentBlocksFrom(recentBlocks.snapshot(), next) {
+		if err := conn.WriteMessage(websocket.TextMessage, m.data); err != nil {
+			return 0, err
+		}
+		next = m.height + 1
+		count++
+	}
+	writeWSMessage(conn, "replay_complete", map[string]interface{}{
+		"since": since,
+		"count": count,
+		"next":  next,
+	})
+	return next, nil
+}
+
+// wsBlockCursor drops live new_block messages already covered by a replay.
+// Once a block at or above next arrives the live stream has caught up and
+// filtering stops.
+type wsBlockCursor struct {
+	next   uint64
+	active bool
+}
+
+func (c *wsBlockCursor) skip(message []byte) bool {
+	if !c.active {
+		return false
+	}
+	var msg struct {
+		Type    string `json:"type"`
+		Payload struct {
+			Height uint64 `json:"height"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Type != "new_block" {
+		return false
+	}
+	if msg.Payload.Height < c.next {
+		return true
+	}
+	c.active = false
+	return false
+}
+
+func writeWSMessage(conn *websocket.Conn, typ string, payload interface{}) {
+	data, _ := json.Marshal(BroadcastMessage{Type: typ, Payload: payload})
+	conn.WriteMessage(websocket.TextMessage, data)
+}
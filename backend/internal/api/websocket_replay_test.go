@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+type fakeWSBlockSource struct {
+	blocks []models.Block
+}
+
+func (f fakeWSBlockSource) GetBlocksFromHeight(_ context.Context, from uint64, limit int) ([]models.Block, error) {
+	var out []models.Block
+	for _, b := range f.blocks {
+		if b.Height >= from && len(out) < limit {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+// blockingWSBlockSource behaves like a slow query: it returns only once ctx
+// is done.
+type blockingWSBlockSource struct{}
+
+func (blockingWSBlockSource) GetBlocksFromHeight(ctx context.Context, _ uint64, _ int) ([]models.Block, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func blocksBetween(from, to uint64) []models.Block {
+	var out []models.Block
+	for h := from; h <= to; h++ {
+		out = append(out, models.Block{Height: h, ID: "id"})
+	}
+	return out
+}
+
+func wsMessageHeight(t *testing.T, data []byte) (string, uint64) {
+	t.Helper()
+	var msg struct {
+		Type    string `json:"type"`
+		Payload struct {
+			Height      uint64 `json:"height"`
+			BlockHeight uint64 `json:"block_height"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	if msg.Type == "new_transaction" {
+		return msg.Type, msg.Payload.BlockHeight
+	}
+	return msg.Type, msg.Payload.Height
+}
+
+func TestWSReplayThenLiveHandoff(t *testing.T) {
+	t.Parallel()
+
+	// Stored blocks run to 14. Block 15 was broadcast but its batch has not
+	// committed yet, so only the recent-block ring knows about it.
+	src := fakeWSBlockSource{blocks: blocksBetween(1, 14)}
+	recent := []wsBlockMsg{
+		{height: 14, data: newBlockMessage(models.Block{Height: 14})},
+		{height: 15, data: newBlockMessage(models.Block{Height: 15})},
+	}
+
+	replay, err := buildBlockReplay(context.Background(), src, 10, recent, 100)
+	if err != nil {
+		t.Fatalf("buildBlockReplay: %v", err)
+	}
+
+	var got []uint64
+	next := uint64(10)
+	for _, m := range replay {
+		got = append(got, m.height)
+		next = m.height + 1
+	}
+
+	// Live messages buffered while the replay ran overlap it.
+	txMsg, _ := json.Marshal(BroadcastMessage{Type: "new_transaction", Payload: WSTransaction{ID: "tx", BlockHeight: 15}})
+	live := [][]byte{
+		newBlockMessage(models.Block{Height: 14}),
+		newBlockMessage(models.Block{Height: 15}),
+		txMsg,
+		newBlockMessage(models.Block{Height: 16}),
+		newBlockMessage(models.Block{Height: 17}),
+	}
+	cursor := wsBlockCursor{next: next, active: true}
+	var liveTypes []string
+	for _, m := range live {
+		if cursor.skip(m) {
+			continue
+		}
+		typ, h := wsMessageHeight(t, m)
+		liveTypes = append(liveTypes, typ)
+		if typ == "new_block" {
+			got = append(got, h)
+		}
+	}
+
+	want := []uint64{10, 11, 12, 13, 14, 15, 16, 17}
+	if len(got) != len(want) {
+		t.Fatalf("block heights = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("block heights = %v, want %v", got, want)
+		}
+	}
+	if strings.Join(liveTypes, ",") != "new_transaction,new_block,new_block" {
+		t.Fatalf("live messages = %v", liveTypes)
+	}
+}
+
+func TestWSReplayRejectsLargeGap(t *testing.T) {
+	t.Parallel()
+
+	src := fakeWSBlockSource{blocks: blocksBetween(1, 50)}
+	if _, err := buildBlockReplay(context.Background(), src, 1, nil, 10); err == nil {
+		t.Fatal("expected gap error")
+	}
+	replay, err := buildBlockReplay(context.Background(), src, 41, nil, 10)
+	if err != nil || len(replay) != 10 {
+		t.Fatalf("replay = %d blocks, err %v", len(replay), err)
+	}
+}
+
+func TestWSReplayStopsWhenClientGoesAway(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := buildBlockReplay(ctx, blockingWSBlockSource{}, 1, nil, 10)
+		done <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("err = %v; want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("replay did not stop after the context was cancelled")
+	}
+}
+
+func TestWSRecentBlocksCoverRegistrationGap(t *testing.T) {
+	t.Parallel()
+
+	// The stored replay ended at 14. Blocks 15 and 16 were broadcast before the
+	// client registered, so only the ring has them; 16 also reached the client
+	// after registration and the cursor must drop it.
+	ring := []wsBlockMsg{
+		{height: 16, data: newBlockMessage(models.Block{Height: 16})},
+		{height: 13, data: newBlockMessage(models.Block{Height: 13})},
+		{height: 15, data: newBlockMessage(models.Block{Height: 15})},
+	}
+	var got []uint64
+	next := uint64(15)
+	for _, m := range recentBlocksFrom(ring, next) {
+		got = append(got, m.height)
+		next = m.height + 1
+	}
+	cursor := wsBlockCursor{next: next, active: true}
+	for _, h := range []uint64{16, 17} {
+		if !cursor.skip(newBlockMessage(models.Block{Height: h})) {
+			got = append(got, h)
+		}
+	}
+	if want := "[15 16 17]"; fmt.Sprint(got) != want {
+		t.Fatalf("block heights = %v, want %s", got, want)
+	}
+}
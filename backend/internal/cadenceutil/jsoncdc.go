@@ -0,0 +1,188 @@
+// Package cadenceutil converts Cadence JSON-CDC values into plain Go values
+// (maps, slices, strings), as stored in raw.events payloads.
+package cadenceutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseEventPayload parses a JSON-CDC event payload into a flat map without
+// using the Cadence type system. This avoids "Restriction kind is not
+// supported" panics. It returns nil if payload is not a JSON-CDC event.
+func ParseEventPayload(payload []byte) map[string]interface{} {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil
+	}
+
+	value, ok := raw["value"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	fields, ok := value["fields"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		field, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := field["name"].(string)
+		if name == "" {
+			continue
+		}
+		val, _ := field["value"].(map[string]interface{})
+		if val == nil {
+			continue
+		}
+		result[name] = FlattenValue(val)
+	}
+	return result
+}
+
+// FlattenValue recursively extracts a plain value from a JSON-CDC
+// {"type", "value"} wrapper.
+func FlattenValue(v map[string]interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	typ, _ := v["type"].(string)
+	value := v["value"]
+
+	switch typ {
+	case "Optional":
+		if value == nil {
+			return nil
+		}
+		inner, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		return FlattenValue(inner)
+
+	case "Bool":
+		return value
+
+	case "String", "Character":
+		return value
+
+	case "Address":
+		// Strip 0x prefix to match SDK's .Hex() output
+		if s, ok := value.(string); ok {
+			return strings.TrimPrefix(s, "0x")
+		}
+		return value
+
+	case "UInt8", "UInt16", "UInt32", "UInt64", "UInt128", "UInt256",
+		"Int8", "Int16", "Int32", "Int64", "Int128", "Int256",
+		"Word8", "Word16", "Word32", "Word64",
+		"UFix64", "Fix64":
+		return value
+
+	case "Array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		result := make([]interface{}, 0, len(arr))
+		for _, item := range arr {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				result = append(result, item)
+				continue
+			}
+			result = append(result, FlattenValue(m))
+		}
+		return result
+
+	case "Dictionary":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		result := make(map[string]interface{}, len(arr))
+		for _, item := range arr {
+			pair, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			keyMap, _ := pair["key"].(map[string]interface{})
+			valMap, _ := pair["value"].(map[string]interface{})
+			if keyMap == nil {
+				continue
+			}
+			key := fmt.Sprintf("%v", FlattenValue(keyMap))
+			if valMap != nil {
+				result[key] = FlattenValue(valMap)
+			} else {
+				result[key] = nil
+			}
+		}
+		return result
+
+	case "Struct", "Resource", "Event", "Contract", "Enum":
+		inner, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		fields, ok := inner["fields"].([]interface{})
+		if !ok {
+			return value
+		}
+		result := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			field, ok := f.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := field["name"].(string)
+			val, _ := field["value"].(map[string]interface{})
+			if name != "" && val != nil {
+				result[name] = FlattenValue(val)
+			}
+		}
+		return result
+
+	case "Type":
+		inner, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		staticType, ok := inner["staticType"].(map[string]interface{})
+		if ok {
+			if typeID, ok := staticType["typeID"].(string); ok {
+				return typeID
+			}
+		}
+		return inner
+
+	case "Path":
+		inner, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		domain, _ := inner["domain"].(string)
+		identifier, _ := inner["identifier"].(string)
+		return fmt.Sprintf("/%s/%s", domain, identifier)
+
+	case "Capability":
+		inner, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		return inner
+
+	case "Void":
+		return nil
+
+	default:
+		return value
+	}
+}
@@ -70,28 +70,70 @@ var testnetAddresses = FlowAddresses{
 	FungibleTokenMetadataViews: "9a0766d93b6608b7",
 }
 
+// emulatorAddresses are the core contract locations on the Flow emulator
+// (service account, plus the FlowToken/FungibleToken/FlowFees bootstrap accounts).
+var emulatorAddresses = FlowAddresses{
+	FlowIDTableStaking: "f8d6e0586b0a20c7",
+	FlowEpoch:          "f8d6e0586b0a20c7",
+	FlowToken:          "0ae53cb6e3f42a79",
+	FungibleToken:       "ee82856bf20e2aa6",
+	NonFungibleToken:    "f8d6e0586b0a20c7",
+	MetadataViews:       "f8d6e0586b0a20c7",
+	ViewResolver:        "f8d6e0586b0a20c7",
+	EVM:                 "f8d6e0586b0a20c7",
+	FlowServiceAccount:  "f8d6e0586b0a20c7",
+	FlowFees:            "e5a8b7f23e8b548f",
+	LockedTokens:        "f8d6e0586b0a20c7",
+	StakingCollection:   "f8d6e0586b0a20c7",
+	FlowEVMBridge:       "f8d6e0586b0a20c7",
+	FlowEVMBridgeConfig: "f8d6e0586b0a20c7",
+	FlowEVMBridgeUtils:  "f8d6e0586b0a20c7",
+	FungibleTokenMetadataViews: "ee82856bf20e2aa6",
+}
+
+// Supported FLOW_NETWORK values.
+const (
+	NetworkMainnet  = "mainnet"
+	NetworkTestnet  = "testnet"
+	NetworkEmulator = "emulator"
+)
+
+// normalizeNetwork maps a FLOW_NETWORK value to a supported network name.
+// Unknown or empty values fall back to mainnet.
+func normalizeNetwork(network string) string {
+	switch strings.TrimSpace(strings.ToLower(network)) {
+	case NetworkTestnet:
+		return NetworkTestnet
+	case NetworkEmulator, "local", "localnet":
+		return NetworkEmulator
+	default:
+		return NetworkMainnet
+	}
+}
+
+// AddressesFor returns a copy of the system contract address set for network.
+func AddressesFor(network string) FlowAddresses {
+	switch normalizeNetwork(network) {
+	case NetworkTestnet:
+		return testnetAddresses
+	case NetworkEmulator:
+		return emulatorAddresses
+	default:
+		return mainnetAddresses
+	}
+}
+
 // Addr returns the global FlowAddresses for the configured network.
-// Reads FLOW_NETWORK env var on first call ("testnet" or "mainnet", default "mainnet").
+// Reads FLOW_NETWORK env var on first call ("mainnet", "testnet" or "emulator", default "mainnet").
 func Addr() *FlowAddresses {
 	addressesOnce.Do(func() {
-		network := strings.TrimSpace(strings.ToLower(os.Getenv("FLOW_NETWORK")))
-		switch network {
-		case "testnet":
-			a := testnetAddresses
-			addresses = &a
-		default:
-			a := mainnetAddresses
-			addresses = &a
-		}
+		a := AddressesFor(os.Getenv("FLOW_NETWORK"))
+		addresses = &a
 	})
 	return addresses
 }
 
-// Network returns "testnet" or "mainnet" based on FLOW_NETWORK env var.
+// Network returns "mainnet", "testnet" or "emulator" based on FLOW_NETWORK env var.
 func Network() string {
-	network := strings.TrimSpace(strings.ToLower(os.Getenv("FLOW_NETWORK")))
-	if network == "testnet" {
-		return "testnet"
-	}
-	return "mainnet"
+	return normalizeNetwork(os.Getenv("FLOW_NETWORK"))
 }
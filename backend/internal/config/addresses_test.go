@@ -0,0 +1,51 @@
+package config
+
+import "testing"
+
+func TestAddressesForNetwork(t *testing.T) {
+	t.Parallel()
+
+	testnet := AddressesFor("testnet")
+	if testnet.FungibleToken != "9a0766d93b6608b7" {
+		t.Fatalf("testnet FungibleToken=%q", testnet.FungibleToken)
+	}
+	if testnet.MetadataViews != "631e88ae7f1d7c20" {
+		t.Fatalf("testnet MetadataViews=%q", testnet.MetadataViews)
+	}
+	if testnet.FlowEVMBridgeConfig != "dfc20aee650fcbdf" {
+		t.Fatalf("testnet FlowEVMBridgeConfig=%q", testnet.FlowEVMBridgeConfig)
+	}
+
+	mainnet := AddressesFor("mainnet")
+	if mainnet.FungibleToken != "f233dcee88fe0abe" {
+		t.Fatalf("mainnet FungibleToken=%q", mainnet.FungibleToken)
+	}
+	if mainnet.MetadataViews != "1d7e57aa55817448" {
+		t.Fatalf("mainnet MetadataViews=%q", mainnet.MetadataViews)
+	}
+	if mainnet.FlowEVMBridgeConfig != "1e4aa0b87d10b141" {
+		t.Fatalf("mainnet FlowEVMBridgeConfig=%q", mainnet.FlowEVMBridgeConfig)
+	}
+
+	if emu := AddressesFor("emulator"); emu.FlowServiceAccount != "f8d6e0586b0a20c7" {
+		t.Fatalf("emulator FlowServiceAccount=%q", emu.FlowServiceAccount)
+	}
+}
+
+func TestNormalizeNetwork(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":          NetworkMainnet,
+		"MAINNET":   NetworkMainnet,
+		" testnet ": NetworkTestnet,
+		"emulator":  NetworkEmulator,
+		"local":     NetworkEmulator,
+		"bogus":     NetworkMainnet,
+	}
+	for in, want := range cases {
+		if got := normalizeNetwork(in); got != want {
+			t.Fatalf("normalizeNetwork(%q)=%q want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package contractid parses and formats Cadence contract identifiers.
+//
+// The indexer has seen contracts written in several shapes over time
+// ("A.1654653399040a61.FlowToken", "A.1654653399040a61.FlowToken.Vault",
+// "0x1654653399040a61.FlowToken", "FlowToken.Vault", a bare address, unpadded
+// or 0x-prefixed addresses). Parse and FromParts map all of them to an ID whose
+// String form is canonical: "A.<16-char lowercase hex>.<Name>".
+package contractid
+
+import "strings"
+
+// ID is a contract identity. Address is 16-char lowercase hex without 0x;
+// either field may be empty when the input only carried the other one.
+type ID struct {
+	Address string
+	Name    string
+}
+
+// Parse reads any identifier shape. Anything after the contract name (a
+// resource or event suffix such as ".Vault" or ".NFT") is dropped. Outside the
+// "A." form a segment counts as an address only when it is 0x-prefixed or a
+// full 16 hex chars, so short names like "Cafe" are not mistaken for one.
+// Input it cannot make sense of yields the zero ID.
+func Parse(s string) ID {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ID{}
+	}
+	parts := strings.Split(s, ".")
+	switch {
+	case len(parts) >= 3 && strings.EqualFold(parts[0], "A"):
+		addr := NormalizeAddress(parts[1])
+		if addr == "" {
+			return ID{}
+		}
+		return ID{Address: addr, Name: strings.TrimSpace(parts[2])}
+	case len(parts) >= 2:
+		if addr := bareAddress(parts[0]); addr != "" {
+			return ID{Address: addr, Name: strings.TrimSpace(parts[1])}
+		}
+		// "Name.Vault" and similar: a contract name with a type suffix.
+		if isName(parts[0]) {
+			return ID{Name: parts[0]}
+		}
+		return ID{}
+	}
+	if addr := bareAddress(s); addr != "" {
+		return ID{Address: addr}
+	}
+	if isName(s) {
+		return ID{Name: s}
+	}
+	return ID{}
+}
+
+// FromParts builds an ID from separately stored address and name columns,
+// either of which may itself hold a full identifier (as some workers wrote
+// them). The address column is always read as an address and a plain name
+// column as a name; an address in the name's identifier only fills a missing
+// address.
+func FromParts(address, name string) ID {
+	address, name = strings.TrimSpace(address), strings.TrimSpace(name)
+	var id ID
+	if strings.Contains(address, ".") {
+		id = Parse(address)
+	} else {
+		id.Address = NormalizeAddress(address)
+	}
+	if !strings.Contains(name, ".") {
+		if name != "" {
+			id.Name = name
+		}
+		return id
+	}
+	n := Parse(name)
+	if id.Address == "" {
+		id.Address = n.Address
+	}
+	if n.Name != "" {
+		id.Name = n.Name
+	}
+	return id
+}
+
+// NormalizeAddress returns a Flow address as 16-char lowercase hex without
+// 0x, or "" when s is not a hex address of at most 8 bytes.
+func NormalizeAddress(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "0x")
+	if s == "" || len(s) > 16 {
+		return ""
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return ""
+		}
+	}
+	return strings.Repeat("0", 16-len(s)) + s
+}
+
+// IsZero reports whether the ID carries neither an address nor a name.
+func (id ID) IsZero() bool {
+	return id.Address == "" && id.Name == ""
+}
+
+// String returns the canonical form: "A.<addr>.<Name>" when both parts are
+// known, otherwise whichever part is set.
+func (id ID) String() string {
+	switch {
+	case id.Address == "":
+		return id.Name
+	case id.Name == "":
+		return id.Address
+	}
+	return "A." + id.Address + "." + id.Name
+}
+
+// Vault returns the fungible token vault type ("A.<addr>.<Name>.Vault"), or
+// String() when the ID is incomplete.
+func (id ID) Vault() string {
+	if id.Address == "" || id.Name == "" {
+		return id.String()
+	}
+	return id.String() + ".Vault"
+}
+
+// bareAddress normalizes s when it is unambiguously an address: 0x-prefixed,
+// or exactly 16 hex chars.
+func bareAddress(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(strings.ToLower(s), "0x") && len(s) != 16 {
+		return ""
+	}
+	return NormalizeAddress(s)
+}
+
+// isName reports whether s looks like a Cadence identifier.
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package contractid
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in   string
+		want ID
+	}{
+		{"A.1654653399040a61.FlowToken", ID{"1654653399040a61", "FlowToken"}},
+		{"A.1654653399040a61.FlowToken.Vault", ID{"1654653399040a61", "FlowToken"}},
+		{"a.0x1654653399040A61.FlowToken", ID{"1654653399040a61", "FlowToken"}},
+		{"A.ae53cb6e3f42a79.FlowToken", ID{"0ae53cb6e3f42a79", "FlowToken"}},
+		{"0x1654653399040a61.FlowToken", ID{"1654653399040a61", "FlowToken"}},
+		{"1654653399040a61.FlowToken", ID{"1654653399040a61", "FlowToken"}},
+		{"FlowToken.Vault", ID{Name: "FlowToken"}},
+		{"0x1654653399040a61", ID{Address: "1654653399040a61"}},
+		{"0x1", ID{Address: "0000000000000001"}},
+		{"1654653399040a61", ID{Address: "1654653399040a61"}},
+		{"FlowToken", ID{Name: "FlowToken"}},
+		{"Cafe", ID{Name: "Cafe"}},
+		{"  A.1654653399040a61.FlowToken  ", ID{"1654653399040a61", "FlowToken"}},
+		{"", ID{}},
+		{"A.zz.FlowToken", ID{}},
+		{"flow.AccountCreated", ID{Name: "flow"}},
+		{"0x12345678901234567", ID{}},
+		{"not an id", ID{}},
+	}
+	for _, tc := range cases {
+		if got := Parse(tc.in); got != tc.want {
+			t.Errorf("Parse(%q) = %+v; want %+v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inputs := []string{
+		"A.1654653399040a61.FlowToken",
+		"A.1654653399040a61.FlowToken.Vault",
+		"A.0x1654653399040A61.FlowToken",
+		"A.ae53cb6e3f42a79.FlowToken",
+		"0x1654653399040a61.FlowToken",
+		"1654653399040a61.FlowToken",
+		"FlowToken.Vault",
+		"0x1654653399040a61",
+		"0x1",
+		"1654653399040a61",
+		"FlowToken",
+		"Cafe",
+		"",
+		"A.zz.FlowToken",
+		"not an id",
+	}
+	for _, in := range inputs {
+		first := Parse(in)
+		canonical := first.String()
+		second := Parse(canonical)
+		if second != first {
+			t.Errorf("Parse(%q) = %+v, but Parse(%q) = %+v", in, first, canonical, second)
+		}
+		if again := second.String(); again != canonical {
+			t.Errorf("format not idempotent for %q: %q then %q", in, canonical, again)
+		}
+	}
+}
+
+func TestFromParts(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		addr, name string
+		want       ID
+	}{
+		{"1654653399040a61", "FlowToken", ID{"1654653399040a61", "FlowToken"}},
+		{"0x1654653399040A61", "A.1654653399040a61.FlowToken", ID{"1654653399040a61", "FlowToken"}},
+		{"A.1654653399040a61.FlowToken", "", ID{"1654653399040a61", "FlowToken"}},
+		{"1654653399040a61", "A.1654653399040a61.FlowToken.Vault", ID{"1654653399040a61", "FlowToken"}},
+		{"0x0ae53cb6e3f42a79", "FlowToken.Vault", ID{"0ae53cb6e3f42a79", "FlowToken"}},
+		{"ae53cb6e3f42a79", "FlowToken", ID{"0ae53cb6e3f42a79", "FlowToken"}},
+		{"", "A.1654653399040a61.FlowToken", ID{"1654653399040a61", "FlowToken"}},
+		{"1654653399040a61", "Cafe", ID{"1654653399040a61", "Cafe"}},
+		{"1654653399040a61", "", ID{Address: "1654653399040a61"}},
+	}
+	for _, tc := range cases {
+		got := FromParts(tc.addr, tc.name)
+		if got != tc.want {
+			t.Errorf("FromParts(%q, %q) = %+v; want %+v", tc.addr, tc.name, got, tc.want)
+		}
+		if back := Parse(got.String()); back != got {
+			t.Errorf("FromParts(%q, %q) = %+v does not round-trip: %+v", tc.addr, tc.name, got, back)
+		}
+	}
+}
+
+func TestIDFormat(t *testing.T) {
+	t.Parallel()
+
+	full := ID{"1654653399040a61", "FlowToken"}
+	if got := full.String(); got != "A.1654653399040a61.FlowToken" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := full.Vault(); got != "A.1654653399040a61.FlowToken.Vault" {
+		t.Errorf("Vault() = %q", got)
+	}
+	if got := (ID{Address: "1654653399040a61"}).Vault(); got != "1654653399040a61" {
+		t.Errorf("address-only Vault() = %q", got)
+	}
+	if got := (ID{Name: "FlowToken"}).String(); got != "FlowToken" {
+		t.Errorf("name-only String() = %q", got)
+	}
+	if !(ID{}).IsZero() || full.IsZero() {
+		t.Error("IsZero mismatch")
+	}
+}
@@ -0,0 +1,71 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// maxBalanceBatch caps the addresses passed to one balance script. Scripts are
+// limited in computation and argument size; a few hundred getAccount calls
+// stays well within both.
+const maxBalanceBatch = 200
+
+const accountBalancesScript = `
+access(all) fun main(addresses: [Address]): {Address: UFix64} {
+    let out: {Address: UFix64} = {}
+    for addr in addresses {
+        out[addr] = getAccount(addr).balance
+    }
+    return out
+}`
+
+type scriptExecutor interface {
+	ExecuteScriptAtLatestBlock(ctx context.Context, script []byte, args []cadence.Value) (cadence.Value, error)
+}
+
+// GetAccountBalances returns the FLOW balance of each address, in the same
+// units as flow.Account.Balance (1e-8 FLOW), keyed by 16-char lowercase hex
+// address. Addresses are fetched with one script per maxBalanceBatch addresses
+// instead of one GetAccount round-trip each.
+func (c *Client) GetAccountBalances(ctx context.Context, addresses []string) (map[string]uint64, error) {
+	return getAccountBalances(ctx, c, addresses, maxBalanceBatch)
+}
+
+func getAccountBalances(ctx context.Context, exec scriptExecutor, addresses []string, batch int) (map[string]uint64, error) {
+	seen := make(map[flow.Address]bool, len(addresses))
+	unique := make([]cadence.Value, 0, len(addresses))
+	for _, a := range addresses {
+		addr := flow.HexToAddress(a)
+		if !seen[addr] {
+			seen[addr] = true
+			unique = append(unique, cadence.NewAddress(addr))
+		}
+	}
+
+	out := make(map[string]uint64, len(unique))
+	for start := 0; start < len(unique); start += batch {
+		end := min(start+batch, len(unique))
+		v, err := exec.ExecuteScriptAtLatestBlock(ctx, []byte(accountBalancesScript), []cadence.Value{
+			cadence.NewArray(unique[start:end]),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("balances for addresses %d-%d: %w", start, end-1, err)
+		}
+		dict, ok := v.(cadence.Dictionary)
+		if !ok {
+			return nil, fmt.Errorf("balances script returned %T, want dictionary", v)
+		}
+		for _, pair := range dict.Pairs {
+			addr, okA := pair.Key.(cadence.Address)
+			bal, okB := pair.Value.(cadence.UFix64)
+			if !okA || !okB {
+				return nil, fmt.Errorf("balances script returned %T: %T pair", pair.Key, pair.Value)
+			}
+			out[flow.Address(addr).Hex()] = uint64(bal)
+		}
+	}
+	return out, nil
+}
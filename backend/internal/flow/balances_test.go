@@ -0,0 +1,53 @@
+package flow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// balanceScriptStub answers the balances script with 1 FLOW per address
+// index, recording how many addresses each call received.
+type balanceScriptStub struct {
+	calls []int
+}
+
+func (s *balanceScriptStub) ExecuteScriptAtLatestBlock(_ context.Context, _ []byte, args []cadence.Value) (cadence.Value, error) {
+	addrs := args[0].(cadence.Array).Values
+	s.calls = append(s.calls, len(addrs))
+	pairs := make([]cadence.KeyValuePair, 0, len(addrs))
+	for _, a := range addrs {
+		idx := flow.Address(a.(cadence.Address)).Bytes()[7]
+		pairs = append(pairs, cadence.KeyValuePair{Key: a, Value: cadence.UFix64(uint64(idx) * 1e8)})
+	}
+	return cadence.NewDictionary(pairs), nil
+}
+
+func TestGetAccountBalancesChunks(t *testing.T) {
+	t.Parallel()
+
+	var addresses []string
+	for i := 1; i <= 7; i++ {
+		addresses = append(addresses, flow.BytesToAddress([]byte{byte(i)}).Hex())
+	}
+	// Duplicates (in another spelling) are only fetched once.
+	addresses = append(addresses, "0x0000000000000001", "0x2")
+
+	stub := &balanceScriptStub{}
+	got, err := getAccountBalances(context.Background(), stub, addresses, 3)
+	if err != nil {
+		t.Fatalf("getAccountBalances: %v", err)
+	}
+	if want := []int{3, 3, 1}; !reflect.DeepEqual(stub.calls, want) {
+		t.Fatalf("script calls = %v, want %v", stub.calls, want)
+	}
+	if len(got) != 7 {
+		t.Fatalf("got %d balances, want 7: %v", len(got), got)
+	}
+	if got["0000000000000005"] != 5e8 {
+		t.Fatalf("balance of 0x5 = %d, want 5e8", got["0000000000000005"])
+	}
+}
@@ -0,0 +1,243 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BreakerState is the state of the access node circuit breaker.
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitOpenError is returned without calling any access node while the
+// breaker is open. RetryAfter is how long until the breaker will let a probe through.
+type CircuitOpenError struct {
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("flow access node circuit breaker open (retry in %s)", e.RetryAfter.Round(time.Second))
+}
+
+// IsCircuitOpen reports whether err came from an open circuit breaker, and if so
+// how long callers should wait before retrying.
+func IsCircuitOpen(err error) (time.Duration, bool) {
+	var openErr *CircuitOpenError
+	if errors.As(err, &openErr) {
+		return openErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// BreakerConfig configures the circuit breaker. Threshold <= 0 disables it.
+type BreakerConfig struct {
+	Threshold int           // consecutive failures (across all nodes) that open the breaker
+	Window    time.Duration // failures older than this no longer count toward Threshold
+	Cooldown  time.Duration // how long the breaker stays open before half-opening
+}
+
+func breakerConfigFromEnv() BreakerConfig {
+	return BreakerConfig{
+		Threshold: int(getEnvFloat("FLOW_BREAKER_THRESHOLD", 20)),
+		Window:    time.Duration(getEnvFloat("FLOW_BREAKER_WINDOW_SEC", 60)) * time.Second,
+		Cooldown:  time.Duration(getEnvFloat("FLOW_BREAKER_COOLDOWN_SEC", 30)) * time.Second,
+	}
+}
+
+// BreakerStats is a snapshot of the breaker for status/metrics endpoints.
+type BreakerStats struct {
+	Enabled             bool      `json:"enabled"`
+	State               string    `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Opens               uint64    `json:"opens"`
+	Rejected            uint64    `json:"rejected"`
+	OpenedAt            time.Time `json:"opened_at,omitempty"`
+}
+
+// circuitBreaker trips after Threshold consecutive transport failures within
+// Window, fails fast for Cooldown, then lets a single probe through
+// (half-open). A successful probe closes it; a failed probe re-opens it.
+type circuitBreaker struct {
+	cfg BreakerConfig
+	now func() time.Time
+
+	mu           sync.Mutex
+	state        BreakerState
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+	probing      bool
+	opens        uint64
+	rejected     uint64
+}
+
+func newCircuitBreaker(cfg BreakerConfig) *circuitBreaker {
+	if cfg.Threshold <= 0 {
+		return nil
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+	return &circuitBreaker{cfg: cfg, now: time.Now}
+}
+
+// allow returns a *CircuitOpenError if the call must not be attempted.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		elapsed := b.now().Sub(b.openedAt)
+		if elapsed < b.cfg.Cooldown {
+			b.rejected++
+			return &CircuitOpenError{RetryAfter: b.cfg.Cooldown - elapsed}
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			b.rejected++
+			return &CircuitOpenError{RetryAfter: time.Second}
+		}
+		b.probing = true
+		return nil
+	}
+	return nil
+}
+
+// record feeds the outcome of an attempted call back into the breaker.
+func (b *circuitBreaker) record(err error) {
+	if b == nil {
+		return
+	}
+	failed := isBreakerFailure(err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// A cancelled call says nothing either way; just release the probe slot.
+	if errors.Is(err, context.Canceled) || status.Code(err) == codes.Canceled {
+		b.probing = false
+		return
+	}
+
+	now := b.now()
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+		if failed {
+			b.trip(now)
+			return
+		}
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	// Any answer from a node (even NotFound) shows the pool is reachable.
+	if !failed {
+		b.failures = 0
+		return
+	}
+	if b.failures == 0 || now.Sub(b.firstFailure) > b.cfg.Window {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+	if b.state == BreakerClosed && b.failures >= b.cfg.Threshold {
+		b.trip(now)
+	}
+}
+
+func (b *circuitBreaker) trip(now time.Time) {
+	b.state = BreakerOpen
+	b.openedAt = now
+	b.opens++
+}
+
+func (b *circuitBreaker) stats() BreakerStats {
+	if b == nil {
+		return BreakerStats{State: BreakerClosed.String()}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := BreakerStats{
+		Enabled:             true,
+		State:               b.state.String(),
+		ConsecutiveFailures: b.failures,
+		Opens:               b.opens,
+		Rejected:            b.rejected,
+	}
+	if b.state != BreakerClosed {
+		out.OpenedAt = b.openedAt
+	}
+	return out
+}
+
+// isBreakerFailure reports whether err indicates the access node itself is
+// unhealthy (unreachable, overloaded, timing out). Application-level errors such
+// as NotFound or spork boundaries say nothing about node health and are ignored.
+func isBreakerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isZeroAddressesResolverError(err) {
+		return true
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	case codes.ResourceExhausted:
+		return !strings.Contains(st.Message(), "received message larger than max")
+	}
+	return false
+}
+
+// guard runs fn through the circuit breaker.
+func (c *Client) guard(fn func() error) error {
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+	err := fn()
+	c.breaker.record(err)
+	return err
+}
+
+// BreakerStats returns a snapshot of the access node circuit breaker.
+func (c *Client) BreakerStats() BreakerStats {
+	return c.breaker.stats()
+}
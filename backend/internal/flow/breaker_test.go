@@ -0,0 +1,135 @@
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAccessNode stands in for the whole access node pool.
+type fakeAccessNode struct {
+	fail  bool
+	calls int
+}
+
+func (n *fakeAccessNode) GetLatestBlockHeight() (uint64, error) {
+	n.calls++
+	if n.fail {
+		return 0, status.Error(codes.Unavailable, "connection refused")
+	}
+	return 100, nil
+}
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newBreakerTestClient(clock *fakeClock) *Client {
+	b := newCircuitBreaker(BreakerConfig{Threshold: 3, Window: time.Minute, Cooldown: 30 * time.Second})
+	b.now = clock.now
+	return &Client{breaker: b}
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}
+	c := newBreakerTestClient(clock)
+	node := &fakeAccessNode{fail: true}
+	call := func() error {
+		return c.guard(func() error {
+			_, err := node.GetLatestBlockHeight()
+			return err
+		})
+	}
+
+	// closed -> open after Threshold consecutive failures.
+	for i := 0; i < 3; i++ {
+		if err := call(); err == nil {
+			t.Fatal("expected failure from failing node")
+		}
+		clock.advance(time.Second)
+	}
+	if got := c.BreakerStats(); got.State != "open" || got.Opens != 1 {
+		t.Fatalf("after failures: %+v", got)
+	}
+
+	// open: fail fast without touching the node.
+	err := call()
+	wait, open := IsCircuitOpen(err)
+	if !open || wait <= 0 || wait > 30*time.Second {
+		t.Fatalf("expected circuit open error, got %v (wait %s)", err, wait)
+	}
+	if node.calls != 3 {
+		t.Fatalf("node called while open: %d calls", node.calls)
+	}
+
+	// open -> half-open after cooldown; a failed probe re-opens.
+	clock.advance(30 * time.Second)
+	if err := call(); err == nil {
+		t.Fatal("probe against failing node should fail")
+	} else if _, open := IsCircuitOpen(err); open {
+		t.Fatal("probe should reach the node")
+	}
+	if got := c.BreakerStats(); got.State != "open" || got.Opens != 2 {
+		t.Fatalf("after failed probe: %+v", got)
+	}
+
+	// half-open admits a single probe at a time.
+	clock.advance(30 * time.Second)
+	if err := c.breaker.allow(); err != nil {
+		t.Fatalf("probe should be admitted: %v", err)
+	}
+	if got := c.BreakerStats(); got.State != "half_open" {
+		t.Fatalf("expected half_open, got %+v", got)
+	}
+	if _, open := IsCircuitOpen(c.breaker.allow()); !open {
+		t.Fatal("second concurrent probe should be rejected")
+	}
+
+	// half-open -> closed once the probe succeeds.
+	node.fail = false
+	_, probeErr := node.GetLatestBlockHeight()
+	c.breaker.record(probeErr)
+	if got := c.BreakerStats(); got.State != "closed" || got.ConsecutiveFailures != 0 {
+		t.Fatalf("after successful probe: %+v", got)
+	}
+	if err := call(); err != nil {
+		t.Fatalf("closed breaker should pass calls through: %v", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresApplicationErrorsAndStaleFailures(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}
+	c := newBreakerTestClient(clock)
+
+	notFound := status.Error(codes.NotFound, "block not found")
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	for i := 0; i < 5; i++ {
+		_ = c.guard(func() error { return notFound })
+	}
+	if got := c.BreakerStats(); got.State != "closed" || got.ConsecutiveFailures != 0 {
+		t.Fatalf("NotFound should not count as a node failure: %+v", got)
+	}
+
+	// Two failures, then the window expires before the third.
+	_ = c.guard(func() error { return unavailable })
+	_ = c.guard(func() error { return unavailable })
+	clock.advance(2 * time.Minute)
+	_ = c.guard(func() error { return unavailable })
+	if got := c.BreakerStats(); got.State != "closed" || got.ConsecutiveFailures != 1 {
+		t.Fatalf("stale failures should not trip the breaker: %+v", got)
+	}
+}
+
+func TestNilBreakerIsDisabled(t *testing.T) {
+	c := &Client{breaker: newCircuitBreaker(BreakerConfig{Threshold: 0})}
+	for i := 0; i < 10; i++ {
+		_ = c.guard(func() error { return status.Error(codes.Unavailable, "down") })
+	}
+	if got := c.BreakerStats(); got.Enabled || got.State != "closed" {
+		t.Fatalf("disabled breaker reported %+v", got)
+	}
+}
@@ -45,6 +45,10 @@ type Client struct {
 	noBulkAPI []uint32
 	limiter   *rate.Limiter
 	rr        uint32
+	// Shared across all nodes; nil when FLOW_BREAKER_THRESHOLD <= 0.
+	breaker *circuitBreaker
+	// Latest-block script results; nil when FLOW_SCRIPT_CACHE_TTL_SEC <= 0.
+	scripts *scriptCache
 }
 
 // NewClient creates a new Flow gRPC client
@@ -82,7 +86,7 @@ func NewClientFromEnv(envKey string, fallback string) (*Client, error) {
 		rawConn, rawErr := grpc.NewClient(node,
 			grpc.WithTransportCredentials(insecure.NewCredentials()),
 			grpc.WithDefaultCallOptions(
-				grpc.MaxCallRecvMsgSize(64*1024*1024),
+				grpc.MaxCallRecvMsgSize(grpcMaxRecvBytes()),
 				grpc.MaxCallSendMsgSize(16*1024*1024),
 			),
 		)
@@ -119,6 +123,8 @@ func NewClientFromEnv(envKey string, fallback string) (*Client, error) {
 		disabledUntil: make([]int64, len(clients)),
 		noBulkAPI:     make([]uint32, len(clients)),
 		limiter:       newLimiterFromEnv(len(clients)),
+		breaker:       newCircuitBreaker(breakerConfigFromEnv()),
+		scripts:       scriptCacheFromEnv(),
 	}
 	c.initSporkMinHeights()
 	return c, nil
@@ -158,6 +164,24 @@ func (c *Client) GetTransaction(ctx context.Context, txID flow.Identifier) (*flo
 	return tx, err
 }
 
+// GetTransactionScript fetches a transaction's script from the node serving
+// height. It satisfies repository.ScriptSource.
+func (c *Client) GetTransactionScript(ctx context.Context, txID string, height uint64) (string, error) {
+	pin, err := c.PinByHeight(height)
+	if err != nil {
+		return "", err
+	}
+	var tx *flow.Transaction
+	if err := pin.withRetry(ctx, func() error {
+		var err error
+		tx, err = pin.cli.GetTransaction(ctx, flow.HexToID(txID))
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("get transaction %s: %w", txID, err)
+	}
+	return string(tx.Script), nil
+}
+
 // GetTransactionsByBlockHeight fetches all transactions for a block at the given height
 // in a single RPC call. Much more efficient than fetching transactions individually.
 func (c *Client) GetTransactionsByBlockHeight(ctx context.Context, height uint64) ([]*flow.Transaction, error) {
@@ -193,6 +217,11 @@ func (c *Client) NetworkName() string {
 	return "flow" // In real implementation, derive from URL or config
 }
 
+// NodeCount returns the number of connected access nodes.
+func (c *Client) NodeCount() int {
+	return len(c.nodes)
+}
+
 // GetAccount fetches account details (balance, keys, contracts)
 func (c *Client) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
 	var acc *flow.Account
@@ -214,17 +243,21 @@ func (c *Client) GetAccountAtBlockHeight(ctx context.Context, address flow.Addre
 	return acc, err
 }
 
+// ExecuteScriptAtLatestBlock runs script against the latest sealed block.
+// Successful results are cached per (script, args) for FLOW_SCRIPT_CACHE_TTL_SEC.
 func (c *Client) ExecuteScriptAtLatestBlock(ctx context.Context, script []byte, args []cadence.Value) (cadence.Value, error) {
-	var out cadence.Value
-	err := c.withRetry(ctx, func() error {
-		v, err := c.pickClient().ExecuteScriptAtLatestBlock(ctx, script, args)
-		if err != nil {
-			return err
-		}
-		out = v
-		return nil
+	return c.scripts.do(script, args, func() (cadence.Value, error) {
+		var out cadence.Value
+		err := c.withRetry(ctx, func() error {
+			v, err := c.pickClient().ExecuteScriptAtLatestBlock(ctx, script, args)
+			if err != nil {
+				return err
+			}
+			out = v
+			return nil
+		})
+		return out, err
 	})
-	return out, err
 }
 
 func (c *Client) ExecuteScriptAtBlockHeight(ctx context.Context, height uint64, script []byte, args []cadence.Value) (cadence.Value, error) {
@@ -278,10 +311,13 @@ func (c *Client) withRetry(ctx context.Context, fn func() error) error {
 			}
 		}
 
-		err := fn()
+		err := c.guard(fn)
 		if err == nil {
 			return nil
 		}
+		if _, open := IsCircuitOpen(err); open {
+			return err
+		}
 
 		st, ok := status.FromError(err)
 		if !ok {
@@ -997,10 +1033,13 @@ func (c *Client) withRetryPinned(ctx context.Context, idx int, node string, fn f
 			}
 		}
 
-		err := fn()
+		err := c.guard(fn)
 		if err == nil {
 			return nil
 		}
+		if _, open := IsCircuitOpen(err); open {
+			return err
+		}
 
 		// If the node cannot resolve to any address, mark it disabled and let the caller repin.
 		if isZeroAddressesResolverError(err) {
@@ -1037,6 +1076,11 @@ func (c *Client) withRetryPinned(ctx context.Context, idx int, node string, fn f
 			// and permanently mark this node's minimum servable height.
 			return &NodeUnavailableError{Node: node, NodeIndex: idx, Err: err}
 		case codes.ResourceExhausted:
+			// An oversized response is deterministic; retrying or repinning won't
+			// help, so surface it for the caller's per-tx fallback.
+			if strings.Contains(st.Message(), "received message larger than max") {
+				return err
+			}
 			if i == maxRetries-1 {
 				return &NodeExhaustedError{Node: node, NodeIndex: idx, Err: err}
 			}
@@ -1372,13 +1416,12 @@ func grpcDialOptionsFromEnv() []grpc.DialOption {
 	// Batching Access API calls (e.g. GetTransactionResultsByBlockID) can exceed the default 4MB
 	// gRPC receive limit on busy blocks. Allow a larger payload so history backfill doesn't stall.
 	//
-	// Defaults: 64MB recv, 16MB send. Override with FLOW_GRPC_MAX_RECV_MB/FLOW_GRPC_MAX_SEND_MB.
-	maxRecv := int(getEnvFloat("FLOW_GRPC_MAX_RECV_MB", 64) * 1024 * 1024)
+	// Defaults: 64MB recv, 16MB send. Override with FLOW_GRPC_MAX_MSG_MB (or
+	// FLOW_GRPC_MAX_RECV_MB) and FLOW_GRPC_MAX_SEND_MB. Blocks that still exceed the
+	// receive limit fall back to per-tx result calls in the ingester.
+	maxRecv := grpcMaxRecvBytes()
 	maxSend := int(getEnvFloat("FLOW_GRPC_MAX_SEND_MB", 16) * 1024 * 1024)
 	const minBytes = 4 * 1024 * 1024
-	if maxRecv < minBytes {
-		maxRecv = minBytes
-	}
 	if maxSend < minBytes {
 		maxSend = minBytes
 	}
@@ -1390,6 +1433,17 @@ func grpcDialOptionsFromEnv() []grpc.DialOption {
 	}
 }
 
+// grpcMaxRecvBytes returns the max gRPC receive size shared by the SDK and raw
+// connections. FLOW_GRPC_MAX_RECV_MB takes precedence over FLOW_GRPC_MAX_MSG_MB.
+func grpcMaxRecvBytes() int {
+	const minBytes = 4 * 1024 * 1024
+	maxRecv := int(getEnvFloat("FLOW_GRPC_MAX_RECV_MB", getEnvFloat("FLOW_GRPC_MAX_MSG_MB", 64)) * 1024 * 1024)
+	if maxRecv < minBytes {
+		maxRecv = minBytes
+	}
+	return maxRecv
+}
+
 // Close closes the connection
 func (c *Client) Close() error {
 	var firstErr error
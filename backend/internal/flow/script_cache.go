@@ -0,0 +1,105 @@
+package flow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/onflow/cadence"
+	cadjson "github.com/onflow/cadence/encoding/json"
+)
+
+// scriptCache memoizes successful ExecuteScriptAtLatestBlock results for a
+// short TTL. Metadata workers run the same script with the same arguments for
+// many rows in a batch; within the TTL the latest-block answer is identical.
+type scriptCache struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]scriptCacheEntry
+}
+
+type scriptCacheEntry struct {
+	value     cadence.Value
+	expiresAt time.Time
+}
+
+// newScriptCache returns nil (caching disabled) when ttl <= 0.
+func newScriptCache(ttl time.Duration, maxEntries int) *scriptCache {
+	if ttl <= 0 {
+		return nil
+	}
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	return &scriptCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]scriptCacheEntry),
+	}
+}
+
+func scriptCacheFromEnv() *scriptCache {
+	ttl := time.Duration(getEnvFloat("FLOW_SCRIPT_CACHE_TTL_SEC", 10) * float64(time.Second))
+	return newScriptCache(ttl, int(getEnvFloat("FLOW_SCRIPT_CACHE_MAX_ENTRIES", 1000)))
+}
+
+// scriptCacheKey hashes the script together with its JSON-CDC encoded
+// arguments; arguments that cannot be encoded are not cacheable.
+func scriptCacheKey(script []byte, args []cadence.Value) (string, bool) {
+	h := sha256.New()
+	h.Write(script)
+	for _, arg := range args {
+		b, err := cadjson.Encode(arg)
+		if err != nil {
+			return "", false
+		}
+		h.Write([]byte{0})
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// do returns the cached result for (script, args) or runs exec and caches a
+// successful result. A nil cache always runs exec.
+func (c *scriptCache) do(script []byte, args []cadence.Value, exec func() (cadence.Value, error)) (cadence.Value, error) {
+	if c == nil {
+		return exec()
+	}
+	key, ok := scriptCacheKey(script, args)
+	if !ok {
+		return exec()
+	}
+
+	c.mu.Lock()
+	if e, hit := c.entries[key]; hit && c.now().Before(e.expiresAt) {
+		c.mu.Unlock()
+		return e.value, nil
+	}
+	c.mu.Unlock()
+
+	v, err := exec()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= c.maxEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			c.entries = make(map[string]scriptCacheEntry)
+		}
+	}
+	c.entries[key] = scriptCacheEntry{value: v, expiresAt: now.Add(c.ttl)}
+	return v, nil
+}
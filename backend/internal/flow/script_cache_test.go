@@ -0,0 +1,72 @@
+package flow
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence"
+)
+
+func TestScriptCacheHitSkipsExecution(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}
+	c := newScriptCache(10*time.Second, 100)
+	c.now = clock.now
+
+	script := []byte(`access(all) fun main(a: Address): String { return "x" }`)
+	argsA := []cadence.Value{cadence.NewAddress([8]byte{0, 0, 0, 0, 0, 0, 0, 1})}
+	argsB := []cadence.Value{cadence.NewAddress([8]byte{0, 0, 0, 0, 0, 0, 0, 2})}
+
+	calls := 0
+	exec := func() (cadence.Value, error) {
+		calls++
+		return cadence.String("x"), nil
+	}
+
+	if _, err := c.do(script, argsA, exec); err != nil {
+		t.Fatal(err)
+	}
+	v, err := c.do(script, argsA, exec)
+	if err != nil || v != cadence.String("x") {
+		t.Fatalf("cached result = %v, %v", v, err)
+	}
+	if calls != 1 {
+		t.Fatalf("identical script+args executed %d times, want 1", calls)
+	}
+
+	// Different args are a different key.
+	if _, err := c.do(script, argsB, exec); err != nil || calls != 2 {
+		t.Fatalf("different args: calls=%d err=%v", calls, err)
+	}
+
+	// Entries expire after the TTL.
+	clock.advance(11 * time.Second)
+	if _, err := c.do(script, argsA, exec); err != nil || calls != 3 {
+		t.Fatalf("after TTL: calls=%d err=%v", calls, err)
+	}
+}
+
+func TestScriptCacheSkipsErrors(t *testing.T) {
+	c := newScriptCache(time.Minute, 100)
+	calls := 0
+	fail := func() (cadence.Value, error) {
+		calls++
+		return nil, errors.New("script failed")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.do([]byte("s"), nil, fail); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("failed execution was cached: calls=%d", calls)
+	}
+
+	if newScriptCache(0, 100) != nil {
+		t.Fatal("ttl 0 should disable the cache")
+	}
+	var disabled *scriptCache
+	if _, err := disabled.do([]byte("s"), nil, func() (cadence.Value, error) { calls++; return cadence.String(""), nil }); err != nil || calls != 3 {
+		t.Fatalf("nil cache should execute: calls=%d err=%v", calls, err)
+	}
+}
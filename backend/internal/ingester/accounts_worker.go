@@ -3,7 +3,6 @@ package ingester
 import (
 	"context"
 	"encoding/json"
-	"strings"
 
 	"flowscan-clone/internal/models"
 	"flowscan-clone/internal/repository"
@@ -22,6 +21,10 @@ func (w *AccountsWorker) Name() string {
 	return "accounts_worker"
 }
 
+func (w *AccountsWorker) SchemaVersion() int {
+	return 1
+}
+
 func (w *AccountsWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	events, err := w.repo.GetRawEventsInRange(ctx, fromHeight, toHeight)
 	if err != nil {
@@ -33,8 +36,6 @@ func (w *AccountsWorker) ProcessRange(ctx context.Context, fromHeight, toHeight
 	}
 
 	seen := make(map[string]*models.AccountCatalog)
-	coaMappings := make(map[string]models.COAAccount)
-	txByID := make(map[string]models.Transaction, len(txs))
 
 	add := func(addr string, height uint64) {
 		addr = normalizeAddressLower(addr)
@@ -65,10 +66,6 @@ func (w *AccountsWorker) ProcessRange(ctx context.Context, fromHeight, toHeight
 		}
 	}
 
-	for _, tx := range txs {
-		txByID[tx.ID] = tx
-	}
-
 	for _, evt := range events {
 		if evt.Type == "flow.AccountCreated" {
 			var payload map[string]interface{}
@@ -79,46 +76,6 @@ func (w *AccountsWorker) ProcessRange(ctx context.Context, fromHeight, toHeight
 			if addr, ok := payload["address"].(string); ok {
 				add(addr, evt.BlockHeight)
 			}
-			continue
-		}
-
-		if strings.Contains(evt.Type, "CadenceOwnedAccountCreated") {
-			var payload map[string]interface{}
-			if err := json.Unmarshal(evt.Payload, &payload); err != nil {
-				_ = w.repo.LogIndexingError(ctx, w.Name(), evt.BlockHeight, evt.TransactionID, "ACCOUNTS_PAYLOAD_DECODE", err.Error(), nil)
-				continue
-			}
-			coaAddr, ok := payload["address"].(string)
-			if !ok || coaAddr == "" {
-				continue
-			}
-			// COA addresses may be EVM-length (40 hex) or Flow-length (16 hex).
-			coaAddr = normalizeEVMAddress(coaAddr)
-			if coaAddr == "" {
-				continue
-			}
-			tx, ok := txByID[evt.TransactionID]
-			if !ok {
-				continue
-			}
-			owner := ""
-			if len(tx.Authorizers) > 0 {
-				owner = tx.Authorizers[0]
-			} else if tx.PayerAddress != "" {
-				owner = tx.PayerAddress
-			} else {
-				owner = tx.ProposerAddress
-			}
-			owner = normalizeAddressLower(owner)
-			if owner == "" || len(owner) != 16 {
-				continue
-			}
-			coaMappings[coaAddr] = models.COAAccount{
-				COAAddress:    coaAddr,
-				FlowAddress:   owner,
-				TransactionID: evt.TransactionID,
-				BlockHeight:   evt.BlockHeight,
-			}
 		}
 	}
 
@@ -134,20 +91,7 @@ func (w *AccountsWorker) ProcessRange(ctx context.Context, fromHeight, toHeight
 	for _, v := range seen {
 		accounts = append(accounts, *v)
 	}
-	if err := w.repo.UpsertAccounts(ctx, accounts); err != nil {
-		return err
-	}
-
-	if len(coaMappings) > 0 {
-		rows := make([]models.COAAccount, 0, len(coaMappings))
-		for _, v := range coaMappings {
-			rows = append(rows, v)
-		}
-		if err := w.repo.UpsertCOAAccounts(ctx, rows); err != nil {
-			return err
-		}
-	}
-	return nil
+	return w.repo.UpsertAccounts(ctx, accounts)
 }
 
 func normalizeAddressLower(addr string) string {
@@ -19,6 +19,10 @@ func (w *AnalyticsDeriverWorker) Name() string {
 	return "analytics_deriver_worker"
 }
 
+func (w *AnalyticsDeriverWorker) SchemaVersion() int {
+	return 1
+}
+
 func (w *AnalyticsDeriverWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	if toHeight <= fromHeight {
 		return nil
@@ -17,6 +17,16 @@ type Processor interface {
 	ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error
 	// Name returns the worker type name (e.g. "token_worker")
 	Name() string
+	// SchemaVersion identifies the shape of the processor's derived output.
+	// Bump it when processing logic changes in a way that requires re-derivation.
+	SchemaVersion() int
+}
+
+// DryRunner is implemented by processors that can report what ProcessRange
+// would change for a range without writing. At most sampleLimit changed rows
+// are returned per table.
+type DryRunner interface {
+	DryRunRange(ctx context.Context, fromHeight, toHeight uint64, sampleLimit int) ([]repository.TableDiff, error)
 }
 
 // AsyncWorker manages the lifecycle of an async worker: leasing, processing, error handling.
@@ -0,0 +1,100 @@
+package ingester
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/repository"
+)
+
+type recordingCompletions struct {
+	mu  sync.Mutex
+	got []repository.BackfillCompletion
+}
+
+func (r *recordingCompletions) MarkBackfillComplete(_ context.Context, c repository.BackfillCompletion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.got = append(r.got, c)
+	return nil
+}
+
+func TestBackwardIngesterMarksCompleteAtStopHeight(t *testing.T) {
+	t.Parallel()
+
+	store := &recordingCompletions{}
+	s := &Service{
+		config: Config{
+			ServiceName: "history_ingester_spork20",
+			Mode:        "backward",
+			BatchSize:   7,
+			StartBlock:  1000,
+			StopHeight:  960,
+		},
+		completions: store,
+	}
+
+	// Simulate process: plan from the checkpoint, "index" the batch and move
+	// the checkpoint down to its lowest height.
+	var lastIndexed uint64
+	var batches int
+	step := func(ctx context.Context) error {
+		plan, ok := s.planBackward(lastIndexed)
+		if !ok {
+			t.Fatal("backward ingester with a StartBlock should always have work")
+		}
+		if plan.done != nil {
+			return s.completeBackfill(ctx, *plan.done)
+		}
+		if plan.start < s.config.StopHeight || plan.end < plan.start {
+			t.Fatalf("batch %d..%d crosses stop height %d", plan.start, plan.end, s.config.StopHeight)
+		}
+		lastIndexed = plan.start
+		batches++
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- s.run(context.Background(), step) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("run returned %v, want nil after completion", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("backward ingester kept running after reaching its stop height")
+	}
+
+	if lastIndexed != 960 || batches != 6 {
+		t.Fatalf("indexed down to %d in %d batches, want 960 in 6", lastIndexed, batches)
+	}
+	if len(store.got) != 1 {
+		t.Fatalf("completion markers = %+v", store.got)
+	}
+	want := repository.BackfillCompletion{ServiceName: "history_ingester_spork20", StartHeight: 1000, FloorHeight: 960, Reason: BackfillReasonStopHeight}
+	if store.got[0] != want {
+		t.Fatalf("marker = %+v, want %+v", store.got[0], want)
+	}
+}
+
+func TestPlanBackwardCompletionReasons(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{config: Config{ServiceName: "h", Mode: "backward", BatchSize: 10}}
+	if _, ok := s.planBackward(0); ok {
+		t.Fatal("no checkpoint and no StartBlock should wait")
+	}
+	if plan, _ := s.planBackward(1); plan.done == nil || plan.done.Reason != BackfillReasonGenesis {
+		t.Fatalf("genesis plan = %+v", plan)
+	}
+
+	s.minAvailableHeight = 500
+	if plan, _ := s.planBackward(505); plan.done != nil || plan.start != 500 || plan.end != 504 {
+		t.Fatalf("spork clamp plan = %+v", plan)
+	}
+	if plan, _ := s.planBackward(500); plan.done == nil || plan.done.Reason != BackfillReasonSporkRoot || plan.done.FloorHeight != 500 {
+		t.Fatalf("spork root plan = %+v", plan)
+	}
+}
@@ -0,0 +1,101 @@
+package ingester
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"flowscan-clone/internal/repository"
+)
+
+// Backpressure slows ingester batch writes while the shared DB pool is nearly
+// exhausted. During API load spikes SaveBatch would otherwise queue for a
+// connection and can time out halfway through a batch; pausing before the
+// write hands those connections to the API instead.
+type Backpressure struct {
+	usage     func() repository.PoolUsage
+	threshold float64       // saturation above which writes are delayed
+	maxDelay  time.Duration // delay when every connection is acquired
+	sleep     func(ctx context.Context, d time.Duration) error
+
+	throttled  atomic.Int64
+	totalDelay atomic.Int64 // nanoseconds
+	lastDelay  atomic.Int64 // nanoseconds
+}
+
+// BackpressureStats is the throttling state exposed on /status/sync.
+type BackpressureStats struct {
+	repository.PoolUsage
+	Threshold    float64 `json:"threshold"`
+	MaxDelayMs   int64   `json:"max_delay_ms"`
+	Throttled    int64   `json:"throttled"` // batches that waited
+	TotalDelayMs int64   `json:"total_delay_ms"`
+	LastDelayMs  int64   `json:"last_delay_ms"`
+}
+
+// NewBackpressure throttles writes once usage().Saturation exceeds threshold
+// (0..1), waiting up to maxDelay per batch.
+func NewBackpressure(usage func() repository.PoolUsage, threshold float64, maxDelay time.Duration) *Backpressure {
+	return &Backpressure{
+		usage:     usage,
+		threshold: threshold,
+		maxDelay:  maxDelay,
+		sleep:     sleepCtx,
+	}
+}
+
+// backpressureDelay grows linearly from nothing at threshold to maxDelay at
+// full saturation, so a brief spike costs a little and a pinned pool the most.
+func backpressureDelay(saturation, threshold float64, maxDelay time.Duration) time.Duration {
+	if maxDelay <= 0 || saturation <= threshold {
+		return 0
+	}
+	if threshold >= 1 {
+		return maxDelay
+	}
+	frac := (saturation - threshold) / (1 - threshold)
+	if frac > 1 {
+		frac = 1
+	}
+	return time.Duration(math.Round(float64(maxDelay) * frac))
+}
+
+// Wait sleeps for the delay the current pool saturation calls for. A nil
+// Backpressure never waits. It returns early with ctx's error if ctx ends.
+func (b *Backpressure) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	d := backpressureDelay(b.usage().Saturation, b.threshold, b.maxDelay)
+	b.lastDelay.Store(int64(d))
+	if d == 0 {
+		return nil
+	}
+	b.throttled.Add(1)
+	b.totalDelay.Add(int64(d))
+	return b.sleep(ctx, d)
+}
+
+// Stats returns the current pool usage and how often writes have been delayed.
+func (b *Backpressure) Stats() BackpressureStats {
+	return BackpressureStats{
+		PoolUsage:    b.usage(),
+		Threshold:    b.threshold,
+		MaxDelayMs:   b.maxDelay.Milliseconds(),
+		Throttled:    b.throttled.Load(),
+		TotalDelayMs: time.Duration(b.totalDelay.Load()).Milliseconds(),
+		LastDelayMs:  time.Duration(b.lastDelay.Load()).Milliseconds(),
+	}
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/repository"
+)
+
+func TestBackpressureDelay(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		saturation, threshold float64
+		want                  time.Duration
+	}{
+		{0.5, 0.8, 0},
+		{0.8, 0.8, 0},
+		{0.9, 0.8, time.Second},
+		{1.0, 0.8, 2 * time.Second},
+		{1.2, 0.8, 2 * time.Second},
+		{1.0, 1.0, 0},
+		{1.1, 1.0, 2 * time.Second},
+	} {
+		got := backpressureDelay(tc.saturation, tc.threshold, 2*time.Second)
+		if diff := got - tc.want; diff < -time.Millisecond || diff > time.Millisecond {
+			t.Errorf("backpressureDelay(%v, %v) = %v; want %v", tc.saturation, tc.threshold, got, tc.want)
+		}
+	}
+	if d := backpressureDelay(1, 0.8, 0); d != 0 {
+		t.Fatalf("max delay 0 still waits %v", d)
+	}
+}
+
+// TestSaveBatchYieldsToSaturatedPool simulates a DB pool with 19 of 20
+// connections checked out and checks the ingester waits before writing.
+func TestSaveBatchYieldsToSaturatedPool(t *testing.T) {
+	t.Parallel()
+
+	usage := repository.PoolUsage{AcquiredConns: 19, MaxConns: 20, Saturation: 0.95}
+	bp := NewBackpressure(func() repository.PoolUsage { return usage }, 0.8, time.Second)
+	var slept []time.Duration
+	bp.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return context.Canceled // stop before SaveBatch; the service has no repo
+	}
+	svc := &Service{config: Config{ServiceName: "test_ingester", Backpressure: bp}}
+
+	if err := svc.saveBatch(context.Background(), nil, 100); !errors.Is(err, context.Canceled) {
+		t.Fatalf("saveBatch err = %v; want the backoff's context.Canceled", err)
+	}
+	if len(slept) != 1 || slept[0] != 750*time.Millisecond {
+		t.Fatalf("backoff = %v; want one wait of 750ms", slept)
+	}
+	st := bp.Stats()
+	if st.Throttled != 1 || st.LastDelayMs != 750 || st.TotalDelayMs != 750 || st.AcquiredConns != 19 || st.MaxConns != 20 {
+		t.Fatalf("stats = %+v", st)
+	}
+
+	usage = repository.PoolUsage{AcquiredConns: 4, MaxConns: 20, Saturation: 0.2}
+	if err := bp.Wait(context.Background()); err != nil || len(slept) != 1 {
+		t.Fatalf("idle pool: err=%v waits=%v", err, slept)
+	}
+	if st := bp.Stats(); st.Throttled != 1 || st.LastDelayMs != 0 {
+		t.Fatalf("idle pool stats = %+v", st)
+	}
+
+	if err := (*Backpressure)(nil).Wait(context.Background()); err != nil {
+		t.Fatalf("nil backpressure: %v", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"flowscan-clone/internal/cadenceutil"
 	"flowscan-clone/internal/flow"
 	"flowscan-clone/internal/models"
 
@@ -58,6 +59,17 @@ type FetchWarning struct {
 	TxID    string
 	TxIndex int
 	Message string
+	// Kind is the raw.indexing_errors error_hash it is logged under; empty
+	// means "fetch_warning".
+	Kind string
+}
+
+// errorHash is the raw.indexing_errors error_hash to log w under.
+func (w FetchWarning) errorHash() string {
+	if w.Kind == "" {
+		return "fetch_warning"
+	}
+	return w.Kind
 }
 
 // FetchResult holds the data for a single block height
@@ -68,11 +80,21 @@ type FetchResult struct {
 	Events       []models.Event
 	Error        error
 	Warnings     []FetchWarning
+	Node         string // access node that served the block (empty on error)
 }
 
 // Worker is a stateless helper to fetch data for one height
 type Worker struct {
 	client *flow.Client
+	// skipCollections disables the per-collection transaction fallback, so
+	// only the bulk block APIs are used (see Config.SkipCollections).
+	skipCollections bool
+	// skipEventRecheck disables re-fetching bulk results with missing events
+	// (see Config.SkipEventRecheck and recheckEventCounts).
+	skipEventRecheck bool
+	// storeCollections fetches collection membership for raw.collections
+	// (see Config.StoreCollections). It has no effect with skipCollections.
+	storeCollections bool
 }
 
 func NewWorker(client *flow.Client) *Worker {
@@ -110,6 +132,10 @@ func (w *Worker) FetchBlockData(ctx context.Context, height uint64) *FetchResult
 			time.Sleep(10 * time.Second)
 			return true
 		}
+		// The breaker fails fast for every node; repinning would just spin.
+		if _, open := flow.IsCircuitOpen(err); open {
+			return false
+		}
 		// Log unhandled errors for debugging
 		log.Printf("[ingester] Unhandled fetch error for height %d (repin=false): %v", height, err)
 		return false
@@ -163,6 +189,20 @@ func (w *Worker) FetchBlockData(ctx context.Context, height uint64) *FetchResult
 			IsSealed:             true,
 		}
 
+		// Optional: persist each collection's transaction ids (one RPC per guarantee).
+		if w.storeCollections && !w.skipCollections {
+			colls, warns, err := fetchBlockCollections(ctx, pin, block)
+			result.Warnings = append(result.Warnings, warns...)
+			if err != nil {
+				if shouldRepin(err) {
+					continue
+				}
+				result.Error = fmt.Errorf("failed to get collections for block %s: %w", block.ID, err)
+				return result
+			}
+			dbBlock.Collections = colls
+		}
+
 		// 2. Fetch All Transactions & Results for the Block
 		// Try bulk APIs first; fall back to per-collection/per-tx for old spork nodes.
 		// If a node has been flagged as not supporting bulk APIs, skip directly to fallback.
@@ -172,43 +212,20 @@ func (w *Worker) FetchBlockData(ctx context.Context, height uint64) *FetchResult
 		usedBulkTxAPI := false
 		noBulk := pin.NoBulkAPI()
 
-		if noBulk {
-			// Skip bulk API — go straight to per-collection/per-tx fallback.
-			var txWarns []FetchWarning
-			txs, txWarns, err = w.fetchTransactionsViaCollections(ctx, pin, block)
-			result.Warnings = append(result.Warnings, txWarns...)
-			if err != nil {
-				if shouldRepin(err) {
-					continue
-				}
-				result.Error = fmt.Errorf("failed to get transactions via collections for block %s: %w", block.ID, err)
-				return result
-			}
-		} else {
-			txs, err = pin.GetTransactionsByBlockID(ctx, block.ID)
-			if err != nil {
-				if isUnimplementedError(err) {
-					// Old spork node: mark it and fall back to per-collection/per-tx
-					w.client.MarkNoBulkAPI(pin.NodeIndex())
-					var txWarns []FetchWarning
-					txs, txWarns, err = w.fetchTransactionsViaCollections(ctx, pin, block)
-					result.Warnings = append(result.Warnings, txWarns...)
-					if err != nil {
-						if shouldRepin(err) {
-							continue
-						}
-						result.Error = fmt.Errorf("failed to get transactions via collections for block %s: %w", block.ID, err)
-						return result
-					}
-				} else if shouldRepin(err) {
-					continue
-				} else {
-					result.Error = fmt.Errorf("failed to get transactions for block %s: %w", block.ID, err)
-					return result
-				}
-			} else {
-				usedBulkTxAPI = true
+		var txWarns []FetchWarning
+		var bulkUnsupported bool
+		txs, usedBulkTxAPI, bulkUnsupported, txWarns, err = w.fetchBlockTransactions(ctx, pin, block, noBulk)
+		result.Warnings = append(result.Warnings, txWarns...)
+		if bulkUnsupported {
+			// Old spork node: mark it so later heights skip the bulk attempt.
+			w.client.MarkNoBulkAPI(pin.NodeIndex())
+		}
+		if err != nil {
+			if shouldRepin(err) {
+				continue
 			}
+			result.Error = fmt.Errorf("failed to get transactions for block %s: %w", block.ID, err)
+			return result
 		}
 
 		// Track whether we should retry this height with a different node pin.
@@ -240,17 +257,7 @@ func (w *Worker) FetchBlockData(ctx context.Context, height uint64) *FetchResult
 				continue
 			}
 		} else {
-			// Wrap in panic recovery — some old spork event payloads cause the
-			// Cadence JSON decoder to panic inside the Flow SDK.
-			func() {
-				defer func() {
-					if r := recover(); r != nil {
-						err = fmt.Errorf("panic in GetTransactionResultsByBlockID for height %d: %v", height, r)
-						log.Printf("[ingester] Recovered from SDK panic at height %d: %v", height, r)
-					}
-				}()
-				results, err = pin.GetTransactionResultsByBlockID(ctx, block.ID)
-			}()
+			results, err = fetchBlockResults(ctx, pin, block.ID, height)
 			isPanic := err != nil && strings.Contains(err.Error(), "panic in GetTransactionResultsByBlockID")
 			if err != nil {
 				if isUnimplementedError(err) {
@@ -279,7 +286,7 @@ func (w *Worker) FetchBlockData(ctx context.Context, height uint64) *FetchResult
 				} else if shouldRepin(err) {
 					continue
 				} else if isGRPCMessageTooLarge(err) {
-					log.Printf("[ingester] Warn: tx results payload too large for block %s (height=%d), falling back to per-tx calls: %v", block.ID, height, err)
+					log.Printf("[ingester] Warn: tx results payload too large for block %s (height=%d, txs=%d), falling back to per-tx calls: %v", block.ID, height, len(txs), err)
 					var warns []FetchWarning
 					results, rawResultsByIdx, warns, repinRequested, err = w.fetchResultsPerTx(ctx, pin, block.ID, txs, usedBulkTxAPI)
 					result.Warnings = append(result.Warnings, warns...)
@@ -294,6 +301,8 @@ func (w *Worker) FetchBlockData(ctx context.Context, height uint64) *FetchResult
 					result.Error = fmt.Errorf("failed to get transaction results for block %s: %w", block.ID, err)
 					return result
 				}
+			} else if !w.skipEventRecheck {
+				result.Warnings = append(result.Warnings, recheckEventCounts(ctx, pin, block.ID, height, results)...)
 			}
 		}
 
@@ -419,16 +428,20 @@ func (w *Worker) FetchBlockData(ctx context.Context, height uint64) *FetchResult
 			dbTx.ProposalKey = pkJSON
 			dbTx.PayloadSignatures = pSigJSON
 			dbTx.EnvelopeSignatures = eSigJSON
+			if signers := TxSigners(tx); len(signers) > 0 {
+				dbTx.Signers, _ = json.Marshal(signers)
+			}
 
 			if res.Error != nil {
 				dbTx.ErrorMessage = res.Error.Error()
 			}
+			dbTx.Status = models.NormalizeTxStatus(dbTx.ExecutionStatus, dbTx.ErrorMessage)
 
 			// Process Events — check if this tx has raw events from gRPC fallback
 			if rawRes, hasRaw := rawResultsByIdx[txIndex]; hasRaw && rawRes != nil {
 				// Use raw gRPC events (SDK panicked during Cadence decoding)
 				for _, rawEvt := range rawRes.Events {
-					parsed := parseJSONCDCEventPayload(rawEvt.Payload)
+					parsed := cadenceutil.ParseEventPayload(rawEvt.Payload)
 					var payloadJSON []byte
 					if parsed != nil {
 						payloadJSON, _ = json.Marshal(parsed)
@@ -502,6 +515,7 @@ func (w *Worker) FetchBlockData(ctx context.Context, height uint64) *FetchResult
 		result.Block = &dbBlock
 		result.Transactions = dbTxs
 		result.Events = dbEvents
+		result.Node = pin.Node()
 		return result
 	}
 
@@ -519,10 +533,54 @@ func shouldMarkNodeMinHeight(err error) bool {
 	return strings.Contains(msg, "spork root block height")
 }
 
+// errCollectionsSkipped is returned when a node lacks the bulk transaction API
+// and the per-collection fallback is disabled.
+var errCollectionsSkipped = errors.New("node lacks GetTransactionsByBlockID and collection fetching is disabled")
+
+// collectionTxClient is the subset of *flow.PinnedClient used to resolve a
+// block's transactions through its collection guarantees.
+type collectionTxClient interface {
+	GetCollection(ctx context.Context, collID flowsdk.Identifier) (*flowsdk.Collection, error)
+	GetTransaction(ctx context.Context, txID flowsdk.Identifier) (*flowsdk.Transaction, error)
+}
+
+// blockTxClient is the subset of *flow.PinnedClient used to fetch a block's
+// transaction bodies.
+type blockTxClient interface {
+	collectionTxClient
+	GetTransactionsByBlockID(ctx context.Context, blockID flowsdk.Identifier) ([]*flowsdk.Transaction, error)
+}
+
+// fetchBlockTransactions fetches a block's transactions with the bulk
+// GetTransactionsByBlockID API, falling back to walking its collection
+// guarantees when the node does not support it (noBulk, or Unimplemented,
+// reported as bulkUnsupported). With skipCollections the fallback is not
+// attempted and errCollectionsSkipped is returned instead.
+func (w *Worker) fetchBlockTransactions(ctx context.Context, c blockTxClient, block *flowsdk.Block, noBulk bool) (txs []*flowsdk.Transaction, usedBulk, bulkUnsupported bool, warns []FetchWarning, err error) {
+	if !noBulk {
+		txs, err = c.GetTransactionsByBlockID(ctx, block.ID)
+		if err == nil {
+			return txs, true, false, nil, nil
+		}
+		if !isUnimplementedError(err) {
+			return nil, false, false, nil, err
+		}
+		bulkUnsupported = true
+	}
+	if w.skipCollections {
+		return nil, false, bulkUnsupported, nil, fmt.Errorf("block %d: %w", block.Height, errCollectionsSkipped)
+	}
+	txs, warns, err = w.fetchTransactionsViaCollections(ctx, c, block)
+	if err != nil {
+		err = fmt.Errorf("via collections: %w", err)
+	}
+	return txs, false, bulkUnsupported, warns, err
+}
+
 // fetchTransactionsViaCollections fetches all transactions in a block by iterating
 // over its collection guarantees concurrently. Used as fallback for old spork nodes
 // that don't support GetTransactionsByBlockID.
-func (w *Worker) fetchTransactionsViaCollections(ctx context.Context, pin *flow.PinnedClient, block *flowsdk.Block) ([]*flowsdk.Transaction, []FetchWarning, error) {
+func (w *Worker) fetchTransactionsViaCollections(ctx context.Context, pin collectionTxClient, block *flowsdk.Block) ([]*flowsdk.Transaction, []FetchWarning, error) {
 	// First collect all transaction IDs from collections (sequential, fast).
 	type txRef struct {
 		id    flowsdk.Identifier
@@ -575,6 +633,31 @@ func (w *Worker) fetchTransactionsViaCollections(ctx context.Context, pin *flow.
 	return allTxs, warns, nil
 }
 
+// fetchBlockCollections resolves a block's collection guarantees to their
+// transaction ids. Collections the node no longer has are skipped with a warning.
+func fetchBlockCollections(ctx context.Context, c collectionTxClient, block *flowsdk.Block) ([]models.Collection, []FetchWarning, error) {
+	var out []models.Collection
+	var warns []FetchWarning
+	for _, cg := range block.CollectionGuarantees {
+		coll, err := c.GetCollection(ctx, cg.CollectionID)
+		if err != nil {
+			if isMissingCollectionError(err) {
+				warns = append(warns, FetchWarning{
+					Message: fmt.Sprintf("missing collection: block=%d collection_id=%s err=%v", block.Height, cg.CollectionID, err),
+				})
+				continue
+			}
+			return nil, warns, fmt.Errorf("GetCollection(%s): %w", cg.CollectionID, err)
+		}
+		txIDs := make([]string, 0, len(coll.TransactionIDs))
+		for _, id := range coll.TransactionIDs {
+			txIDs = append(txIDs, id.String())
+		}
+		out = append(out, models.Collection{ID: cg.CollectionID.String(), BlockHeight: block.Height, TransactionIDs: txIDs})
+	}
+	return out, warns, nil
+}
+
 func isMissingCollectionError(err error) bool {
 	if err == nil {
 		return false
@@ -741,6 +824,101 @@ func (w *Worker) fetchResultsPerTx(ctx context.Context, pin *flow.PinnedClient,
 	return compact, rawResults, warns, false, nil
 }
 
+// blockResultsClient is the subset of *flow.PinnedClient used to fetch a
+// block's transaction results.
+type blockResultsClient interface {
+	GetTransactionResultsByBlockID(ctx context.Context, blockID flowsdk.Identifier) ([]*flowsdk.TransactionResult, error)
+	GetTransactionResultByIndex(ctx context.Context, blockID flowsdk.Identifier, index uint32) (*flowsdk.TransactionResult, error)
+}
+
+// fetchBlockResults fetches all results for a block with the bulk API,
+// turning SDK panics into errors. Very busy blocks can exceed the gRPC receive
+// limit (FLOW_GRPC_MAX_MSG_MB); that error is returned as is so FetchBlockData
+// can fall back to fetchResultsPerTx.
+func fetchBlockResults(ctx context.Context, c blockResultsClient, blockID flowsdk.Identifier, height uint64) (results []*flowsdk.TransactionResult, err error) {
+	// Wrap in panic recovery — some old spork event payloads cause the
+	// Cadence JSON decoder to panic inside the Flow SDK.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in GetTransactionResultsByBlockID for height %d: %v", height, r)
+			log.Printf("[ingester] Recovered from SDK panic at height %d: %v", height, r)
+		}
+	}()
+	return c.GetTransactionResultsByBlockID(ctx, blockID)
+}
+
+// missingEvents is how many events a transaction result evidently lacks.
+// Flow numbers a transaction's events 0..n-1, so the highest index says how
+// many there should be; events dropped from the end cannot be detected.
+func missingEvents(events []flowsdk.Event) int {
+	if len(events) == 0 {
+		return 0
+	}
+	seen := make(map[int]struct{}, len(events))
+	maxIndex := 0
+	for _, e := range events {
+		seen[e.EventIndex] = struct{}{}
+		maxIndex = max(maxIndex, e.EventIndex)
+	}
+	return maxIndex + 1 - len(seen)
+}
+
+// recheckEventCounts guards against a bulk results call that succeeds but
+// returns some transactions with events missing, which would otherwise be
+// saved with an undercounted block event_count. Block headers carry no event
+// count, so the block's expected total is the sum of each transaction's
+// expected count (see missingEvents). Short results are re-fetched one at a
+// time and replaced when the copy is complete; those still short are returned
+// as block_tx_count_mismatch warnings for repair_indexing_anomalies.
+func recheckEventCounts(ctx context.Context, c blockResultsClient, blockID flowsdk.Identifier, height uint64, results []*flowsdk.TransactionResult) []FetchWarning {
+	var short []int
+	got, want := 0, 0
+	for i, r := range results {
+		if r == nil {
+			continue
+		}
+		missing := missingEvents(r.Events)
+		got += len(r.Events)
+		want += len(r.Events) + missing
+		if missing > 0 {
+			short = append(short, i)
+		}
+	}
+	if len(short) == 0 {
+		return nil
+	}
+	log.Printf("[ingester] Warn: bulk results for block %s (height=%d) have %d of %d events, re-fetching %d txs individually", blockID, height, got, want, len(short))
+
+	var warns []FetchWarning
+	for _, i := range short {
+		var refetched *flowsdk.TransactionResult
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic in GetTransactionResultByIndex for height %d idx %d: %v", height, i, r)
+				}
+			}()
+			refetched, err = c.GetTransactionResultByIndex(ctx, blockID, uint32(i))
+		}()
+		if err == nil && refetched != nil && missingEvents(refetched.Events) == 0 && len(refetched.Events) >= len(results[i].Events) {
+			results[i] = refetched
+			continue
+		}
+		msg := fmt.Sprintf("tx result has %d events but event indices reach %d", len(results[i].Events), len(results[i].Events)+missingEvents(results[i].Events))
+		if err != nil {
+			msg += fmt.Sprintf("; re-fetch failed: %v", err)
+		}
+		warns = append(warns, FetchWarning{
+			TxID:    results[i].TransactionID.String(),
+			TxIndex: i,
+			Message: msg,
+			Kind:    "block_tx_count_mismatch",
+		})
+	}
+	return warns
+}
+
 // fetchResultsAllRaw fetches ALL transaction results using raw gRPC with JSON-CDC encoding,
 // completely bypassing the Flow SDK's Cadence decoder. Used for pre-Crescendo blocks
 // where old Cadence types like RestrictedType cause SDK panics or CCF decode errors.
@@ -1031,7 +1209,7 @@ func (w *Worker) safeExtractEventPayload(evt flowsdk.Event) (payload interface{}
 		if r := recover(); r != nil {
 			// Cadence decoder panicked — try raw JSON-CDC parsing of the event Payload
 			if len(evt.Payload) > 0 {
-				parsed := parseJSONCDCEventPayload(evt.Payload)
+				parsed := cadenceutil.ParseEventPayload(evt.Payload)
 				if parsed != nil {
 					payload = parsed
 					return
@@ -1042,180 +1220,3 @@ func (w *Worker) safeExtractEventPayload(evt flowsdk.Event) (payload interface{}
 	}()
 	return w.flattenCadenceValue(evt.Value)
 }
-
-// parseJSONCDCEventPayload parses a JSON-CDC event payload into a flat map
-// without using Cadence type system. This avoids "Restriction kind is not supported" panics.
-func parseJSONCDCEventPayload(payload []byte) map[string]interface{} {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(payload, &raw); err != nil {
-		return nil
-	}
-
-	value, ok := raw["value"].(map[string]interface{})
-	if !ok {
-		return nil
-	}
-
-	fields, ok := value["fields"].([]interface{})
-	if !ok {
-		return nil
-	}
-
-	result := make(map[string]interface{}, len(fields))
-	for _, f := range fields {
-		field, ok := f.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		name, _ := field["name"].(string)
-		if name == "" {
-			continue
-		}
-		val, _ := field["value"].(map[string]interface{})
-		if val == nil {
-			continue
-		}
-		result[name] = extractCDCValue(val)
-	}
-	return result
-}
-
-// extractCDCValue recursively extracts a value from JSON-CDC format.
-func extractCDCValue(v map[string]interface{}) interface{} {
-	if v == nil {
-		return nil
-	}
-
-	typ, _ := v["type"].(string)
-	value := v["value"]
-
-	switch typ {
-	case "Optional":
-		if value == nil {
-			return nil
-		}
-		inner, ok := value.(map[string]interface{})
-		if !ok {
-			return nil
-		}
-		return extractCDCValue(inner)
-
-	case "Bool":
-		return value
-
-	case "String", "Character":
-		return value
-
-	case "Address":
-		// Strip 0x prefix to match SDK's .Hex() output
-		if s, ok := value.(string); ok {
-			return strings.TrimPrefix(s, "0x")
-		}
-		return value
-
-	case "UInt8", "UInt16", "UInt32", "UInt64", "UInt128", "UInt256",
-		"Int8", "Int16", "Int32", "Int64", "Int128", "Int256",
-		"Word8", "Word16", "Word32", "Word64",
-		"UFix64", "Fix64":
-		return value
-
-	case "Array":
-		arr, ok := value.([]interface{})
-		if !ok {
-			return nil
-		}
-		result := make([]interface{}, 0, len(arr))
-		for _, item := range arr {
-			m, ok := item.(map[string]interface{})
-			if !ok {
-				result = append(result, item)
-				continue
-			}
-			result = append(result, extractCDCValue(m))
-		}
-		return result
-
-	case "Dictionary":
-		arr, ok := value.([]interface{})
-		if !ok {
-			return nil
-		}
-		result := make(map[string]interface{}, len(arr))
-		for _, item := range arr {
-			pair, ok := item.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			keyMap, _ := pair["key"].(map[string]interface{})
-			valMap, _ := pair["value"].(map[string]interface{})
-			if keyMap == nil {
-				continue
-			}
-			key := fmt.Sprintf("%v", extractCDCValue(keyMap))
-			if valMap != nil {
-				result[key] = extractCDCValue(valMap)
-			} else {
-				result[key] = nil
-			}
-		}
-		return result
-
-	case "Struct", "Resource", "Event", "Contract", "Enum":
-		inner, ok := value.(map[string]interface{})
-		if !ok {
-			return value
-		}
-		fields, ok := inner["fields"].([]interface{})
-		if !ok {
-			return value
-		}
-		result := make(map[string]interface{}, len(fields))
-		for _, f := range fields {
-			field, ok := f.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			name, _ := field["name"].(string)
-			val, _ := field["value"].(map[string]interface{})
-			if name != "" && val != nil {
-				result[name] = extractCDCValue(val)
-			}
-		}
-		return result
-
-	case "Type":
-		inner, ok := value.(map[string]interface{})
-		if !ok {
-			return value
-		}
-		staticType, ok := inner["staticType"].(map[string]interface{})
-		if ok {
-			if typeID, ok := staticType["typeID"].(string); ok {
-				return typeID
-			}
-		}
-		return inner
-
-	case "Path":
-		inner, ok := value.(map[string]interface{})
-		if !ok {
-			return value
-		}
-		domain, _ := inner["domain"].(string)
-		identifier, _ := inner["identifier"].(string)
-		return fmt.Sprintf("/%s/%s", domain, identifier)
-
-	case "Capability":
-		inner, ok := value.(map[string]interface{})
-		if !ok {
-			return value
-		}
-		return inner
-
-	case "Void":
-		return nil
-
-	default:
-		return value
-	}
-}
@@ -1,9 +1,14 @@
 package ingester
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 
+	flowsdk "github.com/onflow/flow-go-sdk"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -62,3 +67,223 @@ func TestIsMissingCollectionError(t *testing.T) {
 		})
 	}
 }
+
+type oversizedResultsClient struct {
+	mu        sync.Mutex
+	bulkCalls int
+	perTx     map[uint32]int
+}
+
+func (c *oversizedResultsClient) GetTransactionResultsByBlockID(ctx context.Context, blockID flowsdk.Identifier) ([]*flowsdk.TransactionResult, error) {
+	c.mu.Lock()
+	c.bulkCalls++
+	c.mu.Unlock()
+	return nil, status.Error(codes.ResourceExhausted, "grpc: received message larger than max (80000000 vs. 67108864)")
+}
+
+func (c *oversizedResultsClient) GetTransactionResultByIndex(ctx context.Context, blockID flowsdk.Identifier, index uint32) (*flowsdk.TransactionResult, error) {
+	c.mu.Lock()
+	c.perTx[index]++
+	c.mu.Unlock()
+	return &flowsdk.TransactionResult{Status: flowsdk.TransactionStatusSealed, ComputationUsage: uint64(index) + 1}, nil
+}
+
+// An oversized bulk response must reach FetchBlockData unchanged and without
+// per-tx calls: the fallback belongs to fetchResultsPerTx, which keeps the raw
+// CCF results, per-tx warnings and repin handling.
+func TestFetchBlockResultsSurfacesOversizedMessage(t *testing.T) {
+	t.Parallel()
+
+	client := &oversizedResultsClient{perTx: map[uint32]int{}}
+	results, err := fetchBlockResults(context.Background(), client, flowsdk.EmptyID, 100000000)
+	if !isGRPCMessageTooLarge(err) {
+		t.Fatalf("expected oversized message error, got %v", err)
+	}
+	if results != nil {
+		t.Fatalf("expected no results, got %d", len(results))
+	}
+	if client.bulkCalls != 1 {
+		t.Fatalf("expected 1 bulk call, got %d", client.bulkCalls)
+	}
+	if len(client.perTx) != 0 {
+		t.Fatalf("expected no per-tx calls, got %v", client.perTx)
+	}
+}
+
+func TestIsGRPCMessageTooLarge(t *testing.T) {
+	t.Parallel()
+
+	if !isGRPCMessageTooLarge(status.Error(codes.ResourceExhausted, "grpc: received message larger than max (1 vs. 0)")) {
+		t.Fatal("expected oversized message to be detected")
+	}
+	if isGRPCMessageTooLarge(status.Error(codes.ResourceExhausted, "rate limit exceeded")) {
+		t.Fatal("rate limiting must not be treated as an oversized message")
+	}
+}
+
+// countingTxClient serves a block's transactions and records which APIs were used.
+type countingTxClient struct {
+	bulkErr         error
+	bulkCalls       int
+	collectionCalls int
+	txCalls         int
+	txs             []*flowsdk.Transaction
+}
+
+func (c *countingTxClient) GetTransactionsByBlockID(ctx context.Context, blockID flowsdk.Identifier) ([]*flowsdk.Transaction, error) {
+	c.bulkCalls++
+	if c.bulkErr != nil {
+		return nil, c.bulkErr
+	}
+	return c.txs, nil
+}
+
+func (c *countingTxClient) GetCollection(ctx context.Context, collID flowsdk.Identifier) (*flowsdk.Collection, error) {
+	c.collectionCalls++
+	ids := make([]flowsdk.Identifier, len(c.txs))
+	for i, tx := range c.txs {
+		ids[i] = tx.ID()
+	}
+	return &flowsdk.Collection{TransactionIDs: ids}, nil
+}
+
+func (c *countingTxClient) GetTransaction(ctx context.Context, txID flowsdk.Identifier) (*flowsdk.Transaction, error) {
+	c.txCalls++
+	for _, tx := range c.txs {
+		if tx.ID() == txID {
+			return tx, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "transaction not found")
+}
+
+func testBlockWithCollections(n int) *flowsdk.Block {
+	block := &flowsdk.Block{}
+	block.Height = 100000000
+	for i := 0; i < n; i++ {
+		block.CollectionGuarantees = append(block.CollectionGuarantees, &flowsdk.CollectionGuarantee{})
+	}
+	return block
+}
+
+func TestFetchBlockTransactionsSkipCollectionsUsesBulkAPI(t *testing.T) {
+	t.Parallel()
+
+	client := &countingTxClient{txs: []*flowsdk.Transaction{
+		flowsdk.NewTransaction().SetComputeLimit(100),
+		flowsdk.NewTransaction().SetComputeLimit(200),
+	}}
+	w := &Worker{skipCollections: true}
+
+	txs, usedBulk, unsupported, _, err := w.fetchBlockTransactions(context.Background(), client, testBlockWithCollections(2), false)
+	if err != nil {
+		t.Fatalf("fetchBlockTransactions: %v", err)
+	}
+	if !usedBulk || unsupported || len(txs) != 2 {
+		t.Fatalf("usedBulk=%v unsupported=%v txs=%d", usedBulk, unsupported, len(txs))
+	}
+	if client.bulkCalls != 1 || client.collectionCalls != 0 || client.txCalls != 0 {
+		t.Fatalf("bulk=%d collection=%d tx=%d, want only the bulk call", client.bulkCalls, client.collectionCalls, client.txCalls)
+	}
+}
+
+func TestFetchBlockTransactionsSkipCollectionsNeverFallsBack(t *testing.T) {
+	t.Parallel()
+
+	client := &countingTxClient{
+		bulkErr: status.Error(codes.Unimplemented, "unknown method GetTransactionsByBlockID"),
+		txs:     []*flowsdk.Transaction{flowsdk.NewTransaction()},
+	}
+	w := &Worker{skipCollections: true}
+
+	_, _, unsupported, _, err := w.fetchBlockTransactions(context.Background(), client, testBlockWithCollections(2), false)
+	if !errors.Is(err, errCollectionsSkipped) || !unsupported {
+		t.Fatalf("err=%v unsupported=%v, want errCollectionsSkipped on an unsupported node", err, unsupported)
+	}
+	if _, _, _, _, err := w.fetchBlockTransactions(context.Background(), client, testBlockWithCollections(2), true); !errors.Is(err, errCollectionsSkipped) {
+		t.Fatalf("noBulk err=%v, want errCollectionsSkipped", err)
+	}
+	if client.collectionCalls != 0 || client.txCalls != 0 {
+		t.Fatalf("collection=%d tx=%d, want no collection fetching", client.collectionCalls, client.txCalls)
+	}
+}
+
+func TestFetchBlockTransactionsFallsBackToCollections(t *testing.T) {
+	t.Parallel()
+
+	client := &countingTxClient{
+		bulkErr: status.Error(codes.Unimplemented, "unknown method GetTransactionsByBlockID"),
+		txs:     []*flowsdk.Transaction{flowsdk.NewTransaction().SetComputeLimit(100)},
+	}
+	w := &Worker{}
+
+	txs, usedBulk, unsupported, _, err := w.fetchBlockTransactions(context.Background(), client, testBlockWithCollections(1), false)
+	if err != nil || usedBulk || !unsupported || len(txs) != 1 {
+		t.Fatalf("txs=%d usedBulk=%v unsupported=%v err=%v", len(txs), usedBulk, unsupported, err)
+	}
+	if client.collectionCalls != 1 || client.txCalls != 1 {
+		t.Fatalf("collection=%d tx=%d, want the per-collection fallback", client.collectionCalls, client.txCalls)
+	}
+}
+
+// undercountedResultsClient serves re-fetches for recheckEventCounts from
+// perTx, keyed by transaction index.
+type undercountedResultsClient struct {
+	perTx map[uint32]*flowsdk.TransactionResult
+	calls []uint32
+}
+
+func (c *undercountedResultsClient) GetTransactionResultsByBlockID(ctx context.Context, blockID flowsdk.Identifier) ([]*flowsdk.TransactionResult, error) {
+	return nil, errors.New("not used")
+}
+
+func (c *undercountedResultsClient) GetTransactionResultByIndex(ctx context.Context, blockID flowsdk.Identifier, index uint32) (*flowsdk.TransactionResult, error) {
+	c.calls = append(c.calls, index)
+	if r, ok := c.perTx[index]; ok {
+		return r, nil
+	}
+	return nil, status.Error(codes.NotFound, "transaction result not found")
+}
+
+func resultWithEvents(indices ...int) *flowsdk.TransactionResult {
+	r := &flowsdk.TransactionResult{Status: flowsdk.TransactionStatusSealed}
+	for _, i := range indices {
+		r.Events = append(r.Events, flowsdk.Event{Type: "A.1654653399040a61.FlowToken.TokensDeposited", EventIndex: i})
+	}
+	return r
+}
+
+func TestRecheckEventCountsRefetchesUndercountedResults(t *testing.T) {
+	t.Parallel()
+
+	// The bulk call succeeded, but tx 1 lost event 1 and tx 2 lost event 2.
+	bulk := []*flowsdk.TransactionResult{
+		resultWithEvents(0, 1),
+		resultWithEvents(0, 2),
+		resultWithEvents(0, 1, 3),
+		resultWithEvents(),
+	}
+	client := &undercountedResultsClient{perTx: map[uint32]*flowsdk.TransactionResult{
+		1: resultWithEvents(0, 1, 2), // the individual fetch is complete
+		2: resultWithEvents(0, 1, 3), // still short
+	}}
+
+	warns := recheckEventCounts(context.Background(), client, flowsdk.EmptyID, 100000000, bulk)
+	if fmt.Sprint(client.calls) != "[1 2]" {
+		t.Fatalf("re-fetched %v; want [1 2]", client.calls)
+	}
+	if len(bulk[1].Events) != 3 {
+		t.Fatalf("tx 1 not replaced by the complete result: %d events", len(bulk[1].Events))
+	}
+	if len(warns) != 1 || warns[0].TxIndex != 2 || warns[0].errorHash() != "block_tx_count_mismatch" {
+		t.Fatalf("warnings = %+v; want one block_tx_count_mismatch for tx 2", warns)
+	}
+
+	client.calls = nil
+	if warns := recheckEventCounts(context.Background(), client, flowsdk.EmptyID, 100000000, []*flowsdk.TransactionResult{resultWithEvents(0, 1, 2), nil}); warns != nil || client.calls != nil {
+		t.Fatalf("complete results: warns=%v refetches=%v", warns, client.calls)
+	}
+	if (FetchWarning{}).errorHash() != "fetch_warning" {
+		t.Fatal("plain warnings must keep the fetch_warning hash")
+	}
+}
@@ -3,6 +3,8 @@ package ingester
 import (
 	"context"
 	"log"
+	"sort"
+	"sync"
 	"time"
 
 	"flowscan-clone/internal/repository"
@@ -13,17 +15,40 @@ import (
 // 2. Reap expired leases (crashed workers)
 // 3. Detect gaps in lease coverage
 // 4. Alert on permanently failed (dead letter) leases
+// 5. Track the oldest outstanding lease per worker type and alert when stuck
 type CheckpointCommitter struct {
-	repo          *repository.Repository
-	workerTypes   []string
-	lastGapScan   time.Time
-	lastReapCheck time.Time
+	repo            *repository.Repository
+	workerTypes     []string
+	stuckLeaseAfter time.Duration
+	lastGapScan     time.Time
+	lastReapCheck   time.Time
+
+	mu        sync.Mutex
+	leaseAges map[string]LeaseAgeStats
+}
+
+// LeaseAgeStats is the oldest outstanding lease of one worker type, as last
+// seen by the committer. Age is zero when the worker has none.
+type LeaseAgeStats struct {
+	WorkerType string    `json:"worker_type"`
+	AgeSeconds float64   `json:"age_seconds"`
+	FromHeight uint64    `json:"from_height,omitempty"`
+	ToHeight   uint64    `json:"to_height,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	Attempt    int       `json:"attempt,omitempty"`
+	Stuck      bool      `json:"stuck"`
+	CheckedAt  time.Time `json:"checked_at"`
 }
 
-func NewCheckpointCommitter(repo *repository.Repository, workerTypes []string) *CheckpointCommitter {
+// NewCheckpointCommitter creates a committer for workerTypes. An outstanding
+// lease older than stuckLeaseAfter is logged as an alert (0 disables the
+// alert; ages are still tracked).
+func NewCheckpointCommitter(repo *repository.Repository, workerTypes []string, stuckLeaseAfter time.Duration) *CheckpointCommitter {
 	return &CheckpointCommitter{
-		repo:        repo,
-		workerTypes: workerTypes,
+		repo:            repo,
+		workerTypes:     workerTypes,
+		stuckLeaseAfter: stuckLeaseAfter,
+		leaseAges:       make(map[string]LeaseAgeStats),
 	}
 }
 
@@ -54,6 +79,7 @@ func (c *CheckpointCommitter) runLoop(ctx context.Context) {
 			if time.Since(c.lastGapScan) > 60*time.Second {
 				c.detectGaps(ctx)
 				c.detectDeadLeases(ctx)
+				c.checkLeaseAges(ctx)
 				c.lastGapScan = time.Now()
 			}
 		}
@@ -116,3 +142,54 @@ func (c *CheckpointCommitter) detectDeadLeases(ctx context.Context) {
 			d.WorkerType, d.FromHeight, d.ToHeight, d.Attempt)
 	}
 }
+
+// checkLeaseAges records the oldest outstanding lease of each worker type and
+// alerts on those older than stuckLeaseAfter. Leases expire after 5 minutes,
+// so an old one has been failed and reclaimed repeatedly: the worker is most
+// likely stuck on a poison-pill range.
+func (c *CheckpointCommitter) checkLeaseAges(ctx context.Context) {
+	now := time.Now()
+	for _, wType := range c.workerTypes {
+		lease, err := c.repo.GetOldestLeaseAge(ctx, wType)
+		if err != nil {
+			log.Printf("[Committer] Lease age check failed for %s: %v", wType, err)
+			continue
+		}
+		st := leaseAgeStats(wType, lease, c.stuckLeaseAfter, now)
+		c.mu.Lock()
+		c.leaseAges[wType] = st
+		c.mu.Unlock()
+		if st.Stuck {
+			log.Printf("[Committer] ALERT: %s lease [%d, %d) outstanding for %s (status=%s, attempt=%d) — worker may be stuck on this range",
+				wType, st.FromHeight, st.ToHeight, lease.Age.Round(time.Second), st.Status, st.Attempt)
+		}
+	}
+}
+
+// leaseAgeStats turns the oldest outstanding lease (nil when there is none)
+// into the gauge value, flagged stuck when older than stuckAfter.
+func leaseAgeStats(workerType string, lease *repository.LeaseAge, stuckAfter time.Duration, now time.Time) LeaseAgeStats {
+	st := LeaseAgeStats{WorkerType: workerType, CheckedAt: now}
+	if lease == nil {
+		return st
+	}
+	st.AgeSeconds = lease.Age.Seconds()
+	st.FromHeight = lease.FromHeight
+	st.ToHeight = lease.ToHeight
+	st.Status = lease.Status
+	st.Attempt = lease.Attempt
+	st.Stuck = stuckAfter > 0 && lease.Age > stuckAfter
+	return st
+}
+
+// LeaseAges returns the last lease age check of every worker type, by name.
+func (c *CheckpointCommitter) LeaseAges() []LeaseAgeStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]LeaseAgeStats, 0, len(c.leaseAges))
+	for _, st := range c.leaseAges {
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].WorkerType < out[j].WorkerType })
+	return out
+}
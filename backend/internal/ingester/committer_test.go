@@ -0,0 +1,30 @@
+package ingester
+
+import (
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/repository"
+)
+
+func TestLeaseAgeStats(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	idle := leaseAgeStats("token_worker", nil, 30*time.Minute, now)
+	if idle.AgeSeconds != 0 || idle.Stuck || idle.CheckedAt != now {
+		t.Fatalf("no lease: %+v", idle)
+	}
+
+	lease := &repository.LeaseAge{WorkerType: "token_worker", FromHeight: 1000, ToHeight: 2000, Status: "FAILED", Attempt: 7, Age: 2 * time.Hour}
+	st := leaseAgeStats("token_worker", lease, 30*time.Minute, now)
+	if st.AgeSeconds != 7200 || !st.Stuck || st.FromHeight != 1000 || st.ToHeight != 2000 || st.Attempt != 7 {
+		t.Fatalf("stuck lease: %+v", st)
+	}
+	if leaseAgeStats("token_worker", lease, 3*time.Hour, now).Stuck {
+		t.Fatal("lease under the threshold flagged stuck")
+	}
+	if leaseAgeStats("token_worker", lease, 0, now).Stuck {
+		t.Fatal("alert disabled but lease flagged stuck")
+	}
+}
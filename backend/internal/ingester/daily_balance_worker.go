@@ -21,6 +21,10 @@ func (w *DailyBalanceWorker) Name() string {
 	return "daily_balance_worker"
 }
 
+func (w *DailyBalanceWorker) SchemaVersion() int {
+	return 1
+}
+
 func (w *DailyBalanceWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	events, err := w.repo.GetTokenTransfersByRange(ctx, fromHeight, toHeight, false)
 	if err != nil {
@@ -19,6 +19,10 @@ func (w *DailyStatsWorker) Name() string {
 	return "daily_stats_worker"
 }
 
+func (w *DailyStatsWorker) SchemaVersion() int {
+	return 1
+}
+
 func (w *DailyStatsWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	if toHeight <= fromHeight {
 		return nil
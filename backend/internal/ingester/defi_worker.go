@@ -23,6 +23,10 @@ func (w *DefiWorker) Name() string {
 	return "defi_worker"
 }
 
+func (w *DefiWorker) SchemaVersion() int {
+	return 1
+}
+
 // Known DEX contract patterns on Flow mainnet.
 var knownDEXPatterns = []struct {
 	// Substring that must appear in the event type
@@ -0,0 +1,163 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"flowscan-clone/internal/repository"
+)
+
+// EventRetentionConfig controls the raw.events partition retention job.
+type EventRetentionConfig struct {
+	Months           int           // keep partitions newer than this many months (required, > 0)
+	Interval         time.Duration // how often to re-check (default 24h)
+	Drop             bool          // drop detached partitions instead of leaving them for archival
+	Confirm          bool          // must be true to detach/drop anything; otherwise the job only reports
+	DryRun           bool          // report only, even when Confirm is set
+	CheckpointPrefix string        // history deriver checkpoint prefix (default: "history_deriver")
+	// Workers are the enabled block-range processors that derive from
+	// raw.events; each one's checkpoint must be past a partition before it
+	// is detached.
+	Workers []string
+}
+
+// EventRetention detaches (and optionally drops) raw.events partitions older
+// than the configured cutoff, once the history deriver and every enabled
+// worker have confirmed derived data for their whole height range.
+type EventRetention struct {
+	repo *repository.Repository
+	cfg  EventRetentionConfig
+}
+
+func NewEventRetention(repo *repository.Repository, cfg EventRetentionConfig) *EventRetention {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 24 * time.Hour
+	}
+	if cfg.CheckpointPrefix == "" {
+		cfg.CheckpointPrefix = "history_deriver"
+	}
+	return &EventRetention{repo: repo, cfg: cfg}
+}
+
+func (e *EventRetention) Start(ctx context.Context) {
+	mode := "dry-run"
+	if e.cfg.Confirm && !e.cfg.DryRun {
+		mode = "detach"
+		if e.cfg.Drop {
+			mode = "drop"
+		}
+	}
+	log.Printf("[event_retention] Starting (months=%d, mode=%s, interval=%s)", e.cfg.Months, mode, e.cfg.Interval)
+
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.RunOnce(ctx); err != nil {
+			log.Printf("[event_retention] Error: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce evaluates every partition older than the cutoff and detaches the
+// ones whose derived data is complete. In dry-run mode (or without Confirm)
+// it only logs what would be removed.
+func (e *EventRetention) RunOnce(ctx context.Context) error {
+	if e.cfg.Months <= 0 {
+		return fmt.Errorf("retention months must be > 0")
+	}
+	cutoff := time.Now().UTC().AddDate(0, -e.cfg.Months, 0)
+
+	parts, cutoffHeight, err := e.repo.ListEventPartitionsOlderThan(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	up, err := e.repo.GetLastIndexedHeight(ctx, e.cfg.CheckpointPrefix)
+	if err != nil {
+		return fmt.Errorf("read %s checkpoint: %w", e.cfg.CheckpointPrefix, err)
+	}
+	down, err := e.repo.GetLastIndexedHeight(ctx, e.cfg.CheckpointPrefix+"_down")
+	if err != nil {
+		return fmt.Errorf("read %s_down checkpoint: %w", e.cfg.CheckpointPrefix, err)
+	}
+	minRaw, _, _, err := e.repo.GetBlockRange(ctx)
+	if err != nil {
+		return fmt.Errorf("read raw block range: %w", err)
+	}
+	workerFloor, floorWorker, err := e.workerFloor(ctx)
+	if err != nil {
+		return err
+	}
+
+	apply := e.cfg.Confirm && !e.cfg.DryRun
+	for _, p := range parts {
+		if !derivedRangeComplete(p.FromHeight, p.ToHeight, minRaw, down, up) {
+			log.Printf("[event_retention] Skip %s [%d, %d): derived data not confirmed (derived range [%d, %d), min raw %d)",
+				p.QualifiedName(), p.FromHeight, p.ToHeight, down, up, minRaw)
+			continue
+		}
+		if floorWorker != "" && workerFloor < p.ToHeight {
+			log.Printf("[event_retention] Skip %s [%d, %d): %s checkpoint %d is below the partition",
+				p.QualifiedName(), p.FromHeight, p.ToHeight, floorWorker, workerFloor)
+			continue
+		}
+		if !apply {
+			log.Printf("[event_retention] Dry-run: would %s %s [%d, %d) (cutoff %s, height %d)",
+				e.action(), p.QualifiedName(), p.FromHeight, p.ToHeight, cutoff.Format(time.RFC3339), cutoffHeight)
+			continue
+		}
+		if err := e.repo.DetachPartition(ctx, p, e.cfg.Drop); err != nil {
+			return err
+		}
+		log.Printf("[event_retention] %s %s [%d, %d)", e.action(), p.QualifiedName(), p.FromHeight, p.ToHeight)
+	}
+	return nil
+}
+
+// workerFloor returns the lowest checkpoint among the configured workers and
+// the worker that holds it, or "" when no workers are configured.
+func (e *EventRetention) workerFloor(ctx context.Context) (uint64, string, error) {
+	var floor uint64
+	var name string
+	for _, w := range e.cfg.Workers {
+		h, err := e.repo.GetLastIndexedHeight(ctx, w)
+		if err != nil {
+			return 0, "", fmt.Errorf("read %s checkpoint: %w", w, err)
+		}
+		if name == "" || h < floor {
+			floor, name = h, w
+		}
+	}
+	return floor, name, nil
+}
+
+func (e *EventRetention) action() string {
+	if e.cfg.Drop {
+		return "drop"
+	}
+	return "detach"
+}
+
+// derivedRangeComplete reports whether the history deriver has processed the
+// whole [from, to) range. The deriver covers [down, up): the down cursor walks
+// toward genesis and the up cursor toward the live workers. The down cursor
+// stops at the lowest raw height, so a partition that starts below minRaw is
+// covered once the cursor reaches minRaw. A zero down cursor is
+// indistinguishable from "not started", so it never confirms anything.
+func derivedRangeComplete(from, to, minRaw, down, up uint64) bool {
+	if down == 0 {
+		return false
+	}
+	return down <= max(from, minRaw) && up >= to
+}
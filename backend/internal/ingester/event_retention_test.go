@@ -0,0 +1,27 @@
+package ingester
+
+import "testing"
+
+func TestDerivedRangeComplete(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		from, to uint64
+		minRaw   uint64
+		down, up uint64
+		want     bool
+	}{
+		{"covered", 10000000, 20000000, 1, 5000000, 90000000, true},
+		{"down cursor above range", 10000000, 20000000, 1, 15000000, 90000000, false},
+		{"up cursor below range end", 10000000, 20000000, 1, 5000000, 19999999, false},
+		{"downward scan not started", 0, 10000000, 1, 0, 90000000, false},
+		{"oldest partition, cursor at min raw", 0, 10000000, 7000000, 7000000, 90000000, true},
+		{"oldest partition, cursor above min raw", 0, 10000000, 7000000, 8000000, 90000000, false},
+	}
+	for _, tc := range cases {
+		if got := derivedRangeComplete(tc.from, tc.to, tc.minRaw, tc.down, tc.up); got != tc.want {
+			t.Errorf("%s: derivedRangeComplete = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
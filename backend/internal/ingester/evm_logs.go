@@ -0,0 +1,100 @@
+package ingester
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"flowscan-clone/internal/models"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// maxEVMLogTopics is the number of topic columns in app.evm_logs (LOG0..LOG4).
+const maxEVMLogTopics = 4
+
+// decodeEVMLogs decodes the logs of a decoded EVM.TransactionExecuted payload.
+// Flow EVM emits them as the RLP encoding of the receipt's []*types.Log, which
+// raw.events stores as a [UInt8] array (or hex). Logs come back in emission
+// order with LogIndex set; the caller sets the hash and Flow identifiers.
+func decodeEVMLogs(payload map[string]interface{}) ([]models.EVMLog, error) {
+	raw := extractEVMBytes(payload["logs"])
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var decoded []*types.Log
+	if err := rlp.DecodeBytes(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("decode evm logs: %w", err)
+	}
+	out := make([]models.EVMLog, 0, len(decoded))
+	for i, l := range decoded {
+		if l == nil {
+			continue
+		}
+		if len(l.Topics) > maxEVMLogTopics {
+			return nil, fmt.Errorf("decode evm logs: log %d has %d topics", i, len(l.Topics))
+		}
+		topics := make([]string, len(l.Topics))
+		for j, t := range l.Topics {
+			topics[j] = hex.EncodeToString(t.Bytes())
+		}
+		out = append(out, models.EVMLog{
+			LogIndex: i,
+			Address:  hex.EncodeToString(l.Address.Bytes()),
+			Topics:   topics,
+			Data:     hex.EncodeToString(l.Data),
+		})
+	}
+	return out, nil
+}
+
+// evmLogsFromEvent decodes the logs of one EVM.TransactionExecuted event whose
+// payload has already been decoded, keyed by the EVM hash h.
+func evmLogsFromEvent(evt models.Event, h string, payload map[string]interface{}) ([]models.EVMLog, error) {
+	logs, err := decodeEVMLogs(payload)
+	if err != nil {
+		return nil, err
+	}
+	for i := range logs {
+		logs[i].EVMHash = h
+		logs[i].BlockHeight = evt.BlockHeight
+		logs[i].TransactionID = evt.TransactionID
+		logs[i].Timestamp = evt.Timestamp
+	}
+	return logs, nil
+}
+
+// ParseEVMLogs decodes the logs of the EVM.TransactionExecuted events among
+// events with the same logic as the live evm_worker. Events whose payload or
+// logs cannot be decoded are logged and skipped.
+func ParseEVMLogs(events []models.Event) []models.EVMLog {
+	var out []models.EVMLog
+	for _, evt := range events {
+		if !isEVMTransactionExecutedEvent(evt.Type) {
+			continue
+		}
+		var payload map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(evt.Payload))
+		dec.UseNumber()
+		if err := dec.Decode(&payload); err != nil {
+			log.Printf("[evm_logs] skip: JSON decode error at block %d tx %s event %d: %v",
+				evt.BlockHeight, evt.TransactionID, evt.EventIndex, err)
+			continue
+		}
+		h := extractEVMHashFromPayload(payload)
+		if h == "" {
+			continue
+		}
+		logs, err := evmLogsFromEvent(evt, h, payload)
+		if err != nil {
+			log.Printf("[evm_logs] skip: block %d tx %s event %d: %v",
+				evt.BlockHeight, evt.TransactionID, evt.EventIndex, err)
+			continue
+		}
+		out = append(out, logs...)
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package ingester
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestParseEVMLogs(t *testing.T) {
+	t.Parallel()
+
+	token := common.HexToAddress("0x7f27352D5F83Db87a5A3E00f4B07Cc2138D8ee52")
+	transfer := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	from := common.HexToHash("0x00000000000000000000000000000000000000000000000295a2b6ab3e3c7a1f")
+	to := common.HexToHash("0x000000000000000000000000a9d0b4dfcb1f1a9e2c6fde4e3f3a1b2c3d4e5f60")
+	amount := common.FromHex("0x0000000000000000000000000000000000000000000000000de0b6b3a7640000")
+	raw, err := rlp.EncodeToBytes([]*types.Log{
+		{Address: token, Topics: []common.Hash{transfer, from, to}, Data: amount},
+		{Address: token, Topics: []common.Hash{common.HexToHash("0x01")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// raw.events stores [UInt8] fields as arrays of decimal strings.
+	logsField := make([]string, len(raw))
+	for i, b := range raw {
+		logsField[i] = strconv.Itoa(int(b))
+	}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"hash": "0x9f2c1a7e6b5d4c3b2a1908f7e6d5c4b3a29180f7e6d5c4b3a2918f7e6d5c4b3a",
+		"logs": logsField,
+	})
+
+	ts := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []models.Event{
+		{Type: "A.1654653399040a61.FlowToken.TokensWithdrawn", Payload: []byte(`{"amount":"1.0"}`)},
+		{
+			Type:          "A.e467b9dd11fa00df.EVM.TransactionExecuted",
+			BlockHeight:   101,
+			TransactionID: "ab01",
+			EventIndex:    3,
+			Payload:       payload,
+			Timestamp:     ts,
+		},
+		// A transaction without logs yields no rows.
+		{Type: "A.e467b9dd11fa00df.EVM.TransactionExecuted", BlockHeight: 101, TransactionID: "ab02", Payload: []byte(`{"hash":"0x01","logs":[]}`)},
+		// Malformed RLP is skipped rather than failing the batch.
+		{Type: "A.e467b9dd11fa00df.EVM.TransactionExecuted", BlockHeight: 101, TransactionID: "ab03", Payload: []byte(`{"hash":"0x02","logs":["255"]}`)},
+	}
+
+	got := ParseEVMLogs(events)
+	base := models.EVMLog{
+		EVMHash:       "9f2c1a7e6b5d4c3b2a1908f7e6d5c4b3a29180f7e6d5c4b3a2918f7e6d5c4b3a",
+		BlockHeight:   101,
+		TransactionID: "ab01",
+		Address:       "7f27352d5f83db87a5a3e00f4b07cc2138d8ee52",
+		Timestamp:     ts,
+	}
+	first, second := base, base
+	first.Topics = []string{
+		"ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		"00000000000000000000000000000000000000000000000295a2b6ab3e3c7a1f",
+		"000000000000000000000000a9d0b4dfcb1f1a9e2c6fde4e3f3a1b2c3d4e5f60",
+	}
+	first.Data = "0000000000000000000000000000000000000000000000000de0b6b3a7640000"
+	second.LogIndex = 1
+	second.Topics = []string{"0000000000000000000000000000000000000000000000000000000000000001"}
+
+	if want := []models.EVMLog{first, second}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseEVMLogs\n got %+v\nwant %+v", got, want)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"math/big"
+	"strings"
 
 	"flowscan-clone/internal/models"
 	"flowscan-clone/internal/repository"
@@ -16,7 +17,9 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
-// EVMWorker parses EVM events from raw.events and materializes app.evm_* tables.
+// EVMWorker parses EVM events from raw.events and materializes app.evm_* tables
+// (including the decoded app.evm_logs) and the app.coa_accounts Flow ↔ COA
+// mapping.
 type EVMWorker struct {
 	repo *repository.Repository
 }
@@ -29,6 +32,10 @@ func (w *EVMWorker) Name() string {
 	return "evm_worker"
 }
 
+func (w *EVMWorker) SchemaVersion() int {
+	return 1
+}
+
 func (w *EVMWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	// Only fetch EVM events (with payload) — ~4% of total events.
 	events, err := w.repo.GetEVMEventsInRange(ctx, fromHeight, toHeight)
@@ -36,7 +43,12 @@ func (w *EVMWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint6
 		return fmt.Errorf("fetch evm events: %w", err)
 	}
 
+	if err := w.recordCOAAccounts(ctx, fromHeight, toHeight); err != nil {
+		return err
+	}
+
 	hashes := make([]models.EVMTxHash, 0)
+	var logs []models.EVMLog
 	for _, evt := range events {
 
 		var payload map[string]interface{}
@@ -56,93 +68,22 @@ func (w *EVMWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint6
 			_ = w.repo.LogIndexingError(ctx, w.Name(), evt.BlockHeight, evt.TransactionID, "EVM_HASH_MISSING", "no EVM hash in payload", nil)
 			continue
 		}
-		fromAddr, toAddr, dataHex := "", "", ""
-		var (
-			nonce     uint64
-			gasLimit  uint64
-			gasPrice  string
-			gasFeeCap string
-			gasTipCap string
-			value     string
-			txType    int
-			chainID   string
-		)
-		if txPayload := extractEVMPayloadBytes(payload); len(txPayload) > 0 {
-			if decoded, ok := decodeEVMTransactionPayload(txPayload); ok {
-				fromAddr = decoded.From
-				toAddr = decoded.To
-				dataHex = decoded.Data
-				nonce = decoded.Nonce
-				gasLimit = decoded.GasLimit
-				gasPrice = decoded.GasPrice
-				gasFeeCap = decoded.GasFeeCap
-				gasTipCap = decoded.GasTipCap
-				value = decoded.Value
-				txType = decoded.TxType
-				chainID = decoded.ChainID
-			}
-		}
-		if fromAddr == "" {
-			fromAddr = extractEVMHexField(payload, "from", "fromAddress", "sender")
-		}
-		if toAddr == "" {
-			toAddr = extractEVMHexField(payload, "to", "toAddress", "recipient")
-		}
-		if dataHex == "" {
-			dataHex = extractEVMHexField(payload, "data", "input")
-		}
-		if nonce == 0 {
-			nonce = extractEVMUint64(payload, "nonce")
-		}
-		if gasLimit == 0 {
-			gasLimit = extractEVMUint64(payload, "gasLimit", "gas", "gas_limit")
-		}
-		if gasPrice == "" {
-			gasPrice = extractEVMBigIntString(payload, "gasPrice", "gas_price")
-		}
-		if gasFeeCap == "" {
-			gasFeeCap = extractEVMBigIntString(payload, "maxFeePerGas", "max_fee_per_gas", "gasFeeCap", "gas_fee_cap")
-		}
-		if gasTipCap == "" {
-			gasTipCap = extractEVMBigIntString(payload, "maxPriorityFeePerGas", "max_priority_fee_per_gas", "gasTipCap", "gas_tip_cap")
-		}
-		if value == "" {
-			value = extractEVMBigIntString(payload, "value")
-		}
-		if txType == 0 {
-			txType = extractEVMInt(payload, "type", "txType", "tx_type")
-		}
-		if chainID == "" {
-			chainID = extractEVMBigIntString(payload, "chainId", "chain_id")
+		row := parseEVMTransactionExecuted(payload)
+		row.BlockHeight = evt.BlockHeight
+		row.TransactionID = evt.TransactionID
+		row.EVMHash = h
+		row.EventIndex = evt.EventIndex
+		row.TransactionIndex = evt.TransactionIndex
+		row.Timestamp = evt.Timestamp
+		hashes = append(hashes, row)
+
+		txLogs, err := evmLogsFromEvent(evt, h, payload)
+		if err != nil {
+			log.Printf("[evm_worker] skip logs at block %d tx %s event %d: %v",
+				evt.BlockHeight, evt.TransactionID, evt.EventIndex, err)
+			_ = w.repo.LogIndexingError(ctx, w.Name(), evt.BlockHeight, evt.TransactionID, "EVM_LOGS_DECODE", err.Error(), nil)
 		}
-		logsJSON := extractEVMLogsJSON(payload)
-		gasUsed := extractEVMUint64(payload, "gasUsed", "gas_used", "gasConsumed", "gas_consumed")
-		statusCode := extractEVMInt(payload, "statusCode", "status_code", "errorCode", "error_code")
-		status := extractEVMString(payload, "status", "executionStatus", "result")
-
-		hashes = append(hashes, models.EVMTxHash{
-			BlockHeight:      evt.BlockHeight,
-			TransactionID:    evt.TransactionID,
-			EVMHash:          h,
-			EventIndex:       evt.EventIndex,
-			TransactionIndex: evt.TransactionIndex,
-			FromAddress:      fromAddr,
-			ToAddress:        toAddr,
-			Nonce:            nonce,
-			GasLimit:         gasLimit,
-			GasUsed:          gasUsed,
-			GasPrice:         gasPrice,
-			GasFeeCap:        gasFeeCap,
-			GasTipCap:        gasTipCap,
-			Value:            value,
-			TxType:           txType,
-			ChainID:          chainID,
-			Data:             dataHex,
-			Logs:             logsJSON,
-			StatusCode:       statusCode,
-			Status:           status,
-			Timestamp:        evt.Timestamp,
-		})
+		logs = append(logs, txLogs...)
 	}
 
 	if len(hashes) == 0 {
@@ -167,10 +108,180 @@ func (w *EVMWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint6
 	if err := w.repo.UpsertEVMTxHashes(ctx, hashes); err != nil {
 		return fmt.Errorf("upsert evm tx hashes: %w", err)
 	}
+	if err := w.repo.UpsertEVMLogs(ctx, logs); err != nil {
+		return fmt.Errorf("upsert evm logs: %w", err)
+	}
+
+	return nil
+}
+
+// parseEVMTransactionExecuted extracts the EVM transaction fields from a
+// decoded EVM.TransactionExecuted payload. Fields from the embedded RLP
+// transaction win; top-level payload keys fill whatever it lacks. The caller
+// sets the Flow block/transaction identifiers.
+func parseEVMTransactionExecuted(payload map[string]interface{}) models.EVMTxHash {
+	fromAddr, toAddr, dataHex := "", "", ""
+	var (
+		nonce     uint64
+		gasLimit  uint64
+		gasPrice  string
+		gasFeeCap string
+		gasTipCap string
+		value     string
+		txType    int
+		chainID   string
+	)
+	if txPayload := extractEVMPayloadBytes(payload); len(txPayload) > 0 {
+		if decoded, ok := decodeEVMTransactionPayload(txPayload); ok {
+			fromAddr = decoded.From
+			toAddr = decoded.To
+			dataHex = decoded.Data
+			nonce = decoded.Nonce
+			gasLimit = decoded.GasLimit
+			gasPrice = decoded.GasPrice
+			gasFeeCap = decoded.GasFeeCap
+			gasTipCap = decoded.GasTipCap
+			value = decoded.Value
+			txType = decoded.TxType
+			chainID = decoded.ChainID
+		}
+	}
+	if fromAddr == "" {
+		fromAddr = extractEVMHexField(payload, "from", "fromAddress", "sender")
+	}
+	if toAddr == "" {
+		toAddr = extractEVMHexField(payload, "to", "toAddress", "recipient")
+	}
+	if dataHex == "" {
+		dataHex = extractEVMHexField(payload, "data", "input")
+	}
+	if nonce == 0 {
+		nonce = extractEVMUint64(payload, "nonce")
+	}
+	if gasLimit == 0 {
+		gasLimit = extractEVMUint64(payload, "gasLimit", "gas", "gas_limit")
+	}
+	if gasPrice == "" {
+		gasPrice = extractEVMBigIntString(payload, "gasPrice", "gas_price")
+	}
+	if gasFeeCap == "" {
+		gasFeeCap = extractEVMBigIntString(payload, "maxFeePerGas", "max_fee_per_gas", "gasFeeCap", "gas_fee_cap")
+	}
+	if gasTipCap == "" {
+		gasTipCap = extractEVMBigIntString(payload, "maxPriorityFeePerGas", "max_priority_fee_per_gas", "gasTipCap", "gas_tip_cap")
+	}
+	if value == "" {
+		value = extractEVMBigIntString(payload, "value")
+	}
+	if txType == 0 {
+		txType = extractEVMInt(payload, "type", "txType", "tx_type")
+	}
+	if chainID == "" {
+		chainID = extractEVMBigIntString(payload, "chainId", "chain_id")
+	}
+	logsJSON := extractEVMLogsJSON(payload)
+	gasUsed := extractEVMUint64(payload, "gasUsed", "gas_used", "gasConsumed", "gas_consumed")
+	statusCode := extractEVMInt(payload, "statusCode", "status_code", "errorCode", "error_code")
+	status := extractEVMString(payload, "status", "executionStatus", "result")
+	if status == "" {
+		status = evmExecutionStatus(statusCode, extractEVMString(payload, "errorMessage", "error_message"))
+	}
+
+	return models.EVMTxHash{
+		FromAddress: fromAddr,
+		ToAddress:   toAddr,
+		Nonce:       nonce,
+		GasLimit:    gasLimit,
+		GasUsed:     gasUsed,
+		GasPrice:    gasPrice,
+		GasFeeCap:   gasFeeCap,
+		GasTipCap:   gasTipCap,
+		Value:       value,
+		TxType:      txType,
+		ChainID:     chainID,
+		Data:        dataHex,
+		Logs:        logsJSON,
+		StatusCode:  statusCode,
+		Status:      status,
+	}
+}
+
+// evmExecutionStatus maps the event's errorCode/errorMessage to a status.
+// Flow EVM emits TransactionExecuted for reverted transactions too, with a
+// non-zero errorCode.
+func evmExecutionStatus(errorCode int, errorMessage string) string {
+	switch {
+	case errorCode == 0:
+		return "SUCCESS"
+	case strings.Contains(strings.ToLower(errorMessage), "revert"):
+		return "REVERTED"
+	default:
+		return "FAILED"
+	}
+}
 
+// recordCOAAccounts maps COAs created in [fromHeight, toHeight) to their owning
+// Flow account.
+func (w *EVMWorker) recordCOAAccounts(ctx context.Context, fromHeight, toHeight uint64) error {
+	events, err := w.repo.GetCOACreatedEventsInRange(ctx, fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("fetch coa created events: %w", err)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([]models.COAAccount, 0, len(events))
+	for _, evt := range events {
+		row, ok := coaAccountFromEvent(evt)
+		if !ok {
+			_ = w.repo.LogIndexingError(ctx, w.Name(), evt.BlockHeight, evt.TransactionID, "COA_EVENT_PARSE", "cannot resolve coa address or owner", nil)
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if err := w.repo.UpsertCOAAccounts(ctx, rows); err != nil {
+		return fmt.Errorf("upsert coa accounts: %w", err)
+	}
 	return nil
 }
 
+// coaAccountFromEvent builds a COA mapping from an EVM.CadenceOwnedAccountCreated
+// event. The event only carries the EVM address, so the owner is taken from the
+// transaction's first authorizer (falling back to payer, then proposer), which
+// is the account the COA resource is created for in the standard setup flow.
+func coaAccountFromEvent(evt repository.COACreatedEvent) (models.COAAccount, bool) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(evt.Payload, &payload); err != nil {
+		return models.COAAccount{}, false
+	}
+	raw, _ := payload["address"].(string)
+	coaAddr := normalizeEVMAddress(raw)
+	if coaAddr == "" {
+		return models.COAAccount{}, false
+	}
+
+	owner := ""
+	if len(evt.Authorizers) > 0 {
+		owner = evt.Authorizers[0]
+	} else if evt.PayerAddress != "" {
+		owner = evt.PayerAddress
+	} else {
+		owner = evt.ProposerAddress
+	}
+	owner = normalizeFlowAddress(owner)
+	if len(owner) != 16 {
+		return models.COAAccount{}, false
+	}
+
+	return models.COAAccount{
+		COAAddress:    coaAddr,
+		FlowAddress:   owner,
+		TransactionID: evt.TransactionID,
+		BlockHeight:   evt.BlockHeight,
+	}, true
+}
+
 type decodedEVMTx struct {
 	From      string
 	To        string
@@ -0,0 +1,148 @@
+package ingester
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestCOAAccountFromEvent(t *testing.T) {
+	t.Parallel()
+
+	// Flattened payload of EVM.CadenceOwnedAccountCreated(address: String, uuid: UInt64).
+	evt := repository.COACreatedEvent{
+		Event: models.Event{
+			Type:          "A.e467b9dd11fa00df.EVM.CadenceOwnedAccountCreated",
+			TransactionID: "6a1f1d2c3b4a5968778695a4b3c2d1e0f1e2d3c4b5a69788796a5b4c3d2e1f00",
+			BlockHeight:   85000123,
+			Payload:       json.RawMessage(`{"address":"000000000000000000000002a1b2c3d4e5f60718","uuid":"21990232555527"}`),
+		},
+		PayerAddress: "0x1654653399040a61",
+		Authorizers:  []string{"0xE8A2C3D4F5061728"},
+	}
+
+	got, ok := coaAccountFromEvent(evt)
+	if !ok {
+		t.Fatal("expected COA mapping")
+	}
+	want := models.COAAccount{
+		COAAddress:    "000000000000000000000002a1b2c3d4e5f60718",
+		FlowAddress:   "e8a2c3d4f5061728",
+		TransactionID: evt.TransactionID,
+		BlockHeight:   85000123,
+	}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	// Without authorizers the payer owns the COA.
+	evt.Authorizers = nil
+	if got, _ := coaAccountFromEvent(evt); got.FlowAddress != "1654653399040a61" {
+		t.Fatalf("payer fallback = %s", got.FlowAddress)
+	}
+
+	evt.Payload = json.RawMessage(`{"uuid":"1"}`)
+	if _, ok := coaAccountFromEvent(evt); ok {
+		t.Fatal("event without address should be rejected")
+	}
+}
+
+// sampleTransactionExecuted builds an EVM.TransactionExecuted payload (as
+// stored in raw.events) for a signed EIP-1559 contract call carrying value.
+func sampleTransactionExecuted(t *testing.T, errorCode int, errorMessage string) (map[string]interface{}, common.Address) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := big.NewInt(747)
+	to := common.HexToAddress("0x2aaBea2058b5aC2D339b163C6Ab6f2b6d53aabED")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     42,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(100_000_000),
+		Gas:       210_000,
+		To:        &to,
+		Value:     big.NewInt(1_500_000_000_000_000_000),
+		Data:      common.FromHex("0xd0e30db0"), // deposit()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"hash":         tx.Hash().Hex(),
+		"index":        0,
+		"type":         2,
+		"payload":      hex.EncodeToString(raw),
+		"errorCode":    errorCode,
+		"errorMessage": errorMessage,
+		"gasConsumed":  45_123,
+	})
+	var payload map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+	return payload, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestParseEVMTransactionExecutedContractCallWithValue(t *testing.T) {
+	t.Parallel()
+
+	payload, from := sampleTransactionExecuted(t, 0, "")
+	row := parseEVMTransactionExecuted(payload)
+
+	want := models.EVMTxHash{
+		FromAddress: hex.EncodeToString(from.Bytes()),
+		ToAddress:   "2aabea2058b5ac2d339b163c6ab6f2b6d53aabed",
+		Nonce:       42,
+		GasLimit:    210_000,
+		GasUsed:     45_123,
+		GasPrice:    "100000000",
+		GasFeeCap:   "100000000",
+		GasTipCap:   "1",
+		Value:       "1500000000000000000",
+		TxType:      2,
+		ChainID:     "747",
+		Data:        "d0e30db0",
+		Status:      "SUCCESS",
+	}
+	if row != want {
+		t.Fatalf("parsed row\n got %+v\nwant %+v", row, want)
+	}
+}
+
+func TestParseEVMTransactionExecutedReverted(t *testing.T) {
+	t.Parallel()
+
+	payload, _ := sampleTransactionExecuted(t, 400, "execution reverted: insufficient balance")
+	row := parseEVMTransactionExecuted(payload)
+	if row.StatusCode != 400 || row.Status != "REVERTED" {
+		t.Fatalf("status = %d/%q, want 400/REVERTED", row.StatusCode, row.Status)
+	}
+	// A reverted call still consumed gas and carried its value.
+	if row.GasUsed != 45_123 || row.Value != "1500000000000000000" || row.Nonce != 42 {
+		t.Fatalf("row = %+v", row)
+	}
+
+	if got := evmExecutionStatus(201, "nonce too low"); got != "FAILED" {
+		t.Fatalf("evmExecutionStatus(201) = %q, want FAILED", got)
+	}
+}
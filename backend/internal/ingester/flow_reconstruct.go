@@ -0,0 +1,118 @@
+package ingester
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"flowscan-clone/internal/config"
+	"flowscan-clone/internal/models"
+)
+
+// Synthetic FLOW transfers.
+//
+// Some FLOW movements are not visible as a paired FlowToken.TokensWithdrawn /
+// TokensDeposited, so pairing legs alone gives wrong balance deltas. After the
+// legs of a transaction are paired, these event combinations are rewritten or
+// turned into FlowToken transfers:
+//
+//   - FlowFees.FeesDeducted(amount) and a FlowToken transfer of the same amount
+//     with a payer but no recipient (the fee vault lives in the FlowFees
+//     contract, so its deposit has to: nil): the recipient becomes the FlowFees
+//     vault. The usual fee filter then drops it unless INCLUDE_FEE_TRANSFERS=true.
+//   - EVM.FLOWTokensWithdrawn(coa A, amount) and EVM.FLOWTokensDeposited(coa B,
+//     amount) that no FlowToken leg consumed: the vault went straight from one
+//     COA to another, so a FlowToken transfer A -> B is produced.
+//
+// Nothing else produces a transfer. A lone unmatched EVM bridge event has no
+// counterparty and is dropped, and bridge events are never turned into a
+// transfer with contract_name 'EVM' (the pattern reset-token-worker deletes):
+// synthetic transfers always use the FlowToken contract.
+
+// evmBridgeInfo is one EVM.FLOWTokensWithdrawn/Deposited event.
+type evmBridgeInfo struct {
+	coaAddress  string // EVM COA address (hex, 40 chars)
+	amount      string
+	blockHeight uint64
+	eventIndex  int
+	timestamp   time.Time
+}
+
+// isFlowFeesDeductedEvent returns true for FlowFees.FeesDeducted.
+func isFlowFeesDeductedEvent(eventType string) bool {
+	return strings.HasSuffix(eventType, ".FlowFees.FeesDeducted")
+}
+
+// feeVaultAddress is the FlowFees account, overridable with FLOW_FEES_ADDRESS.
+func feeVaultAddress() string {
+	feeVault := strings.ToLower(strings.TrimSpace(os.Getenv("FLOW_FEES_ADDRESS")))
+	if feeVault == "" {
+		feeVault = config.Addr().FlowFees
+	}
+	return normalizeFlowAddress(feeVault)
+}
+
+// attributeFeeDeposits points FlowToken transfers that match a FeesDeducted
+// amount and have no recipient at the fee vault. Each fee is used once.
+func attributeFeeDeposits(transfers []models.TokenTransfer, fees []string) {
+	if len(fees) == 0 {
+		return
+	}
+	vault := feeVaultAddress()
+	remaining := append([]string(nil), fees...)
+	for i := range transfers {
+		t := &transfers[i]
+		if t.IsNFT || t.ContractName != "FlowToken" || t.FromAddress == "" || t.ToAddress != "" {
+			continue
+		}
+		for j, fee := range remaining {
+			if fee == t.Amount {
+				t.ToAddress = vault
+				remaining = append(remaining[:j], remaining[j+1:]...)
+				break
+			}
+		}
+		if len(remaining) == 0 {
+			return
+		}
+	}
+}
+
+// pairEVMBridgeTransfers turns unconsumed EVM FLOW withdrawals and deposits of
+// the same amount into COA-to-COA FlowToken transfers, in event order.
+func pairEVMBridgeTransfers(txID string, withdrawals, deposits []evmBridgeInfo) []models.TokenTransfer {
+	if len(withdrawals) == 0 || len(deposits) == 0 {
+		return nil
+	}
+	flowToken := normalizeFlowAddress(config.Addr().FlowToken)
+	used := make([]bool, len(deposits))
+	var out []models.TokenTransfer
+	for _, wd := range withdrawals {
+		best := -1
+		for j, dp := range deposits {
+			if used[j] || dp.amount != wd.amount || dp.coaAddress == wd.coaAddress {
+				continue
+			}
+			if best < 0 || dp.eventIndex < deposits[best].eventIndex {
+				best = j
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		used[best] = true
+		dp := deposits[best]
+		out = append(out, models.TokenTransfer{
+			TransactionID:        txID,
+			BlockHeight:          dp.blockHeight,
+			EventIndex:           dp.eventIndex,
+			TokenContractAddress: flowToken,
+			ContractName:         "FlowToken",
+			FromAddress:          wd.coaAddress,
+			ToAddress:            dp.coaAddress,
+			Amount:               wd.amount,
+			Timestamp:            dp.timestamp,
+		})
+	}
+	return out
+}
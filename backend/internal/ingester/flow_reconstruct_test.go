@@ -0,0 +1,109 @@
+package ingester
+
+import (
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+// feeAndTransferEvents is a transaction that pays a fee and sends FLOW. The fee
+// deposit has no recipient and FlowFees emits its own TokensDeposited, as on
+// mainnet.
+func feeAndTransferEvents(t *testing.T) []models.Event {
+	t.Helper()
+	ts := time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)
+	txID := "c1f0e4a55c30aa3b0c2d8e7f6a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b"
+	evt := func(idx int, typ string, fields map[string]string) models.Event {
+		return models.Event{
+			TransactionID: txID,
+			BlockHeight:   120000,
+			EventIndex:    idx,
+			Type:          typ,
+			Payload:       makePayload(t, fields),
+			Timestamp:     ts,
+		}
+	}
+	return []models.Event{
+		evt(0, "A.1654653399040a61.FlowToken.TokensWithdrawn", map[string]string{
+			"from": "0x6a1142285bbb7526", "amount": "12.50000000", "withdrawnUUID": "901",
+		}),
+		evt(1, "A.1654653399040a61.FlowToken.TokensDeposited", map[string]string{
+			"to": "0x84221fe0294044d7", "amount": "12.50000000", "depositedUUID": "901",
+		}),
+		evt(2, "A.1654653399040a61.FlowToken.TokensWithdrawn", map[string]string{
+			"from": "0x6a1142285bbb7526", "amount": "0.00001000", "withdrawnUUID": "902",
+		}),
+		evt(3, "A.1654653399040a61.FlowToken.TokensDeposited", map[string]string{
+			"amount": "0.00001000", "depositedUUID": "902",
+		}),
+		evt(4, "A.f919ee77447b7497.FlowFees.TokensDeposited", map[string]string{
+			"amount": "0.00001000",
+		}),
+		evt(5, "A.f919ee77447b7497.FlowFees.FeesDeducted", map[string]string{
+			"amount": "0.00001000", "inclusionEffort": "1.00000000", "executionEffort": "0.00000017",
+		}),
+	}
+}
+
+func TestFeeDeductionWithGenuineFlowTransfer(t *testing.T) {
+	result := processTokenEvents(feeAndTransferEvents(t))
+
+	// Fee transfers are filtered by default: only the genuine transfer remains,
+	// and nothing is attributed to a "FlowFees" token.
+	if len(result.ftTransfers) != 1 {
+		t.Fatalf("expected 1 FT transfer, got %d: %+v", len(result.ftTransfers), result.ftTransfers)
+	}
+	tr := result.ftTransfers[0]
+	if tr.ContractName != "FlowToken" || tr.FromAddress != "6a1142285bbb7526" || tr.ToAddress != "84221fe0294044d7" || tr.Amount != "12.50000000" {
+		t.Fatalf("unexpected transfer: %+v", tr)
+	}
+	if _, ok := result.ftTokens["f919ee77447b7497:FlowFees"]; ok {
+		t.Fatalf("FlowFees should not be discovered as a token: %v", keysOf(result.ftTokens))
+	}
+}
+
+func TestFeeDeductionAttributedToFeeVault(t *testing.T) {
+	t.Setenv("INCLUDE_FEE_TRANSFERS", "true")
+	result := processTokenEvents(feeAndTransferEvents(t))
+
+	if len(result.ftTransfers) != 2 {
+		t.Fatalf("expected 2 FT transfers, got %d: %+v", len(result.ftTransfers), result.ftTransfers)
+	}
+	var fee *models.TokenTransfer
+	for i := range result.ftTransfers {
+		if result.ftTransfers[i].Amount == "0.00001000" {
+			fee = &result.ftTransfers[i]
+		}
+	}
+	if fee == nil {
+		t.Fatalf("fee transfer missing: %+v", result.ftTransfers)
+	}
+	// Payer loses the fee and the fee vault receives it, instead of a transfer to nobody.
+	if fee.FromAddress != "6a1142285bbb7526" || fee.ToAddress != feeVaultAddress() || fee.ContractName != "FlowToken" {
+		t.Fatalf("fee transfer = %+v, want payer -> %s", fee, feeVaultAddress())
+	}
+}
+
+func TestEVMBridgeEventsPairCOAToCOA(t *testing.T) {
+	ts := time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)
+	events := []models.Event{
+		{TransactionID: "tx1", BlockHeight: 7, EventIndex: 0, Timestamp: ts, Type: "A.e467b9dd11fa00df.EVM.FLOWTokensWithdrawn",
+			Payload: makePayload(t, map[string]string{"address": "000000000000000000000002a1b2c3d4e5f60718", "amount": "3.00000000"})},
+		{TransactionID: "tx1", BlockHeight: 7, EventIndex: 1, Timestamp: ts, Type: "A.e467b9dd11fa00df.EVM.FLOWTokensDeposited",
+			Payload: makePayload(t, map[string]string{"address": "000000000000000000000002ffeeddccbbaa9988", "amount": "3.00000000"})},
+		// A lone bridge event has no counterparty and produces nothing.
+		{TransactionID: "tx2", BlockHeight: 7, EventIndex: 0, Timestamp: ts, Type: "A.e467b9dd11fa00df.EVM.FLOWTokensDeposited",
+			Payload: makePayload(t, map[string]string{"address": "000000000000000000000002ffeeddccbbaa9988", "amount": "1.00000000"})},
+	}
+	result := processTokenEvents(events)
+
+	if len(result.ftTransfers) != 1 {
+		t.Fatalf("expected 1 FT transfer, got %d: %+v", len(result.ftTransfers), result.ftTransfers)
+	}
+	tr := result.ftTransfers[0]
+	if tr.ContractName != "FlowToken" || tr.TransactionID != "tx1" || tr.EventIndex != 1 ||
+		tr.FromAddress != "000000000000000000000002a1b2c3d4e5f60718" || tr.ToAddress != "000000000000000000000002ffeeddccbbaa9988" {
+		t.Fatalf("unexpected transfer: %+v", tr)
+	}
+}
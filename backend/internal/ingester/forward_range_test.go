@@ -0,0 +1,63 @@
+package ingester
+
+import "testing"
+
+func TestForwardTarget(t *testing.T) {
+	t.Parallel()
+
+	if got := ForwardTarget(1000, 0); got != 1000 {
+		t.Fatalf("no lag: %d", got)
+	}
+	if got := ForwardTarget(1000, 30); got != 970 {
+		t.Fatalf("lag 30: %d", got)
+	}
+	if got := ForwardTarget(10, 30); got != 0 {
+		t.Fatalf("lag past genesis: %d", got)
+	}
+}
+
+func TestForwardBatchRangeRespectsHeadLag(t *testing.T) {
+	t.Parallel()
+
+	const latestSealed, headLag = 1000, 10
+	target := ForwardTarget(latestSealed, headLag)
+
+	// Far behind: full batches.
+	start, end, ok := forwardBatchRange(500, 0, target, 50)
+	if !ok || start != 501 || end != 550 {
+		t.Fatalf("far behind = %d..%d ok=%v", start, end, ok)
+	}
+
+	// Close to the head: the batch stops at the target, not the sealed tip.
+	start, end, ok = forwardBatchRange(985, 0, target, 50)
+	if !ok || start != 986 || end > target {
+		t.Fatalf("near head = %d..%d ok=%v, target %d", start, end, ok, target)
+	}
+
+	// Walk forward until caught up; nothing past the target is ever fetched.
+	last := uint64(985)
+	for {
+		start, end, ok = forwardBatchRange(last, 0, target, 50)
+		if !ok {
+			break
+		}
+		if end > target || start != last+1 {
+			t.Fatalf("batch %d..%d after %d exceeds target %d", start, end, last, target)
+		}
+		last = end
+	}
+	if last != target {
+		t.Fatalf("stopped at %d, want target %d", last, target)
+	}
+
+	// Without a checkpoint, StartBlock wins; the fallback stays below target.
+	if start, _, ok := forwardBatchRange(0, 700, target, 50); !ok || start != 700 {
+		t.Fatalf("start block = %d ok=%v", start, ok)
+	}
+	if start, _, ok := forwardBatchRange(0, 0, target, 50); !ok || start != target-100 {
+		t.Fatalf("fallback start = %d ok=%v", start, ok)
+	}
+	if _, _, ok := forwardBatchRange(0, 995, target, 50); ok {
+		t.Fatal("start block above target should wait")
+	}
+}
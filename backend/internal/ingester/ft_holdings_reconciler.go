@@ -0,0 +1,91 @@
+package ingester
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"flowscan-clone/internal/repository"
+)
+
+// FTHoldingsReconcilerConfig controls the periodic app.ft_holdings check.
+type FTHoldingsReconcilerConfig struct {
+	Interval time.Duration // how often to re-check (default 24h)
+	Tokens   []string      // A.<address>.<name>; empty checks every token in app.ft_tokens
+	DryRun   bool          // report drift without correcting it
+}
+
+// FTHoldingsReconciler replays each token's transfers and corrects holders
+// whose app.ft_holdings balance has drifted from what ft_holdings_worker
+// should have produced, logging every correction.
+type FTHoldingsReconciler struct {
+	repo *repository.Repository
+	cfg  FTHoldingsReconcilerConfig
+}
+
+func NewFTHoldingsReconciler(repo *repository.Repository, cfg FTHoldingsReconcilerConfig) *FTHoldingsReconciler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 24 * time.Hour
+	}
+	return &FTHoldingsReconciler{repo: repo, cfg: cfg}
+}
+
+// Start runs RunOnce every Interval. The first run waits one Interval, so a
+// restart does not trigger a full replay.
+func (f *FTHoldingsReconciler) Start(ctx context.Context) {
+	log.Printf("[ft_holdings_reconciler] Starting (interval=%s, dry_run=%v, tokens=%d)", f.cfg.Interval, f.cfg.DryRun, len(f.cfg.Tokens))
+
+	ticker := time.NewTicker(f.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := f.RunOnce(ctx); err != nil {
+			log.Printf("[ft_holdings_reconciler] Error: %v", err)
+		}
+	}
+}
+
+// RunOnce reconciles every configured token. A failing token is logged and
+// skipped so one bad token does not block the rest.
+func (f *FTHoldingsReconciler) RunOnce(ctx context.Context) error {
+	tokens := f.cfg.Tokens
+	if len(tokens) == 0 {
+		var err error
+		if tokens, err = f.repo.ListFTTokenIdentifiers(ctx); err != nil {
+			return err
+		}
+	}
+
+	drifted := 0
+	for _, token := range tokens {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		res, err := f.repo.ReconcileFTHoldingsForToken(ctx, token, f.cfg.DryRun)
+		if err != nil {
+			log.Printf("[ft_holdings_reconciler] %s: %v", token, err)
+			continue
+		}
+		if len(res.Drift) == 0 {
+			continue
+		}
+		drifted++
+		verb := "corrected"
+		if res.DryRun {
+			verb = "found (dry-run)"
+		}
+		for _, d := range res.Drift {
+			log.Printf("[ft_holdings_reconciler] %s %s: stored=%s expected=%s delta=%s (%s)",
+				res.Token, d.Address, d.Stored, d.Expected, d.Delta, verb)
+		}
+		log.Printf("[ft_holdings_reconciler] %s up to height %d: %d of %d holders drifted, %d corrected, %d skipped",
+			res.Token, res.UpToHeight, len(res.Drift), res.Checked, res.Corrected, res.Skipped)
+	}
+	log.Printf("[ft_holdings_reconciler] Checked %d tokens, %d drifted", len(tokens), drifted)
+	return nil
+}
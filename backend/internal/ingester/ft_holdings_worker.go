@@ -21,6 +21,10 @@ func (w *FTHoldingsWorker) Name() string {
 	return "ft_holdings_worker"
 }
 
+func (w *FTHoldingsWorker) SchemaVersion() int {
+	return 1
+}
+
 func (w *FTHoldingsWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	events, err := w.repo.GetTokenTransfersByRange(ctx, fromHeight, toHeight, false)
 	if err != nil {
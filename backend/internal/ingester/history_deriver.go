@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -46,6 +47,12 @@ type HistoryDeriver struct {
 	disableDown bool
 	// Hard upper bound for UP scan (0 = use worker floor).
 	ceilingHeight uint64
+	// Per-processor UP weights (chunks per round multiplier, default 1).
+	weights map[string]int
+
+	progressMu sync.Mutex
+	progress   map[string]ProcessorProgress
+	ceiling    uint64
 }
 
 type HistoryDeriverConfig struct {
@@ -57,6 +64,9 @@ type HistoryDeriverConfig struct {
 	DisableUp          bool   // skip processUpward entirely
 	DisableDown        bool   // skip processDownward entirely
 	CeilingHeight      uint64 // hard upper bound for UP (0 = use worker floor)
+	// Weights gives processors more UP chunks per round, e.g. {"token_worker": 4}.
+	// Nil reads HISTORY_DERIVER_WEIGHTS ("token_worker=4,meta_worker=1").
+	Weights map[string]int
 }
 
 func NewHistoryDeriver(repo *repository.Repository, processors []Processor, cfg HistoryDeriverConfig) *HistoryDeriver {
@@ -69,6 +79,9 @@ func NewHistoryDeriver(repo *repository.Repository, processors []Processor, cfg
 	if cfg.ProcessorTimeoutMs == 0 {
 		cfg.ProcessorTimeoutMs = getEnvIntDefaultHD("HISTORY_DERIVER_PROCESSOR_TIMEOUT_MS", 120000)
 	}
+	if cfg.Weights == nil {
+		cfg.Weights = parseProcessorWeights(os.Getenv("HISTORY_DERIVER_WEIGHTS"))
+	}
 	prefix := cfg.CheckpointPrefix
 	if prefix == "" {
 		prefix = "history_deriver"
@@ -89,7 +102,26 @@ func NewHistoryDeriver(repo *repository.Repository, processors []Processor, cfg
 		disableUp:          cfg.DisableUp,
 		disableDown:        cfg.DisableDown,
 		ceilingHeight:      cfg.CeilingHeight,
+		weights:            cfg.Weights,
+	}
+}
+
+// parseProcessorWeights parses "name=weight,..." into a weight map. Malformed
+// entries and weights below 1 are skipped.
+func parseProcessorWeights(s string) map[string]int {
+	weights := make(map[string]int)
+	for _, part := range strings.Split(s, ",") {
+		name, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil || n < 1 {
+			continue
+		}
+		weights[strings.TrimSpace(name)] = n
 	}
+	return weights
 }
 
 func (h *HistoryDeriver) Start(ctx context.Context) {
@@ -98,7 +130,7 @@ func (h *HistoryDeriver) Start(ctx context.Context) {
 		return
 	}
 	log.Printf(
-		"%s Starting (processors=%d chunk=%d concurrency=%d timeout_ms=%d disableUp=%v disableDown=%v ceiling=%d)",
+		"%s Starting (processors=%d chunk=%d concurrency=%d timeout_ms=%d disableUp=%v disableDown=%v ceiling=%d weights=%v)",
 		h.logPrefix,
 		len(h.processors),
 		h.chunkSize,
@@ -107,6 +139,7 @@ func (h *HistoryDeriver) Start(ctx context.Context) {
 		h.disableUp,
 		h.disableDown,
 		h.ceilingHeight,
+		h.weights,
 	)
 	go h.run(ctx)
 }
@@ -172,8 +205,13 @@ func (h *HistoryDeriver) processNext(ctx context.Context) (bool, error) {
 }
 
 // processUpward scans from the upCursor toward the async worker ceiling.
-// When concurrency > 1, it dispatches up to N chunks in parallel and
-// advances the checkpoint to the highest contiguous success from scanFrom.
+//
+// Each processor keeps its own UP checkpoint ("<prefix>:<processor>") and
+// advances independently, so a slow or failing processor no longer gates the
+// others. Per round a processor handles up to weight*concurrency chunks
+// (HISTORY_DERIVER_WEIGHTS, default weight 1). The shared upCheckpoint tracks
+// the slowest processor, which keeps its meaning for the DOWN scan, event
+// retention and status pages.
 func (h *HistoryDeriver) processUpward(ctx context.Context) (bool, error) {
 	upCursor, err := h.repo.GetLastIndexedHeight(ctx, h.upCheckpoint)
 	if err != nil {
@@ -211,88 +249,265 @@ func (h *HistoryDeriver) processUpward(ctx context.Context) (bool, error) {
 		return false, nil
 	}
 
-	// Build up to N chunk ranges.
-	type chunkRange struct {
-		from, to uint64
-		index    int
+	lanes := make([]*deriverLane, 0, len(h.processors))
+	for _, p := range h.processors {
+		cursor, err := h.repo.GetLastIndexedHeight(ctx, h.laneCheckpoint(p.Name()))
+		if err != nil {
+			return false, err
+		}
+		if cursor < scanFrom {
+			cursor = scanFrom
+		}
+		lanes = append(lanes, h.newLane(p, cursor))
+	}
+
+	// Lanes usually scan the same ranges; only ask the DB once per range.
+	var blocksMu sync.Mutex
+	blocksSeen := make(map[[2]uint64]bool)
+	hasBlocks := func(ctx context.Context, from, to uint64) (bool, error) {
+		blocksMu.Lock()
+		has, ok := blocksSeen[[2]uint64{from, to}]
+		blocksMu.Unlock()
+		if ok {
+			return has, nil
+		}
+		has, err := h.repo.HasBlocksInRange(ctx, from, to)
+		if err != nil {
+			return false, err
+		}
+		blocksMu.Lock()
+		blocksSeen[[2]uint64{from, to}] = has
+		blocksMu.Unlock()
+		return has, nil
 	}
-	var chunks []chunkRange
-	cursor := scanFrom
-	for i := 0; i < h.concurrency && cursor < ceiling; i++ {
-		chunkTo := cursor + h.chunkSize
-		if chunkTo > ceiling {
-			chunkTo = ceiling
+
+	h.advanceLanes(ctx, lanes, ceiling, hasBlocks)
+
+	advanced := false
+	advanceTo := uint64(0)
+	for _, l := range lanes {
+		if l.cursor > l.start {
+			advanced = true
+			if err := h.repo.UpdateCheckpoint(ctx, h.laneCheckpoint(l.proc.Name()), l.cursor); err != nil {
+				log.Printf("%s Failed to update %s checkpoint: %v", h.logPrefix, l.proc.Name(), err)
+			}
+		}
+		if advanceTo == 0 || l.cursor < advanceTo {
+			advanceTo = l.cursor
 		}
-		chunks = append(chunks, chunkRange{from: cursor, to: chunkTo, index: i})
-		cursor = chunkTo
 	}
+	h.recordProgress(lanes, ceiling)
 
-	if len(chunks) == 0 {
+	if !advanced {
+		if scanFrom%100000 < h.chunkSize {
+			log.Printf("%s UP: no progress from %d (ceiling=%d), waiting for backward ingester or failing processors", h.logPrefix, scanFrom, ceiling)
+		}
 		return false, nil
 	}
 
-	// Guard: verify raw blocks exist in the first chunk. If the backward
-	// ingester hasn't filled this range yet, don't attempt any chunks.
-	hasBlocks, err := h.repo.HasBlocksInRange(ctx, chunks[0].from, chunks[0].to)
-	if err != nil {
-		return false, err
+	if advanceTo > scanFrom {
+		if err := h.repo.UpdateCheckpoint(ctx, h.upCheckpoint, advanceTo); err != nil {
+			log.Printf("%s Failed to update up checkpoint: %v", h.logPrefix, err)
+		}
+		if advanceTo%100000 < h.chunkSize*uint64(h.concurrency) {
+			log.Printf("%s Progress UP: processed to %d (ceiling=%d)", h.logPrefix, advanceTo, ceiling)
+		}
 	}
-	if !hasBlocks {
-		if scanFrom%100000 < h.chunkSize {
-			log.Printf("%s UP: no raw blocks in [%d,%d), waiting for backward ingester", h.logPrefix, chunks[0].from, chunks[0].to)
+	return true, nil
+}
+
+// deriverLane is one processor's UP cursor within a processUpward round.
+type deriverLane struct {
+	proc      Processor
+	weight    int
+	dependent bool // runs after token_worker and never passes its cursor
+	start     uint64
+	cursor    uint64
+	err       error
+}
+
+func (h *HistoryDeriver) newLane(p Processor, cursor uint64) *deriverLane {
+	return &deriverLane{
+		proc:      p,
+		weight:    h.weight(p.Name()),
+		dependent: dependsOnToken[p.Name()],
+		start:     cursor,
+		cursor:    cursor,
+	}
+}
+
+func (h *HistoryDeriver) laneCheckpoint(processor string) string {
+	return h.upCheckpoint + ":" + processor
+}
+
+func (h *HistoryDeriver) weight(processor string) int {
+	if w := h.weights[processor]; w > 0 {
+		return w
+	}
+	return 1
+}
+
+// advanceLanes moves every lane toward ceiling. Independent processors run
+// first; processors depending on token_worker run afterwards and are capped
+// at token_worker's new cursor.
+func (h *HistoryDeriver) advanceLanes(ctx context.Context, lanes []*deriverLane, ceiling uint64, hasBlocks func(ctx context.Context, from, to uint64) (bool, error)) {
+	var phase1, phase2 []*deriverLane
+	var token *deriverLane
+	for _, l := range lanes {
+		if l.dependent {
+			phase2 = append(phase2, l)
+			continue
+		}
+		phase1 = append(phase1, l)
+		if l.proc.Name() == "token_worker" {
+			token = l
 		}
-		return false, nil
 	}
 
-	// Process chunks concurrently.
-	results := make([]error, len(chunks))
-	var wg sync.WaitGroup
-	for _, c := range chunks {
-		wg.Add(1)
-		go func(cr chunkRange) {
-			defer wg.Done()
-			if ctx.Err() != nil {
-				results[cr.index] = ctx.Err()
-				return
+	runLanes := func(group []*deriverLane, limit uint64) {
+		var wg sync.WaitGroup
+		for _, l := range group {
+			wg.Add(1)
+			go func(l *deriverLane) {
+				defer wg.Done()
+				h.advanceLane(ctx, l, limit, hasBlocks)
+			}(l)
+		}
+		wg.Wait()
+	}
+
+	runLanes(phase1, ceiling)
+	limit := ceiling
+	if token != nil && token.cursor < limit {
+		limit = token.cursor
+	}
+	runLanes(phase2, limit)
+}
+
+// advanceLane processes up to weight batches of concurrency chunks for one
+// processor, moving its cursor to the highest contiguous success.
+func (h *HistoryDeriver) advanceLane(ctx context.Context, l *deriverLane, limit uint64, hasBlocks func(ctx context.Context, from, to uint64) (bool, error)) {
+	l.err = nil
+	for batch := 0; batch < l.weight && l.cursor < limit; batch++ {
+		if ctx.Err() != nil {
+			l.err = ctx.Err()
+			return
+		}
+
+		var chunks [][2]uint64
+		cursor := l.cursor
+		for i := 0; i < h.concurrency && cursor < limit; i++ {
+			chunkTo := cursor + h.chunkSize
+			if chunkTo > limit {
+				chunkTo = limit
 			}
-			// Check that raw blocks exist for non-first chunks too.
-			if cr.index > 0 {
-				has, err := h.repo.HasBlocksInRange(ctx, cr.from, cr.to)
-				if err != nil {
-					results[cr.index] = err
-					return
-				}
-				if !has {
-					results[cr.index] = fmt.Errorf("no raw blocks in [%d,%d)", cr.from, cr.to)
-					return
+			chunks = append(chunks, [2]uint64{cursor, chunkTo})
+			cursor = chunkTo
+		}
+
+		// Guard: if the backward ingester hasn't filled the first chunk yet,
+		// wait instead of attempting any of them.
+		has, err := hasBlocks(ctx, chunks[0][0], chunks[0][1])
+		if err != nil {
+			l.err = err
+			return
+		}
+		if !has {
+			return
+		}
+
+		results := make([]error, len(chunks))
+		var wg sync.WaitGroup
+		for i, c := range chunks {
+			wg.Add(1)
+			go func(i int, from, to uint64) {
+				defer wg.Done()
+				if i > 0 {
+					has, err := hasBlocks(ctx, from, to)
+					if err != nil {
+						results[i] = err
+						return
+					}
+					if !has {
+						results[i] = fmt.Errorf("no raw blocks in [%d,%d)", from, to)
+						return
+					}
 				}
-			}
-			results[cr.index] = h.runProcessors(ctx, cr.from, cr.to)
-		}(c)
-	}
-	wg.Wait()
+				results[i] = h.runProcessor(ctx, l.proc, from, to)
+			}(i, c[0], c[1])
+		}
+		wg.Wait()
 
-	// Find highest contiguous success from the start.
-	advanceTo := scanFrom
-	for i, c := range chunks {
-		if results[i] != nil {
-			log.Printf("%s UP: chunk [%d,%d) failed: %v — stopping at %d", h.logPrefix, c.from, c.to, results[i], advanceTo)
-			break
+		for i, c := range chunks {
+			if results[i] != nil {
+				l.err = results[i]
+				log.Printf("%s UP: %s chunk [%d,%d) failed: %v — stopping at %d", h.logPrefix, l.proc.Name(), c[0], c[1], results[i], l.cursor)
+				return
+			}
+			l.cursor = c[1]
 		}
-		advanceTo = c.to
 	}
+}
 
-	if advanceTo > scanFrom {
-		if err := h.repo.UpdateCheckpoint(ctx, h.upCheckpoint, advanceTo); err != nil {
-			log.Printf("%s Failed to update up checkpoint: %v", h.logPrefix, err)
+// ProcessorProgress is one processor's UP position as of the last round.
+type ProcessorProgress struct {
+	Processor string    `json:"processor"`
+	Weight    int       `json:"weight"`
+	Cursor    uint64    `json:"cursor"`
+	Behind    uint64    `json:"behind"` // blocks left to the ceiling
+	LastError string    `json:"last_error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HistoryDeriverProgress is a snapshot of a HistoryDeriver's UP scan.
+type HistoryDeriverProgress struct {
+	Checkpoint string              `json:"checkpoint"`
+	Ceiling    uint64              `json:"ceiling"`
+	Processors []ProcessorProgress `json:"processors"`
+}
+
+func (h *HistoryDeriver) recordProgress(lanes []*deriverLane, ceiling uint64) {
+	now := time.Now()
+	h.progressMu.Lock()
+	defer h.progressMu.Unlock()
+	h.ceiling = ceiling
+	if h.progress == nil {
+		h.progress = make(map[string]ProcessorProgress, len(lanes))
+	}
+	for _, l := range lanes {
+		p := ProcessorProgress{
+			Processor: l.proc.Name(),
+			Weight:    l.weight,
+			Cursor:    l.cursor,
+			UpdatedAt: now,
 		}
-		if advanceTo%100000 < h.chunkSize*uint64(h.concurrency) {
-			log.Printf("%s Progress UP: processed to %d (ceiling=%d, chunks=%d)", h.logPrefix, advanceTo, ceiling, len(chunks))
+		if ceiling > l.cursor {
+			p.Behind = ceiling - l.cursor
 		}
-		return true, nil
+		if l.err != nil {
+			p.LastError = l.err.Error()
+		}
+		h.progress[p.Processor] = p
 	}
+}
 
-	return false, nil
+// Progress returns the per-processor UP cursors recorded by the last round.
+// Processors appear once the UP scan has run at least once.
+func (h *HistoryDeriver) Progress() HistoryDeriverProgress {
+	h.progressMu.Lock()
+	defer h.progressMu.Unlock()
+	out := HistoryDeriverProgress{
+		Checkpoint: h.upCheckpoint,
+		Ceiling:    h.ceiling,
+		Processors: make([]ProcessorProgress, 0, len(h.progress)),
+	}
+	for _, p := range h.progress {
+		out.Processors = append(out.Processors, p)
+	}
+	sort.Slice(out.Processors, func(i, j int) bool {
+		return out.Processors[i].Processor < out.Processors[j].Processor
+	})
+	return out
 }
 
 // processDownward scans from the downCursor toward the current minRaw.
@@ -423,6 +638,14 @@ func isDeadlock(err error) bool {
 
 var maxDeadlockRetries = getEnvIntDefaultHD("HISTORY_DERIVER_MAX_DEADLOCK_RETRIES", 10)
 
+// dependsOnToken lists processors that need token_worker output for the same
+// range (ft_holdings_worker, nft_ownership_worker, daily_balance_worker).
+var dependsOnToken = map[string]bool{
+	"ft_holdings_worker":   true,
+	"nft_ownership_worker": true,
+	"daily_balance_worker": true,
+}
+
 // runProcessors executes processors concurrently for the given range.
 // Processors that depend on others (ft_holdings_worker depends on token_worker,
 // nft_ownership_worker depends on token_worker) run after their dependencies complete.
@@ -435,12 +658,6 @@ func (h *HistoryDeriver) runProcessors(ctx context.Context, from, to uint64) err
 	// Split processors into two phases:
 	// Phase 1: all processors except those that depend on token_worker output
 	// Phase 2: processors that need token_worker to finish first
-	dependsOnToken := map[string]bool{
-		"ft_holdings_worker":   true,
-		"nft_ownership_worker": true,
-		"daily_balance_worker": true,
-	}
-
 	var phase1, phase2 []Processor
 	for _, p := range h.processors {
 		if dependsOnToken[p.Name()] {
@@ -462,44 +679,10 @@ func (h *HistoryDeriver) runProcessors(ctx context.Context, from, to uint64) err
 				if ctx.Err() != nil {
 					return
 				}
-				began := time.Now()
-				var lastErr error
-				for attempt := 1; attempt <= maxDeadlockRetries; attempt++ {
-					procCtx := ctx
-					cancel := func() {}
-					if h.processorTimeoutMs > 0 {
-						procCtx, cancel = context.WithTimeout(ctx, time.Duration(h.processorTimeoutMs)*time.Millisecond)
-					}
-					lastErr = safeProcessRange(procCtx, proc, from, to)
-					cancel()
-					if lastErr == nil {
-						break
-					}
-					if !isDeadlock(lastErr) {
-						break // non-deadlock error, don't retry
-					}
-					if attempt < maxDeadlockRetries {
-						log.Printf("%s %s range [%d,%d) deadlock (attempt %d/%d), retrying...", h.logPrefix,
-							proc.Name(), from, to, attempt, maxDeadlockRetries)
-						select {
-						case <-ctx.Done():
-							return
-						case <-time.After(time.Duration(attempt*500) * time.Millisecond):
-						}
-					}
-				}
-
-				if lastErr != nil {
-					log.Printf("%s %s range [%d,%d) failed: %v", h.logPrefix, proc.Name(), from, to, lastErr)
-					_ = h.repo.LogIndexingError(ctx, proc.Name(), from, "", "HISTORY_DERIVER_ERROR", lastErr.Error(), nil)
+				if err := h.runProcessor(ctx, proc, from, to); err != nil {
 					mu.Lock()
-					errs = append(errs, fmt.Sprintf("%s: %v", proc.Name(), lastErr))
+					errs = append(errs, fmt.Sprintf("%s: %v", proc.Name(), err))
 					mu.Unlock()
-					return
-				}
-
-				if dur := time.Since(began); dur > 2*time.Second {
-					log.Printf("%s %s range [%d,%d) took %s", h.logPrefix, proc.Name(), from, to, dur)
 				}
 			}(p)
 		}
@@ -520,6 +703,50 @@ func (h *HistoryDeriver) runProcessors(ctx context.Context, from, to uint64) err
 	return nil
 }
 
+// runProcessor runs one processor over [from,to), retrying deadlocks and
+// logging the final failure to the indexing error log.
+func (h *HistoryDeriver) runProcessor(ctx context.Context, proc Processor, from, to uint64) error {
+	began := time.Now()
+	var lastErr error
+	for attempt := 1; attempt <= maxDeadlockRetries; attempt++ {
+		procCtx := ctx
+		cancel := func() {}
+		if h.processorTimeoutMs > 0 {
+			procCtx, cancel = context.WithTimeout(ctx, time.Duration(h.processorTimeoutMs)*time.Millisecond)
+		}
+		lastErr = safeProcessRange(procCtx, proc, from, to)
+		cancel()
+		if lastErr == nil {
+			break
+		}
+		if !isDeadlock(lastErr) {
+			break // non-deadlock error, don't retry
+		}
+		if attempt < maxDeadlockRetries {
+			log.Printf("%s %s range [%d,%d) deadlock (attempt %d/%d), retrying...", h.logPrefix,
+				proc.Name(), from, to, attempt, maxDeadlockRetries)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt*500) * time.Millisecond):
+			}
+		}
+	}
+
+	if lastErr != nil {
+		log.Printf("%s %s range [%d,%d) failed: %v", h.logPrefix, proc.Name(), from, to, lastErr)
+		if h.repo != nil {
+			_ = h.repo.LogIndexingError(ctx, proc.Name(), from, "", "HISTORY_DERIVER_ERROR", lastErr.Error(), nil)
+		}
+		return lastErr
+	}
+
+	if dur := time.Since(began); dur > 2*time.Second {
+		log.Printf("%s %s range [%d,%d) took %s", h.logPrefix, proc.Name(), from, to, dur)
+	}
+	return nil
+}
+
 func (h *HistoryDeriver) findWorkerFloor(ctx context.Context) (uint64, error) {
 	workerNames := []string{"token_worker", "evm_worker", "accounts_worker", "meta_worker"}
 
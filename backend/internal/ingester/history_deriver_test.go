@@ -0,0 +1,132 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeDeriverProcessor struct {
+	name  string
+	delay time.Duration
+	err   error
+
+	mu     sync.Mutex
+	ranges [][2]uint64
+}
+
+func (p *fakeDeriverProcessor) Name() string { return p.name }
+
+func (p *fakeDeriverProcessor) SchemaVersion() int { return 1 }
+
+func (p *fakeDeriverProcessor) ProcessRange(ctx context.Context, from, to uint64) error {
+	if p.delay > 0 {
+		time.Sleep(p.delay)
+	}
+	if p.err != nil {
+		return p.err
+	}
+	p.mu.Lock()
+	p.ranges = append(p.ranges, [2]uint64{from, to})
+	p.mu.Unlock()
+	return nil
+}
+
+func TestHistoryDeriverLanesAdvanceIndependently(t *testing.T) {
+	t.Parallel()
+
+	token := &fakeDeriverProcessor{name: "token_worker"}
+	meta := &fakeDeriverProcessor{name: "meta_worker", delay: 20 * time.Millisecond}
+	holdings := &fakeDeriverProcessor{name: "ft_holdings_worker"}
+	broken := &fakeDeriverProcessor{name: "tx_metrics_worker", err: errors.New("boom")}
+
+	h := NewHistoryDeriver(nil, []Processor{token, meta, holdings, broken}, HistoryDeriverConfig{
+		ChunkSize:          10,
+		Concurrency:        2,
+		ProcessorTimeoutMs: -1,
+		CheckpointPrefix:   "hd_test",
+		Weights:            map[string]int{"token_worker": 3, "ft_holdings_worker": 5},
+	})
+	var lanes []*deriverLane
+	for _, p := range h.processors {
+		lanes = append(lanes, h.newLane(p, 100))
+	}
+	hasBlocks := func(context.Context, uint64, uint64) (bool, error) { return true, nil }
+
+	h.advanceLanes(context.Background(), lanes, 1000, hasBlocks)
+	h.recordProgress(lanes, 1000)
+
+	// token: 3 batches x 2 chunks x 10 blocks; meta: 1 batch despite being slow;
+	// holdings: weight 5 but capped at token_worker's cursor; broken: stuck.
+	want := map[string]uint64{
+		"token_worker":       160,
+		"meta_worker":        120,
+		"ft_holdings_worker": 160,
+		"tx_metrics_worker":  100,
+	}
+	progress := h.Progress()
+	if progress.Checkpoint != "hd_test" || progress.Ceiling != 1000 {
+		t.Fatalf("progress header = %+v", progress)
+	}
+	if len(progress.Processors) != len(want) {
+		t.Fatalf("got %d processors, want %d", len(progress.Processors), len(want))
+	}
+	for _, p := range progress.Processors {
+		if p.Cursor != want[p.Processor] {
+			t.Errorf("%s cursor = %d, want %d", p.Processor, p.Cursor, want[p.Processor])
+		}
+		if p.Behind != 1000-want[p.Processor] {
+			t.Errorf("%s behind = %d, want %d", p.Processor, p.Behind, 1000-want[p.Processor])
+		}
+		if (p.LastError != "") != (p.Processor == "tx_metrics_worker") {
+			t.Errorf("%s last error = %q", p.Processor, p.LastError)
+		}
+	}
+	if len(meta.ranges) != 2 || meta.ranges[1] != [2]uint64{110, 120} {
+		t.Errorf("meta ranges = %v", meta.ranges)
+	}
+
+	// Second round continues from each lane's own cursor.
+	for _, l := range lanes {
+		l.start = l.cursor
+	}
+	h.advanceLanes(context.Background(), lanes, 1000, hasBlocks)
+	for _, l := range lanes {
+		if l.proc.Name() == "meta_worker" && l.cursor != 140 {
+			t.Errorf("meta_worker second round cursor = %d, want 140", l.cursor)
+		}
+		if l.proc.Name() == "token_worker" && l.cursor != 220 {
+			t.Errorf("token_worker second round cursor = %d, want 220", l.cursor)
+		}
+	}
+}
+
+func TestHistoryDeriverLaneWaitsForRawBlocks(t *testing.T) {
+	t.Parallel()
+
+	token := &fakeDeriverProcessor{name: "token_worker"}
+	h := NewHistoryDeriver(nil, []Processor{token}, HistoryDeriverConfig{
+		ChunkSize:          10,
+		ProcessorTimeoutMs: -1,
+		Weights:            map[string]int{"token_worker": 4},
+	})
+	lanes := []*deriverLane{h.newLane(token, 100)}
+	// Raw blocks only exist below 120.
+	hasBlocks := func(_ context.Context, from, _ uint64) (bool, error) { return from < 120, nil }
+
+	h.advanceLanes(context.Background(), lanes, 1000, hasBlocks)
+	if lanes[0].cursor != 120 || lanes[0].err != nil {
+		t.Fatalf("cursor = %d err = %v, want 120 and no error", lanes[0].cursor, lanes[0].err)
+	}
+}
+
+func TestParseProcessorWeights(t *testing.T) {
+	t.Parallel()
+
+	got := parseProcessorWeights(" token_worker=4, meta_worker=1,bad,evm_worker=0,x=y,")
+	if len(got) != 2 || got["token_worker"] != 4 || got["meta_worker"] != 1 {
+		t.Fatalf("weights = %v", got)
+	}
+}
@@ -35,6 +35,10 @@ func (w *MetaWorker) Name() string {
 	return "meta_worker"
 }
 
+func (w *MetaWorker) SchemaVersion() int {
+	return 1
+}
+
 func (w *MetaWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	// Address lookups and stats are derived entirely from raw.transactions and can be
 	// materialized efficiently in SQL. Importantly, the implementation must be idempotent
@@ -103,6 +107,10 @@ func (w *MetaWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint
 		return fmt.Errorf("upsert discovered NFT collections: %w", err)
 	}
 
+	if err := w.repo.InsertContractChanges(ctx, extractContractChanges(events)); err != nil {
+		return fmt.Errorf("insert contract changes: %w", err)
+	}
+
 	// Insert contract version records for each add/update event so the versions tab is populated.
 	for _, ce := range contractEvents {
 		if ce.address == "" || ce.name == "" {
@@ -249,6 +257,61 @@ type contractEventInfo struct {
 	txID    string
 }
 
+// contractEventTarget returns the contract address and name from an
+// AccountContractAdded/Updated/Removed payload.
+func contractEventTarget(payload map[string]interface{}) (string, string) {
+	address, _ := payload["address"].(string)
+	name, _ := payload["name"].(string)
+	if name == "" {
+		if v, ok := payload["contract"].(string); ok {
+			name = v
+		}
+	}
+	if name == "" {
+		if v, ok := payload["contractName"].(string); ok {
+			name = v
+		}
+	}
+	return normalizeFlowAddress(address), name
+}
+
+// extractContractChanges turns AccountContractAdded/Updated/Removed events into
+// app.contract_changes rows.
+func extractContractChanges(events []models.Event) []models.ContractChange {
+	var out []models.ContractChange
+	for _, evt := range events {
+		var change string
+		switch {
+		case strings.HasSuffix(evt.Type, ".AccountContractAdded"):
+			change = models.ContractChangeAdded
+		case strings.HasSuffix(evt.Type, ".AccountContractUpdated"):
+			change = models.ContractChangeUpdated
+		case strings.HasSuffix(evt.Type, ".AccountContractRemoved"):
+			change = models.ContractChangeRemoved
+		default:
+			continue
+		}
+		var payload map[string]interface{}
+		if err := json.Unmarshal(evt.Payload, &payload); err != nil {
+			continue
+		}
+		address, name := contractEventTarget(payload)
+		if address == "" || name == "" {
+			continue
+		}
+		out = append(out, models.ContractChange{
+			Address:       address,
+			Name:          name,
+			Change:        change,
+			BlockHeight:   evt.BlockHeight,
+			TransactionID: evt.TransactionID,
+			EventIndex:    evt.EventIndex,
+			Timestamp:     evt.Timestamp,
+		})
+	}
+	return out
+}
+
 func (w *MetaWorker) extractContracts(ctx context.Context, events []models.Event) ([]models.SmartContract, []contractEventInfo) {
 
 	var extracted []contractEventInfo
@@ -263,20 +326,8 @@ func (w *MetaWorker) extractContracts(ctx context.Context, events []models.Event
 			continue
 		}
 
-		address, _ := payload["address"].(string)
-		name, _ := payload["name"].(string)
+		address, name := contractEventTarget(payload)
 		code, _ := payload["code"].(string) // some nodes/decoders may include this directly
-		if name == "" {
-			if v, ok := payload["contract"].(string); ok {
-				name = v
-			}
-		}
-		if name == "" {
-			if v, ok := payload["contractName"].(string); ok {
-				name = v
-			}
-		}
-		address = normalizeFlowAddress(address)
 		if address == "" || name == "" {
 			continue
 		}
@@ -0,0 +1,77 @@
+package ingester
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
+)
+
+func TestContractTimelineFromEvents(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	event := func(typ, name string, height uint64, txID string) models.Event {
+		payload, _ := json.Marshal(map[string]interface{}{
+			"address":  "0x1654653399040a61",
+			"codeHash": []int{1, 2, 3},
+			"contract": name,
+		})
+		return models.Event{
+			Type:          typ,
+			Payload:       payload,
+			BlockHeight:   height,
+			TransactionID: txID,
+			Timestamp:     base.Add(time.Duration(height) * time.Second),
+		}
+	}
+	events := []models.Event{
+		// Out of order on purpose; unrelated events are ignored.
+		event("flow.AccountContractUpdated", "Vault", 300, "cc"),
+		event("flow.AccountContractAdded", "Vault", 100, "aa"),
+		event("A.1654653399040a61.FlowToken.TokensDeposited", "Vault", 150, "xx"),
+		event("flow.AccountContractUpdated", "Vault", 200, "bb"),
+		event("flow.AccountContractAdded", "Other", 120, "dd"),
+	}
+
+	var vault []models.ContractChange
+	for _, c := range extractContractChanges(events) {
+		if c.Address != "1654653399040a61" {
+			t.Fatalf("address not normalized: %q", c.Address)
+		}
+		if c.Name == "Vault" {
+			vault = append(vault, c)
+		}
+	}
+
+	timeline := repository.BuildContractTimeline(vault)
+	want := []struct {
+		kind    string
+		version int
+		height  uint64
+		tx      string
+	}{
+		{repository.ContractTimelineDeploy, 1, 100, "aa"},
+		{repository.ContractTimelineUpdate, 2, 200, "bb"},
+		{repository.ContractTimelineUpdate, 3, 300, "cc"},
+	}
+	if len(timeline) != len(want) {
+		t.Fatalf("timeline = %+v", timeline)
+	}
+	for i, w := range want {
+		e := timeline[i]
+		if e.Kind != w.kind || e.Version != w.version || e.BlockHeight != w.height || e.TransactionID != w.tx {
+			t.Fatalf("entry %d = %+v, want %+v", i, e, w)
+		}
+		if !e.Timestamp.Equal(base.Add(time.Duration(w.height) * time.Second)) {
+			t.Fatalf("entry %d timestamp = %v", i, e.Timestamp)
+		}
+	}
+
+	removed := append(vault, extractContractChanges([]models.Event{event("flow.AccountContractRemoved", "Vault", 400, "ee")})...)
+	if last := repository.BuildContractTimeline(removed)[3]; last.Kind != repository.ContractTimelineRemove || last.Version != 0 {
+		t.Fatalf("removal entry = %+v", last)
+	}
+}
@@ -41,7 +41,8 @@ func NewNFTItemMetadataWorker(repo *repository.Repository, flow *flowclient.Clie
 	}
 }
 
-func (w *NFTItemMetadataWorker) Name() string { return "nft_item_metadata_worker" }
+func (w *NFTItemMetadataWorker) Name() string       { return "nft_item_metadata_worker" }
+func (w *NFTItemMetadataWorker) SchemaVersion() int { return 1 }
 
 // ProcessRange is queue-based: it ignores block heights and instead picks (owner, collection)
 // pairs that need metadata. This makes it compatible with the AsyncWorker framework while
@@ -48,7 +48,8 @@ func NewNFTOwnershipReconciler(repo *repository.Repository, flow *flowclient.Cli
 	}
 }
 
-func (w *NFTOwnershipReconciler) Name() string { return "nft_ownership_reconciler" }
+func (w *NFTOwnershipReconciler) Name() string       { return "nft_ownership_reconciler" }
+func (w *NFTOwnershipReconciler) SchemaVersion() int { return 1 }
 
 // ProcessRange is queue-based: ignores block heights, picks top holders to reconcile.
 func (w *NFTOwnershipReconciler) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
@@ -45,6 +45,10 @@ func (w *NFTOwnershipWorker) Name() string {
 	return "nft_ownership_worker"
 }
 
+func (w *NFTOwnershipWorker) SchemaVersion() int {
+	return 1
+}
+
 func (w *NFTOwnershipWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	transfers, err := w.repo.GetTokenTransfersByRange(ctx, fromHeight, toHeight, true)
 	if err != nil {
@@ -0,0 +1,317 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
+)
+
+// NFTSalesWorker derives marketplace listings and sales from raw.events and
+// writes app.nft_listings / app.nft_sales. A sale is a ListingCompleted event
+// with purchased=true; listings that are cancelled or expire never count
+// towards volume.
+type NFTSalesWorker struct {
+	repo *repository.Repository
+}
+
+func NewNFTSalesWorker(repo *repository.Repository) *NFTSalesWorker {
+	return &NFTSalesWorker{repo: repo}
+}
+
+func (w *NFTSalesWorker) Name() string {
+	return "nft_sales_worker"
+}
+
+func (w *NFTSalesWorker) SchemaVersion() int {
+	return 1
+}
+
+// Known marketplace contracts, matched on the contract name of the event type.
+// All NFTStorefront deployments share the same event shapes.
+var knownMarketplaces = []struct {
+	ContractName   string
+	MarketplaceKey string
+}{
+	{ContractName: "NFTStorefrontV2", MarketplaceKey: "nftstorefront_v2"},
+	{ContractName: "NFTStorefront", MarketplaceKey: "nftstorefront"},
+}
+
+const (
+	nftListingAvailableEvent = "ListingAvailable"
+	nftListingCompletedEvent = "ListingCompleted"
+)
+
+// matchMarketplace returns the marketplace key and event name for a
+// ListingAvailable/ListingCompleted event of a known marketplace.
+func matchMarketplace(eventType string) (marketplace, eventName string, ok bool) {
+	parts := strings.Split(eventType, ".")
+	if len(parts) != 4 || parts[0] != "A" {
+		return "", "", false
+	}
+	eventName = parts[3]
+	if eventName != nftListingAvailableEvent && eventName != nftListingCompletedEvent {
+		return "", "", false
+	}
+	for _, m := range knownMarketplaces {
+		if parts[2] == m.ContractName {
+			return m.MarketplaceKey, eventName, true
+		}
+	}
+	return "", "", false
+}
+
+// nftListingCompletion is a parsed ListingCompleted event. NFTStorefrontV2
+// repeats the listing details; for NFTStorefront they come from the listing.
+type nftListingCompletion struct {
+	evt               models.Event
+	marketplace       string
+	listingID         string
+	purchased         bool
+	collectionAddress string
+	collectionName    string
+	nftID             string
+	price             string
+	paymentToken      string
+}
+
+// nftDeposit is an NFT landing in an account, used to find a sale's buyer.
+type nftDeposit struct {
+	collectionAddress string
+	collectionName    string
+	nftID             string
+	to                string
+}
+
+// nftMarketBatch is everything collectNFTMarketEvents pulls out of a range.
+type nftMarketBatch struct {
+	listings    []repository.NFTListing
+	completions []nftListingCompletion
+	deposits    map[string][]nftDeposit // by transaction id
+}
+
+// collectNFTMarketEvents parses marketplace listings, completions and the NFT
+// deposits needed to attribute buyers.
+func collectNFTMarketEvents(events []models.Event) nftMarketBatch {
+	batch := nftMarketBatch{deposits: make(map[string][]nftDeposit)}
+	for _, evt := range events {
+		if marketplace, eventName, ok := matchMarketplace(evt.Type); ok {
+			fields, ok := parseCadenceEventFields(evt.Payload)
+			if !ok {
+				continue
+			}
+			listingID := extractString(fields["listingResourceID"])
+			if listingID == "" {
+				continue
+			}
+			collAddr, collName := splitNFTTypeID(extractTypeID(fields["nftType"]))
+			price := firstNonEmpty(extractString(fields["salePrice"]), extractString(fields["price"]))
+			paymentToken := firstNonEmpty(extractTypeID(fields["salePaymentVaultType"]), extractTypeID(fields["ftVaultType"]))
+
+			if eventName == nftListingAvailableEvent {
+				if collAddr == "" {
+					continue
+				}
+				batch.listings = append(batch.listings, repository.NFTListing{
+					Marketplace:       marketplace,
+					ListingID:         listingID,
+					Seller:            extractAddressFromFields(fields, "storefrontAddress"),
+					CollectionAddress: collAddr,
+					CollectionName:    collName,
+					NFTID:             extractString(fields["nftID"]),
+					Price:             price,
+					PaymentToken:      paymentToken,
+					BlockHeight:       evt.BlockHeight,
+					TransactionID:     evt.TransactionID,
+					Timestamp:         evt.Timestamp,
+				})
+				continue
+			}
+			batch.completions = append(batch.completions, nftListingCompletion{
+				evt:               evt,
+				marketplace:       marketplace,
+				listingID:         listingID,
+				purchased:         extractBool(fields["purchased"]),
+				collectionAddress: collAddr,
+				collectionName:    collName,
+				nftID:             extractString(fields["nftID"]),
+				price:             price,
+				paymentToken:      paymentToken,
+			})
+			continue
+		}
+
+		isToken, isNFT := classifyTokenEvent(evt.Type)
+		if !isToken || !isNFT || !strings.Contains(evt.Type, ".Deposit") {
+			continue
+		}
+		fields, ok := parseCadenceEventFields(evt.Payload)
+		if !ok {
+			continue
+		}
+		to := extractAddressFromFields(fields, "to")
+		id := extractString(fields["id"])
+		if to == "" || id == "" {
+			continue
+		}
+		// NonFungibleToken.Deposited names the NFT type; collection events are the type.
+		collAddr, collName := splitNFTTypeID(extractTypeID(fields["type"]))
+		if collAddr == "" {
+			collAddr, collName = parseContractAddress(evt.Type), parseContractName(evt.Type)
+		}
+		batch.deposits[evt.TransactionID] = append(batch.deposits[evt.TransactionID], nftDeposit{
+			collectionAddress: collAddr,
+			collectionName:    collName,
+			nftID:             id,
+			to:                to,
+		})
+	}
+	return batch
+}
+
+// nftListingKey identifies a listing across marketplaces.
+func nftListingKey(marketplace, listingID string) string {
+	return marketplace + "/" + listingID
+}
+
+// buildNFTSales turns completions into listing closes and, for purchases,
+// sales. known holds listings by nftListingKey (this batch plus stored ones).
+// A purchase whose listing details are unknown is closed but not recorded as a
+// sale, since it has no collection or price to attribute.
+func buildNFTSales(batch nftMarketBatch, known map[string]repository.NFTListing) ([]repository.NFTListingClose, []repository.NFTSale) {
+	var closes []repository.NFTListingClose
+	var sales []repository.NFTSale
+	for _, c := range batch.completions {
+		status := repository.NFTListingRemoved
+		if c.purchased {
+			status = repository.NFTListingSold
+		}
+		closes = append(closes, repository.NFTListingClose{
+			Marketplace: c.marketplace,
+			ListingID:   c.listingID,
+			Status:      status,
+			BlockHeight: c.evt.BlockHeight,
+		})
+		if !c.purchased {
+			continue
+		}
+
+		listing := known[nftListingKey(c.marketplace, c.listingID)]
+		sale := repository.NFTSale{
+			BlockHeight:       c.evt.BlockHeight,
+			TransactionID:     c.evt.TransactionID,
+			EventIndex:        c.evt.EventIndex,
+			Marketplace:       c.marketplace,
+			ListingID:         c.listingID,
+			CollectionAddress: firstNonEmpty(c.collectionAddress, listing.CollectionAddress),
+			CollectionName:    firstNonEmpty(c.collectionName, listing.CollectionName),
+			NFTID:             firstNonEmpty(c.nftID, listing.NFTID),
+			Price:             firstNonEmpty(c.price, listing.Price),
+			PaymentToken:      firstNonEmpty(c.paymentToken, listing.PaymentToken),
+			Seller:            listing.Seller,
+			Timestamp:         c.evt.Timestamp,
+		}
+		if sale.CollectionAddress == "" || sale.NFTID == "" || sale.Price == "" {
+			continue
+		}
+		for _, d := range batch.deposits[c.evt.TransactionID] {
+			if d.nftID == sale.NFTID && d.collectionAddress == sale.CollectionAddress &&
+				d.collectionName == sale.CollectionName && d.to != sale.Seller {
+				sale.Buyer = d.to
+				break
+			}
+		}
+		sales = append(sales, sale)
+	}
+	return closes, sales
+}
+
+func (w *NFTSalesWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
+	events, err := w.repo.GetRawEventsInRange(ctx, fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("failed to fetch raw events: %w", err)
+	}
+
+	batch := collectNFTMarketEvents(events)
+	if len(batch.listings) == 0 && len(batch.completions) == 0 {
+		return nil
+	}
+
+	known := make(map[string]repository.NFTListing, len(batch.listings))
+	for _, l := range batch.listings {
+		known[nftListingKey(l.Marketplace, l.ListingID)] = l
+	}
+	// Purchases of listings created before this range need the stored listing.
+	missing := make(map[string][]string)
+	for _, c := range batch.completions {
+		if _, ok := known[nftListingKey(c.marketplace, c.listingID)]; !ok && c.purchased {
+			missing[c.marketplace] = append(missing[c.marketplace], c.listingID)
+		}
+	}
+	for marketplace, ids := range missing {
+		stored, err := w.repo.GetNFTListings(ctx, marketplace, ids)
+		if err != nil {
+			return fmt.Errorf("failed to load nft listings: %w", err)
+		}
+		for id, l := range stored {
+			known[nftListingKey(marketplace, id)] = l
+		}
+	}
+
+	closes, sales := buildNFTSales(batch, known)
+
+	if err := w.repo.UpsertNFTListings(ctx, batch.listings); err != nil {
+		return fmt.Errorf("failed to upsert nft listings: %w", err)
+	}
+	if err := w.repo.CloseNFTListings(ctx, closes); err != nil {
+		return fmt.Errorf("failed to close nft listings: %w", err)
+	}
+	if err := w.repo.InsertNFTSales(ctx, sales); err != nil {
+		return fmt.Errorf("failed to insert nft sales: %w", err)
+	}
+	return nil
+}
+
+// extractTypeID returns the type identifier of a Cadence Type value, which is
+// either already a string or {"staticType": {"typeID": ...}}.
+func extractTypeID(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case map[string]interface{}:
+		switch st := val["staticType"].(type) {
+		case string:
+			return st
+		case map[string]interface{}:
+			return extractString(st["typeID"])
+		}
+		return extractString(val["typeID"])
+	}
+	return ""
+}
+
+// splitNFTTypeID splits "A.<addr>.<Contract>.NFT" into address and contract name.
+func splitNFTTypeID(typeID string) (address, contractName string) {
+	return parseContractAddress(typeID), parseContractName(typeID)
+}
+
+func extractBool(v interface{}) bool {
+	switch val := v.(type) {
+	case bool:
+		return val
+	case string:
+		return strings.EqualFold(val, "true")
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package ingester
+
+import (
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
+)
+
+func TestMatchMarketplace(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		eventType   string
+		marketplace string
+		eventName   string
+		ok          bool
+	}{
+		{"A.4eb8a10cb9f87357.NFTStorefront.ListingAvailable", "nftstorefront", "ListingAvailable", true},
+		{"A.4eb8a10cb9f87357.NFTStorefrontV2.ListingCompleted", "nftstorefront_v2", "ListingCompleted", true},
+		{"A.4eb8a10cb9f87357.NFTStorefrontV2.StorefrontInitialized", "", "", false},
+		{"A.0b2a3299cc857e29.TopShot.Deposit", "", "", false},
+	}
+	for _, c := range cases {
+		marketplace, eventName, ok := matchMarketplace(c.eventType)
+		if marketplace != c.marketplace || eventName != c.eventName || ok != c.ok {
+			t.Errorf("matchMarketplace(%q) = %q, %q, %v", c.eventType, marketplace, eventName, ok)
+		}
+	}
+}
+
+// TestNFTListingCompletedSale lists two NFTs, sells one and cancels the other.
+// Only the purchase becomes a sale; the cancelled listing is closed as removed.
+func TestNFTListingCompletedSale(t *testing.T) {
+	t.Parallel()
+
+	listedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	soldAt := listedAt.Add(2 * time.Hour)
+	listTx := "aa00000000000000000000000000000000000000000000000000000000000001"
+	buyTx := "bb00000000000000000000000000000000000000000000000000000000000002"
+	cancelTx := "cc00000000000000000000000000000000000000000000000000000000000003"
+	const storefront = "A.4eb8a10cb9f87357.NFTStorefront"
+
+	events := []models.Event{
+		{TransactionID: listTx, BlockHeight: 1000, EventIndex: 0, Timestamp: listedAt,
+			Type: storefront + ".ListingAvailable",
+			Payload: makePayload(t, map[string]string{
+				"storefrontAddress": "0x1e3c78c6d580273b", "listingResourceID": "501",
+				"nftType": "A.0b2a3299cc857e29.TopShot.NFT", "nftID": "42",
+				"ftVaultType": "A.ead892083b3e2c6c.DapperUtilityCoin.Vault", "price": "25.00000000",
+			})},
+		{TransactionID: listTx, BlockHeight: 1000, EventIndex: 1, Timestamp: listedAt,
+			Type: storefront + ".ListingAvailable",
+			Payload: makePayload(t, map[string]string{
+				"storefrontAddress": "0x1e3c78c6d580273b", "listingResourceID": "502",
+				"nftType": "A.0b2a3299cc857e29.TopShot.NFT", "nftID": "43",
+				"ftVaultType": "A.ead892083b3e2c6c.DapperUtilityCoin.Vault", "price": "30.00000000",
+			})},
+		{TransactionID: buyTx, BlockHeight: 1200, EventIndex: 0, Timestamp: soldAt,
+			Type:    "A.0b2a3299cc857e29.TopShot.Withdraw",
+			Payload: makePayload(t, map[string]string{"id": "42", "from": "0x1e3c78c6d580273b"})},
+		{TransactionID: buyTx, BlockHeight: 1200, EventIndex: 1, Timestamp: soldAt,
+			Type:    storefront + ".ListingCompleted",
+			Payload: makePayload(t, map[string]string{"listingResourceID": "501", "purchased": "true"})},
+		{TransactionID: buyTx, BlockHeight: 1200, EventIndex: 2, Timestamp: soldAt,
+			Type:    "A.0b2a3299cc857e29.TopShot.Deposit",
+			Payload: makePayload(t, map[string]string{"id": "42", "to": "0x8c5303eaa26202d6"})},
+		{TransactionID: cancelTx, BlockHeight: 1300, EventIndex: 0, Timestamp: soldAt,
+			Type:    storefront + ".ListingCompleted",
+			Payload: makePayload(t, map[string]string{"listingResourceID": "502", "purchased": "false"})},
+	}
+
+	batch := collectNFTMarketEvents(events)
+	if len(batch.listings) != 2 || len(batch.completions) != 2 {
+		t.Fatalf("expected 2 listings and 2 completions, got %d and %d", len(batch.listings), len(batch.completions))
+	}
+	l := batch.listings[0]
+	if l.Seller != "1e3c78c6d580273b" || l.CollectionAddress != "0b2a3299cc857e29" || l.CollectionName != "TopShot" ||
+		l.NFTID != "42" || l.Price != "25.00000000" || l.PaymentToken != "A.ead892083b3e2c6c.DapperUtilityCoin.Vault" {
+		t.Fatalf("unexpected listing: %+v", l)
+	}
+
+	known := map[string]repository.NFTListing{}
+	for _, l := range batch.listings {
+		known[nftListingKey(l.Marketplace, l.ListingID)] = l
+	}
+	closes, sales := buildNFTSales(batch, known)
+
+	if len(closes) != 2 || closes[0].Status != repository.NFTListingSold || closes[1].Status != repository.NFTListingRemoved {
+		t.Fatalf("unexpected closes: %+v", closes)
+	}
+	if len(sales) != 1 {
+		t.Fatalf("expected 1 sale, got %d: %+v", len(sales), sales)
+	}
+	s := sales[0]
+	if s.ListingID != "501" || s.NFTID != "42" || s.Price != "25.00000000" || s.Seller != "1e3c78c6d580273b" ||
+		s.Buyer != "8c5303eaa26202d6" || s.TransactionID != buyTx || s.EventIndex != 1 || !s.Timestamp.Equal(soldAt) {
+		t.Fatalf("unexpected sale: %+v", s)
+	}
+}
+
+// TestNFTListingCompletedWithoutListing covers a purchase whose listing was
+// never seen: the listing is closed but no sale can be attributed.
+func TestNFTListingCompletedWithoutListing(t *testing.T) {
+	t.Parallel()
+
+	batch := collectNFTMarketEvents([]models.Event{{
+		TransactionID: "dd00000000000000000000000000000000000000000000000000000000000004",
+		BlockHeight:   2000,
+		Type:          "A.4eb8a10cb9f87357.NFTStorefront.ListingCompleted",
+		Payload:       makePayload(t, map[string]string{"listingResourceID": "777", "purchased": "true"}),
+		Timestamp:     time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+	}})
+	closes, sales := buildNFTSales(batch, nil)
+	if len(closes) != 1 || closes[0].Status != repository.NFTListingSold {
+		t.Fatalf("unexpected closes: %+v", closes)
+	}
+	if len(sales) != 0 {
+		t.Fatalf("expected no sales without listing details, got %+v", sales)
+	}
+}
+
+func TestExtractTypeID(t *testing.T) {
+	t.Parallel()
+
+	nested := map[string]interface{}{"staticType": map[string]interface{}{"kind": "Resource", "typeID": "A.0b2a3299cc857e29.TopShot.NFT"}}
+	if got := extractTypeID(nested); got != "A.0b2a3299cc857e29.TopShot.NFT" {
+		t.Errorf("nested type = %q", got)
+	}
+	if got := extractTypeID("A.1654653399040a61.FlowToken.Vault"); got != "A.1654653399040a61.FlowToken.Vault" {
+		t.Errorf("string type = %q", got)
+	}
+	if got := extractTypeID(nil); got != "" {
+		t.Errorf("nil type = %q", got)
+	}
+}
@@ -51,7 +51,8 @@ func NewProposerKeyBackfillWorker(repo *repository.Repository, flow interface{})
 	return w
 }
 
-func (w *ProposerKeyBackfillWorker) Name() string { return "proposer_key_backfill" }
+func (w *ProposerKeyBackfillWorker) Name() string       { return "proposer_key_backfill" }
+func (w *ProposerKeyBackfillWorker) SchemaVersion() int { return 1 }
 
 func (w *ProposerKeyBackfillWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	// 1. Get distinct block heights that need backfill.
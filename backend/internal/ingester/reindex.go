@@ -0,0 +1,69 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+
+	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
+)
+
+// ReindexServiceName is the checkpoint service SaveBatch records for
+// single-block reindexes triggered from the admin API.
+const ReindexServiceName = "admin_reindex"
+
+// BlockFetcher fetches everything for one height; *Worker satisfies it.
+type BlockFetcher interface {
+	FetchBlockData(ctx context.Context, height uint64) *FetchResult
+}
+
+// ReindexStore is the storage ReindexBlock needs; *repository.Repository
+// satisfies it.
+type ReindexStore interface {
+	GetBlockIndexCounts(ctx context.Context, height uint64) (repository.BlockIndexCounts, error)
+	SaveBatch(ctx context.Context, blocks []*models.Block, txs []models.Transaction, events []models.Event, serviceName string, checkpointHeight uint64) error
+}
+
+// ReindexResult reports what a single-block reindex changed.
+type ReindexResult struct {
+	Height   uint64                      `json:"height"`
+	Node     string                      `json:"node"`
+	Before   repository.BlockIndexCounts `json:"before"`
+	After    repository.BlockIndexCounts `json:"after"`
+	Warnings int                         `json:"warnings"`
+}
+
+// ReindexBlock re-fetches height from the access node and overwrites the stored
+// block, transactions and events with SaveBatch, the same way the
+// repair_indexing_anomalies tool repairs a target. Nothing is written when the
+// fetch fails.
+func ReindexBlock(ctx context.Context, f BlockFetcher, store ReindexStore, height uint64) (*ReindexResult, error) {
+	before, err := store.GetBlockIndexCounts(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("read counts for block %d: %w", height, err)
+	}
+
+	res := f.FetchBlockData(ctx, height)
+	if res.Error != nil {
+		return nil, fmt.Errorf("fetch block %d: %w", height, res.Error)
+	}
+	if res.Block == nil {
+		return nil, fmt.Errorf("fetch block %d: access node returned no block", height)
+	}
+
+	if err := store.SaveBatch(ctx, []*models.Block{res.Block}, res.Transactions, res.Events, ReindexServiceName, height); err != nil {
+		return nil, fmt.Errorf("save block %d: %w", height, err)
+	}
+
+	after, err := store.GetBlockIndexCounts(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("read counts for block %d: %w", height, err)
+	}
+	return &ReindexResult{
+		Height:   height,
+		Node:     res.Node,
+		Before:   before,
+		After:    after,
+		Warnings: len(res.Warnings),
+	}, nil
+}
@@ -0,0 +1,92 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
+)
+
+type fakeBlockFetcher struct {
+	res   *FetchResult
+	calls int
+}
+
+func (f *fakeBlockFetcher) FetchBlockData(_ context.Context, height uint64) *FetchResult {
+	f.calls++
+	f.res.Height = height
+	return f.res
+}
+
+// fakeReindexStore holds one block's counts and applies SaveBatch to them.
+type fakeReindexStore struct {
+	counts  repository.BlockIndexCounts
+	saves   int
+	service string
+}
+
+func (s *fakeReindexStore) GetBlockIndexCounts(_ context.Context, _ uint64) (repository.BlockIndexCounts, error) {
+	return s.counts, nil
+}
+
+func (s *fakeReindexStore) SaveBatch(_ context.Context, blocks []*models.Block, txs []models.Transaction, events []models.Event, serviceName string, _ uint64) error {
+	s.saves++
+	s.service = serviceName
+	s.counts = repository.BlockIndexCounts{
+		Indexed:      true,
+		BlockTxCount: uint64(blocks[0].TxCount),
+		TxCount:      uint64(len(txs)),
+		EventCount:   uint64(len(events)),
+	}
+	return nil
+}
+
+func TestReindexBlockOverwritesWithFreshData(t *testing.T) {
+	t.Parallel()
+
+	// Stored: the block claims 3 transactions but only 1 tx and 2 events made it in.
+	store := &fakeReindexStore{counts: repository.BlockIndexCounts{Indexed: true, BlockTxCount: 3, TxCount: 1, EventCount: 2}}
+	fetcher := &fakeBlockFetcher{res: &FetchResult{
+		Block:        &models.Block{Height: 500, CollectionCount: 2, TxCount: 3},
+		Transactions: []models.Transaction{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+		Events:       []models.Event{{EventIndex: 0}, {EventIndex: 1}, {EventIndex: 2}, {EventIndex: 3}, {EventIndex: 4}},
+		Node:         "access-001.mainnet28.nodes.onflow.org:9000",
+	}}
+
+	got, err := ReindexBlock(context.Background(), fetcher, store, 500)
+	if err != nil {
+		t.Fatalf("ReindexBlock: %v", err)
+	}
+	if fetcher.calls != 1 || store.saves != 1 || store.service != ReindexServiceName {
+		t.Fatalf("fetch calls=%d saves=%d service=%q", fetcher.calls, store.saves, store.service)
+	}
+	if got.Before.TxCount != 1 || got.Before.EventCount != 2 {
+		t.Fatalf("before = %+v", got.Before)
+	}
+	want := repository.BlockIndexCounts{Indexed: true, BlockTxCount: 3, TxCount: 3, EventCount: 5}
+	if got.After != want {
+		t.Fatalf("after = %+v, want %+v", got.After, want)
+	}
+	if got.Height != 500 || got.Node != fetcher.res.Node {
+		t.Fatalf("result = %+v", got)
+	}
+}
+
+func TestReindexBlockFetchErrorWritesNothing(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeReindexStore{}
+	for _, res := range []*FetchResult{
+		{Error: errors.New("rpc error: code = Unavailable")},
+		{},
+	} {
+		if _, err := ReindexBlock(context.Background(), &fakeBlockFetcher{res: res}, store, 7); err == nil {
+			t.Fatalf("expected error for %+v", res)
+		}
+	}
+	if store.saves != 0 {
+		t.Fatalf("failed fetch saved %d times", store.saves)
+	}
+}
@@ -8,12 +8,11 @@ import (
 	"strings"
 	"time"
 
+	"flowscan-clone/internal/config"
 	"flowscan-clone/internal/models"
 	"flowscan-clone/internal/repository"
 )
 
-const schedulerAddress = "e467b9dd11fa00df"
-
 // ScheduledWorker processes FlowTransactionScheduler events from raw.events
 // and writes to app.scheduled_transactions.
 type ScheduledWorker struct {
@@ -28,8 +27,13 @@ func (w *ScheduledWorker) Name() string {
 	return "scheduled_worker"
 }
 
+func (w *ScheduledWorker) SchemaVersion() int {
+	return 1
+}
+
 func (w *ScheduledWorker) schedulerEventPrefix() string {
-	return "A." + schedulerAddress + ".FlowTransactionScheduler."
+	// FlowTransactionScheduler is deployed to the service account on every network.
+	return "A." + config.Addr().FlowServiceAccount + ".FlowTransactionScheduler."
 }
 
 func (w *ScheduledWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
@@ -0,0 +1,73 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// schemaVersionStore is the subset of *repository.Repository used to track
+// processor schema versions.
+type schemaVersionStore interface {
+	GetProcessorSchemaVersions(ctx context.Context) (map[string]int, error)
+	SetProcessorSchemaVersion(ctx context.Context, processorName string, version int) error
+	ResetProcessorForRederive(ctx context.Context, processor string, historyPrefixes, siblings []string) (int64, error)
+}
+
+// ReconcileProcessorSchemaVersions compares each processor's SchemaVersion()
+// against the version recorded in app.processor_schema_versions.
+//
+// Processors seen for the first time are recorded without a reset. When a
+// version was bumped and autoReset is true, the processor is rewound with
+// ResetProcessorForRederive: its async worker leases and checkpoint, and its
+// UP lane in each of historyPrefixes (the history derivers' checkpoint
+// prefixes), so its output gets re-derived. With autoReset false the bump is
+// only logged and left unrecorded, so it is picked up once auto-reset is
+// enabled. Returns the names of processors that were reset.
+func ReconcileProcessorSchemaVersions(ctx context.Context, store schemaVersionStore, processors []Processor, historyPrefixes []string, autoReset bool) ([]string, error) {
+	stored, err := store.GetProcessorSchemaVersions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load processor schema versions: %w", err)
+	}
+
+	names := make([]string, 0, len(processors))
+	for _, p := range processors {
+		names = append(names, p.Name())
+	}
+
+	var reset []string
+	seen := make(map[string]bool, len(processors))
+	for _, p := range processors {
+		name := p.Name()
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		current := p.SchemaVersion()
+		prev, ok := stored[name]
+		switch {
+		case !ok:
+			if err := store.SetProcessorSchemaVersion(ctx, name, current); err != nil {
+				return reset, fmt.Errorf("record schema version for %s: %w", name, err)
+			}
+		case current > prev:
+			if !autoReset {
+				log.Printf("[schema_versions] %s schema version bumped %d -> %d; set PROCESSOR_SCHEMA_AUTO_RESET=true to re-derive", name, prev, current)
+				continue
+			}
+			leases, err := store.ResetProcessorForRederive(ctx, name, historyPrefixes, names)
+			if err != nil {
+				return reset, fmt.Errorf("reset %s: %w", name, err)
+			}
+			if err := store.SetProcessorSchemaVersion(ctx, name, current); err != nil {
+				return reset, fmt.Errorf("record schema version for %s: %w", name, err)
+			}
+			log.Printf("[schema_versions] %s schema version bumped %d -> %d; checkpoint, %d leases and history lanes %v reset for re-derivation", name, prev, current, leases, historyPrefixes)
+			reset = append(reset, name)
+		case current < prev:
+			log.Printf("[schema_versions] Warn: %s schema version %d is older than recorded %d; leaving checkpoint as-is", name, current, prev)
+		}
+	}
+	return reset, nil
+}
@@ -0,0 +1,111 @@
+package ingester
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeVersionedProcessor struct {
+	name    string
+	version int
+}
+
+func (p *fakeVersionedProcessor) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
+	return nil
+}
+func (p *fakeVersionedProcessor) Name() string       { return p.name }
+func (p *fakeVersionedProcessor) SchemaVersion() int { return p.version }
+
+type fakeSchemaVersionStore struct {
+	versions    map[string]int
+	checkpoints map[string]uint64
+}
+
+func (s *fakeSchemaVersionStore) GetProcessorSchemaVersions(ctx context.Context) (map[string]int, error) {
+	out := make(map[string]int, len(s.versions))
+	for k, v := range s.versions {
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (s *fakeSchemaVersionStore) SetProcessorSchemaVersion(ctx context.Context, name string, version int) error {
+	s.versions[name] = version
+	return nil
+}
+
+func (s *fakeSchemaVersionStore) ResetProcessorForRederive(ctx context.Context, name string, historyPrefixes, siblings []string) (int64, error) {
+	s.checkpoints[name] = 0
+	for _, prefix := range historyPrefixes {
+		s.checkpoints[prefix+":"+name] = 0
+	}
+	return 0, nil
+}
+
+func newFakeSchemaVersionStore() *fakeSchemaVersionStore {
+	return &fakeSchemaVersionStore{
+		versions:    map[string]int{"token_worker": 1, "evm_worker": 1, "staking_worker": 2},
+		checkpoints: map[string]uint64{"token_worker": 5000, "evm_worker": 5000, "staking_worker": 5000},
+	}
+}
+
+func TestReconcileProcessorSchemaVersionsResetsOnlyBumped(t *testing.T) {
+	t.Parallel()
+
+	store := newFakeSchemaVersionStore()
+	procs := []Processor{
+		&fakeVersionedProcessor{name: "token_worker", version: 1},
+		&fakeVersionedProcessor{name: "evm_worker", version: 2},
+		&fakeVersionedProcessor{name: "staking_worker", version: 2},
+		&fakeVersionedProcessor{name: "defi_worker", version: 1},
+	}
+
+	reset, err := ReconcileProcessorSchemaVersions(context.Background(), store, procs, []string{"history_deriver"}, true)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(reset) != 1 || reset[0] != "evm_worker" {
+		t.Fatalf("expected only evm_worker reset, got %v", reset)
+	}
+	if h, ok := store.checkpoints["evm_worker"]; !ok || h != 0 {
+		t.Fatalf("evm_worker checkpoint = %d, want 0", h)
+	}
+	if h, ok := store.checkpoints["history_deriver:evm_worker"]; !ok || h != 0 {
+		t.Fatalf("evm_worker history lane = %d (set=%v), want 0", h, ok)
+	}
+	for _, name := range []string{"token_worker", "staking_worker"} {
+		if store.checkpoints[name] != 5000 {
+			t.Fatalf("%s checkpoint changed to %d", name, store.checkpoints[name])
+		}
+	}
+	if _, ok := store.checkpoints["history_deriver:token_worker"]; ok {
+		t.Fatal("history lane of an unchanged processor was touched")
+	}
+	if _, ok := store.checkpoints["defi_worker"]; ok {
+		t.Fatal("first-seen processor should not get a checkpoint reset")
+	}
+	if store.versions["evm_worker"] != 2 || store.versions["defi_worker"] != 1 {
+		t.Fatalf("versions not recorded: %v", store.versions)
+	}
+}
+
+func TestReconcileProcessorSchemaVersionsDisabled(t *testing.T) {
+	t.Parallel()
+
+	store := newFakeSchemaVersionStore()
+	procs := []Processor{&fakeVersionedProcessor{name: "evm_worker", version: 2}}
+
+	reset, err := ReconcileProcessorSchemaVersions(context.Background(), store, procs, []string{"history_deriver"}, false)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(reset) != 0 {
+		t.Fatalf("expected no resets with auto-reset disabled, got %v", reset)
+	}
+	if store.checkpoints["evm_worker"] != 5000 {
+		t.Fatalf("evm_worker checkpoint changed to %d", store.checkpoints["evm_worker"])
+	}
+	if store.versions["evm_worker"] != 1 {
+		t.Fatalf("bump should stay unrecorded until auto-reset runs, got %d", store.versions["evm_worker"])
+	}
+}
@@ -2,6 +2,7 @@ package ingester
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -26,7 +27,9 @@ type Service struct {
 	// before the spork root height. We learn that boundary from the error message and
 	// clamp history backfill to avoid getting stuck in an infinite retry loop.
 	minAvailableHeight uint64
-	loggedHistoryFloor bool
+
+	// completions records the backward-mode completion marker; defaults to repo.
+	completions backfillCompletionStore
 }
 
 // Callback type for real-time updates
@@ -36,20 +39,39 @@ type TxBatchCallback func([]models.Transaction, []models.Event)
 type RangeCallback func(fromHeight, toHeight uint64)
 
 type Config struct {
-	BatchSize        int
-	WorkerCount      int
-	ServiceName      string
-	StartBlock       uint64
-	StopHeight       uint64 // backward mode: stop when reaching this height (0 = go to genesis)
-	Mode             string // "forward" (default) or "backward"
-	MaxReorgDepth    uint64
+	BatchSize     int
+	WorkerCount   int
+	ServiceName   string
+	StartBlock    uint64
+	StopHeight    uint64 // backward mode: stop when reaching this height (0 = go to genesis)
+	Mode          string // "forward" (default) or "backward"
+	MaxReorgDepth uint64
+	// HeadLag keeps forward mode this many blocks behind the latest sealed
+	// height, trading latency for fewer reorgs at the tip (0 = index the tip).
+	HeadLag           uint64
 	OnNewBlock        BlockCallback
-	OnNewTransaction  TxCallback        // deprecated: use OnNewTransactions for batch enrichment
+	OnNewTransaction  TxCallback // deprecated: use OnNewTransactions for batch enrichment
 	OnNewTransactions TxBatchCallback
 	// OnIndexedRange is invoked after a batch has been persisted to raw.* tables.
 	// It is intended for lightweight, real-time derived materialization at the chain head.
 	// Range is half-open: [fromHeight, toHeight).
 	OnIndexedRange RangeCallback
+	// SkipCollections never resolves collection guarantees: transactions and
+	// results come only from the bulk block APIs, and heights served by nodes
+	// without them fail instead of falling back to per-collection fetches.
+	// collection_count still comes from the block header. Nothing downstream
+	// reads collection membership, so events/transactions-only deployments
+	// lose nothing.
+	SkipCollections bool
+	// SkipEventRecheck trusts bulk transaction results as returned instead of
+	// re-fetching those whose event indices show missing events.
+	SkipEventRecheck bool
+	// StoreCollections fetches each collection's transaction ids (one RPC per
+	// guarantee) and saves them to raw.collections. Ignored with SkipCollections.
+	StoreCollections bool
+	// Backpressure, when set, delays each batch write while the DB pool is
+	// saturated so API requests can get connections.
+	Backpressure *Backpressure
 }
 
 func NewService(client *flow.Client, repo *repository.Repository, cfg Config) *Service {
@@ -65,39 +87,60 @@ func NewService(client *flow.Client, repo *repository.Repository, cfg Config) *S
 	if cfg.MaxReorgDepth == 0 {
 		cfg.MaxReorgDepth = 1000
 	}
-	return &Service{
+	if cfg.StoreCollections && cfg.SkipCollections {
+		log.Printf("[%s] Warn: STORE_COLLECTIONS is ignored because INGEST_SKIP_COLLECTIONS is set", cfg.ServiceName)
+		cfg.StoreCollections = false
+	}
+	svc := &Service{
 		client: client,
 		repo:   repo,
 		config: cfg,
 	}
+	if repo != nil {
+		svc.completions = repo
+	}
+	return svc
 }
 
-// Start runs the ingestion loop
+// Start runs the ingestion loop. In backward mode it returns nil once the
+// history range is complete (see ErrBackfillComplete).
 func (s *Service) Start(ctx context.Context) error {
 	log.Printf("Starting %s Ingester in %s mode...", s.config.ServiceName, s.config.Mode)
+	return s.run(ctx, s.process)
+}
+
+func (s *Service) run(ctx context.Context, step func(context.Context) error) error {
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 			// Run one processing cycle
-			err := s.process(ctx)
+			err := step(ctx)
+			if errors.Is(err, ErrBackfillComplete) {
+				log.Printf("[%s] History backfill complete; ingester stopped.", s.config.ServiceName)
+				return nil
+			}
 			if err != nil {
+				if wait, open := flow.IsCircuitOpen(err); open {
+					// All access nodes are failing; pause until the breaker half-opens.
+					if wait < time.Second {
+						wait = time.Second
+					}
+					log.Printf("[%s] Access node circuit breaker open, pausing %s", s.config.ServiceName, wait.Round(time.Second))
+					time.Sleep(wait)
+					continue
+				}
 				log.Printf("[%s] Error processing batch: %v", s.config.ServiceName, err)
 				time.Sleep(10 * time.Second) // Increased backoff on error
 				continue
 			}
 
-			// If process returns nil, it means we are up to date (live) or finished (history)
-			// For Live, we poll every few seconds.
-			// For History, if finished, we stop?
-			// Checking process() return values... it returns nil when "Done! reached 0" (Backward)
-			// or when "startHeight > latestHeight" (Forward).
-
-			// Sleep to avoid hot loop when caught up (forward) or done (backward).
-			// For backward mode we only pause briefly between batches to let the
-			// DB breathe; the 1-second wait is wasteful when there are millions of
-			// blocks remaining.
+			// process returns nil when the forward ingester is caught up or a
+			// batch was saved. Sleep to avoid a hot loop when caught up. For
+			// backward mode we only pause briefly between batches to let the
+			// DB breathe; the 1-second wait is wasteful when there are millions
+			// of blocks remaining.
 			if s.config.Mode == "backward" {
 				time.Sleep(50 * time.Millisecond)
 			} else {
@@ -118,67 +161,14 @@ func (s *Service) process(ctx context.Context) error {
 
 	if s.config.Mode == "backward" {
 		// --- Backward Mode ---
-		// If lastIndexed is 0, we haven't started (or really just started from scratch).
-		// We assume we start from Config.StartBlock - 1.
-		// But wait, GetLastIndexedHeight returns 0 if no row.
-		// If row exists with 0, it means we scanned down to 0 ?
-		// For simplicity: If lastIndexed == 0, check if we have a row?
-		// repo.GetLastIndexedHeight is simple scan.
-		// Let's assume if 0, we start at StartBlock.
-
-		currentTip := lastIndexed
-		if currentTip == 0 {
-			currentTip = s.config.StartBlock
-		}
-
-		if currentTip <= 1 {
-			// Done! reached 0 (or 1)
+		plan, ok := s.planBackward(lastIndexed)
+		if !ok {
 			return nil
 		}
-
-		// StopHeight: stop when we've reached the configured floor.
-		if s.config.StopHeight > 0 && currentTip <= s.config.StopHeight {
-			if !s.loggedHistoryFloor {
-				log.Printf("[%s] Reached stop height %d, done.", s.config.ServiceName, s.config.StopHeight)
-				s.loggedHistoryFloor = true
-			}
-			return nil
-		}
-
-		// Define batch: [currentTip - batch, currentTip - 1]
-		// But practically: We want to fetch decreasing.
-		// Let's settle on: range is [targetStart, targetEnd]
-		// targetEnd = currentTip - 1
-
-		targetEnd := currentTip - 1
-		targetStart := uint64(0)
-		if targetEnd >= uint64(s.config.BatchSize) {
-			targetStart = targetEnd - uint64(s.config.BatchSize) + 1
-		}
-
-		startHeight = targetStart
-		endHeight = targetEnd
-
-		// Clamp to configured StopHeight floor.
-		if s.config.StopHeight > 0 && startHeight < s.config.StopHeight {
-			startHeight = s.config.StopHeight
-		}
-
-		// If we've learned a spork root floor for this client, clamp the range.
-		// This avoids retrying a range that contains heights the node cannot serve.
-		if s.minAvailableHeight > 0 {
-			if endHeight < s.minAvailableHeight {
-				// Nothing left this node can serve.
-				if !s.loggedHistoryFloor {
-					log.Printf("[%s] History backfill reached spork root height %d. Configure FLOW_HISTORIC_ACCESS_NODES to continue indexing earlier history.", s.config.ServiceName, s.minAvailableHeight)
-					s.loggedHistoryFloor = true
-				}
-				return nil
-			}
-			if startHeight < s.minAvailableHeight {
-				startHeight = s.minAvailableHeight
-			}
+		if plan.done != nil {
+			return s.completeBackfill(ctx, *plan.done)
 		}
+		startHeight, endHeight = plan.start, plan.end
 
 		// Backward checkpoint is the lowest height we processed in this batch.
 		checkpointHeight = startHeight
@@ -192,51 +182,17 @@ func (s *Service) process(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		target := ForwardTarget(latestHeight, s.config.HeadLag)
 
-		// 3. Decide Start Height
-		if lastIndexed > 0 {
-			startHeight = lastIndexed + 1
-		} else if s.config.StartBlock > 0 {
-			startHeight = s.config.StartBlock
-		} else {
-			startHeight = latestHeight - 100 // Fallback
-		}
-
-		// 4. Check if we need to run
-		if startHeight > latestHeight {
+		// 3-5. Decide the batch, never fetching past the target
+		var ok bool
+		startHeight, endHeight, ok = forwardBatchRange(lastIndexed, s.config.StartBlock, target, s.config.BatchSize)
+		if !ok {
 			return nil
 		}
 
-		// 5. Define Batch
-		behind := latestHeight - startHeight
-
-		// Blockscout-style behavior: when we're close to the head, prefer small batches so
-		// the UI (websocket) feels real-time. Use larger batches only when we're far behind.
-		batchSize := uint64(s.config.BatchSize)
-		switch {
-		case behind == 0:
-			batchSize = 1
-		case behind <= 3:
-			batchSize = 1
-		case behind <= 20:
-			if batchSize > 5 {
-				batchSize = 5
-			}
-		case behind <= 100:
-			if batchSize > 10 {
-				batchSize = 10
-			}
-		}
-
-		endHeight = startHeight + batchSize - 1
-		if endHeight > latestHeight {
-			endHeight = latestHeight
-		}
-
-		if startHeight == latestHeight {
-			endHeight = startHeight // Real-time
-		} else {
-			log.Printf("[Live] Syncing range %d -> %d (%d blocks, behind: %d)", startHeight, endHeight, endHeight-startHeight+1, latestHeight-endHeight)
+		if startHeight != target {
+			log.Printf("[Live] Syncing range %d -> %d (%d blocks, behind: %d)", startHeight, endHeight, endHeight-startHeight+1, target-endHeight)
 		}
 
 		// Checkpoint is the highest block
@@ -254,17 +210,13 @@ func (s *Service) process(ctx context.Context) error {
 			if root, ok := extractSporkRootHeight(err); ok {
 				if root > s.minAvailableHeight {
 					s.minAvailableHeight = root
-					s.loggedHistoryFloor = false
 				}
 
 				// If this batch crosses below the spork root, clamp and retry so we still
 				// persist the blocks above the spork boundary.
 				if endHeight < s.minAvailableHeight {
-					if !s.loggedHistoryFloor {
-						log.Printf("[%s] History backfill reached spork root height %d. Configure FLOW_HISTORIC_ACCESS_NODES to continue indexing earlier history.", s.config.ServiceName, s.minAvailableHeight)
-						s.loggedHistoryFloor = true
-					}
-					return nil
+					log.Printf("[%s] History backfill reached spork root height %d. Configure FLOW_HISTORIC_ACCESS_NODES to continue indexing earlier history.", s.config.ServiceName, s.minAvailableHeight)
+					return s.completeBackfill(ctx, s.backfillCompletion(endHeight+1, BackfillReasonSporkRoot))
 				}
 
 				if startHeight < s.minAvailableHeight {
@@ -417,7 +369,7 @@ func (s *Service) ensureBatchCoverage(ctx context.Context, results []*FetchResul
 			continue
 		}
 		for _, w := range res.Warnings {
-			if err := s.repo.LogIndexingError(ctx, s.config.ServiceName, res.Height, w.TxID, "fetch_warning", w.Message, nil); err != nil {
+			if err := s.repo.LogIndexingError(ctx, s.config.ServiceName, res.Height, w.TxID, w.errorHash(), w.Message, nil); err != nil {
 				log.Printf("[%s] failed to log fetch warning before retry: %v", s.config.ServiceName, err)
 			}
 		}
@@ -461,12 +413,19 @@ func (s *Service) fetchBatchParallel(ctx context.Context, start, end uint64) ([]
 	sem := make(chan struct{}, s.config.WorkerCount) // Semaphore for concurrency control
 
 	worker := NewWorker(s.client)
+	worker.skipCollections = s.config.SkipCollections
+	worker.skipEventRecheck = s.config.SkipEventRecheck
+	worker.storeCollections = s.config.StoreCollections
 
 	// Track whether ALL blocks in the batch failed with spork-related errors,
 	// which signals we should propagate the error so the spork-boundary handler
 	// in process() can adjust the floor.
 	var sporkErr error
 	var sporkErrOnce sync.Once
+	// An open circuit breaker means no block in the batch can be fetched; abort
+	// instead of recording every height as skipped.
+	var breakerErr error
+	var breakerErrOnce sync.Once
 	var skippedCount int64
 
 	for i := 0; i < total; i++ {
@@ -482,6 +441,10 @@ func (s *Service) fetchBatchParallel(ctx context.Context, start, end uint64) ([]
 
 			res := worker.FetchBlockData(ctx, height)
 			if res.Error != nil {
+				if _, open := flow.IsCircuitOpen(res.Error); open {
+					breakerErrOnce.Do(func() { breakerErr = res.Error })
+					return
+				}
 				// Spork boundary errors should still propagate so the service can
 				// adjust the floor height. Detect and capture them.
 				errMsg := res.Error.Error()
@@ -508,6 +471,10 @@ func (s *Service) fetchBatchParallel(ctx context.Context, start, end uint64) ([]
 
 	wg.Wait()
 
+	if breakerErr != nil {
+		return nil, breakerErr
+	}
+
 	// If we got spork boundary errors, propagate so the caller can handle the floor.
 	if sporkErr != nil {
 		return nil, sporkErr
@@ -534,6 +501,10 @@ func (s *Service) fetchBatchParallel(ctx context.Context, start, end uint64) ([]
 }
 
 func (s *Service) saveBatch(ctx context.Context, results []*FetchResult, checkpointHeight uint64) error {
+	if err := s.config.Backpressure.Wait(ctx); err != nil {
+		return err
+	}
+
 	// Filter out any nils (shouldn't be any if no error)
 	var blocks []*models.Block
 	var txs []models.Transaction
@@ -559,7 +530,7 @@ func (s *Service) saveBatch(ctx context.Context, results []*FetchResult, checkpo
 
 		// Log warnings (including from skipped/failed blocks) to indexing_errors for later revisit.
 		for _, w := range res.Warnings {
-			if logErr := s.repo.LogIndexingError(ctx, s.config.ServiceName, res.Height, w.TxID, "fetch_warning", w.Message, nil); logErr != nil {
+			if logErr := s.repo.LogIndexingError(ctx, s.config.ServiceName, res.Height, w.TxID, w.errorHash(), w.Message, nil); logErr != nil {
 				log.Printf("[%s] failed to log indexing warning: %v", s.config.ServiceName, logErr)
 			}
 		}
@@ -641,6 +612,151 @@ func (s *Service) saveBatch(ctx context.Context, results []*FetchResult, checkpo
 	return nil
 }
 
+// ErrBackfillComplete is returned by process once the backward ingester has
+// indexed down to genesis, its StopHeight or the access node's spork root. The
+// completion marker has been written; Start returns nil.
+var ErrBackfillComplete = errors.New("history backfill complete")
+
+// Reasons recorded on backfill completion markers.
+const (
+	BackfillReasonGenesis    = "genesis"
+	BackfillReasonStopHeight = "stop_height"
+	BackfillReasonSporkRoot  = "spork_root"
+)
+
+// backfillCompletionStore persists completion markers; *repository.Repository
+// satisfies it.
+type backfillCompletionStore interface {
+	MarkBackfillComplete(ctx context.Context, c repository.BackfillCompletion) error
+}
+
+// backwardPlan is the next backward batch, or the completion marker when the
+// range is exhausted.
+type backwardPlan struct {
+	start, end uint64
+	done       *repository.BackfillCompletion
+}
+
+func (s *Service) backfillCompletion(floor uint64, reason string) repository.BackfillCompletion {
+	return repository.BackfillCompletion{
+		ServiceName: s.config.ServiceName,
+		StartHeight: s.config.StartBlock,
+		FloorHeight: floor,
+		Reason:      reason,
+	}
+}
+
+// planBackward picks the batch below lastIndexed (the lowest height indexed so
+// far). It reports false when there is nothing to do yet: no checkpoint and no
+// StartBlock.
+func (s *Service) planBackward(lastIndexed uint64) (backwardPlan, bool) {
+	currentTip := lastIndexed
+	if currentTip == 0 {
+		currentTip = s.config.StartBlock
+	}
+	if currentTip == 0 {
+		return backwardPlan{}, false
+	}
+	finish := func(reason string) (backwardPlan, bool) {
+		c := s.backfillCompletion(currentTip, reason)
+		return backwardPlan{done: &c}, true
+	}
+
+	if currentTip <= 1 {
+		return finish(BackfillReasonGenesis)
+	}
+	// StopHeight: stop when we've reached the configured floor.
+	if s.config.StopHeight > 0 && currentTip <= s.config.StopHeight {
+		return finish(BackfillReasonStopHeight)
+	}
+
+	// Batch: [currentTip - BatchSize, currentTip - 1], fetched descending.
+	end := currentTip - 1
+	start := uint64(0)
+	if end >= uint64(s.config.BatchSize) {
+		start = end - uint64(s.config.BatchSize) + 1
+	}
+	// Clamp to configured StopHeight floor.
+	if s.config.StopHeight > 0 && start < s.config.StopHeight {
+		start = s.config.StopHeight
+	}
+
+	// If we've learned a spork root floor for this client, clamp the range.
+	// This avoids retrying a range that contains heights the node cannot serve.
+	if s.minAvailableHeight > 0 {
+		if end < s.minAvailableHeight {
+			// Nothing left this node can serve.
+			log.Printf("[%s] History backfill reached spork root height %d. Configure FLOW_HISTORIC_ACCESS_NODES to continue indexing earlier history.", s.config.ServiceName, s.minAvailableHeight)
+			return finish(BackfillReasonSporkRoot)
+		}
+		if start < s.minAvailableHeight {
+			start = s.minAvailableHeight
+		}
+	}
+	return backwardPlan{start: start, end: end}, true
+}
+
+// completeBackfill writes the completion marker and returns ErrBackfillComplete.
+func (s *Service) completeBackfill(ctx context.Context, c repository.BackfillCompletion) error {
+	log.Printf("[%s] History backfill reached %s at height %d (start %d), marking complete.", s.config.ServiceName, c.Reason, c.FloorHeight, c.StartHeight)
+	if s.completions != nil {
+		if err := s.completions.MarkBackfillComplete(ctx, c); err != nil {
+			return fmt.Errorf("mark backfill complete: %w", err)
+		}
+	}
+	return ErrBackfillComplete
+}
+
+// ForwardTarget is the highest height forward mode indexes: the latest sealed
+// height minus headLag.
+func ForwardTarget(latestSealed, headLag uint64) uint64 {
+	if headLag >= latestSealed {
+		return 0
+	}
+	return latestSealed - headLag
+}
+
+// forwardBatchRange picks the next forward batch ending at or below target. It
+// reports false when the ingester is already caught up with target.
+func forwardBatchRange(lastIndexed, startBlock, target uint64, batchSize int) (uint64, uint64, bool) {
+	var startHeight uint64
+	if lastIndexed > 0 {
+		startHeight = lastIndexed + 1
+	} else if startBlock > 0 {
+		startHeight = startBlock
+	} else if target > 100 {
+		startHeight = target - 100 // Fallback
+	}
+
+	if startHeight > target || target == 0 {
+		return 0, 0, false
+	}
+
+	behind := target - startHeight
+
+	// Blockscout-style behavior: when we're close to the head, prefer small batches so
+	// the UI (websocket) feels real-time. Use larger batches only when we're far behind.
+	size := uint64(batchSize)
+	switch {
+	case behind <= 3:
+		size = 1
+	case behind <= 20:
+		if size > 5 {
+			size = 5
+		}
+	case behind <= 100:
+		if size > 10 {
+			size = 10
+		}
+	}
+
+	endHeight := startHeight + size - 1
+	if endHeight > target {
+		endHeight = target
+	}
+	return startHeight, endHeight, true
+}
+
 func isSystemFlowTransaction(tx models.Transaction) bool {
 	return strings.EqualFold(strings.TrimSpace(tx.PayerAddress), "0000000000000000") &&
 		strings.EqualFold(strings.TrimSpace(tx.ProposerAddress), "0000000000000000")
@@ -32,6 +32,10 @@ func (w *StakingWorker) Name() string {
 	return "staking_worker"
 }
 
+func (w *StakingWorker) SchemaVersion() int {
+	return 1
+}
+
 // stakingEventPrefix returns the prefix for FlowIDTableStaking events.
 func (w *StakingWorker) stakingEventPrefix() string {
 	return "A." + w.stakingAddress + ".FlowIDTableStaking."
@@ -46,6 +46,7 @@ func NewTokenMetadataWorker(repo *repository.Repository, flow *flowclient.Client
 }
 
 func (w *TokenMetadataWorker) Name() string { return "token_metadata_worker" }
+func (w *TokenMetadataWorker) SchemaVersion() int { return 1 }
 
 func (w *TokenMetadataWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	if w.flow == nil {
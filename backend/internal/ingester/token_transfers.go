@@ -0,0 +1,100 @@
+package ingester
+
+import (
+	"sort"
+
+	"flowscan-clone/internal/models"
+)
+
+// ParseTokenTransfers runs the token_worker transfer parsing over raw events and
+// returns FT and NFT transfers together, in the order produced by
+// NormalizeTokenTransfers. Events may be passed in any order; repeated
+// (transaction_id, event_index) pairs are parsed once.
+func ParseTokenTransfers(events []models.Event) []models.TokenTransfer {
+	sorted := make([]models.Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.BlockHeight != b.BlockHeight {
+			return a.BlockHeight < b.BlockHeight
+		}
+		if a.TransactionIndex != b.TransactionIndex {
+			return a.TransactionIndex < b.TransactionIndex
+		}
+		if a.TransactionID != b.TransactionID {
+			return a.TransactionID < b.TransactionID
+		}
+		return a.EventIndex < b.EventIndex
+	})
+
+	uniq := sorted[:0]
+	for _, e := range sorted {
+		if n := len(uniq); n > 0 && e.TransactionID == uniq[n-1].TransactionID && e.EventIndex == uniq[n-1].EventIndex {
+			continue
+		}
+		uniq = append(uniq, e)
+	}
+
+	res := processTokenEvents(uniq)
+	out := make([]models.TokenTransfer, 0, len(res.ftTransfers)+len(res.nftTransfers))
+	out = append(out, res.ftTransfers...)
+	out = append(out, res.nftTransfers...)
+	return NormalizeTokenTransfers(out)
+}
+
+// NormalizeTokenTransfers sorts transfers by (block_height, transaction_id,
+// event_index) and drops duplicates of the (block_height, transaction_id,
+// event_index, is_nft) key that the ft/nft transfer upserts conflict on.
+// Duplicates are resolved by comparing the remaining fields, so the result does
+// not depend on input order. The slice is sorted in place.
+func NormalizeTokenTransfers(transfers []models.TokenTransfer) []models.TokenTransfer {
+	if len(transfers) < 2 {
+		return transfers
+	}
+	sort.Slice(transfers, func(i, j int) bool {
+		return tokenTransferLess(transfers[i], transfers[j])
+	})
+
+	out := transfers[:1]
+	for _, t := range transfers[1:] {
+		if sameTokenTransferKey(out[len(out)-1], t) {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+func sameTokenTransferKey(a, b models.TokenTransfer) bool {
+	return a.BlockHeight == b.BlockHeight && a.TransactionID == b.TransactionID &&
+		a.EventIndex == b.EventIndex && a.IsNFT == b.IsNFT
+}
+
+func tokenTransferLess(a, b models.TokenTransfer) bool {
+	if a.BlockHeight != b.BlockHeight {
+		return a.BlockHeight < b.BlockHeight
+	}
+	if a.TransactionID != b.TransactionID {
+		return a.TransactionID < b.TransactionID
+	}
+	if a.EventIndex != b.EventIndex {
+		return a.EventIndex < b.EventIndex
+	}
+	if a.IsNFT != b.IsNFT {
+		return !a.IsNFT
+	}
+	// Tie-breaks for duplicate keys.
+	for _, p := range [][2]string{
+		{a.TokenContractAddress, b.TokenContractAddress},
+		{a.ContractName, b.ContractName},
+		{a.FromAddress, b.FromAddress},
+		{a.ToAddress, b.ToAddress},
+		{a.Amount, b.Amount},
+		{a.TokenID, b.TokenID},
+	} {
+		if p[0] != p[1] {
+			return p[0] < p[1]
+		}
+	}
+	return false
+}
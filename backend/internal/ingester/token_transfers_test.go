@@ -0,0 +1,102 @@
+package ingester
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+func TestNormalizeTokenTransfersStableAndDeduplicated(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	base := []models.TokenTransfer{
+		{TransactionID: "aa", BlockHeight: 100, EventIndex: 2, Amount: "1.0", FromAddress: "01", ToAddress: "02", Timestamp: ts},
+		{TransactionID: "aa", BlockHeight: 100, EventIndex: 5, Amount: "2.0", FromAddress: "02", ToAddress: "03", Timestamp: ts},
+		{TransactionID: "aa", BlockHeight: 100, EventIndex: 5, TokenID: "7", IsNFT: true, Amount: "1", ToAddress: "03", Timestamp: ts},
+		{TransactionID: "bb", BlockHeight: 100, EventIndex: 0, Amount: "3.0", ToAddress: "04", Timestamp: ts},
+		{TransactionID: "01", BlockHeight: 101, EventIndex: 1, Amount: "4.0", FromAddress: "05", Timestamp: ts},
+	}
+	want := append([]models.TokenTransfer(nil), base...)
+
+	// Exact duplicates (e.g. the same tx handed to two workers) and a
+	// conflicting row for an existing key.
+	input := append(append([]models.TokenTransfer(nil), base...), base[0], base[2], base[4])
+	conflict := base[1]
+	conflict.ToAddress = "ff"
+	input = append(input, conflict)
+
+	rng := rand.New(rand.NewSource(1))
+	var first []models.TokenTransfer
+	for i := 0; i < 20; i++ {
+		shuffled := append([]models.TokenTransfer(nil), input...)
+		rng.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+
+		got := NormalizeTokenTransfers(shuffled)
+		if len(got) != len(want) {
+			t.Fatalf("iteration %d: expected %d transfers, got %d: %+v", i, len(want), len(got), got)
+		}
+		for j := range want {
+			if got[j].TransactionID != want[j].TransactionID || got[j].BlockHeight != want[j].BlockHeight ||
+				got[j].EventIndex != want[j].EventIndex || got[j].IsNFT != want[j].IsNFT {
+				t.Fatalf("iteration %d: position %d is %+v, want %+v", i, j, got[j], want[j])
+			}
+		}
+		if first == nil {
+			first = append([]models.TokenTransfer(nil), got...)
+		} else if !reflect.DeepEqual(got, first) {
+			t.Fatalf("iteration %d: output depends on input order:\n%+v\n%+v", i, got, first)
+		}
+	}
+}
+
+func TestParseTokenTransfersIgnoresEventOrder(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	var events []models.Event
+	for i, tx := range []string{"tx1", "tx2", "tx3"} {
+		events = append(events,
+			models.Event{
+				TransactionID: tx, BlockHeight: uint64(200 + i), EventIndex: 0,
+				Type: "A.1654653399040a61.FlowToken.TokensWithdrawn",
+				Payload: makePayload(t, map[string]string{
+					"from": "0xaaaa1111aaaa1111", "amount": "1.00000000",
+				}),
+				Timestamp: ts,
+			},
+			models.Event{
+				TransactionID: tx, BlockHeight: uint64(200 + i), EventIndex: 1,
+				Type: "A.1654653399040a61.FlowToken.TokensDeposited",
+				Payload: makePayload(t, map[string]string{
+					"to": "0xbbbb2222bbbb2222", "amount": "1.00000000",
+				}),
+				Timestamp: ts,
+			},
+		)
+	}
+
+	want := ParseTokenTransfers(events)
+	if len(want) != 3 {
+		t.Fatalf("expected 3 transfers, got %d: %+v", len(want), want)
+	}
+	for i, tr := range want {
+		if tr.BlockHeight != uint64(200+i) || tr.FromAddress != "aaaa1111aaaa1111" || tr.ToAddress != "bbbb2222bbbb2222" {
+			t.Fatalf("unexpected transfer %d: %+v", i, tr)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(7))
+	for i := 0; i < 10; i++ {
+		shuffled := append([]models.Event(nil), events...)
+		rng.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+		// Duplicate an event, as happens when a tx is fetched twice.
+		shuffled = append(shuffled, shuffled[0])
+		if got := ParseTokenTransfers(shuffled); !reflect.DeepEqual(got, want) {
+			t.Fatalf("iteration %d: got %+v, want %+v", i, got, want)
+		}
+	}
+}
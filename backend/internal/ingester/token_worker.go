@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"flowscan-clone/internal/contractid"
 	"flowscan-clone/internal/models"
 	"flowscan-clone/internal/repository"
 )
@@ -42,6 +43,10 @@ func (w *TokenWorker) Name() string {
 	return "token_worker"
 }
 
+func (w *TokenWorker) SchemaVersion() int {
+	return 1
+}
+
 // wrapperInfo holds metadata from FungibleToken/NonFungibleToken wrapper events
 // used to enrich token-specific legs, and to create legs for tokens that only
 // emit wrapper events (e.g. EVMVMBridgedToken).
@@ -85,13 +90,13 @@ func processTokenEvents(events []models.Event) processTokenEventsResult {
 
 	// EVM bridge events (EVM.FLOWTokensWithdrawn/Deposited) represent cross-VM FLOW
 	// transfers. Collect them to enrich unpaired FlowToken legs with the COA address.
-	type evmBridgeInfo struct {
-		coaAddress string // EVM COA address (hex, 40 chars)
-		amount     string
-	}
 	evmWithdrawalsByTx := make(map[string][]evmBridgeInfo) // FLOW left EVM → Cadence deposit
 	evmDepositsByTx := make(map[string][]evmBridgeInfo)    // FLOW entered EVM → Cadence withdrawal
 
+	// FlowFees.FeesDeducted amounts per tx, used to attribute fee deposits that
+	// carry no recipient (see flow_reconstruct.go).
+	feesByTx := make(map[string][]string)
+
 	// EVM TransactionExecuted events contain from/to/data that let us decode
 	// ERC-20/721/1155 transfer recipients for bridge transactions.
 	evmCallsByTx := make(map[string][]evmCallInfo)
@@ -110,6 +115,15 @@ func processTokenEvents(events []models.Event) processTokenEventsResult {
 			}
 		}
 
+		if isFlowFeesDeductedEvent(evt.Type) {
+			if fields, ok := parseCadenceEventFields(evt.Payload); ok {
+				if amount := extractString(fields["amount"]); amount != "" {
+					feesByTx[evt.TransactionID] = append(feesByTx[evt.TransactionID], amount)
+				}
+			}
+			continue
+		}
+
 		// Collect EVM bridge events for cross-VM transfer enrichment
 		if isEVMBridgeEvent(evt.Type) {
 			fields, ok := parseCadenceEventFields(evt.Payload)
@@ -117,7 +131,13 @@ func processTokenEvents(events []models.Event) processTokenEventsResult {
 				amount := extractString(fields["amount"])
 				addr := extractString(fields["address"])
 				if amount != "" && addr != "" {
-					info := evmBridgeInfo{coaAddress: normalizeEVMAddress(addr), amount: amount}
+					info := evmBridgeInfo{
+						coaAddress:  normalizeEVMAddress(addr),
+						amount:      amount,
+						blockHeight: evt.BlockHeight,
+						eventIndex:  evt.EventIndex,
+						timestamp:   evt.Timestamp,
+					}
 					if strings.Contains(evt.Type, "FLOWTokensWithdrawn") {
 						evmWithdrawalsByTx[evt.TransactionID] = append(evmWithdrawalsByTx[evt.TransactionID], info)
 					} else {
@@ -222,6 +242,7 @@ func processTokenEvents(events []models.Event) processTokenEventsResult {
 					}
 				}
 			}
+			evmWithdrawalsByTx[txID] = evmWds
 		}
 		if evmDps := evmDepositsByTx[txID]; len(evmDps) > 0 {
 			for i := range legs {
@@ -237,6 +258,7 @@ func processTokenEvents(events []models.Event) processTokenEventsResult {
 					}
 				}
 			}
+			evmDepositsByTx[txID] = evmDps
 		}
 
 		legsByTx[txID] = legs
@@ -313,54 +335,62 @@ func processTokenEvents(events []models.Event) processTokenEventsResult {
 		}
 	}
 
-	for _, legs := range legsByTx {
-		transfers := buildTokenTransfers(legs)
-		for _, transfer := range transfers {
-			// Filter out wrapper contract duplicates (FungibleToken/NonFungibleToken
-			// emit generic events alongside specific token events like FlowToken).
-			if isWrapperContractName(transfer.ContractName) {
+	var transfers []models.TokenTransfer
+	for txID, legs := range legsByTx {
+		built := buildTokenTransfers(legs)
+		attributeFeeDeposits(built, feesByTx[txID])
+		transfers = append(transfers, built...)
+	}
+	// EVM bridge events left over after enrichment may pair up COA to COA.
+	for txID, wds := range evmWithdrawalsByTx {
+		transfers = append(transfers, pairEVMBridgeTransfers(txID, wds, evmDepositsByTx[txID])...)
+	}
+
+	for _, transfer := range transfers {
+		// Filter out wrapper contract duplicates (FungibleToken/NonFungibleToken
+		// emit generic events alongside specific token events like FlowToken).
+		if isWrapperContractName(transfer.ContractName) {
+			continue
+		}
+		// Filter fee-related transfers AFTER pairing to avoid orphaned legs.
+		if !includeFeeTransfers() {
+			if isFeeVaultAddress(transfer.FromAddress) || isFeeVaultAddress(transfer.ToAddress) {
 				continue
 			}
-			// Filter fee-related transfers AFTER pairing to avoid orphaned legs.
-			if !includeFeeTransfers() {
-				if isFeeVaultAddress(transfer.FromAddress) || isFeeVaultAddress(transfer.ToAddress) {
-					continue
-				}
-			}
+		}
 
+		if transfer.IsNFT {
+			nftTransfers = append(nftTransfers, transfer)
+		} else {
+			ftTransfers = append(ftTransfers, transfer)
+		}
+		contractAddr := strings.TrimSpace(transfer.TokenContractAddress)
+		contractName := strings.TrimSpace(transfer.ContractName)
+		if contractAddr != "" && contractName != "" && !isWrapperContractName(contractName) {
+			key := contractAddr + ":" + contractName
 			if transfer.IsNFT {
-				nftTransfers = append(nftTransfers, transfer)
+				nftCollections[key] = models.NFTCollection{
+					ContractAddress: contractAddr,
+					ContractName:    contractName,
+				}
+				contracts[key] = models.SmartContract{
+					Address:         contractAddr,
+					Name:            contractName,
+					Kind:            "NFT",
+					FirstSeenHeight: transfer.BlockHeight,
+					LastSeenHeight:  transfer.BlockHeight,
+				}
 			} else {
-				ftTransfers = append(ftTransfers, transfer)
-			}
-			contractAddr := strings.TrimSpace(transfer.TokenContractAddress)
-			contractName := strings.TrimSpace(transfer.ContractName)
-			if contractAddr != "" && contractName != "" && !isWrapperContractName(contractName) {
-				key := contractAddr + ":" + contractName
-				if transfer.IsNFT {
-					nftCollections[key] = models.NFTCollection{
-						ContractAddress: contractAddr,
-						ContractName:    contractName,
-					}
-					contracts[key] = models.SmartContract{
-						Address:         contractAddr,
-						Name:            contractName,
-						Kind:            "NFT",
-						FirstSeenHeight: transfer.BlockHeight,
-						LastSeenHeight:  transfer.BlockHeight,
-					}
-				} else {
-					ftTokens[key] = models.FTToken{
-						ContractAddress: contractAddr,
-						ContractName:    contractName,
-					}
-					contracts[key] = models.SmartContract{
-						Address:         contractAddr,
-						Name:            contractName,
-						Kind:            "FT",
-						FirstSeenHeight: transfer.BlockHeight,
-						LastSeenHeight:  transfer.BlockHeight,
-					}
+				ftTokens[key] = models.FTToken{
+					ContractAddress: contractAddr,
+					ContractName:    contractName,
+				}
+				contracts[key] = models.SmartContract{
+					Address:         contractAddr,
+					Name:            contractName,
+					Kind:            "FT",
+					FirstSeenHeight: transfer.BlockHeight,
+					LastSeenHeight:  transfer.BlockHeight,
 				}
 			}
 		}
@@ -386,8 +416,8 @@ func processTokenEvents(events []models.Event) processTokenEventsResult {
 	}
 
 	return processTokenEventsResult{
-		ftTransfers:    ftTransfers,
-		nftTransfers:   nftTransfers,
+		ftTransfers:    NormalizeTokenTransfers(ftTransfers),
+		nftTransfers:   NormalizeTokenTransfers(nftTransfers),
 		ftTokens:       ftTokens,
 		nftCollections: nftCollections,
 		contracts:      contracts,
@@ -552,25 +582,16 @@ func extractEVMContractFromBridgedName(name string) string {
 }
 
 func (w *TokenWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
-	// 1. Fetch Raw Events
-	events, err := w.repo.GetRawEventsInRange(ctx, fromHeight, toHeight)
+	result, err := w.deriveRange(ctx, fromHeight, toHeight)
 	if err != nil {
-		return fmt.Errorf("failed to fetch raw events: %w", err)
+		return err
 	}
-
-	result := processTokenEvents(events)
 	ftTransfers := result.ftTransfers
 	nftTransfers := result.nftTransfers
 	ftTokens := result.ftTokens
 	nftCollections := result.nftCollections
 	contracts := result.contracts
 
-	// Blockscout fallback: for bridge transfers with unknown EVM selectors,
-	// query Blockscout API to resolve token transfer recipients.
-	if len(result.evmCallsByTx) > 0 {
-		enrichBridgeTransfersFromBlockscout(ctx, ftTransfers, nftTransfers, result.evmCallsByTx)
-	}
-
 	// 3. Upsert to App DB
 	if len(ftTransfers) > 0 || len(nftTransfers) > 0 {
 		minH, maxH := uint64(0), uint64(0)
@@ -618,43 +639,7 @@ func (w *TokenWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uin
 	}
 	// Build address_transaction records for transfer participants so that
 	// GetTransactionsByAddress can find transfers without UNION-ing ft/nft_transfers.
-	var addrTxs []models.AddressTransaction
-	for _, ft := range ftTransfers {
-		if ft.FromAddress != "" {
-			addrTxs = append(addrTxs, models.AddressTransaction{
-				Address:       ft.FromAddress,
-				TransactionID: ft.TransactionID,
-				BlockHeight:   ft.BlockHeight,
-				Role:          "FT_SENDER",
-			})
-		}
-		if ft.ToAddress != "" {
-			addrTxs = append(addrTxs, models.AddressTransaction{
-				Address:       ft.ToAddress,
-				TransactionID: ft.TransactionID,
-				BlockHeight:   ft.BlockHeight,
-				Role:          "FT_RECEIVER",
-			})
-		}
-	}
-	for _, nt := range nftTransfers {
-		if nt.FromAddress != "" {
-			addrTxs = append(addrTxs, models.AddressTransaction{
-				Address:       nt.FromAddress,
-				TransactionID: nt.TransactionID,
-				BlockHeight:   nt.BlockHeight,
-				Role:          "NFT_SENDER",
-			})
-		}
-		if nt.ToAddress != "" {
-			addrTxs = append(addrTxs, models.AddressTransaction{
-				Address:       nt.ToAddress,
-				TransactionID: nt.TransactionID,
-				BlockHeight:   nt.BlockHeight,
-				Role:          "NFT_RECEIVER",
-			})
-		}
-	}
+	addrTxs := repository.TransferAddressTransactions(ftTransfers, nftTransfers)
 	if len(addrTxs) > 0 {
 		if err := w.repo.UpsertAddressTransactions(ctx, addrTxs); err != nil {
 			return fmt.Errorf("upsert address txs for transfers: %w", err)
@@ -692,6 +677,43 @@ func (w *TokenWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uin
 	return nil
 }
 
+// deriveRange fetches raw events for the range and runs the transfer parsing,
+// including the Blockscout fallback, without writing anything.
+func (w *TokenWorker) deriveRange(ctx context.Context, fromHeight, toHeight uint64) (processTokenEventsResult, error) {
+	// 1. Fetch Raw Events
+	events, err := w.repo.GetRawEventsInRange(ctx, fromHeight, toHeight)
+	if err != nil {
+		return processTokenEventsResult{}, fmt.Errorf("failed to fetch raw events: %w", err)
+	}
+
+	result := processTokenEvents(events)
+
+	// Blockscout fallback: for bridge transfers with unknown EVM selectors,
+	// query Blockscout API to resolve token transfer recipients.
+	if len(result.evmCallsByTx) > 0 {
+		enrichBridgeTransfersFromBlockscout(ctx, result.ftTransfers, result.nftTransfers, result.evmCallsByTx)
+	}
+	return result, nil
+}
+
+// DryRunRange computes the ft/nft transfer rows ProcessRange would write for
+// the range and diffs them against the stored rows.
+func (w *TokenWorker) DryRunRange(ctx context.Context, fromHeight, toHeight uint64, sampleLimit int) ([]repository.TableDiff, error) {
+	result, err := w.deriveRange(ctx, fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+	ft, err := w.repo.DiffTokenTransfers(ctx, fromHeight, toHeight, false, result.ftTransfers, sampleLimit)
+	if err != nil {
+		return nil, err
+	}
+	nft, err := w.repo.DiffTokenTransfers(ctx, fromHeight, toHeight, true, result.nftTransfers, sampleLimit)
+	if err != nil {
+		return nil, err
+	}
+	return []repository.TableDiff{ft, nft}, nil
+}
+
 // parseTokenLeg parses a raw event into a transfer leg for pairing.
 func (w *TokenWorker) parseTokenLeg(evt models.Event, isNFT bool) *tokenLeg {
 	fields, ok := parseCadenceEventFields(evt.Payload)
@@ -767,14 +789,8 @@ func includeFeeTransfers() bool {
 }
 
 func isFeeVaultAddress(addr string) bool {
-	feeVault := strings.ToLower(strings.TrimSpace(os.Getenv("FLOW_FEES_ADDRESS")))
-	if feeVault == "" {
-		feeVault = "f919ee77447b7497"
-	}
-	feeVault = strings.TrimPrefix(feeVault, "0x")
-
 	normalized := normalizeFlowAddress(addr)
-	return normalized != "" && normalized == feeVault
+	return normalized != "" && normalized == feeVaultAddress()
 }
 
 // stakingContracts are contracts whose events track staking state changes,
@@ -796,6 +812,12 @@ func classifyTokenEvent(eventType string) (bool, bool) {
 	if cn == "EVM" {
 		return false, false
 	}
+	// FlowFees.TokensDeposited/TokensWithdrawn are the fee vault's own bookkeeping
+	// alongside the FlowToken events; treating them as a token yields a bogus
+	// "FlowFees" transfer with no addresses.
+	if cn == "FlowFees" {
+		return false, false
+	}
 	// Staking/epoch contract events (e.g. FlowIDTableStaking.TokensWithdrawn) are NOT
 	// token transfer events — they track staking state changes, not FT movements.
 	if stakingContracts[cn] {
@@ -1187,20 +1209,20 @@ func extractAddressFromFields(fields map[string]interface{}, keys ...string) str
 	return ""
 }
 
+// parseContractAddress returns the contract address of an "A.<addr>.<Name>..."
+// type; protocol types such as "flow.AccountCreated" have none.
 func parseContractAddress(eventType string) string {
-	parts := strings.Split(eventType, ".")
-	if len(parts) >= 3 && parts[0] == "A" {
-		return normalizeFlowAddress(parts[1])
+	if !strings.HasPrefix(eventType, "A.") {
+		return ""
 	}
-	return ""
+	return contractid.Parse(eventType).Address
 }
 
 func parseContractName(eventType string) string {
-	parts := strings.Split(eventType, ".")
-	if len(parts) >= 3 && parts[0] == "A" {
-		return strings.TrimSpace(parts[2])
+	if !strings.HasPrefix(eventType, "A.") {
+		return ""
 	}
-	return ""
+	return contractid.Parse(eventType).Name
 }
 
 func isWrapperContractName(name string) bool {
@@ -34,6 +34,11 @@ func (w *TxContractsWorker) Name() string {
 	return "tx_contracts_worker"
 }
 
+// SchemaVersion 2 adds the SIMPLE_TRANSFER tag.
+func (w *TxContractsWorker) SchemaVersion() int {
+	return 2
+}
+
 var importRe = regexp.MustCompile(`(?m)^\s*import\s+([A-Za-z0-9_]+)(?:\s+from\s+0x([0-9a-fA-F]+))?`)
 
 // parseImports extracts contract identifiers from a Cadence script.
@@ -86,17 +91,12 @@ func (w *TxContractsWorker) ProcessRange(ctx context.Context, fromHeight, toHeig
 		if err != nil {
 			return err
 		}
-		// Parse and cache each script.
+		// Parse and cache each script. GetScriptTextsByHashes loads scripts
+		// stored by hash only from the chain, so every hash is present.
 		for hash, text := range scriptTexts {
 			imports := parseImports(text)
 			w.importCache.Store(hash, imports)
 		}
-		// For hashes not found in raw.scripts (shouldn't happen but be safe), cache empty.
-		for _, h := range hashList {
-			if _, ok := scriptTexts[h]; !ok {
-				w.importCache.Store(h, []contractImport(nil))
-			}
-		}
 	}
 
 	// Step 4: Build script_imports, tx_contracts, and tags from cached imports.
@@ -106,6 +106,9 @@ func (w *TxContractsWorker) ProcessRange(ctx context.Context, fromHeight, toHeig
 	seenContract := make(map[string]bool)
 	tags := make([]models.TxTag, 0)
 	seenTag := make(map[string]bool)
+	// importsByTx holds the imports of every tx whose script was parsed, for
+	// the transfer classification below.
+	importsByTx := make(map[string][]string)
 	addTag := func(txID, tag string) {
 		if txID == "" || tag == "" {
 			return
@@ -127,7 +130,9 @@ func (w *TxContractsWorker) ProcessRange(ctx context.Context, fromHeight, toHeig
 			continue
 		}
 		imports, _ := cached.([]contractImport)
+		importsByTx[tx.ID] = make([]string, 0, len(imports))
 		for _, imp := range imports {
+			importsByTx[tx.ID] = append(importsByTx[tx.ID], imp.Identifier)
 			// Only add one script_import row per unique (hash, identifier).
 			key := tx.ScriptHash + "|" + imp.Identifier
 			if !seenImport[key] {
@@ -262,6 +267,21 @@ func (w *TxContractsWorker) ProcessRange(ctx context.Context, fromHeight, toHeig
 		}
 	}
 
+	// Tag plain token sends; a tx without a parsed script is left unclassified.
+	tagsByTx := make(map[string]map[string]bool)
+	for _, t := range tags {
+		if tagsByTx[t.TransactionID] == nil {
+			tagsByTx[t.TransactionID] = make(map[string]bool)
+		}
+		tagsByTx[t.TransactionID][t.Tag] = true
+	}
+	for txID, txTags := range tagsByTx {
+		imports, ok := importsByTx[txID]
+		if ok && classifyTxKind(imports, txTags) == txKindTransfer {
+			addTag(txID, models.TxTagSimpleTransfer)
+		}
+	}
+
 	if err := w.repo.BulkUpsertTxContracts(ctx, txContracts); err != nil {
 		return err
 	}
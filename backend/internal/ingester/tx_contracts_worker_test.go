@@ -0,0 +1,97 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+	"flowscan-clone/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type stubScriptSource map[string]string // txID -> script
+
+func (s stubScriptSource) GetTransactionScript(_ context.Context, txID string, _ uint64) (string, error) {
+	if text, ok := s[txID]; ok {
+		return text, nil
+	}
+	return "", errors.New("not found")
+}
+
+// TestTxContractsWorkerOversizeScript needs a database with the schema
+// applied. A script over TX_SCRIPT_STORE_MAX_BYTES is stored by hash only; the
+// worker must still record its imports by loading it from the chain.
+func TestTxContractsWorkerOversizeScript(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	t.Setenv("TX_SCRIPT_STORE_MAX_BYTES", "64")
+	ctx := context.Background()
+
+	repo, err := repository.NewRepository(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	db, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	const height = 1_999_999_597
+	txID := fmt.Sprintf("%064x", 0x597)
+	script := "import FlowToken from 0x1654653399040a61\ntransaction { execute { log(\"" + strings.Repeat("x", 200) + "\") } }"
+	cleanup := func() {
+		for _, q := range []string{
+			`DELETE FROM app.tx_contracts WHERE block_height = $1`,
+			`DELETE FROM raw.transactions WHERE block_height = $1`,
+			`DELETE FROM raw.tx_lookup WHERE block_height = $1`,
+			`DELETE FROM raw.block_lookup WHERE height = $1`,
+			`DELETE FROM raw.blocks WHERE height = $1`,
+		} {
+			if _, err := db.Exec(ctx, q, height); err != nil {
+				t.Logf("cleanup: %v", err)
+			}
+		}
+		db.Exec(ctx, `DELETE FROM app.indexing_checkpoints WHERE service_name = 'test_tx_contracts_oversize'`)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	ts := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	tx := models.Transaction{
+		ID: txID, BlockHeight: height, Timestamp: ts, Status: "SEALED", Script: script,
+		PayerAddress: "1654653399040a61", ProposerAddress: "1654653399040a61", Authorizers: []string{"1654653399040a61"},
+	}
+	block := &models.Block{Height: height, ID: fmt.Sprintf("%064x", 0x5970), ParentID: fmt.Sprintf("%064x", 0x596f), Timestamp: ts, TxCount: 1}
+	if err := repo.SaveBatch(ctx, []*models.Block{block}, []models.Transaction{tx}, nil, "test_tx_contracts_oversize", height); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a script source the body cannot be loaded, and the range fails
+	// instead of deriving no imports.
+	w := NewTxContractsWorker(repo)
+	if err := w.ProcessRange(ctx, height, height+1); err == nil {
+		t.Fatal("expected an error while the oversize script is unloadable")
+	}
+
+	repo.SetScriptSource(stubScriptSource{txID: script})
+	if err := w.ProcessRange(ctx, height, height+1); err != nil {
+		t.Fatal(err)
+	}
+	imports, err := repo.GetTransactionImports(ctx, txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imports.Contracts) != 1 || imports.Contracts[0] != "A.1654653399040a61.FlowToken" {
+		t.Fatalf("tx_contracts = %v; want the FlowToken import", imports.Contracts)
+	}
+}
@@ -0,0 +1,69 @@
+package ingester
+
+import "strings"
+
+// Transaction kinds assigned by classifyTxKind.
+const (
+	txKindTransfer            = "transfer"
+	txKindContractInteraction = "contract_interaction"
+)
+
+// transferOnlyContracts are the contracts a plain token send imports. Any
+// other import means the script calls into application contracts.
+var transferOnlyContracts = map[string]bool{
+	"FungibleToken":              true,
+	"FlowToken":                  true,
+	"NonFungibleToken":           true,
+	"MetadataViews":              true,
+	"ViewResolver":               true,
+	"FungibleTokenMetadataViews": true,
+	"Burner":                     true,
+	"FungibleTokenSwitchboard":   true,
+	"TokenForwarding":            true,
+	"FlowStorageFees":            true,
+	"FlowFees":                   true,
+	"FlowServiceAccount":         true,
+}
+
+// nonTransferTags are tx_contracts_worker tags that rule out a simple
+// transfer even when tokens moved (swaps, sales, staking, EVM calls, ...).
+var nonTransferTags = map[string]bool{
+	"EVM":             true,
+	"EVM_BRIDGE":      true,
+	"MARKETPLACE":     true,
+	"CONTRACT_DEPLOY": true,
+	"ACCOUNT_CREATED": true,
+	"KEY_UPDATE":      true,
+	"SCHEDULED_TX":    true,
+	"SWAP":            true,
+	"LIQUIDITY":       true,
+	"STAKING":         true,
+	"LIQUID_STAKING":  true,
+	"TOKEN_MINT":      true,
+	"TOKEN_BURN":      true,
+}
+
+// classifyTxKind tells a simple value transfer from a contract interaction,
+// from the contract identifiers a transaction's script imports and the tags
+// derived for it. A transfer moves tokens (FT_TRANSFER or NFT_TRANSFER),
+// imports only the token standards, and has no tag naming another activity.
+func classifyTxKind(imports []string, tags map[string]bool) string {
+	if !tags["FT_TRANSFER"] && !tags["NFT_TRANSFER"] {
+		return txKindContractInteraction
+	}
+	for tag := range tags {
+		if nonTransferTags[tag] {
+			return txKindContractInteraction
+		}
+	}
+	for _, identifier := range imports {
+		name := identifier
+		if i := strings.LastIndex(identifier, "."); i >= 0 {
+			name = identifier[i+1:]
+		}
+		if !transferOnlyContracts[name] {
+			return txKindContractInteraction
+		}
+	}
+	return txKindTransfer
+}
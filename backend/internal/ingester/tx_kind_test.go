@@ -0,0 +1,45 @@
+package ingester
+
+import "testing"
+
+func TestClassifyTxKind(t *testing.T) {
+	t.Parallel()
+
+	flowTransfer := parseImports(`
+import FungibleToken from 0xf233dcee88fe0abe
+import FlowToken from 0x1654653399040a61
+
+transaction(amount: UFix64, to: Address) {}`)
+	swap := parseImports(`
+import FungibleToken from 0xf233dcee88fe0abe
+import FlowToken from 0x1654653399040a61
+import SwapRouter from 0xa6850776a94e6551
+
+transaction(amountIn: UFix64) {}`)
+	ids := func(imports []contractImport) []string {
+		out := make([]string, 0, len(imports))
+		for _, imp := range imports {
+			out = append(out, imp.Identifier)
+		}
+		return out
+	}
+
+	cases := []struct {
+		name    string
+		imports []string
+		tags    map[string]bool
+		want    string
+	}{
+		{"flow token transfer", ids(flowTransfer), map[string]bool{"FT_TRANSFER": true}, txKindTransfer},
+		{"dex swap", ids(swap), map[string]bool{"FT_TRANSFER": true, "SWAP": true}, txKindContractInteraction},
+		{"swap without swap event", ids(swap), map[string]bool{"FT_TRANSFER": true}, txKindContractInteraction},
+		{"token standards but evm call", ids(flowTransfer), map[string]bool{"FT_TRANSFER": true, "EVM": true}, txKindContractInteraction},
+		{"no tokens moved", ids(flowTransfer), map[string]bool{}, txKindContractInteraction},
+		{"nft send", []string{"A.1d7e57aa55817448.NonFungibleToken", "A.1d7e57aa55817448.MetadataViews"}, map[string]bool{"NFT_TRANSFER": true}, txKindTransfer},
+	}
+	for _, tc := range cases {
+		if got := classifyTxKind(tc.imports, tc.tags); got != tc.want {
+			t.Errorf("%s: classifyTxKind = %q; want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -19,6 +19,10 @@ func (w *TxMetricsWorker) Name() string {
 	return "tx_metrics_worker"
 }
 
+func (w *TxMetricsWorker) SchemaVersion() int {
+	return 1
+}
+
 func (w *TxMetricsWorker) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	// All workers use half-open ranges: [fromHeight, toHeight).
 	// BackfillTxMetricsRange uses inclusive bounds (BETWEEN), so we convert.
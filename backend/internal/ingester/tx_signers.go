@@ -0,0 +1,59 @@
+package ingester
+
+import (
+	"flowscan-clone/internal/models"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+)
+
+// TxSigners decodes which key each proposer/payer/authorizer signed with from
+// the transaction's payload and envelope signatures. The proposer's key also
+// carries the proposal sequence number.
+func TxSigners(tx *flowsdk.Transaction) []models.TxSigner {
+	if tx == nil {
+		return nil
+	}
+	authorizers := make(map[flowsdk.Address]bool, len(tx.Authorizers))
+	for _, a := range tx.Authorizers {
+		authorizers[a] = true
+	}
+
+	type sigKey struct {
+		addr flowsdk.Address
+		key  uint32
+		kind string
+	}
+	seen := make(map[sigKey]bool)
+	var out []models.TxSigner
+	add := func(sigs []flowsdk.TransactionSignature, kind string) {
+		for _, sig := range sigs {
+			k := sigKey{sig.Address, sig.KeyIndex, kind}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+
+			s := models.TxSigner{
+				Address:  sig.Address.Hex(),
+				KeyIndex: sig.KeyIndex,
+				Roles:    []string{},
+				Kind:     kind,
+			}
+			if sig.Address == tx.ProposalKey.Address && sig.KeyIndex == tx.ProposalKey.KeyIndex {
+				s.Roles = append(s.Roles, models.TxRoleProposer)
+				seq := tx.ProposalKey.SequenceNumber
+				s.SequenceNumber = &seq
+			}
+			if sig.Address == tx.Payer {
+				s.Roles = append(s.Roles, models.TxRolePayer)
+			}
+			if authorizers[sig.Address] {
+				s.Roles = append(s.Roles, models.TxRoleAuthorizer)
+			}
+			out = append(out, s)
+		}
+	}
+	add(tx.PayloadSignatures, "payload")
+	add(tx.EnvelopeSignatures, "envelope")
+	return out
+}
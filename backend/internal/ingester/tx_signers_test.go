@@ -0,0 +1,65 @@
+package ingester
+
+import (
+	"reflect"
+	"testing"
+
+	"flowscan-clone/internal/models"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+)
+
+func TestTxSignersNonZeroKeyIndex(t *testing.T) {
+	t.Parallel()
+
+	proposer := flowsdk.HexToAddress("0000000000000001")
+	authorizer := flowsdk.HexToAddress("0000000000000002")
+	payer := flowsdk.HexToAddress("0000000000000003")
+
+	tx := flowsdk.NewTransaction().
+		SetProposalKey(proposer, 3, 42).
+		SetPayer(payer).
+		AddAuthorizer(proposer).
+		AddAuthorizer(authorizer)
+	tx.PayloadSignatures = []flowsdk.TransactionSignature{
+		{Address: proposer, KeyIndex: 3, Signature: []byte{1}},
+		{Address: authorizer, KeyIndex: 7, Signature: []byte{2}},
+		{Address: authorizer, KeyIndex: 7, Signature: []byte{2}}, // duplicate is dropped
+	}
+	tx.EnvelopeSignatures = []flowsdk.TransactionSignature{
+		{Address: payer, KeyIndex: 5, Signature: []byte{3}},
+	}
+
+	got := TxSigners(tx)
+	seq := uint64(42)
+	want := []models.TxSigner{
+		{Address: proposer.Hex(), KeyIndex: 3, Roles: []string{models.TxRoleProposer, models.TxRoleAuthorizer}, Kind: "payload", SequenceNumber: &seq},
+		{Address: authorizer.Hex(), KeyIndex: 7, Roles: []string{models.TxRoleAuthorizer}, Kind: "payload"},
+		{Address: payer.Hex(), KeyIndex: 5, Roles: []string{models.TxRolePayer}, Kind: "envelope"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("TxSigners =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestTxSignersOtherKeyOfProposerIsNotProposer(t *testing.T) {
+	t.Parallel()
+
+	acct := flowsdk.HexToAddress("0000000000000001")
+	tx := flowsdk.NewTransaction().SetProposalKey(acct, 0, 9).SetPayer(acct).AddAuthorizer(acct)
+	tx.EnvelopeSignatures = []flowsdk.TransactionSignature{
+		{Address: acct, KeyIndex: 0},
+		{Address: acct, KeyIndex: 2},
+	}
+
+	got := TxSigners(tx)
+	if len(got) != 2 {
+		t.Fatalf("got %d signers, want 2", len(got))
+	}
+	if got[0].SequenceNumber == nil || *got[0].SequenceNumber != 9 || got[0].Roles[0] != models.TxRoleProposer {
+		t.Fatalf("key 0 = %+v, want proposer with sequence 9", got[0])
+	}
+	if got[1].SequenceNumber != nil || !reflect.DeepEqual(got[1].Roles, []string{models.TxRolePayer, models.TxRoleAuthorizer}) {
+		t.Fatalf("key 2 = %+v, want payer+authorizer without sequence", got[1])
+	}
+}
@@ -0,0 +1,191 @@
+package ingester
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// checkpointReader is the slice of the repository the watchdog needs.
+type checkpointReader interface {
+	GetLastIndexedHeight(ctx context.Context, serviceName string) (uint64, error)
+}
+
+// WatchdogServiceStats is the liveness state of one supervised ingester.
+type WatchdogServiceStats struct {
+	Service      string    `json:"service"`
+	LastHeight   uint64    `json:"last_height"`
+	LastProgress time.Time `json:"last_progress"`
+	Stalled      bool      `json:"stalled"`
+	StalledFor   string    `json:"stalled_for,omitempty"`
+	Restarts     int       `json:"restarts"`
+	LastRestart  time.Time `json:"last_restart,omitempty"`
+}
+
+type watchedService struct {
+	lastHeight   uint64
+	headAtCheck  uint64 // chain head when lastHeight last moved
+	lastProgress time.Time
+	stalled      bool
+	restarts     int
+	lastRestart  time.Time
+	cancel       context.CancelFunc // cancels the current run of the loop
+}
+
+// Watchdog restarts ingester loops that silently stall. A service is stalled
+// when its checkpoint has not moved for stallAfter while the chain head has;
+// the watchdog then cancels the loop's context and Supervise re-enters it.
+type Watchdog struct {
+	checkpoints checkpointReader
+	chainHead   func(ctx context.Context) (uint64, error)
+	stallAfter  time.Duration
+	interval    time.Duration
+
+	mu       sync.Mutex
+	services map[string]*watchedService
+}
+
+// NewWatchdog creates a watchdog that checks every interval and treats
+// stallAfter without checkpoint progress (while the head moves) as a stall.
+func NewWatchdog(checkpoints checkpointReader, chainHead func(ctx context.Context) (uint64, error), stallAfter, interval time.Duration) *Watchdog {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Watchdog{
+		checkpoints: checkpoints,
+		chainHead:   chainHead,
+		stallAfter:  stallAfter,
+		interval:    interval,
+		services:    make(map[string]*watchedService),
+	}
+}
+
+// Supervise runs loop (typically Service.Start) until ctx is done or loop
+// returns on its own, re-entering it whenever the watchdog cancels it for
+// stalling. It blocks like loop does.
+func (w *Watchdog) Supervise(ctx context.Context, service string, loop func(context.Context) error) error {
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+		w.mu.Lock()
+		svc := w.services[service]
+		if svc == nil {
+			svc = &watchedService{lastProgress: time.Now()}
+			w.services[service] = svc
+		}
+		svc.cancel = cancel
+		w.mu.Unlock()
+
+		err := loop(runCtx)
+		restarted := runCtx.Err() != nil
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !restarted {
+			// The loop finished by itself (e.g. history backfill complete).
+			w.mu.Lock()
+			delete(w.services, service)
+			w.mu.Unlock()
+			return err
+		}
+		log.Printf("[watchdog] Restarting %s ingester loop", service)
+	}
+}
+
+// Run checks every interval until ctx is done.
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(ctx, time.Now())
+		}
+	}
+}
+
+// check compares every supervised service's checkpoint with the last one
+// seen and restarts those stalled for longer than stallAfter.
+func (w *Watchdog) check(ctx context.Context, now time.Time) {
+	head, err := w.chainHead(ctx)
+	if err != nil {
+		// Without a head we cannot tell a stall from a halted chain.
+		log.Printf("[watchdog] chain head unavailable: %v", err)
+		return
+	}
+
+	w.mu.Lock()
+	names := make([]string, 0, len(w.services))
+	for name := range w.services {
+		names = append(names, name)
+	}
+	w.mu.Unlock()
+
+	for _, name := range names {
+		height, err := w.checkpoints.GetLastIndexedHeight(ctx, name)
+		if err != nil {
+			log.Printf("[watchdog] read checkpoint %s: %v", name, err)
+			continue
+		}
+
+		w.mu.Lock()
+		svc := w.services[name]
+		if svc == nil {
+			w.mu.Unlock()
+			continue
+		}
+		if height != svc.lastHeight || svc.headAtCheck == 0 {
+			svc.lastHeight = height
+			svc.headAtCheck = head
+			svc.lastProgress = now
+			svc.stalled = false
+			w.mu.Unlock()
+			continue
+		}
+		stalledFor := now.Sub(svc.lastProgress)
+		// A restarted loop gets a fresh stallAfter before it is judged again.
+		if head <= svc.headAtCheck || stalledFor < w.stallAfter || now.Sub(svc.lastRestart) < w.stallAfter {
+			w.mu.Unlock()
+			continue
+		}
+		svc.stalled = true
+		svc.restarts++
+		svc.lastRestart = now
+		cancel := svc.cancel
+		w.mu.Unlock()
+
+		log.Printf("[watchdog] ALERT: %s stalled at height %d for %s while chain head reached %d; restarting",
+			name, height, stalledFor.Round(time.Second), head)
+		if cancel != nil {
+			cancel()
+		}
+	}
+}
+
+// Stats returns the liveness state of every supervised service, by name.
+func (w *Watchdog) Stats() []WatchdogServiceStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := time.Now()
+	out := make([]WatchdogServiceStats, 0, len(w.services))
+	for name, svc := range w.services {
+		s := WatchdogServiceStats{
+			Service:      name,
+			LastHeight:   svc.lastHeight,
+			LastProgress: svc.lastProgress,
+			Stalled:      svc.stalled,
+			Restarts:     svc.restarts,
+			LastRestart:  svc.lastRestart,
+		}
+		if svc.stalled {
+			s.StalledFor = now.Sub(svc.lastProgress).Round(time.Second).String()
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Service < out[j].Service })
+	return out
+}
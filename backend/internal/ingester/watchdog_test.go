@@ -0,0 +1,125 @@
+package ingester
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeCheckpoints struct {
+	mu      sync.Mutex
+	heights map[string]uint64
+}
+
+func (f *fakeCheckpoints) GetLastIndexedHeight(ctx context.Context, serviceName string) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.heights[serviceName], nil
+}
+
+func (f *fakeCheckpoints) set(name string, h uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.heights[name] = h
+}
+
+func TestWatchdogRestartsStalledIngester(t *testing.T) {
+	t.Parallel()
+
+	checkpoints := &fakeCheckpoints{heights: map[string]uint64{"main_ingester": 1000}}
+	var head uint64 = 1000
+	var headMu sync.Mutex
+	chainHead := func(ctx context.Context) (uint64, error) {
+		headMu.Lock()
+		defer headMu.Unlock()
+		return head, nil
+	}
+	setHead := func(h uint64) {
+		headMu.Lock()
+		head = h
+		headMu.Unlock()
+	}
+	wd := NewWatchdog(checkpoints, chainHead, 5*time.Minute, time.Minute)
+
+	// The loop blocks until cancelled, like an ingester stuck on a dead connection.
+	starts := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- wd.Supervise(ctx, "main_ingester", func(runCtx context.Context) error {
+			starts <- struct{}{}
+			<-runCtx.Done()
+			return runCtx.Err()
+		})
+	}()
+	waitStart := func() {
+		t.Helper()
+		select {
+		case <-starts:
+		case <-time.After(5 * time.Second):
+			t.Fatal("ingester loop was not (re)started")
+		}
+	}
+	waitStart()
+
+	t0 := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	wd.check(ctx, t0) // baseline
+
+	// Checkpoint advancing with the head is healthy.
+	checkpoints.set("main_ingester", 1100)
+	setHead(1100)
+	wd.check(ctx, t0.Add(10*time.Minute))
+	if s := wd.Stats()[0]; s.Stalled || s.Restarts != 0 || s.LastHeight != 1100 {
+		t.Fatalf("advancing ingester should not be stalled: %+v", s)
+	}
+
+	// A halted chain is not a stall: neither checkpoint nor head moves.
+	wd.check(ctx, t0.Add(11*time.Minute))
+	if s := wd.Stats()[0]; s.Stalled {
+		t.Fatalf("unchanged head should not count as a stall: %+v", s)
+	}
+
+	// The head moves on but the checkpoint does not.
+	setHead(1500)
+	wd.check(ctx, t0.Add(13*time.Minute))
+	if s := wd.Stats()[0]; s.Stalled {
+		t.Fatalf("stall shorter than stallAfter should not fire yet: %+v", s)
+	}
+	wd.check(ctx, t0.Add(16*time.Minute))
+	s := wd.Stats()[0]
+	if !s.Stalled || s.Restarts != 1 {
+		t.Fatalf("expected watchdog to fire once, got %+v", s)
+	}
+	waitStart()
+
+	// Right after a restart the loop gets a grace period.
+	wd.check(ctx, t0.Add(17*time.Minute))
+	if s := wd.Stats()[0]; s.Restarts != 1 {
+		t.Fatalf("restarted loop should get a grace period, got %+v", s)
+	}
+
+	// Progress clears the stall.
+	checkpoints.set("main_ingester", 1501)
+	wd.check(ctx, t0.Add(18*time.Minute))
+	if s := wd.Stats()[0]; s.Stalled || s.Restarts != 1 {
+		t.Fatalf("progress should clear the stall: %+v", s)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Supervise should return the parent context error, got %v", err)
+	}
+}
+
+func TestWatchdogSuperviseReturnsWhenLoopFinishes(t *testing.T) {
+	t.Parallel()
+
+	wd := NewWatchdog(&fakeCheckpoints{heights: map[string]uint64{}}, func(context.Context) (uint64, error) { return 1, nil }, time.Minute, time.Minute)
+	if err := wd.Supervise(context.Background(), "history_ingester", func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("Supervise = %v, want nil", err)
+	}
+	if stats := wd.Stats(); len(stats) != 0 {
+		t.Fatalf("finished loop should no longer be watched: %+v", stats)
+	}
+}
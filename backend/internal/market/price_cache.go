@@ -12,19 +12,73 @@ type DailyPrice struct {
 	Price float64
 }
 
+// SourceStatic marks prices pinned in code (stablecoins); they never go stale.
+const SourceStatic = "static"
+
+// DefaultStaleAfter is how old an asset's latest price may get before it is
+// reported stale, unless a threshold is configured for its source.
+const DefaultStaleAfter = 48 * time.Hour
+
 type PriceCache struct {
-	mu     sync.RWMutex
-	prices map[string][]DailyPrice // key: uppercase asset symbol
+	mu         sync.RWMutex
+	prices     map[string][]DailyPrice  // key: uppercase asset symbol
+	updated    map[string]time.Time     // when each asset was last loaded or appended to
+	observed   map[string]PriceQuote    // newest live quote per asset
+	staleAfter map[string]time.Duration // key: source; "" is the default
+}
+
+// LatestQuote is an asset's newest cached price and how fresh it is.
+type LatestQuote struct {
+	Price  float64
+	AsOf   time.Time
+	Source string // empty when only the daily series is known
+	Stale  bool
+}
+
+// AssetStats summarizes the cached series of one asset.
+type AssetStats struct {
+	Asset       string
+	Points      int
+	LatestPrice float64
+	LatestDate  time.Time
+	UpdatedAt   time.Time
 }
 
 func NewPriceCache() *PriceCache {
-	return &PriceCache{prices: make(map[string][]DailyPrice)}
+	return &PriceCache{
+		prices:     make(map[string][]DailyPrice),
+		updated:    make(map[string]time.Time),
+		observed:   make(map[string]PriceQuote),
+		staleAfter: map[string]time.Duration{"": DefaultStaleAfter},
+	}
+}
+
+// SetStaleAfter sets the age after which prices from source are stale; an
+// empty source sets the default. d <= 0 means prices from source never go stale.
+func (c *PriceCache) SetStaleAfter(source string, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.staleAfter[strings.ToLower(source)] = d
+}
+
+// Observe records a live quote for asset. Unlike the daily series, which keeps
+// the first price seen each day, it tracks the newest quote and its source.
+func (c *PriceCache) Observe(asset string, q PriceQuote) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := strings.ToUpper(asset)
+	if prev, ok := c.observed[key]; ok && prev.AsOf.After(q.AsOf) {
+		return
+	}
+	c.observed[key] = q
 }
 
 func (c *PriceCache) Load(asset string, prices []DailyPrice) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.prices[strings.ToUpper(asset)] = prices
+	key := strings.ToUpper(asset)
+	c.prices[key] = prices
+	c.updated[key] = time.Now()
 }
 
 func (c *PriceCache) Append(asset string, prices []DailyPrice) {
@@ -45,6 +99,7 @@ func (c *PriceCache) Append(asset string, prices []DailyPrice) {
 	}
 	sort.Slice(existing, func(i, j int) bool { return existing[i].Date.Before(existing[j].Date) })
 	c.prices[key] = existing
+	c.updated[key] = time.Now()
 }
 
 // GetPriceAt returns the daily price closest to ts. Returns (0, false) if no price within 48h.
@@ -92,6 +147,39 @@ func (c *PriceCache) GetLatestPrice(asset string) (float64, bool) {
 	return ps[len(ps)-1].Price, true
 }
 
+// GetLatestQuote returns the newest price known for asset, from either the
+// daily series or the last observed quote, and whether it is stale at now.
+func (c *PriceCache) GetLatestQuote(asset string, now time.Time) (LatestQuote, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key := strings.ToUpper(asset)
+	ps := c.prices[key]
+	obs, hasObs := c.observed[key]
+	if len(ps) == 0 && !hasObs {
+		return LatestQuote{}, false
+	}
+	var q LatestQuote
+	if len(ps) > 0 {
+		q = LatestQuote{Price: ps[len(ps)-1].Price, AsOf: ps[len(ps)-1].Date}
+	}
+	if hasObs && !obs.AsOf.Before(q.AsOf) {
+		q = LatestQuote{Price: obs.Price, AsOf: obs.AsOf, Source: obs.Source}
+	}
+	q.Stale = c.isStale(q, now)
+	return q, true
+}
+
+func (c *PriceCache) isStale(q LatestQuote, now time.Time) bool {
+	if q.Source == SourceStatic {
+		return false
+	}
+	d, ok := c.staleAfter[strings.ToLower(q.Source)]
+	if !ok {
+		d = c.staleAfter[""]
+	}
+	return d > 0 && now.Sub(q.AsOf) > d
+}
+
 // GetLatestPriceWithChange returns the latest price and 24h change percentage.
 func (c *PriceCache) GetLatestPriceWithChange(asset string) (price float64, change float64, ok bool) {
 	c.mu.RLock()
@@ -139,3 +227,20 @@ func (c *PriceCache) GetAllLatestPrices() map[string]float64 {
 	}
 	return out
 }
+
+// Stats returns a summary of every cached asset, sorted by asset symbol.
+func (c *PriceCache) Stats() []AssetStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]AssetStats, 0, len(c.prices))
+	for asset, ps := range c.prices {
+		st := AssetStats{Asset: asset, Points: len(ps), UpdatedAt: c.updated[asset]}
+		if len(ps) > 0 {
+			st.LatestPrice = ps[len(ps)-1].Price
+			st.LatestDate = ps[len(ps)-1].Date
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Asset < out[j].Asset })
+	return out
+}
@@ -0,0 +1,56 @@
+package market
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetLatestQuoteStaleness(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	c := NewPriceCache()
+	c.Load("FLOW", []DailyPrice{{Date: now.Add(-72 * time.Hour).Truncate(24 * time.Hour), Price: 0.5}})
+
+	q, ok := c.GetLatestQuote("flow", now)
+	if !ok {
+		t.Fatal("expected a cached FLOW price")
+	}
+	if !q.Stale || q.Price != 0.5 {
+		t.Fatalf("3-day-old price should be stale under the default threshold: %+v", q)
+	}
+
+	// A per-source threshold applies to observed quotes from that source.
+	c.Observe("FLOW", PriceQuote{Price: 0.6, Source: "defillama", AsOf: now.Add(-3 * time.Hour)})
+	if q, _ := c.GetLatestQuote("FLOW", now); q.Stale || q.Price != 0.6 || q.Source != "defillama" {
+		t.Fatalf("fresh observed quote should win and not be stale: %+v", q)
+	}
+	c.SetStaleAfter("defillama", 2*time.Hour)
+	if q, _ := c.GetLatestQuote("FLOW", now); !q.Stale {
+		t.Fatalf("3h-old quote should be stale under a 2h defillama threshold: %+v", q)
+	}
+
+	// An older observation does not replace a newer one.
+	c.Observe("FLOW", PriceQuote{Price: 0.1, Source: "coingecko", AsOf: now.Add(-5 * time.Hour)})
+	if q, _ := c.GetLatestQuote("FLOW", now); q.Price != 0.6 {
+		t.Fatalf("older quote replaced newer one: %+v", q)
+	}
+
+	// Disabling the default threshold never marks prices stale.
+	c.SetStaleAfter("", 0)
+	c.Load("ABC", []DailyPrice{{Date: now.Add(-30 * 24 * time.Hour), Price: 2}})
+	if q, _ := c.GetLatestQuote("ABC", now); q.Stale {
+		t.Fatalf("threshold 0 should disable staleness: %+v", q)
+	}
+
+	// Static prices never go stale.
+	c.SetStaleAfter("", time.Hour)
+	c.Observe("USDC", PriceQuote{Price: 1, Source: SourceStatic, AsOf: now.Add(-90 * 24 * time.Hour)})
+	if q, _ := c.GetLatestQuote("USDC", now); q.Stale {
+		t.Fatalf("static price should never be stale: %+v", q)
+	}
+
+	if _, ok := c.GetLatestQuote("NOPE", now); ok {
+		t.Fatal("unknown asset should not resolve")
+	}
+}
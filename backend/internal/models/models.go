@@ -27,9 +27,18 @@ type Block struct {
 	TotalGasUsed uint64        `json:"total_gas_used"`
 	IsSealed     bool          `json:"is_sealed"`
 	Transactions []Transaction `json:"transactions,omitempty"` // For block details
+	Collections  []Collection  `json:"collections,omitempty"`  // Only fetched with STORE_COLLECTIONS
 	CreatedAt    time.Time     `json:"created_at"`
 }
 
+// Collection represents raw.collections: the transactions of one collection
+// guarantee in a block.
+type Collection struct {
+	ID             string   `json:"id"`
+	BlockHeight    uint64   `json:"block_height"`
+	TransactionIDs []string `json:"transaction_ids"`
+}
+
 // Transaction represents the 'transactions' table
 type Transaction struct {
 	ID                     string          `json:"id"`
@@ -42,7 +51,7 @@ type Transaction struct {
 	Authorizers            []string        `json:"authorizers"` // Stored as TEXT[] in DB
 	Script                 string          `json:"script,omitempty"`
 	Arguments              json.RawMessage `json:"arguments,omitempty"` // Stored as JSONB
-	Status                 string          `json:"status"`              // SEALED, EXPIRED, PENDING
+	Status                 string          `json:"status"`              // canonical TxStatus* value, see NormalizeTxStatus
 	ErrorMessage           string          `json:"error_message,omitempty"`
 
 	// Redundancy Fields
@@ -50,6 +59,8 @@ type Transaction struct {
 	ProposalKey        []byte `json:"proposal_key,omitempty"`
 	PayloadSignatures  []byte `json:"payload_signatures,omitempty"`
 	EnvelopeSignatures []byte `json:"envelope_signatures,omitempty"`
+	// Signers is a JSON []TxSigner (raw.transactions.signers).
+	Signers json.RawMessage `json:"signers,omitempty"`
 
 	// EVM Support
 	IsEVM    bool   `json:"is_evm"`
@@ -119,6 +130,19 @@ type EVMTxHash struct {
 	CreatedAt        time.Time `json:"created_at"`
 }
 
+// EVMLog is one log of an EVM transaction (app.evm_logs). LogIndex is the
+// position of the log within its EVM transaction.
+type EVMLog struct {
+	EVMHash       string    `json:"evm_hash"`
+	LogIndex      int       `json:"log_index"`
+	BlockHeight   uint64    `json:"block_height"`
+	TransactionID string    `json:"transaction_id"`
+	Address       string    `json:"address"`
+	Topics        []string  `json:"topics"`
+	Data          string    `json:"data,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
 // Event represents the 'events' table
 type Event struct {
 	ID               int             `json:"id"`
@@ -229,12 +253,19 @@ type SmartContract struct {
 
 // AddressStats represents the address_stats table
 type AddressStats struct {
-	Address          string    `json:"address"`
-	TxCount          int64     `json:"tx_count"`
-	TotalGasUsed     uint64    `json:"total_gas_used"`
-	LastUpdatedBlock uint64    `json:"last_updated_block"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	Address          string `json:"address"`
+	TxCount          int64  `json:"tx_count"`
+	TotalGasUsed     uint64 `json:"total_gas_used"`
+	LastUpdatedBlock uint64 `json:"last_updated_block"`
+	// Per-role counts of app.address_transactions rows. FT and NFT transfers
+	// are counted separately, so one tx moving both counts twice.
+	PayerCount            int64     `json:"payer_count"`
+	ProposerCount         int64     `json:"proposer_count"`
+	AuthorizerCount       int64     `json:"authorizer_count"`
+	TransferSentCount     int64     `json:"transfer_sent_count"`
+	TransferReceivedCount int64     `json:"transfer_received_count"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
 }
 
 // DailyStat represents daily transaction statistics
@@ -336,6 +367,11 @@ type TxTag struct {
 	Tag           string `json:"tag"`
 }
 
+// TxTagSimpleTransfer marks a plain token send: the transaction moves FT/NFT
+// tokens and touches nothing beyond the token standards. Transactions without
+// it are contract interactions.
+const TxTagSimpleTransfer = "SIMPLE_TRANSFER"
+
 // AccountLabel represents app.account_labels
 type AccountLabel struct {
 	Address  string `json:"address"`
@@ -412,6 +448,24 @@ type ContractVersion struct {
 	CreatedAt     time.Time `json:"created_at"`
 }
 
+// Contract change kinds recorded in app.contract_changes.
+const (
+	ContractChangeAdded   = "added"
+	ContractChangeUpdated = "updated"
+	ContractChangeRemoved = "removed"
+)
+
+// ContractChange is one AccountContractAdded/Updated/Removed event for a contract.
+type ContractChange struct {
+	Address       string    `json:"address"`
+	Name          string    `json:"name"`
+	Change        string    `json:"change"`
+	BlockHeight   uint64    `json:"block_height"`
+	TransactionID string    `json:"transaction_id"`
+	EventIndex    int       `json:"event_index"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
 // StakingEvent represents a staking-related event from app.staking_events.
 type StakingEvent struct {
 	BlockHeight   uint64    `json:"block_height"`
@@ -0,0 +1,20 @@
+package models
+
+// Roles a TxSigner can hold in a transaction.
+const (
+	TxRoleProposer   = "proposer"
+	TxRolePayer      = "payer"
+	TxRoleAuthorizer = "authorizer"
+)
+
+// TxSigner is one signature on a transaction: which account key signed, in
+// which roles, and whether it signed the payload or the envelope. Stored as
+// JSON in raw.transactions.signers.
+type TxSigner struct {
+	Address  string   `json:"address"`
+	KeyIndex uint32   `json:"key_index"`
+	Roles    []string `json:"roles"`
+	Kind     string   `json:"kind"` // "payload" or "envelope"
+	// SequenceNumber is set only on the proposer's key.
+	SequenceNumber *uint64 `json:"sequence_number,omitempty"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "strings"
+
+// Canonical values of Transaction.Status (raw.transactions.status).
+const (
+	TxStatusSealed   = "SEALED"
+	TxStatusExecuted = "EXECUTED"
+	TxStatusExpired  = "EXPIRED"
+	TxStatusPending  = "PENDING"
+	TxStatusFailed   = "FAILED"
+)
+
+// NormalizeTxStatus maps an access-node status (flowsdk.TransactionStatus
+// String form) and the transaction's error message onto a canonical status.
+// A transaction that ran and returned an error is FAILED; UNKNOWN, FINALIZED
+// and empty statuses are PENDING.
+func NormalizeTxStatus(status, errorMessage string) string {
+	s := strings.ToUpper(strings.TrimSpace(status))
+	if s == TxStatusExpired {
+		return TxStatusExpired
+	}
+	if strings.TrimSpace(errorMessage) != "" || s == TxStatusFailed {
+		return TxStatusFailed
+	}
+	switch s {
+	case TxStatusSealed, TxStatusExecuted:
+		return s
+	}
+	return TxStatusPending
+}
+
+// ParseTxStatus validates a user-supplied status (case-insensitive) against
+// the canonical set.
+func ParseTxStatus(s string) (string, bool) {
+	switch s = strings.ToUpper(strings.TrimSpace(s)); s {
+	case TxStatusSealed, TxStatusExecuted, TxStatusExpired, TxStatusPending, TxStatusFailed:
+		return s, true
+	}
+	return "", false
+}
@@ -0,0 +1,39 @@
+package models
+
+import "testing"
+
+func TestNormalizeTxStatus(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		status, errMsg, want string
+	}{
+		{"SEALED", "", TxStatusSealed},
+		{"SEALED", "[Error Code: 1101] cadence runtime error", TxStatusFailed},
+		{"EXECUTED", "", TxStatusExecuted},
+		{"EXECUTED", "out of gas", TxStatusFailed},
+		{"EXPIRED", "", TxStatusExpired},
+		{"FINALIZED", "", TxStatusPending},
+		{"UNKNOWN", "", TxStatusPending},
+		{"", "", TxStatusPending},
+		{"sealed", "", TxStatusSealed},
+		{"FAILED", "", TxStatusFailed},
+	} {
+		if got := NormalizeTxStatus(tc.status, tc.errMsg); got != tc.want {
+			t.Errorf("NormalizeTxStatus(%q, %q) = %q, want %q", tc.status, tc.errMsg, got, tc.want)
+		}
+	}
+}
+
+func TestParseTxStatus(t *testing.T) {
+	t.Parallel()
+
+	if got, ok := ParseTxStatus(" failed "); !ok || got != TxStatusFailed {
+		t.Fatalf("ParseTxStatus(failed) = %q, %v", got, ok)
+	}
+	for _, bad := range []string{"", "FINALIZED", "SEALED' OR 1=1 --"} {
+		if _, ok := ParseTxStatus(bad); ok {
+			t.Fatalf("ParseTxStatus(%q) accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// AccountGrowthRow is one day of account growth: accounts created that day
+// (flow.AccountCreated events) and the running total up to and including it.
+type AccountGrowthRow struct {
+	Date          string `json:"date"`
+	NewAccounts   int64  `json:"new_accounts"`
+	TotalAccounts int64  `json:"total_accounts"`
+}
+
+// GetAccountGrowth returns per-day new and cumulative account counts for the
+// UTC dates in [from, to], read from app.daily_stats.accounts_created (kept up
+// to date by the analytics deriver). Days without stats count as zero.
+func (r *Repository) GetAccountGrowth(ctx context.Context, from, to time.Time) ([]AccountGrowthRow, error) {
+	var base int64
+	if err := r.readDB().QueryRow(ctx, `
+		SELECT COALESCE(SUM(accounts_created), 0)::bigint
+		FROM app.daily_stats
+		WHERE date < $1::date`, from.UTC()).Scan(&base); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.readDB().Query(ctx, `
+		WITH dates AS (
+			SELECT generate_series($1::date, $2::date, '1 day'::interval)::date AS date
+		)
+		SELECT d.date::text, COALESCE(s.accounts_created, 0)::bigint
+		FROM dates d
+		LEFT JOIN app.daily_stats s ON s.date = d.date
+		ORDER BY d.date ASC`, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []AccountGrowthRow{}
+	for rows.Next() {
+		var row AccountGrowthRow
+		if err := rows.Scan(&row.Date, &row.NewAccounts); err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	accumulateAccountGrowth(base, out)
+	return out, nil
+}
+
+// accumulateAccountGrowth fills TotalAccounts as base plus the running sum of
+// NewAccounts. rows must be in date order.
+func accumulateAccountGrowth(base int64, rows []AccountGrowthRow) {
+	total := base
+	for i := range rows {
+		total += rows[i].NewAccounts
+		rows[i].TotalAccounts = total
+	}
+}
@@ -0,0 +1,30 @@
+package repository
+
+import "testing"
+
+func TestAccumulateAccountGrowth(t *testing.T) {
+	t.Parallel()
+
+	rows := []AccountGrowthRow{
+		{Date: "2026-01-01", NewAccounts: 5},
+		{Date: "2026-01-02", NewAccounts: 0},
+		{Date: "2026-01-03", NewAccounts: 12},
+		{Date: "2026-01-04", NewAccounts: 1},
+	}
+	const base = 100
+	accumulateAccountGrowth(base, rows)
+
+	prev := int64(base)
+	for i, row := range rows {
+		if row.TotalAccounts < prev {
+			t.Fatalf("row %d: total %d decreased from %d", i, row.TotalAccounts, prev)
+		}
+		if row.TotalAccounts-prev != row.NewAccounts {
+			t.Errorf("row %d: total grew by %d, want daily delta %d", i, row.TotalAccounts-prev, row.NewAccounts)
+		}
+		prev = row.TotalAccounts
+	}
+	if prev != 118 {
+		t.Errorf("final total = %d, want 118", prev)
+	}
+}
@@ -16,7 +16,7 @@ func (r *Repository) GetAccountStorageSnapshot(ctx context.Context, address stri
 		WHERE address = $1`, hexToBytes(address))
 	var s AccountStorageSnapshot
 	if err := row.Scan(&s.Address, &s.StorageUsed, &s.StorageCapacity, &s.StorageAvailable); err != nil {
-		return nil, err
+		return nil, wrapDBErr(err, "storage snapshot "+address)
 	}
 	return &s, nil
 }
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"flowscan-clone/internal/models"
+)
+
+// TransferAddressTransactions builds the app.address_transactions rows for
+// transfer participants (FT_/NFT_ SENDER and RECEIVER), so that account
+// activity reads can stay a single indexed scan of address_transactions
+// instead of UNION-ing ft_transfers and nft_transfers.
+func TransferAddressTransactions(ftTransfers, nftTransfers []models.TokenTransfer) []models.AddressTransaction {
+	out := make([]models.AddressTransaction, 0, 2*(len(ftTransfers)+len(nftTransfers)))
+	add := func(transfers []models.TokenTransfer, senderRole, receiverRole string) {
+		for _, t := range transfers {
+			if t.FromAddress != "" {
+				out = append(out, models.AddressTransaction{
+					Address:       t.FromAddress,
+					TransactionID: t.TransactionID,
+					BlockHeight:   t.BlockHeight,
+					Role:          senderRole,
+				})
+			}
+			if t.ToAddress != "" {
+				out = append(out, models.AddressTransaction{
+					Address:       t.ToAddress,
+					TransactionID: t.TransactionID,
+					BlockHeight:   t.BlockHeight,
+					Role:          receiverRole,
+				})
+			}
+		}
+	}
+	add(ftTransfers, "FT_SENDER", "FT_RECEIVER")
+	add(nftTransfers, "NFT_SENDER", "NFT_RECEIVER")
+	return out
+}
+
+// BackfillAddressTransferRolesRange inserts the transfer-participant rows of
+// app.address_transactions for [fromHeight, toHeight) from app.ft_transfers and
+// app.nft_transfers, and bumps the transfer counters in app.address_stats for
+// newly inserted rows only. Use it for history indexed before the token worker
+// wrote these roles; it is safe to run repeatedly.
+func (r *Repository) BackfillAddressTransferRolesRange(ctx context.Context, fromHeight, toHeight uint64) (int64, error) {
+	if toHeight <= fromHeight {
+		return 0, nil
+	}
+
+	var inserted int64
+	err := r.db.QueryRow(ctx, `
+		WITH src AS (
+			SELECT from_address AS address, transaction_id, block_height, 'FT_SENDER' AS role
+			FROM app.ft_transfers
+			WHERE block_height >= $1 AND block_height < $2 AND COALESCE(length(from_address), 0) > 0
+
+			UNION ALL
+			SELECT to_address, transaction_id, block_height, 'FT_RECEIVER'
+			FROM app.ft_transfers
+			WHERE block_height >= $1 AND block_height < $2 AND COALESCE(length(to_address), 0) > 0
+
+			UNION ALL
+			SELECT from_address, transaction_id, block_height, 'NFT_SENDER'
+			FROM app.nft_transfers
+			WHERE block_height >= $1 AND block_height < $2 AND COALESCE(length(from_address), 0) > 0
+
+			UNION ALL
+			SELECT to_address, transaction_id, block_height, 'NFT_RECEIVER'
+			FROM app.nft_transfers
+			WHERE block_height >= $1 AND block_height < $2 AND COALESCE(length(to_address), 0) > 0
+		),
+		ins AS (
+			INSERT INTO app.address_transactions (address, transaction_id, block_height, role)
+			SELECT DISTINCT address, transaction_id, block_height, role FROM src
+			WHERE NOT EXISTS (
+				SELECT 1 FROM app.address_stats st
+				WHERE st.address = src.address AND src.block_height < st.compacted_below_height
+			)
+			ON CONFLICT (address, block_height, transaction_id, role) DO NOTHING
+			RETURNING address, role
+		),
+		roles AS (
+			SELECT address,
+			       COUNT(*) FILTER (WHERE role IN ('FT_SENDER', 'NFT_SENDER'))::bigint AS sent,
+			       COUNT(*) FILTER (WHERE role IN ('FT_RECEIVER', 'NFT_RECEIVER'))::bigint AS received
+			FROM ins
+			GROUP BY address
+		),
+		stats AS (
+			INSERT INTO app.address_stats (address, transfer_sent_count, transfer_received_count, created_at, updated_at)
+			SELECT address, sent, received, NOW(), NOW()
+			FROM roles
+			ON CONFLICT (address) DO UPDATE SET
+				transfer_sent_count = app.address_stats.transfer_sent_count + EXCLUDED.transfer_sent_count,
+				transfer_received_count = app.address_stats.transfer_received_count + EXCLUDED.transfer_received_count,
+				updated_at = NOW()
+		)
+		SELECT COUNT(*) FROM ins
+	`, fromHeight, toHeight).Scan(&inserted)
+	if err != nil {
+		return 0, fmt.Errorf("backfill address transfer roles: %w", err)
+	}
+	return inserted, nil
+}
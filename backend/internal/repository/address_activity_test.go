@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"testing"
+
+	"flowscan-clone/internal/models"
+)
+
+type activityKey struct {
+	height uint64
+	txID   string
+}
+
+// TestTransferAddressTransactionsMatchesUnion checks that the denormalized
+// address_transactions rows (signer roles + transfer roles) yield the same
+// transaction set for an address as the old UNION over raw.transactions,
+// ft_transfers and nft_transfers.
+func TestTransferAddressTransactionsMatchesUnion(t *testing.T) {
+	t.Parallel()
+
+	const addr = "00000000000000aa"
+	txs := []models.Transaction{
+		{ID: "t1", BlockHeight: 10, PayerAddress: addr, ProposerAddress: addr, Authorizers: []string{addr}},
+		{ID: "t2", BlockHeight: 11, PayerAddress: "00000000000000bb", ProposerAddress: "00000000000000bb"},
+		{ID: "t3", BlockHeight: 12, PayerAddress: "00000000000000cc", ProposerAddress: "00000000000000cc"},
+		{ID: "t4", BlockHeight: 13, PayerAddress: "00000000000000bb", ProposerAddress: "00000000000000bb"},
+	}
+	ft := []models.TokenTransfer{
+		{TransactionID: "t1", BlockHeight: 10, FromAddress: addr, ToAddress: "00000000000000bb"},
+		{TransactionID: "t2", BlockHeight: 11, FromAddress: "00000000000000bb", ToAddress: addr},
+		{TransactionID: "t4", BlockHeight: 13, FromAddress: "", ToAddress: "00000000000000bb"},
+	}
+	nft := []models.TokenTransfer{
+		{TransactionID: "t3", BlockHeight: 12, FromAddress: "", ToAddress: addr},
+	}
+
+	// Old UNION: signer roles on raw.transactions plus either side of a transfer.
+	union := map[activityKey]bool{}
+	for _, tx := range txs {
+		involved := tx.PayerAddress == addr || tx.ProposerAddress == addr
+		for _, a := range tx.Authorizers {
+			involved = involved || a == addr
+		}
+		if involved {
+			union[activityKey{tx.BlockHeight, tx.ID}] = true
+		}
+	}
+	for _, tr := range append(append([]models.TokenTransfer{}, ft...), nft...) {
+		if tr.FromAddress == addr || tr.ToAddress == addr {
+			union[activityKey{tr.BlockHeight, tr.TransactionID}] = true
+		}
+	}
+
+	// Denormalized: signer rows as written by the derivers plus transfer rows.
+	var rows []models.AddressTransaction
+	for _, tx := range txs {
+		rows = append(rows,
+			models.AddressTransaction{Address: tx.PayerAddress, TransactionID: tx.ID, BlockHeight: tx.BlockHeight, Role: "PAYER"},
+			models.AddressTransaction{Address: tx.ProposerAddress, TransactionID: tx.ID, BlockHeight: tx.BlockHeight, Role: "PROPOSER"},
+		)
+		for _, a := range tx.Authorizers {
+			rows = append(rows, models.AddressTransaction{Address: a, TransactionID: tx.ID, BlockHeight: tx.BlockHeight, Role: "AUTHORIZER"})
+		}
+	}
+	rows = append(rows, TransferAddressTransactions(ft, nft)...)
+
+	denorm := map[activityKey]bool{}
+	for _, r := range rows {
+		if r.Address == addr {
+			denorm[activityKey{r.BlockHeight, r.TransactionID}] = true
+		}
+	}
+
+	if len(union) != 3 {
+		t.Fatalf("sample setup: union has %d txs, want 3", len(union))
+	}
+	if len(denorm) != len(union) {
+		t.Fatalf("denormalized set %v != union set %v", denorm, union)
+	}
+	for k := range union {
+		if !denorm[k] {
+			t.Fatalf("denormalized set missing %+v", k)
+		}
+	}
+}
+
+func TestTransferAddressTransactionsRoles(t *testing.T) {
+	t.Parallel()
+
+	rows := TransferAddressTransactions(
+		[]models.TokenTransfer{{TransactionID: "t1", BlockHeight: 1, FromAddress: "aa", ToAddress: "bb"}},
+		[]models.TokenTransfer{{TransactionID: "t2", BlockHeight: 2, ToAddress: "cc"}},
+	)
+	want := []string{"aa/FT_SENDER", "bb/FT_RECEIVER", "cc/NFT_RECEIVER"}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(rows), len(want), rows)
+	}
+	for i, w := range want {
+		if got := rows[i].Address + "/" + rows[i].Role; got != w {
+			t.Fatalf("row %d = %s, want %s", i, got, w)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AddressCompaction reports one address trimmed by CompactAddressActivity.
+type AddressCompaction struct {
+	Address              string `json:"address"`
+	DeletedRows          int64  `json:"deleted_rows"`
+	CompactedBelowHeight uint64 `json:"compacted_below_height"`
+}
+
+// addressCompactionCutoff returns the highest block height to drop so that
+// only the newest keep transactions remain. txHeights holds the address's
+// distinct transactions' heights, newest first. Whole blocks are dropped, so
+// when several transactions share the cutoff block slightly fewer than keep
+// remain.
+func addressCompactionCutoff(txHeights []uint64, keep int) (uint64, bool) {
+	if keep <= 0 || len(txHeights) <= keep {
+		return 0, false
+	}
+	return txHeights[keep], true
+}
+
+// CompactAddressActivity caps app.address_transactions at roughly the newest
+// keep transactions for up to maxAddresses of the busiest addresses (by
+// address_stats.tx_count). The aggregate counts in app.address_stats are left
+// untouched and compacted_below_height records the cut, so derivers do not
+// re-insert (and re-count) trimmed history and the API can flag the list as
+// truncated.
+func (r *Repository) CompactAddressActivity(ctx context.Context, keep, maxAddresses int) ([]AddressCompaction, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+	if maxAddresses <= 0 {
+		maxAddresses = 100
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT address
+		FROM app.address_stats
+		WHERE tx_count > $1
+		ORDER BY tx_count DESC
+		LIMIT $2`, keep, maxAddresses)
+	if err != nil {
+		return nil, fmt.Errorf("compaction candidates: %w", err)
+	}
+	var candidates [][]byte
+	for rows.Next() {
+		var addr []byte
+		if err := rows.Scan(&addr); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		candidates = append(candidates, addr)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var out []AddressCompaction
+	for _, addr := range candidates {
+		c, ok, err := r.compactAddress(ctx, addr, keep)
+		if err != nil {
+			return out, err
+		}
+		if ok {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (r *Repository) compactAddress(ctx context.Context, addr []byte, keep int) (AddressCompaction, bool, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT block_height
+		FROM (
+			SELECT DISTINCT block_height, transaction_id
+			FROM app.address_transactions
+			WHERE address = $1
+			ORDER BY block_height DESC, transaction_id DESC
+			LIMIT $2
+		) t
+		ORDER BY block_height DESC`, addr, keep+1)
+	if err != nil {
+		return AddressCompaction{}, false, fmt.Errorf("compaction cutoff %s: %w", bytesToHex(addr), err)
+	}
+	txHeights := make([]uint64, 0, keep+1)
+	for rows.Next() {
+		var h uint64
+		if err := rows.Scan(&h); err != nil {
+			rows.Close()
+			return AddressCompaction{}, false, err
+		}
+		txHeights = append(txHeights, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return AddressCompaction{}, false, err
+	}
+	cutoff, ok := addressCompactionCutoff(txHeights, keep)
+	if !ok {
+		return AddressCompaction{}, false, nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return AddressCompaction{}, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Mark first so concurrent derivers stop inserting below the cut.
+	if _, err := tx.Exec(ctx, `
+		UPDATE app.address_stats
+		SET compacted_below_height = GREATEST(COALESCE(compacted_below_height, 0), $2),
+		    compacted_at = NOW()
+		WHERE address = $1`, addr, int64(cutoff)+1); err != nil {
+		return AddressCompaction{}, false, fmt.Errorf("mark compacted %s: %w", bytesToHex(addr), err)
+	}
+	cmd, err := tx.Exec(ctx, `
+		DELETE FROM app.address_transactions
+		WHERE address = $1 AND block_height <= $2`, addr, int64(cutoff))
+	if err != nil {
+		return AddressCompaction{}, false, fmt.Errorf("compact %s: %w", bytesToHex(addr), err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return AddressCompaction{}, false, err
+	}
+	return AddressCompaction{
+		Address:              bytesToHex(addr),
+		DeletedRows:          cmd.RowsAffected(),
+		CompactedBelowHeight: cutoff + 1,
+	}, true, nil
+}
+
+// GetAddressCompaction returns the height below which an address's activity
+// was compacted away, or 0 when its list is complete.
+func (r *Repository) GetAddressCompaction(ctx context.Context, address string) (uint64, error) {
+	var below *int64
+	err := r.db.QueryRow(ctx, `
+		SELECT compacted_below_height FROM app.address_stats WHERE address = $1`,
+		hexToBytes(address)).Scan(&below)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && below == nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return uint64(*below), nil
+}
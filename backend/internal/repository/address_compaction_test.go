@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"sort"
+	"testing"
+
+	"flowscan-clone/internal/models"
+)
+
+func TestAddressCompactionKeepsNewest(t *testing.T) {
+	t.Parallel()
+
+	// Eight transactions at heights 101..108, each with two role rows.
+	var rows []models.AddressTransaction
+	for h := uint64(101); h <= 108; h++ {
+		id := string(rune('a' + h - 101))
+		rows = append(rows,
+			models.AddressTransaction{TransactionID: id, BlockHeight: h, Role: "PAYER"},
+			models.AddressTransaction{TransactionID: id, BlockHeight: h, Role: "AUTHORIZER"},
+		)
+	}
+
+	// Distinct transactions newest first, as compactAddress reads them.
+	seen := map[string]bool{}
+	var txHeights []uint64
+	sort.Slice(rows, func(i, j int) bool { return rows[i].BlockHeight > rows[j].BlockHeight })
+	for _, r := range rows {
+		if !seen[r.TransactionID] {
+			seen[r.TransactionID] = true
+			txHeights = append(txHeights, r.BlockHeight)
+		}
+	}
+
+	const keep = 3
+	cutoff, ok := addressCompactionCutoff(txHeights, keep)
+	if !ok || cutoff != 105 {
+		t.Fatalf("cutoff = %d, %v; want 105, true", cutoff, ok)
+	}
+	kept := map[string]bool{}
+	for _, r := range rows {
+		if r.BlockHeight > cutoff {
+			kept[r.TransactionID] = true
+		}
+	}
+	if len(kept) != keep || !kept["h"] || !kept["g"] || !kept["f"] {
+		t.Fatalf("kept transactions = %v, want the newest %d (f, g, h)", kept, keep)
+	}
+
+	// Nothing to compact at or under the cap.
+	if _, ok := addressCompactionCutoff(txHeights[:keep], keep); ok {
+		t.Error("address with exactly keep transactions should not be compacted")
+	}
+	if _, ok := addressCompactionCutoff(txHeights, 0); ok {
+		t.Error("keep 0 disables compaction")
+	}
+}
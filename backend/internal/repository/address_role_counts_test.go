@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"flowscan-clone/internal/models"
+)
+
+// TestUpsertAddressTransactionsRoleCounts needs a database with the schema
+// applied. Role counters are bumped in one UPSERT for newly inserted rows
+// only, so replaying a batch leaves them unchanged.
+func TestUpsertAddressTransactionsRoleCounts(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	repo, err := NewRepository(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	ctx := context.Background()
+
+	const a, b = "00000000a11ce001", "00000000b0b00002"
+	addrs := [][]byte{hexToBytes(a), hexToBytes(b)}
+	cleanup := func() {
+		repo.db.Exec(ctx, `DELETE FROM app.address_transactions WHERE address = ANY($1)`, addrs)
+		repo.db.Exec(ctx, `DELETE FROM app.address_stats WHERE address = ANY($1)`, addrs)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	tx1, tx2 := "00000000000000000000000000000000000000000000000000000000000000f1", "00000000000000000000000000000000000000000000000000000000000000f2"
+	first := []models.AddressTransaction{
+		{Address: a, TransactionID: tx1, BlockHeight: 10, Role: "PAYER"},
+		{Address: a, TransactionID: tx1, BlockHeight: 10, Role: "AUTHORIZER"},
+		{Address: b, TransactionID: tx1, BlockHeight: 10, Role: "FT_RECEIVER"},
+	}
+	if err := repo.UpsertAddressTransactions(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	// A replay of the first batch plus one new row.
+	second := append(append([]models.AddressTransaction(nil), first...),
+		models.AddressTransaction{Address: a, TransactionID: tx2, BlockHeight: 11, Role: "FT_SENDER"})
+	if err := repo.UpsertAddressTransactions(ctx, second); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]AddressRoleCounts{
+		a: {Address: a, Payer: 1, Authorizer: 1, TransferSent: 1},
+		b: {Address: b, TransferReceived: 1},
+	}
+	for addr, w := range want {
+		got := AddressRoleCounts{Address: addr}
+		err := repo.db.QueryRow(ctx, `
+			SELECT payer_count, proposer_count, authorizer_count, transfer_sent_count, transfer_received_count
+			FROM app.address_stats WHERE address = $1`, hexToBytes(addr)).
+			Scan(&got.Payer, &got.Proposer, &got.Authorizer, &got.TransferSent, &got.TransferReceived)
+		if err != nil {
+			t.Fatalf("%s: %v", addr, err)
+		}
+		if got != w {
+			t.Errorf("%s counts = %+v; want %+v", addr, got, w)
+		}
+	}
+}
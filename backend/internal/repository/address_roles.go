@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"flowscan-clone/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AddressRoleCounts is a per-address delta for the role counters in app.address_stats.
+type AddressRoleCounts struct {
+	Address          string
+	Payer            int64
+	Proposer         int64
+	Authorizer       int64
+	TransferSent     int64
+	TransferReceived int64
+}
+
+// add counts one app.address_transactions row. Unknown roles are ignored.
+func (c *AddressRoleCounts) add(role string) bool {
+	switch role {
+	case "PAYER":
+		c.Payer++
+	case "PROPOSER":
+		c.Proposer++
+	case "AUTHORIZER":
+		c.Authorizer++
+	case "FT_SENDER", "NFT_SENDER":
+		c.TransferSent++
+	case "FT_RECEIVER", "NFT_RECEIVER":
+		c.TransferReceived++
+	default:
+		return false
+	}
+	return true
+}
+
+// addressTxRoleFilters maps the ?role= values accepted by the account
+// transactions endpoints to the app.address_transactions roles they match.
+var addressTxRoleFilters = map[string][]string{
+	"payer":      {"PAYER"},
+	"proposer":   {"PROPOSER"},
+	"authorizer": {"AUTHORIZER"},
+	"sender":     {"FT_SENDER", "NFT_SENDER"},
+	"receiver":   {"FT_RECEIVER", "NFT_RECEIVER"},
+}
+
+// addressTxRoles resolves a role filter to stored roles. An empty filter
+// matches every role and yields nil, which the queries bind as NULL.
+func addressTxRoles(role string) ([]string, error) {
+	if role == "" {
+		return nil, nil
+	}
+	roles, ok := addressTxRoleFilters[role]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (want payer, proposer, authorizer, sender or receiver)", ErrInvalidAddressTxRole, role)
+	}
+	return roles, nil
+}
+
+// tallyAddressRoles aggregates inserted address_transactions rows into
+// per-address role deltas, ordered by address.
+func tallyAddressRoles(rows []models.AddressTransaction) []AddressRoleCounts {
+	byAddr := make(map[string]*AddressRoleCounts)
+	for _, at := range rows {
+		c := byAddr[at.Address]
+		if c == nil {
+			c = &AddressRoleCounts{Address: at.Address}
+		}
+		if c.add(at.Role) {
+			byAddr[at.Address] = c
+		}
+	}
+
+	out := make([]AddressRoleCounts, 0, len(byAddr))
+	for _, c := range byAddr {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Address < out[j].Address })
+	return out
+}
+
+// addAddressRoleCounts adds role deltas to app.address_stats inside tx in a
+// single UNNEST-based UPSERT. deltas must hold each address once, as
+// tallyAddressRoles returns them. Callers must only pass deltas for rows that
+// were actually inserted so that reprocessing a range never double counts.
+func addAddressRoleCounts(ctx context.Context, tx pgx.Tx, deltas []AddressRoleCounts) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	addrs := make([][]byte, len(deltas))
+	payer := make([]int64, len(deltas))
+	proposer := make([]int64, len(deltas))
+	authorizer := make([]int64, len(deltas))
+	sent := make([]int64, len(deltas))
+	received := make([]int64, len(deltas))
+	for i, d := range deltas {
+		addrs[i] = hexToBytes(d.Address)
+		payer[i] = d.Payer
+		proposer[i] = d.Proposer
+		authorizer[i] = d.Authorizer
+		sent[i] = d.TransferSent
+		received[i] = d.TransferReceived
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO app.address_stats (address, payer_count, proposer_count, authorizer_count,
+			transfer_sent_count, transfer_received_count, created_at, updated_at)
+		SELECT u.address, u.payer, u.proposer, u.authorizer, u.sent, u.received, NOW(), NOW()
+		FROM UNNEST($1::bytea[], $2::bigint[], $3::bigint[], $4::bigint[], $5::bigint[], $6::bigint[])
+			AS u(address, payer, proposer, authorizer, sent, received)
+		ORDER BY u.address
+		ON CONFLICT (address) DO UPDATE SET
+			payer_count = app.address_stats.payer_count + EXCLUDED.payer_count,
+			proposer_count = app.address_stats.proposer_count + EXCLUDED.proposer_count,
+			authorizer_count = app.address_stats.authorizer_count + EXCLUDED.authorizer_count,
+			transfer_sent_count = app.address_stats.transfer_sent_count + EXCLUDED.transfer_sent_count,
+			transfer_received_count = app.address_stats.transfer_received_count + EXCLUDED.transfer_received_count,
+			updated_at = NOW()`,
+		addrs, payer, proposer, authorizer, sent, received,
+	)
+	if err != nil {
+		return fmt.Errorf("update address role counts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"flowscan-clone/internal/models"
+)
+
+func TestTallyAddressRoles(t *testing.T) {
+	t.Parallel()
+
+	rows := []models.AddressTransaction{
+		{Address: "bb", TransactionID: "t1", Role: "PAYER"},
+		{Address: "bb", TransactionID: "t1", Role: "PROPOSER"},
+		{Address: "bb", TransactionID: "t1", Role: "AUTHORIZER"},
+		{Address: "aa", TransactionID: "t1", Role: "AUTHORIZER"},
+		{Address: "aa", TransactionID: "t2", Role: "FT_SENDER"},
+		{Address: "aa", TransactionID: "t3", Role: "NFT_SENDER"},
+		{Address: "bb", TransactionID: "t2", Role: "FT_RECEIVER"},
+		{Address: "bb", TransactionID: "t3", Role: "NFT_RECEIVER"},
+		{Address: "cc", TransactionID: "t4", Role: "UNKNOWN"},
+	}
+
+	got := tallyAddressRoles(rows)
+	want := []AddressRoleCounts{
+		{Address: "aa", Authorizer: 1, TransferSent: 2},
+		{Address: "bb", Payer: 1, Proposer: 1, Authorizer: 1, TransferReceived: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tallyAddressRoles() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTallyAddressRolesEmpty(t *testing.T) {
+	t.Parallel()
+
+	if got := tallyAddressRoles(nil); len(got) != 0 {
+		t.Fatalf("expected no deltas, got %+v", got)
+	}
+}
+
+func TestAddressTxRoles(t *testing.T) {
+	t.Parallel()
+
+	// Each stored role must be matched by exactly the filter whose counter
+	// AddressRoleCounts.add bumps for it, so list totals and filters agree.
+	stored := []string{"PAYER", "PROPOSER", "AUTHORIZER", "FT_SENDER", "NFT_SENDER", "FT_RECEIVER", "NFT_RECEIVER"}
+	counter := map[string]func(AddressRoleCounts) int64{
+		"payer":      func(c AddressRoleCounts) int64 { return c.Payer },
+		"proposer":   func(c AddressRoleCounts) int64 { return c.Proposer },
+		"authorizer": func(c AddressRoleCounts) int64 { return c.Authorizer },
+		"sender":     func(c AddressRoleCounts) int64 { return c.TransferSent },
+		"receiver":   func(c AddressRoleCounts) int64 { return c.TransferReceived },
+	}
+	for filter, count := range counter {
+		roles, err := addressTxRoles(filter)
+		if err != nil {
+			t.Fatalf("%s: %v", filter, err)
+		}
+		matched := make(map[string]bool, len(roles))
+		for _, r := range roles {
+			matched[r] = true
+		}
+		for _, role := range stored {
+			var c AddressRoleCounts
+			c.add(role)
+			if want := count(c) == 1; matched[role] != want {
+				t.Fatalf("role=%s matches %s = %v, want %v", filter, role, matched[role], want)
+			}
+		}
+	}
+
+	if roles, err := addressTxRoles(""); err != nil || roles != nil {
+		t.Fatalf("empty filter = %v, %v; want nil, nil", roles, err)
+	}
+	if _, err := addressTxRoles("PAYER' OR '1'='1"); !errors.Is(err, ErrInvalidAddressTxRole) {
+		t.Fatalf("expected ErrInvalidAddressTxRole, got %v", err)
+	}
+}
+
+func TestGetTransactionsByAddressRejectsUnknownRole(t *testing.T) {
+	t.Parallel()
+
+	// Validation happens before any query, so no database is needed.
+	if _, err := (&Repository{}).GetTransactionsByAddress(context.Background(), "01", "minter", 10, 0); !errors.Is(err, ErrInvalidAddressTxRole) {
+		t.Fatalf("expected ErrInvalidAddressTxRole, got %v", err)
+	}
+	if _, err := (&Repository{}).GetTransactionsByAddressCursor(context.Background(), "01", "minter", 10, nil); !errors.Is(err, ErrInvalidAddressTxRole) {
+		t.Fatalf("cursor: expected ErrInvalidAddressTxRole, got %v", err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"flowscan-clone/internal/models"
+
+	"github.com/jackc/pgx/v5"
 )
 
 // AdminListFTTokens returns all FT tokens with optional search.
@@ -202,16 +204,61 @@ func (r *Repository) AdminListAccountLabels(ctx context.Context, search string,
 	return result, rows.Err()
 }
 
+const upsertAccountLabelSQL = `INSERT INTO app.account_labels (address, tag, label, category)
+	 VALUES ($1, $2, $3, $4)
+	 ON CONFLICT (address, tag) DO UPDATE SET label = $3, category = $4`
+
 // AdminUpsertAccountLabel inserts or updates an account label.
 func (r *Repository) AdminUpsertAccountLabel(ctx context.Context, label models.AccountLabel) error {
-	_, err := r.db.Exec(ctx,
-		`INSERT INTO app.account_labels (address, tag, label, category)
-		 VALUES ($1, $2, $3, $4)
-		 ON CONFLICT (address, tag) DO UPDATE SET label = $3, category = $4`,
-		label.Address, label.Tag, label.Label, label.Category)
+	_, err := r.db.Exec(ctx, upsertAccountLabelSQL, label.Address, label.Tag, label.Label, label.Category)
 	return err
 }
 
+// ImportAccountLabels upserts labels in a single transaction; either all rows
+// are written or none are.
+func (r *Repository) ImportAccountLabels(ctx context.Context, labels []models.AccountLabel) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for _, l := range labels {
+		batch.Queue(upsertAccountLabelSQL, l.Address, l.Tag, l.Label, l.Category)
+	}
+	return r.sendBatchInTx(ctx, batch, "import account labels")
+}
+
+// ImportTokenMetadata upserts FT tokens and NFT collections in a single
+// transaction using the same conflict rules as UpsertFTTokens/UpsertNFTCollections.
+func (r *Repository) ImportTokenMetadata(ctx context.Context, tokens []models.FTToken, collections []models.NFTCollection) error {
+	if len(tokens) == 0 && len(collections) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for _, t := range tokens {
+		queueFTTokenUpsert(batch, t)
+	}
+	for _, c := range collections {
+		queueNFTCollectionUpsert(batch, c)
+	}
+	return r.sendBatchInTx(ctx, batch, "import token metadata")
+}
+
+func (r *Repository) sendBatchInTx(ctx context.Context, batch *pgx.Batch, what string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	return nil
+}
+
 // AdminDeleteAccountLabel removes a label from an account.
 func (r *Repository) AdminDeleteAccountLabel(ctx context.Context, address, tag string) error {
 	_, err := r.db.Exec(ctx,
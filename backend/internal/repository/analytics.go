@@ -50,7 +50,7 @@ func (r *Repository) GetAnalyticsDailyAccountsModule(ctx context.Context, from,
 		FROM dates d
 		LEFT JOIN analytics.daily_metrics m ON m.date = d.date
 		ORDER BY d.date ASC`
-	rows, err := r.db.Query(ctx, query, from.UTC(), to.UTC())
+	rows, err := r.readDB().Query(ctx, query, from.UTC(), to.UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +76,7 @@ func (r *Repository) GetAnalyticsDailyEVMModule(ctx context.Context, from, to ti
 		FROM dates d
 		LEFT JOIN analytics.daily_metrics m ON m.date = d.date
 		ORDER BY d.date ASC`
-	rows, err := r.db.Query(ctx, query, from.UTC(), to.UTC())
+	rows, err := r.readDB().Query(ctx, query, from.UTC(), to.UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -104,7 +104,7 @@ func (r *Repository) GetAnalyticsDailyDefiModule(ctx context.Context, from, to t
 		FROM dates d
 		LEFT JOIN analytics.daily_metrics m ON m.date = d.date
 		ORDER BY d.date ASC`
-	rows, err := r.db.Query(ctx, query, from.UTC(), to.UTC())
+	rows, err := r.readDB().Query(ctx, query, from.UTC(), to.UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +135,7 @@ func (r *Repository) GetAnalyticsDailyEpochModule(ctx context.Context, from, to
 		    AND e.payout_time > '0001-01-01'
 		    AND e.payout_total > 0
 		ORDER BY d.date ASC`
-	rows, err := r.db.Query(ctx, query, from.UTC(), to.UTC())
+	rows, err := r.readDB().Query(ctx, query, from.UTC(), to.UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +161,7 @@ func (r *Repository) GetAnalyticsDailyBridgeModule(ctx context.Context, from, to
 		FROM dates d
 		LEFT JOIN analytics.daily_metrics m ON m.date = d.date
 		ORDER BY d.date ASC`
-	rows, err := r.db.Query(ctx, query, from.UTC(), to.UTC())
+	rows, err := r.readDB().Query(ctx, query, from.UTC(), to.UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -187,7 +187,7 @@ func (r *Repository) GetAnalyticsDailyContractsModule(ctx context.Context, from,
 		FROM dates d
 		LEFT JOIN analytics.daily_metrics m ON m.date = d.date
 		ORDER BY d.date ASC`
-	rows, err := r.db.Query(ctx, query, from.UTC(), to.UTC())
+	rows, err := r.readDB().Query(ctx, query, from.UTC(), to.UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -228,7 +228,7 @@ func (r *Repository) GetAnalyticsDailyBaseStats(ctx context.Context, from, to ti
 		LEFT JOIN app.daily_stats s ON s.date = d.date
 		ORDER BY d.date ASC`
 
-	rows, err := r.db.Query(ctx, query, from.UTC(), to.UTC())
+	rows, err := r.readDB().Query(ctx, query, from.UTC(), to.UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -259,7 +259,7 @@ func (r *Repository) GetAnalyticsDailyBaseStats(ctx context.Context, from, to ti
 	overlayFrom := maxDate(from.UTC(), to.UTC().AddDate(0, 0, -14))
 	if !overlayFrom.After(to.UTC()) {
 		heightLo, heightHi := r.estimateRecentHeightBounds(ctx, overlayFrom, to.UTC())
-		rows2, err := r.db.Query(ctx, `
+		rows2, err := r.readDB().Query(ctx, `
 			SELECT
 				DATE(t.timestamp)::text AS date,
 				COUNT(*)::bigint AS tx_count,
@@ -345,7 +345,7 @@ func (r *Repository) GetTransferDailyStats(ctx context.Context, from, to time.Ti
 		LEFT JOIN app.daily_stats s ON s.date = d.date
 		ORDER BY d.date ASC`
 
-	rows, err := r.db.Query(ctx, query, from.UTC(), to.UTC())
+	rows, err := r.readDB().Query(ctx, query, from.UTC(), to.UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -372,7 +372,7 @@ func (r *Repository) GetTransferDailyStats(ctx context.Context, from, to time.Ti
 	overlayFrom := maxDate(from.UTC(), to.UTC().AddDate(0, 0, -14))
 	if !overlayFrom.After(to.UTC()) {
 		heightLo, heightHi := r.estimateRecentHeightBounds(ctx, overlayFrom, to.UTC())
-		rows2, err := r.db.Query(ctx, `
+		rows2, err := r.readDB().Query(ctx, `
 			WITH recent AS (
 				SELECT 'ft'::text AS kind, DATE(ft.timestamp)::text AS date, COUNT(*)::bigint AS cnt
 				FROM app.ft_transfers ft
@@ -431,7 +431,7 @@ func (r *Repository) GetTransferDailyStats(ctx context.Context, from, to time.Ti
 
 func (r *Repository) estimateRecentHeightBounds(ctx context.Context, from, to time.Time) (int64, int64) {
 	var latestHeight int64
-	_ = r.db.QueryRow(ctx, `SELECT COALESCE(last_height, 0) FROM app.indexing_checkpoints WHERE service_name = 'main_ingester'`).Scan(&latestHeight)
+	_ = r.readDB().QueryRow(ctx, `SELECT COALESCE(last_height, 0) FROM app.indexing_checkpoints WHERE service_name = 'main_ingester'`).Scan(&latestHeight)
 	if latestHeight < 0 {
 		latestHeight = 0
 	}
@@ -515,10 +515,10 @@ func (r *Repository) GetBigTransfers(ctx context.Context, priceMap map[string]fl
 	// timestamp index and causes full-table scans).
 	// ~720k blocks per 7 days (1 block per ~0.84s).
 	var latestHeight int64
-	_ = r.db.QueryRow(ctx, `SELECT COALESCE(last_height, 0) FROM app.indexing_checkpoints WHERE service_name = 'main_ingester'`).Scan(&latestHeight)
+	_ = r.readDB().QueryRow(ctx, `SELECT COALESCE(last_height, 0) FROM app.indexing_checkpoints WHERE service_name = 'main_ingester'`).Scan(&latestHeight)
 	if latestHeight == 0 {
 		// Fallback: quick max from the latest partition only
-		_ = r.db.QueryRow(ctx, `SELECT COALESCE(MAX(block_height), 0) FROM app.ft_transfers WHERE block_height > 140000000`).Scan(&latestHeight)
+		_ = r.readDB().QueryRow(ctx, `SELECT COALESCE(MAX(block_height), 0) FROM app.ft_transfers WHERE block_height > 140000000`).Scan(&latestHeight)
 	}
 	heightLo := latestHeight - 750000 // ~7 days with margin
 	if heightLo < 0 {
@@ -616,7 +616,7 @@ LIMIT $%d OFFSET $%d`,
 		offsetIdx,
 	)
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := r.readDB().Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -658,7 +658,7 @@ func (r *Repository) GetTopContracts(ctx context.Context, hours int, limit int)
 	}
 	// Compute height bounds in Go to avoid scanning raw.blocks/tx_lookup by timestamp.
 	var latestHeight int64
-	_ = r.db.QueryRow(ctx, `SELECT COALESCE(last_height, 0) FROM app.indexing_checkpoints WHERE service_name = 'main_ingester'`).Scan(&latestHeight)
+	_ = r.readDB().QueryRow(ctx, `SELECT COALESCE(last_height, 0) FROM app.indexing_checkpoints WHERE service_name = 'main_ingester'`).Scan(&latestHeight)
 	heightLo := latestHeight - int64(hours)*100000/24
 	if heightLo < 0 {
 		heightLo = 0
@@ -693,7 +693,7 @@ func (r *Repository) GetTopContracts(ctx context.Context, hours int, limit int)
 			  AND tc2.block_height >= $1 AND tc2.block_height < $2
 		) callers ON true
 		ORDER BY top.tx_count DESC`
-	rows, err := r.db.Query(ctx, query, heightLo, latestHeight+1, limit)
+	rows, err := r.readDB().Query(ctx, query, heightLo, latestHeight+1, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -743,7 +743,7 @@ func (r *Repository) GetTokenVolume(ctx context.Context, hours int, limit int, p
 
 	// Compute height bounds in Go to enable partition pruning.
 	var latestHeight int64
-	_ = r.db.QueryRow(ctx, `SELECT COALESCE(last_height, 0) FROM app.indexing_checkpoints WHERE service_name = 'main_ingester'`).Scan(&latestHeight)
+	_ = r.readDB().QueryRow(ctx, `SELECT COALESCE(last_height, 0) FROM app.indexing_checkpoints WHERE service_name = 'main_ingester'`).Scan(&latestHeight)
 	heightLo := latestHeight - int64(hours)*100000/24 // ~100k blocks/day
 	if heightLo < 0 {
 		heightLo = 0
@@ -774,7 +774,7 @@ func (r *Repository) GetTokenVolume(ctx context.Context, hours int, limit int, p
 		LIMIT $%d`,
 		pricesCTE, heightLoIdx, heightHiIdx, hoursIdx, limitIdx)
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := r.readDB().Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -159,33 +159,41 @@ func (r *Repository) ListAccounts(ctx context.Context, limit, offset int) ([]mod
 
 // --- FT metadata/holdings ---
 
+// queueFTTokenUpsert queues the app.ft_tokens upsert shared by UpsertFTTokens and
+// ImportTokenMetadata. Existing non-empty metadata is kept. The key is
+// normalized first so "A.addr.Name" and bare-address forms land on one row.
+func queueFTTokenUpsert(batch *pgx.Batch, t models.FTToken) {
+	address, contractName := normalizeFTTokenKey(t.ContractAddress, t.ContractName)
+	batch.Queue(`
+		INSERT INTO app.ft_tokens (contract_address, contract_name, name, symbol, decimals,
+			description, external_url, logo, vault_path, receiver_path, balance_path, socials, evm_address, total_supply, updated_at)
+		VALUES ($1, $2, NULLIF($3,''), NULLIF($4,''), NULLIF($5,0),
+			NULLIF($6,''), NULLIF($7,''), $8, NULLIF($9,''), NULLIF($10,''), NULLIF($11,''), $12, NULLIF($13,''), NULLIF($14,'')::numeric, NOW())
+		ON CONFLICT (contract_address, contract_name) DO UPDATE SET
+			name = COALESCE(app.ft_tokens.name, EXCLUDED.name),
+			symbol = COALESCE(app.ft_tokens.symbol, EXCLUDED.symbol),
+			decimals = COALESCE(app.ft_tokens.decimals, EXCLUDED.decimals),
+			description = COALESCE(app.ft_tokens.description, EXCLUDED.description),
+			external_url = COALESCE(app.ft_tokens.external_url, EXCLUDED.external_url),
+			logo = COALESCE(app.ft_tokens.logo, EXCLUDED.logo),
+			vault_path = COALESCE(app.ft_tokens.vault_path, EXCLUDED.vault_path),
+			receiver_path = COALESCE(app.ft_tokens.receiver_path, EXCLUDED.receiver_path),
+			balance_path = COALESCE(app.ft_tokens.balance_path, EXCLUDED.balance_path),
+			socials = COALESCE(app.ft_tokens.socials, EXCLUDED.socials),
+			evm_address = COALESCE(app.ft_tokens.evm_address, EXCLUDED.evm_address),
+			total_supply = COALESCE(EXCLUDED.total_supply, app.ft_tokens.total_supply),
+			updated_at = NOW()`,
+		hexToBytes(address), contractName, t.Name, t.Symbol, t.Decimals,
+		t.Description, t.ExternalURL, nullIfEmpty(t.Logo), t.VaultPath, t.ReceiverPath, t.BalancePath, nullIfEmptyJSON(t.Socials), t.EVMAddress, t.TotalSupply)
+}
+
 func (r *Repository) UpsertFTTokens(ctx context.Context, tokens []models.FTToken) error {
 	if len(tokens) == 0 {
 		return nil
 	}
 	batch := &pgx.Batch{}
 	for _, t := range tokens {
-		batch.Queue(`
-			INSERT INTO app.ft_tokens (contract_address, contract_name, name, symbol, decimals,
-				description, external_url, logo, vault_path, receiver_path, balance_path, socials, evm_address, total_supply, updated_at)
-			VALUES ($1, $2, NULLIF($3,''), NULLIF($4,''), NULLIF($5,0),
-				NULLIF($6,''), NULLIF($7,''), $8, NULLIF($9,''), NULLIF($10,''), NULLIF($11,''), $12, NULLIF($13,''), NULLIF($14,'')::numeric, NOW())
-			ON CONFLICT (contract_address, contract_name) DO UPDATE SET
-				name = COALESCE(app.ft_tokens.name, EXCLUDED.name),
-				symbol = COALESCE(app.ft_tokens.symbol, EXCLUDED.symbol),
-				decimals = COALESCE(app.ft_tokens.decimals, EXCLUDED.decimals),
-				description = COALESCE(app.ft_tokens.description, EXCLUDED.description),
-				external_url = COALESCE(app.ft_tokens.external_url, EXCLUDED.external_url),
-				logo = COALESCE(app.ft_tokens.logo, EXCLUDED.logo),
-				vault_path = COALESCE(app.ft_tokens.vault_path, EXCLUDED.vault_path),
-				receiver_path = COALESCE(app.ft_tokens.receiver_path, EXCLUDED.receiver_path),
-				balance_path = COALESCE(app.ft_tokens.balance_path, EXCLUDED.balance_path),
-				socials = COALESCE(app.ft_tokens.socials, EXCLUDED.socials),
-				evm_address = COALESCE(app.ft_tokens.evm_address, EXCLUDED.evm_address),
-				total_supply = COALESCE(EXCLUDED.total_supply, app.ft_tokens.total_supply),
-				updated_at = NOW()`,
-			hexToBytes(t.ContractAddress), t.ContractName, t.Name, t.Symbol, t.Decimals,
-			t.Description, t.ExternalURL, nullIfEmpty(t.Logo), t.VaultPath, t.ReceiverPath, t.BalancePath, nullIfEmptyJSON(t.Socials), t.EVMAddress, t.TotalSupply)
+		queueFTTokenUpsert(batch, t)
 	}
 	br := r.db.SendBatch(ctx, batch)
 	defer br.Close()
@@ -430,18 +438,50 @@ func (r *Repository) ListFTHoldingsByToken(ctx context.Context, contract, contra
 	return out, nil
 }
 
-func (r *Repository) ListFTTokens(ctx context.Context, limit, offset int, filters ...string) ([]models.FTToken, error) {
-	filter := ""
-	if len(filters) > 0 {
-		filter = filters[0]
-	}
-	where := ""
-	switch filter {
+// FTTokenListFilter narrows ListFTTokens and CountFTTokens. Preset is the
+// legacy ?filter= value ("has_price" or "evm_bridged"); Query matches a
+// symbol or name prefix, case-insensitively.
+type FTTokenListFilter struct {
+	Preset   string
+	Verified bool
+	Bridged  bool
+	Query    string
+}
+
+// likePrefixEscaper escapes LIKE wildcards so user input only matches literally.
+var likePrefixEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// where returns the WHERE clause for app.ft_tokens aliased as ft, with
+// placeholders numbered from firstArg, and its arguments. The prefix match
+// uses lower(...) so it can be served by the text_pattern_ops indexes.
+func (f FTTokenListFilter) where(firstArg int) (string, []any) {
+	var clauses []string
+	var args []any
+	switch f.Preset {
 	case "has_price":
-		where = "WHERE COALESCE(ft.market_symbol, '') != ''"
+		clauses = append(clauses, "COALESCE(ft.market_symbol, '') != ''")
 	case "evm_bridged":
-		where = "WHERE COALESCE(ft.evm_address, '') != ''"
+		f.Bridged = true
 	}
+	if f.Verified {
+		clauses = append(clauses, "ft.is_verified")
+	}
+	if f.Bridged {
+		clauses = append(clauses, "COALESCE(ft.evm_address, '') != ''")
+	}
+	if q := strings.TrimSpace(f.Query); q != "" {
+		n := firstArg + len(args)
+		clauses = append(clauses, fmt.Sprintf("(lower(ft.symbol) LIKE $%d OR lower(ft.name) LIKE $%d)", n, n))
+		args = append(args, likePrefixEscaper.Replace(strings.ToLower(q))+"%")
+	}
+	if len(clauses) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(clauses, " AND "), args
+}
+
+func (r *Repository) ListFTTokens(ctx context.Context, limit, offset int, filter FTTokenListFilter) ([]models.FTToken, error) {
+	where, args := filter.where(3)
 	rows, err := r.db.Query(ctx, `
 		SELECT `+ftTokenSelectCols+`, COALESCE(h.holder_count, 0), b.timestamp
 		FROM app.ft_tokens ft
@@ -459,7 +499,7 @@ func (r *Repository) ListFTTokens(ctx context.Context, limit, offset int, filter
 		`+where+`
 		ORDER BY CASE WHEN COALESCE(ft.market_symbol, '') != '' THEN 0 ELSE 1 END,
 		         COALESCE(h.holder_count, 0) DESC, ft.contract_address ASC
-		LIMIT $1 OFFSET $2`, limit, offset)
+		LIMIT $1 OFFSET $2`, append([]any{limit, offset}, args...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -616,27 +656,33 @@ func (r *Repository) GetFTToken(ctx context.Context, contract, contractName stri
 
 // --- NFT collections/ownership ---
 
+// queueNFTCollectionUpsert queues the app.nft_collections upsert shared by
+// UpsertNFTCollections and ImportTokenMetadata. Existing non-empty metadata is kept.
+func queueNFTCollectionUpsert(batch *pgx.Batch, c models.NFTCollection) {
+	batch.Queue(`
+		INSERT INTO app.nft_collections (contract_address, contract_name, name, symbol, description, external_url, square_image, banner_image, socials, evm_address, updated_at)
+		VALUES ($1, $2, NULLIF($3,''), NULLIF($4,''), NULLIF($5,''), NULLIF($6,''), NULLIF($7,''), NULLIF($8,''), $9, NULLIF($10,''), NOW())
+		ON CONFLICT (contract_address, contract_name) DO UPDATE SET
+			name = COALESCE(app.nft_collections.name, EXCLUDED.name),
+			symbol = COALESCE(app.nft_collections.symbol, EXCLUDED.symbol),
+			description = COALESCE(app.nft_collections.description, EXCLUDED.description),
+			external_url = COALESCE(app.nft_collections.external_url, EXCLUDED.external_url),
+			square_image = COALESCE(app.nft_collections.square_image, EXCLUDED.square_image),
+			banner_image = COALESCE(app.nft_collections.banner_image, EXCLUDED.banner_image),
+			socials = COALESCE(app.nft_collections.socials, EXCLUDED.socials),
+			evm_address = COALESCE(app.nft_collections.evm_address, EXCLUDED.evm_address),
+			updated_at = NOW()`,
+		hexToBytes(c.ContractAddress), c.ContractName, c.Name, c.Symbol, c.Description, c.ExternalURL,
+		c.SquareImage, c.BannerImage, nullIfEmptyJSON(c.Socials), c.EVMAddress)
+}
+
 func (r *Repository) UpsertNFTCollections(ctx context.Context, collections []models.NFTCollection) error {
 	if len(collections) == 0 {
 		return nil
 	}
 	batch := &pgx.Batch{}
 	for _, c := range collections {
-		batch.Queue(`
-			INSERT INTO app.nft_collections (contract_address, contract_name, name, symbol, description, external_url, square_image, banner_image, socials, evm_address, updated_at)
-			VALUES ($1, $2, NULLIF($3,''), NULLIF($4,''), NULLIF($5,''), NULLIF($6,''), NULLIF($7,''), NULLIF($8,''), $9, NULLIF($10,''), NOW())
-			ON CONFLICT (contract_address, contract_name) DO UPDATE SET
-				name = COALESCE(app.nft_collections.name, EXCLUDED.name),
-				symbol = COALESCE(app.nft_collections.symbol, EXCLUDED.symbol),
-				description = COALESCE(app.nft_collections.description, EXCLUDED.description),
-				external_url = COALESCE(app.nft_collections.external_url, EXCLUDED.external_url),
-				square_image = COALESCE(app.nft_collections.square_image, EXCLUDED.square_image),
-				banner_image = COALESCE(app.nft_collections.banner_image, EXCLUDED.banner_image),
-				socials = COALESCE(app.nft_collections.socials, EXCLUDED.socials),
-				evm_address = COALESCE(app.nft_collections.evm_address, EXCLUDED.evm_address),
-				updated_at = NOW()`,
-			hexToBytes(c.ContractAddress), c.ContractName, c.Name, c.Symbol, c.Description, c.ExternalURL,
-			c.SquareImage, c.BannerImage, nullIfEmptyJSON(c.Socials), c.EVMAddress)
+		queueNFTCollectionUpsert(batch, c)
 	}
 	br := r.db.SendBatch(ctx, batch)
 	defer br.Close()
@@ -898,6 +944,35 @@ func (r *Repository) ListContracts(ctx context.Context, address string, limit, o
 	})
 }
 
+// ListContractsByDeployer returns the contracts deployed on address itself,
+// as opposed to contracts it imports, ordered by name. Code is omitted; each
+// row carries the current version and the height/time it was last updated.
+func (r *Repository) ListContractsByDeployer(ctx context.Context, address string) ([]models.SmartContract, error) {
+	rows, err := r.readQuery(ctx, "contracts_by_deployer", `
+		SELECT encode(sc.address, 'hex'), sc.name, COALESCE(sc.version, 1), COALESCE(sc.kind, ''),
+		       COALESCE(sc.first_seen_height, 0), COALESCE(sc.last_updated_height, 0),
+		       COALESCE(sc.is_verified, false), COALESCE(sc.dependent_count, 0),
+		       sc.created_at, COALESCE(bl.timestamp, sc.updated_at)
+		FROM app.smart_contracts sc
+		LEFT JOIN raw.block_lookup bl ON bl.height = sc.last_updated_height
+		WHERE sc.address = $1
+		ORDER BY sc.name ASC`, hexToBytes(address))
+	if err != nil {
+		return nil, wrapDBErr(err, "list contracts by deployer")
+	}
+	defer rows.Close()
+	var out []models.SmartContract
+	for rows.Next() {
+		var c models.SmartContract
+		if err := rows.Scan(&c.Address, &c.Name, &c.Version, &c.Kind, &c.FirstSeenHeight, &c.BlockHeight,
+			&c.IsVerified, &c.DependentCount, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
 func (r *Repository) GetContractByIdentifier(ctx context.Context, identifier string) ([]models.SmartContract, error) {
 	identifier = strings.ToLower(strings.TrimSpace(identifier))
 	if identifier == "" {
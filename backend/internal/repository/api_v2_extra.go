@@ -23,6 +23,8 @@ type NFTCollectionSummary struct {
 	Count           int64
 	HolderCount     int64
 	TransferCount   int64
+	MintedCount     int64 // only set by GetNFTCollectionSummary
+	BurnedCount     int64 // only set by GetNFTCollectionSummary
 	EVMAddress      string
 	IsVerified      bool
 	UpdatedAt       time.Time
@@ -74,9 +76,10 @@ func (r *Repository) CountFTHoldingsByAddress(ctx context.Context, address strin
 	return total, nil
 }
 
-func (r *Repository) CountFTTokens(ctx context.Context) (int64, error) {
+func (r *Repository) CountFTTokens(ctx context.Context, filter FTTokenListFilter) (int64, error) {
+	where, args := filter.where(1)
 	var total int64
-	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM app.ft_tokens`).Scan(&total); err != nil {
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM app.ft_tokens ft `+where, args...).Scan(&total); err != nil {
 		return 0, err
 	}
 	return total, nil
@@ -176,6 +179,18 @@ func (r *Repository) CountNFTCollectionSummariesByOwner(ctx context.Context, own
 	return total, nil
 }
 
+// CountNFTsByOwner counts the NFTs currently owned by owner across all collections.
+func (r *Repository) CountNFTsByOwner(ctx context.Context, owner string) (int64, error) {
+	var total int64
+	if err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM app.nft_ownership
+		WHERE owner = $1`, hexToBytes(owner)).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
 func (r *Repository) CountNFTOwnersByCollection(ctx context.Context, collection, contractName string) (int64, error) {
 	var total int64
 	if err := r.db.QueryRow(ctx, `
@@ -204,6 +219,7 @@ type EVMTransactionRecord struct {
 	ChainID     string
 	Data        string
 	Logs        string
+	LogCount    int // decoded logs in app.evm_logs; set by GetEVMTransactionsByCadenceTx
 	Position    int
 	EventIndex  int
 	StatusCode  int
@@ -450,15 +466,18 @@ func (r *Repository) GetNFTCollectionSummary(ctx context.Context, contract, cont
 				COALESCE(c.socials::text, '') AS socials,
 				COALESCE(s.nft_count, 0) AS cnt,
 				COALESCE(s.holder_count, 0) AS holder_cnt,
+				COALESCE(m.minted_count, 0) AS minted_cnt,
+				COALESCE(m.burned_count, 0) AS burned_cnt,
 				COALESCE(c.evm_address, '') AS evm_address,
 				COALESCE(c.is_verified, false) AS is_verified,
 				COALESCE(c.updated_at, NOW()) AS updated_at
 			FROM app.nft_collections c
 			FULL OUTER JOIN app.nft_collection_stats s ON s.contract_address = c.contract_address AND s.contract_name = c.contract_name
+			LEFT JOIN app.nft_collection_supply m ON m.contract_address = COALESCE(c.contract_address, s.contract_address) AND m.contract_name = COALESCE(c.contract_name, s.contract_name)
 			WHERE COALESCE(c.contract_address, s.contract_address) = $1
 			ORDER BY COALESCE(c.contract_name, s.contract_name) ASC
 			LIMIT 1`, hexToBytes(contract)).
-			Scan(&row.ContractAddress, &row.ContractName, &row.Name, &row.Symbol, &row.Description, &row.ExternalURL, &row.SquareImage, &row.BannerImage, &row.Socials, &row.Count, &row.HolderCount, &row.EVMAddress, &row.IsVerified, &row.UpdatedAt)
+			Scan(&row.ContractAddress, &row.ContractName, &row.Name, &row.Symbol, &row.Description, &row.ExternalURL, &row.SquareImage, &row.BannerImage, &row.Socials, &row.Count, &row.HolderCount, &row.MintedCount, &row.BurnedCount, &row.EVMAddress, &row.IsVerified, &row.UpdatedAt)
 		if err == pgx.ErrNoRows {
 			return nil, nil
 		}
@@ -480,14 +499,17 @@ func (r *Repository) GetNFTCollectionSummary(ctx context.Context, contract, cont
 			COALESCE(c.socials::text, '') AS socials,
 			COALESCE(s.nft_count, 0) AS cnt,
 			COALESCE(s.holder_count, 0) AS holder_cnt,
+			COALESCE(m.minted_count, 0) AS minted_cnt,
+			COALESCE(m.burned_count, 0) AS burned_cnt,
 			COALESCE(c.evm_address, '') AS evm_address,
 			COALESCE(c.is_verified, false) AS is_verified,
 			COALESCE(c.updated_at, NOW()) AS updated_at
 		FROM app.nft_collections c
 		FULL OUTER JOIN app.nft_collection_stats s ON s.contract_address = c.contract_address AND s.contract_name = c.contract_name
+		LEFT JOIN app.nft_collection_supply m ON m.contract_address = COALESCE(c.contract_address, s.contract_address) AND m.contract_name = COALESCE(c.contract_name, s.contract_name)
 		WHERE COALESCE(c.contract_address, s.contract_address) = $1 AND COALESCE(c.contract_name, s.contract_name) = $2`,
 		hexToBytes(contract), contractName).
-		Scan(&row.ContractAddress, &row.ContractName, &row.Name, &row.Symbol, &row.Description, &row.ExternalURL, &row.SquareImage, &row.BannerImage, &row.Socials, &row.Count, &row.HolderCount, &row.EVMAddress, &row.IsVerified, &row.UpdatedAt)
+		Scan(&row.ContractAddress, &row.ContractName, &row.Name, &row.Symbol, &row.Description, &row.ExternalURL, &row.SquareImage, &row.BannerImage, &row.Socials, &row.Count, &row.HolderCount, &row.MintedCount, &row.BurnedCount, &row.EVMAddress, &row.IsVerified, &row.UpdatedAt)
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
@@ -528,29 +550,6 @@ func (r *Repository) ListNFTCollectionSummariesByOwner(ctx context.Context, owne
 	return out, nil
 }
 
-func (r *Repository) ListNFTOwnershipByOwnerAndCollection(ctx context.Context, owner, collection, contractName string, limit, offset int) ([]models.NFTOwnership, error) {
-	rows, err := r.db.Query(ctx, `
-		SELECT encode(contract_address, 'hex') AS contract_address, COALESCE(contract_name, '') AS contract_name, nft_id,
-		       COALESCE(encode(owner, 'hex'), '') AS owner, COALESCE(last_height,0), updated_at
-		FROM app.nft_ownership
-		WHERE owner = $1 AND contract_address = $2 AND ($3 = '' OR contract_name = $3)
-		ORDER BY contract_name ASC, nft_id ASC
-		LIMIT $4 OFFSET $5`, hexToBytes(owner), hexToBytes(collection), contractName, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var out []models.NFTOwnership
-	for rows.Next() {
-		var o models.NFTOwnership
-		if err := rows.Scan(&o.ContractAddress, &o.ContractName, &o.NFTID, &o.Owner, &o.LastHeight, &o.UpdatedAt); err != nil {
-			return nil, err
-		}
-		out = append(out, o)
-	}
-	return out, nil
-}
-
 func (r *Repository) ListNFTOwnerCountsByCollection(ctx context.Context, collection, contractName string, limit, offset int) ([]NFTOwnerCount, bool, error) {
 	// Fetch limit+1 rows to determine hasMore without a separate COUNT(*) query.
 	fetchLimit := limit + 1
@@ -732,8 +731,9 @@ func (r *Repository) GetEVMTransactionsByCadenceTx(ctx context.Context, txID str
 			COALESCE(event_index, 0),
 			COALESCE(status_code, 0),
 			COALESCE(status, ''),
-			timestamp
-		FROM app.evm_transactions
+			timestamp,
+			(SELECT COUNT(*) FROM app.evm_logs l WHERE l.evm_hash = e.evm_hash)
+		FROM app.evm_transactions e
 		WHERE transaction_id = $1 AND block_height = $2
 		ORDER BY event_index`, hexToBytes(txID), blockHeight)
 	if err != nil {
@@ -764,6 +764,7 @@ func (r *Repository) GetEVMTransactionsByCadenceTx(ctx context.Context, txID str
 			&row.StatusCode,
 			&row.Status,
 			&row.Timestamp,
+			&row.LogCount,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// BackfillCompletion marks a backward ingester instance as having indexed its
+// whole range, down to FloorHeight.
+type BackfillCompletion struct {
+	ServiceName string    `json:"service_name"`
+	StartHeight uint64    `json:"start_height"`
+	FloorHeight uint64    `json:"floor_height"`
+	Reason      string    `json:"reason"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// MarkBackfillComplete records (or refreshes) a completion marker.
+func (r *Repository) MarkBackfillComplete(ctx context.Context, c BackfillCompletion) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO app.backfill_completions (service_name, start_height, floor_height, reason, completed_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (service_name, start_height, floor_height) DO UPDATE SET
+			reason = EXCLUDED.reason`,
+		c.ServiceName, int64(c.StartHeight), int64(c.FloorHeight), c.Reason)
+	return err
+}
+
+// ListBackfillCompletions returns every completion marker, newest first.
+func (r *Repository) ListBackfillCompletions(ctx context.Context) ([]BackfillCompletion, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT service_name, start_height, floor_height, reason, completed_at
+		FROM app.backfill_completions
+		ORDER BY completed_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []BackfillCompletion
+	for rows.Next() {
+		var c BackfillCompletion
+		var start, floor int64
+		if err := rows.Scan(&c.ServiceName, &start, &floor, &c.Reason, &c.CompletedAt); err != nil {
+			return nil, err
+		}
+		c.StartHeight, c.FloorHeight = uint64(start), uint64(floor)
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Where a looked-up timestamp falls relative to the indexed blocks.
+const (
+	BlockTimestampWithinRange = "within_range"
+	BlockTimestampBeforeRange = "before_range" // earlier than the first indexed block
+	BlockTimestampAfterRange  = "after_range"  // later than the latest indexed block
+)
+
+// BlockTime identifies a block by height, id and timestamp.
+type BlockTime struct {
+	Height    uint64    `json:"height"`
+	ID        string    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// BlockAtTimestamp is the result of GetBlockByTimestamp.
+type BlockAtTimestamp struct {
+	Nearest  BlockTime  // closest block to the timestamp; ties go to the earlier block
+	Before   *BlockTime // latest block at or before the timestamp, nil before the indexed range
+	After    *BlockTime // first block at or after the timestamp, nil after the indexed tip
+	Position string     // one of the BlockTimestamp* constants
+}
+
+// resolveBlockAtTimestamp picks the nearest of the blocks bracketing t.
+// Outside the indexed range the nearest block is the first or latest one.
+func resolveBlockAtTimestamp(t time.Time, before, after *BlockTime) (*BlockAtTimestamp, error) {
+	out := &BlockAtTimestamp{Before: before, After: after, Position: BlockTimestampWithinRange}
+	switch {
+	case before == nil && after == nil:
+		return nil, fmt.Errorf("block at %s: %w", t.Format(time.RFC3339), ErrNotFound)
+	case before == nil:
+		out.Nearest, out.Position = *after, BlockTimestampBeforeRange
+	case after == nil:
+		out.Nearest, out.Position = *before, BlockTimestampAfterRange
+	case after.Timestamp.Sub(t) < t.Sub(before.Timestamp):
+		out.Nearest = *after
+	default:
+		out.Nearest = *before
+	}
+	return out, nil
+}
+
+// GetBlockByTimestamp finds the blocks around t using raw.block_lookup's
+// timestamp index: the latest at or before t, the first at or after t, and
+// whichever of the two is closer. It returns ErrNotFound when no blocks are
+// indexed.
+func (r *Repository) GetBlockByTimestamp(ctx context.Context, t time.Time) (*BlockAtTimestamp, error) {
+	before, err := r.blockTimeAt(ctx, "block_at_or_before_ts", `
+		SELECT height, encode(id, 'hex'), timestamp
+		FROM raw.block_lookup
+		WHERE timestamp <= $1
+		ORDER BY timestamp DESC, height DESC
+		LIMIT 1`, t)
+	if err != nil {
+		return nil, err
+	}
+	after, err := r.blockTimeAt(ctx, "block_at_or_after_ts", `
+		SELECT height, encode(id, 'hex'), timestamp
+		FROM raw.block_lookup
+		WHERE timestamp >= $1
+		ORDER BY timestamp ASC, height ASC
+		LIMIT 1`, t)
+	if err != nil {
+		return nil, err
+	}
+	return resolveBlockAtTimestamp(t, before, after)
+}
+
+// blockTimeAt runs a single-block lookup, returning nil when nothing matches.
+func (r *Repository) blockTimeAt(ctx context.Context, tag, sql string, t time.Time) (*BlockTime, error) {
+	var b BlockTime
+	err := r.queryRow(ctx, tag, sql, t.UTC()).Scan(&b.Height, &b.ID, &b.Timestamp)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("block at %s: %w", t.Format(time.RFC3339), err)
+	}
+	return &b, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// bracketBlocks mirrors GetBlockByTimestamp's two index lookups over a
+// fixture ordered by timestamp.
+func bracketBlocks(blocks []BlockTime, t time.Time) (before, after *BlockTime) {
+	for i := range blocks {
+		b := blocks[i]
+		if !b.Timestamp.After(t) {
+			before = &b
+		}
+		if after == nil && !b.Timestamp.Before(t) {
+			after = &b
+		}
+	}
+	return before, after
+}
+
+func TestResolveBlockAtTimestamp(t *testing.T) {
+	t.Parallel()
+
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	blocks := []BlockTime{
+		{Height: 100, ID: "a", Timestamp: t0},
+		{Height: 101, ID: "b", Timestamp: t0.Add(2 * time.Second)},
+		{Height: 102, ID: "c", Timestamp: t0.Add(6 * time.Second)},
+	}
+
+	cases := []struct {
+		name     string
+		at       time.Time
+		want     uint64
+		position string
+	}{
+		{"exact", t0.Add(2 * time.Second), 101, BlockTimestampWithinRange},
+		{"closer to later", t0.Add(5 * time.Second), 102, BlockTimestampWithinRange},
+		{"closer to earlier", t0.Add(3 * time.Second), 101, BlockTimestampWithinRange},
+		{"tie goes to earlier", t0.Add(4 * time.Second), 101, BlockTimestampWithinRange},
+		{"before first block", t0.Add(-time.Hour), 100, BlockTimestampBeforeRange},
+		{"future", t0.Add(time.Hour), 102, BlockTimestampAfterRange},
+	}
+	for _, tc := range cases {
+		before, after := bracketBlocks(blocks, tc.at)
+		got, err := resolveBlockAtTimestamp(tc.at, before, after)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+		if got.Nearest.Height != tc.want || got.Position != tc.position {
+			t.Errorf("%s: got height %d (%s), want %d (%s)", tc.name, got.Nearest.Height, got.Position, tc.want, tc.position)
+		}
+	}
+
+	// First block at or after the timestamp.
+	before, after := bracketBlocks(blocks, t0.Add(3*time.Second))
+	got, _ := resolveBlockAtTimestamp(t0.Add(3*time.Second), before, after)
+	if got.After == nil || got.After.Height != 102 || got.Before == nil || got.Before.Height != 101 {
+		t.Errorf("bracketing blocks = %+v / %+v, want 101 / 102", got.Before, got.After)
+	}
+
+	if _, err := resolveBlockAtTimestamp(t0, nil, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("empty index: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"flowscan-clone/internal/models"
+)
+
+// BlockFull is a block with its collections and one page of its transactions
+// (ordered by transaction_index), each with its events.
+type BlockFull struct {
+	Block        models.Block
+	Collections  []models.Collection
+	Transactions []models.Transaction
+	TxTotal      int // transactions in the whole block, not just this page
+}
+
+// GetBlockFull loads a block, its collections and transactions [offset,
+// offset+limit) with their events. The query count does not depend on the
+// block size: one each for the header, collections, transactions and events.
+// Returns ErrNotFound when the block is not indexed.
+func (r *Repository) GetBlockFull(ctx context.Context, height uint64, limit, offset int) (*BlockFull, error) {
+	out := &BlockFull{}
+	b := &out.Block
+	err := r.queryRow(ctx, "block_full_header", `
+		SELECT height,
+		       encode(id, 'hex') AS id,
+		       COALESCE(encode(parent_id, 'hex'), '') AS parent_id,
+		       timestamp,
+		       COALESCE(collection_count, 0),
+		       COALESCE(event_count, 0),
+		       COALESCE(total_gas_used, 0),
+		       COALESCE(is_sealed, FALSE),
+		       (SELECT COUNT(*) FROM raw.transactions t WHERE t.block_height = $1)
+		FROM raw.blocks
+		WHERE height = $1`, height).
+		Scan(&b.Height, &b.ID, &b.ParentID, &b.Timestamp, &b.CollectionCount, &b.EventCount, &b.TotalGasUsed, &b.IsSealed, &out.TxTotal)
+	if err != nil {
+		return nil, wrapDBErr(err, fmt.Sprintf("block %d", height))
+	}
+	b.TxCount = out.TxTotal
+
+	rows, err := r.query(ctx, "block_full_collections", `
+		SELECT encode(id, 'hex'),
+		       ARRAY(SELECT encode(t, 'hex') FROM unnest(transaction_ids) WITH ORDINALITY AS u(t, n) ORDER BY n)
+		FROM raw.collections
+		WHERE block_height = $1`, height)
+	if err != nil {
+		return nil, fmt.Errorf("block %d collections: %w", height, err)
+	}
+	for rows.Next() {
+		c := models.Collection{BlockHeight: height}
+		if err := rows.Scan(&c.ID, &c.TransactionIDs); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		out.Collections = append(out.Collections, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.query(ctx, "block_full_transactions", `
+		SELECT encode(t.id, 'hex') AS id, t.block_height, t.transaction_index,
+		       COALESCE(encode(t.proposer_address, 'hex'), '') AS proposer_address,
+		       COALESCE(t.proposer_key_index, 0), COALESCE(t.proposer_sequence_number, 0),
+		       COALESCE(encode(t.payer_address, 'hex'), '') AS payer_address,
+		       COALESCE(ARRAY(SELECT encode(a, 'hex') FROM unnest(t.authorizers) a), ARRAY[]::text[]) AS authorizers,
+		       t.status, COALESCE(t.error_message, '') AS error_message, t.is_evm, t.gas_limit,
+		       COALESCE(m.gas_used, t.gas_used) AS gas_used,
+		       t.timestamp,
+		       COALESCE(m.event_count, t.event_count) AS event_count,
+		       COALESCE(t.script_hash, '') AS script_hash
+		FROM raw.transactions t
+		LEFT JOIN app.tx_metrics m ON m.transaction_id = t.id AND m.block_height = t.block_height
+		WHERE t.block_height = $1
+		ORDER BY t.transaction_index ASC
+		LIMIT $2 OFFSET $3`, height, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("block %d transactions: %w", height, err)
+	}
+	for rows.Next() {
+		var t models.Transaction
+		if err := rows.Scan(&t.ID, &t.BlockHeight, &t.TransactionIndex, &t.ProposerAddress, &t.ProposerKeyIndex, &t.ProposerSequenceNumber,
+			&t.PayerAddress, &t.Authorizers, &t.Status, &t.ErrorMessage, &t.IsEVM, &t.GasLimit, &t.GasUsed, &t.Timestamp,
+			&t.EventCount, &t.ScriptHash); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		t.CreatedAt = t.Timestamp
+		out.Transactions = append(out.Transactions, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(out.Transactions) == 0 {
+		return out, nil
+	}
+	refs := make([]TxRef, 0, len(out.Transactions))
+	for _, t := range out.Transactions {
+		refs = append(refs, TxRef{ID: t.ID, BlockHeight: height})
+	}
+	events, err := r.GetEventsByTxRefs(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("block %d events: %w", height, err)
+	}
+	byTx := make(map[string][]models.Event, len(out.Transactions))
+	for _, e := range events {
+		byTx[e.TransactionID] = append(byTx[e.TransactionID], e)
+	}
+	for i := range out.Transactions {
+		out.Transactions[i].Events = byTx[out.Transactions[i].ID]
+	}
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+// TestGetBlockFull needs a database with the schema applied. It stores a
+// block with two collections and checks the nested result and that loading it
+// takes the same handful of queries however many transactions it has.
+func TestGetBlockFull(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	repo, err := NewRepository(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	ctx := context.Background()
+
+	const height = 1_999_999_001
+	cleanup := func() {
+		for _, q := range []string{
+			`DELETE FROM raw.events WHERE block_height = $1`,
+			`DELETE FROM raw.transactions WHERE block_height = $1`,
+			`DELETE FROM raw.tx_lookup WHERE block_height = $1`,
+			`DELETE FROM raw.collections WHERE block_height = $1`,
+			`DELETE FROM raw.block_lookup WHERE height = $1`,
+			`DELETE FROM raw.blocks WHERE height = $1`,
+		} {
+			if _, err := repo.db.Exec(ctx, q, height); err != nil {
+				t.Logf("cleanup: %v", err)
+			}
+		}
+		repo.db.Exec(ctx, `DELETE FROM app.indexing_checkpoints WHERE service_name = 'test_block_full'`)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	ts := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	txID := func(i int) string { return fmt.Sprintf("%064x", 0xb10c00+i) }
+	var txs []models.Transaction
+	var events []models.Event
+	for i := 0; i < 5; i++ {
+		txs = append(txs, models.Transaction{
+			ID: txID(i), BlockHeight: height, TransactionIndex: i, Timestamp: ts, Status: "SEALED",
+			PayerAddress: "1654653399040a61", ProposerAddress: "1654653399040a61", Authorizers: []string{"1654653399040a61"},
+		})
+		for j := 0; j < 2; j++ {
+			events = append(events, models.Event{
+				TransactionID: txID(i), TransactionIndex: i, EventIndex: j, BlockHeight: height, Timestamp: ts,
+				Type: "A.1654653399040a61.FlowToken.TokensDeposited", EventName: "TokensDeposited", Payload: []byte(`{"amount":"1.0"}`),
+			})
+		}
+	}
+	collA, collB := fmt.Sprintf("%064x", 0xc0a), fmt.Sprintf("%064x", 0xc0b)
+	block := &models.Block{
+		Height: height, ID: fmt.Sprintf("%064x", 0xb10c), ParentID: fmt.Sprintf("%064x", 0xb10b), Timestamp: ts,
+		CollectionCount: 2, TxCount: len(txs), EventCount: len(events),
+		Collections: []models.Collection{
+			{ID: collA, BlockHeight: height, TransactionIDs: []string{txID(0), txID(1)}},
+			{ID: collB, BlockHeight: height, TransactionIDs: []string{txID(2), txID(3)}},
+		},
+	}
+	if err := repo.SaveBatch(ctx, []*models.Block{block}, txs, events, "test_block_full", height); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		limit, offset int
+		want          []string
+	}{
+		{limit: 10, want: []string{txID(0), txID(1), txID(2), txID(3), txID(4)}},
+		{limit: 2, offset: 2, want: []string{txID(2), txID(3)}},
+	} {
+		before := repo.QueryStats()
+		full, err := repo.GetBlockFull(ctx, height, tc.limit, tc.offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		after := repo.QueryStats()
+
+		if full.Block.ID != block.ID || full.TxTotal != 5 || len(full.Collections) != 2 {
+			t.Fatalf("block = %+v total=%d collections=%d", full.Block, full.TxTotal, len(full.Collections))
+		}
+		for _, c := range full.Collections {
+			if c.ID == collB && !reflect.DeepEqual(c.TransactionIDs, []string{txID(2), txID(3)}) {
+				t.Fatalf("collection %s txs = %v", c.ID, c.TransactionIDs)
+			}
+		}
+		var got []string
+		for _, tx := range full.Transactions {
+			got = append(got, tx.ID)
+			if len(tx.Events) != 2 || tx.Events[0].TransactionID != tx.ID {
+				t.Fatalf("tx %s events = %+v", tx.ID, tx.Events)
+			}
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("limit=%d offset=%d txs = %v; want %v", tc.limit, tc.offset, got, tc.want)
+		}
+
+		for _, tag := range []string{"block_full_header", "block_full_collections", "block_full_transactions", "events_by_tx_refs"} {
+			if n := after[tag].Count - before[tag].Count; n != 1 {
+				t.Errorf("%s ran %d times; want 1", tag, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"flowscan-clone/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// BlockReorg records a block id at a height being replaced by a different one.
+type BlockReorg struct {
+	ID         int64     `json:"id"`
+	Height     uint64    `json:"height"`
+	OldID      string    `json:"old_id"`
+	NewID      string    `json:"new_id"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// detectBlockReorgs compares incoming blocks against the ids already stored at
+// their heights and returns one BlockReorg per height whose id changes.
+// Heights that are not stored yet (the normal forward-ingest case) are ignored.
+func detectBlockReorgs(stored map[uint64][]byte, blocks []*models.Block) []BlockReorg {
+	if len(stored) == 0 {
+		return nil
+	}
+	var out []BlockReorg
+	for _, b := range blocks {
+		old, ok := stored[b.Height]
+		if !ok || len(old) == 0 {
+			continue
+		}
+		newID := hexToBytes(b.ID)
+		if len(newID) == 0 || bytes.Equal(old, newID) {
+			continue
+		}
+		out = append(out, BlockReorg{
+			Height: b.Height,
+			OldID:  bytesToHex(old),
+			NewID:  bytesToHex(newID),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Height < out[j].Height })
+	return out
+}
+
+// recordBlockReorgs looks up the stored ids for the batch heights and logs any
+// that the upcoming upsert will overwrite. It must run inside the SaveBatch
+// transaction before raw.blocks is written. The lookup is a single primary-key
+// probe per height, and forward ingestion of new heights finds no rows.
+func recordBlockReorgs(ctx context.Context, tx pgx.Tx, blocks []*models.Block) error {
+	heights := make([]int64, len(blocks))
+	for i, b := range blocks {
+		heights[i] = int64(b.Height)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT height, id FROM raw.blocks WHERE height = ANY($1::bigint[])`, heights)
+	if err != nil {
+		return fmt.Errorf("lookup stored block ids: %w", err)
+	}
+	stored := make(map[uint64][]byte)
+	for rows.Next() {
+		var h int64
+		var id []byte
+		if err := rows.Scan(&h, &id); err != nil {
+			rows.Close()
+			return fmt.Errorf("lookup stored block ids: %w", err)
+		}
+		stored[uint64(h)] = id
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("lookup stored block ids: %w", err)
+	}
+
+	reorgs := detectBlockReorgs(stored, blocks)
+	for _, ro := range reorgs {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO app.block_reorgs (height, old_id, new_id, detected_at)
+			VALUES ($1, $2, $3, NOW())`,
+			int64(ro.Height), hexToBytes(ro.OldID), hexToBytes(ro.NewID),
+		); err != nil {
+			return fmt.Errorf("record block reorg at %d: %w", ro.Height, err)
+		}
+	}
+	return nil
+}
+
+// ListBlockReorgs returns the most recently detected reorgs, newest first.
+func (r *Repository) ListBlockReorgs(ctx context.Context, limit int) ([]BlockReorg, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT id, height, encode(old_id, 'hex'), encode(new_id, 'hex'), detected_at
+		FROM app.block_reorgs
+		ORDER BY detected_at DESC, id DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []BlockReorg{}
+	for rows.Next() {
+		var ro BlockReorg
+		var h int64
+		if err := rows.Scan(&ro.ID, &h, &ro.OldID, &ro.NewID, &ro.DetectedAt); err != nil {
+			return nil, err
+		}
+		ro.Height = uint64(h)
+		out = append(out, ro)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"testing"
+
+	"flowscan-clone/internal/models"
+)
+
+func TestDetectBlockReorgs(t *testing.T) {
+	t.Parallel()
+
+	stored := map[uint64][]byte{
+		100: hexToBytes("aa01"),
+		101: hexToBytes("bb01"),
+		102: hexToBytes("cc01"),
+	}
+	blocks := []*models.Block{
+		{Height: 102, ID: "cc02"},   // simulated reorg: id replaced
+		{Height: 100, ID: "0xAA01"}, // same id, different formatting
+		{Height: 101, ID: "bb02"},   // simulated reorg
+		{Height: 103, ID: "dd01"},   // new height, nothing stored
+	}
+
+	got := detectBlockReorgs(stored, blocks)
+	if len(got) != 2 {
+		t.Fatalf("got %d reorgs, want 2: %+v", len(got), got)
+	}
+	want := []BlockReorg{
+		{Height: 101, OldID: "bb01", NewID: "bb02"},
+		{Height: 102, OldID: "cc01", NewID: "cc02"},
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("reorg[%d]=%+v want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDetectBlockReorgsNothingStored(t *testing.T) {
+	t.Parallel()
+
+	blocks := []*models.Block{{Height: 1, ID: "01"}, {Height: 2, ID: "02"}}
+	if got := detectBlockReorgs(nil, blocks); len(got) != 0 {
+		t.Fatalf("expected no reorgs on forward ingest, got %+v", got)
+	}
+}
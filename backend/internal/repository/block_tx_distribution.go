@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultTxDistributionBounds are the lower bounds of the default tx-per-block
+// buckets: 0, 1-10, 11-50, 51-100, 101-500, 501-1000 and 1001+.
+var DefaultTxDistributionBounds = []int64{0, 1, 11, 51, 101, 501, 1001}
+
+// maxTxDistributionBuckets caps how many bounds a caller may pass.
+const maxTxDistributionBuckets = 32
+
+// TxCountBucket is one histogram bucket: the number of blocks whose tx_count
+// falls in [Min, Max]. Max is nil for the open-ended last bucket.
+type TxCountBucket struct {
+	Label  string `json:"label"`
+	Min    int64  `json:"min"`
+	Max    *int64 `json:"max"`
+	Blocks int64  `json:"blocks"`
+}
+
+// ParseTxDistributionBounds parses a comma-separated list of bucket lower
+// bounds (e.g. "0,1,11,51"). Bounds must be non-negative and strictly
+// increasing; a leading 0 is added when missing so every block lands in a
+// bucket. Empty input returns DefaultTxDistributionBounds.
+func ParseTxDistributionBounds(s string) ([]int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return DefaultTxDistributionBounds, nil
+	}
+	var bounds []int64
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %q is not a non-negative integer", ErrInvalidTxBuckets, part)
+		}
+		if len(bounds) > 0 && n <= bounds[len(bounds)-1] {
+			return nil, fmt.Errorf("%w: bounds must be strictly increasing", ErrInvalidTxBuckets)
+		}
+		bounds = append(bounds, n)
+	}
+	if bounds[0] != 0 {
+		bounds = append([]int64{0}, bounds...)
+	}
+	if len(bounds) > maxTxDistributionBuckets {
+		return nil, fmt.Errorf("%w: at most %d buckets", ErrInvalidTxBuckets, maxTxDistributionBuckets)
+	}
+	return bounds, nil
+}
+
+// txCountBucketIndex mirrors Postgres width_bucket(count, bounds) for a
+// sorted bounds array starting at 0: bucket i (1-based) holds
+// bounds[i-1] <= count < bounds[i], the last bucket is open-ended.
+func txCountBucketIndex(bounds []int64, count int64) int {
+	i := 0
+	for i < len(bounds) && count >= bounds[i] {
+		i++
+	}
+	return i
+}
+
+// buildTxCountBuckets lays out every bucket for bounds (including empty ones)
+// and fills in the block counts keyed by width_bucket index.
+func buildTxCountBuckets(bounds []int64, counts map[int]int64) []TxCountBucket {
+	out := make([]TxCountBucket, len(bounds))
+	for i, lo := range bounds {
+		b := TxCountBucket{Min: lo, Blocks: counts[i+1]}
+		switch {
+		case i == len(bounds)-1:
+			b.Label = strconv.FormatInt(lo, 10) + "+"
+		case bounds[i+1]-1 == lo:
+			hi := lo
+			b.Max = &hi
+			b.Label = strconv.FormatInt(lo, 10)
+		default:
+			hi := bounds[i+1] - 1
+			b.Max = &hi
+			b.Label = strconv.FormatInt(lo, 10) + "-" + strconv.FormatInt(hi, 10)
+		}
+		out[i] = b
+	}
+	return out
+}
+
+// GetTxPerBlockDistribution returns a histogram of raw.blocks.tx_count over
+// the blocks in [fromHeight, toHeight], bucketed by the lower bounds in
+// bounds (see ParseTxDistributionBounds).
+func (r *Repository) GetTxPerBlockDistribution(ctx context.Context, fromHeight, toHeight uint64, bounds []int64) ([]TxCountBucket, error) {
+	rows, err := r.readDB().Query(ctx, `
+		SELECT width_bucket(COALESCE(tx_count, 0), $3::bigint[]) AS bucket, COUNT(*)
+		FROM raw.blocks
+		WHERE height >= $1 AND height <= $2
+		GROUP BY bucket`, fromHeight, toHeight, bounds)
+	if err != nil {
+		return nil, fmt.Errorf("tx per block distribution [%d, %d]: %w", fromHeight, toHeight, err)
+	}
+	defer rows.Close()
+	counts := make(map[int]int64, len(bounds))
+	for rows.Next() {
+		var bucket int
+		var n int64
+		if err := rows.Scan(&bucket, &n); err != nil {
+			return nil, err
+		}
+		counts[bucket] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return buildTxCountBuckets(bounds, counts), nil
+}
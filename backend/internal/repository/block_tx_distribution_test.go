@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTxPerBlockDistributionBuckets(t *testing.T) {
+	t.Parallel()
+
+	// tx_count of each block in the fixture range.
+	blocks := []int64{0, 0, 0, 1, 3, 10, 11, 50, 51, 99, 100, 101, 480, 500, 501, 1000, 1001, 4200}
+	counts := map[int]int64{}
+	for _, n := range blocks {
+		counts[txCountBucketIndex(DefaultTxDistributionBounds, n)]++
+	}
+
+	got := buildTxCountBuckets(DefaultTxDistributionBounds, counts)
+	want := map[string]int64{
+		"0": 3, "1-10": 3, "11-50": 2, "51-100": 3, "101-500": 3, "501-1000": 2, "1001+": 2,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d buckets, got %d: %+v", len(want), len(got), got)
+	}
+	var total int64
+	for _, b := range got {
+		if b.Blocks != want[b.Label] {
+			t.Errorf("bucket %s: got %d blocks, want %d", b.Label, b.Blocks, want[b.Label])
+		}
+		total += b.Blocks
+	}
+	if total != int64(len(blocks)) {
+		t.Errorf("buckets cover %d blocks, want %d", total, len(blocks))
+	}
+	if last := got[len(got)-1]; last.Max != nil || last.Min != 1001 {
+		t.Errorf("last bucket should be open-ended from 1001, got %+v", last)
+	}
+	if first := got[0]; first.Max == nil || *first.Max != 0 {
+		t.Errorf("first bucket should be exactly 0, got %+v", first)
+	}
+}
+
+func TestTxPerBlockDistributionCustomBounds(t *testing.T) {
+	t.Parallel()
+
+	bounds, err := ParseTxDistributionBounds("5, 20")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !reflect.DeepEqual(bounds, []int64{0, 5, 20}) {
+		t.Fatalf("bounds = %v, want leading 0 added", bounds)
+	}
+	counts := map[int]int64{}
+	for _, n := range []int64{0, 4, 5, 19, 20, 21, 300} {
+		counts[txCountBucketIndex(bounds, n)]++
+	}
+	got := buildTxCountBuckets(bounds, counts)
+	labels := []string{got[0].Label, got[1].Label, got[2].Label}
+	if !reflect.DeepEqual(labels, []string{"0-4", "5-19", "20+"}) {
+		t.Errorf("labels = %v", labels)
+	}
+	if got[0].Blocks != 2 || got[1].Blocks != 2 || got[2].Blocks != 3 {
+		t.Errorf("unexpected counts: %+v", got)
+	}
+
+	for _, bad := range []string{"1,1", "10,5", "-1", "abc"} {
+		if _, err := ParseTxDistributionBounds(bad); !errors.Is(err, ErrInvalidTxBuckets) {
+			t.Errorf("ParseTxDistributionBounds(%q) error = %v, want ErrInvalidTxBuckets", bad, err)
+		}
+	}
+	if bounds, err := ParseTxDistributionBounds(""); err != nil || !reflect.DeepEqual(bounds, DefaultTxDistributionBounds) {
+		t.Errorf("empty bounds = %v, %v; want defaults", bounds, err)
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 
 	"flowscan-clone/internal/models"
 
@@ -109,3 +110,80 @@ func (r *Repository) GetCOAByFlowAddress(ctx context.Context, flowAddress string
 	}
 	return &out, nil
 }
+
+// ListCOAsByFlowAddress returns every COA owned by a Flow account, oldest first.
+// An account can create more than one COA (e.g. from different apps).
+func (r *Repository) ListCOAsByFlowAddress(ctx context.Context, flowAddress string) ([]models.COAAccount, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT encode(coa_address, 'hex') AS coa_address,
+		       encode(flow_address, 'hex') AS flow_address,
+		       COALESCE(encode(transaction_id, 'hex'), '') AS transaction_id,
+		       COALESCE(block_height,0), created_at, updated_at
+		FROM app.coa_accounts
+		WHERE flow_address = $1
+		ORDER BY COALESCE(block_height,0) ASC, coa_address ASC`, hexToBytes(flowAddress))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.COAAccount
+	for rows.Next() {
+		var row models.COAAccount
+		if err := rows.Scan(&row.COAAddress, &row.FlowAddress, &row.TransactionID, &row.BlockHeight, &row.CreatedAt, &row.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+// COACreatedEvent is an EVM.CadenceOwnedAccountCreated event together with the
+// signers of the transaction that emitted it.
+type COACreatedEvent struct {
+	models.Event
+	ProposerAddress string
+	PayerAddress    string
+	Authorizers     []string
+}
+
+// GetCOACreatedEventsInRange returns COA creation events in [fromHeight, toHeight).
+func (r *Repository) GetCOACreatedEventsInRange(ctx context.Context, fromHeight, toHeight uint64) ([]COACreatedEvent, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			e.block_height,
+			encode(e.transaction_id, 'hex') AS transaction_id,
+			e.event_index,
+			e.transaction_index,
+			e.type,
+			e.payload,
+			COALESCE(encode(t.proposer_address, 'hex'), '') AS proposer_address,
+			COALESCE(encode(t.payer_address, 'hex'), '') AS payer_address,
+			COALESCE(ARRAY(SELECT encode(a, 'hex') FROM unnest(t.authorizers) a), ARRAY[]::text[]) AS authorizers,
+			e.payload_compressed
+		FROM raw.events e
+		LEFT JOIN raw.transactions t ON t.block_height = e.block_height AND t.id = e.transaction_id
+		WHERE e.block_height >= $1 AND e.block_height < $2
+		  AND e.type LIKE '%EVM.CadenceOwnedAccountCreated'
+		ORDER BY e.block_height ASC, e.transaction_index ASC, e.event_index ASC`,
+		fromHeight, toHeight,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query coa created events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []COACreatedEvent
+	for rows.Next() {
+		var e COACreatedEvent
+		var compressed []byte
+		if err := rows.Scan(&e.BlockHeight, &e.TransactionID, &e.EventIndex, &e.TransactionIndex, &e.Type, &e.Payload, &e.ProposerAddress, &e.PayerAddress, &e.Authorizers, &compressed); err != nil {
+			return nil, err
+		}
+		if err := restoreEventPayload(&e.Event, compressed); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
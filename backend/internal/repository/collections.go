@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CollectionTransaction is one transaction of a collection. Indexed is false
+// when the transaction has no raw.tx_lookup row yet.
+type CollectionTransaction struct {
+	ID               string    `json:"id"`
+	Indexed          bool      `json:"indexed"`
+	BlockHeight      uint64    `json:"block_height"`
+	TransactionIndex int       `json:"transaction_index"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// CollectionDetail is a raw.collections row with its transactions resolved.
+type CollectionDetail struct {
+	ID           string
+	BlockHeight  uint64
+	Transactions []CollectionTransaction
+	Partial      bool // some transactions are not indexed
+}
+
+// buildCollectionDetail orders the resolved lookups by the collection's
+// transaction order, marking transactions missing from lookups as unindexed.
+func buildCollectionDetail(id string, height uint64, txIDs []string, lookups map[string]CollectionTransaction) *CollectionDetail {
+	out := &CollectionDetail{ID: id, BlockHeight: height, Transactions: make([]CollectionTransaction, 0, len(txIDs))}
+	for _, txID := range txIDs {
+		tx, ok := lookups[txID]
+		if !ok {
+			tx = CollectionTransaction{ID: txID}
+			out.Partial = true
+		}
+		out.Transactions = append(out.Transactions, tx)
+	}
+	return out
+}
+
+// GetCollectionByID returns a collection stored with STORE_COLLECTIONS and its
+// transactions resolved through raw.tx_lookup. Returns ErrNotFound when the
+// collection is not stored.
+func (r *Repository) GetCollectionByID(ctx context.Context, id string) (*CollectionDetail, error) {
+	id = strings.ToLower(strings.TrimPrefix(id, "0x"))
+	var height uint64
+	var rawTxIDs [][]byte
+	err := r.queryRow(ctx, "collection_by_id", `
+		SELECT block_height, transaction_ids
+		FROM raw.collections
+		WHERE id = $1`, hexToBytes(id)).Scan(&height, &rawTxIDs)
+	if err != nil {
+		return nil, wrapDBErr(err, "collection "+id)
+	}
+
+	lookups := make(map[string]CollectionTransaction, len(rawTxIDs))
+	if len(rawTxIDs) > 0 {
+		rows, err := r.query(ctx, "collection_tx_lookup", `
+			SELECT id, block_height, COALESCE(transaction_index, 0), timestamp
+			FROM raw.tx_lookup
+			WHERE id = ANY($1)`, rawTxIDs)
+		if err != nil {
+			return nil, fmt.Errorf("collection %s transactions: %w", id, err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var txID []byte
+			var ts *time.Time
+			tx := CollectionTransaction{Indexed: true}
+			if err := rows.Scan(&txID, &tx.BlockHeight, &tx.TransactionIndex, &ts); err != nil {
+				return nil, err
+			}
+			tx.ID = bytesToHex(txID)
+			if ts != nil {
+				tx.Timestamp = *ts
+			}
+			lookups[tx.ID] = tx
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return buildCollectionDetail(id, height, sliceBytesToHex(rawTxIDs), lookups), nil
+}
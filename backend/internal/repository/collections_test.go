@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildCollectionDetail(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 4, 2, 9, 0, 0, 0, time.UTC)
+	txIDs := []string{"aa01", "bb02", "cc03"}
+	lookups := map[string]CollectionTransaction{
+		"cc03": {ID: "cc03", Indexed: true, BlockHeight: 500, TransactionIndex: 2, Timestamp: ts},
+		"aa01": {ID: "aa01", Indexed: true, BlockHeight: 500, TransactionIndex: 0, Timestamp: ts},
+		"bb02": {ID: "bb02", Indexed: true, BlockHeight: 500, TransactionIndex: 1, Timestamp: ts},
+	}
+
+	got := buildCollectionDetail("c0", 500, txIDs, lookups)
+	if got.Partial || len(got.Transactions) != 3 {
+		t.Fatalf("fully indexed collection = %+v", got)
+	}
+	for i, tx := range got.Transactions {
+		if tx.ID != txIDs[i] || tx.TransactionIndex != i || tx.BlockHeight != 500 || !tx.Indexed {
+			t.Errorf("tx %d = %+v, want %s at index %d", i, tx, txIDs[i], i)
+		}
+	}
+
+	delete(lookups, "bb02")
+	got = buildCollectionDetail("c0", 500, txIDs, lookups)
+	if !got.Partial || len(got.Transactions) != 3 {
+		t.Fatalf("collection with an unindexed tx should be partial: %+v", got)
+	}
+	if tx := got.Transactions[1]; tx.ID != "bb02" || tx.Indexed || tx.BlockHeight != 0 {
+		t.Errorf("unindexed tx = %+v", tx)
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ContractActivity is the number of transactions that imported one contract
+// over a window.
+type ContractActivity struct {
+	Identifier string `json:"identifier"` // A.<address>.<Name>
+	Address    string `json:"address"`
+	Name       string `json:"name"`
+	TxCount    int64  `json:"tx_count"`
+}
+
+// GetTopContractsByActivity returns the limit contracts imported by the most
+// transactions over the UTC days [from, to), read from the
+// app.daily_contract_tx_counts rollup. Ties are broken by identifier.
+func (r *Repository) GetTopContractsByActivity(ctx context.Context, from, to time.Time, limit int) ([]ContractActivity, error) {
+	rows, err := r.readQuery(ctx, "top_contracts_by_activity", `
+		SELECT contract_identifier, SUM(tx_count)::bigint
+		FROM app.daily_contract_tx_counts
+		WHERE date >= $1::date AND date < $2::date
+		GROUP BY contract_identifier
+		ORDER BY SUM(tx_count) DESC, contract_identifier
+		LIMIT $3`, from.UTC(), to.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ContractActivity
+	for rows.Next() {
+		var c ContractActivity
+		if err := rows.Scan(&c.Identifier, &c.TxCount); err != nil {
+			return nil, err
+		}
+		if parts := strings.SplitN(c.Identifier, ".", 3); len(parts) == 3 {
+			c.Address = "0x" + parts[1]
+			c.Name = parts[2]
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// refreshDailyContractTxCountsRange recounts app.daily_contract_tx_counts for
+// the UTC days touched by blocks in [fromHeight, toHeight) from the
+// tx_contracts_worker output. Whole days are replaced, so retries and
+// overlapping ranges are safe; imports derived after a day was counted are
+// picked up by its next refresh.
+func (r *Repository) refreshDailyContractTxCountsRange(ctx context.Context, fromHeight, toHeight uint64) error {
+	dayFrom, dayTo, lo, hi, ok, err := r.dailyStatsBounds(ctx, fromHeight, toHeight)
+	if err != nil || !ok {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM app.daily_contract_tx_counts
+		WHERE date >= $1::date AND date < $2::date`, dayFrom, dayTo); err != nil {
+		return err
+	}
+	// tx_contracts holds one row per (transaction, contract), so COUNT(*) is
+	// the number of importing transactions. Bare imports without an address
+	// cannot be tied to a deployed contract and are left out.
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO app.daily_contract_tx_counts (date, contract_identifier, tx_count, updated_at)
+		SELECT (bl.timestamp AT TIME ZONE 'UTC')::date, tc.contract_identifier, COUNT(*), NOW()
+		FROM app.tx_contracts tc
+		JOIN raw.block_lookup bl ON bl.height = tc.block_height
+		WHERE tc.block_height >= $1 AND tc.block_height < $2
+		  AND bl.timestamp >= $3 AND bl.timestamp < $4
+		  AND tc.contract_identifier LIKE 'A.%'
+		GROUP BY 1, 2`, lo, hi, dayFrom, dayTo); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestGetTopContractsByActivity needs a database with the schema applied. It
+// uses days far in the past so live rollup rows do not interfere.
+func TestGetTopContractsByActivity(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	repo, err := NewRepository(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	ctx := context.Background()
+
+	day1 := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	cleanup := func() {
+		repo.db.Exec(ctx, `DELETE FROM app.daily_contract_tx_counts WHERE date >= $1::date AND date < $2::date`, day1, day2.AddDate(0, 0, 1))
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	// Per-day import counts; the ranking uses the sum over both days.
+	for _, row := range []struct {
+		day   time.Time
+		id    string
+		count int64
+	}{
+		{day1, "A.1d7e57aa55817448.NonFungibleToken", 100},
+		{day2, "A.1d7e57aa55817448.NonFungibleToken", 20},
+		{day1, "A.1654653399040a61.FlowToken", 900},
+		{day1, "A.e467b9dd11fa00df.FlowStorageFees", 15},
+		{day1, "A.f233dcee88fe0abe.FungibleToken", 400},
+		{day2, "A.f233dcee88fe0abe.FungibleToken", 500},
+		{day2, "A.b19436aae4d94622.FiatToken", 40},
+	} {
+		if _, err := repo.db.Exec(ctx, `
+			INSERT INTO app.daily_contract_tx_counts (date, contract_identifier, tx_count, updated_at)
+			VALUES ($1::date, $2, $3, NOW())`, row.day, row.id, row.count); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	top, err := repo.GetTopContractsByActivity(ctx, day1, day2.AddDate(0, 0, 1), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"A.1654653399040a61.FlowToken", // ties broken by identifier
+		"A.f233dcee88fe0abe.FungibleToken",
+		"A.1d7e57aa55817448.NonFungibleToken",
+	}
+	if len(top) != len(want) {
+		t.Fatalf("got %d contracts, want %d: %+v", len(top), len(want), top)
+	}
+	for i, id := range want {
+		if top[i].Identifier != id {
+			t.Fatalf("rank %d = %s, want %s", i, top[i].Identifier, id)
+		}
+	}
+	if top[0].Address != "0x1654653399040a61" || top[0].Name != "FlowToken" || top[0].TxCount != 900 {
+		t.Fatalf("top[0] = %+v", top[0])
+	}
+	if top[2].TxCount != 120 {
+		t.Fatalf("NonFungibleToken = %d, want 120 summed over both days", top[2].TxCount)
+	}
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"flowscan-clone/internal/models"
+)
+
+// EventCursor is a keyset position in a (block_height, transaction_index, event_index)
+// ordered event feed. It is serialized as "height:tx_index:event_index".
+type EventCursor struct {
+	BlockHeight uint64
+	TxIndex     int
+	EventIndex  int
+}
+
+func (c EventCursor) String() string {
+	return fmt.Sprintf("%d:%d:%d", c.BlockHeight, c.TxIndex, c.EventIndex)
+}
+
+// ParseEventCursor parses a cursor produced by EventCursor.String.
+func ParseEventCursor(s string) (*EventCursor, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid event cursor %q", s)
+	}
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event cursor height: %w", err)
+	}
+	txIndex, err := strconv.Atoi(parts[1])
+	if err != nil || txIndex < 0 {
+		return nil, fmt.Errorf("invalid event cursor tx index %q", parts[1])
+	}
+	eventIndex, err := strconv.Atoi(parts[2])
+	if err != nil || eventIndex < 0 {
+		return nil, fmt.Errorf("invalid event cursor event index %q", parts[2])
+	}
+	return &EventCursor{BlockHeight: height, TxIndex: txIndex, EventIndex: eventIndex}, nil
+}
+
+// contractEventsQuery builds the feed query for ListEventsByContract. The explicit
+// block_height bounds let the planner prune raw.events partitions outside the range.
+func contractEventsQuery(contractAddress, contractName, eventName string, fromHeight, toHeight uint64, limit int, cursor *EventCursor) (string, []interface{}) {
+	typePrefix := "A." + contractAddress + "." + contractName + "."
+	args := []interface{}{hexToBytes(contractAddress), fromHeight, toHeight}
+	clauses := []string{
+		"contract_address = $1",
+		"block_height >= $2",
+		"block_height <= $3",
+	}
+	if eventName != "" {
+		args = append(args, typePrefix+eventName)
+		clauses = append(clauses, fmt.Sprintf("type = $%d", len(args)))
+	} else {
+		args = append(args, typePrefix+"%")
+		clauses = append(clauses, fmt.Sprintf("type LIKE $%d", len(args)))
+	}
+	if cursor != nil {
+		args = append(args, cursor.BlockHeight, cursor.TxIndex, cursor.EventIndex)
+		n := len(args)
+		clauses = append(clauses, fmt.Sprintf("(block_height, transaction_index, event_index) < ($%d, $%d, $%d)", n-2, n-1, n))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT event_index AS id,
+		       encode(transaction_id, 'hex') AS transaction_id,
+		       COALESCE(transaction_index, 0), type, event_index,
+		       COALESCE(encode(contract_address, 'hex'), '') AS contract_address,
+		       '' AS contract_name,
+		       COALESCE(event_name, '') AS event_name,
+		       COALESCE(payload, '{}'::jsonb) AS payload,
+		       '{}'::jsonb AS values,
+		       block_height, timestamp, payload_compressed
+		FROM raw.events
+		WHERE %s
+		ORDER BY block_height DESC, transaction_index DESC, event_index DESC
+		LIMIT $%d`, strings.Join(clauses, " AND "), len(args))
+	return query, args
+}
+
+// ListEventsByContract returns events emitted by A.<contractAddress>.<contractName>
+// within [fromHeight, toHeight], newest first. eventName optionally restricts the
+// feed to a single event type. Pass the EventCursor of the last row to page.
+func (r *Repository) ListEventsByContract(ctx context.Context, contractAddress, contractName, eventName string, fromHeight, toHeight uint64, limit int, cursor *EventCursor) ([]models.Event, error) {
+	if toHeight < fromHeight {
+		return nil, fmt.Errorf("invalid range: to_height %d < from_height %d", toHeight, fromHeight)
+	}
+	query, args := contractEventsQuery(contractAddress, contractName, eventName, fromHeight, toHeight, limit, cursor)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.Event
+	for rows.Next() {
+		var e models.Event
+		var compressed []byte
+		if err := rows.Scan(&e.ID, &e.TransactionID, &e.TransactionIndex, &e.Type, &e.EventIndex, &e.ContractAddress, &e.ContractName,
+			&e.EventName, &e.Payload, &e.Values, &e.BlockHeight, &e.Timestamp, &compressed); err != nil {
+			return nil, err
+		}
+		if err := restoreEventPayload(&e, compressed); err != nil {
+			return nil, err
+		}
+		e.ContractName = contractName
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContractEventsQueryFiltersByEventName(t *testing.T) {
+	t.Parallel()
+
+	// One contract emitting several event types; each name narrows the feed to
+	// an exact type while the unfiltered query matches the contract prefix.
+	cases := []struct {
+		eventName string
+		wantType  string
+		wantOp    string
+	}{
+		{"", "A.1654653399040a61.FlowToken.%", "type LIKE $4"},
+		{"TokensDeposited", "A.1654653399040a61.FlowToken.TokensDeposited", "type = $4"},
+		{"TokensWithdrawn", "A.1654653399040a61.FlowToken.TokensWithdrawn", "type = $4"},
+		{"TokensMinted", "A.1654653399040a61.FlowToken.TokensMinted", "type = $4"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.eventName, func(t *testing.T) {
+			t.Parallel()
+			query, args := contractEventsQuery("1654653399040a61", "FlowToken", tc.eventName, 100, 200, 25, nil)
+			if !strings.Contains(query, tc.wantOp) {
+				t.Fatalf("query missing %q:\n%s", tc.wantOp, query)
+			}
+			if len(args) != 5 {
+				t.Fatalf("expected 5 args, got %d", len(args))
+			}
+			if got := args[3]; got != tc.wantType {
+				t.Fatalf("type arg = %v, want %s", got, tc.wantType)
+			}
+			if args[1] != uint64(100) || args[2] != uint64(200) {
+				t.Fatalf("height bounds = %v..%v, want 100..200", args[1], args[2])
+			}
+			if args[4] != 25 {
+				t.Fatalf("limit arg = %v, want 25", args[4])
+			}
+		})
+	}
+}
+
+func TestContractEventsQueryCursor(t *testing.T) {
+	t.Parallel()
+
+	cursor := &EventCursor{BlockHeight: 150, TxIndex: 3, EventIndex: 7}
+	query, args := contractEventsQuery("1654653399040a61", "FlowToken", "TokensDeposited", 100, 200, 10, cursor)
+	if !strings.Contains(query, "(block_height, transaction_index, event_index) < ($5, $6, $7)") {
+		t.Fatalf("query missing keyset predicate:\n%s", query)
+	}
+	if !strings.Contains(query, "LIMIT $8") {
+		t.Fatalf("query missing limit placeholder:\n%s", query)
+	}
+	if args[4] != uint64(150) || args[5] != 3 || args[6] != 7 || args[7] != 10 {
+		t.Fatalf("unexpected cursor args: %v", args[4:])
+	}
+}
+
+func TestParseEventCursorRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	in := EventCursor{BlockHeight: 85000123, TxIndex: 4, EventIndex: 12}
+	got, err := ParseEventCursor(in.String())
+	if err != nil {
+		t.Fatalf("ParseEventCursor: %v", err)
+	}
+	if *got != in {
+		t.Fatalf("round trip = %+v, want %+v", *got, in)
+	}
+
+	for _, bad := range []string{"", "1:2", "a:1:2", "1:-1:2", "1:2:x"} {
+		if _, err := ParseEventCursor(bad); err == nil {
+			t.Errorf("ParseEventCursor(%q) expected error", bad)
+		}
+	}
+}
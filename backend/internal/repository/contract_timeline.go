@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+// Contract timeline entry kinds.
+const (
+	ContractTimelineDeploy = "deploy"
+	ContractTimelineUpdate = "update"
+	ContractTimelineRemove = "remove"
+)
+
+// maxContractTimelineRows caps the changes loaded for one contract.
+const maxContractTimelineRows = 1000
+
+// ContractTimelineEntry is one deploy, update or removal of a contract. Version
+// is the code version in effect after a deploy or update.
+type ContractTimelineEntry struct {
+	Kind          string    `json:"kind"`
+	Version       int       `json:"version,omitempty"`
+	BlockHeight   uint64    `json:"block_height"`
+	TransactionID string    `json:"transaction_id"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// InsertContractChanges records contract add/update/remove events. Re-processing
+// the same range is a no-op.
+func (r *Repository) InsertContractChanges(ctx context.Context, changes []models.ContractChange) error {
+	for _, c := range changes {
+		var ts interface{}
+		if !c.Timestamp.IsZero() {
+			ts = c.Timestamp
+		}
+		if _, err := r.db.Exec(ctx, `
+			INSERT INTO app.contract_changes (address, name, block_height, transaction_id, event_index, change, timestamp)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT DO NOTHING`,
+			hexToBytes(c.Address), c.Name, int64(c.BlockHeight), hexToBytes(c.TransactionID), c.EventIndex, c.Change, ts,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildContractTimeline orders changes by height and event index and numbers
+// the code versions: every add or update starts a new version.
+func BuildContractTimeline(changes []models.ContractChange) []ContractTimelineEntry {
+	sorted := append([]models.ContractChange(nil), changes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].BlockHeight != sorted[j].BlockHeight {
+			return sorted[i].BlockHeight < sorted[j].BlockHeight
+		}
+		return sorted[i].EventIndex < sorted[j].EventIndex
+	})
+
+	out := make([]ContractTimelineEntry, 0, len(sorted))
+	version := 0
+	for _, c := range sorted {
+		e := ContractTimelineEntry{BlockHeight: c.BlockHeight, TransactionID: c.TransactionID, Timestamp: c.Timestamp}
+		switch c.Change {
+		case models.ContractChangeAdded:
+			version++
+			e.Kind, e.Version = ContractTimelineDeploy, version
+		case models.ContractChangeUpdated:
+			version++
+			e.Kind, e.Version = ContractTimelineUpdate, version
+		case models.ContractChangeRemoved:
+			e.Kind = ContractTimelineRemove
+		default:
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// contractVersionsAsChanges reads app.contract_versions as changes for
+// contracts indexed before app.contract_changes existed. Version 1 is the
+// deploy; removals are not recorded there.
+func contractVersionsAsChanges(versions []models.ContractVersion) []models.ContractChange {
+	out := make([]models.ContractChange, 0, len(versions))
+	for _, v := range versions {
+		change := models.ContractChangeUpdated
+		if v.Version == 1 {
+			change = models.ContractChangeAdded
+		}
+		out = append(out, models.ContractChange{
+			Address:       v.Address,
+			Name:          v.Name,
+			Change:        change,
+			BlockHeight:   v.BlockHeight,
+			TransactionID: v.TransactionID,
+			EventIndex:    v.Version,
+			Timestamp:     v.CreatedAt,
+		})
+	}
+	return out
+}
+
+// GetContractTimeline returns every deploy, update and removal of
+// A.<address>.<name>, oldest first, with the transaction that caused it.
+func (r *Repository) GetContractTimeline(ctx context.Context, address, name string) ([]ContractTimelineEntry, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT encode(c.address, 'hex'), c.name, c.change, c.block_height,
+		       encode(c.transaction_id, 'hex'), c.event_index,
+		       COALESCE(c.timestamp, b.timestamp)
+		FROM app.contract_changes c
+		LEFT JOIN raw.blocks b ON b.height = c.block_height
+		WHERE c.address = $1 AND c.name = $2
+		ORDER BY c.block_height, c.event_index
+		LIMIT $3`, hexToBytes(address), name, maxContractTimelineRows)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []models.ContractChange
+	for rows.Next() {
+		var c models.ContractChange
+		var h int64
+		var ts *time.Time
+		if err := rows.Scan(&c.Address, &c.Name, &c.Change, &h, &c.TransactionID, &c.EventIndex, &ts); err != nil {
+			return nil, err
+		}
+		c.BlockHeight = uint64(h)
+		if ts != nil {
+			c.Timestamp = *ts
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 {
+		versions, err := r.ListContractVersions(ctx, address, name, maxContractTimelineRows, 0)
+		if err != nil {
+			return nil, err
+		}
+		changes = contractVersionsAsChanges(versions)
+	}
+	return BuildContractTimeline(changes), nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultCopyChunkRows caps the rows sent per COPY / UNNEST statement in
+// SaveBatch. Chunks share the batch's transaction, so a batch still commits
+// or rolls back as a whole. This bounds statement size, not the batch's
+// memory: COPY already streams rows, and the batch and its per-transaction
+// script plan are held in full either way.
+const defaultCopyChunkRows = 10000
+
+// copyChunkRowsFromEnv reads DB_COPY_CHUNK_ROWS; 0 sends each table in one
+// statement.
+func copyChunkRowsFromEnv() int {
+	if v := strings.TrimSpace(os.Getenv("DB_COPY_CHUNK_ROWS")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return defaultCopyChunkRows
+}
+
+// forEachChunk calls fn with consecutive [start, end) windows covering n rows,
+// each at most size rows (one window when size <= 0), and stops at the first
+// error.
+func forEachChunk(n, size int, fn func(start, end int) error) error {
+	if size <= 0 {
+		size = n
+	}
+	for start := 0; start < n; start += size {
+		end := min(start+size, n)
+		if err := fn(start, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
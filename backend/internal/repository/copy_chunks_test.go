@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+func TestForEachChunkSplitsAndCoversAllRows(t *testing.T) {
+	t.Parallel()
+
+	rows := make([]int, 25)
+	for i := range rows {
+		rows[i] = i
+	}
+	var windows [][2]int
+	var written []int
+	err := forEachChunk(len(rows), 10, func(start, end int) error {
+		windows = append(windows, [2]int{start, end})
+		written = append(written, rows[start:end]...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][2]int{{0, 10}, {10, 20}, {20, 25}}; !reflect.DeepEqual(windows, want) {
+		t.Fatalf("windows = %v; want %v", windows, want)
+	}
+	if !reflect.DeepEqual(written, rows) {
+		t.Fatalf("written = %v; want every row once, in order", written)
+	}
+}
+
+func TestForEachChunkUnbounded(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{0, -1, 100} {
+		calls := 0
+		_ = forEachChunk(25, size, func(start, end int) error {
+			calls++
+			if start != 0 || end != 25 {
+				t.Fatalf("size %d: window [%d, %d)", size, start, end)
+			}
+			return nil
+		})
+		if calls != 1 {
+			t.Fatalf("size %d: %d calls; want 1", size, calls)
+		}
+	}
+	if err := forEachChunk(0, 10, func(int, int) error { t.Fatal("called for an empty batch"); return nil }); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestForEachChunkStopsOnError(t *testing.T) {
+	t.Parallel()
+
+	boom := errors.New("copy failed")
+	calls := 0
+	err := forEachChunk(30, 10, func(start, end int) error {
+		calls++
+		if start == 10 {
+			return boom
+		}
+		return nil
+	})
+	if !errors.Is(err, boom) || calls != 2 {
+		t.Fatalf("err = %v after %d calls; want copy failed after 2", err, calls)
+	}
+}
+
+// TestSaveBatchChunked needs a database with the schema applied. With a chunk
+// size smaller than the batch, every transaction, lookup row and event must
+// still land.
+func TestSaveBatchChunked(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	repo, err := NewRepository(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	repo.copyChunkRows = 3
+	ctx := context.Background()
+
+	const height = 1_999_999_002
+	cleanup := func() {
+		for _, q := range []string{
+			`DELETE FROM raw.events WHERE block_height = $1`,
+			`DELETE FROM raw.transactions WHERE block_height = $1`,
+			`DELETE FROM raw.tx_lookup WHERE block_height = $1`,
+			`DELETE FROM raw.block_lookup WHERE height = $1`,
+			`DELETE FROM raw.blocks WHERE height = $1`,
+		} {
+			if _, err := repo.db.Exec(ctx, q, height); err != nil {
+				t.Logf("cleanup: %v", err)
+			}
+		}
+		repo.db.Exec(ctx, `DELETE FROM app.indexing_checkpoints WHERE service_name = 'test_save_batch_chunked'`)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	const nTxs, eventsPerTx = 8, 2
+	ts := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	txID := func(i int) string { return fmt.Sprintf("%064x", 0xc4c400+i) }
+	var txs []models.Transaction
+	var events []models.Event
+	for i := 0; i < nTxs; i++ {
+		txs = append(txs, models.Transaction{
+			ID: txID(i), BlockHeight: height, TransactionIndex: i, Timestamp: ts, Status: "SEALED",
+			PayerAddress: "1654653399040a61", ProposerAddress: "1654653399040a61", Authorizers: []string{"1654653399040a61"},
+			Script: fmt.Sprintf("transaction { prepare(acct: &Account) { log(%d) } }", i),
+		})
+		for j := 0; j < eventsPerTx; j++ {
+			events = append(events, models.Event{
+				TransactionID: txID(i), TransactionIndex: i, EventIndex: j, BlockHeight: height, Timestamp: ts,
+				Type: "A.1654653399040a61.FlowToken.TokensDeposited", EventName: "TokensDeposited", Payload: []byte(`{"amount":"1.0"}`),
+			})
+		}
+	}
+	block := &models.Block{
+		Height: height, ID: fmt.Sprintf("%064x", 0xc4c4), ParentID: fmt.Sprintf("%064x", 0xc4c3), Timestamp: ts,
+		TxCount: len(txs), EventCount: len(events),
+	}
+	if err := repo.SaveBatch(ctx, []*models.Block{block}, txs, events, "test_save_batch_chunked", height); err != nil {
+		t.Fatal(err)
+	}
+
+	for table, want := range map[string]int{
+		"raw.transactions": nTxs,
+		"raw.tx_lookup":    nTxs,
+		"raw.events":       nTxs * eventsPerTx,
+	} {
+		var got int
+		if err := repo.db.QueryRow(ctx, `SELECT COUNT(*) FROM `+table+` WHERE block_height = $1`, height).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s has %d rows; want %d", table, got, want)
+		}
+	}
+}
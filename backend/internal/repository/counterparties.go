@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CounterpartyTokenAmount is the volume exchanged with a counterparty in one token.
+type CounterpartyTokenAmount struct {
+	TokenAddress   string `json:"token_address"`
+	ContractName   string `json:"contract_name"`
+	SentAmount     string `json:"sent_amount"`
+	ReceivedAmount string `json:"received_amount"`
+}
+
+// Counterparty aggregates the FT transfers between an account and another address.
+type Counterparty struct {
+	Address       string                    `json:"address"`
+	TransferCount int64                     `json:"transfer_count"`
+	SentCount     int64                     `json:"sent_count"`
+	ReceivedCount int64                     `json:"received_count"`
+	Tokens        []CounterpartyTokenAmount `json:"tokens"`
+}
+
+// counterpartyTokenRow is one (counterparty, token) aggregate as returned by SQL.
+type counterpartyTokenRow struct {
+	Counterparty   string
+	TokenAddress   string
+	ContractName   string
+	SentCount      int64
+	ReceivedCount  int64
+	SentAmount     string
+	ReceivedAmount string
+}
+
+// groupCounterparties folds per-token rows into one entry per counterparty,
+// ordered by total transfer count (desc) then address. Tokens within a
+// counterparty are ordered the same way.
+func groupCounterparties(rows []counterpartyTokenRow) []Counterparty {
+	byAddr := make(map[string]*Counterparty)
+	tokenCounts := make(map[string]map[string]int64)
+	var order []string
+	for _, row := range rows {
+		c, ok := byAddr[row.Counterparty]
+		if !ok {
+			c = &Counterparty{Address: row.Counterparty}
+			byAddr[row.Counterparty] = c
+			tokenCounts[row.Counterparty] = make(map[string]int64)
+			order = append(order, row.Counterparty)
+		}
+		c.SentCount += row.SentCount
+		c.ReceivedCount += row.ReceivedCount
+		c.TransferCount += row.SentCount + row.ReceivedCount
+		c.Tokens = append(c.Tokens, CounterpartyTokenAmount{
+			TokenAddress:   row.TokenAddress,
+			ContractName:   row.ContractName,
+			SentAmount:     row.SentAmount,
+			ReceivedAmount: row.ReceivedAmount,
+		})
+		tokenCounts[row.Counterparty][row.TokenAddress+"."+row.ContractName] = row.SentCount + row.ReceivedCount
+	}
+
+	out := make([]Counterparty, 0, len(order))
+	for _, addr := range order {
+		c := byAddr[addr]
+		counts := tokenCounts[addr]
+		sort.SliceStable(c.Tokens, func(i, j int) bool {
+			ci := counts[c.Tokens[i].TokenAddress+"."+c.Tokens[i].ContractName]
+			cj := counts[c.Tokens[j].TokenAddress+"."+c.Tokens[j].ContractName]
+			if ci != cj {
+				return ci > cj
+			}
+			return c.Tokens[i].ContractName < c.Tokens[j].ContractName
+		})
+		out = append(out, *c)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].TransferCount != out[j].TransferCount {
+			return out[i].TransferCount > out[j].TransferCount
+		}
+		return out[i].Address < out[j].Address
+	})
+	return out
+}
+
+// GetTopCounterparties returns the addresses an account most frequently exchanges
+// fungible tokens with. direction is "sent", "received" or "both" (default);
+// a non-zero since restricts the window to transfers at or after that time.
+func (r *Repository) GetTopCounterparties(ctx context.Context, address, direction string, since time.Time, limit int) ([]Counterparty, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	var dirClause string
+	switch direction {
+	case "sent":
+		dirClause = "ft.from_address = $1"
+	case "received":
+		dirClause = "ft.to_address = $1"
+	case "", "both":
+		dirClause = "(ft.from_address = $1 OR ft.to_address = $1)"
+	default:
+		return nil, fmt.Errorf("invalid direction %q", direction)
+	}
+	var sinceArg interface{}
+	if !since.IsZero() {
+		sinceArg = since
+	}
+
+	query := `
+		WITH t AS (
+			SELECT
+				CASE WHEN ft.from_address = $1 THEN ft.to_address ELSE ft.from_address END AS counterparty,
+				ft.from_address = $1 AS sent,
+				ft.token_contract_address,
+				COALESCE(ft.contract_name, '') AS contract_name,
+				ft.amount
+			FROM app.ft_transfers ft
+			WHERE ` + dirClause + `
+			  AND ($2::timestamptz IS NULL OR ft.timestamp >= $2)
+		),
+		per_token AS (
+			SELECT
+				counterparty,
+				token_contract_address,
+				contract_name,
+				COUNT(*) FILTER (WHERE sent) AS sent_count,
+				COUNT(*) FILTER (WHERE NOT sent) AS received_count,
+				COALESCE(SUM(amount) FILTER (WHERE sent), 0) AS sent_amount,
+				COALESCE(SUM(amount) FILTER (WHERE NOT sent), 0) AS received_amount
+			FROM t
+			WHERE counterparty IS NOT NULL AND counterparty <> $1
+			GROUP BY 1, 2, 3
+		),
+		top AS (
+			SELECT counterparty, SUM(sent_count + received_count) AS total
+			FROM per_token
+			GROUP BY counterparty
+			ORDER BY total DESC, counterparty
+			LIMIT $3
+		)
+		SELECT
+			encode(p.counterparty, 'hex'),
+			COALESCE(encode(p.token_contract_address, 'hex'), ''),
+			p.contract_name,
+			p.sent_count,
+			p.received_count,
+			p.sent_amount::text,
+			p.received_amount::text
+		FROM per_token p
+		JOIN top ON top.counterparty = p.counterparty`
+
+	rows, err := r.db.Query(ctx, query, hexToBytes(address), sinceArg, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var agg []counterpartyTokenRow
+	for rows.Next() {
+		var row counterpartyTokenRow
+		if err := rows.Scan(&row.Counterparty, &row.TokenAddress, &row.ContractName, &row.SentCount, &row.ReceivedCount, &row.SentAmount, &row.ReceivedAmount); err != nil {
+			return nil, err
+		}
+		agg = append(agg, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return groupCounterparties(agg), nil
+}
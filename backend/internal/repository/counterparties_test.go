@@ -0,0 +1,46 @@
+package repository
+
+import "testing"
+
+func TestGroupCounterpartiesGroupsAndOrders(t *testing.T) {
+	t.Parallel()
+
+	rows := []counterpartyTokenRow{
+		{Counterparty: "aa", TokenAddress: "1654653399040a61", ContractName: "FlowToken", SentCount: 1, ReceivedCount: 1, SentAmount: "1.5", ReceivedAmount: "2"},
+		{Counterparty: "bb", TokenAddress: "1654653399040a61", ContractName: "FlowToken", SentCount: 3, ReceivedCount: 0, SentAmount: "30", ReceivedAmount: "0"},
+		{Counterparty: "aa", TokenAddress: "b19436aae4d94622", ContractName: "FiatToken", SentCount: 0, ReceivedCount: 4, SentAmount: "0", ReceivedAmount: "400"},
+		{Counterparty: "cc", TokenAddress: "1654653399040a61", ContractName: "FlowToken", SentCount: 2, ReceivedCount: 1, SentAmount: "5", ReceivedAmount: "1"},
+	}
+
+	got := groupCounterparties(rows)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 counterparties, got %d", len(got))
+	}
+
+	// aa: 6 transfers, then bb and cc tie at 3 and sort by address.
+	wantOrder := []string{"aa", "bb", "cc"}
+	for i, addr := range wantOrder {
+		if got[i].Address != addr {
+			t.Fatalf("position %d: got %s, want %s", i, got[i].Address, addr)
+		}
+	}
+
+	aa := got[0]
+	if aa.TransferCount != 6 || aa.SentCount != 1 || aa.ReceivedCount != 5 {
+		t.Fatalf("aa counts = %+v", aa)
+	}
+	if len(aa.Tokens) != 2 || aa.Tokens[0].ContractName != "FiatToken" || aa.Tokens[0].ReceivedAmount != "400" {
+		t.Fatalf("aa tokens not grouped/ordered by count: %+v", aa.Tokens)
+	}
+	if aa.Tokens[1].SentAmount != "1.5" {
+		t.Fatalf("aa FlowToken sent amount = %s", aa.Tokens[1].SentAmount)
+	}
+}
+
+func TestGroupCounterpartiesEmpty(t *testing.T) {
+	t.Parallel()
+
+	if got := groupCounterparties(nil); len(got) != 0 {
+		t.Fatalf("expected no counterparties, got %v", got)
+	}
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DailyMetricPoint is one day of a single metric. Value is a decimal string so
+// counts and token amounts (fees) share the same shape.
+type DailyMetricPoint struct {
+	Date  string `json:"date"`
+	Value string `json:"value"`
+}
+
+// dailyMetricSource is the pre-aggregated table and column holding a metric.
+type dailyMetricSource struct {
+	table  string
+	column string
+}
+
+// dailyMetricSources maps public metric names to their source. Only names in
+// this map ever reach SQL.
+var dailyMetricSources = map[string]dailyMetricSource{
+	"tx_count":             {"app.daily_stats", "tx_count"},
+	"evm_tx_count":         {"app.daily_stats", "evm_tx_count"},
+	"active_accounts":      {"app.daily_stats", "active_accounts"},
+	"new_contracts":        {"app.daily_stats", "new_contracts"},
+	"failed_tx_count":      {"app.daily_stats", "failed_tx_count"},
+	"total_gas_used":       {"app.daily_stats", "total_gas_used"},
+	"accounts_created":     {"app.daily_stats", "accounts_created"},
+	"new_accounts":         {"analytics.daily_metrics", "new_accounts"},
+	"coa_new_accounts":     {"analytics.daily_metrics", "coa_new_accounts"},
+	"evm_active_addresses": {"analytics.daily_metrics", "evm_active_addresses"},
+	"defi_swap_count":      {"analytics.daily_metrics", "defi_swap_count"},
+	"defi_unique_traders":  {"analytics.daily_metrics", "defi_unique_traders"},
+	"bridge_to_evm_txs":    {"analytics.daily_metrics", "bridge_to_evm_txs"},
+	"contract_updates":     {"analytics.daily_metrics", "contract_updates"},
+}
+
+// feesMetric has no pre-aggregated column; it is summed from app.tx_metrics.
+const feesMetric = "fees"
+
+// IsDailyMetric reports whether metric can be passed to GetAnalyticsDailyMetrics.
+func IsDailyMetric(metric string) bool {
+	_, ok := dailyMetricSources[metric]
+	return ok || metric == feesMetric
+}
+
+// DailyMetricNames lists the supported metric names in sorted order.
+func DailyMetricNames() []string {
+	names := make([]string, 0, len(dailyMetricSources)+1)
+	for name := range dailyMetricSources {
+		names = append(names, name)
+	}
+	names = append(names, feesMetric)
+	sort.Strings(names)
+	return names
+}
+
+// dailyMetricQuery builds the series query for a whitelisted metric.
+func dailyMetricQuery(metric string) (string, error) {
+	src, ok := dailyMetricSources[metric]
+	if !ok {
+		return "", fmt.Errorf("unknown daily metric %q", metric)
+	}
+	return fmt.Sprintf(`
+		WITH dates AS (
+			SELECT generate_series($1::date, $2::date, '1 day'::interval)::date AS date
+		)
+		SELECT d.date::text, COALESCE(m.%s, 0)::text
+		FROM dates d
+		LEFT JOIN %s m ON m.date = d.date
+		ORDER BY d.date ASC`, src.column, src.table), nil
+}
+
+// GetAnalyticsDailyMetrics returns one value per day in [from, to] for metric,
+// with zero for days that have no row. metric must satisfy IsDailyMetric.
+func (r *Repository) GetAnalyticsDailyMetrics(ctx context.Context, metric string, from, to time.Time) ([]DailyMetricPoint, error) {
+	if metric == feesMetric {
+		// Whole UTC days, with to inclusive like the date series below.
+		fromDay := from.UTC().Truncate(24 * time.Hour)
+		toDay := to.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+		points, err := r.GetFeeMetricPoints(ctx, fromDay, toDay, "daily")
+		if err != nil {
+			return nil, err
+		}
+		out := make([]DailyMetricPoint, 0, len(points))
+		for _, p := range points {
+			out = append(out, DailyMetricPoint{Date: p.Time.UTC().Format("2006-01-02"), Value: p.Number})
+		}
+		return out, nil
+	}
+
+	query, err := dailyMetricQuery(metric)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Query(ctx, query, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]DailyMetricPoint, 0)
+	for rows.Next() {
+		var p DailyMetricPoint
+		if err := rows.Scan(&p.Date, &p.Value); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
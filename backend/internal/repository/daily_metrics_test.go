@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestDailyMetricQuery(t *testing.T) {
+	t.Parallel()
+
+	q, err := dailyMetricQuery("tx_count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(q, "COALESCE(m.tx_count, 0)") || !strings.Contains(q, "LEFT JOIN app.daily_stats m") {
+		t.Errorf("unexpected query: %s", q)
+	}
+
+	q, err = dailyMetricQuery("evm_active_addresses")
+	if err != nil || !strings.Contains(q, "LEFT JOIN analytics.daily_metrics m") {
+		t.Errorf("evm_active_addresses: %v %s", err, q)
+	}
+
+	for _, bad := range []string{"", "fees", "tx_count; DROP TABLE app.daily_stats", "date"} {
+		if _, err := dailyMetricQuery(bad); err == nil {
+			t.Errorf("dailyMetricQuery(%q) should fail", bad)
+		}
+	}
+}
+
+func TestDailyMetricNames(t *testing.T) {
+	t.Parallel()
+
+	names := DailyMetricNames()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("names not sorted: %v", names)
+	}
+	for _, want := range []string{"tx_count", "active_accounts", "fees", "new_contracts", "evm_tx_count"} {
+		if !IsDailyMetric(want) {
+			t.Errorf("%s should be a daily metric", want)
+		}
+	}
+	if IsDailyMetric("updated_at") {
+		t.Error("updated_at must not be exposed")
+	}
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// dailyStatsHeightMargin widens the height bounds derived from block timestamps.
+// Flow block timestamps are not strictly monotonic, so a block just outside the
+// bounds can still belong to the window; the timestamp filter keeps the result exact.
+const dailyStatsHeightMargin = 1000
+
+// utcDayWindow returns the half-open [from, to) range of whole UTC days covering
+// minTs..maxTs.
+func utcDayWindow(minTs, maxTs time.Time) (time.Time, time.Time) {
+	from := minTs.UTC().Truncate(24 * time.Hour)
+	to := maxTs.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	return from, to
+}
+
+// widenHeightBounds applies dailyStatsHeightMargin to [lo, hi).
+func widenHeightBounds(lo, hi uint64) (uint64, uint64) {
+	if lo > dailyStatsHeightMargin {
+		lo -= dailyStatsHeightMargin
+	} else {
+		lo = 0
+	}
+	return lo, hi + dailyStatsHeightMargin
+}
+
+// dailyStatsBounds resolves the UTC days touched by blocks in [fromHeight, toHeight)
+// and the height range covering those whole days. Everything comes from
+// raw.block_lookup via its height and timestamp indexes, so no raw partition is scanned.
+func (r *Repository) dailyStatsBounds(ctx context.Context, fromHeight, toHeight uint64) (dayFrom, dayTo time.Time, lo, hi uint64, ok bool, err error) {
+	var minTs, maxTs *time.Time
+	if err = r.db.QueryRow(ctx, `
+		SELECT MIN(timestamp), MAX(timestamp)
+		FROM raw.block_lookup
+		WHERE height >= $1 AND height < $2`, fromHeight, toHeight).Scan(&minTs, &maxTs); err != nil {
+		return
+	}
+	if minTs == nil || maxTs == nil {
+		return
+	}
+	dayFrom, dayTo = utcDayWindow(*minTs, *maxTs)
+
+	// First block at or after each day boundary. When no block exists past dayTo
+	// (the window includes today) fall back to the indexed tip.
+	var loH, hiH *int64
+	if err = r.db.QueryRow(ctx, `
+		SELECT
+			(SELECT height FROM raw.block_lookup WHERE timestamp >= $1 ORDER BY timestamp LIMIT 1),
+			COALESCE(
+				(SELECT height FROM raw.block_lookup WHERE timestamp >= $2 ORDER BY timestamp LIMIT 1),
+				(SELECT MAX(height) + 1 FROM raw.block_lookup)
+			)`, dayFrom, dayTo).Scan(&loH, &hiH); err != nil {
+		return
+	}
+	if loH == nil || hiH == nil {
+		return
+	}
+	lo, hi = widenHeightBounds(uint64(*loH), uint64(*hiH))
+	ok = true
+	return
+}
+
+// RefreshDailyStatsRange idempotently refreshes daily_stats for all UTC dates
+// touched by blocks in the half-open range [fromHeight, toHeight).
+//
+// Each touched date is recounted in full, so retries and overlapping ranges are
+// safe. The dates and their height bounds are resolved from raw.block_lookup,
+// so the aggregation only reads the raw/app partitions covering those days
+// instead of scanning raw.blocks by DATE(timestamp). This keeps the refresh
+// cheap enough to run on small live-deriver chunks.
+//
+// The per-type event rollup (app.daily_event_type_counts) and the per-contract
+// import rollup (app.daily_contract_tx_counts) are refreshed for the same days
+// on a best-effort basis; backfill_daily_stats fills them for history.
+func (r *Repository) RefreshDailyStatsRange(ctx context.Context, fromHeight, toHeight uint64) error {
+	dayFrom, dayTo, lo, hi, ok, err := r.dailyStatsBounds(ctx, fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("resolve daily stats bounds [%d, %d): %w", fromHeight, toHeight, err)
+	}
+	if !ok {
+		return nil
+	}
+
+	_, err = r.db.Exec(ctx, `
+		WITH tx_agg AS (
+			SELECT
+				(t.timestamp AT TIME ZONE 'UTC')::date AS date,
+				COUNT(*) AS tx_count,
+				COUNT(*) FILTER (WHERE t.is_evm = TRUE) AS evm_tx_count,
+				COALESCE(SUM(t.gas_used), 0) AS total_gas_used,
+				COUNT(DISTINCT t.proposer_address) AS active_accounts,
+				COUNT(*) FILTER (WHERE t.error_message IS NOT NULL AND t.error_message != '') AS failed_tx_count
+			FROM raw.transactions t
+			WHERE t.block_height >= $1 AND t.block_height < $2
+			  AND t.timestamp >= $3 AND t.timestamp < $4
+			GROUP BY 1
+		),
+		ft_agg AS (
+			SELECT (ft.timestamp AT TIME ZONE 'UTC')::date AS date, COUNT(*) AS cnt
+			FROM app.ft_transfers ft
+			WHERE ft.block_height >= $1 AND ft.block_height < $2
+			  AND ft.timestamp >= $3 AND ft.timestamp < $4
+			GROUP BY 1
+		),
+		nft_agg AS (
+			SELECT (nt.timestamp AT TIME ZONE 'UTC')::date AS date, COUNT(*) AS cnt
+			FROM app.nft_transfers nt
+			WHERE nt.block_height >= $1 AND nt.block_height < $2
+			  AND nt.timestamp >= $3 AND nt.timestamp < $4
+			GROUP BY 1
+		),
+		acct_agg AS (
+			SELECT (e.timestamp AT TIME ZONE 'UTC')::date AS date, COUNT(*) AS cnt
+			FROM raw.events e
+			WHERE e.block_height >= $1 AND e.block_height < $2
+			  AND e.timestamp >= $3 AND e.timestamp < $4
+			  AND e.type = 'flow.AccountCreated'
+			GROUP BY 1
+		)
+		INSERT INTO app.daily_stats (date, tx_count, evm_tx_count, total_gas_used, active_accounts, failed_tx_count, ft_transfer_count, nft_transfer_count, accounts_created, updated_at)
+		SELECT
+			a.date, a.tx_count, a.evm_tx_count, a.total_gas_used, a.active_accounts,
+			a.failed_tx_count,
+			COALESCE(f.cnt, 0),
+			COALESCE(n.cnt, 0),
+			COALESCE(ac.cnt, 0),
+			NOW()
+		FROM tx_agg a
+		LEFT JOIN ft_agg f ON f.date = a.date
+		LEFT JOIN nft_agg n ON n.date = a.date
+		LEFT JOIN acct_agg ac ON ac.date = a.date
+		ON CONFLICT (date) DO UPDATE SET
+			tx_count = EXCLUDED.tx_count,
+			evm_tx_count = EXCLUDED.evm_tx_count,
+			total_gas_used = EXCLUDED.total_gas_used,
+			active_accounts = EXCLUDED.active_accounts,
+			failed_tx_count = EXCLUDED.failed_tx_count,
+			ft_transfer_count = EXCLUDED.ft_transfer_count,
+			nft_transfer_count = EXCLUDED.nft_transfer_count,
+			accounts_created = EXCLUDED.accounts_created,
+			updated_at = NOW();
+	`, lo, hi, dayFrom, dayTo)
+	if err != nil {
+		return fmt.Errorf("refresh daily stats range [%d, %d): %w", fromHeight, toHeight, err)
+	}
+
+	// Best effort: contract stats should not block core daily tx stats.
+	if err := r.refreshDailyContractStatsRange(ctx, fromHeight, toHeight); err != nil {
+		log.Printf("[daily_stats] contract stats refresh skipped for range [%d,%d): %v", fromHeight, toHeight, err)
+	}
+	if err := r.refreshDailyEventTypeCountsRange(ctx, fromHeight, toHeight); err != nil {
+		log.Printf("[daily_stats] event type counts refresh skipped for range [%d,%d): %v", fromHeight, toHeight, err)
+	}
+	if err := r.refreshDailyContractTxCountsRange(ctx, fromHeight, toHeight); err != nil {
+		log.Printf("[daily_stats] contract tx counts refresh skipped for range [%d,%d): %v", fromHeight, toHeight, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+type fixtureBlock struct {
+	height uint64
+	ts     time.Time
+	txs    int
+}
+
+// dailyStatsFixture spans three UTC days with a few blocks whose timestamps run
+// slightly backwards, as happens on Flow around day boundaries.
+func dailyStatsFixture() []fixtureBlock {
+	base := time.Date(2025, 3, 1, 22, 0, 0, 0, time.UTC)
+	var blocks []fixtureBlock
+	for h := uint64(10000); h < 14000; h++ {
+		ts := base.Add(time.Duration(h-10000) * 40 * time.Second)
+		if h%97 == 0 {
+			ts = ts.Add(-90 * time.Second)
+		}
+		blocks = append(blocks, fixtureBlock{height: h, ts: ts, txs: int(h % 5)})
+	}
+	return blocks
+}
+
+func countByDay(blocks []fixtureBlock, keep func(fixtureBlock) bool) map[string]int {
+	out := map[string]int{}
+	for _, b := range blocks {
+		if keep(b) && b.txs > 0 {
+			out[b.ts.UTC().Format("2006-01-02")] += b.txs
+		}
+	}
+	return out
+}
+
+// firstHeightAtOrAfter mirrors the ORDER BY timestamp LIMIT 1 lookups on raw.block_lookup.
+func firstHeightAtOrAfter(blocks []fixtureBlock, ts time.Time) (uint64, bool) {
+	sorted := append([]fixtureBlock(nil), blocks...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ts.Before(sorted[j].ts) })
+	for _, b := range sorted {
+		if !b.ts.Before(ts) {
+			return b.height, true
+		}
+	}
+	return 0, false
+}
+
+func TestDailyStatsBoundsMatchFullScan(t *testing.T) {
+	t.Parallel()
+
+	blocks := dailyStatsFixture()
+	tip := blocks[len(blocks)-1].height
+
+	for _, rng := range [][2]uint64{{10000, 10010}, {11500, 11600}, {12100, 12101}, {13990, 14000}} {
+		var minTs, maxTs time.Time
+		for _, b := range blocks {
+			if b.height < rng[0] || b.height >= rng[1] {
+				continue
+			}
+			if minTs.IsZero() || b.ts.Before(minTs) {
+				minTs = b.ts
+			}
+			if b.ts.After(maxTs) {
+				maxTs = b.ts
+			}
+		}
+		dayFrom, dayTo := utcDayWindow(minTs, maxTs)
+
+		// Full scan: every transaction on the touched days, regardless of height.
+		want := countByDay(blocks, func(b fixtureBlock) bool {
+			return !b.ts.Before(dayFrom) && b.ts.Before(dayTo)
+		})
+
+		lo, _ := firstHeightAtOrAfter(blocks, dayFrom)
+		hi, ok := firstHeightAtOrAfter(blocks, dayTo)
+		if !ok {
+			hi = tip + 1
+		}
+		lo, hi = widenHeightBounds(lo, hi)
+		got := countByDay(blocks, func(b fixtureBlock) bool {
+			return b.height >= lo && b.height < hi && !b.ts.Before(dayFrom) && b.ts.Before(dayTo)
+		})
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("range %v: bounded aggregation %v != full scan %v", rng, got, want)
+		}
+	}
+}
+
+func TestUTCDayWindow(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("UTC+8", 8*3600)
+	from, to := utcDayWindow(
+		time.Date(2025, 3, 2, 7, 30, 0, 0, loc), // 2025-03-01T23:30Z
+		time.Date(2025, 3, 2, 9, 0, 0, 0, loc),  // 2025-03-02T01:00Z
+	)
+	if !from.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected window [%s, %s)", from, to)
+	}
+}
+
+func TestWidenHeightBoundsClampsAtZero(t *testing.T) {
+	t.Parallel()
+
+	if lo, hi := widenHeightBounds(10, 20); lo != 0 || hi != 20+dailyStatsHeightMargin {
+		t.Fatalf("got [%d, %d)", lo, hi)
+	}
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Sentinel errors returned (wrapped) by repository methods. Callers should use
+// errors.Is rather than comparing against pgx errors directly.
+var (
+	// ErrNotFound indicates the requested row does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict indicates a write violated a uniqueness constraint.
+	ErrConflict = errors.New("conflict")
+	// ErrInvalidInput indicates a caller-supplied filter or parameter the
+	// repository rejected before querying. Every ErrInvalid* sentinel below
+	// matches it, so handlers can map them all to 400.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrInvalidTxStatus indicates a transaction status filter outside the
+	// canonical models.TxStatus* set.
+	ErrInvalidTxStatus = invalidInput("invalid transaction status")
+	// ErrInvalidTxKind indicates a transaction kind filter other than
+	// TxKindTransfer.
+	ErrInvalidTxKind = invalidInput("invalid transaction kind")
+	// ErrInvalidAddressTxRole indicates an account transactions role filter
+	// other than payer, proposer, authorizer, sender or receiver.
+	ErrInvalidAddressTxRole = invalidInput("invalid address transaction role")
+	// ErrInvalidFTToken indicates a token that is not an "A.<address>.<Name>"
+	// contract identifier.
+	ErrInvalidFTToken = invalidInput("invalid ft token")
+	// ErrInvalidEventScope indicates a contract scope GetTopEventTypes cannot
+	// turn into an event type prefix.
+	ErrInvalidEventScope = invalidInput("invalid event scope")
+	// ErrInvalidTxBuckets indicates tx-per-block histogram bounds that are not
+	// non-negative and strictly increasing.
+	ErrInvalidTxBuckets = invalidInput("invalid tx count buckets")
+	// ErrUnknownExplainQuery indicates a query name outside the ExplainQuery
+	// whitelist.
+	ErrUnknownExplainQuery = invalidInput("unknown explain query")
+	// ErrInvalidExplainParams indicates ExplainQuery params that cannot be
+	// turned into the query's arguments.
+	ErrInvalidExplainParams = invalidInput("invalid explain params")
+)
+
+// invalidInputError is a sentinel that also matches ErrInvalidInput.
+type invalidInputError struct{ msg string }
+
+func invalidInput(msg string) error { return &invalidInputError{msg: msg} }
+
+func (e *invalidInputError) Error() string { return e.msg }
+
+func (e *invalidInputError) Is(target error) bool { return target == ErrInvalidInput }
+
+// pgUniqueViolation is the SQLSTATE for unique_violation.
+const pgUniqueViolation = "23505"
+
+// wrapDBErr maps driver-level errors onto the repository sentinels, keeping
+// the original error in the chain. what describes the looked-up entity and is
+// used as the message prefix (e.g. "block 123").
+func wrapDBErr(err error, what string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%s: %w: %w", what, ErrNotFound, err)
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return fmt.Errorf("%s: %w: %w", what, ErrConflict, err)
+	}
+	return err
+}
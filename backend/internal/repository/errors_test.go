@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestWrapDBErr(t *testing.T) {
+	t.Parallel()
+
+	if err := wrapDBErr(nil, "block 1"); err != nil {
+		t.Fatalf("nil error should stay nil, got %v", err)
+	}
+
+	notFound := wrapDBErr(fmt.Errorf("scan: %w", pgx.ErrNoRows), "block 1")
+	if !errors.Is(notFound, ErrNotFound) || !errors.Is(notFound, pgx.ErrNoRows) {
+		t.Fatalf("expected ErrNotFound wrapping pgx.ErrNoRows, got %v", notFound)
+	}
+
+	dup := wrapDBErr(&pgconn.PgError{Code: "23505", Message: "duplicate key"}, "label")
+	if !errors.Is(dup, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", dup)
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(dup, &pgErr) {
+		t.Fatal("expected original PgError to remain in the chain")
+	}
+
+	other := errors.New("connection reset")
+	if got := wrapDBErr(other, "block 1"); got != other || errors.Is(got, ErrNotFound) {
+		t.Fatalf("unexpected mapping for generic error: %v", got)
+	}
+}
+
+func TestListTransactionsFilteredRejectsUnknownStatus(t *testing.T) {
+	t.Parallel()
+
+	// Validation happens before any query, so no database is needed.
+	_, err := (&Repository{}).ListTransactionsFiltered(context.Background(), TransactionFilter{Status: "SEALED' OR '1'='1"})
+	if !errors.Is(err, ErrInvalidTxStatus) {
+		t.Fatalf("expected ErrInvalidTxStatus, got %v", err)
+	}
+}
+
+func TestListTransactionsFilteredRejectsUnknownKind(t *testing.T) {
+	t.Parallel()
+
+	_, err := (&Repository{}).ListTransactionsFiltered(context.Background(), TransactionFilter{Kind: "swap"})
+	if !errors.Is(err, ErrInvalidTxKind) {
+		t.Fatalf("expected ErrInvalidTxKind, got %v", err)
+	}
+}
+
+func TestInvalidInputSentinels(t *testing.T) {
+	t.Parallel()
+
+	for _, sentinel := range []error{
+		ErrInvalidTxStatus, ErrInvalidTxKind, ErrInvalidAddressTxRole, ErrInvalidFTToken,
+		ErrInvalidEventScope, ErrInvalidTxBuckets, ErrUnknownExplainQuery, ErrInvalidExplainParams,
+	} {
+		err := fmt.Errorf("%w: %q", sentinel, "bad")
+		if !errors.Is(err, ErrInvalidInput) || !errors.Is(err, sentinel) {
+			t.Errorf("%v should match both ErrInvalidInput and its sentinel", err)
+		}
+		if errors.Is(err, ErrNotFound) {
+			t.Errorf("%v should not match ErrNotFound", err)
+		}
+	}
+	if errors.Is(ErrInvalidTxKind, ErrInvalidTxStatus) {
+		t.Fatal("distinct invalid-input sentinels must not match each other")
+	}
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEventCountsForHeightsQueryIsRangeBounded(t *testing.T) {
+	t.Parallel()
+
+	for _, want := range []string{
+		"FROM raw.events",
+		"block_height BETWEEN $1 AND $2",
+		"block_height = ANY($3)",
+		"GROUP BY block_height",
+	} {
+		if !strings.Contains(eventCountsForHeightsQuery, want) {
+			t.Fatalf("event count query missing %q:\n%s", want, eventCountsForHeightsQuery)
+		}
+	}
+}
+
+func TestGetEventCountsForHeightsEmpty(t *testing.T) {
+	t.Parallel()
+
+	// No heights must not touch the database.
+	counts, err := (&Repository{}).GetEventCountsForHeights(context.Background(), nil)
+	if err != nil || len(counts) != 0 {
+		t.Fatalf("counts = %v, err = %v", counts, err)
+	}
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// EventPartition is a height-range child partition of raw.events.
+type EventPartition struct {
+	Schema     string
+	Name       string
+	FromHeight uint64 // inclusive
+	ToHeight   uint64 // exclusive
+}
+
+// QualifiedName returns the schema-qualified, quoted partition name.
+func (p EventPartition) QualifiedName() string {
+	return pgx.Identifier{p.Schema, p.Name}.Sanitize()
+}
+
+// eventPartitionsQuery lists the attached children of raw.events together with
+// their partition bound expression, e.g. "FOR VALUES FROM ('0') TO ('10000000')".
+const eventPartitionsQuery = `
+	SELECT cn.nspname, c.relname, pg_get_expr(c.relpartbound, c.oid)
+	FROM pg_inherits i
+	JOIN pg_class c ON c.oid = i.inhrelid
+	JOIN pg_namespace cn ON cn.oid = c.relnamespace
+	JOIN pg_class p ON p.oid = i.inhparent
+	JOIN pg_namespace pn ON pn.oid = p.relnamespace
+	WHERE pn.nspname = 'raw' AND p.relname = 'events'
+	ORDER BY c.relname`
+
+var rangeBoundRe = regexp.MustCompile(`^FOR VALUES FROM \('?(\d+)'?\) TO \('?(\d+)'?\)$`)
+
+// parseRangeBound extracts [from, to) from a range partition bound expression.
+// DEFAULT partitions and MINVALUE/MAXVALUE bounds are reported as !ok.
+func parseRangeBound(expr string) (from, to uint64, ok bool) {
+	m := rangeBoundRe.FindStringSubmatch(expr)
+	if m == nil {
+		return 0, 0, false
+	}
+	from, err := strconv.ParseUint(m[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	to, err = strconv.ParseUint(m[2], 10, 64)
+	if err != nil || to <= from {
+		return 0, 0, false
+	}
+	return from, to, true
+}
+
+// partitionsBelow returns the partitions that end at or below cutoffHeight.
+func partitionsBelow(parts []EventPartition, cutoffHeight uint64) []EventPartition {
+	var out []EventPartition
+	for _, p := range parts {
+		if p.ToHeight <= cutoffHeight {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// ListEventPartitions returns the height-range partitions attached to raw.events,
+// ordered by starting height.
+func (r *Repository) ListEventPartitions(ctx context.Context) ([]EventPartition, error) {
+	rows, err := r.db.Query(ctx, eventPartitionsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("list raw.events partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []EventPartition
+	for rows.Next() {
+		var schema, name, bound string
+		if err := rows.Scan(&schema, &name, &bound); err != nil {
+			return nil, err
+		}
+		from, to, ok := parseRangeBound(bound)
+		if !ok {
+			continue
+		}
+		out = append(out, EventPartition{Schema: schema, Name: name, FromHeight: from, ToHeight: to})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].FromHeight < out[j].FromHeight })
+	return out, nil
+}
+
+// ListEventPartitionsOlderThan returns raw.events partitions whose entire height
+// range was sealed before cutoff. The cutoff is mapped to a height through
+// raw.block_lookup; if no block at or after cutoff is indexed yet, nothing is
+// considered old enough.
+func (r *Repository) ListEventPartitionsOlderThan(ctx context.Context, cutoff time.Time) ([]EventPartition, uint64, error) {
+	var cutoffHeight uint64
+	err := r.db.QueryRow(ctx, `
+		SELECT height FROM raw.block_lookup
+		WHERE timestamp >= $1
+		ORDER BY timestamp ASC
+		LIMIT 1`, cutoff.UTC()).Scan(&cutoffHeight)
+	if err == pgx.ErrNoRows {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("resolve cutoff height: %w", err)
+	}
+
+	parts, err := r.ListEventPartitions(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return partitionsBelow(parts, cutoffHeight), cutoffHeight, nil
+}
+
+// DetachPartition detaches a partition from raw.events, leaving it as a
+// standalone table that can be archived (pg_dump) or dropped. If drop is true
+// the detached table is dropped in the same transaction.
+//
+// Note that raw.create_partitions uses CREATE TABLE IF NOT EXISTS, so a detached
+// but not dropped table blocks re-creating that range until it is removed.
+func (r *Repository) DetachPartition(ctx context.Context, p EventPartition, drop bool) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("ALTER TABLE raw.events DETACH PARTITION %s", p.QualifiedName())); err != nil {
+		return fmt.Errorf("detach %s: %w", p.QualifiedName(), err)
+	}
+	if drop {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("DROP TABLE %s", p.QualifiedName())); err != nil {
+			return fmt.Errorf("drop %s: %w", p.QualifiedName(), err)
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEventPartitionsQueryTargetsRawEvents(t *testing.T) {
+	t.Parallel()
+
+	for _, want := range []string{
+		"pg_get_expr(c.relpartbound, c.oid)",
+		"pn.nspname = 'raw' AND p.relname = 'events'",
+		"FROM pg_inherits i",
+	} {
+		if !strings.Contains(eventPartitionsQuery, want) {
+			t.Fatalf("partition listing query missing %q:\n%s", want, eventPartitionsQuery)
+		}
+	}
+}
+
+func TestParseRangeBound(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expr     string
+		from, to uint64
+		ok       bool
+	}{
+		{"FOR VALUES FROM ('0') TO ('10000000')", 0, 10000000, true},
+		{"FOR VALUES FROM ('80000000') TO ('90000000')", 80000000, 90000000, true},
+		{"FOR VALUES FROM (10000000) TO (20000000)", 10000000, 20000000, true},
+		{"DEFAULT", 0, 0, false},
+		{"FOR VALUES FROM (MINVALUE) TO ('10000000')", 0, 0, false},
+		{"FOR VALUES FROM ('20') TO ('10')", 0, 0, false},
+	}
+	for _, tc := range cases {
+		from, to, ok := parseRangeBound(tc.expr)
+		if ok != tc.ok || from != tc.from || to != tc.to {
+			t.Errorf("parseRangeBound(%q) = (%d, %d, %v), want (%d, %d, %v)", tc.expr, from, to, ok, tc.from, tc.to, tc.ok)
+		}
+	}
+}
+
+func TestPartitionsBelowKeepsOnlyFullyOlderRanges(t *testing.T) {
+	t.Parallel()
+
+	parts := []EventPartition{
+		{Schema: "raw", Name: "events_p0", FromHeight: 0, ToHeight: 10000000},
+		{Schema: "raw", Name: "events_p10000000", FromHeight: 10000000, ToHeight: 20000000},
+		{Schema: "raw", Name: "events_p20000000", FromHeight: 20000000, ToHeight: 30000000},
+	}
+	got := partitionsBelow(parts, 20000000)
+	if len(got) != 2 || got[1].Name != "events_p10000000" {
+		t.Fatalf("unexpected partitions below cutoff: %+v", got)
+	}
+	// A partition straddling the cutoff must be kept.
+	if got := partitionsBelow(parts, 25000000); len(got) != 2 {
+		t.Fatalf("straddling partition selected: %+v", got)
+	}
+	if got := parts[0].QualifiedName(); got != `"raw"."events_p0"` {
+		t.Fatalf("QualifiedName = %s", got)
+	}
+}
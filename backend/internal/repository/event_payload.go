@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"flowscan-clone/internal/models"
+)
+
+// Format byte prefixed to every raw.events.payload_compressed value, so rows
+// written under different settings can be read side by side. Rows written
+// without compression keep their payload in the JSONB payload column instead.
+const (
+	eventPayloadPlain byte = 0 // uncompressed JSON
+	eventPayloadGzip  byte = 1
+	eventPayloadZstd  byte = 2 // reserved; not decodable by this build
+)
+
+// eventPayloadCompressionFromEnv reads EVENT_PAYLOAD_COMPRESSION. It returns
+// the format to write, or eventPayloadPlain for the JSONB column (the default).
+func eventPayloadCompressionFromEnv() byte {
+	switch v := strings.ToLower(strings.TrimSpace(os.Getenv("EVENT_PAYLOAD_COMPRESSION"))); v {
+	case "", "none", "off", "false":
+		return eventPayloadPlain
+	case "gzip":
+		return eventPayloadGzip
+	case "zstd":
+		log.Printf("[repository] EVENT_PAYLOAD_COMPRESSION=zstd is not available in this build; using gzip")
+		return eventPayloadGzip
+	default:
+		log.Printf("[repository] Unknown EVENT_PAYLOAD_COMPRESSION=%q; storing payloads uncompressed", v)
+		return eventPayloadPlain
+	}
+}
+
+// gzipWriters reuses gzip writers; allocating one per event dominates the
+// cost of compressing small payloads.
+var gzipWriters = sync.Pool{New: func() any {
+	zw, _ := gzip.NewWriterLevel(io.Discard, gzip.BestSpeed)
+	return zw
+}}
+
+// encodeEventPayload returns payload prefixed with format and compressed by it.
+func encodeEventPayload(payload []byte, format byte) ([]byte, error) {
+	switch format {
+	case eventPayloadPlain:
+		return append([]byte{eventPayloadPlain}, payload...), nil
+	case eventPayloadGzip:
+		var buf bytes.Buffer
+		buf.WriteByte(eventPayloadGzip)
+		zw := gzipWriters.Get().(*gzip.Writer)
+		defer gzipWriters.Put(zw)
+		zw.Reset(&buf)
+		if _, err := zw.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("event payload format %d not supported", format)
+}
+
+// decodeEventPayload reverses encodeEventPayload.
+func decodeEventPayload(stored []byte) ([]byte, error) {
+	if len(stored) == 0 {
+		return nil, nil
+	}
+	switch stored[0] {
+	case eventPayloadPlain:
+		return stored[1:], nil
+	case eventPayloadGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(stored[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("decode event payload: %w", err)
+		}
+		defer zr.Close()
+		out, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, fmt.Errorf("decode event payload: %w", err)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("decode event payload: format %d not supported", stored[0])
+}
+
+// eventPayloadColumns returns the values for raw.events (payload,
+// payload_compressed): the sanitized JSON in one of them, nil in the other.
+func (r *Repository) eventPayloadColumns(raw []byte) (payload, compressed any, err error) {
+	sanitized := sanitizeJSONB(raw)
+	if sanitized == nil || r.payloadCompression == eventPayloadPlain {
+		return sanitized, nil, nil
+	}
+	enc, err := encodeEventPayload(sanitized.([]byte), r.payloadCompression)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, enc, nil
+}
+
+// restoreEventPayload replaces e.Payload with the decoded payload_compressed
+// value when the row has one.
+func restoreEventPayload(e *models.Event, compressed []byte) error {
+	if len(compressed) == 0 {
+		return nil
+	}
+	payload, err := decodeEventPayload(compressed)
+	if err != nil {
+		return fmt.Errorf("event %s/%d: %w", e.TransactionID, e.EventIndex, err)
+	}
+	e.Payload = payload
+	return nil
+}
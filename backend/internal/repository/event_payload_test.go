@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"flowscan-clone/internal/models"
+)
+
+// sampleEventPayload is a typical JSON-CDC FungibleToken.Deposited payload.
+const sampleEventPayload = `{"type":"Event","value":{"id":"A.f233dcee88fe0abe.FungibleToken.Deposited","fields":[` +
+	`{"name":"type","value":{"type":"String","value":"A.1654653399040a61.FlowToken.Vault"}},` +
+	`{"name":"amount","value":{"type":"UFix64","value":"0.00100000"}},` +
+	`{"name":"to","value":{"type":"Optional","value":{"type":"Address","value":"0xf919ee77447b7497"}}},` +
+	`{"name":"toUUID","value":{"type":"UInt64","value":"230225189"}},` +
+	`{"name":"depositedUUID","value":{"type":"UInt64","value":"211108040384738"}},` +
+	`{"name":"balanceAfter","value":{"type":"UFix64","value":"12.34500000"}}]}}`
+
+func TestEventPayloadRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []byte{eventPayloadPlain, eventPayloadGzip} {
+		stored, err := encodeEventPayload([]byte(sampleEventPayload), format)
+		if err != nil {
+			t.Fatalf("encode format %d: %v", format, err)
+		}
+		if stored[0] != format {
+			t.Fatalf("format byte = %d; want %d", stored[0], format)
+		}
+		var e models.Event
+		if err := restoreEventPayload(&e, stored); err != nil {
+			t.Fatalf("restore format %d: %v", format, err)
+		}
+		if string(e.Payload) != sampleEventPayload {
+			t.Fatalf("format %d payload = %s", format, e.Payload)
+		}
+	}
+
+	stored, _ := encodeEventPayload([]byte(sampleEventPayload), eventPayloadGzip)
+	if len(stored) >= len(sampleEventPayload) {
+		t.Errorf("gzip payload is %d bytes, plain %d", len(stored), len(sampleEventPayload))
+	}
+}
+
+func TestRestoreEventPayloadKeepsUncompressedRows(t *testing.T) {
+	t.Parallel()
+
+	e := models.Event{Payload: []byte(`{"a":1}`)}
+	if err := restoreEventPayload(&e, nil); err != nil {
+		t.Fatal(err)
+	}
+	if string(e.Payload) != `{"a":1}` {
+		t.Fatalf("payload = %s", e.Payload)
+	}
+	if err := restoreEventPayload(&e, []byte{eventPayloadZstd, 1, 2}); err == nil {
+		t.Fatal("expected an error for an undecodable format")
+	}
+}
+
+func TestEventPayloadColumns(t *testing.T) {
+	t.Parallel()
+
+	plain := &Repository{}
+	payload, compressed, err := plain.eventPayloadColumns([]byte(sampleEventPayload))
+	if err != nil || compressed != nil || !bytes.Equal(payload.([]byte), []byte(sampleEventPayload)) {
+		t.Fatalf("uncompressed columns = %v, %v, %v", payload, compressed, err)
+	}
+
+	gz := &Repository{payloadCompression: eventPayloadGzip}
+	payload, compressed, err = gz.eventPayloadColumns([]byte(sampleEventPayload))
+	if err != nil || payload != nil {
+		t.Fatalf("compressed columns = %v, %v, %v", payload, compressed, err)
+	}
+	back, err := decodeEventPayload(compressed.([]byte))
+	if err != nil || string(back) != sampleEventPayload {
+		t.Fatalf("decoded = %s, %v", back, err)
+	}
+
+	payload, compressed, err = gz.eventPayloadColumns(nil)
+	if err != nil || payload != nil || compressed != nil {
+		t.Fatalf("empty payload columns = %v, %v, %v", payload, compressed, err)
+	}
+}
+
+// BenchmarkEventPayloadGzip reports the stored size as a fraction of the
+// plain JSON for a block's worth of similar events.
+func BenchmarkEventPayloadGzip(b *testing.B) {
+	payloads := []string{
+		sampleEventPayload,
+		strings.Replace(sampleEventPayload, "Deposited", "Withdrawn", 1),
+		strings.Replace(sampleEventPayload, "0.00100000", "250.00000000", 1),
+	}
+	var plain, stored int
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := payloads[i%len(payloads)]
+		enc, err := encodeEventPayload([]byte(p), eventPayloadGzip)
+		if err != nil {
+			b.Fatal(err)
+		}
+		plain += len(p)
+		stored += len(enc)
+	}
+	b.ReportMetric(float64(stored)/float64(plain), "stored/plain")
+}
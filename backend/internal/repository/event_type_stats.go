@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultEventStatsDays is the window used by GetTopEventTypes when no height
+// range is given.
+const defaultEventStatsDays = 30
+
+// EventTypeCount is the number of events of one type over a window.
+type EventTypeCount struct {
+	Type  string  `json:"type"`
+	Count int64   `json:"count"`
+	Share float64 `json:"share"` // fraction of all events in the window (within the contract scope)
+}
+
+// TopEventTypes is the result of GetTopEventTypes. Counts come from the
+// app.daily_event_type_counts rollup, so they cover whole UTC days.
+type TopEventTypes struct {
+	DateFrom time.Time        // inclusive
+	DateTo   time.Time        // exclusive
+	Total    int64            // events of every type in scope
+	Types    []EventTypeCount // most frequent first
+}
+
+var (
+	eventScopeAddressPattern  = regexp.MustCompile(`^(0x)?[0-9a-fA-F]{1,16}$`)
+	eventScopeContractPattern = regexp.MustCompile(`^A\.([0-9a-fA-F]{16})\.([A-Za-z_][A-Za-z0-9_]*)$`)
+)
+
+// eventTypePrefix turns a ?contract= scope into an event type prefix. It
+// accepts an account address (every contract on it), a contract identifier
+// (A.<address>.<Name>) or "flow" for protocol events. Empty means no scope.
+func eventTypePrefix(contract string) (string, error) {
+	contract = strings.TrimSpace(contract)
+	switch {
+	case contract == "":
+		return "", nil
+	case strings.EqualFold(contract, "flow"):
+		return "flow.", nil
+	case eventScopeContractPattern.MatchString(contract):
+		m := eventScopeContractPattern.FindStringSubmatch(contract)
+		return "A." + strings.ToLower(m[1]) + "." + m[2] + ".", nil
+	case eventScopeAddressPattern.MatchString(contract):
+		addr := strings.ToLower(strings.TrimPrefix(contract, "0x"))
+		return "A." + strings.Repeat("0", 16-len(addr)) + addr + ".", nil
+	}
+	return "", fmt.Errorf("%w: contract %q, want an address, A.<address>.<Name> or flow", ErrInvalidEventScope, contract)
+}
+
+// GetTopEventTypes ranks event types by occurrence over the UTC days touched
+// by blocks in [fromHeight, toHeight], optionally scoped to a contract (see
+// eventTypePrefix). With both heights zero it covers the last 30 days. The
+// limit most frequent types are returned, ties broken by type; the total
+// over every type in scope is taken with a window sum before the LIMIT.
+func (r *Repository) GetTopEventTypes(ctx context.Context, fromHeight, toHeight uint64, contract string, limit int) (*TopEventTypes, error) {
+	prefix, err := eventTypePrefix(contract)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &TopEventTypes{}
+	if fromHeight == 0 && toHeight == 0 {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		out.DateFrom, out.DateTo = today.AddDate(0, 0, 1-defaultEventStatsDays), today.AddDate(0, 0, 1)
+	} else {
+		var minTs, maxTs *time.Time
+		if err := r.db.QueryRow(ctx, `
+			SELECT MIN(timestamp), MAX(timestamp)
+			FROM raw.block_lookup
+			WHERE height >= $1 AND height <= $2`, fromHeight, toHeight).Scan(&minTs, &maxTs); err != nil {
+			return nil, fmt.Errorf("resolve event stats window [%d, %d]: %w", fromHeight, toHeight, err)
+		}
+		if minTs == nil || maxTs == nil {
+			out.Types = []EventTypeCount{}
+			return out, nil
+		}
+		out.DateFrom, out.DateTo = utcDayWindow(*minTs, *maxTs)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT event_type, SUM(count)::bigint, (SUM(SUM(count)) OVER ())::bigint
+		FROM app.daily_event_type_counts
+		WHERE date >= $1::date AND date < $2::date
+		  AND ($3 = '' OR starts_with(event_type, $3))
+		GROUP BY event_type
+		ORDER BY SUM(count) DESC, event_type
+		LIMIT $4`, out.DateFrom, out.DateTo, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out.Types = []EventTypeCount{}
+	for rows.Next() {
+		var c EventTypeCount
+		if err := rows.Scan(&c.Type, &c.Count, &out.Total); err != nil {
+			return nil, err
+		}
+		out.Types = append(out.Types, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range out.Types {
+		if out.Total > 0 {
+			out.Types[i].Share = float64(out.Types[i].Count) / float64(out.Total)
+		}
+	}
+	return out, nil
+}
+
+// refreshDailyEventTypeCountsRange recounts app.daily_event_type_counts for
+// the UTC days touched by blocks in [fromHeight, toHeight). Whole days are
+// replaced, so retries and overlapping ranges are safe.
+func (r *Repository) refreshDailyEventTypeCountsRange(ctx context.Context, fromHeight, toHeight uint64) error {
+	dayFrom, dayTo, lo, hi, ok, err := r.dailyStatsBounds(ctx, fromHeight, toHeight)
+	if err != nil || !ok {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM app.daily_event_type_counts
+		WHERE date >= $1::date AND date < $2::date`, dayFrom, dayTo); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO app.daily_event_type_counts (date, event_type, count, updated_at)
+		SELECT (e.timestamp AT TIME ZONE 'UTC')::date, e.type, COUNT(*), NOW()
+		FROM raw.events e
+		WHERE e.block_height >= $1 AND e.block_height < $2
+		  AND e.timestamp >= $3 AND e.timestamp < $4
+		GROUP BY 1, 2`, lo, hi, dayFrom, dayTo); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestGetTopEventTypes needs a database with the schema applied. Its event
+// types sit under a contract scope no real events use.
+func TestGetTopEventTypes(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	repo, err := NewRepository(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	ctx := context.Background()
+
+	const scope = "A.00000000000e7e57."
+	cleanup := func() {
+		repo.db.Exec(ctx, `DELETE FROM app.daily_event_type_counts WHERE starts_with(event_type, $1)`, scope)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for typ, count := range map[string]int64{
+		"Token.Deposited":  400,
+		"Fees.Deducted":    300,
+		"Token.Withdrawn":  400,
+		"Account.KeyAdded": 95,
+		"NFT.Deposited":    100,
+		"Account.Created":  5,
+	} {
+		if _, err := repo.db.Exec(ctx, `
+			INSERT INTO app.daily_event_type_counts (date, event_type, count, updated_at)
+			VALUES ($1::date, $2, $3, NOW())`, today, scope+typ, count); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	top, err := repo.GetTopEventTypes(ctx, 0, 0, "A.00000000000e7e57.Token", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if top.Total != 800 || len(top.Types) != 2 {
+		t.Fatalf("contract scope = %+v", top)
+	}
+
+	top, err = repo.GetTopEventTypes(ctx, 0, 0, "0x00000000000e7e57", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if top.Total != 1300 {
+		t.Fatalf("total = %d, want 1300 over every type, not just the top 4", top.Total)
+	}
+	want := []string{"Token.Deposited", "Token.Withdrawn", "Fees.Deducted", "NFT.Deposited"} // ties broken by type
+	if len(top.Types) != len(want) {
+		t.Fatalf("got %d types, want %d: %+v", len(top.Types), len(want), top.Types)
+	}
+	for i, typ := range want {
+		if top.Types[i].Type != scope+typ {
+			t.Fatalf("rank %d = %s, want %s", i, top.Types[i].Type, scope+typ)
+		}
+	}
+	if math.Abs(top.Types[2].Share-300.0/1300.0) > 1e-9 {
+		t.Fatalf("Fees.Deducted share = %v", top.Types[2].Share)
+	}
+}
+
+func TestEventTypePrefix(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"":                             "",
+		"flow":                         "flow.",
+		"0x1654653399040a61":           "A.1654653399040a61.",
+		"0x1":                          "A.0000000000000001.",
+		"A.1654653399040A61.FlowToken": "A.1654653399040a61.FlowToken.",
+	} {
+		got, err := eventTypePrefix(in)
+		if err != nil || got != want {
+			t.Errorf("eventTypePrefix(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"A.xyz.FlowToken", "FlowToken", "A.1654653399040a61.Flow%"} {
+		if _, err := eventTypePrefix(bad); !errors.Is(err, ErrInvalidEventScope) {
+			t.Errorf("eventTypePrefix(%q) err = %v, want ErrInvalidEventScope", bad, err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// explainSampleAddress is used when a caller does not pass an address: the
+// FlowToken account, one of the busiest on mainnet.
+const explainSampleAddress = "1654653399040a61"
+
+// explainTimeout caps the EXPLAIN ANALYZE run, which executes the query.
+const explainTimeout = "15s"
+
+// explainQuery is a repository query that may be explained, with a builder
+// turning caller params (falling back to sample values) into its arguments.
+type explainQuery struct {
+	sql  func() string
+	args func(p ExplainParams) ([]any, error)
+}
+
+// explainQueries is the whitelist served by ExplainQuery, keyed by the name
+// of the repository method whose SQL it runs.
+var explainQueries = map[string]explainQuery{
+	"GetTransactionsByAddress": {
+		sql: func() string { return addressTransactionsSQL },
+		args: func(p ExplainParams) ([]any, error) {
+			limit, offset, err := p.limitOffset()
+			if err != nil {
+				return nil, err
+			}
+			roles, err := p.roles()
+			if err != nil {
+				return nil, err
+			}
+			return []any{hexToBytes(p.address()), limit + 1, offset, roles}, nil
+		},
+	},
+	"GetTransactionsByAddressCursor": {
+		sql: func() string { return addressTransactionsCursorSQL },
+		args: func(p ExplainParams) ([]any, error) {
+			limit, _, err := p.limitOffset()
+			if err != nil {
+				return nil, err
+			}
+			height, err := p.intParam("cursor_height", 0)
+			if err != nil {
+				return nil, err
+			}
+			var bh, id any
+			if height > 0 {
+				bh, id = height, hexToBytes(p.stringParam("cursor_tx_id", ""))
+			}
+			roles, err := p.roles()
+			if err != nil {
+				return nil, err
+			}
+			return []any{hexToBytes(p.address()), bh, id, limit, roles}, nil
+		},
+	},
+	"GetRecentTransactions": {
+		sql: func() string { return recentTransactionsSQL },
+		args: func(p ExplainParams) ([]any, error) {
+			limit, offset, err := p.limitOffset()
+			if err != nil {
+				return nil, err
+			}
+			return []any{limit, offset}, nil
+		},
+	},
+}
+
+// ExplainQueryNames returns the names accepted by ExplainQuery, sorted.
+func ExplainQueryNames() []string {
+	names := make([]string, 0, len(explainQueries))
+	for name := range explainQueries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsExplainQuery reports whether name is in the ExplainQuery whitelist.
+func IsExplainQuery(name string) bool {
+	_, ok := explainQueries[name]
+	return ok
+}
+
+// ExplainParams are the caller-supplied parameters of an explained query, as
+// decoded from JSON. Missing values fall back to sample ones.
+type ExplainParams map[string]any
+
+func (p ExplainParams) stringParam(key, def string) string {
+	if v, ok := p[key].(string); ok && strings.TrimSpace(v) != "" {
+		return strings.TrimSpace(v)
+	}
+	return def
+}
+
+func (p ExplainParams) intParam(key string, def int64) (int64, error) {
+	switch v := p[key].(type) {
+	case nil:
+		return def, nil
+	case float64:
+		if v < 0 || v != float64(int64(v)) {
+			return 0, fmt.Errorf("%w: %s must be a non-negative integer", ErrInvalidExplainParams, key)
+		}
+		return int64(v), nil
+	}
+	return 0, fmt.Errorf("%w: %s must be a number", ErrInvalidExplainParams, key)
+}
+
+func (p ExplainParams) address() string {
+	return strings.TrimPrefix(strings.ToLower(p.stringParam("address", explainSampleAddress)), "0x")
+}
+
+func (p ExplainParams) roles() ([]string, error) {
+	roles, err := addressTxRoles(strings.ToLower(p.stringParam("role", "")))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExplainParams, err)
+	}
+	return roles, nil
+}
+
+func (p ExplainParams) limitOffset() (limit, offset int64, err error) {
+	if limit, err = p.intParam("limit", 25); err != nil {
+		return 0, 0, err
+	}
+	if limit == 0 || limit > 1000 {
+		return 0, 0, fmt.Errorf("%w: limit must be between 1 and 1000", ErrInvalidExplainParams)
+	}
+	offset, err = p.intParam("offset", 0)
+	return limit, offset, err
+}
+
+// explainStatement returns the EXPLAIN statement and arguments for the named
+// query.
+func explainStatement(name string, params ExplainParams) (string, []any, error) {
+	q, ok := explainQueries[name]
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %q", ErrUnknownExplainQuery, name)
+	}
+	args, err := q.args(params)
+	if err != nil {
+		return "", nil, err
+	}
+	return "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) " + q.sql(), args, nil
+}
+
+// ExplainQuery runs EXPLAIN (ANALYZE, BUFFERS) for a whitelisted repository
+// query against the read pool and returns the JSON plan. ANALYZE executes the
+// query, so it runs in a read-only transaction with a statement timeout.
+func (r *Repository) ExplainQuery(ctx context.Context, name string, params ExplainParams) (json.RawMessage, error) {
+	stmt, args, err := explainStatement(name, params)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.readDB().BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, wrapDBErr(err, "explain begin")
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, "SET LOCAL statement_timeout = '"+explainTimeout+"'"); err != nil {
+		return nil, wrapDBErr(err, "explain timeout")
+	}
+	var plan []byte
+	if err := tx.QueryRow(ctx, stmt, args...).Scan(&plan); err != nil {
+		return nil, wrapDBErr(err, "explain "+name)
+	}
+	return json.RawMessage(plan), nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExplainStatementWhitelist(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := explainStatement("DROP TABLE raw.blocks", nil); !errors.Is(err, ErrUnknownExplainQuery) {
+		t.Fatalf("unknown query err = %v; want ErrUnknownExplainQuery", err)
+	}
+	for _, name := range ExplainQueryNames() {
+		stmt, _, err := explainStatement(name, nil)
+		if err != nil {
+			t.Fatalf("%s with sample params: %v", name, err)
+		}
+		if !strings.HasPrefix(stmt, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) ") {
+			t.Fatalf("%s statement = %q", name, stmt)
+		}
+	}
+}
+
+func TestExplainStatementParams(t *testing.T) {
+	t.Parallel()
+
+	_, args, err := explainStatement("GetTransactionsByAddress", ExplainParams{"address": "0x0000000000000001", "limit": float64(10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 4 || string(args[0].([]byte)) != string(hexToBytes("0000000000000001")) || args[1] != int64(11) || args[2] != int64(0) {
+		t.Fatalf("args = %#v", args)
+	}
+
+	_, args, err = explainStatement("GetTransactionsByAddressCursor", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[1] != nil || args[2] != nil {
+		t.Fatalf("cursor args without a cursor = %#v", args)
+	}
+
+	for _, p := range []ExplainParams{{"limit": "ten"}, {"limit": float64(0)}, {"offset": float64(-1)}, {"limit": float64(1.5)}} {
+		if _, _, err := explainStatement("GetRecentTransactions", p); !errors.Is(err, ErrInvalidExplainParams) {
+			t.Fatalf("params %v: err = %v; want ErrInvalidExplainParams", p, err)
+		}
+	}
+}
+
+// TestExplainQueryPlan needs a database with the schema applied.
+func TestExplainQueryPlan(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	repo, err := NewRepository(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	plan, err := repo.ExplainQuery(context.Background(), "GetTransactionsByAddress", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(plan, &decoded); err != nil || len(decoded) != 1 || decoded[0]["Plan"] == nil {
+		t.Fatalf("plan = %s (%v)", plan, err)
+	}
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"flowscan-clone/internal/models"
+)
+
+// String serializes the cursor as "height:tx_index:id".
+func (c TxCursor) String() string {
+	return fmt.Sprintf("%d:%d:%s", c.BlockHeight, c.TxIndex, c.ID)
+}
+
+// ParseTxCursor parses a cursor produced by TxCursor.String.
+func ParseTxCursor(s string) (*TxCursor, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid tx cursor %q", s)
+	}
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tx cursor height: %w", err)
+	}
+	txIndex, err := strconv.Atoi(parts[1])
+	if err != nil || txIndex < 0 {
+		return nil, fmt.Errorf("invalid tx cursor tx index %q", parts[1])
+	}
+	id := normalizeHex(parts[2])
+	if id == "" {
+		return nil, fmt.Errorf("invalid tx cursor id %q", parts[2])
+	}
+	return &TxCursor{BlockHeight: height, TxIndex: txIndex, ID: id}, nil
+}
+
+// failedTransactionsQuery builds the feed query for ListFailedTransactions. The
+// block_height bounds let the planner prune raw.transactions partitions.
+func failedTransactionsQuery(fromHeight, toHeight uint64, limit int, cursor *TxCursor) (string, []interface{}) {
+	args := []interface{}{fromHeight, toHeight}
+	clauses := []string{
+		"t.block_height >= $1",
+		"t.block_height <= $2",
+		"COALESCE(t.error_message, '') <> ''",
+	}
+	if cursor != nil {
+		args = append(args, cursor.BlockHeight, cursor.TxIndex, hexToBytes(cursor.ID))
+		n := len(args)
+		clauses = append(clauses, fmt.Sprintf("(t.block_height, t.transaction_index, t.id) < ($%d, $%d, $%d)", n-2, n-1, n))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT
+			encode(t.id, 'hex') AS id,
+			t.block_height,
+			t.transaction_index,
+			COALESCE(encode(t.proposer_address, 'hex'), '') AS proposer_address,
+			COALESCE(encode(t.payer_address, 'hex'), '') AS payer_address,
+			COALESCE(ARRAY(SELECT encode(a, 'hex') FROM unnest(t.authorizers) a), ARRAY[]::text[]) AS authorizers,
+			COALESCE(t.status, '') AS status,
+			t.error_message,
+			t.timestamp
+		FROM raw.transactions t
+		WHERE %s
+		ORDER BY t.block_height DESC, t.transaction_index DESC, t.id DESC
+		LIMIT $%d`, strings.Join(clauses, " AND "), len(args))
+	return query, args
+}
+
+// ListFailedTransactions returns transactions with a non-empty error_message in
+// [fromHeight, toHeight], newest first. Pass the TxCursor of the last row to page.
+func (r *Repository) ListFailedTransactions(ctx context.Context, fromHeight, toHeight uint64, limit int, cursor *TxCursor) ([]models.Transaction, error) {
+	if toHeight < fromHeight {
+		return nil, fmt.Errorf("invalid range: to_height %d < from_height %d", toHeight, fromHeight)
+	}
+	query, args := failedTransactionsQuery(fromHeight, toHeight, limit, cursor)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txs []models.Transaction
+	for rows.Next() {
+		var t models.Transaction
+		if err := rows.Scan(&t.ID, &t.BlockHeight, &t.TransactionIndex, &t.ProposerAddress, &t.PayerAddress, &t.Authorizers, &t.Status, &t.ErrorMessage, &t.Timestamp); err != nil {
+			return nil, err
+		}
+		txs = append(txs, t)
+	}
+	return txs, rows.Err()
+}
+
+// TxErrorGroup is a set of failed transactions whose error messages normalize
+// to the same Pattern.
+type TxErrorGroup struct {
+	Pattern         string `json:"pattern"`
+	Count           int64  `json:"count"`
+	Example         string `json:"example"`
+	LastBlockHeight uint64 `json:"last_block_height"`
+}
+
+// txErrorCount is one distinct raw error message and how often it occurred.
+type txErrorCount struct {
+	Message    string
+	Count      int64
+	LastHeight uint64
+}
+
+// maxTxErrorPatternLen caps the normalized prefix used as a group key.
+const maxTxErrorPatternLen = 200
+
+// maxDistinctTxErrors caps the distinct raw messages fetched for grouping.
+const maxDistinctTxErrors = 5000
+
+var (
+	txErrCodeRe   = regexp.MustCompile(`\[Error Code: (\d+)\]`)
+	txErrHexRe    = regexp.MustCompile(`(?i)\b(?:0x)?[0-9a-f]{16,}\b`)
+	txErrNumRe    = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	txErrSpaceRe  = regexp.MustCompile(`\s+`)
+	txErrNoiseRes = []*regexp.Regexp{
+		regexp.MustCompile(`error caused by: \d+ errors? occurred:`),
+		regexp.MustCompile(`\* transaction (?:execute|verification) failed:`),
+		regexp.MustCompile(`cadence runtime error: Execution failed:`),
+	}
+)
+
+// NormalizeTxError reduces a transaction error message to a grouping key:
+// Cadence boilerplate is dropped, addresses and ids become <addr>, numbers
+// become <n> (Flow error codes are kept), and everything from the first source
+// location pointer on is cut.
+func NormalizeTxError(msg string) string {
+	s := txErrSpaceRe.ReplaceAllString(strings.TrimSpace(msg), " ")
+	if i := strings.Index(s, " --> "); i >= 0 {
+		s = s[:i]
+	}
+	for _, re := range txErrNoiseRes {
+		s = re.ReplaceAllString(s, "")
+	}
+	s = txErrCodeRe.ReplaceAllString(s, "[E$1]")
+	s = txErrHexRe.ReplaceAllString(s, "<addr>")
+	s = txErrNumRe.ReplaceAllString(s, "<n>")
+	// Drop the error code repeated by each boilerplate wrapper.
+	fields := strings.Fields(s)
+	kept := fields[:0]
+	for _, f := range fields {
+		if len(kept) > 0 && f == kept[len(kept)-1] && strings.HasPrefix(f, "[E") {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	s = strings.Join(kept, " ")
+	if len(s) > maxTxErrorPatternLen {
+		s = s[:maxTxErrorPatternLen]
+	}
+	return s
+}
+
+// groupTxErrors merges distinct error messages by their normalized pattern and
+// returns the limit largest groups.
+func groupTxErrors(counts []txErrorCount, limit int) []TxErrorGroup {
+	byPattern := make(map[string]*TxErrorGroup)
+	for _, c := range counts {
+		p := NormalizeTxError(c.Message)
+		g := byPattern[p]
+		if g == nil {
+			g = &TxErrorGroup{Pattern: p, Example: c.Message}
+			byPattern[p] = g
+		}
+		g.Count += c.Count
+		if c.LastHeight > g.LastBlockHeight {
+			g.LastBlockHeight = c.LastHeight
+		}
+	}
+
+	out := make([]TxErrorGroup, 0, len(byPattern))
+	for _, g := range byPattern {
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Pattern < out[j].Pattern
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// GetTopTransactionErrors groups the failed transactions in [fromHeight,
+// toHeight] by normalized error message and returns the limit most common.
+func (r *Repository) GetTopTransactionErrors(ctx context.Context, fromHeight, toHeight uint64, limit int) ([]TxErrorGroup, error) {
+	if toHeight < fromHeight {
+		return nil, fmt.Errorf("invalid range: to_height %d < from_height %d", toHeight, fromHeight)
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT error_message, COUNT(*), MAX(block_height)
+		FROM raw.transactions
+		WHERE block_height >= $1 AND block_height <= $2
+		  AND COALESCE(error_message, '') <> ''
+		GROUP BY error_message
+		ORDER BY COUNT(*) DESC
+		LIMIT $3`, fromHeight, toHeight, maxDistinctTxErrors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []txErrorCount
+	for rows.Next() {
+		var c txErrorCount
+		var h int64
+		if err := rows.Scan(&c.Message, &c.Count, &h); err != nil {
+			return nil, err
+		}
+		c.LastHeight = uint64(h)
+		counts = append(counts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return groupTxErrors(counts, limit), nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFailedTransactionsQuery(t *testing.T) {
+	t.Parallel()
+
+	query, args := failedTransactionsQuery(100, 200, 25, nil)
+	if !strings.Contains(query, "COALESCE(t.error_message, '') <> ''") {
+		t.Fatalf("query does not filter on error_message:\n%s", query)
+	}
+	if len(args) != 3 || args[2] != 25 {
+		t.Fatalf("args = %v", args)
+	}
+
+	cursor, err := ParseTxCursor(TxCursor{BlockHeight: 150, TxIndex: 2, ID: "ab01"}.String())
+	if err != nil {
+		t.Fatalf("ParseTxCursor: %v", err)
+	}
+	query, args = failedTransactionsQuery(100, 200, 25, cursor)
+	if !strings.Contains(query, "(t.block_height, t.transaction_index, t.id) < ($3, $4, $5)") || !strings.Contains(query, "LIMIT $6") {
+		t.Fatalf("query missing keyset predicate:\n%s", query)
+	}
+	if args[2] != uint64(150) || args[3] != 2 {
+		t.Fatalf("cursor args = %v", args[2:])
+	}
+}
+
+func TestGroupTxErrorsCollapsesSimilarMessages(t *testing.T) {
+	t.Parallel()
+
+	wrap := func(detail string) string {
+		return "[Error Code: 1101] error caused by: 1 error occurred:\n\t* transaction execute failed: [Error Code: 1101] cadence runtime error: Execution failed:\nerror: " + detail + "\n --> 1654653399040a61.FlowToken:104:16\n"
+	}
+	counts := []txErrorCount{
+		{Message: wrap("pre-condition failed: Amount withdrawn must be less than or equal than the balance of the Vault"), Count: 5, LastHeight: 90},
+		{Message: wrap("pre-condition failed: Amount withdrawn must be less than or equal than the balance of the Vault"), Count: 2, LastHeight: 120},
+		{Message: "[Error Code: 1007] invalid proposal key: public key 0 on account 18eb4ee6b3c026d2 does not have a valid signature", Count: 3, LastHeight: 100},
+		{Message: "[Error Code: 1007] invalid proposal key: public key 2 on account e467b9dd11fa00df does not have a valid signature", Count: 3, LastHeight: 110},
+		{Message: "[Error Code: 1103] storage limit exceeded: 1000 > 900", Count: 1, LastHeight: 80},
+	}
+
+	groups := groupTxErrors(counts, 10)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d: %+v", len(groups), groups)
+	}
+	if groups[0].Count != 7 || groups[0].LastBlockHeight != 120 {
+		t.Fatalf("balance group = %+v", groups[0])
+	}
+	if want := "[E1101] error: pre-condition failed: Amount withdrawn"; !strings.HasPrefix(groups[0].Pattern, want) {
+		t.Fatalf("pattern = %q, want prefix %q", groups[0].Pattern, want)
+	}
+	if groups[1].Count != 6 || groups[1].Pattern != "[E1007] invalid proposal key: public key <n> on account <addr> does not have a valid signature" {
+		t.Fatalf("proposal key group = %+v", groups[1])
+	}
+	if groups[2].Pattern != "[E1103] storage limit exceeded: <n> > <n>" {
+		t.Fatalf("storage group = %+v", groups[2])
+	}
+
+	if top := groupTxErrors(counts, 1); len(top) != 1 {
+		t.Fatalf("limit not applied: %d groups", len(top))
+	}
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"flowscan-clone/internal/contractid"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ftHoldingsRebuildWindow is the block span replayed per progress step of
+// RebuildFTHoldingsForToken.
+const ftHoldingsRebuildWindow = 1_000_000
+
+// ftBalanceScale matches app.ft_holdings.balance NUMERIC(78, 18).
+const ftBalanceScale = 18
+
+// FTHoldingsRebuildResult summarises a RebuildFTHoldingsForToken run.
+type FTHoldingsRebuildResult struct {
+	Token      string
+	UpToHeight uint64 // transfers below this height were replayed
+	Holders    int    // addresses left with a non-zero balance
+	Zeroed     int    // holders whose balance was set to 0
+}
+
+// ftBalanceDelta is one address's balance change over a replayed window.
+type ftBalanceDelta struct {
+	Address string
+	Amount  string // signed decimal
+	Height  uint64
+}
+
+// ftBalances accumulates exact per-address balances in 1e-18 units.
+type ftBalances struct {
+	balance map[string]*big.Int
+	last    map[string]uint64
+}
+
+func newFTBalances() *ftBalances {
+	return &ftBalances{balance: make(map[string]*big.Int), last: make(map[string]uint64)}
+}
+
+func (b *ftBalances) apply(d ftBalanceDelta) error {
+	v, err := parseFTBalance(d.Amount)
+	if err != nil {
+		return err
+	}
+	cur, ok := b.balance[d.Address]
+	if !ok {
+		cur = new(big.Int)
+		b.balance[d.Address] = cur
+	}
+	cur.Add(cur, v)
+	if d.Height > b.last[d.Address] {
+		b.last[d.Address] = d.Height
+	}
+	return nil
+}
+
+// parseFTBalance parses a NUMERIC(78, 18) amount into 1e-18 units.
+func parseFTBalance(s string) (*big.Int, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("invalid ft amount %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(ftBalanceScale), nil)))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("ft amount %q has more than %d decimals", s, ftBalanceScale)
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// RebuildFTHoldingsForToken recomputes app.ft_holdings for one token by
+// replaying its app.ft_transfers, for when ft_holdings_worker missed the token
+// and a full worker reset is too costly. Transfers below the worker's
+// checkpoint are replayed; later ones are applied by the worker as usual.
+// Existing holders that the replay leaves at zero, or no longer sees, are set
+// to 0. progress, if non-nil, is called after each replayed window.
+func (r *Repository) RebuildFTHoldingsForToken(ctx context.Context, token string, progress func(done, total int)) (FTHoldingsRebuildResult, error) {
+	id := contractid.Parse(token)
+	if id.Address == "" || id.Name == "" {
+		return FTHoldingsRebuildResult{}, fmt.Errorf("%w: %q", ErrInvalidFTToken, token)
+	}
+	res := FTHoldingsRebuildResult{Token: id.String()}
+	contract := hexToBytes(id.Address)
+
+	upTo, err := r.GetLastIndexedHeight(ctx, "ft_holdings_worker")
+	if err != nil {
+		return res, fmt.Errorf("rebuild ft holdings: load checkpoint: %w", err)
+	}
+	res.UpToHeight = upTo
+
+	balances, err := r.replayFTHoldings(ctx, contract, id.Name, upTo, progress)
+	if err != nil {
+		return res, fmt.Errorf("rebuild ft holdings: %w", err)
+	}
+
+	zeroed, err := r.writeRebuiltFTHoldings(ctx, contract, id.Name, balances)
+	if err != nil {
+		return res, fmt.Errorf("rebuild ft holdings: %w", err)
+	}
+	for _, v := range balances.balance {
+		if v.Sign() != 0 {
+			res.Holders++
+		}
+	}
+	res.Zeroed = zeroed
+	return res, nil
+}
+
+// replayFTHoldings replays the token's transfers below upTo into exact
+// per-address balances, one ftHoldingsRebuildWindow at a time.
+func (r *Repository) replayFTHoldings(ctx context.Context, contract []byte, name string, upTo uint64, progress func(done, total int)) (*ftBalances, error) {
+	var minHeight uint64
+	if err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(MIN(block_height), 0)
+		FROM app.ft_transfers
+		WHERE token_contract_address = $1 AND contract_name = $2 AND block_height < $3`,
+		contract, name, int64(upTo)).Scan(&minHeight); err != nil {
+		return nil, err
+	}
+
+	balances := newFTBalances()
+	total := 0
+	if upTo > minHeight {
+		total = int((upTo - minHeight + ftHoldingsRebuildWindow - 1) / ftHoldingsRebuildWindow)
+	}
+	for i := 0; i < total; i++ {
+		from := minHeight + uint64(i)*ftHoldingsRebuildWindow
+		to := from + ftHoldingsRebuildWindow
+		if to > upTo {
+			to = upTo
+		}
+		if err := r.replayFTTransferWindow(ctx, contract, name, from, to, balances); err != nil {
+			return nil, fmt.Errorf("[%d, %d): %w", from, to, err)
+		}
+		if progress != nil {
+			progress(i+1, total)
+		}
+	}
+	return balances, nil
+}
+
+// replayFTTransferWindow applies the token's net per-address transfers in
+// [from, to) to balances. Empty sides (mints, burns) are skipped, as in
+// ft_holdings_worker.
+func (r *Repository) replayFTTransferWindow(ctx context.Context, contract []byte, name string, from, to uint64, balances *ftBalances) error {
+	rows, err := r.db.Query(ctx, `
+		SELECT encode(address, 'hex'), SUM(delta)::text, MAX(block_height)
+		FROM (
+			SELECT to_address AS address, amount AS delta, block_height
+			FROM app.ft_transfers
+			WHERE token_contract_address = $1 AND contract_name = $2
+			  AND block_height >= $3 AND block_height < $4
+			  AND octet_length(to_address) > 0 AND amount IS NOT NULL
+			UNION ALL
+			SELECT from_address, -amount, block_height
+			FROM app.ft_transfers
+			WHERE token_contract_address = $1 AND contract_name = $2
+			  AND block_height >= $3 AND block_height < $4
+			  AND octet_length(from_address) > 0 AND amount IS NOT NULL
+		) d
+		GROUP BY address`, contract, name, int64(from), int64(to))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d ftBalanceDelta
+		var height int64
+		if err := rows.Scan(&d.Address, &d.Amount, &height); err != nil {
+			return err
+		}
+		d.Height = uint64(height)
+		if err := balances.apply(d); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// writeRebuiltFTHoldings replaces the token's holdings with balances in one
+// transaction and returns how many holders ended at zero.
+func (r *Repository) writeRebuiltFTHoldings(ctx context.Context, contract []byte, name string, balances *ftBalances) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE tmp_ft_rebuild (
+			address BYTEA,
+			balance NUMERIC,
+			last_height BIGINT
+		) ON COMMIT DROP`); err != nil {
+		return 0, err
+	}
+	zeroed := 0
+	copyRows := make([][]interface{}, 0, len(balances.balance))
+	for addr, v := range balances.balance {
+		if v.Sign() == 0 {
+			zeroed++
+		}
+		copyRows = append(copyRows, []interface{}{
+			hexToBytes(addr),
+			pgtype.Numeric{Int: v, Exp: -ftBalanceScale, Valid: true},
+			int64(balances.last[addr]),
+		})
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"tmp_ft_rebuild"}, []string{"address", "balance", "last_height"}, pgx.CopyFromRows(copyRows)); err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE app.ft_holdings h
+		SET balance = 0, updated_at = NOW()
+		WHERE h.contract_address = $1 AND h.contract_name = $2 AND h.balance <> 0
+		  AND NOT EXISTS (SELECT 1 FROM tmp_ft_rebuild t WHERE t.address = h.address)`, contract, name)
+	if err != nil {
+		return 0, err
+	}
+	zeroed += int(tag.RowsAffected())
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO app.ft_holdings (address, contract_address, contract_name, balance, last_height, updated_at)
+		SELECT address, $1, $2, balance, last_height, NOW()
+		FROM tmp_ft_rebuild
+		ON CONFLICT (address, contract_address, contract_name) DO UPDATE SET
+			balance = EXCLUDED.balance,
+			last_height = EXCLUDED.last_height,
+			updated_at = NOW()`, contract, name); err != nil {
+		return 0, err
+	}
+	return zeroed, tx.Commit(ctx)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFTBalancesReplayDepositWithdraw(t *testing.T) {
+	t.Parallel()
+
+	// A is minted 10, sends 4 to B, B forwards all 4 to C, A burns 1.5.
+	// Each transfer contributes a deposit and a withdrawal row, as the
+	// window query emits them.
+	b := newFTBalances()
+	for _, d := range []ftBalanceDelta{
+		{Address: "a", Amount: "10.00000000", Height: 100},
+		{Address: "a", Amount: "-4", Height: 110},
+		{Address: "b", Amount: "4", Height: 110},
+		{Address: "b", Amount: "-4.000000000000000000", Height: 120},
+		{Address: "c", Amount: "4", Height: 120},
+		{Address: "a", Amount: "-1.5", Height: 130},
+	} {
+		if err := b.apply(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]string{"a": "4500000000000000000", "b": "0", "c": "4000000000000000000"}
+	for addr, w := range want {
+		if got := b.balance[addr].String(); got != w {
+			t.Errorf("balance[%s] = %s; want %s", addr, got, w)
+		}
+	}
+	if b.last["a"] != 130 || b.last["b"] != 120 || b.last["c"] != 120 {
+		t.Fatalf("last heights = %v", b.last)
+	}
+}
+
+func TestParseFTBalanceRejectsExcessPrecision(t *testing.T) {
+	t.Parallel()
+
+	if _, err := parseFTBalance("0.0000000000000000001"); err == nil {
+		t.Fatal("expected an error for 19 decimals")
+	}
+	if v, err := parseFTBalance("-0.000000000000000001"); err != nil || v.String() != "-1" {
+		t.Fatalf("parseFTBalance = %v, %v; want -1", v, err)
+	}
+}
+
+func TestRebuildFTHoldingsRejectsInvalidToken(t *testing.T) {
+	t.Parallel()
+
+	// Validation happens before any query, so no database is needed.
+	_, err := (&Repository{}).RebuildFTHoldingsForToken(context.Background(), "FlowToken", nil)
+	if !errors.Is(err, ErrInvalidFTToken) {
+		t.Fatalf("expected ErrInvalidFTToken, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"flowscan-clone/internal/contractid"
+)
+
+// FTBalanceDrift is one holder whose app.ft_holdings balance differs from the
+// balance replayed from app.ft_transfers. Delta is Expected - Stored.
+type FTBalanceDrift struct {
+	Address  string `json:"address"`
+	Stored   string `json:"stored"`
+	Expected string `json:"expected"`
+	Delta    string `json:"delta"`
+}
+
+// FTHoldingsReconcileResult summarises a ReconcileFTHoldingsForToken run.
+type FTHoldingsReconcileResult struct {
+	Token      string
+	UpToHeight uint64 // transfers below this height were replayed
+	Checked    int    // holders compared
+	Skipped    int    // holders ft_holdings_worker updated past UpToHeight; not compared
+	Drift      []FTBalanceDrift
+	Corrected  int // drifted rows rewritten; always 0 in dry-run mode
+	DryRun     bool
+}
+
+// ftStoredHolding is a holder's app.ft_holdings row.
+type ftStoredHolding struct {
+	Balance    *big.Int // 1e-18 units
+	LastHeight uint64
+}
+
+// ReconcileFTHoldingsForToken checks app.ft_holdings for one token against a
+// replay of its app.ft_transfers below the ft_holdings_worker checkpoint.
+// Unlike RebuildFTHoldingsForToken it only touches holders that drifted, and
+// only when dryRun is false. Holders the worker has already moved past the
+// checkpoint are skipped, so it is safe to run while the worker is live.
+func (r *Repository) ReconcileFTHoldingsForToken(ctx context.Context, token string, dryRun bool) (FTHoldingsReconcileResult, error) {
+	id := contractid.Parse(token)
+	if id.Address == "" || id.Name == "" {
+		return FTHoldingsReconcileResult{}, fmt.Errorf("%w: %q", ErrInvalidFTToken, token)
+	}
+	res := FTHoldingsReconcileResult{Token: id.String(), DryRun: dryRun}
+	contract := hexToBytes(id.Address)
+
+	upTo, err := r.GetLastIndexedHeight(ctx, "ft_holdings_worker")
+	if err != nil {
+		return res, fmt.Errorf("reconcile ft holdings: load checkpoint: %w", err)
+	}
+	res.UpToHeight = upTo
+
+	expected, err := r.replayFTHoldings(ctx, contract, id.Name, upTo, nil)
+	if err != nil {
+		return res, fmt.Errorf("reconcile ft holdings: %w", err)
+	}
+	stored, err := r.loadFTHoldings(ctx, contract, id.Name)
+	if err != nil {
+		return res, fmt.Errorf("reconcile ft holdings: %w", err)
+	}
+	res.Drift, res.Checked, res.Skipped = diffFTHoldings(expected, stored, upTo)
+	if dryRun || len(res.Drift) == 0 {
+		return res, nil
+	}
+
+	res.Corrected, err = r.correctFTHoldingsDrift(ctx, contract, id.Name, upTo, res.Drift, expected)
+	if err != nil {
+		return res, fmt.Errorf("reconcile ft holdings: %w", err)
+	}
+	return res, nil
+}
+
+// ListFTTokenIdentifiers returns every token in app.ft_tokens as
+// A.<address>.<name>, ordered.
+func (r *Repository) ListFTTokenIdentifiers(ctx context.Context) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT 'A.' || encode(contract_address, 'hex') || '.' || contract_name
+		FROM app.ft_tokens
+		WHERE contract_name <> ''
+		ORDER BY 1`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+func (r *Repository) loadFTHoldings(ctx context.Context, contract []byte, name string) (map[string]ftStoredHolding, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT encode(address, 'hex'), balance::text, COALESCE(last_height, 0)
+		FROM app.ft_holdings
+		WHERE contract_address = $1 AND contract_name = $2`, contract, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string]ftStoredHolding)
+	for rows.Next() {
+		var addr, balance string
+		var height int64
+		if err := rows.Scan(&addr, &balance, &height); err != nil {
+			return nil, err
+		}
+		v, err := parseFTBalance(balance)
+		if err != nil {
+			return nil, err
+		}
+		out[addr] = ftStoredHolding{Balance: v, LastHeight: uint64(height)}
+	}
+	return out, rows.Err()
+}
+
+// diffFTHoldings compares replayed balances with stored holdings, ordered by
+// address. A holder missing on either side counts as 0. Stored rows with a
+// last_height at or past upTo already include transfers the replay did not
+// see and are skipped.
+func diffFTHoldings(expected *ftBalances, stored map[string]ftStoredHolding, upTo uint64) (drift []FTBalanceDrift, checked, skipped int) {
+	addrs := make(map[string]struct{}, len(stored)+len(expected.balance))
+	for a := range stored {
+		addrs[a] = struct{}{}
+	}
+	for a := range expected.balance {
+		addrs[a] = struct{}{}
+	}
+
+	zero := new(big.Int)
+	for a := range addrs {
+		have, want := zero, zero
+		if s, ok := stored[a]; ok {
+			if s.LastHeight >= upTo {
+				skipped++
+				continue
+			}
+			have = s.Balance
+		}
+		if v, ok := expected.balance[a]; ok {
+			want = v
+		}
+		checked++
+		if have.Cmp(want) == 0 {
+			continue
+		}
+		drift = append(drift, FTBalanceDrift{
+			Address:  a,
+			Stored:   formatFTBalance(have),
+			Expected: formatFTBalance(want),
+			Delta:    formatFTBalance(new(big.Int).Sub(want, have)),
+		})
+	}
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Address < drift[j].Address })
+	return drift, checked, skipped
+}
+
+// formatFTBalance renders 1e-18 units as a plain decimal without trailing
+// zeros, the inverse of parseFTBalance.
+func formatFTBalance(v *big.Int) string {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(ftBalanceScale), nil)
+	s := new(big.Rat).SetFrac(v, unit).FloatString(ftBalanceScale)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// correctFTHoldingsDrift writes the expected balance for each drifted holder
+// and returns how many rows changed. A row the worker has updated past upTo
+// since it was read is left alone.
+func (r *Repository) correctFTHoldingsDrift(ctx context.Context, contract []byte, name string, upTo uint64, drift []FTBalanceDrift, expected *ftBalances) (int, error) {
+	addrs := make([]string, len(drift))
+	balances := make([]string, len(drift))
+	heights := make([]int64, len(drift))
+	for i, d := range drift {
+		addrs[i] = d.Address
+		balances[i] = d.Expected
+		heights[i] = int64(expected.last[d.Address])
+	}
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO app.ft_holdings (address, contract_address, contract_name, balance, last_height, updated_at)
+		SELECT decode(d.address, 'hex'), $1, $2, d.balance::numeric, d.last_height, NOW()
+		FROM unnest($3::text[], $4::text[], $5::bigint[]) AS d(address, balance, last_height)
+		ON CONFLICT (address, contract_address, contract_name) DO UPDATE SET
+			balance = EXCLUDED.balance,
+			last_height = GREATEST(app.ft_holdings.last_height, EXCLUDED.last_height),
+			updated_at = NOW()
+		WHERE app.ft_holdings.last_height IS NULL OR app.ft_holdings.last_height < $6`,
+		contract, name, addrs, balances, heights, int64(upTo))
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestDiffFTHoldingsReportsAndCorrectsDrift(t *testing.T) {
+	t.Parallel()
+
+	// Replay: a = 4.5, b = 0, c = 4, f = 2.
+	expected := newFTBalances()
+	for _, d := range []ftBalanceDelta{
+		{Address: "a", Amount: "4.5", Height: 130},
+		{Address: "b", Amount: "0", Height: 120},
+		{Address: "c", Amount: "4", Height: 120},
+		{Address: "f", Amount: "2", Height: 140},
+	} {
+		if err := expected.apply(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bal := func(s string) *big.Int {
+		v, err := parseFTBalance(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	const upTo = 200
+	stored := map[string]ftStoredHolding{
+		"a": {Balance: bal("5.25"), LastHeight: 130},                // drifted up
+		"b": {Balance: bal("0"), LastHeight: 120},                   // in sync at zero
+		"c": {Balance: bal("4"), LastHeight: 120},                   // in sync
+		"d": {Balance: bal("0.000000000000000001"), LastHeight: 90}, // never received anything
+		"e": {Balance: bal("7"), LastHeight: upTo},                  // worker moved past the checkpoint
+	}
+
+	drift, checked, skipped := diffFTHoldings(expected, stored, upTo)
+	want := []FTBalanceDrift{
+		{Address: "a", Stored: "5.25", Expected: "4.5", Delta: "-0.75"},
+		{Address: "d", Stored: "0.000000000000000001", Expected: "0", Delta: "-0.000000000000000001"},
+		{Address: "f", Stored: "0", Expected: "2", Delta: "2"},
+	}
+	if !reflect.DeepEqual(drift, want) {
+		t.Fatalf("drift = %+v\nwant %+v", drift, want)
+	}
+	if checked != 5 || skipped != 1 {
+		t.Fatalf("checked=%d skipped=%d; want 5 and 1", checked, skipped)
+	}
+
+	// Writing the expected balances, as correctFTHoldingsDrift does, leaves
+	// nothing to report on the next pass.
+	for _, d := range drift {
+		stored[d.Address] = ftStoredHolding{Balance: bal(d.Expected), LastHeight: expected.last[d.Address]}
+	}
+	if drift, _, _ := diffFTHoldings(expected, stored, upTo); len(drift) != 0 {
+		t.Fatalf("drift after correction = %+v", drift)
+	}
+}
+
+func TestFormatFTBalanceRoundTrips(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"0", "1", "-2.5", "0.000000000000000001", "123456789012345678901234.123456789012345678"} {
+		v, err := parseFTBalance(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := formatFTBalance(v); got != s {
+			t.Errorf("formatFTBalance(parseFTBalance(%q)) = %q", s, got)
+		}
+	}
+}
+
+func TestReconcileFTHoldingsRejectsInvalidToken(t *testing.T) {
+	t.Parallel()
+
+	_, err := (&Repository{}).ReconcileFTHoldingsForToken(context.Background(), "A.1654653399040a61", true)
+	if !errors.Is(err, ErrInvalidFTToken) {
+		t.Fatalf("expected ErrInvalidFTToken, got %v", err)
+	}
+}
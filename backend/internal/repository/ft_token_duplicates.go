@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"flowscan-clone/internal/contractid"
+	"flowscan-clone/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// FTTokenDuplicateGroup is a set of app.ft_tokens rows that normalize to the
+// same (contract_address, contract_name). Records are ordered by preference;
+// the first one is the row a merge keeps.
+type FTTokenDuplicateGroup struct {
+	ContractAddress string           `json:"contract_address"`
+	ContractName    string           `json:"contract_name"`
+	Records         []models.FTToken `json:"records"`
+}
+
+// normalizeFTTokenKey maps the address/name forms the workers have written over
+// time ("A.addr.Name", "Name.Vault", unpadded or 0x-prefixed addresses) to the
+// canonical (16-char hex address, contract name) key.
+func normalizeFTTokenKey(address, contractName string) (string, string) {
+	id := contractid.FromParts(address, contractName)
+	return id.Address, id.Name
+}
+
+// ftTokenCompleteness counts the metadata fields a row has filled in.
+func ftTokenCompleteness(t models.FTToken) int {
+	n := 0
+	for _, s := range []string{t.Name, t.Symbol, t.Description, t.ExternalURL, t.Logo,
+		t.VaultPath, t.ReceiverPath, t.BalancePath, t.EVMAddress, t.TotalSupply, t.MarketSymbol} {
+		if s != "" {
+			n++
+		}
+	}
+	if t.Decimals > 0 {
+		n++
+	}
+	if len(t.Socials) > 0 {
+		n++
+	}
+	return n
+}
+
+// preferFTToken reports whether a should be kept over b: manually verified
+// first, then the most complete, then the one already stored under the
+// canonical key, then the most recently updated.
+func preferFTToken(a, b models.FTToken) bool {
+	if a.IsVerified != b.IsVerified {
+		return a.IsVerified
+	}
+	if ca, cb := ftTokenCompleteness(a), ftTokenCompleteness(b); ca != cb {
+		return ca > cb
+	}
+	aAddr, aName := normalizeFTTokenKey(a.ContractAddress, a.ContractName)
+	bAddr, bName := normalizeFTTokenKey(b.ContractAddress, b.ContractName)
+	aCanon := aAddr == a.ContractAddress && aName == a.ContractName
+	bCanon := bAddr == b.ContractAddress && bName == b.ContractName
+	if aCanon != bCanon {
+		return aCanon
+	}
+	return a.UpdatedAt.After(b.UpdatedAt)
+}
+
+// groupFTTokenDuplicates groups rows by normalized key and returns the groups
+// with more than one row. A row without a contract name joins the named group
+// at its address when there is exactly one.
+func groupFTTokenDuplicates(rows []models.FTToken) []FTTokenDuplicateGroup {
+	type key struct{ addr, name string }
+	groups := map[key][]models.FTToken{}
+	namedByAddr := map[string][]string{}
+	var unnamed []models.FTToken
+
+	for _, t := range rows {
+		addr, name := normalizeFTTokenKey(t.ContractAddress, t.ContractName)
+		if name == "" {
+			unnamed = append(unnamed, t)
+			continue
+		}
+		k := key{addr, name}
+		if _, ok := groups[k]; !ok {
+			namedByAddr[addr] = append(namedByAddr[addr], name)
+		}
+		groups[k] = append(groups[k], t)
+	}
+	for _, t := range unnamed {
+		addr, _ := normalizeFTTokenKey(t.ContractAddress, t.ContractName)
+		k := key{addr, ""}
+		if names := namedByAddr[addr]; len(names) == 1 {
+			k.name = names[0]
+		}
+		groups[k] = append(groups[k], t)
+	}
+
+	var out []FTTokenDuplicateGroup
+	for k, recs := range groups {
+		if len(recs) < 2 {
+			continue
+		}
+		sort.SliceStable(recs, func(i, j int) bool { return preferFTToken(recs[i], recs[j]) })
+		out = append(out, FTTokenDuplicateGroup{ContractAddress: k.addr, ContractName: k.name, Records: recs})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ContractAddress != out[j].ContractAddress {
+			return out[i].ContractAddress < out[j].ContractAddress
+		}
+		return out[i].ContractName < out[j].ContractName
+	})
+	return out
+}
+
+// mergeFTTokenGroup returns the preferred record under the canonical key with
+// any empty fields filled from the other records in preference order.
+func mergeFTTokenGroup(g FTTokenDuplicateGroup) models.FTToken {
+	merged := g.Records[0]
+	merged.ContractAddress = g.ContractAddress
+	merged.ContractName = g.ContractName
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	for _, t := range g.Records[1:] {
+		fill(&merged.Name, t.Name)
+		fill(&merged.Symbol, t.Symbol)
+		fill(&merged.Description, t.Description)
+		fill(&merged.ExternalURL, t.ExternalURL)
+		fill(&merged.Logo, t.Logo)
+		fill(&merged.VaultPath, t.VaultPath)
+		fill(&merged.ReceiverPath, t.ReceiverPath)
+		fill(&merged.BalancePath, t.BalancePath)
+		fill(&merged.EVMAddress, t.EVMAddress)
+		fill(&merged.TotalSupply, t.TotalSupply)
+		fill(&merged.MarketSymbol, t.MarketSymbol)
+		if merged.Decimals == 0 {
+			merged.Decimals = t.Decimals
+		}
+		if len(merged.Socials) == 0 {
+			merged.Socials = t.Socials
+		}
+		merged.IsVerified = merged.IsVerified || t.IsVerified
+	}
+	return merged
+}
+
+// FindDuplicateFTTokens scans app.ft_tokens for rows that normalize to the same
+// contract and returns them grouped, preferred record first.
+func (r *Repository) FindDuplicateFTTokens(ctx context.Context) ([]FTTokenDuplicateGroup, error) {
+	rows, err := r.query(ctx, "ft_token_duplicates", `
+		SELECT encode(contract_address, 'hex'), contract_name,
+		       COALESCE(name, ''), COALESCE(symbol, ''), COALESCE(decimals, 0),
+		       COALESCE(description, ''), COALESCE(external_url, ''), COALESCE(logo, ''),
+		       COALESCE(vault_path, ''), COALESCE(receiver_path, ''), COALESCE(balance_path, ''),
+		       socials, COALESCE(evm_address, ''), COALESCE(total_supply::text, ''),
+		       COALESCE(is_verified, false), COALESCE(market_symbol, ''), updated_at
+		FROM app.ft_tokens`)
+	if err != nil {
+		return nil, wrapDBErr(err, "find duplicate ft tokens")
+	}
+	defer rows.Close()
+
+	var all []models.FTToken
+	for rows.Next() {
+		var t models.FTToken
+		if err := rows.Scan(&t.ContractAddress, &t.ContractName,
+			&t.Name, &t.Symbol, &t.Decimals,
+			&t.Description, &t.ExternalURL, &t.Logo,
+			&t.VaultPath, &t.ReceiverPath, &t.BalancePath,
+			&t.Socials, &t.EVMAddress, &t.TotalSupply,
+			&t.IsVerified, &t.MarketSymbol, &t.UpdatedAt); err != nil {
+			return nil, wrapDBErr(err, "find duplicate ft tokens")
+		}
+		all = append(all, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapDBErr(err, "find duplicate ft tokens")
+	}
+	return groupFTTokenDuplicates(all), nil
+}
+
+// MergeDuplicateFTTokens collapses a duplicate group into a single row under
+// the canonical key. The preferred row is kept (so columns outside the model,
+// such as coingecko_id, survive), its empty fields are filled from the others,
+// and the other rows are deleted, all in one transaction.
+func (r *Repository) MergeDuplicateFTTokens(ctx context.Context, g FTTokenDuplicateGroup) (models.FTToken, error) {
+	if len(g.Records) < 2 {
+		return models.FTToken{}, fmt.Errorf("merge ft tokens %s.%s: need at least two records", g.ContractAddress, g.ContractName)
+	}
+	merged := mergeFTTokenGroup(g)
+	keep := g.Records[0]
+
+	batch := &pgx.Batch{}
+	for _, t := range g.Records[1:] {
+		batch.Queue(`DELETE FROM app.ft_tokens WHERE contract_address = $1 AND contract_name = $2`,
+			hexToBytes(t.ContractAddress), t.ContractName)
+	}
+	batch.Queue(`
+		UPDATE app.ft_tokens SET
+			contract_address = $1, contract_name = $2,
+			name = NULLIF($3,''), symbol = NULLIF($4,''), decimals = NULLIF($5,0),
+			description = NULLIF($6,''), external_url = NULLIF($7,''), logo = NULLIF($8,''),
+			vault_path = NULLIF($9,''), receiver_path = NULLIF($10,''), balance_path = NULLIF($11,''),
+			socials = $12, evm_address = NULLIF($13,''), total_supply = NULLIF($14,'')::numeric,
+			is_verified = $15, market_symbol = NULLIF($16,''), updated_at = NOW()
+		WHERE contract_address = $17 AND contract_name = $18`,
+		hexToBytes(merged.ContractAddress), merged.ContractName,
+		merged.Name, merged.Symbol, merged.Decimals,
+		merged.Description, merged.ExternalURL, merged.Logo,
+		merged.VaultPath, merged.ReceiverPath, merged.BalancePath,
+		nullIfEmptyJSON(merged.Socials), merged.EVMAddress, merged.TotalSupply,
+		merged.IsVerified, merged.MarketSymbol,
+		hexToBytes(keep.ContractAddress), keep.ContractName)
+
+	if err := r.sendBatchInTx(ctx, batch, "merge ft tokens"); err != nil {
+		return models.FTToken{}, err
+	}
+	return merged, nil
+}
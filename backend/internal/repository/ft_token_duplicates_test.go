@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+func TestNormalizeFTTokenKey(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		addr, name         string
+		wantAddr, wantName string
+	}{
+		{"1654653399040a61", "FlowToken", "1654653399040a61", "FlowToken"},
+		{"0x1654653399040A61", "A.1654653399040a61.FlowToken", "1654653399040a61", "FlowToken"},
+		{"A.1654653399040a61.FlowToken", "", "1654653399040a61", "FlowToken"},
+		{"1654653399040a61", "A.1654653399040a61.FlowToken.Vault", "1654653399040a61", "FlowToken"},
+		{"0x0ae53cb6e3f42a79", "FlowToken.Vault", "0ae53cb6e3f42a79", "FlowToken"},
+		{"ae53cb6e3f42a79", "FlowToken", "0ae53cb6e3f42a79", "FlowToken"},
+		{"1654653399040a61", "", "1654653399040a61", ""},
+	}
+	for _, tc := range cases {
+		addr, name := normalizeFTTokenKey(tc.addr, tc.name)
+		if addr != tc.wantAddr || name != tc.wantName {
+			t.Errorf("normalizeFTTokenKey(%q, %q) = %q, %q; want %q, %q", tc.addr, tc.name, addr, name, tc.wantAddr, tc.wantName)
+		}
+	}
+}
+
+func TestDuplicateFTTokensMergeKeepsRicherRecord(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rows := []models.FTToken{
+		// Newer but sparse row written under the identifier form.
+		{ContractAddress: "b19436aae4d94622", ContractName: "A.b19436aae4d94622.FiatToken", Symbol: "USDC", EVMAddress: "0xf1815bd50389c46847f0bda824ec8da914045d14", UpdatedAt: now},
+		// Older row with full metadata under the canonical key.
+		{ContractAddress: "b19436aae4d94622", ContractName: "FiatToken", Name: "USD Coin", Symbol: "USDC", Decimals: 8,
+			Logo: "https://example.com/usdc.png", VaultPath: "/storage/USDCVault", IsVerified: true, UpdatedAt: now.Add(-time.Hour)},
+		// Unrelated token is not a duplicate.
+		{ContractAddress: "1654653399040a61", ContractName: "FlowToken", Name: "Flow", Symbol: "FLOW", Decimals: 8, UpdatedAt: now},
+	}
+
+	groups := groupFTTokenDuplicates(rows)
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 duplicate group, got %d: %+v", len(groups), groups)
+	}
+	g := groups[0]
+	if g.ContractAddress != "b19436aae4d94622" || g.ContractName != "FiatToken" || len(g.Records) != 2 {
+		t.Fatalf("group = %+v", g)
+	}
+	if g.Records[0].ContractName != "FiatToken" || !g.Records[0].IsVerified {
+		t.Fatalf("preferred record = %+v, want the verified canonical row", g.Records[0])
+	}
+
+	merged := mergeFTTokenGroup(g)
+	if merged.Name != "USD Coin" || merged.Decimals != 8 || merged.Logo == "" || !merged.IsVerified {
+		t.Fatalf("merged lost metadata from the richer row: %+v", merged)
+	}
+	if merged.EVMAddress != "0xf1815bd50389c46847f0bda824ec8da914045d14" {
+		t.Fatalf("merged did not fill evm_address from the sparse row: %+v", merged)
+	}
+	if merged.ContractAddress != "b19436aae4d94622" || merged.ContractName != "FiatToken" {
+		t.Fatalf("merged key = %s.%s", merged.ContractAddress, merged.ContractName)
+	}
+}
+
+func TestDuplicateFTTokensUnnamedRowJoinsOnlyNamedToken(t *testing.T) {
+	t.Parallel()
+
+	rows := []models.FTToken{
+		{ContractAddress: "0ae53cb6e3f42a79", ContractName: ""},
+		{ContractAddress: "0ae53cb6e3f42a79", ContractName: "FlowToken", Name: "Flow"},
+		// Two named tokens at one address: the unnamed row is ambiguous and left alone.
+		{ContractAddress: "f233dcee88fe0abe", ContractName: ""},
+		{ContractAddress: "f233dcee88fe0abe", ContractName: "FUSD"},
+		{ContractAddress: "f233dcee88fe0abe", ContractName: "FungibleToken"},
+	}
+	groups := groupFTTokenDuplicates(rows)
+	if len(groups) != 1 || groups[0].ContractName != "FlowToken" || groups[0].Records[0].Name != "Flow" {
+		t.Fatalf("groups = %+v", groups)
+	}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFTTokenListFilterWhere(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		filter    FTTokenListFilter
+		wantWhere string
+		wantArgs  []any
+	}{
+		{"none", FTTokenListFilter{}, "", nil},
+		{"verified", FTTokenListFilter{Verified: true}, "WHERE ft.is_verified", nil},
+		{"bridged", FTTokenListFilter{Bridged: true}, "WHERE COALESCE(ft.evm_address, '') != ''", nil},
+		{"legacy evm_bridged preset", FTTokenListFilter{Preset: "evm_bridged"}, "WHERE COALESCE(ft.evm_address, '') != ''", nil},
+		{
+			"query",
+			FTTokenListFilter{Query: " USDC "},
+			"WHERE (lower(ft.symbol) LIKE $3 OR lower(ft.name) LIKE $3)",
+			[]any{"usdc%"},
+		},
+		{
+			"query escapes wildcards",
+			FTTokenListFilter{Query: `50%_a\b`},
+			"WHERE (lower(ft.symbol) LIKE $3 OR lower(ft.name) LIKE $3)",
+			[]any{`50\%\_a\\b%`},
+		},
+		{
+			"combined",
+			FTTokenListFilter{Preset: "has_price", Verified: true, Bridged: true, Query: "Flow"},
+			"WHERE COALESCE(ft.market_symbol, '') != '' AND ft.is_verified AND COALESCE(ft.evm_address, '') != '' AND (lower(ft.symbol) LIKE $3 OR lower(ft.name) LIKE $3)",
+			[]any{"flow%"},
+		},
+	}
+	for _, tc := range cases {
+		where, args := tc.filter.where(3)
+		if where != tc.wantWhere {
+			t.Errorf("%s: where = %q; want %q", tc.name, where, tc.wantWhere)
+		}
+		if !reflect.DeepEqual(args, tc.wantArgs) {
+			t.Errorf("%s: args = %#v; want %#v", tc.name, args, tc.wantArgs)
+		}
+	}
+}
+
+func TestFTTokenListFilterCountPlaceholders(t *testing.T) {
+	t.Parallel()
+
+	where, args := FTTokenListFilter{Verified: true, Query: "a"}.where(1)
+	if want := "WHERE ft.is_verified AND (lower(ft.symbol) LIKE $1 OR lower(ft.name) LIKE $1)"; where != want {
+		t.Fatalf("where = %q; want %q", where, want)
+	}
+	if len(args) != 1 {
+		t.Fatalf("args = %#v", args)
+	}
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIndexingErrorsWhere(t *testing.T) {
+	t.Parallel()
+
+	unresolved := false
+	resolved := true
+	cases := []struct {
+		name       string
+		filter     IndexingErrorFilter
+		withCursor bool
+		wantWhere  string
+		wantArgs   []interface{}
+	}{
+		{"no filter", IndexingErrorFilter{}, true, "TRUE", nil},
+		{"worker", IndexingErrorFilter{Worker: "token_worker"}, true, "worker_name = $1", []interface{}{"token_worker"}},
+		{"error hash", IndexingErrorFilter{ErrorHash: "ab12"}, true, "error_hash = $1", []interface{}{"ab12"}},
+		{"unresolved", IndexingErrorFilter{Resolved: &unresolved}, true, "resolved = $1", []interface{}{false}},
+		{"resolved", IndexingErrorFilter{Resolved: &resolved}, true, "resolved = $1", []interface{}{true}},
+		{"height from", IndexingErrorFilter{HeightFrom: 100}, true, "block_height >= $1", []interface{}{uint64(100)}},
+		{"height to", IndexingErrorFilter{HeightTo: 200}, true, "block_height <= $1", []interface{}{uint64(200)}},
+		{"cursor", IndexingErrorFilter{Cursor: 42}, true, "id > $1", []interface{}{int64(42)}},
+		{"cursor ignored for counts", IndexingErrorFilter{Cursor: 42}, false, "TRUE", nil},
+		{
+			"combined",
+			IndexingErrorFilter{Worker: "evm_worker", ErrorHash: "ff", Resolved: &unresolved, HeightFrom: 10, HeightTo: 20, Cursor: 7},
+			true,
+			"worker_name = $1 AND error_hash = $2 AND resolved = $3 AND block_height >= $4 AND block_height <= $5 AND id > $6",
+			[]interface{}{"evm_worker", "ff", false, uint64(10), uint64(20), int64(7)},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			where, args := indexingErrorsWhere(tc.filter, tc.withCursor)
+			if where != tc.wantWhere {
+				t.Fatalf("where = %q, want %q", where, tc.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Fatalf("args = %#v, want %#v", args, tc.wantArgs)
+			}
+		})
+	}
+}
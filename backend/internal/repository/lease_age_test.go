@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestGetOldestLeaseAge needs a database with the schema applied. Completed
+// leases are ignored; among outstanding ones the earliest claim wins.
+func TestGetOldestLeaseAge(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	repo, err := NewRepository(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	ctx := context.Background()
+
+	const worker = "test_oldest_lease_worker"
+	cleanup := func() {
+		repo.db.Exec(ctx, `DELETE FROM app.worker_leases WHERE worker_type = $1`, worker)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	if l, err := repo.GetOldestLeaseAge(ctx, worker); err != nil || l != nil {
+		t.Fatalf("no leases: %+v, %v", l, err)
+	}
+
+	// from_height, status, attempt, claimed this long ago
+	fixtures := []struct {
+		from    uint64
+		status  string
+		attempt int
+		ago     time.Duration
+	}{
+		{0, "COMPLETED", 0, 3 * time.Hour},
+		{1000, "FAILED", 7, 2 * time.Hour}, // the poison pill
+		{2000, "ACTIVE", 0, 2 * time.Minute},
+		{3000, "COMPLETED", 1, 4 * time.Hour},
+	}
+	for _, f := range fixtures {
+		if _, err := repo.db.Exec(ctx, `
+			INSERT INTO app.worker_leases (worker_type, from_height, to_height, leased_by, lease_expires_at, status, attempt, created_at)
+			VALUES ($1, $2, $3, 'test', NOW(), $4, $5, NOW() - make_interval(secs => $6))`,
+			worker, f.from, f.from+1000, f.status, f.attempt, f.ago.Seconds()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l, err := repo.GetOldestLeaseAge(ctx, worker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l == nil || l.FromHeight != 1000 || l.ToHeight != 2000 || l.Status != "FAILED" || l.Attempt != 7 {
+		t.Fatalf("oldest lease = %+v", l)
+	}
+	if l.Age < 2*time.Hour || l.Age > 2*time.Hour+time.Minute {
+		t.Fatalf("age = %s; want about 2h", l.Age)
+	}
+}
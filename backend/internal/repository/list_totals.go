@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// listTotalSource describes where the size of a list endpoint comes from: the
+// planner's reltuples over its partitions, or a real COUNT(*) on the parent.
+type listTotalSource struct {
+	schema     string
+	relPattern string // partition name pattern for estimatePartitionCount
+	table      string // parent table for the exact count
+}
+
+var listTotalSources = map[string]listTotalSource{
+	"blocks":       {"raw", "blocks_p%", "raw.blocks"},
+	"transactions": {"raw", "transactions_p%", "raw.transactions"},
+	"ft_transfers": {"app", "ft_transfers_p%", "app.ft_transfers"},
+}
+
+// EstimateListTotal returns the reltuples estimate for a list ("blocks",
+// "transactions" or "ft_transfers"). It is cheap but only as fresh as the last
+// ANALYZE, so it can be far off right after bulk loads.
+func (r *Repository) EstimateListTotal(ctx context.Context, list string) (int64, error) {
+	src, ok := listTotalSources[list]
+	if !ok {
+		return 0, fmt.Errorf("unknown list %q", list)
+	}
+	return r.estimatePartitionCount(ctx, src.schema, src.relPattern)
+}
+
+// ExactListTotal runs COUNT(*) over every partition of a list. This is a full
+// (parallel) scan of tables with hundreds of millions of rows; callers must
+// bound it with a context deadline.
+func (r *Repository) ExactListTotal(ctx context.Context, list string) (int64, error) {
+	src, ok := listTotalSources[list]
+	if !ok {
+		return 0, fmt.Errorf("unknown list %q", list)
+	}
+	var n int64
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM "+src.table).Scan(&n)
+	return n, err
+}
@@ -57,7 +57,7 @@ func (r *Repository) GetFeeMetricPoints(ctx context.Context, from, to time.Time,
 		ORDER BY b.bucket ASC
 	`, timescaleToDateTrunc(timescale), timescaleToDateTrunc(timescale), step, bucketExpr)
 
-	rows, err := r.db.Query(ctx, query, from.UTC(), to.UTC())
+	rows, err := r.readDB().Query(ctx, query, from.UTC(), to.UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"flowscan-clone/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// epochPhaseNames maps FlowEpoch.EpochPhase raw values to names.
+var epochPhaseNames = map[uint64]string{
+	0: "staking_auction",
+	1: "epoch_setup",
+	2: "epoch_commit",
+}
+
+// CurrentEpoch is the network's current epoch as last seen by the
+// NetworkPoller. StartHeight and EndHeight are zero until the staking worker
+// has indexed the corresponding epoch events.
+type CurrentEpoch struct {
+	Epoch       uint64    `json:"epoch"`
+	Phase       uint64    `json:"phase"`
+	PhaseName   string    `json:"phase_name"`
+	Progress    float64   `json:"progress"`
+	StartView   uint64    `json:"start_view"`
+	EndView     uint64    `json:"end_view"`
+	CurrentView uint64    `json:"current_view"`
+	StartHeight uint64    `json:"start_height"`
+	EndHeight   uint64    `json:"end_height"`
+	NodeCount   int       `json:"node_count"`
+	AsOf        time.Time `json:"as_of"`
+}
+
+// currentEpochFromSnapshot decodes the poller's epoch_status snapshot.
+func currentEpochFromSnapshot(snap *models.StatusSnapshot) (*CurrentEpoch, error) {
+	var p struct {
+		Epoch       uint64  `json:"epoch"`
+		Phase       uint64  `json:"phase"`
+		Progress    float64 `json:"epoch_progress"`
+		StartView   uint64  `json:"start_view"`
+		EndView     uint64  `json:"end_view"`
+		CurrentView uint64  `json:"current_view"`
+	}
+	if err := json.Unmarshal(snap.Payload, &p); err != nil {
+		return nil, fmt.Errorf("decode epoch_status snapshot: %w", err)
+	}
+	return &CurrentEpoch{
+		Epoch:       p.Epoch,
+		Phase:       p.Phase,
+		PhaseName:   epochPhaseNames[p.Phase],
+		Progress:    p.Progress,
+		StartView:   p.StartView,
+		EndView:     p.EndView,
+		CurrentView: p.CurrentView,
+		AsOf:        snap.AsOf,
+	}, nil
+}
+
+// applyStats fills heights and the node count from the epoch's
+// app.epoch_stats row (nil when missing) and its staking node count, falling
+// back to the row's total_nodes before the node list has been polled.
+func (e *CurrentEpoch) applyStats(stats *models.EpochStats, nodeCount int) {
+	e.NodeCount = nodeCount
+	if stats == nil || uint64(stats.Epoch) != e.Epoch {
+		return
+	}
+	e.StartHeight = stats.StartHeight
+	e.EndHeight = stats.EndHeight
+	if e.NodeCount == 0 {
+		e.NodeCount = stats.TotalNodes
+	}
+}
+
+// GetCurrentEpoch returns the current epoch, or ErrNotFound when the
+// NetworkPoller has not stored an epoch_status snapshot yet.
+func (r *Repository) GetCurrentEpoch(ctx context.Context) (*CurrentEpoch, error) {
+	snap, err := r.GetStatusSnapshot(ctx, "epoch_status")
+	if err != nil {
+		return nil, fmt.Errorf("current epoch: %w", err)
+	}
+	if snap == nil {
+		return nil, fmt.Errorf("current epoch: %w", ErrNotFound)
+	}
+	out, err := currentEpochFromSnapshot(snap)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats *models.EpochStats
+	var s models.EpochStats
+	err = r.queryRow(ctx, "current_epoch_stats", `
+		SELECT epoch, COALESCE(start_height, 0), COALESCE(end_height, 0), COALESCE(total_nodes, 0)
+		FROM app.epoch_stats
+		WHERE epoch = $1`, int64(out.Epoch)).Scan(&s.Epoch, &s.StartHeight, &s.EndHeight, &s.TotalNodes)
+	switch {
+	case err == nil:
+		stats = &s
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, wrapDBErr(err, "current epoch stats")
+	}
+
+	var nodes int
+	if err := r.queryRow(ctx, "current_epoch_nodes", `
+		SELECT COUNT(*) FROM app.staking_nodes WHERE epoch = $1`, int64(out.Epoch)).Scan(&nodes); err != nil {
+		return nil, wrapDBErr(err, "current epoch nodes")
+	}
+	out.applyStats(stats, nodes)
+	return out, nil
+}
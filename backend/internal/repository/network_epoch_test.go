@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+// epochStatusFixture is an epoch_status snapshot as written by NetworkPoller.
+var epochStatusFixture = &models.StatusSnapshot{
+	Kind: "epoch_status",
+	Payload: []byte(`{"epoch":112,"epoch_progress":41.27,"phase":1,` +
+		`"start_view":70500000,"end_view":71100000,"current_view":70747620,"updated_at":1760000000}`),
+	AsOf: time.Date(2026, 10, 9, 12, 0, 0, 0, time.UTC),
+}
+
+func TestCurrentEpochFromSnapshot(t *testing.T) {
+	t.Parallel()
+
+	e, err := currentEpochFromSnapshot(epochStatusFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Epoch != 112 || e.Phase != 1 || e.PhaseName != "epoch_setup" || e.Progress != 41.27 {
+		t.Fatalf("epoch = %+v", e)
+	}
+	if e.StartView != 70500000 || e.EndView != 71100000 || e.CurrentView != 70747620 {
+		t.Fatalf("views = %+v", e)
+	}
+	if !e.AsOf.Equal(epochStatusFixture.AsOf) {
+		t.Fatalf("as_of = %v", e.AsOf)
+	}
+
+	if _, err := currentEpochFromSnapshot(&models.StatusSnapshot{Payload: []byte("not json")}); err == nil {
+		t.Fatal("expected a decode error")
+	}
+}
+
+func TestCurrentEpochApplyStats(t *testing.T) {
+	t.Parallel()
+
+	stats := &models.EpochStats{Epoch: 112, StartHeight: 130000000, TotalNodes: 480}
+
+	e, _ := currentEpochFromSnapshot(epochStatusFixture)
+	e.applyStats(stats, 502)
+	if e.StartHeight != 130000000 || e.EndHeight != 0 || e.NodeCount != 502 {
+		t.Fatalf("with node list = %+v", e)
+	}
+
+	e, _ = currentEpochFromSnapshot(epochStatusFixture)
+	e.applyStats(stats, 0)
+	if e.NodeCount != 480 {
+		t.Fatalf("node count fallback = %d; want 480", e.NodeCount)
+	}
+
+	// A stats row for another epoch is ignored.
+	e, _ = currentEpochFromSnapshot(epochStatusFixture)
+	e.applyStats(&models.EpochStats{Epoch: 111, StartHeight: 1, TotalNodes: 9}, 0)
+	if e.StartHeight != 0 || e.NodeCount != 0 {
+		t.Fatalf("stale stats applied: %+v", e)
+	}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"sort"
+)
+
+// nftHistoryLimit caps the provenance chain returned for a single NFT.
+const nftHistoryLimit = 1000
+
+// NFT history entry kinds.
+const (
+	NFTHistoryMint     = "mint"
+	NFTHistoryTransfer = "transfer"
+	NFTHistoryBurn     = "burn"
+)
+
+// NFTHistoryEntry is one hop in the provenance chain of a single NFT.
+type NFTHistoryEntry struct {
+	TokenTransferWithContract
+	Kind string
+}
+
+// nftHistoryKind classifies a transfer row: no sender means a mint, no
+// receiver means a burn (matching RefreshNFTMintBurnCounts).
+func nftHistoryKind(from, to string) string {
+	switch {
+	case from == "" && to != "":
+		return NFTHistoryMint
+	case to == "" && from != "":
+		return NFTHistoryBurn
+	default:
+		return NFTHistoryTransfer
+	}
+}
+
+// buildNFTHistory orders transfers chronologically and labels each hop.
+func buildNFTHistory(transfers []TokenTransferWithContract) []NFTHistoryEntry {
+	sort.SliceStable(transfers, func(i, j int) bool {
+		if transfers[i].BlockHeight != transfers[j].BlockHeight {
+			return transfers[i].BlockHeight < transfers[j].BlockHeight
+		}
+		return transfers[i].EventIndex < transfers[j].EventIndex
+	})
+	out := make([]NFTHistoryEntry, 0, len(transfers))
+	for _, t := range transfers {
+		out = append(out, NFTHistoryEntry{
+			TokenTransferWithContract: t,
+			Kind:                      nftHistoryKind(t.FromAddress, t.ToAddress),
+		})
+	}
+	return out
+}
+
+// GetNFTTransferHistory returns the full transfer chain of one NFT, oldest
+// first, so a mint (if indexed) comes first and a burn last. collectionName
+// may be empty to match any contract at the address.
+func (r *Repository) GetNFTTransferHistory(ctx context.Context, collectionAddress, collectionName, tokenID string) ([]NFTHistoryEntry, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			encode(t.transaction_id, 'hex') AS transaction_id,
+			t.block_height,
+			COALESCE(encode(t.token_contract_address, 'hex'), '') AS token_contract_address,
+			COALESCE(encode(t.from_address, 'hex'), '') AS from_address,
+			COALESCE(encode(t.to_address, 'hex'), '') AS to_address,
+			COALESCE(t.token_id, '') AS token_id,
+			t.event_index,
+			t.timestamp,
+			COALESCE(t.contract_name, '') AS contract_name
+		FROM app.nft_transfers t
+		WHERE t.token_contract_address = $1
+		  AND t.token_id = $2
+		  AND ($3 = '' OR t.contract_name = $3)
+		ORDER BY t.block_height ASC, t.event_index ASC
+		LIMIT $4`,
+		hexToBytes(collectionAddress), tokenID, collectionName, nftHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []TokenTransferWithContract
+	for rows.Next() {
+		var t TokenTransferWithContract
+		if err := rows.Scan(
+			&t.TransactionID,
+			&t.BlockHeight,
+			&t.TokenContractAddress,
+			&t.FromAddress,
+			&t.ToAddress,
+			&t.TokenID,
+			&t.EventIndex,
+			&t.Timestamp,
+			&t.ContractName,
+		); err != nil {
+			return nil, err
+		}
+		t.CreatedAt = t.Timestamp
+		t.IsNFT = true
+		transfers = append(transfers, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return buildNFTHistory(transfers), nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"testing"
+
+	"flowscan-clone/internal/models"
+)
+
+func nftHop(height uint64, idx int, from, to string) TokenTransferWithContract {
+	return TokenTransferWithContract{TokenTransfer: models.TokenTransfer{
+		BlockHeight: height, EventIndex: idx, FromAddress: from, ToAddress: to, TokenID: "42", IsNFT: true,
+	}}
+}
+
+func TestBuildNFTHistoryMultiHop(t *testing.T) {
+	t.Parallel()
+
+	// Deliberately out of order: mint -> alice -> bob -> (same block) carol -> burn.
+	transfers := []TokenTransferWithContract{
+		nftHop(300, 2, "00000000000000cc", ""),
+		nftHop(200, 5, "00000000000000bb", "00000000000000cc"),
+		nftHop(100, 0, "", "00000000000000aa"),
+		nftHop(200, 1, "00000000000000aa", "00000000000000bb"),
+	}
+
+	got := buildNFTHistory(transfers)
+	want := []struct {
+		height uint64
+		idx    int
+		kind   string
+	}{
+		{100, 0, NFTHistoryMint},
+		{200, 1, NFTHistoryTransfer},
+		{200, 5, NFTHistoryTransfer},
+		{300, 2, NFTHistoryBurn},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].BlockHeight != w.height || got[i].EventIndex != w.idx || got[i].Kind != w.kind {
+			t.Fatalf("entry %d = {%d %d %s}, want %+v", i, got[i].BlockHeight, got[i].EventIndex, got[i].Kind, w)
+		}
+	}
+	// Each hop's receiver is the next hop's sender.
+	for i := 1; i < len(got); i++ {
+		if got[i].FromAddress != got[i-1].ToAddress {
+			t.Fatalf("chain broken at %d: from %q, previous to %q", i, got[i].FromAddress, got[i-1].ToAddress)
+		}
+	}
+}
+
+func TestNFTHistoryKind(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct{ from, to, want string }{
+		{"", "aa", NFTHistoryMint},
+		{"aa", "", NFTHistoryBurn},
+		{"aa", "bb", NFTHistoryTransfer},
+		{"", "", NFTHistoryTransfer},
+	}
+	for _, c := range cases {
+		if got := nftHistoryKind(c.from, c.to); got != c.want {
+			t.Fatalf("nftHistoryKind(%q,%q)=%q want %q", c.from, c.to, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"flowscan-clone/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// NFT transfer kinds derived from the empty side of a transfer.
+const (
+	NFTTransferMint = "mint"
+	NFTTransferBurn = "burn"
+)
+
+// classifyNFTTransfer returns NFTTransferMint for transfers without a sender,
+// NFTTransferBurn for transfers without a receiver, and "" otherwise. A
+// transfer with neither side set is ambiguous and counts as neither.
+func classifyNFTTransfer(from, to string) string {
+	from, to = normalizeHex(from), normalizeHex(to)
+	switch {
+	case from == "" && to == "":
+		return ""
+	case from == "":
+		return NFTTransferMint
+	case to == "":
+		return NFTTransferBurn
+	}
+	return ""
+}
+
+// NFTMintBurnCounts is a per-collection delta for app.nft_collection_supply.
+type NFTMintBurnCounts struct {
+	ContractAddress string
+	ContractName    string
+	Minted          int64
+	Burned          int64
+}
+
+// tallyNFTMintBurn aggregates inserted NFT transfers into per-collection
+// mint/burn deltas, ordered by collection.
+func tallyNFTMintBurn(transfers []models.TokenTransfer) []NFTMintBurnCounts {
+	type key struct{ addr, name string }
+	byCollection := make(map[key]*NFTMintBurnCounts)
+	for _, t := range transfers {
+		kind := classifyNFTTransfer(t.FromAddress, t.ToAddress)
+		if kind == "" {
+			continue
+		}
+		k := key{normalizeHex(t.TokenContractAddress), t.ContractName}
+		c := byCollection[k]
+		if c == nil {
+			c = &NFTMintBurnCounts{ContractAddress: k.addr, ContractName: k.name}
+			byCollection[k] = c
+		}
+		if kind == NFTTransferMint {
+			c.Minted++
+		} else {
+			c.Burned++
+		}
+	}
+
+	out := make([]NFTMintBurnCounts, 0, len(byCollection))
+	for _, c := range byCollection {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ContractAddress != out[j].ContractAddress {
+			return out[i].ContractAddress < out[j].ContractAddress
+		}
+		return out[i].ContractName < out[j].ContractName
+	})
+	return out
+}
+
+// addNFTMintBurnCounts adds deltas to app.nft_collection_supply inside tx.
+// Callers must only pass deltas for newly inserted transfers so that
+// reprocessing a range never double counts.
+func addNFTMintBurnCounts(ctx context.Context, tx pgx.Tx, deltas []NFTMintBurnCounts) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, d := range deltas {
+		batch.Queue(`
+			INSERT INTO app.nft_collection_supply (contract_address, contract_name, minted_count, burned_count, updated_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			ON CONFLICT (contract_address, contract_name) DO UPDATE SET
+				minted_count = app.nft_collection_supply.minted_count + EXCLUDED.minted_count,
+				burned_count = app.nft_collection_supply.burned_count + EXCLUDED.burned_count,
+				updated_at = NOW()`,
+			hexToBytes(d.ContractAddress), d.ContractName, d.Minted, d.Burned,
+		)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+	defer br.Close()
+
+	for i := 0; i < len(deltas); i++ {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("update nft mint/burn counts: %w", err)
+		}
+	}
+	return nil
+}
+
+// RefreshNFTMintBurnCounts recomputes app.nft_collection_supply from
+// app.nft_transfers. Use it to seed the counters for history indexed before
+// they existed; it scans the whole transfers table.
+func (r *Repository) RefreshNFTMintBurnCounts(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO app.nft_collection_supply (contract_address, contract_name, minted_count, burned_count, updated_at)
+		SELECT token_contract_address, COALESCE(contract_name, ''),
+		       COUNT(*) FILTER (WHERE COALESCE(length(from_address), 0) = 0),
+		       COUNT(*) FILTER (WHERE COALESCE(length(to_address), 0) = 0),
+		       NOW()
+		FROM app.nft_transfers
+		WHERE token_contract_address IS NOT NULL
+		  AND (COALESCE(length(from_address), 0) = 0) <> (COALESCE(length(to_address), 0) = 0)
+		GROUP BY token_contract_address, COALESCE(contract_name, '')
+		ON CONFLICT (contract_address, contract_name) DO UPDATE SET
+			minted_count = EXCLUDED.minted_count,
+			burned_count = EXCLUDED.burned_count,
+			updated_at = NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("refresh nft mint/burn counts: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetNFTMintBurnHistory lists mints or burns (kind) of a collection, newest
+// first, optionally limited to blocks in [fromHeight, toHeight].
+func (r *Repository) GetNFTMintBurnHistory(ctx context.Context, collectionAddr, collectionName, kind string, fromHeight, toHeight *uint64, limit, offset int) ([]TokenTransferWithContract, bool, error) {
+	clauses := []string{"t.token_contract_address = $1"}
+	args := []interface{}{hexToBytes(collectionAddr)}
+	switch kind {
+	case NFTTransferMint:
+		clauses = append(clauses, "COALESCE(length(t.from_address), 0) = 0", "COALESCE(length(t.to_address), 0) > 0")
+	case NFTTransferBurn:
+		clauses = append(clauses, "COALESCE(length(t.to_address), 0) = 0", "COALESCE(length(t.from_address), 0) > 0")
+	default:
+		return nil, false, fmt.Errorf("unknown nft transfer kind %q", kind)
+	}
+	if collectionName != "" {
+		args = append(args, collectionName)
+		clauses = append(clauses, fmt.Sprintf("t.contract_name = $%d", len(args)))
+	}
+	if fromHeight != nil {
+		args = append(args, *fromHeight)
+		clauses = append(clauses, fmt.Sprintf("t.block_height >= $%d", len(args)))
+	}
+	if toHeight != nil {
+		args = append(args, *toHeight)
+		clauses = append(clauses, fmt.Sprintf("t.block_height <= $%d", len(args)))
+	}
+	args = append(args, limit+1, offset)
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			encode(t.transaction_id, 'hex'),
+			t.block_height,
+			COALESCE(encode(t.token_contract_address, 'hex'), ''),
+			COALESCE(encode(t.from_address, 'hex'), ''),
+			COALESCE(encode(t.to_address, 'hex'), ''),
+			COALESCE(t.token_id, ''),
+			t.event_index,
+			t.timestamp,
+			COALESCE(t.contract_name, '')
+		FROM app.nft_transfers t
+		WHERE `+strings.Join(clauses, " AND ")+`
+		ORDER BY t.block_height DESC, t.event_index DESC
+		LIMIT $`+fmt.Sprint(len(args)-1)+` OFFSET $`+fmt.Sprint(len(args)), args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	var out []TokenTransferWithContract
+	for rows.Next() {
+		var t TokenTransferWithContract
+		if err := rows.Scan(&t.TransactionID, &t.BlockHeight, &t.TokenContractAddress, &t.FromAddress, &t.ToAddress,
+			&t.TokenID, &t.EventIndex, &t.Timestamp, &t.ContractName); err != nil {
+			return nil, false, err
+		}
+		t.IsNFT = true
+		t.Amount = "1"
+		t.CreatedAt = t.Timestamp
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	hasMore := len(out) > limit
+	if hasMore {
+		out = out[:limit]
+	}
+	return out, hasMore, nil
+}
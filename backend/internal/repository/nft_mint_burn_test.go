@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"testing"
+
+	"flowscan-clone/internal/models"
+)
+
+func TestClassifyNFTTransfer(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		from, to string
+		want     string
+	}{
+		{"", "0x1d7e57aa55817448", NFTTransferMint},
+		{"1d7e57aa55817448", "", NFTTransferBurn},
+		{"1d7e57aa55817448", "0b2a3299cc857e29", ""},
+		{"", "", ""},
+		{"0x", "\\x", ""},
+	}
+	for _, c := range cases {
+		if got := classifyNFTTransfer(c.from, c.to); got != c.want {
+			t.Errorf("classifyNFTTransfer(%q, %q) = %q, want %q", c.from, c.to, got, c.want)
+		}
+	}
+}
+
+func TestTallyNFTMintBurn(t *testing.T) {
+	t.Parallel()
+
+	const (
+		topShot = "0b2a3299cc857e29"
+		alice   = "1d7e57aa55817448"
+		bob     = "e4cf4bdc1751c65d"
+	)
+	nft := func(contract, name, from, to string) models.TokenTransfer {
+		return models.TokenTransfer{TokenContractAddress: contract, ContractName: name, FromAddress: from, ToAddress: to, TokenID: "1", IsNFT: true}
+	}
+	// Mint to alice, transfer to bob, bob burns; plus a second mint in another
+	// collection and an ambiguous row with neither side set.
+	got := tallyNFTMintBurn([]models.TokenTransfer{
+		nft(topShot, "TopShot", "", alice),
+		nft(topShot, "TopShot", alice, bob),
+		nft("0x"+topShot, "TopShot", bob, ""),
+		nft(alice, "Pets", "", bob),
+		nft(topShot, "TopShot", "", ""),
+	})
+
+	want := []NFTMintBurnCounts{
+		{ContractAddress: topShot, ContractName: "TopShot", Minted: 1, Burned: 1},
+		{ContractAddress: alice, ContractName: "Pets", Minted: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d collections, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"flowscan-clone/internal/models"
+)
+
+// nftIDOrderKey is the sort key for NFT ids within a collection: numeric ids
+// first, ordered by value (shorter digit strings first, then digit by digit),
+// then any non-numeric ids ordered lexically. Ids with leading zeros sort by
+// their length, so "007" comes after "7" but the order is still total.
+// idx_nft_ownership_owner_items indexes the same expressions.
+func nftIDOrderKey(col string) string {
+	return fmt.Sprintf("%[1]s !~ '^[0-9]+$', CASE WHEN %[1]s ~ '^[0-9]+$' THEN length(%[1]s) ELSE 0 END, %[1]s", col)
+}
+
+// NFTOwnershipCursor is the last item of a page of
+// ListNFTOwnershipByOwnerAndCollection.
+type NFTOwnershipCursor struct {
+	ContractName string
+	NFTID        string
+}
+
+func (c NFTOwnershipCursor) String() string {
+	return c.ContractName + ":" + c.NFTID
+}
+
+// ParseNFTOwnershipCursor parses a cursor produced by NFTOwnershipCursor.String.
+// Contract names cannot contain ':', so everything after the first one is the
+// NFT id.
+func ParseNFTOwnershipCursor(s string) (*NFTOwnershipCursor, error) {
+	name, id, ok := strings.Cut(s, ":")
+	if !ok || id == "" {
+		return nil, fmt.Errorf("invalid nft cursor %q", s)
+	}
+	return &NFTOwnershipCursor{ContractName: name, NFTID: id}, nil
+}
+
+func nftOwnershipByOwnerQuery(owner, collection, contractName string, limit, offset int, cursor *NFTOwnershipCursor) (string, []interface{}) {
+	args := []interface{}{hexToBytes(owner), hexToBytes(collection), contractName}
+	var where string
+	if cursor != nil {
+		args = append(args, cursor.ContractName, cursor.NFTID)
+		where = fmt.Sprintf(`
+		  AND (contract_name, %s) > ($4, %s)`, nftIDOrderKey("nft_id"), nftIDOrderKey("$5::text"))
+		offset = 0
+	}
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT encode(contract_address, 'hex') AS contract_address, COALESCE(contract_name, '') AS contract_name, nft_id,
+		       COALESCE(encode(owner, 'hex'), '') AS owner, COALESCE(last_height,0), updated_at
+		FROM app.nft_ownership
+		WHERE owner = $1 AND contract_address = $2 AND ($3 = '' OR contract_name = $3)%s
+		ORDER BY contract_name ASC, %s
+		LIMIT $%d OFFSET $%d`, where, nftIDOrderKey("nft_id"), len(args)-1, len(args))
+	return query, args
+}
+
+// ListNFTOwnershipByOwnerAndCollection lists the NFTs owner holds in a
+// collection in nftIDOrderKey order. With a cursor the page starts after it
+// and offset is ignored; deep pages then cost the same as the first.
+func (r *Repository) ListNFTOwnershipByOwnerAndCollection(ctx context.Context, owner, collection, contractName string, limit, offset int, cursor *NFTOwnershipCursor) ([]models.NFTOwnership, error) {
+	query, args := nftOwnershipByOwnerQuery(owner, collection, contractName, limit, offset, cursor)
+	rows, err := r.query(ctx, "nft_ownership_by_owner_collection", query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.NFTOwnership
+	for rows.Next() {
+		var o models.NFTOwnership
+		if err := rows.Scan(&o.ContractAddress, &o.ContractName, &o.NFTID, &o.Owner, &o.LastHeight, &o.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Listing states in app.nft_listings.
+const (
+	NFTListingAvailable = "available"
+	NFTListingSold      = "sold"
+	NFTListingRemoved   = "removed" // completed without a purchase (cancelled or expired)
+)
+
+// NFTListing is a marketplace listing from a ListingAvailable event.
+type NFTListing struct {
+	Marketplace       string
+	ListingID         string
+	Seller            string
+	CollectionAddress string
+	CollectionName    string
+	NFTID             string
+	Price             string
+	PaymentToken      string // vault type identifier, e.g. A.1654653399040a61.FlowToken.Vault
+	BlockHeight       uint64
+	TransactionID     string
+	Timestamp         time.Time
+}
+
+// NFTListingClose records a ListingCompleted event for a listing.
+type NFTListingClose struct {
+	Marketplace string
+	ListingID   string
+	Status      string // NFTListingSold or NFTListingRemoved
+	BlockHeight uint64
+}
+
+// NFTSale is one completed purchase.
+type NFTSale struct {
+	BlockHeight       uint64    `json:"block_height"`
+	TransactionID     string    `json:"transaction_id"`
+	EventIndex        int       `json:"event_index"`
+	Marketplace       string    `json:"marketplace"`
+	ListingID         string    `json:"listing_id"`
+	CollectionAddress string    `json:"collection_address"`
+	CollectionName    string    `json:"collection_name"`
+	NFTID             string    `json:"nft_id"`
+	Price             string    `json:"price"`
+	PaymentToken      string    `json:"payment_token"`
+	Seller            string    `json:"seller"`
+	Buyer             string    `json:"buyer"`
+	Timestamp         time.Time `json:"timestamp"`
+}
+
+// NFTSaleStats is a collection's market summary in one payment token.
+type NFTSaleStats struct {
+	PaymentToken   string `json:"payment_token"`
+	Floor          string `json:"floor"`
+	ActiveListings int64  `json:"active_listings"`
+	Volume24h      string `json:"volume_24h"`
+	Sales24h       int64  `json:"sales_24h"`
+	SalesCount     int64  `json:"sales_count"`
+}
+
+// UpsertNFTListings stores ListingAvailable events. A listing that was already
+// closed (its completion processed first, e.g. during backfill) keeps its status.
+func (r *Repository) UpsertNFTListings(ctx context.Context, listings []NFTListing) error {
+	if len(listings) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for _, l := range listings {
+		batch.Queue(`
+			INSERT INTO app.nft_listings (marketplace, listing_id, seller, collection_address, collection_name,
+				nft_id, price, payment_token, status, block_height, transaction_id, timestamp)
+			VALUES ($1, $2, $3, $4, $5, $6, $7::numeric, NULLIF($8, ''), 'available', $9, $10, $11)
+			ON CONFLICT (marketplace, listing_id) DO UPDATE SET
+				seller = EXCLUDED.seller,
+				collection_address = EXCLUDED.collection_address,
+				collection_name = EXCLUDED.collection_name,
+				nft_id = EXCLUDED.nft_id,
+				price = EXCLUDED.price,
+				payment_token = EXCLUDED.payment_token,
+				block_height = EXCLUDED.block_height,
+				transaction_id = EXCLUDED.transaction_id,
+				timestamp = EXCLUDED.timestamp`,
+			l.Marketplace, l.ListingID, hexToBytes(l.Seller), hexToBytes(l.CollectionAddress), l.CollectionName,
+			l.NFTID, numericOrZero(l.Price), l.PaymentToken, l.BlockHeight, hexToBytes(l.TransactionID), l.Timestamp)
+	}
+	br := r.db.SendBatch(ctx, batch)
+	defer br.Close()
+	for range listings {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("upsert nft listings: %w", err)
+		}
+	}
+	return nil
+}
+
+// CloseNFTListings marks listings sold or removed. A close seen before its
+// listing inserts a placeholder row that UpsertNFTListings fills in later.
+func (r *Repository) CloseNFTListings(ctx context.Context, closes []NFTListingClose) error {
+	if len(closes) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for _, c := range closes {
+		batch.Queue(`
+			INSERT INTO app.nft_listings (marketplace, listing_id, status, completed_height, block_height)
+			VALUES ($1, $2, $3, $4, $4)
+			ON CONFLICT (marketplace, listing_id) DO UPDATE SET
+				status = EXCLUDED.status,
+				completed_height = EXCLUDED.completed_height`,
+			c.Marketplace, c.ListingID, c.Status, c.BlockHeight)
+	}
+	br := r.db.SendBatch(ctx, batch)
+	defer br.Close()
+	for range closes {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("close nft listings: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetNFTListings loads stored listings by id for one marketplace, keyed by
+// listing id. Placeholder rows without listing details are skipped.
+func (r *Repository) GetNFTListings(ctx context.Context, marketplace string, listingIDs []string) (map[string]NFTListing, error) {
+	out := make(map[string]NFTListing, len(listingIDs))
+	if len(listingIDs) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT listing_id, COALESCE(encode(seller, 'hex'), ''), encode(collection_address, 'hex'), collection_name,
+			COALESCE(nft_id, ''), COALESCE(price::text, ''), COALESCE(payment_token, ''), block_height, encode(transaction_id, 'hex'), timestamp
+		FROM app.nft_listings
+		WHERE marketplace = $1 AND listing_id = ANY($2) AND collection_address IS NOT NULL`,
+		marketplace, listingIDs)
+	if err != nil {
+		return nil, wrapDBErr(err, "get nft listings")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		l := NFTListing{Marketplace: marketplace}
+		if err := rows.Scan(&l.ListingID, &l.Seller, &l.CollectionAddress, &l.CollectionName,
+			&l.NFTID, &l.Price, &l.PaymentToken, &l.BlockHeight, &l.TransactionID, &l.Timestamp); err != nil {
+			return nil, wrapDBErr(err, "get nft listings")
+		}
+		out[l.ListingID] = l
+	}
+	return out, wrapDBErr(rows.Err(), "get nft listings")
+}
+
+// InsertNFTSales stores completed purchases; re-processing a range is a no-op.
+func (r *Repository) InsertNFTSales(ctx context.Context, sales []NFTSale) error {
+	if len(sales) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for _, s := range sales {
+		batch.Queue(`
+			INSERT INTO app.nft_sales (block_height, transaction_id, event_index, marketplace, listing_id,
+				collection_address, collection_name, nft_id, price, payment_token, seller, buyer, timestamp)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::numeric, NULLIF($10, ''), $11, $12, $13)
+			ON CONFLICT (block_height, transaction_id, event_index) DO NOTHING`,
+			s.BlockHeight, hexToBytes(s.TransactionID), s.EventIndex, s.Marketplace, s.ListingID,
+			hexToBytes(s.CollectionAddress), s.CollectionName, s.NFTID, numericOrZero(s.Price), s.PaymentToken,
+			hexToBytes(s.Seller), hexToBytes(s.Buyer), s.Timestamp)
+	}
+	br := r.db.SendBatch(ctx, batch)
+	defer br.Close()
+	for range sales {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("insert nft sales: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetNFTSaleStats returns floor (cheapest open listing), 24h volume and sale
+// counts for a collection, one row per payment token. Listings that never
+// complete only ever count towards the floor, never towards volume.
+func (r *Repository) GetNFTSaleStats(ctx context.Context, collectionAddress, collectionName string, now time.Time) ([]NFTSaleStats, error) {
+	rows, err := r.readDB().Query(ctx, `
+		WITH listings AS (
+			SELECT COALESCE(payment_token, '') AS payment_token, MIN(price) AS floor, COUNT(*) AS active
+			FROM app.nft_listings
+			WHERE collection_address = $1 AND collection_name = $2 AND status = 'available'
+			GROUP BY 1
+		), sales AS (
+			SELECT COALESCE(payment_token, '') AS payment_token,
+				COALESCE(SUM(price) FILTER (WHERE timestamp >= $3), 0) AS volume_24h,
+				COUNT(*) FILTER (WHERE timestamp >= $3) AS sales_24h,
+				COUNT(*) AS sales_count
+			FROM app.nft_sales
+			WHERE collection_address = $1 AND collection_name = $2
+			GROUP BY 1
+		)
+		SELECT COALESCE(l.payment_token, s.payment_token),
+			COALESCE(l.floor::text, ''), COALESCE(l.active, 0),
+			COALESCE(s.volume_24h, 0)::text, COALESCE(s.sales_24h, 0), COALESCE(s.sales_count, 0)
+		FROM listings l
+		FULL OUTER JOIN sales s ON s.payment_token = l.payment_token
+		ORDER BY 6 DESC, 1`,
+		hexToBytes(collectionAddress), collectionName, now.Add(-24*time.Hour))
+	if err != nil {
+		return nil, wrapDBErr(err, "nft sale stats")
+	}
+	defer rows.Close()
+	var out []NFTSaleStats
+	for rows.Next() {
+		var s NFTSaleStats
+		if err := rows.Scan(&s.PaymentToken, &s.Floor, &s.ActiveListings, &s.Volume24h, &s.Sales24h, &s.SalesCount); err != nil {
+			return nil, wrapDBErr(err, "nft sale stats")
+		}
+		out = append(out, s)
+	}
+	return out, wrapDBErr(rows.Err(), "nft sale stats")
+}
+
+// ListNFTSales returns a collection's sales, newest first.
+func (r *Repository) ListNFTSales(ctx context.Context, collectionAddress, collectionName string, limit, offset int) ([]NFTSale, error) {
+	rows, err := r.readDB().Query(ctx, `
+		SELECT block_height, encode(transaction_id, 'hex'), event_index, marketplace, listing_id,
+			encode(collection_address, 'hex'), collection_name, nft_id, price::text, COALESCE(payment_token, ''),
+			COALESCE(encode(seller, 'hex'), ''), COALESCE(encode(buyer, 'hex'), ''), timestamp
+		FROM app.nft_sales
+		WHERE collection_address = $1 AND collection_name = $2
+		ORDER BY block_height DESC, event_index DESC
+		LIMIT $3 OFFSET $4`,
+		hexToBytes(collectionAddress), collectionName, limit, offset)
+	if err != nil {
+		return nil, wrapDBErr(err, "list nft sales")
+	}
+	defer rows.Close()
+	var out []NFTSale
+	for rows.Next() {
+		var s NFTSale
+		if err := rows.Scan(&s.BlockHeight, &s.TransactionID, &s.EventIndex, &s.Marketplace, &s.ListingID,
+			&s.CollectionAddress, &s.CollectionName, &s.NFTID, &s.Price, &s.PaymentToken,
+			&s.Seller, &s.Buyer, &s.Timestamp); err != nil {
+			return nil, wrapDBErr(err, "list nft sales")
+		}
+		out = append(out, s)
+	}
+	return out, wrapDBErr(rows.Err(), "list nft sales")
+}
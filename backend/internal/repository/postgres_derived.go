@@ -6,12 +6,14 @@ import (
 	"strings"
 	"time"
 
+	"flowscan-clone/internal/contractid"
 	"flowscan-clone/internal/models"
 
 	"github.com/jackc/pgx/v5"
 )
 
 // GetRawTransactionsInRange fetches raw transactions for a height range.
+// Scripts stored by hash only are loaded from the chain.
 func (r *Repository) GetRawTransactionsInRange(ctx context.Context, fromHeight, toHeight uint64) ([]models.Transaction, error) {
 	rows, err := r.db.Query(ctx, `
 		SELECT
@@ -22,6 +24,8 @@ func (r *Repository) GetRawTransactionsInRange(ctx context.Context, fromHeight,
 			COALESCE(encode(payer_address, 'hex'), '') AS payer_address,
 			COALESCE(ARRAY(SELECT encode(a, 'hex') FROM unnest(authorizers) a), ARRAY[]::text[]) AS authorizers,
 			COALESCE(raw.transactions.script, raw.scripts.script_text, '') AS script,
+			CASE WHEN raw.transactions.script IS NULL AND raw.scripts.script_hash IS NULL
+			     THEN COALESCE(raw.transactions.script_hash, '') ELSE '' END AS missing_script_hash,
 			gas_used,
 			timestamp
 		FROM raw.transactions
@@ -34,13 +38,36 @@ func (r *Repository) GetRawTransactionsInRange(ctx context.Context, fromHeight,
 	defer rows.Close()
 
 	var txs []models.Transaction
+	missing := make(map[int]string)
 	for rows.Next() {
 		var t models.Transaction
-		if err := rows.Scan(&t.ID, &t.BlockHeight, &t.TransactionIndex, &t.ProposerAddress, &t.PayerAddress, &t.Authorizers, &t.Script, &t.GasUsed, &t.Timestamp); err != nil {
+		var missingHash string
+		if err := rows.Scan(&t.ID, &t.BlockHeight, &t.TransactionIndex, &t.ProposerAddress, &t.PayerAddress, &t.Authorizers, &t.Script, &missingHash, &t.GasUsed, &t.Timestamp); err != nil {
 			return nil, err
 		}
+		if missingHash != "" {
+			missing[len(txs)] = missingHash
+		}
 		txs = append(txs, t)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if len(missing) > 0 {
+		hashes := make([]string, 0, len(missing))
+		for _, h := range missing {
+			hashes = append(hashes, h)
+		}
+		texts := make(map[string]string, len(hashes))
+		if err := r.fillMissingScripts(ctx, hashes, texts); err != nil {
+			return nil, err
+		}
+		for i, h := range missing {
+			txs[i].Script = texts[h]
+		}
+	}
 	return txs, nil
 }
 
@@ -124,6 +151,7 @@ func (r *Repository) UpsertSmartContracts(ctx context.Context, contracts []model
 
 	batch := &pgx.Batch{}
 	for _, c := range contracts {
+		id := contractid.FromParts(c.Address, c.Name)
 		batch.Queue(`
 			INSERT INTO app.smart_contracts (address, name, code, last_updated_height, created_at, updated_at)
 			VALUES ($1, $2, NULLIF($3, ''), $4, NOW(), NOW())
@@ -134,7 +162,7 @@ func (r *Repository) UpsertSmartContracts(ctx context.Context, contracts []model
 				               THEN app.smart_contracts.version + 1
 				               ELSE app.smart_contracts.version END,
 				updated_at = NOW()`,
-			hexToBytes(c.Address), c.Name, c.Code, c.BlockHeight,
+			hexToBytes(id.Address), id.Name, c.Code, c.BlockHeight,
 		)
 	}
 
@@ -201,10 +229,12 @@ func (r *Repository) UpsertContractRegistry(ctx context.Context, rows []models.S
 	// per (address, name) to avoid duplicate-key errors within the same batch.
 	deduped := make(map[string]models.SmartContract, len(rows))
 	for _, c := range rows {
-		if c.Address == "" || c.Name == "" {
+		id := contractid.FromParts(c.Address, c.Name)
+		if id.Address == "" || id.Name == "" {
 			continue
 		}
-		key := c.Address + ":" + c.Name
+		c.Address, c.Name = id.Address, id.Name
+		key := id.String()
 		if existing, ok := deduped[key]; ok {
 			if c.FirstSeenHeight > 0 && (existing.FirstSeenHeight == 0 || c.FirstSeenHeight < existing.FirstSeenHeight) {
 				existing.FirstSeenHeight = c.FirstSeenHeight
@@ -272,31 +302,58 @@ func (r *Repository) UpsertContractRegistry(ctx context.Context, rows []models.S
 	return nil
 }
 
-// UpsertAddressTransactions inserts address->tx relations.
+// UpsertAddressTransactions inserts address->tx relations and bumps the role
+// counters in app.address_stats for rows that were not already present.
 func (r *Repository) UpsertAddressTransactions(ctx context.Context, rows []models.AddressTransaction) error {
 	if len(rows) == 0 {
 		return nil
 	}
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin address transactions: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	batch := &pgx.Batch{}
 	for _, at := range rows {
+		// Rows below an address's compaction cut were trimmed on purpose and
+		// are already counted; skip them.
 		batch.Queue(`
 			INSERT INTO app.address_transactions (address, transaction_id, block_height, role)
-			VALUES ($1, $2, $3, $4)
-			ON CONFLICT (address, block_height, transaction_id, role) DO NOTHING`,
+			SELECT $1::bytea, $2::bytea, $3::bigint, $4::varchar
+			WHERE NOT EXISTS (
+				SELECT 1 FROM app.address_stats st
+				WHERE st.address = $1::bytea AND $3::bigint < st.compacted_below_height
+			)
+			ON CONFLICT (address, block_height, transaction_id, role) DO NOTHING
+			RETURNING 1`,
 			hexToBytes(at.Address), hexToBytes(at.TransactionID), at.BlockHeight, at.Role,
 		)
 	}
 
-	br := r.db.SendBatch(ctx, batch)
-	defer br.Close()
-
-	for i := 0; i < len(rows); i++ {
-		if _, err := br.Exec(); err != nil {
+	br := tx.SendBatch(ctx, batch)
+	inserted := make([]models.AddressTransaction, 0, len(rows))
+	for _, at := range rows {
+		var one int
+		err := br.QueryRow().Scan(&one)
+		if err == pgx.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			br.Close()
 			return fmt.Errorf("upsert address transactions: %w", err)
 		}
+		inserted = append(inserted, at)
 	}
-	return nil
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("upsert address transactions: %w", err)
+	}
+
+	if err := addAddressRoleCounts(ctx, tx, tallyAddressRoles(inserted)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 // UpsertEVMTxHashes inserts EVM hash mappings derived from raw.events.
@@ -450,6 +507,55 @@ func (r *Repository) UpsertEVMTxHashes(ctx context.Context, rows []models.EVMTxH
 	return nil
 }
 
+// UpsertEVMLogs writes decoded EVM logs to app.evm_logs. A log is identified
+// by (evm_hash, log_index) and never changes, so re-processing a range is a
+// no-op.
+func (r *Repository) UpsertEVMLogs(ctx context.Context, rows []models.EVMLog) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	batch := &pgx.Batch{}
+	for _, row := range rows {
+		ts := row.Timestamp
+		if ts.IsZero() {
+			ts = now
+		}
+		topics := make([]interface{}, 4)
+		for i := 0; i < len(topics) && i < len(row.Topics); i++ {
+			topics[i] = nullIfEmptyBytes(hexToBytes(row.Topics[i]))
+		}
+
+		batch.Queue(`
+			INSERT INTO app.evm_logs (
+				evm_hash, log_index, block_height, transaction_id, address,
+				topic0, topic1, topic2, topic3, data, timestamp
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (evm_hash, log_index) DO NOTHING`,
+			hexToBytes(row.EVMHash),
+			row.LogIndex,
+			row.BlockHeight,
+			hexToBytes(row.TransactionID),
+			hexToBytes(row.Address),
+			topics[0], topics[1], topics[2], topics[3],
+			nullIfEmptyBytes(hexToBytes(row.Data)),
+			ts,
+		)
+	}
+
+	br := r.db.SendBatch(ctx, batch)
+	defer br.Close()
+
+	for i := 0; i < len(rows); i++ {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("upsert evm_logs: %w", err)
+		}
+	}
+	return nil
+}
+
 // BackfillAddressTransactionsRange populates app.address_transactions from raw.transactions
 // for a block height range [fromHeight, toHeight). It is safe to run repeatedly.
 func (r *Repository) BackfillAddressTransactionsRange(ctx context.Context, fromHeight, toHeight uint64) (int64, error) {
@@ -476,6 +582,10 @@ func (r *Repository) BackfillAddressTransactionsRange(ctx context.Context, fromH
 			WHERE block_height >= $1 AND block_height < $2
 		) s
 		WHERE address IS NOT NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM app.address_stats st
+			WHERE st.address = s.address AND s.block_height < st.compacted_below_height
+		  )
 		ON CONFLICT (address, block_height, transaction_id, role) DO NOTHING
 	`, fromHeight, toHeight)
 	if err != nil {
@@ -515,8 +625,20 @@ func (r *Repository) BackfillAddressTransactionsAndStatsRange(ctx context.Contex
 				WHERE block_height >= $1 AND block_height < $2
 			) s
 			WHERE address IS NOT NULL
+			  AND NOT EXISTS (
+				SELECT 1 FROM app.address_stats st
+				WHERE st.address = s.address AND s.block_height < st.compacted_below_height
+			  )
 			ON CONFLICT (address, block_height, transaction_id, role) DO NOTHING
-			RETURNING address, transaction_id, block_height
+			RETURNING address, transaction_id, block_height, role
+		),
+		roles AS (
+			SELECT address,
+			       COUNT(*) FILTER (WHERE role = 'PAYER')::bigint AS payer_count,
+			       COUNT(*) FILTER (WHERE role = 'PROPOSER')::bigint AS proposer_count,
+			       COUNT(*) FILTER (WHERE role = 'AUTHORIZER')::bigint AS authorizer_count
+			FROM ins
+			GROUP BY address
 		),
 		dedup AS (
 			SELECT address, transaction_id, block_height
@@ -534,56 +656,24 @@ func (r *Repository) BackfillAddressTransactionsAndStatsRange(ctx context.Contex
 			 AND t.id = d.transaction_id
 			GROUP BY d.address
 		)
-		INSERT INTO app.address_stats (address, tx_count, total_gas_used, last_updated_block, created_at, updated_at)
-		SELECT address, tx_count, total_gas_used, last_updated_block, NOW(), NOW()
-		FROM agg
+		INSERT INTO app.address_stats (address, tx_count, total_gas_used, last_updated_block,
+			payer_count, proposer_count, authorizer_count, created_at, updated_at)
+		SELECT a.address, a.tx_count, a.total_gas_used, a.last_updated_block,
+		       r.payer_count, r.proposer_count, r.authorizer_count, NOW(), NOW()
+		FROM agg a
+		JOIN roles r ON r.address = a.address
 		ON CONFLICT (address) DO UPDATE SET
 			tx_count = app.address_stats.tx_count + EXCLUDED.tx_count,
 			total_gas_used = app.address_stats.total_gas_used + EXCLUDED.total_gas_used,
 			last_updated_block = GREATEST(app.address_stats.last_updated_block, EXCLUDED.last_updated_block),
+			payer_count = app.address_stats.payer_count + EXCLUDED.payer_count,
+			proposer_count = app.address_stats.proposer_count + EXCLUDED.proposer_count,
+			authorizer_count = app.address_stats.authorizer_count + EXCLUDED.authorizer_count,
 			updated_at = NOW()
 	`, fromHeight, toHeight)
 	return err
 }
 
-type AddressStatDelta struct {
-	Address          string
-	TxCount          int64
-	TotalGasUsed     uint64
-	LastUpdatedBlock uint64
-}
-
-// UpdateAddressStatsBatch updates address_stats using aggregated deltas.
-func (r *Repository) UpdateAddressStatsBatch(ctx context.Context, deltas []AddressStatDelta) error {
-	if len(deltas) == 0 {
-		return nil
-	}
-
-	batch := &pgx.Batch{}
-	for _, d := range deltas {
-		batch.Queue(`
-			INSERT INTO app.address_stats (address, tx_count, total_gas_used, last_updated_block, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, NOW(), NOW())
-			ON CONFLICT (address) DO UPDATE SET
-				tx_count = app.address_stats.tx_count + EXCLUDED.tx_count,
-				total_gas_used = app.address_stats.total_gas_used + EXCLUDED.total_gas_used,
-				last_updated_block = GREATEST(app.address_stats.last_updated_block, EXCLUDED.last_updated_block),
-				updated_at = NOW()`,
-			hexToBytes(d.Address), d.TxCount, d.TotalGasUsed, d.LastUpdatedBlock,
-		)
-	}
-
-	br := r.db.SendBatch(ctx, batch)
-	defer br.Close()
-
-	for i := 0; i < len(deltas); i++ {
-		if _, err := br.Exec(); err != nil {
-			return fmt.Errorf("update address stats: %w", err)
-		}
-	}
-	return nil
-}
-
 // CountAddressTransactions checks whether any indexed transactions exist for an address.
 // Uses EXISTS instead of COUNT(*) to avoid full-table scans on high-activity addresses.
 func (r *Repository) CountAddressTransactions(ctx context.Context, address string) (int64, error) {
@@ -2,13 +2,11 @@ package repository
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -81,6 +79,11 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 		_, _ = dbtx.Exec(ctx, "SET LOCAL synchronous_commit = off")
 	}
 
+	// Log any block ids the upsert below is about to overwrite (reorg audit).
+	if err := recordBlockReorgs(ctx, dbtx, blocks); err != nil {
+		return err
+	}
+
 	// Precompute block timestamps for downstream inserts
 	blockTimeByHeight := make(map[uint64]time.Time, len(blocks))
 
@@ -237,32 +240,8 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 	}
 
 	// 2. Insert Transactions
-	scriptInlineMaxBytes := 0
-	if v := os.Getenv("TX_SCRIPT_INLINE_MAX_BYTES"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
-			scriptInlineMaxBytes = n
-		}
-	}
-
 	// Precompute script hashes and upsert unique scripts in one statement (reduces DB round trips).
-	scriptHashes := make([]string, len(txs))
-	scriptInlines := make([]string, len(txs))
-	scriptsByHash := make(map[string]string)
-	for i := range txs {
-		scriptText := strings.TrimSpace(txs[i].Script)
-		if scriptText == "" {
-			continue
-		}
-		sum := sha256.Sum256([]byte(scriptText))
-		scriptHash := hex.EncodeToString(sum[:])
-		scriptHashes[i] = scriptHash
-		if _, ok := scriptsByHash[scriptHash]; !ok {
-			scriptsByHash[scriptHash] = sanitizeForPG(scriptText)
-		}
-		if scriptInlineMaxBytes > 0 && len(scriptText) <= scriptInlineMaxBytes {
-			scriptInlines[i] = scriptText
-		}
-	}
+	scriptHashes, scriptInlines, scriptsByHash := planScriptStorage(txs, envScriptBytes("TX_SCRIPT_INLINE_MAX_BYTES"), envScriptBytes("TX_SCRIPT_STORE_MAX_BYTES"))
 
 	if len(scriptsByHash) > 0 {
 		hashes := make([]string, 0, len(scriptsByHash))
@@ -291,77 +270,83 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 		if err == nil {
 			defer sub.Rollback(ctx)
 
-			_, errTx := sub.CopyFrom(ctx,
-				pgx.Identifier{"raw", "transactions"},
-				[]string{
-					"block_height", "id", "transaction_index",
-					"proposer_address", "payer_address", "authorizers",
-					"script_hash", "script", "arguments",
-					"status", "error_message", "is_evm",
-					"gas_limit", "gas_used", "event_count",
-					"timestamp",
-					"proposer_key_index", "proposer_sequence_number",
-				},
-				pgx.CopyFromSlice(len(txs), func(i int) ([]any, error) {
-					t := txs[i]
-
-					// Ensure timestamp is present; default to block timestamp if missing
-					txTimestamp := t.Timestamp
-					if txTimestamp.IsZero() {
-						if ts, ok := blockTimeByHeight[t.BlockHeight]; ok {
-							txTimestamp = ts
-						}
-					}
-					if txTimestamp.IsZero() {
-						txTimestamp = batchCreatedAt
-					}
+			txRow := func(i int) ([]any, error) {
+				t := txs[i]
 
-					eventCount := t.EventCount
-					if eventCount == 0 {
-						eventCount = len(t.Events)
+				// Ensure timestamp is present; default to block timestamp if missing
+				txTimestamp := t.Timestamp
+				if txTimestamp.IsZero() {
+					if ts, ok := blockTimeByHeight[t.BlockHeight]; ok {
+						txTimestamp = ts
 					}
+				}
+				if txTimestamp.IsZero() {
+					txTimestamp = batchCreatedAt
+				}
 
-					var scriptHash any
-					if scriptHashes[i] != "" {
-						scriptHash = scriptHashes[i]
-					}
-					var scriptInline any
-					if scriptInlines[i] != "" {
-						scriptInline = sanitizeForPG(scriptInlines[i])
-					}
+				eventCount := t.EventCount
+				if eventCount == 0 {
+					eventCount = len(t.Events)
+				}
 
-					var args any
-					if len(t.Arguments) > 0 {
-						args = sanitizeJSONB(t.Arguments)
-					}
+				var scriptHash any
+				if scriptHashes[i] != "" {
+					scriptHash = scriptHashes[i]
+				}
+				var scriptInline any
+				if scriptInlines[i] != "" {
+					scriptInline = sanitizeForPG(scriptInlines[i])
+				}
 
-					var errMsg any
-					if strings.TrimSpace(t.ErrorMessage) != "" {
-						errMsg = sanitizeForPG(t.ErrorMessage)
-					}
+				var args any
+				if len(t.Arguments) > 0 {
+					args = sanitizeJSONB(t.Arguments)
+				}
 
-					return []any{
-						t.BlockHeight,
-						hexToBytes(t.ID),
-						t.TransactionIndex,
-						hexToBytes(t.ProposerAddress),
-						hexToBytes(t.PayerAddress),
-						sliceHexToBytes(t.Authorizers),
-						scriptHash,
-						scriptInline,
-						args,
-						t.Status,
-						errMsg,
-						t.IsEVM,
-						t.GasLimit,
-						t.GasUsed,
-						eventCount,
-						txTimestamp,
-						int32(t.ProposerKeyIndex),
-						int64(t.ProposerSequenceNumber),
-					}, nil
-				}),
-			)
+				var errMsg any
+				if strings.TrimSpace(t.ErrorMessage) != "" {
+					errMsg = sanitizeForPG(t.ErrorMessage)
+				}
+
+				return []any{
+					t.BlockHeight,
+					hexToBytes(t.ID),
+					t.TransactionIndex,
+					hexToBytes(t.ProposerAddress),
+					hexToBytes(t.PayerAddress),
+					sliceHexToBytes(t.Authorizers),
+					scriptHash,
+					scriptInline,
+					args,
+					t.Status,
+					errMsg,
+					t.IsEVM,
+					t.GasLimit,
+					t.GasUsed,
+					eventCount,
+					txTimestamp,
+					int32(t.ProposerKeyIndex),
+					int64(t.ProposerSequenceNumber),
+					sanitizeJSONB(t.Signers),
+				}, nil
+			}
+			errTx := forEachChunk(len(txs), r.copyChunkRows, func(start, end int) error {
+				_, err := sub.CopyFrom(ctx,
+					pgx.Identifier{"raw", "transactions"},
+					[]string{
+						"block_height", "id", "transaction_index",
+						"proposer_address", "payer_address", "authorizers",
+						"script_hash", "script", "arguments",
+						"status", "error_message", "is_evm",
+						"gas_limit", "gas_used", "event_count",
+						"timestamp",
+						"proposer_key_index", "proposer_sequence_number",
+						"signers",
+					},
+					pgx.CopyFromSlice(end-start, func(j int) ([]any, error) { return txRow(start + j) }),
+				)
+				return err
+			})
 
 			if errTx == nil {
 				if err := sub.Commit(ctx); err == nil {
@@ -378,36 +363,39 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 	// raw.tx_lookup needs UPSERT semantics (id is globally unique). We batch UPSERT it with UNNEST
 	// so we don't spam the DB with per-row inserts, while still being idempotent across retries.
 	if usedCopyForTx {
-		ids := make([][]byte, 0, len(txs))
-		heights := make([]int64, 0, len(txs))
-		txIndexes := make([]int32, 0, len(txs))
-		timestamps := make([]time.Time, 0, len(txs))
-
 		batchCreatedAt := time.Now()
 
-		for _, t := range txs {
-			if isSystemTransaction(t.PayerAddress, t.ProposerAddress) {
-				continue
-			}
+		err := forEachChunk(len(txs), r.copyChunkRows, func(start, end int) error {
+			ids := make([][]byte, 0, end-start)
+			heights := make([]int64, 0, end-start)
+			txIndexes := make([]int32, 0, end-start)
+			timestamps := make([]time.Time, 0, end-start)
 
-			ids = append(ids, hexToBytes(t.ID))
-			heights = append(heights, int64(t.BlockHeight))
-			txIndexes = append(txIndexes, int32(t.TransactionIndex))
+			for _, t := range txs[start:end] {
+				if isSystemTransaction(t.PayerAddress, t.ProposerAddress) {
+					continue
+				}
 
-			// Keep the same timestamp fallback as raw.transactions COPY.
-			ts := t.Timestamp
-			if ts.IsZero() {
-				if bts, ok := blockTimeByHeight[t.BlockHeight]; ok {
-					ts = bts
+				ids = append(ids, hexToBytes(t.ID))
+				heights = append(heights, int64(t.BlockHeight))
+				txIndexes = append(txIndexes, int32(t.TransactionIndex))
+
+				// Keep the same timestamp fallback as raw.transactions COPY.
+				ts := t.Timestamp
+				if ts.IsZero() {
+					if bts, ok := blockTimeByHeight[t.BlockHeight]; ok {
+						ts = bts
+					}
 				}
+				if ts.IsZero() {
+					ts = batchCreatedAt
+				}
+				timestamps = append(timestamps, ts)
 			}
-			if ts.IsZero() {
-				ts = batchCreatedAt
-			}
-			timestamps = append(timestamps, ts)
-		}
 
-		if len(ids) > 0 {
+			if len(ids) == 0 {
+				return nil
+			}
 			_, err := dbtx.Exec(ctx, `
 				INSERT INTO raw.tx_lookup (id, block_height, transaction_index, timestamp)
 				SELECT DISTINCT ON (u.id)
@@ -421,9 +409,10 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 					transaction_index = EXCLUDED.transaction_index,
 					timestamp = EXCLUDED.timestamp
 			`, ids, heights, txIndexes, timestamps)
-			if err != nil {
-				return fmt.Errorf("failed to upsert tx_lookup batch: %w", err)
-			}
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upsert tx_lookup batch: %w", err)
 		}
 	}
 
@@ -465,9 +454,10 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 					status, error_message, is_evm,
 					gas_limit, gas_used, event_count,
 					timestamp,
-					proposer_key_index, proposer_sequence_number
+					proposer_key_index, proposer_sequence_number,
+					signers
 				)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 				ON CONFLICT (block_height, id) DO UPDATE SET
 					transaction_index = EXCLUDED.transaction_index,
 					status = EXCLUDED.status,
@@ -477,7 +467,8 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 					is_evm = EXCLUDED.is_evm,
 					script_hash = COALESCE(EXCLUDED.script_hash, raw.transactions.script_hash),
 					proposer_key_index = EXCLUDED.proposer_key_index,
-					proposer_sequence_number = EXCLUDED.proposer_sequence_number`,
+					proposer_sequence_number = EXCLUDED.proposer_sequence_number,
+					signers = COALESCE(EXCLUDED.signers, raw.transactions.signers)`,
 				t.BlockHeight, hexToBytes(t.ID), t.TransactionIndex,
 				hexToBytes(t.ProposerAddress), hexToBytes(t.PayerAddress), sliceHexToBytes(t.Authorizers),
 				scriptHash, func() any {
@@ -495,6 +486,7 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 				t.GasLimit, t.GasUsed, eventCount,
 				txTimestamp,
 				int32(t.ProposerKeyIndex), int64(t.ProposerSequenceNumber),
+				sanitizeJSONB(t.Signers),
 			)
 			if err != nil {
 				// Rollback to savepoint, log the error, and continue with remaining txs.
@@ -532,45 +524,50 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 		if err == nil {
 			defer sub.Rollback(ctx)
 
-			_, errCopy := sub.CopyFrom(ctx,
-				pgx.Identifier{"raw", "events"},
-				[]string{
-					"block_height", "transaction_id", "event_index",
-					"transaction_index", "type", "payload",
-					"contract_address", "event_name",
-					"timestamp",
-				},
-				pgx.CopyFromSlice(len(events), func(i int) ([]any, error) {
-					e := events[i]
-
-					eventTimestamp := e.Timestamp
-					if eventTimestamp.IsZero() {
-						if ts, ok := blockTimeByHeight[e.BlockHeight]; ok {
-							eventTimestamp = ts
-						}
-					}
-					if eventTimestamp.IsZero() {
-						eventTimestamp = time.Now()
-					}
+			eventRow := func(i int) ([]any, error) {
+				e := events[i]
 
-					var payload any
-					if len(e.Payload) > 0 {
-						payload = sanitizeJSONB(e.Payload)
+				eventTimestamp := e.Timestamp
+				if eventTimestamp.IsZero() {
+					if ts, ok := blockTimeByHeight[e.BlockHeight]; ok {
+						eventTimestamp = ts
 					}
+				}
+				if eventTimestamp.IsZero() {
+					eventTimestamp = time.Now()
+				}
 
-					return []any{
-						e.BlockHeight,
-						hexToBytes(e.TransactionID),
-						e.EventIndex,
-						e.TransactionIndex,
-						e.Type,
-						payload,
-						hexToBytes(e.ContractAddress),
-						e.EventName,
-						eventTimestamp,
-					}, nil
-				}),
-			)
+				payload, compressed, err := r.eventPayloadColumns(e.Payload)
+				if err != nil {
+					return nil, err
+				}
+
+				return []any{
+					e.BlockHeight,
+					hexToBytes(e.TransactionID),
+					e.EventIndex,
+					e.TransactionIndex,
+					e.Type,
+					payload,
+					compressed,
+					hexToBytes(e.ContractAddress),
+					e.EventName,
+					eventTimestamp,
+				}, nil
+			}
+			errCopy := forEachChunk(len(events), r.copyChunkRows, func(start, end int) error {
+				_, err := sub.CopyFrom(ctx,
+					pgx.Identifier{"raw", "events"},
+					[]string{
+						"block_height", "transaction_id", "event_index",
+						"transaction_index", "type", "payload", "payload_compressed",
+						"contract_address", "event_name",
+						"timestamp",
+					},
+					pgx.CopyFromSlice(end-start, func(j int) ([]any, error) { return eventRow(start + j) }),
+				)
+				return err
+			})
 			if errCopy == nil {
 				if err := sub.Commit(ctx); err == nil {
 					usedCopyForEvents = true
@@ -594,17 +591,21 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 			if eventTimestamp.IsZero() {
 				eventTimestamp = time.Now()
 			}
-			_, err := dbtx.Exec(ctx, `
+			payload, compressed, err := r.eventPayloadColumns(e.Payload)
+			if err != nil {
+				return fmt.Errorf("failed to encode event %s idx=%d at height %d: %w", e.TransactionID, e.EventIndex, e.BlockHeight, err)
+			}
+			_, err = dbtx.Exec(ctx, `
 				INSERT INTO raw.events (
 					block_height, transaction_id, event_index,
-					transaction_index, type, payload,
+					transaction_index, type, payload, payload_compressed,
 					contract_address, event_name,
 					timestamp
 				)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 				ON CONFLICT (block_height, transaction_id, event_index) DO NOTHING`,
 				e.BlockHeight, hexToBytes(e.TransactionID), e.EventIndex,
-				e.TransactionIndex, e.Type, sanitizeJSONB(e.Payload),
+				e.TransactionIndex, e.Type, payload, compressed,
 				hexToBytes(e.ContractAddress), e.EventName,
 				eventTimestamp,
 			)
@@ -614,6 +615,10 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 		}
 	}
 
+	if err := insertCollections(ctx, dbtx, blocks); err != nil {
+		return err
+	}
+
 	// 4. Update Checkpoint (app schema)
 	_, err = dbtx.Exec(ctx, `
 		INSERT INTO app.indexing_checkpoints (service_name, last_height, updated_at)
@@ -628,6 +633,51 @@ func (r *Repository) SaveBatch(ctx context.Context, blocks []*models.Block, txs
 	return dbtx.Commit(ctx)
 }
 
+// insertCollections upserts the collections fetched with STORE_COLLECTIONS in
+// one UNNEST statement. Postgres arrays cannot nest, so each collection's
+// transaction ids travel as one comma-separated hex string.
+func insertCollections(ctx context.Context, dbtx pgx.Tx, blocks []*models.Block) error {
+	var ids [][]byte
+	var heights []int64
+	var txIDs []string
+	for _, b := range blocks {
+		for _, c := range b.Collections {
+			hexIDs := make([]string, 0, len(c.TransactionIDs))
+			for _, id := range c.TransactionIDs {
+				if raw := hexToBytes(id); raw != nil {
+					hexIDs = append(hexIDs, hex.EncodeToString(raw))
+				}
+			}
+			ids = append(ids, hexToBytes(c.ID))
+			heights = append(heights, int64(c.BlockHeight))
+			txIDs = append(txIDs, strings.Join(hexIDs, ","))
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := dbtx.Exec(ctx, `
+		INSERT INTO raw.collections (id, block_height, transaction_ids)
+		SELECT DISTINCT ON (u.id)
+			u.id, u.block_height,
+			ARRAY(
+				SELECT decode(t.id, 'hex')
+				FROM unnest(string_to_array(u.tx_ids, ',')) WITH ORDINALITY AS t(id, n)
+				ORDER BY t.n
+			)
+		FROM UNNEST($1::bytea[], $2::bigint[], $3::text[]) AS u(id, block_height, tx_ids)
+		ORDER BY u.id, u.block_height DESC
+		ON CONFLICT (id) DO UPDATE SET
+			block_height = EXCLUDED.block_height,
+			transaction_ids = EXCLUDED.transaction_ids`,
+		ids, heights, txIDs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert collections batch: %w", err)
+	}
+	return nil
+}
+
 // SaveBlockOnly inserts a block without affecting the checkpoint or other tables.
 // Used for pre-insertion in batches to satisfy FK constraints.
 func (r *Repository) SaveBlockOnly(ctx context.Context, block models.Block) error {
@@ -3,7 +3,10 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"flowscan-clone/internal/models"
 
@@ -114,6 +117,42 @@ type DeadLeaseInfo struct {
 	Attempt    int
 }
 
+// LeaseAge describes the oldest outstanding (ACTIVE or FAILED) lease of a
+// worker type. Age runs from the lease's first claim, so a range that keeps
+// failing and being reclaimed keeps getting older.
+type LeaseAge struct {
+	WorkerType string
+	FromHeight uint64
+	ToHeight   uint64
+	Status     string
+	Attempt    int
+	Age        time.Duration
+}
+
+// GetOldestLeaseAge returns the oldest outstanding lease of workerType, or nil
+// when every lease is COMPLETED.
+func (r *Repository) GetOldestLeaseAge(ctx context.Context, workerType string) (*LeaseAge, error) {
+	l := LeaseAge{WorkerType: workerType}
+	var ageSec float64
+	err := r.queryRow(ctx, "oldest_lease_age", `
+		SELECT from_height, to_height, status, attempt,
+		       EXTRACT(EPOCH FROM NOW() - created_at)::float8
+		FROM app.worker_leases
+		WHERE worker_type = $1 AND status IN ('ACTIVE', 'FAILED')
+		ORDER BY created_at ASC, from_height ASC
+		LIMIT 1`, workerType).Scan(&l.FromHeight, &l.ToHeight, &l.Status, &l.Attempt, &ageSec)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ageSec > 0 {
+		l.Age = time.Duration(ageSec * float64(time.Second))
+	}
+	return &l, nil
+}
+
 // CompleteLease marks a lease as COMPLETED
 func (r *Repository) CompleteLease(ctx context.Context, leaseID int64) error {
 	_, err := r.db.Exec(ctx, `
@@ -200,6 +239,54 @@ func (r *Repository) SetCheckpoint(ctx context.Context, serviceName string, heig
 	return err
 }
 
+// SetCheckpointGroup force-sets every checkpoint named "<group>:<suffix>"
+// (e.g. the per-processor history deriver cursors) to height.
+func (r *Repository) SetCheckpointGroup(ctx context.Context, group string, height uint64) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE app.indexing_checkpoints
+		SET last_height = $2, updated_at = NOW()
+		WHERE starts_with(service_name, $1 || ':')`,
+		group, height,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetProcessorSchemaVersions returns the last recorded schema version per processor.
+func (r *Repository) GetProcessorSchemaVersions(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT processor_name, version FROM app.processor_schema_versions`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var version int
+		if err := rows.Scan(&name, &version); err != nil {
+			return nil, err
+		}
+		out[name] = version
+	}
+	return out, rows.Err()
+}
+
+// SetProcessorSchemaVersion records the schema version a processor's output was derived with.
+func (r *Repository) SetProcessorSchemaVersion(ctx context.Context, processorName string, version int) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO app.processor_schema_versions (processor_name, version, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (processor_name) DO UPDATE SET
+			version = EXCLUDED.version,
+			updated_at = NOW()`,
+		processorName, version,
+	)
+	return err
+}
+
 // SetCheckpointWithConfig sets a checkpoint and stores job config in the subcursor JSONB field.
 func (r *Repository) SetCheckpointWithConfig(ctx context.Context, serviceName string, height uint64, config map[string]interface{}) error {
 	configJSON, err := json.Marshal(config)
@@ -379,6 +466,77 @@ func (r *Repository) ResetWorkerToHeight(ctx context.Context, workerType string,
 	return tag.RowsAffected(), nil
 }
 
+// ResetProcessorForRederive rewinds a processor so all of its output is
+// derived again, in one transaction:
+//   - like ResetWorkerToHeight(processor, 0), its async worker leases are
+//     deleted and its checkpoint set to 0, so completed ranges are leased again;
+//   - for each history deriver prefix, its UP lane "<prefix>:<processor>" is
+//     set to 0 and the shared "<prefix>" UP checkpoint lowered to 0, so the
+//     scan restarts from the lowest raw block. The lanes of siblings are first
+//     raised to the old shared height so they do not rescan with it.
+//
+// Returns the number of leases deleted.
+func (r *Repository) ResetProcessorForRederive(ctx context.Context, processor string, historyPrefixes, siblings []string) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	setCheckpoint := func(name string, height uint64) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO app.indexing_checkpoints (service_name, last_height, updated_at)
+			VALUES ($1, $2, NOW())
+			ON CONFLICT (service_name) DO UPDATE SET last_height = EXCLUDED.last_height, updated_at = NOW()`,
+			name, height)
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, "DELETE FROM app.worker_leases WHERE worker_type = $1", processor)
+	if err != nil {
+		return 0, fmt.Errorf("delete leases: %w", err)
+	}
+	if err := setCheckpoint(processor, 0); err != nil {
+		return 0, fmt.Errorf("reset checkpoint: %w", err)
+	}
+
+	others := make([]string, 0, len(siblings))
+	for _, s := range siblings {
+		if s != processor {
+			others = append(others, s)
+		}
+	}
+	for _, prefix := range historyPrefixes {
+		var up uint64
+		err := tx.QueryRow(ctx, "SELECT last_height FROM app.indexing_checkpoints WHERE service_name = $1", prefix).Scan(&up)
+		if err != nil && err != pgx.ErrNoRows {
+			return 0, fmt.Errorf("load %s checkpoint: %w", prefix, err)
+		}
+		if up > 0 {
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO app.indexing_checkpoints (service_name, last_height, updated_at)
+				SELECT $1 || ':' || s, $2, NOW() FROM unnest($3::text[]) AS s
+				ON CONFLICT (service_name) DO UPDATE SET
+					last_height = GREATEST(app.indexing_checkpoints.last_height, EXCLUDED.last_height),
+					updated_at = NOW()`,
+				prefix, up, others); err != nil {
+				return 0, fmt.Errorf("pin %s lanes: %w", prefix, err)
+			}
+			if err := setCheckpoint(prefix, 0); err != nil {
+				return 0, fmt.Errorf("reset %s checkpoint: %w", prefix, err)
+			}
+		}
+		if err := setCheckpoint(prefix+":"+processor, 0); err != nil {
+			return 0, fmt.Errorf("reset %s lane: %w", prefix, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
 // LeaseGap represents a missing range between completed leases.
 type LeaseGap struct {
 	From uint64
@@ -446,7 +604,8 @@ func (r *Repository) GetRawEventsInRange(ctx context.Context, fromHeight, toHeig
 			payload,
 			COALESCE(encode(contract_address, 'hex'), '') AS contract_address,
 			event_name,
-			timestamp
+			timestamp,
+			payload_compressed
 		FROM raw.events
 		WHERE block_height >= $1 AND block_height < $2
 		ORDER BY block_height ASC, transaction_index ASC, event_index ASC`,
@@ -460,7 +619,11 @@ func (r *Repository) GetRawEventsInRange(ctx context.Context, fromHeight, toHeig
 	var events []models.Event
 	for rows.Next() {
 		var e models.Event
-		if err := rows.Scan(&e.BlockHeight, &e.TransactionID, &e.EventIndex, &e.TransactionIndex, &e.Type, &e.Payload, &e.ContractAddress, &e.EventName, &e.Timestamp); err != nil {
+		var compressed []byte
+		if err := rows.Scan(&e.BlockHeight, &e.TransactionID, &e.EventIndex, &e.TransactionIndex, &e.Type, &e.Payload, &e.ContractAddress, &e.EventName, &e.Timestamp, &compressed); err != nil {
+			return nil, err
+		}
+		if err := restoreEventPayload(&e, compressed); err != nil {
 			return nil, err
 		}
 		events = append(events, e)
@@ -512,6 +675,14 @@ func (r *Repository) UpsertNFTTransfers(ctx context.Context, transfers []models.
 		return nil
 	}
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin nft transfers: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Insert new rows first so mint/burn counters only see first-time inserts,
+	// then apply the update half of the upsert to rows that already existed.
 	batch := &pgx.Batch{}
 	for _, t := range transfers {
 		batch.Queue(`
@@ -521,27 +692,59 @@ func (r *Repository) UpsertNFTTransfers(ctx context.Context, transfers []models.
 				token_id, timestamp
 			)
 			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-			ON CONFLICT (block_height, transaction_id, event_index) DO UPDATE SET
-				token_contract_address = EXCLUDED.token_contract_address,
-				contract_name = COALESCE(NULLIF(EXCLUDED.contract_name, ''), app.nft_transfers.contract_name),
-				from_address = EXCLUDED.from_address,
-				to_address = EXCLUDED.to_address,
-				token_id = EXCLUDED.token_id`,
+			ON CONFLICT (block_height, transaction_id, event_index) DO NOTHING
+			RETURNING 1`,
 			t.BlockHeight, hexToBytes(t.TransactionID), t.EventIndex,
 			hexToBytes(t.TokenContractAddress), t.ContractName, hexToBytes(t.FromAddress), hexToBytes(t.ToAddress),
 			t.TokenID, t.Timestamp,
 		)
 	}
 
-	br := r.db.SendBatch(ctx, batch)
-	defer br.Close()
-
-	for i := 0; i < len(transfers); i++ {
-		if _, err := br.Exec(); err != nil {
+	br := tx.SendBatch(ctx, batch)
+	inserted := make([]models.TokenTransfer, 0, len(transfers))
+	var existing []models.TokenTransfer
+	for _, t := range transfers {
+		var one int
+		err := br.QueryRow().Scan(&one)
+		if err == pgx.ErrNoRows {
+			existing = append(existing, t)
+			continue
+		}
+		if err != nil {
+			br.Close()
 			return fmt.Errorf("failed to insert nft transfer batch: %w", err)
 		}
+		inserted = append(inserted, t)
 	}
-	return nil
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("failed to insert nft transfer batch: %w", err)
+	}
+
+	if len(existing) > 0 {
+		batch = &pgx.Batch{}
+		for _, t := range existing {
+			batch.Queue(`
+				UPDATE app.nft_transfers SET
+					token_contract_address = $4,
+					contract_name = COALESCE(NULLIF($5, ''), contract_name),
+					from_address = $6,
+					to_address = $7,
+					token_id = $8
+				WHERE block_height = $1 AND transaction_id = $2 AND event_index = $3`,
+				t.BlockHeight, hexToBytes(t.TransactionID), t.EventIndex,
+				hexToBytes(t.TokenContractAddress), t.ContractName, hexToBytes(t.FromAddress), hexToBytes(t.ToAddress),
+				t.TokenID,
+			)
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return fmt.Errorf("failed to update nft transfer batch: %w", err)
+		}
+	}
+
+	if err := addNFTMintBurnCounts(ctx, tx, tallyNFTMintBurn(inserted)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 // UpsertTokenTransfers keeps legacy callers working by routing to FT/NFT tables.
@@ -584,28 +787,63 @@ type IndexingError struct {
 	CreatedAt    string  `json:"created_at"`
 }
 
-// ListIndexingErrors returns unresolved errors, optionally filtered by worker.
-func (r *Repository) ListIndexingErrors(ctx context.Context, workerName string, limit int) ([]IndexingError, error) {
+// IndexingErrorFilter narrows raw.indexing_errors queries. Zero values are
+// unbounded; Resolved == nil matches both resolved and unresolved rows.
+type IndexingErrorFilter struct {
+	Worker     string
+	ErrorHash  string
+	Resolved   *bool
+	HeightFrom uint64 // inclusive
+	HeightTo   uint64 // inclusive
+	Cursor     int64  // return rows with id > Cursor (ListIndexingErrors only)
+}
+
+// indexingErrorsWhere builds the WHERE clause shared by ListIndexingErrors and
+// GetErrorMessageCounts. The cursor is only applied when withCursor is set.
+func indexingErrorsWhere(f IndexingErrorFilter, withCursor bool) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	add := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if f.Worker != "" {
+		add("worker_name = $%d", f.Worker)
+	}
+	if f.ErrorHash != "" {
+		add("error_hash = $%d", f.ErrorHash)
+	}
+	if f.Resolved != nil {
+		add("resolved = $%d", *f.Resolved)
+	}
+	if f.HeightFrom > 0 {
+		add("block_height >= $%d", f.HeightFrom)
+	}
+	if f.HeightTo > 0 {
+		add("block_height <= $%d", f.HeightTo)
+	}
+	if withCursor && f.Cursor > 0 {
+		add("id > $%d", f.Cursor)
+	}
+	if len(clauses) == 0 {
+		return "TRUE", args
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// ListIndexingErrors returns errors matching the filter ordered by id, so the
+// last id of a page can be passed back as Cursor for stable deep pagination.
+func (r *Repository) ListIndexingErrors(ctx context.Context, f IndexingErrorFilter, limit int) ([]IndexingError, error) {
 	if limit <= 0 || limit > 500 {
 		limit = 100
 	}
-	var query string
-	var args []interface{}
-	if workerName != "" {
-		query = `SELECT id, worker_name, block_height, COALESCE(transaction_id,''), COALESCE(error_hash,''), error_message, severity, resolved, created_at
-			FROM raw.indexing_errors
-			WHERE resolved = FALSE AND worker_name = $1
-			ORDER BY block_height ASC
-			LIMIT $2`
-		args = []interface{}{workerName, limit}
-	} else {
-		query = `SELECT id, worker_name, block_height, COALESCE(transaction_id,''), COALESCE(error_hash,''), error_message, severity, resolved, created_at
-			FROM raw.indexing_errors
-			WHERE resolved = FALSE
-			ORDER BY worker_name, block_height ASC
-			LIMIT $1`
-		args = []interface{}{limit}
-	}
+	where, args := indexingErrorsWhere(f, true)
+	args = append(args, limit)
+	query := fmt.Sprintf(`SELECT id, worker_name, block_height, COALESCE(transaction_id,''), COALESCE(error_hash,''), error_message, severity, resolved, created_at
+		FROM raw.indexing_errors
+		WHERE %s
+		ORDER BY id ASC
+		LIMIT $%d`, where, len(args))
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -628,30 +866,20 @@ func (r *Repository) ListIndexingErrors(ctx context.Context, workerName string,
 	return out, rows.Err()
 }
 
-// GetErrorMessageCounts returns the top N most common error messages for a worker.
-func (r *Repository) GetErrorMessageCounts(ctx context.Context, workerName string, limit int) ([]ErrorMessageCount, error) {
+// GetErrorMessageCounts returns the top N error_hash groups matching the filter
+// (the cursor is ignored), with a sample message and affected height range.
+func (r *Repository) GetErrorMessageCounts(ctx context.Context, f IndexingErrorFilter, limit int) ([]ErrorMessageCount, error) {
 	if limit <= 0 || limit > 100 {
 		limit = 20
 	}
-	var query string
-	var args []interface{}
-	if workerName != "" {
-		query = `SELECT error_message, COUNT(*) as cnt, MIN(block_height) as min_h, MAX(block_height) as max_h
-			FROM raw.indexing_errors
-			WHERE resolved = FALSE AND worker_name = $1
-			GROUP BY error_message
-			ORDER BY cnt DESC
-			LIMIT $2`
-		args = []interface{}{workerName, limit}
-	} else {
-		query = `SELECT error_message, COUNT(*) as cnt, MIN(block_height) as min_h, MAX(block_height) as max_h
-			FROM raw.indexing_errors
-			WHERE resolved = FALSE
-			GROUP BY error_message
-			ORDER BY cnt DESC
-			LIMIT $1`
-		args = []interface{}{limit}
-	}
+	where, args := indexingErrorsWhere(f, false)
+	args = append(args, limit)
+	query := fmt.Sprintf(`SELECT COALESCE(error_hash,''), MIN(error_message), COUNT(*) as cnt, MIN(block_height) as min_h, MAX(block_height) as max_h
+		FROM raw.indexing_errors
+		WHERE %s
+		GROUP BY COALESCE(error_hash,'')
+		ORDER BY cnt DESC
+		LIMIT $%d`, where, len(args))
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -662,7 +890,7 @@ func (r *Repository) GetErrorMessageCounts(ctx context.Context, workerName strin
 	var out []ErrorMessageCount
 	for rows.Next() {
 		var e ErrorMessageCount
-		if err := rows.Scan(&e.Message, &e.Count, &e.MinHeight, &e.MaxHeight); err != nil {
+		if err := rows.Scan(&e.ErrorHash, &e.Message, &e.Count, &e.MinHeight, &e.MaxHeight); err != nil {
 			return nil, err
 		}
 		out = append(out, e)
@@ -670,8 +898,9 @@ func (r *Repository) GetErrorMessageCounts(ctx context.Context, workerName strin
 	return out, rows.Err()
 }
 
-// ErrorMessageCount groups errors by message with count and affected height range.
+// ErrorMessageCount groups errors by error_hash with count and affected height range.
 type ErrorMessageCount struct {
+	ErrorHash string `json:"error_hash"`
 	Message   string `json:"message"`
 	Count     int64  `json:"count"`
 	MinHeight *int64 `json:"min_height"`
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"flowscan-clone/internal/models"
+)
+
+func TestGroupKeyAddresses(t *testing.T) {
+	t.Parallel()
+
+	// One key shared by two accounts; it was revoked on the second.
+	keys := []models.AccountKey{
+		{Address: "0000000000000002", KeyIndex: 0, Revoked: true, LastUpdatedHeight: 300},
+		{Address: "0000000000000001", KeyIndex: 1, LastUpdatedHeight: 100},
+		{Address: "0000000000000001", KeyIndex: 0, LastUpdatedHeight: 120},
+	}
+
+	active := groupKeyAddresses(keys, false)
+	wantActive := []PublicKeyAddress{
+		{Address: "0000000000000001", KeyIndexes: []int{0, 1}, Active: true, LastUpdatedHeight: 120},
+	}
+	if !reflect.DeepEqual(active, wantActive) {
+		t.Fatalf("active = %+v, want %+v", active, wantActive)
+	}
+
+	all := groupKeyAddresses(keys, true)
+	wantAll := []PublicKeyAddress{
+		{Address: "0000000000000001", KeyIndexes: []int{0, 1}, Active: true, LastUpdatedHeight: 120},
+		{Address: "0000000000000002", KeyIndexes: []int{0}, Active: false, LastUpdatedHeight: 300},
+	}
+	if !reflect.DeepEqual(all, wantAll) {
+		t.Fatalf("all = %+v, want %+v", all, wantAll)
+	}
+}
+
+func TestControlledAccounts(t *testing.T) {
+	t.Parallel()
+
+	keys := []models.AccountKey{
+		// Account 1: the key is registered at two indexes, 500 + 500 = full control.
+		{Address: "0000000000000001", KeyIndex: 3, Weight: 500},
+		{Address: "0000000000000001", KeyIndex: 1, Weight: 500},
+		// Account 2: a third index was revoked, leaving only 600.
+		{Address: "0000000000000002", KeyIndex: 0, Weight: 600},
+		{Address: "0000000000000002", KeyIndex: 1, Weight: 400, Revoked: true},
+		// Account 3: single full-weight key.
+		{Address: "0000000000000003", KeyIndex: 0, Weight: 1000},
+	}
+
+	got := controlledAccounts(keys, FullKeyWeight)
+	want := []ControlledAccount{
+		{Address: "0000000000000001", KeyIndexes: []int{1, 3}, Weight: 1000},
+		{Address: "0000000000000003", KeyIndexes: []int{0}, Weight: 1000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("min 1000 = %+v, want %+v", got, want)
+	}
+
+	if got := controlledAccounts(keys, 500); len(got) != 3 || got[2].Address != "0000000000000002" || got[2].Weight != 600 {
+		t.Fatalf("min 500 = %+v", got)
+	}
+}
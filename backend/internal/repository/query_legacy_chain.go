@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"flowscan-clone/internal/models"
+
+	"github.com/jackc/pgx/v5"
 )
 
 const systemFlowAddressHex = "0000000000000000"
@@ -37,7 +39,7 @@ func recentTxWindowFromEnv() int64 {
 }
 
 func (r *Repository) ListBlocks(ctx context.Context, limit, offset int) ([]models.Block, error) {
-	rows, err := r.db.Query(ctx, `
+	rows, err := r.readQuery(ctx, "list_blocks", `
 		SELECT height,
 		       encode(id, 'hex') AS id,
 		       encode(parent_id, 'hex') AS parent_id,
@@ -69,6 +71,47 @@ func (r *Repository) ListBlocks(ctx context.Context, limit, offset int) ([]model
 	return blocks, nil
 }
 
+// eventCountsForHeightsQuery counts events per block for a set of heights.
+// The BETWEEN bounds let Postgres prune raw.events partitions outside the set.
+const eventCountsForHeightsQuery = `
+	SELECT block_height, COUNT(*)
+	FROM raw.events
+	WHERE block_height BETWEEN $1 AND $2
+	  AND block_height = ANY($3)
+	GROUP BY block_height`
+
+// GetEventCountsForHeights returns the live raw.events count for each height
+// in a single grouped query. Every requested height is present in the result,
+// with 0 for blocks that have no events.
+func (r *Repository) GetEventCountsForHeights(ctx context.Context, heights []uint64) (map[uint64]int, error) {
+	counts := make(map[uint64]int, len(heights))
+	if len(heights) == 0 {
+		return counts, nil
+	}
+	lo, hi := heights[0], heights[0]
+	params := make([]int64, 0, len(heights))
+	for _, h := range heights {
+		counts[h] = 0
+		lo, hi = min(lo, h), max(hi, h)
+		params = append(params, int64(h))
+	}
+
+	rows, err := r.db.Query(ctx, eventCountsForHeightsQuery, int64(lo), int64(hi), params)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var height int64
+		var n int
+		if err := rows.Scan(&height, &n); err != nil {
+			return nil, err
+		}
+		counts[uint64(height)] = n
+	}
+	return counts, rows.Err()
+}
+
 func (r *Repository) GetRecentBlocks(ctx context.Context, limit, offset int) ([]models.Block, error) {
 	query := `
 		SELECT b.height,
@@ -79,7 +122,7 @@ func (r *Repository) GetRecentBlocks(ctx context.Context, limit, offset int) ([]
 		ORDER BY b.height DESC 
 		LIMIT $1 OFFSET $2`
 
-	rows, err := r.db.Query(ctx, query, limit, offset)
+	rows, err := r.readQuery(ctx, "recent_blocks", query, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +150,7 @@ func (r *Repository) GetBlocksByCursor(ctx context.Context, limit int, cursorHei
 		ORDER BY b.height DESC
 		LIMIT $2`
 
-	rows, err := r.db.Query(ctx, query, cursorHeight, limit)
+	rows, err := r.readQuery(ctx, "blocks_by_cursor", query, cursorHeight, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -124,18 +167,45 @@ func (r *Repository) GetBlocksByCursor(ctx context.Context, limit int, cursorHei
 	return blocks, nil
 }
 
+// GetBlocksFromHeight returns up to limit blocks at or above fromHeight in
+// ascending height order. Used to replay missed blocks to WS clients.
+func (r *Repository) GetBlocksFromHeight(ctx context.Context, fromHeight uint64, limit int) ([]models.Block, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT b.height,
+		       encode(b.id, 'hex') AS id,
+		       b.timestamp, COALESCE(b.tx_count, 0), COALESCE(b.event_count, 0)
+		FROM raw.blocks b
+		WHERE b.height >= $1
+		ORDER BY b.height ASC
+		LIMIT $2`, int64(fromHeight), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []models.Block
+	for rows.Next() {
+		var b models.Block
+		if err := rows.Scan(&b.Height, &b.ID, &b.Timestamp, &b.TxCount, &b.EventCount); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, rows.Err()
+}
+
 func (r *Repository) GetBlockByID(ctx context.Context, id string) (*models.Block, error) {
 	var height uint64
 	err := r.db.QueryRow(ctx, "SELECT height FROM raw.block_lookup WHERE id = $1", hexToBytes(id)).Scan(&height)
 	if err != nil {
-		return nil, err
+		return nil, wrapDBErr(err, "block "+id)
 	}
 	return r.GetBlockByHeight(ctx, height)
 }
 
 func (r *Repository) GetBlockByHeight(ctx context.Context, height uint64) (*models.Block, error) {
 	var b models.Block
-	err := r.db.QueryRow(ctx, `
+	err := r.queryRow(ctx, "block_by_height", `
 		SELECT
 			height,
 			encode(id, 'hex') AS id,
@@ -149,11 +219,11 @@ func (r *Repository) GetBlockByHeight(ctx context.Context, height uint64) (*mode
 	`, height).
 		Scan(&b.Height, &b.ID, &b.ParentID, &b.Timestamp, &b.CollectionCount, &b.TotalGasUsed, &b.IsSealed)
 	if err != nil {
-		return nil, err
+		return nil, wrapDBErr(err, fmt.Sprintf("block %d", height))
 	}
 
 	// Get transactions for this block
-	txRows, err := r.db.Query(ctx, `
+	txRows, err := r.query(ctx, "block_transactions", `
 		SELECT
 			encode(id, 'hex') AS id,
 			block_height,
@@ -205,9 +275,9 @@ func (r *Repository) GetTransactionByID(ctx context.Context, id string) (*models
 
 	// 1. Try resolving ID via raw.tx_lookup
 	var blockHeight uint64
-	err := r.db.QueryRow(ctx, "SELECT block_height FROM raw.tx_lookup WHERE id = $1", hexToBytes(id)).Scan(&blockHeight)
+	err := r.queryRow(ctx, "tx_lookup", "SELECT block_height FROM raw.tx_lookup WHERE id = $1", hexToBytes(id)).Scan(&blockHeight)
 	if err != nil && has0x {
-		err = r.db.QueryRow(ctx, "SELECT block_height FROM raw.tx_lookup WHERE id = $1", hexToBytes(normalizedID)).Scan(&blockHeight)
+		err = r.queryRow(ctx, "tx_lookup", "SELECT block_height FROM raw.tx_lookup WHERE id = $1", hexToBytes(normalizedID)).Scan(&blockHeight)
 	}
 
 	query := ""
@@ -240,7 +310,8 @@ func (r *Repository) GetTransactionByID(ctx context.Context, id string) (*models
 				COALESCE(encode(et.from_address, 'hex'), '') AS from_address,
 				COALESCE(encode(et.to_address, 'hex'), '') AS to_address,
 				'' AS evm_value,
-				COALESCE(t.script_hash, '') AS script_hash
+				COALESCE(t.script_hash, '') AS script_hash,
+			t.signers
 			FROM raw.transactions t
 			LEFT JOIN raw.scripts s ON t.script_hash = s.script_hash
 			LEFT JOIN app.evm_transactions et ON t.id = et.transaction_id AND t.block_height = et.block_height
@@ -277,7 +348,8 @@ func (r *Repository) GetTransactionByID(ctx context.Context, id string) (*models
 					COALESCE(encode(et.from_address, 'hex'), '') AS from_address,
 					COALESCE(encode(et.to_address, 'hex'), '') AS to_address,
 					'' AS evm_value,
-					COALESCE(t.script_hash, '') AS script_hash
+					COALESCE(t.script_hash, '') AS script_hash,
+				t.signers
 				FROM raw.transactions t
 				LEFT JOIN raw.scripts s ON t.script_hash = s.script_hash
 				LEFT JOIN app.evm_transactions et ON t.id = et.transaction_id AND t.block_height = et.block_height
@@ -314,7 +386,8 @@ func (r *Repository) GetTransactionByID(ctx context.Context, id string) (*models
 						COALESCE(encode(et.from_address, 'hex'), '') AS from_address,
 						COALESCE(encode(et.to_address, 'hex'), '') AS to_address,
 						'' AS evm_value,
-						COALESCE(t.script_hash, '') AS script_hash
+						COALESCE(t.script_hash, '') AS script_hash,
+					t.signers
 					FROM raw.transactions t
 					LEFT JOIN raw.scripts s ON t.script_hash = s.script_hash
 					LEFT JOIN app.evm_transactions et ON t.id = et.transaction_id AND t.block_height = et.block_height
@@ -322,29 +395,41 @@ func (r *Repository) GetTransactionByID(ctx context.Context, id string) (*models
 					WHERE t.id = $1 AND t.block_height = $2`
 				args = []interface{}{hexToBytes(txID), bh}
 			} else {
-				return nil, fmt.Errorf("transaction not found")
+				return nil, fmt.Errorf("transaction %s: %w", id, ErrNotFound)
 			}
 		}
 	}
 
-	err = r.db.QueryRow(ctx, query, args...).
+	err = r.queryRow(ctx, "transaction_by_id", query, args...).
 		Scan(&t.ID, &t.BlockHeight, &t.TransactionIndex, &t.ProposerAddress, &t.ProposerKeyIndex, &t.ProposerSequenceNumber,
 			&t.PayerAddress, &t.Authorizers, &t.Script, &t.Arguments, &t.Status, &t.ErrorMessage, &t.IsEVM, &t.GasLimit, &t.GasUsed, &t.EventCount, &t.Timestamp,
-			&t.EVMHash, &t.EVMFrom, &t.EVMTo, &t.EVMValue, &t.ScriptHash)
+			&t.EVMHash, &t.EVMFrom, &t.EVMTo, &t.EVMValue, &t.ScriptHash, &t.Signers)
 
 	if err != nil {
-		return nil, err
+		return nil, wrapDBErr(err, "transaction "+id)
 	}
 
-	// Fetch events for this transaction separately to ensure they are always present
-	events, err := r.GetEventsByTransactionID(ctx, t.ID)
-	if err == nil {
-		t.Events = events
+	// Fetch events for this transaction separately to ensure they are always
+	// present, capped so a transaction with thousands of events stays cheap;
+	// use GetEventsByTransactionIDPage for the rest.
+	if page, err := r.txEventsPage(ctx, t.ID, t.BlockHeight, DefaultTxEventsLimit, 0); err == nil {
+		t.Events = page.Events
 	}
 
 	return &t, nil
 }
 
+// DefaultTxEventsLimit is how many events GetTransactionByID loads and the
+// transaction detail returns unless asked for another page.
+const DefaultTxEventsLimit = 100
+
+// TxEventsPage is one page of a transaction's events in event_index order.
+type TxEventsPage struct {
+	Events  []models.Event
+	Total   int // events the whole transaction emitted
+	HasMore bool
+}
+
 func (r *Repository) GetEventsByTransactionID(ctx context.Context, txID string) ([]models.Event, error) {
 	var blockHeight uint64
 	err := r.db.QueryRow(ctx, "SELECT block_height FROM raw.tx_lookup WHERE id = $1", hexToBytes(txID)).Scan(&blockHeight)
@@ -353,39 +438,81 @@ func (r *Repository) GetEventsByTransactionID(ctx context.Context, txID string)
 	}
 
 	rows, err := r.db.Query(ctx, `
-		SELECT encode(transaction_id, 'hex') AS transaction_id, block_height, transaction_index, type, event_index, payload, timestamp
+		SELECT encode(transaction_id, 'hex') AS transaction_id, block_height, transaction_index, type, event_index, payload, timestamp, payload_compressed
 		FROM raw.events
 		WHERE transaction_id = $1 AND block_height = $2
 		ORDER BY event_index ASC`, hexToBytes(txID), blockHeight)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return scanTxEvents(rows)
+}
 
+// GetEventsByTransactionIDPage loads events [offset, offset+limit) of a
+// transaction along with its total event count. Returns ErrNotFound when the
+// transaction is not indexed.
+func (r *Repository) GetEventsByTransactionIDPage(ctx context.Context, txID string, limit, offset int) (*TxEventsPage, error) {
+	var blockHeight uint64
+	err := r.queryRow(ctx, "tx_lookup", "SELECT block_height FROM raw.tx_lookup WHERE id = $1", hexToBytes(txID)).Scan(&blockHeight)
+	if err != nil {
+		return nil, wrapDBErr(err, "transaction "+txID)
+	}
+	return r.txEventsPage(ctx, txID, blockHeight, limit, offset)
+}
+
+func (r *Repository) txEventsPage(ctx context.Context, txID string, blockHeight uint64, limit, offset int) (*TxEventsPage, error) {
+	page := &TxEventsPage{}
+	err := r.queryRow(ctx, "tx_event_count", `
+		SELECT COUNT(*) FROM raw.events WHERE transaction_id = $1 AND block_height = $2`,
+		hexToBytes(txID), blockHeight).Scan(&page.Total)
+	if err != nil {
+		return nil, fmt.Errorf("count events of %s: %w", txID, err)
+	}
+	if offset >= page.Total {
+		return page, nil
+	}
+
+	rows, err := r.query(ctx, "tx_events_page", `
+		SELECT encode(transaction_id, 'hex') AS transaction_id, block_height, transaction_index, type, event_index, payload, timestamp, payload_compressed
+		FROM raw.events
+		WHERE transaction_id = $1 AND block_height = $2
+		ORDER BY event_index ASC
+		LIMIT $3 OFFSET $4`, hexToBytes(txID), blockHeight, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if page.Events, err = scanTxEvents(rows); err != nil {
+		return nil, err
+	}
+	page.HasMore = offset+len(page.Events) < page.Total
+	return page, nil
+}
+
+func scanTxEvents(rows pgx.Rows) ([]models.Event, error) {
+	defer rows.Close()
 	var events []models.Event
 	for rows.Next() {
 		var e models.Event
-		if err := rows.Scan(&e.TransactionID, &e.BlockHeight, &e.TransactionIndex, &e.Type, &e.EventIndex, &e.Payload, &e.Timestamp); err != nil {
+		var compressed []byte
+		if err := rows.Scan(&e.TransactionID, &e.BlockHeight, &e.TransactionIndex, &e.Type, &e.EventIndex, &e.Payload, &e.Timestamp, &compressed); err != nil {
+			return nil, err
+		}
+		if err := restoreEventPayload(&e, compressed); err != nil {
 			return nil, err
 		}
 		events = append(events, e)
 	}
-	return events, nil
+	return events, rows.Err()
 }
 
-func (r *Repository) GetTransactionsByAddress(ctx context.Context, address string, limit, offset int) ([]models.Transaction, error) {
-	// address_transactions now contains all roles (PROPOSER, PAYER, AUTHORIZER,
-	// FT_SENDER, FT_RECEIVER, NFT_SENDER, NFT_RECEIVER) so we can query it
-	// directly instead of the old 5-way UNION with ft_transfers/nft_transfers.
-	// The composite index (address, block_height DESC, transaction_id DESC)
-	// makes this an efficient index-only scan even for high-volume accounts.
-	// Fetch limit+1 to determine hasMore without an extra COUNT(*).
-	fetchLimit := limit + 1
-	query := `
+// addressTransactionsSQL backs GetTransactionsByAddress; args are
+// (address, limit, offset, roles or nil).
+const addressTransactionsSQL = `
 		WITH addr_txs AS (
 			SELECT DISTINCT block_height, transaction_id
 			FROM app.address_transactions
 			WHERE address = $1
+			  AND ($4::text[] IS NULL OR role = ANY($4))
 			ORDER BY block_height DESC, transaction_id DESC
 			LIMIT $2 OFFSET $3
 		)
@@ -416,9 +543,26 @@ func (r *Repository) GetTransactionsByAddress(ctx context.Context, address strin
 			LIMIT 1
 		) et ON true
 		ORDER BY a.block_height DESC, a.transaction_id DESC
-	`
+`
+
+// GetTransactionsByAddress lists the address's transactions, newest first.
+// role restricts them to one participation type (see addressTxRoleFilters);
+// empty means any role.
+func (r *Repository) GetTransactionsByAddress(ctx context.Context, address, role string, limit, offset int) ([]models.Transaction, error) {
+	roles, err := addressTxRoles(role)
+	if err != nil {
+		return nil, err
+	}
+
+	// address_transactions now contains all roles (PROPOSER, PAYER, AUTHORIZER,
+	// FT_SENDER, FT_RECEIVER, NFT_SENDER, NFT_RECEIVER) so we can query it
+	// directly instead of the old 5-way UNION with ft_transfers/nft_transfers.
+	// The composite index (address, block_height DESC, transaction_id DESC)
+	// makes this an efficient index-only scan even for high-volume accounts.
+	// Fetch limit+1 to determine hasMore without an extra COUNT(*).
+	fetchLimit := limit + 1
 
-	rows, err := r.db.Query(ctx, query, hexToBytes(address), fetchLimit, offset)
+	rows, err := r.db.Query(ctx, addressTransactionsSQL, hexToBytes(address), fetchLimit, offset, roles)
 	if err != nil {
 		return nil, err
 	}
@@ -452,13 +596,15 @@ type AddressTxCursor struct {
 	TxID        string
 }
 
-func (r *Repository) GetTransactionsByAddressCursor(ctx context.Context, address string, limit int, cursor *AddressTxCursor) ([]models.Transaction, error) {
-	query := `
+// addressTransactionsCursorSQL backs GetTransactionsByAddressCursor; args are
+// (address, cursor height or nil, cursor tx id or nil, limit, roles or nil).
+const addressTransactionsCursorSQL = `
 		WITH addr_txs AS (
 			SELECT DISTINCT block_height, transaction_id
 			FROM app.address_transactions
 			WHERE address = $1
 			  AND ($2::bigint IS NULL OR (block_height, transaction_id) < ($2, $3))
+			  AND ($5::text[] IS NULL OR role = ANY($5))
 			ORDER BY block_height DESC, transaction_id DESC
 			LIMIT $4
 		)
@@ -478,8 +624,15 @@ func (r *Repository) GetTransactionsByAddressCursor(ctx context.Context, address
 		JOIN raw.transactions t ON t.id = a.transaction_id AND t.block_height = a.block_height
 		LEFT JOIN app.tx_metrics m ON m.transaction_id = t.id AND m.block_height = t.block_height
 		ORDER BY a.block_height DESC, a.transaction_id DESC
-	`
+`
 
+// GetTransactionsByAddressCursor is the keyset-paginated variant of
+// GetTransactionsByAddress and takes the same role filter.
+func (r *Repository) GetTransactionsByAddressCursor(ctx context.Context, address, role string, limit int, cursor *AddressTxCursor) ([]models.Transaction, error) {
+	roles, err := addressTxRoles(role)
+	if err != nil {
+		return nil, err
+	}
 	var (
 		bh interface{}
 		id interface{}
@@ -489,7 +642,7 @@ func (r *Repository) GetTransactionsByAddressCursor(ctx context.Context, address
 		id = hexToBytes(cursor.TxID)
 	}
 
-	rows, err := r.db.Query(ctx, query, hexToBytes(address), bh, id, limit)
+	rows, err := r.readQuery(ctx, "address_txs_cursor", addressTransactionsCursorSQL, hexToBytes(address), bh, id, limit, roles)
 	if err != nil {
 		return nil, err
 	}
@@ -506,8 +659,8 @@ func (r *Repository) GetTransactionsByAddressCursor(ctx context.Context, address
 	return txs, nil
 }
 
-func (r *Repository) GetRecentTransactions(ctx context.Context, limit, offset int) ([]models.Transaction, error) {
-	query := fmt.Sprintf(`
+// recentTransactionsSQL backs GetRecentTransactions; args are (limit, offset).
+var recentTransactionsSQL = fmt.Sprintf(`
 		SELECT
 			encode(t.id, 'hex') AS id,
 			t.block_height,
@@ -523,7 +676,8 @@ func (r *Repository) GetRecentTransactions(ctx context.Context, limit, offset in
 		ORDER BY t.block_height DESC, t.transaction_index DESC, t.id DESC
 		LIMIT $1 OFFSET $2`, nonSystemTxSQL("t"))
 
-	rows, err := r.db.Query(ctx, query, limit, offset)
+func (r *Repository) GetRecentTransactions(ctx context.Context, limit, offset int) ([]models.Transaction, error) {
+	rows, err := r.readQuery(ctx, "recent_transactions", recentTransactionsSQL, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -578,7 +732,7 @@ func (r *Repository) GetTransactionsByCursor(ctx context.Context, limit int, cur
 
 	if cursor == nil {
 		window := recentTxWindowFromEnv()
-		rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		rows, err := r.readQuery(ctx, "recent_tx_window", fmt.Sprintf(`
 			WITH latest AS (
 				SELECT COALESCE(MAX(height), 0) AS max_height
 				FROM raw.blocks
@@ -622,7 +776,7 @@ func (r *Repository) GetTransactionsByCursor(ctx context.Context, limit int, cur
 		id = hexToBytes(cursor.ID)
 	}
 
-	rows, err := r.db.Query(ctx, query, bh, ti, id, limit)
+	rows, err := r.readQuery(ctx, "txs_by_cursor", query, bh, ti, id, limit)
 	if err != nil {
 		return nil, err
 	}
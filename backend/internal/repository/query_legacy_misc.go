@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
@@ -220,13 +221,17 @@ func (r *Repository) GetNFTTransfersByAddressCursor(ctx context.Context, address
 func (r *Repository) GetAddressStats(ctx context.Context, address string) (*models.AddressStats, error) {
 	var s models.AddressStats
 	err := r.db.QueryRow(ctx, `
-		SELECT encode(address, 'hex') AS address, tx_count, total_gas_used, last_updated_block, created_at, updated_at
+		SELECT encode(address, 'hex') AS address, tx_count, total_gas_used, last_updated_block,
+		       payer_count, proposer_count, authorizer_count, transfer_sent_count, transfer_received_count,
+		       created_at, updated_at
 		FROM app.address_stats
 		WHERE address = $1`, hexToBytes(address)).Scan(
-		&s.Address, &s.TxCount, &s.TotalGasUsed, &s.LastUpdatedBlock, &s.CreatedAt, &s.UpdatedAt,
+		&s.Address, &s.TxCount, &s.TotalGasUsed, &s.LastUpdatedBlock,
+		&s.PayerCount, &s.ProposerCount, &s.AuthorizerCount, &s.TransferSentCount, &s.TransferReceivedCount,
+		&s.CreatedAt, &s.UpdatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, wrapDBErr(err, "address stats "+address)
 	}
 	return &s, nil
 }
@@ -242,93 +247,11 @@ func (r *Repository) GetContractByAddress(ctx context.Context, address string) (
 		&c.Address, &c.Name, &c.Version, &c.BlockHeight, &c.CreatedAt, &c.UpdatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, wrapDBErr(err, "contract "+address)
 	}
 	return &c, nil
 }
 
-// RefreshDailyStatsRange idempotently refreshes daily_stats for all dates
-// touched by transactions in the half-open block range [fromHeight, toHeight).
-//
-// Finds affected dates from the input range, then does a full-day recount
-// across ALL block heights for those dates. This makes retries/overlapping
-// ranges safe — running the same range twice produces the same result.
-// Height bounds on the full-day scan enable partition pruning.
-func (r *Repository) RefreshDailyStatsRange(ctx context.Context, fromHeight, toHeight uint64) error {
-	_, err := r.db.Exec(ctx, `
-		WITH affected AS (
-			SELECT DISTINCT DATE(t.timestamp) AS d
-			FROM raw.transactions t
-			WHERE t.block_height >= $1 AND t.block_height < $2
-			  AND t.timestamp IS NOT NULL
-		),
-		bounds AS (
-			SELECT MIN(b.height) AS lo, MAX(b.height) + 1 AS hi
-			FROM raw.blocks b
-			WHERE DATE(b.timestamp) IN (SELECT d FROM affected)
-		),
-		tx_agg AS (
-			SELECT
-				DATE(t.timestamp) AS date,
-				COUNT(*) AS tx_count,
-				COUNT(*) FILTER (WHERE t.is_evm = TRUE) AS evm_tx_count,
-				COALESCE(SUM(t.gas_used), 0) AS total_gas_used,
-				COUNT(DISTINCT t.proposer_address) AS active_accounts,
-				COUNT(*) FILTER (WHERE t.error_message IS NOT NULL AND t.error_message != '') AS failed_tx_count
-			FROM raw.transactions t
-			WHERE t.block_height >= (SELECT lo FROM bounds)
-			  AND t.block_height < (SELECT hi FROM bounds)
-			  AND DATE(t.timestamp) IN (SELECT d FROM affected)
-			  AND t.timestamp IS NOT NULL
-			GROUP BY DATE(t.timestamp)
-		),
-		ft_agg AS (
-			SELECT DATE(ft.timestamp) AS date, COUNT(*) AS cnt
-			FROM app.ft_transfers ft
-			WHERE ft.block_height >= (SELECT lo FROM bounds)
-			  AND ft.block_height < (SELECT hi FROM bounds)
-			  AND DATE(ft.timestamp) IN (SELECT d FROM affected)
-			GROUP BY 1
-		),
-		nft_agg AS (
-			SELECT DATE(nt.timestamp) AS date, COUNT(*) AS cnt
-			FROM app.nft_transfers nt
-			WHERE nt.block_height >= (SELECT lo FROM bounds)
-			  AND nt.block_height < (SELECT hi FROM bounds)
-			  AND DATE(nt.timestamp) IN (SELECT d FROM affected)
-			GROUP BY 1
-		)
-		INSERT INTO app.daily_stats (date, tx_count, evm_tx_count, total_gas_used, active_accounts, failed_tx_count, ft_transfer_count, nft_transfer_count, updated_at)
-		SELECT
-			a.date, a.tx_count, a.evm_tx_count, a.total_gas_used, a.active_accounts,
-			a.failed_tx_count,
-			COALESCE(f.cnt, 0),
-			COALESCE(n.cnt, 0),
-			NOW()
-		FROM tx_agg a
-		LEFT JOIN ft_agg f ON f.date = a.date
-		LEFT JOIN nft_agg n ON n.date = a.date
-		ON CONFLICT (date) DO UPDATE SET
-			tx_count = EXCLUDED.tx_count,
-			evm_tx_count = EXCLUDED.evm_tx_count,
-			total_gas_used = EXCLUDED.total_gas_used,
-			active_accounts = EXCLUDED.active_accounts,
-			failed_tx_count = EXCLUDED.failed_tx_count,
-			ft_transfer_count = EXCLUDED.ft_transfer_count,
-			nft_transfer_count = EXCLUDED.nft_transfer_count,
-			updated_at = NOW();
-	`, fromHeight, toHeight)
-	if err != nil {
-		return fmt.Errorf("refresh daily stats range [%d, %d): %w", fromHeight, toHeight, err)
-	}
-
-	// Best effort: contract stats should not block core daily tx stats.
-	if err := r.refreshDailyContractStatsRange(ctx, fromHeight, toHeight); err != nil {
-		log.Printf("[daily_stats] contract stats refresh skipped for range [%d,%d): %v", fromHeight, toHeight, err)
-	}
-	return nil
-}
-
 // RefreshAnalyticsDailyMetricsRange idempotently refreshes analytics.daily_metrics
 // for all dates touched by transactions in [fromHeight, toHeight).
 //
@@ -470,10 +393,12 @@ func (r *Repository) RefreshDailyStats(ctx context.Context, fullScan bool) error
 	whereClause := "AND t.timestamp >= NOW() - INTERVAL '30 days'"
 	ftWhereClause := "AND ft.timestamp >= NOW() - INTERVAL '30 days'"
 	nftWhereClause := "AND nt.timestamp >= NOW() - INTERVAL '30 days'"
+	acctWhereClause := "AND e.timestamp >= NOW() - INTERVAL '30 days'"
 	if fullScan {
 		whereClause = ""
 		ftWhereClause = ""
 		nftWhereClause = ""
+		acctWhereClause = ""
 	}
 	query := fmt.Sprintf(`
 		WITH tx_agg AS (
@@ -500,17 +425,25 @@ func (r *Repository) RefreshDailyStats(ctx context.Context, fullScan bool) error
 			FROM app.nft_transfers nt
 			WHERE nt.timestamp IS NOT NULL %s
 			GROUP BY 1
+		),
+		acct_agg AS (
+			SELECT DATE(e.timestamp) AS date, COUNT(*) AS cnt
+			FROM raw.events e
+			WHERE e.type = 'flow.AccountCreated' %s
+			GROUP BY 1
 		)
-		INSERT INTO app.daily_stats (date, tx_count, evm_tx_count, total_gas_used, active_accounts, failed_tx_count, ft_transfer_count, nft_transfer_count, updated_at)
+		INSERT INTO app.daily_stats (date, tx_count, evm_tx_count, total_gas_used, active_accounts, failed_tx_count, ft_transfer_count, nft_transfer_count, accounts_created, updated_at)
 		SELECT
 			a.date, a.tx_count, a.evm_tx_count, a.total_gas_used, a.active_accounts,
 			a.failed_tx_count,
 			COALESCE(f.cnt, 0),
 			COALESCE(n.cnt, 0),
+			COALESCE(ac.cnt, 0),
 			NOW()
 		FROM tx_agg a
 		LEFT JOIN ft_agg f ON f.date = a.date
 		LEFT JOIN nft_agg n ON n.date = a.date
+		LEFT JOIN acct_agg ac ON ac.date = a.date
 		ON CONFLICT (date) DO UPDATE SET
 			tx_count = EXCLUDED.tx_count,
 			evm_tx_count = EXCLUDED.evm_tx_count,
@@ -519,8 +452,9 @@ func (r *Repository) RefreshDailyStats(ctx context.Context, fullScan bool) error
 			failed_tx_count = EXCLUDED.failed_tx_count,
 			ft_transfer_count = EXCLUDED.ft_transfer_count,
 			nft_transfer_count = EXCLUDED.nft_transfer_count,
+			accounts_created = EXCLUDED.accounts_created,
 			updated_at = NOW();
-	`, whereClause, ftWhereClause, nftWhereClause)
+	`, whereClause, ftWhereClause, nftWhereClause, acctWhereClause)
 	_, err := r.db.Exec(ctx, query)
 	if err != nil {
 		return fmt.Errorf("failed to refresh daily stats: %w", err)
@@ -785,6 +719,35 @@ func (r *Repository) GetAllCheckpoints(ctx context.Context) (map[string]uint64,
 	return results, nil
 }
 
+// IndexingCheckpoint is a row of app.indexing_checkpoints.
+type IndexingCheckpoint struct {
+	ServiceName string
+	LastHeight  uint64
+	UpdatedAt   time.Time
+}
+
+// ListCheckpoints returns every service checkpoint with its last update time.
+func (r *Repository) ListCheckpoints(ctx context.Context) ([]IndexingCheckpoint, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT service_name, last_height, updated_at
+		FROM app.indexing_checkpoints
+		ORDER BY service_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []IndexingCheckpoint
+	for rows.Next() {
+		var c IndexingCheckpoint
+		if err := rows.Scan(&c.ServiceName, &c.LastHeight, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
 // GetAddressByPublicKey finds the address associated with a public key
 func (r *Repository) GetAddressByPublicKey(ctx context.Context, publicKey string) (string, error) {
 	publicKey = strings.TrimSpace(publicKey)
@@ -847,6 +810,121 @@ func (r *Repository) ListAccountsByPublicKey(ctx context.Context, publicKey stri
 	return results, hasMore, nil
 }
 
+// PublicKeyAddress is an account that holds a given public key, with the
+// indexes it is registered under.
+type PublicKeyAddress struct {
+	Address    string `json:"address"`
+	KeyIndexes []int  `json:"key_indexes"`
+	// Active is true while at least one of those keys is un-revoked.
+	Active            bool   `json:"active"`
+	LastUpdatedHeight uint64 `json:"last_updated_height"`
+}
+
+// maxPublicKeyAddressRows caps how many key rows a single public key lookup reads.
+const maxPublicKeyAddressRows = 1000
+
+// ListAddressesByPublicKey returns the accounts holding publicKey, one entry per
+// address. Only accounts where the key is still un-revoked are returned unless
+// includeRevoked is set.
+func (r *Repository) ListAddressesByPublicKey(ctx context.Context, publicKey string, includeRevoked bool) ([]PublicKeyAddress, error) {
+	keys, _, err := r.ListAccountsByPublicKey(ctx, publicKey, maxPublicKeyAddressRows, 0)
+	if err != nil {
+		return nil, err
+	}
+	return groupKeyAddresses(keys, includeRevoked), nil
+}
+
+// groupKeyAddresses folds key rows into one entry per address, active accounts
+// first and then most recently updated.
+func groupKeyAddresses(keys []models.AccountKey, includeRevoked bool) []PublicKeyAddress {
+	byAddr := make(map[string]*PublicKeyAddress)
+	var order []string
+	for _, k := range keys {
+		if k.Revoked && !includeRevoked {
+			continue
+		}
+		a := byAddr[k.Address]
+		if a == nil {
+			a = &PublicKeyAddress{Address: k.Address}
+			byAddr[k.Address] = a
+			order = append(order, k.Address)
+		}
+		a.KeyIndexes = append(a.KeyIndexes, k.KeyIndex)
+		a.Active = a.Active || !k.Revoked
+		a.LastUpdatedHeight = max(a.LastUpdatedHeight, k.LastUpdatedHeight)
+	}
+
+	out := make([]PublicKeyAddress, 0, len(order))
+	for _, addr := range order {
+		a := byAddr[addr]
+		sort.Ints(a.KeyIndexes)
+		out = append(out, *a)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Active != out[j].Active {
+			return out[i].Active
+		}
+		return out[i].LastUpdatedHeight > out[j].LastUpdatedHeight
+	})
+	return out
+}
+
+// FullKeyWeight is the total key weight a Flow account needs to authorize a
+// transaction.
+const FullKeyWeight = 1000
+
+// ControlledAccount is an account where one public key carries Weight of
+// un-revoked signing weight, possibly split across several key indexes.
+type ControlledAccount struct {
+	Address    string `json:"address"`
+	KeyIndexes []int  `json:"key_indexes"`
+	Weight     int    `json:"weight"`
+}
+
+// GetAccountsControlledByKey returns the accounts where publicKey's total
+// un-revoked weight is at least minWeight, heaviest first.
+func (r *Repository) GetAccountsControlledByKey(ctx context.Context, publicKey string, minWeight int) ([]ControlledAccount, error) {
+	keys, _, err := r.ListAccountsByPublicKey(ctx, publicKey, maxPublicKeyAddressRows, 0)
+	if err != nil {
+		return nil, err
+	}
+	return controlledAccounts(keys, minWeight), nil
+}
+
+// controlledAccounts sums un-revoked key weight per address and keeps the
+// addresses meeting minWeight.
+func controlledAccounts(keys []models.AccountKey, minWeight int) []ControlledAccount {
+	byAddr := make(map[string]*ControlledAccount)
+	for _, k := range keys {
+		if k.Revoked {
+			continue
+		}
+		a := byAddr[k.Address]
+		if a == nil {
+			a = &ControlledAccount{Address: k.Address}
+			byAddr[k.Address] = a
+		}
+		a.KeyIndexes = append(a.KeyIndexes, k.KeyIndex)
+		a.Weight += k.Weight
+	}
+
+	out := make([]ControlledAccount, 0, len(byAddr))
+	for _, a := range byAddr {
+		if a.Weight < minWeight {
+			continue
+		}
+		sort.Ints(a.KeyIndexes)
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Weight != out[j].Weight {
+			return out[i].Weight > out[j].Weight
+		}
+		return out[i].Address < out[j].Address
+	})
+	return out
+}
+
 // IndexedRange represents a contiguous range of indexed block heights.
 type IndexedRange struct {
 	From uint64 `json:"from"`
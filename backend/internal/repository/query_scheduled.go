@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -454,7 +455,7 @@ func (r *Repository) GetExecutorEvents(ctx context.Context, txID string, blockHe
 	txIDBytes, _ := hex.DecodeString(txID)
 
 	q := `
-		SELECT type, COALESCE(event_name, ''), event_index, payload
+		SELECT type, COALESCE(event_name, ''), event_index, payload, payload_compressed
 		FROM raw.events
 		WHERE transaction_id = $1 AND block_height = $2
 		ORDER BY event_index
@@ -470,9 +471,19 @@ func (r *Repository) GetExecutorEvents(ctx context.Context, txID string, blockHe
 		var eventType, eventName string
 		var eventIndex int
 		var payload interface{}
-		if err := rows.Scan(&eventType, &eventName, &eventIndex, &payload); err != nil {
+		var compressed []byte
+		if err := rows.Scan(&eventType, &eventName, &eventIndex, &payload, &compressed); err != nil {
 			return nil, err
 		}
+		if len(compressed) > 0 {
+			raw, err := decodeEventPayload(compressed)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(raw, &payload); err != nil {
+				return nil, fmt.Errorf("decode executor event payload: %w", err)
+			}
+		}
 		results = append(results, map[string]interface{}{
 			"type":        eventType,
 			"event_name":  eventName,
@@ -15,11 +15,16 @@ type TransactionFilter struct {
 	Proposer      string
 	Authorizer    string
 	Status        string
+	Kind          string // TxKindTransfer keeps only simple token transfers
 	Limit         int
 	Offset        int
 	IncludeEvents bool
 }
 
+// TxKindTransfer is the TransactionFilter.Kind for transactions tagged
+// models.TxTagSimpleTransfer.
+const TxKindTransfer = "transfer"
+
 // ListTransactionsFiltered returns transactions using basic filters.
 func (r *Repository) ListTransactionsFiltered(ctx context.Context, f TransactionFilter) ([]models.Transaction, error) {
 	if f.Limit <= 0 {
@@ -31,7 +36,7 @@ func (r *Repository) ListTransactionsFiltered(ctx context.Context, f Transaction
 
 	// Fast path: no filters → use tx_lookup (non-partitioned, indexed) to find
 	// latest tx IDs, then join raw.transactions with known heights for partition pruning.
-	hasFilters := f.Height != nil || f.Payer != "" || f.Proposer != "" || f.Authorizer != "" || f.Status != ""
+	hasFilters := f.Height != nil || f.Payer != "" || f.Proposer != "" || f.Authorizer != "" || f.Status != "" || f.Kind != ""
 	if !hasFilters {
 		return r.listLatestTransactions(ctx, f.Limit, f.Offset)
 	}
@@ -62,8 +67,29 @@ func (r *Repository) ListTransactionsFiltered(ctx context.Context, f Transaction
 		arg++
 	}
 	if f.Status != "" {
-		clauses = append(clauses, fmt.Sprintf("t.status = $%d", arg))
-		args = append(args, f.Status)
+		status, ok := models.ParseTxStatus(f.Status)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q (want SEALED, EXECUTED, EXPIRED, PENDING or FAILED)", ErrInvalidTxStatus, f.Status)
+		}
+		// Rows indexed before statuses were normalized store SEALED plus an
+		// error message for failed transactions.
+		switch status {
+		case models.TxStatusFailed:
+			clauses = append(clauses, fmt.Sprintf("(t.status = $%d OR COALESCE(t.error_message, '') <> '')", arg))
+		case models.TxStatusSealed, models.TxStatusExecuted:
+			clauses = append(clauses, fmt.Sprintf("(t.status = $%d AND COALESCE(t.error_message, '') = '')", arg))
+		default:
+			clauses = append(clauses, fmt.Sprintf("t.status = $%d", arg))
+		}
+		args = append(args, status)
+		arg++
+	}
+	if f.Kind != "" {
+		if f.Kind != TxKindTransfer {
+			return nil, fmt.Errorf("%w: %q (want transfer)", ErrInvalidTxKind, f.Kind)
+		}
+		clauses = append(clauses, fmt.Sprintf("EXISTS (SELECT 1 FROM app.tx_tags tg WHERE tg.transaction_id = t.id AND tg.tag = $%d)", arg))
+		args = append(args, models.TxTagSimpleTransfer)
 		arg++
 	}
 
@@ -74,7 +100,7 @@ func (r *Repository) ListTransactionsFiltered(ctx context.Context, f Transaction
 
 	args = append(args, f.Limit, f.Offset)
 
-	rows, err := r.db.Query(ctx, `
+	rows, err := r.readQuery(ctx, "transactions_filtered", `
 		SELECT encode(t.id, 'hex') AS id, t.block_height, t.transaction_index,
 		       COALESCE(encode(t.proposer_address, 'hex'), '') AS proposer_address,
 		       COALESCE(encode(t.payer_address, 'hex'), '') AS payer_address,
@@ -115,7 +141,7 @@ func (r *Repository) ListTransactionsFiltered(ctx context.Context, f Transaction
 // to quickly find the most recent tx IDs, then joins raw.transactions with the
 // known block_height for each row so PostgreSQL can prune partitions.
 func (r *Repository) listLatestTransactions(ctx context.Context, limit, offset int) ([]models.Transaction, error) {
-	rows, err := r.db.Query(ctx, `
+	rows, err := r.readQuery(ctx, "latest_transactions", `
 		WITH latest AS (
 			SELECT id, block_height, transaction_index
 			FROM raw.tx_lookup
@@ -154,7 +180,7 @@ func (r *Repository) listLatestTransactions(ctx context.Context, limit, offset i
 }
 
 func (r *Repository) ListTransactionsByBlock(ctx context.Context, height uint64, includeEvents bool) ([]models.Transaction, error) {
-	rows, err := r.db.Query(ctx, `
+	rows, err := r.query(ctx, "transactions_by_block", `
 		SELECT encode(t.id, 'hex') AS id, t.block_height, t.transaction_index,
 		       COALESCE(encode(t.proposer_address, 'hex'), '') AS proposer_address,
 		       COALESCE(encode(t.payer_address, 'hex'), '') AS payer_address,
@@ -207,7 +233,7 @@ func (r *Repository) GetEventsByTransactionIDs(ctx context.Context, txIDs []stri
 		return nil, nil
 	}
 	txIDBytes := sliceHexToBytes(txIDs)
-	rows, err := r.db.Query(ctx, `
+	rows, err := r.query(ctx, "events_by_tx_ids", `
 		SELECT event_index AS id,
 		       encode(transaction_id, 'hex') AS transaction_id,
 		       transaction_index, type, event_index,
@@ -216,7 +242,7 @@ func (r *Repository) GetEventsByTransactionIDs(ctx context.Context, txIDs []stri
 		       COALESCE(event_name, '') AS event_name,
 		       COALESCE(payload, '{}'::jsonb) AS payload,
 		       '{}'::jsonb AS values,
-		       block_height, timestamp
+		       block_height, timestamp, payload_compressed
 		FROM raw.events
 		WHERE transaction_id = ANY($1)
 		ORDER BY block_height DESC, transaction_index ASC, event_index ASC`, txIDBytes)
@@ -228,8 +254,12 @@ func (r *Repository) GetEventsByTransactionIDs(ctx context.Context, txIDs []stri
 	var out []models.Event
 	for rows.Next() {
 		var e models.Event
+		var compressed []byte
 		if err := rows.Scan(&e.ID, &e.TransactionID, &e.TransactionIndex, &e.Type, &e.EventIndex, &e.ContractAddress, &e.ContractName,
-			&e.EventName, &e.Payload, &e.Values, &e.BlockHeight, &e.Timestamp); err != nil {
+			&e.EventName, &e.Payload, &e.Values, &e.BlockHeight, &e.Timestamp, &compressed); err != nil {
+			return nil, err
+		}
+		if err := restoreEventPayload(&e, compressed); err != nil {
 			return nil, err
 		}
 		out = append(out, e)
@@ -243,7 +273,7 @@ func (r *Repository) GetEventsByTxRefs(ctx context.Context, refs []TxRef) ([]mod
 		return nil, nil
 	}
 	txIDBytes, heights := splitTxRefs(refs)
-	rows, err := r.db.Query(ctx, `
+	rows, err := r.query(ctx, "events_by_tx_refs", `
 		SELECT event_index AS id,
 		       encode(transaction_id, 'hex') AS transaction_id,
 		       transaction_index, type, event_index,
@@ -252,7 +282,7 @@ func (r *Repository) GetEventsByTxRefs(ctx context.Context, refs []TxRef) ([]mod
 		       COALESCE(event_name, '') AS event_name,
 		       COALESCE(payload, '{}'::jsonb) AS payload,
 		       '{}'::jsonb AS values,
-		       block_height, timestamp
+		       block_height, timestamp, payload_compressed
 		FROM raw.events
 		WHERE transaction_id = ANY($1) AND block_height = ANY($2)
 		ORDER BY block_height DESC, transaction_index ASC, event_index ASC`, txIDBytes, heights)
@@ -264,8 +294,12 @@ func (r *Repository) GetEventsByTxRefs(ctx context.Context, refs []TxRef) ([]mod
 	var out []models.Event
 	for rows.Next() {
 		var e models.Event
+		var compressed []byte
 		if err := rows.Scan(&e.ID, &e.TransactionID, &e.TransactionIndex, &e.Type, &e.EventIndex, &e.ContractAddress, &e.ContractName,
-			&e.EventName, &e.Payload, &e.Values, &e.BlockHeight, &e.Timestamp); err != nil {
+			&e.EventName, &e.Payload, &e.Values, &e.BlockHeight, &e.Timestamp, &compressed); err != nil {
+			return nil, err
+		}
+		if err := restoreEventPayload(&e, compressed); err != nil {
 			return nil, err
 		}
 		out = append(out, e)
@@ -274,7 +308,7 @@ func (r *Repository) GetEventsByTxRefs(ctx context.Context, refs []TxRef) ([]mod
 }
 
 func (r *Repository) GetEventsByBlockHeight(ctx context.Context, height uint64) ([]models.Event, error) {
-	rows, err := r.db.Query(ctx, `
+	rows, err := r.query(ctx, "events_by_block", `
 		SELECT event_index AS id,
 		       encode(transaction_id, 'hex') AS transaction_id,
 		       transaction_index, type, event_index,
@@ -283,7 +317,7 @@ func (r *Repository) GetEventsByBlockHeight(ctx context.Context, height uint64)
 		       COALESCE(event_name, '') AS event_name,
 		       COALESCE(payload, '{}'::jsonb) AS payload,
 		       '{}'::jsonb AS values,
-		       block_height, timestamp
+		       block_height, timestamp, payload_compressed
 		FROM raw.events
 		WHERE block_height = $1
 		ORDER BY transaction_index ASC, event_index ASC`, height)
@@ -294,8 +328,12 @@ func (r *Repository) GetEventsByBlockHeight(ctx context.Context, height uint64)
 	var out []models.Event
 	for rows.Next() {
 		var e models.Event
+		var compressed []byte
 		if err := rows.Scan(&e.ID, &e.TransactionID, &e.TransactionIndex, &e.Type, &e.EventIndex, &e.ContractAddress, &e.ContractName,
-			&e.EventName, &e.Payload, &e.Values, &e.BlockHeight, &e.Timestamp); err != nil {
+			&e.EventName, &e.Payload, &e.Values, &e.BlockHeight, &e.Timestamp, &compressed); err != nil {
+			return nil, err
+		}
+		if err := restoreEventPayload(&e, compressed); err != nil {
 			return nil, err
 		}
 		out = append(out, e)
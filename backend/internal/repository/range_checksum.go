@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+// RangeChecksum summarizes the indexed contents of a block height range so two
+// indexer instances can cheaply verify they agree on [FromHeight, ToHeight].
+type RangeChecksum struct {
+	FromHeight    uint64 `json:"from_height"`
+	ToHeight      uint64 `json:"to_height"`
+	Checksum      string `json:"checksum"`
+	BlockCount    uint64 `json:"block_count"`
+	MissingBlocks uint64 `json:"missing_blocks"`
+	TxCount       uint64 `json:"tx_count"`
+	EventCount    uint64 `json:"event_count"`
+}
+
+// checksumModulus bounds the running sum to 256 bits so the checksum has a fixed width.
+var checksumModulus = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// rangeChecksumAccumulator folds per-block (height, tx_count, event_count) tuples
+// into an order-independent checksum: the sum of per-block SHA-256 digests mod 2^256.
+// Because addition commutes, sub-ranges can be computed in parallel and combined.
+type rangeChecksumAccumulator struct {
+	sum        *big.Int
+	blocks     uint64
+	txCount    uint64
+	eventCount uint64
+}
+
+func newRangeChecksumAccumulator() *rangeChecksumAccumulator {
+	return &rangeChecksumAccumulator{sum: new(big.Int)}
+}
+
+func blockTupleDigest(height, txCount, eventCount uint64) []byte {
+	var buf [24]byte
+	binary.BigEndian.PutUint64(buf[0:8], height)
+	binary.BigEndian.PutUint64(buf[8:16], txCount)
+	binary.BigEndian.PutUint64(buf[16:24], eventCount)
+	d := sha256.Sum256(buf[:])
+	return d[:]
+}
+
+func (a *rangeChecksumAccumulator) add(height, txCount, eventCount uint64) {
+	a.sum.Add(a.sum, new(big.Int).SetBytes(blockTupleDigest(height, txCount, eventCount)))
+	a.sum.Mod(a.sum, checksumModulus)
+	a.blocks++
+	a.txCount += txCount
+	a.eventCount += eventCount
+}
+
+func (a *rangeChecksumAccumulator) checksum() string {
+	out := make([]byte, 32)
+	a.sum.FillBytes(out)
+	return hex.EncodeToString(out)
+}
+
+// ComputeRangeChecksum returns a deterministic checksum over the blocks in
+// [fromHeight, toHeight] (inclusive). Transaction and event counts are taken from
+// the raw tables rather than the denormalized block columns, so a missing row in
+// either table changes the result.
+func (r *Repository) ComputeRangeChecksum(ctx context.Context, fromHeight, toHeight uint64) (*RangeChecksum, error) {
+	if toHeight < fromHeight {
+		return nil, fmt.Errorf("invalid range: to_height %d < from_height %d", toHeight, fromHeight)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		WITH tx AS (
+			SELECT block_height, COUNT(*) AS cnt
+			FROM raw.transactions
+			WHERE block_height >= $1 AND block_height <= $2
+			GROUP BY block_height
+		), ev AS (
+			SELECT block_height, COUNT(*) AS cnt
+			FROM raw.events
+			WHERE block_height >= $1 AND block_height <= $2
+			GROUP BY block_height
+		)
+		SELECT b.height, COALESCE(tx.cnt, 0), COALESCE(ev.cnt, 0)
+		FROM raw.blocks b
+		LEFT JOIN tx ON tx.block_height = b.height
+		LEFT JOIN ev ON ev.block_height = b.height
+		WHERE b.height >= $1 AND b.height <= $2`, fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	acc := newRangeChecksumAccumulator()
+	for rows.Next() {
+		var height, txCount, eventCount int64
+		if err := rows.Scan(&height, &txCount, &eventCount); err != nil {
+			return nil, err
+		}
+		acc.add(uint64(height), uint64(txCount), uint64(eventCount))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	expected := toHeight - fromHeight + 1
+	missing := uint64(0)
+	if acc.blocks < expected {
+		missing = expected - acc.blocks
+	}
+	return &RangeChecksum{
+		FromHeight:    fromHeight,
+		ToHeight:      toHeight,
+		Checksum:      acc.checksum(),
+		BlockCount:    acc.blocks,
+		MissingBlocks: missing,
+		TxCount:       acc.txCount,
+		EventCount:    acc.eventCount,
+	}, nil
+}
+
+// BlockIndexCounts is what is stored for one block: whether raw.blocks has the
+// row, its denormalized tx_count, and the rows actually present in
+// raw.transactions and raw.events.
+type BlockIndexCounts struct {
+	Indexed      bool   `json:"indexed"`
+	BlockTxCount uint64 `json:"block_tx_count"`
+	TxCount      uint64 `json:"tx_count"`
+	EventCount   uint64 `json:"event_count"`
+}
+
+// GetBlockIndexCounts returns the stored counts for height. A block that is not
+// indexed returns Indexed=false and zero counts.
+func (r *Repository) GetBlockIndexCounts(ctx context.Context, height uint64) (BlockIndexCounts, error) {
+	var out BlockIndexCounts
+	var blockTxCount, txCount, eventCount int64
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			EXISTS (SELECT 1 FROM raw.blocks WHERE height = $1),
+			COALESCE((SELECT tx_count FROM raw.blocks WHERE height = $1), 0),
+			(SELECT COUNT(*) FROM raw.transactions WHERE block_height = $1),
+			(SELECT COUNT(*) FROM raw.events WHERE block_height = $1)`, height).
+		Scan(&out.Indexed, &blockTxCount, &txCount, &eventCount)
+	if err != nil {
+		return BlockIndexCounts{}, err
+	}
+	out.BlockTxCount, out.TxCount, out.EventCount = uint64(blockTxCount), uint64(txCount), uint64(eventCount)
+	return out, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import "testing"
+
+type blockTuple struct {
+	height, txCount, eventCount uint64
+}
+
+func checksumOf(tuples []blockTuple) string {
+	acc := newRangeChecksumAccumulator()
+	for _, b := range tuples {
+		acc.add(b.height, b.txCount, b.eventCount)
+	}
+	return acc.checksum()
+}
+
+func TestRangeChecksumOrderIndependent(t *testing.T) {
+	t.Parallel()
+
+	forward := []blockTuple{{100, 2, 7}, {101, 0, 1}, {102, 5, 20}, {103, 1, 3}}
+	shuffled := []blockTuple{{102, 5, 20}, {100, 2, 7}, {103, 1, 3}, {101, 0, 1}}
+
+	if a, b := checksumOf(forward), checksumOf(shuffled); a != b {
+		t.Fatalf("checksum depends on row order: %s != %s", a, b)
+	}
+	if got := checksumOf(forward); len(got) != 64 {
+		t.Fatalf("expected 64 hex chars, got %d (%s)", len(got), got)
+	}
+}
+
+func TestRangeChecksumDetectsDeletedRows(t *testing.T) {
+	t.Parallel()
+
+	full := []blockTuple{{100, 2, 7}, {101, 0, 1}, {102, 5, 20}}
+	before := checksumOf(full)
+
+	// Deleting an event from block 102 lowers its event_count.
+	missingEvent := []blockTuple{{100, 2, 7}, {101, 0, 1}, {102, 5, 19}}
+	if checksumOf(missingEvent) == before {
+		t.Fatal("checksum unchanged after deleting an event row")
+	}
+
+	// Deleting the whole block drops its tuple.
+	missingBlock := []blockTuple{{100, 2, 7}, {102, 5, 20}}
+	if checksumOf(missingBlock) == before {
+		t.Fatal("checksum unchanged after deleting a block row")
+	}
+}
+
+func TestRangeChecksumSubRangesCombine(t *testing.T) {
+	t.Parallel()
+
+	whole := newRangeChecksumAccumulator()
+	lo := newRangeChecksumAccumulator()
+	hi := newRangeChecksumAccumulator()
+	for h := uint64(1); h <= 10; h++ {
+		whole.add(h, h, h*2)
+		if h <= 5 {
+			lo.add(h, h, h*2)
+		} else {
+			hi.add(h, h, h*2)
+		}
+	}
+	lo.sum.Add(lo.sum, hi.sum)
+	lo.sum.Mod(lo.sum, checksumModulus)
+	if lo.checksum() != whole.checksum() {
+		t.Fatalf("combined sub-range checksum %s != whole-range %s", lo.checksum(), whole.checksum())
+	}
+}
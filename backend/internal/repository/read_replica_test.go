@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// unreachablePool returns a pool that connects lazily to a closed port, so any
+// query fails with an error naming the pool's user.
+func unreachablePool(t *testing.T, user string) *pgxpool.Pool {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), "postgres://"+user+"@127.0.0.1:1/flowscan?connect_timeout=1")
+	if err != nil {
+		t.Fatalf("pool for %s: %v", user, err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestReadReplicaRouting(t *testing.T) {
+	t.Parallel()
+
+	primary := unreachablePool(t, "primary_user")
+	replica := unreachablePool(t, "replica_user")
+	r := &Repository{db: primary, read: replica}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	reads := map[string]error{}
+	_, reads["ListBlocks"] = r.ListBlocks(ctx, 10, 0)
+	_, reads["GetAccountGrowth"] = r.GetAccountGrowth(ctx, from, from.AddDate(0, 0, 7))
+	_, reads["GetAnalyticsDailyAccountsModule"] = r.GetAnalyticsDailyAccountsModule(ctx, from, from.AddDate(0, 0, 7))
+	for name, err := range reads {
+		if err == nil || !strings.Contains(err.Error(), "replica_user") {
+			t.Errorf("%s should use the read pool, got %v", name, err)
+		}
+	}
+
+	err := r.UpsertFTTokens(ctx, []models.FTToken{{ContractAddress: "1654653399040a61", ContractName: "FlowToken"}})
+	if err == nil || !strings.Contains(err.Error(), "primary_user") {
+		t.Errorf("UpsertFTTokens should use the primary pool, got %v", err)
+	}
+
+	if got := (&Repository{db: primary}).readDB(); got != primary {
+		t.Errorf("readDB without a replica should fall back to the primary")
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+// TestResetProcessorForRederive needs a database with the schema applied. A
+// completed range cannot be leased again until the processor is reset; after
+// the reset it can, and the history deriver rescans only the reset lane.
+func TestResetProcessorForRederive(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	repo, err := NewRepository(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	ctx := context.Background()
+
+	const (
+		worker  = "test_rederive_worker"
+		sibling = "test_rederive_sibling"
+		prefix  = "test_rederive_history"
+	)
+	checkpoints := []string{worker, prefix, prefix + ":" + worker, prefix + ":" + sibling}
+	cleanup := func() {
+		repo.db.Exec(ctx, `DELETE FROM app.worker_leases WHERE worker_type = $1`, worker)
+		repo.db.Exec(ctx, `DELETE FROM app.indexing_checkpoints WHERE service_name = ANY($1)`, checkpoints)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	id, err := repo.AcquireLease(ctx, worker, 1000, 2000, "test")
+	if err != nil || id == 0 {
+		t.Fatalf("first lease = %d, %v", id, err)
+	}
+	if err := repo.CompleteLease(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	for name, h := range map[string]uint64{worker: 2000, prefix: 5000, prefix + ":" + worker: 6000} {
+		if err := repo.SetCheckpoint(ctx, name, h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Resetting only the checkpoint leaves the completed lease in the way.
+	if err := repo.SetCheckpoint(ctx, worker, 0); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := repo.AcquireLease(ctx, worker, 1000, 2000, "test"); err != nil || id != 0 {
+		t.Fatalf("lease over a completed range = %d, %v; want 0", id, err)
+	}
+	if id, err := repo.ReclaimLease(ctx, worker, 1000, 2000, "test"); err != nil || id != 0 {
+		t.Fatalf("reclaim of a completed range = %d, %v; want 0", id, err)
+	}
+
+	deleted, err := repo.ResetProcessorForRederive(ctx, worker, []string{prefix}, []string{worker, sibling})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Fatalf("deleted %d leases; want 1", deleted)
+	}
+	if id, err := repo.AcquireLease(ctx, worker, 1000, 2000, "test"); err != nil || id == 0 {
+		t.Fatalf("lease after reset = %d, %v; want a new lease", id, err)
+	}
+
+	for name, want := range map[string]uint64{
+		worker:                 0,
+		prefix:                 0,    // UP restarts from the lowest raw block
+		prefix + ":" + worker:  0,    // the reset lane rescans
+		prefix + ":" + sibling: 5000, // pinned at the old shared cursor
+	} {
+		got, err := repo.GetLastIndexedHeight(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s = %d; want %d", name, got, want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -12,10 +13,74 @@ import (
 )
 
 type Repository struct {
-	db *pgxpool.Pool
+	db           *pgxpool.Pool
+	read         *pgxpool.Pool  // optional replica for heavy API reads, see readDB
+	scriptSource ScriptSource   // optional, see SetScriptSource
+	queries      *queryObserver // optional, see query/queryRow in slow_query.go
+
+	payloadCompression byte // EVENT_PAYLOAD_COMPRESSION, see event_payload.go
+	copyChunkRows      int  // DB_COPY_CHUNK_ROWS, see copy_chunks.go
 }
 
+// NewRepository connects to dbURL. When DB_READ_URL is set to a different
+// database, a second pool is opened for the heavy read-only API queries so they
+// do not compete with ingestion writes on the primary.
 func NewRepository(dbURL string) (*Repository, error) {
+	pool, err := newPool(dbURL)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &Repository{
+		db:                 pool,
+		queries:            newQueryObserver(slowQueryThresholdFromEnv()),
+		payloadCompression: eventPayloadCompressionFromEnv(),
+		copyChunkRows:      copyChunkRowsFromEnv(),
+	}
+	if readURL := strings.TrimSpace(os.Getenv("DB_READ_URL")); readURL != "" && readURL != dbURL {
+		read, err := newPool(readURL)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("read replica: %w", err)
+		}
+		repo.read = read
+	}
+	if err := repo.ensureScriptTemplatesSchema(context.Background()); err != nil {
+		repo.Close()
+		return nil, fmt.Errorf("ensure script_templates schema: %w", err)
+	}
+	return repo, nil
+}
+
+// PoolUsage is a snapshot of the primary pool's connection usage.
+type PoolUsage struct {
+	AcquiredConns int32   `json:"acquired_conns"`
+	MaxConns      int32   `json:"max_conns"`
+	Saturation    float64 `json:"saturation"` // AcquiredConns / MaxConns
+}
+
+// PoolUsage reports how many of the primary pool's connections are checked
+// out. Ingestion and API writes share this pool, so a saturated pool means
+// one side is about to wait on the other.
+func (r *Repository) PoolUsage() PoolUsage {
+	st := r.db.Stat()
+	u := PoolUsage{AcquiredConns: st.AcquiredConns(), MaxConns: st.MaxConns()}
+	if u.MaxConns > 0 {
+		u.Saturation = float64(u.AcquiredConns) / float64(u.MaxConns)
+	}
+	return u
+}
+
+// readDB returns the pool for heavy read-only queries: the replica when
+// DB_READ_URL is configured, otherwise the primary.
+func (r *Repository) readDB() *pgxpool.Pool {
+	if r.read != nil {
+		return r.read
+	}
+	return r.db
+}
+
+func newPool(dbURL string) (*pgxpool.Pool, error) {
 	config, err := pgxpool.ParseConfig(dbURL)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse db url: %w", err)
@@ -56,13 +121,7 @@ func NewRepository(dbURL string) (*Repository, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to connect to database: %w", err)
 	}
-
-	repo := &Repository{db: pool}
-	if err := repo.ensureScriptTemplatesSchema(context.Background()); err != nil {
-		pool.Close()
-		return nil, fmt.Errorf("ensure script_templates schema: %w", err)
-	}
-	return repo, nil
+	return pool, nil
 }
 
 func getEnvDefault(key, def string) string {
@@ -175,6 +234,9 @@ func RunMigration(ctx context.Context, dbURL, schemaPath string, terminateConns
 
 func (r *Repository) Close() {
 	r.db.Close()
+	if r.read != nil {
+		r.read.Close()
+	}
 }
 
 // TerminateOtherConnections kills ALL connections from previous backend instances
@@ -101,11 +101,32 @@ func (r *Repository) RollbackFromHeight(ctx context.Context, rollbackHeight uint
 	if _, err := tx.Exec(ctx, "DELETE FROM raw.block_lookup WHERE height >= $1", rollbackHeight); err != nil {
 		return fmt.Errorf("rollback raw.block_lookup: %w", err)
 	}
+	if _, err := tx.Exec(ctx, "DELETE FROM raw.collections WHERE block_height >= $1", rollbackHeight); err != nil {
+		return fmt.Errorf("rollback raw.collections: %w", err)
+	}
 
 	// Derived tables with block_height — precise deletes
 	if _, err := tx.Exec(ctx, "DELETE FROM app.ft_transfers WHERE block_height >= $1", rollbackHeight); err != nil {
 		return fmt.Errorf("rollback app.ft_transfers: %w", err)
 	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE app.nft_collection_supply s SET
+			minted_count = GREATEST(s.minted_count - d.minted, 0),
+			burned_count = GREATEST(s.burned_count - d.burned, 0),
+			updated_at = NOW()
+		FROM (
+			SELECT token_contract_address, COALESCE(contract_name, '') AS contract_name,
+			       COUNT(*) FILTER (WHERE COALESCE(length(from_address), 0) = 0) AS minted,
+			       COUNT(*) FILTER (WHERE COALESCE(length(to_address), 0) = 0) AS burned
+			FROM app.nft_transfers
+			WHERE block_height >= $1
+			  AND (COALESCE(length(from_address), 0) = 0) <> (COALESCE(length(to_address), 0) = 0)
+			GROUP BY 1, 2
+		) d
+		WHERE s.contract_address = d.token_contract_address AND s.contract_name = d.contract_name
+	`, rollbackHeight); err != nil {
+		return fmt.Errorf("rollback app.nft_collection_supply: %w", err)
+	}
 	if _, err := tx.Exec(ctx, "DELETE FROM app.nft_transfers WHERE block_height >= $1", rollbackHeight); err != nil {
 		return fmt.Errorf("rollback app.nft_transfers: %w", err)
 	}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"flowscan-clone/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ScriptUsage is a deduplicated transaction script from raw.scripts and how
+// many transactions used it.
+type ScriptUsage struct {
+	ScriptHash string `json:"script_hash"`
+	ScriptText string `json:"script_text,omitempty"`
+	TxCount    int64  `json:"tx_count"`
+}
+
+// GetScriptByHash returns the script stored under hash with its usage count,
+// or nil if no such script exists. Scripts stored by hash only are loaded
+// through FetchScriptFromChainIfMissing.
+func (r *Repository) GetScriptByHash(ctx context.Context, hash string) (*ScriptUsage, error) {
+	s := ScriptUsage{ScriptHash: hash}
+	var text *string
+	err := r.db.QueryRow(ctx, `
+		SELECT (SELECT COALESCE(script_text, '') FROM raw.scripts WHERE script_hash = $1),
+		       (SELECT COUNT(*) FROM raw.transactions WHERE script_hash = $1)`, hash).Scan(&text, &s.TxCount)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case text != nil:
+		s.ScriptText = *text
+	case s.TxCount == 0:
+		return nil, nil
+	default:
+		if s.ScriptText, err = r.FetchScriptFromChainIfMissing(ctx, hash); err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
+
+// ListPopularScripts returns the most used scripts by transaction count. The
+// aggregation is an index-only scan over idx_transactions_script_hash; script
+// text is omitted to keep the response small.
+func (r *Repository) ListPopularScripts(ctx context.Context, limit, offset int) ([]ScriptUsage, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT script_hash, COUNT(*) AS tx_count
+		FROM raw.transactions
+		WHERE script_hash IS NOT NULL
+		GROUP BY script_hash
+		ORDER BY tx_count DESC, script_hash
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []ScriptUsage{}
+	for rows.Next() {
+		var s ScriptUsage
+		if err := rows.Scan(&s.ScriptHash, &s.TxCount); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// ScriptSource loads a transaction's script from the chain. It is used to
+// serve script bodies that were stored by hash only (TX_SCRIPT_STORE_MAX_BYTES).
+type ScriptSource interface {
+	GetTransactionScript(ctx context.Context, txID string, height uint64) (string, error)
+}
+
+// SetScriptSource enables on-demand loading of scripts missing from raw.scripts.
+func (r *Repository) SetScriptSource(src ScriptSource) {
+	r.scriptSource = src
+}
+
+// envScriptBytes reads a non-negative byte threshold; 0 (or unset) disables it.
+func envScriptBytes(key string) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// planScriptStorage hashes each transaction script and decides where its body
+// goes: inline in raw.transactions when <= inlineMax (if > 0), and into
+// raw.scripts unless it exceeds storeMax (if > 0). Over-limit scripts keep
+// only their hash and are fetched from the chain when requested.
+func planScriptStorage(txs []models.Transaction, inlineMax, storeMax int) (hashes, inlines []string, bodies map[string]string) {
+	hashes = make([]string, len(txs))
+	inlines = make([]string, len(txs))
+	bodies = make(map[string]string)
+	for i := range txs {
+		scriptText := strings.TrimSpace(txs[i].Script)
+		if scriptText == "" {
+			continue
+		}
+		sum := sha256.Sum256([]byte(scriptText))
+		hashes[i] = hex.EncodeToString(sum[:])
+		if storeMax > 0 && len(scriptText) > storeMax {
+			continue
+		}
+		if _, ok := bodies[hashes[i]]; !ok {
+			bodies[hashes[i]] = sanitizeForPG(scriptText)
+		}
+		if inlineMax > 0 && len(scriptText) <= inlineMax {
+			inlines[i] = scriptText
+		}
+	}
+	return hashes, inlines, bodies
+}
+
+type scriptTxRef struct {
+	id     string
+	height uint64
+}
+
+// FetchScriptFromChainIfMissing returns the script text for hash from
+// raw.scripts, falling back to loading it from the chain through one of the
+// transactions that used it. Fetched scripts are not persisted, so storage
+// stays bounded. Returns "" if the script is unknown or no ScriptSource is set.
+func (r *Repository) FetchScriptFromChainIfMissing(ctx context.Context, hash string) (string, error) {
+	var text string
+	err := r.db.QueryRow(ctx, `SELECT COALESCE(script_text, '') FROM raw.scripts WHERE script_hash = $1`, hash).Scan(&text)
+	if err == nil && text != "" {
+		return text, nil
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+	if r.scriptSource == nil {
+		return "", nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT encode(id, 'hex'), block_height
+		FROM raw.transactions
+		WHERE script_hash = $1
+		ORDER BY block_height DESC
+		LIMIT 3`, hash)
+	if err != nil {
+		return "", err
+	}
+	var refs []scriptTxRef
+	for rows.Next() {
+		var ref scriptTxRef
+		if err := rows.Scan(&ref.id, &ref.height); err != nil {
+			rows.Close()
+			return "", err
+		}
+		refs = append(refs, ref)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return fetchScriptFromSource(ctx, r.scriptSource, hash, refs)
+}
+
+// fillMissingScripts loads every hash absent from texts from the chain. It is
+// used by derivation, which must see the full text of scripts stored by hash
+// only.
+func (r *Repository) fillMissingScripts(ctx context.Context, hashes []string, texts map[string]string) error {
+	return resolveMissingScripts(ctx, hashes, texts, r.FetchScriptFromChainIfMissing)
+}
+
+// resolveMissingScripts fills texts for every hash it lacks using fetch. A
+// script that cannot be loaded is an error: deriving from an empty body would
+// silently drop its imports.
+func resolveMissingScripts(ctx context.Context, hashes []string, texts map[string]string, fetch func(context.Context, string) (string, error)) error {
+	for _, h := range hashes {
+		if _, ok := texts[h]; ok || h == "" {
+			continue
+		}
+		text, err := fetch(ctx, h)
+		if err != nil {
+			return err
+		}
+		if text == "" {
+			return fmt.Errorf("script %s is stored by hash only and could not be loaded", h)
+		}
+		texts[h] = text
+	}
+	return nil
+}
+
+// fetchScriptFromSource tries refs in order and returns the first script whose
+// hash matches, so a wrong or truncated response is never served.
+func fetchScriptFromSource(ctx context.Context, src ScriptSource, hash string, refs []scriptTxRef) (string, error) {
+	var lastErr error
+	for _, ref := range refs {
+		text, err := src.GetTransactionScript(ctx, ref.id, ref.height)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		text = strings.TrimSpace(text)
+		sum := sha256.Sum256([]byte(text))
+		if hex.EncodeToString(sum[:]) == hash {
+			return text, nil
+		}
+		lastErr = fmt.Errorf("script of tx %s does not match hash %s", ref.id, hash)
+	}
+	if lastErr != nil {
+		return "", fmt.Errorf("fetch script %s from chain: %w", hash, lastErr)
+	}
+	return "", nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"flowscan-clone/internal/models"
+)
+
+func scriptHashOf(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+type fakeScriptSource struct {
+	scripts map[string]string // txID -> script
+	calls   []string
+}
+
+func (f *fakeScriptSource) GetTransactionScript(_ context.Context, txID string, _ uint64) (string, error) {
+	f.calls = append(f.calls, txID)
+	s, ok := f.scripts[txID]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return s, nil
+}
+
+func TestPlanScriptStorageStoresLargeScriptsByHashOnly(t *testing.T) {
+	t.Parallel()
+
+	small := "transaction { execute {} }"
+	large := "transaction { execute { log(\"" + strings.Repeat("x", 200) + "\") } }"
+	txs := []models.Transaction{{Script: small}, {Script: "  " + large + "\n"}, {Script: ""}, {Script: small}}
+
+	hashes, inlines, bodies := planScriptStorage(txs, 64, 100)
+
+	if hashes[0] != scriptHashOf(small) || hashes[1] != scriptHashOf(large) || hashes[2] != "" || hashes[3] != hashes[0] {
+		t.Fatalf("unexpected hashes: %v", hashes)
+	}
+	if _, ok := bodies[hashes[1]]; ok {
+		t.Error("over-threshold script should be stored by hash only")
+	}
+	if bodies[hashes[0]] != small || len(bodies) != 1 {
+		t.Errorf("bodies = %v, want only the small script", bodies)
+	}
+	if inlines[0] != small || inlines[1] != "" {
+		t.Errorf("inlines = %q", inlines)
+	}
+
+	// Without a store threshold every script body is kept.
+	_, _, bodies = planScriptStorage(txs, 0, 0)
+	if len(bodies) != 2 {
+		t.Errorf("got %d bodies with threshold disabled, want 2", len(bodies))
+	}
+}
+
+func TestFetchScriptFromSourceLazilyLoadsMatchingScript(t *testing.T) {
+	t.Parallel()
+
+	large := "transaction { execute { log(\"" + strings.Repeat("x", 200) + "\") } }"
+	hash := scriptHashOf(large)
+	src := &fakeScriptSource{scripts: map[string]string{
+		"aa": "transaction {}", // wrong script, must be rejected
+		"bb": large + "\n",
+	}}
+	refs := []scriptTxRef{{id: "missing"}, {id: "aa"}, {id: "bb"}, {id: "cc"}}
+
+	got, err := fetchScriptFromSource(context.Background(), src, hash, refs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != large {
+		t.Errorf("got %q, want the large script", got)
+	}
+	if strings.Join(src.calls, ",") != "missing,aa,bb" {
+		t.Errorf("calls = %v, want to stop at the first match", src.calls)
+	}
+
+	if _, err := fetchScriptFromSource(context.Background(), src, hash, refs[:2]); err == nil {
+		t.Error("expected an error when no transaction yields a matching script")
+	}
+	if got, err := fetchScriptFromSource(context.Background(), src, hash, nil); got != "" || err != nil {
+		t.Errorf("no refs: got %q, %v", got, err)
+	}
+}
+
+func TestResolveMissingScriptsLoadsHashOnlyScripts(t *testing.T) {
+	t.Parallel()
+
+	large := "import FlowToken from 0x1654653399040a61\ntransaction { execute { log(\"" + strings.Repeat("x", 200) + "\") } }"
+	stored := "transaction {}"
+	texts := map[string]string{scriptHashOf(stored): stored}
+	var fetched []string
+	fetch := func(_ context.Context, hash string) (string, error) {
+		fetched = append(fetched, hash)
+		if hash == scriptHashOf(large) {
+			return large, nil
+		}
+		return "", nil
+	}
+
+	hashes := []string{scriptHashOf(stored), scriptHashOf(large)}
+	if err := resolveMissingScripts(context.Background(), hashes, texts, fetch); err != nil {
+		t.Fatal(err)
+	}
+	if texts[scriptHashOf(large)] != large {
+		t.Errorf("hash-only script not loaded: %q", texts[scriptHashOf(large)])
+	}
+	if len(fetched) != 1 {
+		t.Errorf("fetched %v; want only the missing hash", fetched)
+	}
+
+	// A script that cannot be loaded must fail the call, not derive as empty.
+	if err := resolveMissingScripts(context.Background(), []string{"deadbeef"}, texts, fetch); err == nil {
+		t.Error("expected an error for an unloadable script")
+	}
+	if _, ok := texts["deadbeef"]; ok {
+		t.Error("unloadable script was recorded")
+	}
+}
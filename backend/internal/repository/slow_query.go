@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// defaultSlowQueryMs is the slow-query log threshold. Override with
+// DB_SLOW_QUERY_MS; 0 disables the log (durations are still recorded).
+const defaultSlowQueryMs = 500
+
+// queryDurationBucketsMs are the upper bounds of the query duration histogram.
+var queryDurationBucketsMs = []int64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// maxFingerprintLen caps the normalized SQL printed in slow-query lines.
+const maxFingerprintLen = 160
+
+var (
+	fpStringRe = regexp.MustCompile(`'(?:[^']|'')*'`)
+	fpNumberRe = regexp.MustCompile(`\b\d+\b`)
+	fpSpaceRe  = regexp.MustCompile(`\s+`)
+)
+
+// QueryFingerprint normalizes SQL for logging: whitespace is collapsed and
+// inline literals become ?. Bound parameters ($1...) are never included. It
+// returns a short stable hash and the (truncated) normalized text.
+func QueryFingerprint(sql string) (string, string) {
+	s := fpStringRe.ReplaceAllString(sql, "?")
+	s = fpNumberRe.ReplaceAllString(s, "?")
+	s = strings.TrimSpace(fpSpaceRe.ReplaceAllString(s, " "))
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	if len(s) > maxFingerprintLen {
+		s = s[:maxFingerprintLen] + "..."
+	}
+	return fmt.Sprintf("%08x", h.Sum32()), s
+}
+
+// QueryStat is the duration histogram for one query tag. Buckets[i] counts
+// queries at or under BucketsMs[i]; the last entry is overflow.
+type QueryStat struct {
+	Count     int64   `json:"count"`
+	Slow      int64   `json:"slow"`
+	TotalMs   float64 `json:"total_ms"`
+	MaxMs     float64 `json:"max_ms"`
+	BucketsMs []int64 `json:"buckets_ms"`
+	Buckets   []int64 `json:"buckets"`
+}
+
+// queryObserver records per-tag query durations and logs queries slower than
+// threshold.
+type queryObserver struct {
+	threshold time.Duration
+	logf      func(format string, args ...interface{})
+
+	mu    sync.Mutex
+	stats map[string]*QueryStat
+}
+
+func newQueryObserver(threshold time.Duration) *queryObserver {
+	return &queryObserver{threshold: threshold, logf: log.Printf, stats: make(map[string]*QueryStat)}
+}
+
+func slowQueryThresholdFromEnv() time.Duration {
+	ms := defaultSlowQueryMs
+	if v := strings.TrimSpace(os.Getenv("DB_SLOW_QUERY_MS")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			ms = n
+		}
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// observe records one query of duration d under tag.
+func (o *queryObserver) observe(tag, sql string, d time.Duration, err error) {
+	ms := float64(d.Microseconds()) / 1000
+	slow := o.threshold > 0 && d >= o.threshold
+
+	o.mu.Lock()
+	st := o.stats[tag]
+	if st == nil {
+		st = &QueryStat{BucketsMs: queryDurationBucketsMs, Buckets: make([]int64, len(queryDurationBucketsMs)+1)}
+		o.stats[tag] = st
+	}
+	st.Count++
+	st.TotalMs += ms
+	if ms > st.MaxMs {
+		st.MaxMs = ms
+	}
+	i := sort.Search(len(queryDurationBucketsMs), func(i int) bool { return float64(queryDurationBucketsMs[i]) >= ms })
+	st.Buckets[i]++
+	if slow {
+		st.Slow++
+	}
+	o.mu.Unlock()
+
+	if slow {
+		fp, text := QueryFingerprint(sql)
+		status := "ok"
+		if err != nil {
+			status = "error"
+		}
+		o.logf("[slow_query] tag=%s duration_ms=%.1f threshold_ms=%d status=%s fingerprint=%s sql=%q",
+			tag, ms, o.threshold.Milliseconds(), status, fp, text)
+	}
+}
+
+// snapshot copies the per-tag stats.
+func (o *queryObserver) snapshot() map[string]QueryStat {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := make(map[string]QueryStat, len(o.stats))
+	for tag, st := range o.stats {
+		cp := *st
+		cp.Buckets = append([]int64(nil), st.Buckets...)
+		out[tag] = cp
+	}
+	return out
+}
+
+// QueryStats returns the per-tag duration histograms of instrumented queries.
+func (r *Repository) QueryStats() map[string]QueryStat {
+	if r.queries == nil {
+		return map[string]QueryStat{}
+	}
+	return r.queries.snapshot()
+}
+
+// timedRows records the query when the caller finishes reading rows, so the
+// duration covers the full result transfer rather than the first round trip.
+type timedRows struct {
+	pgx.Rows
+	done func(error)
+	once sync.Once
+}
+
+func (t *timedRows) Close() {
+	t.Rows.Close()
+	t.once.Do(func() { t.done(t.Rows.Err()) })
+}
+
+func (t *timedRows) Next() bool {
+	if t.Rows.Next() {
+		return true
+	}
+	t.once.Do(func() { t.done(t.Rows.Err()) })
+	return false
+}
+
+type timedRow struct {
+	row  pgx.Row
+	done func(error)
+}
+
+func (t timedRow) Scan(dest ...any) error {
+	err := t.row.Scan(dest...)
+	t.done(err)
+	return err
+}
+
+// query is r.db.Query with duration tracking under tag.
+func (r *Repository) query(ctx context.Context, tag, sql string, args ...any) (pgx.Rows, error) {
+	return r.queryOn(ctx, r.db, tag, sql, args...)
+}
+
+// readQuery is query against readDB, for heavy read-only API queries.
+func (r *Repository) readQuery(ctx context.Context, tag, sql string, args ...any) (pgx.Rows, error) {
+	return r.queryOn(ctx, r.readDB(), tag, sql, args...)
+}
+
+func (r *Repository) queryOn(ctx context.Context, db *pgxpool.Pool, tag, sql string, args ...any) (pgx.Rows, error) {
+	if r.queries == nil {
+		return db.Query(ctx, sql, args...)
+	}
+	start := time.Now()
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		r.queries.observe(tag, sql, time.Since(start), err)
+		return nil, err
+	}
+	return &timedRows{Rows: rows, done: func(err error) { r.queries.observe(tag, sql, time.Since(start), err) }}, nil
+}
+
+// queryRow is r.db.QueryRow with duration tracking under tag.
+func (r *Repository) queryRow(ctx context.Context, tag, sql string, args ...any) pgx.Row {
+	if r.queries == nil {
+		return r.db.QueryRow(ctx, sql, args...)
+	}
+	start := time.Now()
+	row := r.db.QueryRow(ctx, sql, args...)
+	return timedRow{row: row, done: func(err error) { r.queries.observe(tag, sql, time.Since(start), err) }}
+}
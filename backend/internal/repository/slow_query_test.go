@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// sleepyRows is a pgx.Rows that takes delay to deliver its single row.
+type sleepyRows struct {
+	pgx.Rows
+	delay time.Duration
+	read  bool
+}
+
+func (s *sleepyRows) Next() bool {
+	if s.read {
+		return false
+	}
+	time.Sleep(s.delay)
+	s.read = true
+	return true
+}
+
+func (s *sleepyRows) Close()     {}
+func (s *sleepyRows) Err() error { return nil }
+
+type capturedLog struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (c *capturedLog) logf(format string, args ...interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lines = append(c.lines, fmt.Sprintf(format, args...))
+}
+
+func TestSlowQueryLoggedAboveThreshold(t *testing.T) {
+	t.Parallel()
+
+	logs := &capturedLog{}
+	obs := newQueryObserver(20 * time.Millisecond)
+	obs.logf = logs.logf
+
+	run := func(tag, sql string, delay time.Duration) {
+		start := time.Now()
+		rows := &timedRows{
+			Rows: &sleepyRows{delay: delay},
+			done: func(err error) { obs.observe(tag, sql, time.Since(start), err) },
+		}
+		for rows.Next() {
+		}
+		rows.Close()
+	}
+
+	run("recent_blocks", "SELECT height FROM raw.blocks ORDER BY height DESC LIMIT $1", time.Millisecond)
+	run("tx_lookup", "SELECT block_height\n\t\tFROM raw.tx_lookup WHERE id = $1 AND status = 'SEALED' AND n > 42", 40*time.Millisecond)
+
+	if len(logs.lines) != 1 {
+		t.Fatalf("expected exactly the slow query to be logged, got %q", logs.lines)
+	}
+	line := logs.lines[0]
+	for _, want := range []string{"[slow_query]", "tag=tx_lookup", "threshold_ms=20", "status=ok", `sql="SELECT block_height FROM raw.tx_lookup WHERE id = $? AND status = ? AND n > ?"`} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("log line missing %q:\n%s", want, line)
+		}
+	}
+	if strings.Contains(line, "SEALED") || strings.Contains(line, "42") {
+		t.Fatalf("literals leaked into the log: %s", line)
+	}
+
+	stats := obs.snapshot()
+	if stats["tx_lookup"].Count != 1 || stats["tx_lookup"].Slow != 1 {
+		t.Fatalf("tx_lookup stats = %+v", stats["tx_lookup"])
+	}
+	if st := stats["recent_blocks"]; st.Count != 1 || st.Slow != 0 || st.Buckets[0]+st.Buckets[1] != 1 {
+		t.Fatalf("recent_blocks stats = %+v", st)
+	}
+}
+
+func TestSlowQueryDisabledThresholdStillRecords(t *testing.T) {
+	t.Parallel()
+
+	logs := &capturedLog{}
+	obs := newQueryObserver(0)
+	obs.logf = logs.logf
+	obs.observe("block_by_height", "SELECT 1", 10*time.Second, errors.New("boom"))
+	if len(logs.lines) != 0 {
+		t.Fatalf("threshold 0 should disable the log, got %q", logs.lines)
+	}
+	if st := obs.snapshot()["block_by_height"]; st.Count != 1 || st.Buckets[len(st.Buckets)-1] != 1 {
+		t.Fatalf("overflow bucket not counted: %+v", st)
+	}
+
+	fp1, _ := QueryFingerprint("SELECT * FROM t WHERE a = 1")
+	fp2, _ := QueryFingerprint("SELECT *   FROM t\nWHERE a = 2")
+	if fp1 != fp2 {
+		t.Fatalf("fingerprints differ: %s vs %s", fp1, fp2)
+	}
+}
+
+// TestReadQueryCancelledByContext needs a database. It checks that cancelling
+// the caller's context (as net/http does when a client disconnects) aborts a
+// running query promptly instead of letting it run to completion.
+func TestReadQueryCancelledByContext(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	repo, err := NewRepository(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err = func() error {
+		rows, err := repo.readQuery(ctx, "pg_sleep", "SELECT pg_sleep(10)")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+		}
+		return rows.Err()
+	}()
+	if err == nil {
+		t.Fatal("query finished despite cancelled context")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v; want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("cancellation took %s", elapsed)
+	}
+}
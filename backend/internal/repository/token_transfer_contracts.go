@@ -13,6 +13,9 @@ import (
 type TokenTransferWithContract struct {
 	models.TokenTransfer
 	ContractName string
+	// Meta is the token's app.ft_tokens (or, for NFTs, app.nft_collections)
+	// row when ListTokenTransfersWithContractFiltered found one.
+	Meta *TokenMetadataInfo
 }
 
 type FTVaultSummary struct {
@@ -118,8 +121,10 @@ func (r *Repository) ListTokenTransfersWithContractFiltered(ctx context.Context,
 				t.event_index,
 				t.timestamp,
 				t.timestamp AS created_at,
-				COALESCE(t.contract_name, '') AS contract_name
+				COALESCE(t.contract_name, '') AS contract_name,
+				`+transferTokenMetaColumns(isNFT)+`
 			FROM `+table+` t
+			`+transferTokenMetaJoin(isNFT)+`
 			`+where+`
 		ORDER BY t.block_height DESC, t.event_index DESC
 		LIMIT $`+fmt.Sprint(arg)+` OFFSET $`+fmt.Sprint(arg+1), listArgs...)
@@ -130,7 +135,11 @@ func (r *Repository) ListTokenTransfersWithContractFiltered(ctx context.Context,
 
 	var out []TokenTransferWithContract
 	for rows.Next() {
-		var t TokenTransferWithContract
+		var (
+			t       TokenTransferWithContract
+			hasMeta bool
+			meta    TokenMetadataInfo
+		)
 		if err := rows.Scan(
 			&t.TransactionID,
 			&t.BlockHeight,
@@ -143,10 +152,21 @@ func (r *Repository) ListTokenTransfersWithContractFiltered(ctx context.Context,
 			&t.Timestamp,
 			&t.CreatedAt,
 			&t.ContractName,
+			&hasMeta,
+			&meta.Name,
+			&meta.Symbol,
+			&meta.Decimals,
+			&meta.Logo,
+			&meta.Description,
+			&meta.MarketSymbol,
+			&meta.BannerImage,
 		); err != nil {
 			return nil, 0, err
 		}
 		t.IsNFT = isNFT
+		if hasMeta {
+			t.Meta = &meta
+		}
 		out = append(out, t)
 	}
 	if err := rows.Err(); err != nil {
@@ -155,6 +175,31 @@ func (r *Repository) ListTokenTransfersWithContractFiltered(ctx context.Context,
 	return out, total, nil
 }
 
+// transferTokenMetaJoin joins a transfer row t to its token's metadata row tk.
+// Both tables are keyed by (contract_address, contract_name), so it never
+// multiplies transfer rows.
+func transferTokenMetaJoin(isNFT bool) string {
+	if isNFT {
+		return "LEFT JOIN app.nft_collections tk ON tk.contract_address = t.token_contract_address AND tk.contract_name = t.contract_name"
+	}
+	return "LEFT JOIN app.ft_tokens tk ON tk.contract_address = t.token_contract_address AND tk.contract_name = t.contract_name"
+}
+
+// transferTokenMetaColumns selects the TokenMetadataInfo fields from the
+// transferTokenMetaJoin row, led by whether there was one.
+func transferTokenMetaColumns(isNFT bool) string {
+	if isNFT {
+		return `tk.contract_address IS NOT NULL,
+				COALESCE(tk.name, ''), COALESCE(tk.symbol, ''), 0,
+				COALESCE(tk.square_image::text, ''), COALESCE(tk.description, ''), '',
+				COALESCE(tk.banner_image::text, '')`
+	}
+	return `tk.contract_address IS NOT NULL,
+				COALESCE(tk.name, ''), COALESCE(tk.symbol, ''), COALESCE(tk.decimals, 0),
+				COALESCE(tk.logo::text, ''), COALESCE(tk.description, ''), COALESCE(tk.market_symbol, ''),
+				''`
+}
+
 func (r *Repository) ListNFTItemTransfers(ctx context.Context, tokenAddress, tokenName, tokenID string, limit, offset int) ([]TokenTransferWithContract, int64, error) {
 	clauses := []string{}
 	args := []interface{}{}
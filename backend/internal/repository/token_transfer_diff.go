@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"flowscan-clone/internal/models"
+)
+
+// TableDiff summarizes what an upsert of proposed rows would change in one table.
+type TableDiff struct {
+	Table     string `json:"table"`
+	Inserted  int    `json:"inserted"`
+	Updated   int    `json:"updated"`
+	Deleted   int    `json:"deleted"`
+	Unchanged int    `json:"unchanged"`
+	// Stale counts existing rows in the range that the processor no longer
+	// produces. Upserts leave them in place, so they are not in Deleted.
+	Stale   int         `json:"stale"`
+	Samples []RowChange `json:"samples,omitempty"`
+}
+
+// Add accumulates counts (and samples, up to sampleLimit) from other.
+func (d *TableDiff) Add(other TableDiff, sampleLimit int) {
+	d.Inserted += other.Inserted
+	d.Updated += other.Updated
+	d.Deleted += other.Deleted
+	d.Unchanged += other.Unchanged
+	d.Stale += other.Stale
+	for _, s := range other.Samples {
+		if len(d.Samples) >= sampleLimit {
+			break
+		}
+		d.Samples = append(d.Samples, s)
+	}
+}
+
+// RowChange is a sample row of a TableDiff. Op is "insert", "update" or "stale".
+type RowChange struct {
+	Op     string                `json:"op"`
+	Before *models.TokenTransfer `json:"before,omitempty"`
+	After  *models.TokenTransfer `json:"after,omitempty"`
+}
+
+// GetTokenTransfersInRange returns the stored FT or NFT transfers in [fromHeight, toHeight).
+func (r *Repository) GetTokenTransfersInRange(ctx context.Context, fromHeight, toHeight uint64, isNFT bool) ([]models.TokenTransfer, error) {
+	table, value := "app.ft_transfers", "COALESCE(amount::text, '')"
+	if isNFT {
+		table, value = "app.nft_transfers", "COALESCE(token_id, '')"
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT block_height, encode(transaction_id, 'hex'), event_index,
+		       COALESCE(encode(token_contract_address, 'hex'), ''), COALESCE(contract_name, ''),
+		       COALESCE(encode(from_address, 'hex'), ''), COALESCE(encode(to_address, 'hex'), ''),
+		       `+value+`, timestamp
+		FROM `+table+`
+		WHERE block_height >= $1 AND block_height < $2`,
+		fromHeight, toHeight)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var out []models.TokenTransfer
+	for rows.Next() {
+		t := models.TokenTransfer{IsNFT: isNFT}
+		var value string
+		if err := rows.Scan(&t.BlockHeight, &t.TransactionID, &t.EventIndex,
+			&t.TokenContractAddress, &t.ContractName, &t.FromAddress, &t.ToAddress, &value, &t.Timestamp); err != nil {
+			return nil, err
+		}
+		if isNFT {
+			t.TokenID, t.Amount = value, "1"
+		} else {
+			t.Amount = value
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// DiffTokenTransfers compares proposed transfers against what is stored for
+// [fromHeight, toHeight) without writing anything. The counts match what
+// UpsertFTTransfers / UpsertNFTTransfers would do with the same rows.
+func (r *Repository) DiffTokenTransfers(ctx context.Context, fromHeight, toHeight uint64, isNFT bool, proposed []models.TokenTransfer, sampleLimit int) (TableDiff, error) {
+	existing, err := r.GetTokenTransfersInRange(ctx, fromHeight, toHeight, isNFT)
+	if err != nil {
+		return TableDiff{}, err
+	}
+	return diffTokenTransfers(existing, proposed, isNFT, sampleLimit), nil
+}
+
+type transferRowKey struct {
+	height uint64
+	txID   string
+	index  int
+}
+
+// diffTokenTransfers applies the ON CONFLICT rules of the transfer upserts to
+// existing in memory and reports the outcome per row.
+func diffTokenTransfers(existing, proposed []models.TokenTransfer, isNFT bool, sampleLimit int) TableDiff {
+	diff := TableDiff{Table: "app.ft_transfers"}
+	if isNFT {
+		diff.Table = "app.nft_transfers"
+	}
+	sample := func(c RowChange) {
+		if len(diff.Samples) < sampleLimit {
+			diff.Samples = append(diff.Samples, c)
+		}
+	}
+
+	current := make(map[transferRowKey]models.TokenTransfer, len(existing))
+	for _, t := range existing {
+		current[transferKeyOf(t)] = t
+	}
+	touched := make(map[transferRowKey]bool, len(proposed))
+
+	for _, p := range proposed {
+		if p.IsNFT != isNFT {
+			continue
+		}
+		key := transferKeyOf(p)
+		// A key repeated within proposed upserts over the earlier row, which
+		// current already holds.
+		before, ok := current[key]
+		after := applyTransferUpsert(before, p, ok, isNFT)
+		touched[key] = true
+		switch {
+		case !ok:
+			diff.Inserted++
+			sample(RowChange{Op: "insert", After: &after})
+		case transferRowEqual(before, after, isNFT):
+			diff.Unchanged++
+		default:
+			diff.Updated++
+			b := before
+			sample(RowChange{Op: "update", Before: &b, After: &after})
+		}
+		current[key] = after
+	}
+
+	for _, t := range existing {
+		if !touched[transferKeyOf(t)] {
+			diff.Stale++
+			e := t
+			sample(RowChange{Op: "stale", Before: &e})
+		}
+	}
+	return diff
+}
+
+func transferKeyOf(t models.TokenTransfer) transferRowKey {
+	return transferRowKey{height: t.BlockHeight, txID: bytesToHex(hexToBytes(t.TransactionID)), index: t.EventIndex}
+}
+
+// applyTransferUpsert returns the row stored after upserting p over before
+// (exists=false means a plain insert), normalized the way it reads back.
+func applyTransferUpsert(before, p models.TokenTransfer, exists, isNFT bool) models.TokenTransfer {
+	after := models.TokenTransfer{
+		TransactionID:        bytesToHex(hexToBytes(p.TransactionID)),
+		BlockHeight:          p.BlockHeight,
+		EventIndex:           p.EventIndex,
+		TokenContractAddress: bytesToHex(hexToBytes(p.TokenContractAddress)),
+		ContractName:         p.ContractName,
+		FromAddress:          bytesToHex(hexToBytes(p.FromAddress)),
+		ToAddress:            bytesToHex(hexToBytes(p.ToAddress)),
+		Amount:               p.Amount,
+		TokenID:              p.TokenID,
+		IsNFT:                isNFT,
+		Timestamp:            p.Timestamp,
+	}
+	if isNFT {
+		after.Amount = "1"
+	}
+	if exists {
+		// timestamp is not part of the DO UPDATE SET list.
+		after.Timestamp = before.Timestamp
+		if after.ContractName == "" {
+			after.ContractName = before.ContractName
+		}
+	}
+	return after
+}
+
+func transferRowEqual(a, b models.TokenTransfer, isNFT bool) bool {
+	if a.TokenContractAddress != b.TokenContractAddress || a.ContractName != b.ContractName ||
+		a.FromAddress != b.FromAddress || a.ToAddress != b.ToAddress {
+		return false
+	}
+	if isNFT {
+		return a.TokenID == b.TokenID
+	}
+	return normalizeDecimalText(a.Amount) == normalizeDecimalText(b.Amount)
+}
+
+// normalizeDecimalText strips trailing fractional zeros so "1.5" and the
+// DECIMAL(78,18) text form "1.500000000000000000" compare equal.
+func normalizeDecimalText(v string) string {
+	v = strings.TrimSpace(v)
+	if strings.Contains(v, ".") {
+		v = strings.TrimRight(v, "0")
+		v = strings.TrimSuffix(v, ".")
+	}
+	return v
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+// fakeFTTable mimics app.ft_transfers and the UpsertFTTransfers ON CONFLICT
+// clause, storing amounts the way DECIMAL(78, 18) renders them.
+type fakeFTTable map[string]models.TokenTransfer
+
+func ftKey(t models.TokenTransfer) string {
+	return fmt.Sprintf("%d/%s/%d", t.BlockHeight, normalizeHex(t.TransactionID), t.EventIndex)
+}
+
+func decimal18(v string) string {
+	r, ok := new(big.Rat).SetString(v)
+	if !ok {
+		return v
+	}
+	return r.FloatString(18)
+}
+
+// upsert returns "insert", "update" or "noop" based on whether the stored row changed.
+func (f fakeFTTable) upsert(t models.TokenTransfer) string {
+	row := t
+	row.TransactionID = normalizeHex(t.TransactionID)
+	row.Amount = decimal18(t.Amount)
+	old, ok := f[ftKey(t)]
+	if !ok {
+		f[ftKey(t)] = row
+		return "insert"
+	}
+	if row.ContractName == "" {
+		row.ContractName = old.ContractName
+	}
+	row.Timestamp = old.Timestamp
+	f[ftKey(t)] = row
+	if row.TokenContractAddress == old.TokenContractAddress && row.ContractName == old.ContractName &&
+		row.FromAddress == old.FromAddress && row.ToAddress == old.ToAddress && row.Amount == old.Amount {
+		return "noop"
+	}
+	return "update"
+}
+
+func (f fakeFTTable) rows() []models.TokenTransfer {
+	out := make([]models.TokenTransfer, 0, len(f))
+	for _, t := range f {
+		out = append(out, t)
+	}
+	return out
+}
+
+func TestDiffTokenTransfersMatchesWrites(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	table := fakeFTTable{}
+	for _, tr := range []models.TokenTransfer{
+		{BlockHeight: 10, TransactionID: "aa01", EventIndex: 0, TokenContractAddress: "1654653399040a61", ContractName: "FlowToken", FromAddress: "01", ToAddress: "02", Amount: "1.5", Timestamp: ts},
+		{BlockHeight: 10, TransactionID: "aa01", EventIndex: 3, TokenContractAddress: "1654653399040a61", ContractName: "FlowToken", FromAddress: "02", ToAddress: "03", Amount: "2", Timestamp: ts},
+		{BlockHeight: 11, TransactionID: "bb02", EventIndex: 1, TokenContractAddress: "b19436aae4d94622", ContractName: "FiatToken", FromAddress: "04", ToAddress: "05", Amount: "10", Timestamp: ts},
+		{BlockHeight: 12, TransactionID: "cc03", EventIndex: 0, TokenContractAddress: "b19436aae4d94622", ContractName: "FiatToken", FromAddress: "06", Amount: "1", Timestamp: ts},
+	} {
+		table.upsert(tr)
+	}
+
+	proposed := []models.TokenTransfer{
+		// Same values, different formatting: unchanged.
+		{BlockHeight: 10, TransactionID: "0xAA01", EventIndex: 0, TokenContractAddress: "1654653399040a61", ContractName: "FlowToken", FromAddress: "01", ToAddress: "02", Amount: "1.50000000", Timestamp: ts},
+		// Recipient fixed: update.
+		{BlockHeight: 10, TransactionID: "aa01", EventIndex: 3, TokenContractAddress: "1654653399040a61", ContractName: "FlowToken", FromAddress: "02", ToAddress: "09", Amount: "2.0", Timestamp: ts},
+		// Empty contract name keeps the stored one: unchanged.
+		{BlockHeight: 11, TransactionID: "bb02", EventIndex: 1, TokenContractAddress: "b19436aae4d94622", FromAddress: "04", ToAddress: "05", Amount: "10", Timestamp: ts.Add(time.Hour)},
+		// New rows.
+		{BlockHeight: 12, TransactionID: "cc03", EventIndex: 2, TokenContractAddress: "b19436aae4d94622", ContractName: "FiatToken", ToAddress: "07", Amount: "3", Timestamp: ts},
+		{BlockHeight: 13, TransactionID: "dd04", EventIndex: 0, TokenContractAddress: "1654653399040a61", ContractName: "FlowToken", FromAddress: "08", ToAddress: "01", Amount: "0.1", Timestamp: ts},
+		// NFT rows are diffed separately and ignored here.
+		{BlockHeight: 13, TransactionID: "dd04", EventIndex: 1, IsNFT: true, TokenID: "5", ToAddress: "01", Timestamp: ts},
+	}
+
+	diff := diffTokenTransfers(table.rows(), proposed, false, 10)
+
+	writes := map[string]int{}
+	for _, p := range proposed {
+		if !p.IsNFT {
+			writes[table.upsert(p)]++
+		}
+	}
+
+	if diff.Inserted != writes["insert"] || diff.Updated != writes["update"] || diff.Unchanged != writes["noop"] {
+		t.Fatalf("dry run %+v does not match writes %v", diff, writes)
+	}
+	if diff.Inserted != 2 || diff.Updated != 1 || diff.Unchanged != 2 {
+		t.Fatalf("unexpected counts: %+v", diff)
+	}
+	// cc03/0 exists but is not re-derived.
+	if diff.Stale != 1 || diff.Deleted != 0 {
+		t.Fatalf("stale=%d deleted=%d, want 1/0", diff.Stale, diff.Deleted)
+	}
+	if len(diff.Samples) != 4 {
+		t.Fatalf("expected 4 samples (2 insert, 1 update, 1 stale), got %+v", diff.Samples)
+	}
+
+	// Running the same rows again is a no-op.
+	again := diffTokenTransfers(table.rows(), proposed, false, 10)
+	if again.Inserted != 0 || again.Updated != 0 || again.Unchanged != 5 {
+		t.Fatalf("second pass should be unchanged: %+v", again)
+	}
+}
+
+func TestDiffTokenTransfersSampleLimit(t *testing.T) {
+	t.Parallel()
+
+	var proposed []models.TokenTransfer
+	for i := 0; i < 5; i++ {
+		proposed = append(proposed, models.TokenTransfer{BlockHeight: 1, TransactionID: "aa", EventIndex: i, IsNFT: true, TokenID: "1"})
+	}
+	diff := diffTokenTransfers(nil, proposed, true, 2)
+	if diff.Table != "app.nft_transfers" || diff.Inserted != 5 || len(diff.Samples) != 2 {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+
+	var total TableDiff
+	total.Add(diff, 3)
+	total.Add(diff, 3)
+	if total.Inserted != 10 || len(total.Samples) != 3 {
+		t.Fatalf("unexpected total: %+v", total)
+	}
+}
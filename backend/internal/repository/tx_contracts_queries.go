@@ -54,7 +54,9 @@ func (r *Repository) GetTxScriptHashesInRange(ctx context.Context, fromHeight, t
 }
 
 // GetScriptTextsByHashes fetches script texts for a set of script_hash values.
-// Returns a map of script_hash -> script_text.
+// Returns a map of script_hash -> script_text. Scripts stored by hash only
+// (TX_SCRIPT_STORE_MAX_BYTES) are loaded from the chain; one that cannot be
+// loaded is an error.
 func (r *Repository) GetScriptTextsByHashes(ctx context.Context, hashes []string) (map[string]string, error) {
 	if len(hashes) == 0 {
 		return nil, nil
@@ -76,7 +78,13 @@ func (r *Repository) GetScriptTextsByHashes(ctx context.Context, hashes []string
 		}
 		out[hash] = text
 	}
-	return out, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.fillMissingScripts(ctx, hashes, out); err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
 // GetEventTypesInRange fetches only (transaction_id, type) from raw events,
@@ -153,7 +161,8 @@ func (r *Repository) GetEVMEventsInRange(ctx context.Context, fromHeight, toHeig
 			payload,
 			COALESCE(encode(contract_address, 'hex'), '') AS contract_address,
 			event_name,
-			timestamp
+			timestamp,
+			payload_compressed
 		FROM raw.events
 		WHERE block_height >= $1 AND block_height < $2
 		  AND type LIKE '%EVM.TransactionExecuted%'
@@ -168,7 +177,11 @@ func (r *Repository) GetEVMEventsInRange(ctx context.Context, fromHeight, toHeig
 	var events []models.Event
 	for rows.Next() {
 		var e models.Event
-		if err := rows.Scan(&e.BlockHeight, &e.TransactionID, &e.EventIndex, &e.TransactionIndex, &e.Type, &e.Payload, &e.ContractAddress, &e.EventName, &e.Timestamp); err != nil {
+		var compressed []byte
+		if err := rows.Scan(&e.BlockHeight, &e.TransactionID, &e.EventIndex, &e.TransactionIndex, &e.Type, &e.Payload, &e.ContractAddress, &e.EventName, &e.Timestamp, &compressed); err != nil {
+			return nil, err
+		}
+		if err := restoreEventPayload(&e, compressed); err != nil {
 			return nil, err
 		}
 		events = append(events, e)
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"flowscan-clone/internal/contractid"
+)
+
+// TxImports is what a transaction's script imports, as stored: the script body
+// and the contracts tx_contracts_worker recorded for it.
+type TxImports struct {
+	BlockHeight uint64
+	Script      string
+	Contracts   []string // A.<address>.<name>, ordered
+}
+
+// GetTransactionImports loads a transaction's script and recorded contract
+// imports. Returns ErrNotFound when the transaction is not indexed.
+func (r *Repository) GetTransactionImports(ctx context.Context, txID string) (*TxImports, error) {
+	txBytes := hexToBytes(txID)
+	if txBytes == nil {
+		return nil, fmt.Errorf("transaction %s: %w", txID, ErrNotFound)
+	}
+	out := &TxImports{}
+	if err := r.queryRow(ctx, "tx_lookup", "SELECT block_height FROM raw.tx_lookup WHERE id = $1", txBytes).Scan(&out.BlockHeight); err != nil {
+		return nil, wrapDBErr(err, "transaction "+txID)
+	}
+	err := r.queryRow(ctx, "tx_script", `
+		SELECT COALESCE(t.script, s.script_text, '')
+		FROM raw.transactions t
+		LEFT JOIN raw.scripts s ON s.script_hash = t.script_hash
+		WHERE t.id = $1 AND t.block_height = $2`, txBytes, out.BlockHeight).Scan(&out.Script)
+	if err != nil {
+		return nil, wrapDBErr(err, "transaction "+txID)
+	}
+
+	rows, err := r.query(ctx, "tx_contract_imports", `
+		SELECT contract_identifier
+		FROM app.tx_contracts
+		WHERE transaction_id = $1
+		ORDER BY contract_identifier`, txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s imports: %w", txID, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out.Contracts = append(out.Contracts, id)
+	}
+	return out, rows.Err()
+}
+
+// GetContractCodes returns the stored code of each contract in ids
+// (A.<address>.<name>), keyed by canonical identifier. Contracts that are not
+// indexed are absent from the result.
+func (r *Repository) GetContractCodes(ctx context.Context, ids []string) (map[string]string, error) {
+	addrs := make([]string, 0, len(ids))
+	names := make([]string, 0, len(ids))
+	for _, s := range ids {
+		id := contractid.Parse(s)
+		if id.Address == "" || id.Name == "" {
+			continue
+		}
+		addrs = append(addrs, id.Address)
+		names = append(names, id.Name)
+	}
+	out := make(map[string]string, len(addrs))
+	if len(addrs) == 0 {
+		return out, nil
+	}
+	rows, err := r.query(ctx, "contract_codes", `
+		SELECT 'A.' || encode(c.address, 'hex') || '.' || c.name, COALESCE(c.code, '')
+		FROM app.smart_contracts c
+		JOIN unnest($1::text[], $2::text[]) AS u(address, name)
+		  ON c.address = decode(u.address, 'hex') AND c.name = u.name`, addrs, names)
+	if err != nil {
+		return nil, fmt.Errorf("contract codes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, code string
+		if err := rows.Scan(&id, &code); err != nil {
+			return nil, err
+		}
+		out[id] = code
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"flowscan-clone/internal/models"
+)
+
+// TestGetEventsByTransactionIDPage needs a database with the schema applied.
+// It stores a transaction with 250 events and checks GetTransactionByID only
+// loads the first DefaultTxEventsLimit of them and the pages add up.
+func TestGetEventsByTransactionIDPage(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	repo, err := NewRepository(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	ctx := context.Background()
+
+	const height, eventCount = 1_999_999_002, 250
+	cleanup := func() {
+		for _, q := range []string{
+			`DELETE FROM raw.events WHERE block_height = $1`,
+			`DELETE FROM raw.transactions WHERE block_height = $1`,
+			`DELETE FROM raw.tx_lookup WHERE block_height = $1`,
+			`DELETE FROM raw.block_lookup WHERE height = $1`,
+			`DELETE FROM raw.blocks WHERE height = $1`,
+		} {
+			if _, err := repo.db.Exec(ctx, q, height); err != nil {
+				t.Logf("cleanup: %v", err)
+			}
+		}
+		repo.db.Exec(ctx, `DELETE FROM app.indexing_checkpoints WHERE service_name = 'test_tx_events_page'`)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	ts := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	txID := fmt.Sprintf("%064x", 0xe7e7)
+	tx := models.Transaction{
+		ID: txID, BlockHeight: height, Timestamp: ts, Status: "SEALED",
+		PayerAddress: "1654653399040a61", ProposerAddress: "1654653399040a61", Authorizers: []string{"1654653399040a61"},
+	}
+	var events []models.Event
+	for i := 0; i < eventCount; i++ {
+		events = append(events, models.Event{
+			TransactionID: txID, EventIndex: i, BlockHeight: height, Timestamp: ts,
+			Type: "A.1654653399040a61.FlowToken.TokensDeposited", EventName: "TokensDeposited", Payload: []byte(`{"amount":"1.0"}`),
+		})
+	}
+	block := &models.Block{Height: height, ID: fmt.Sprintf("%064x", 0xb10d), Timestamp: ts, TxCount: 1, EventCount: eventCount}
+	if err := repo.SaveBatch(ctx, []*models.Block{block}, []models.Transaction{tx}, events, "test_tx_events_page", height); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.GetTransactionByID(ctx, txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Events) != DefaultTxEventsLimit {
+		t.Fatalf("GetTransactionByID loaded %d events; want the %d cap", len(got.Events), DefaultTxEventsLimit)
+	}
+
+	seen := 0
+	for offset := 0; ; offset += DefaultTxEventsLimit {
+		page, err := repo.GetEventsByTransactionIDPage(ctx, txID, DefaultTxEventsLimit, offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != eventCount {
+			t.Fatalf("offset %d: total = %d; want %d", offset, page.Total, eventCount)
+		}
+		for i, e := range page.Events {
+			if e.EventIndex != offset+i {
+				t.Fatalf("offset %d: event %d has index %d", offset, i, e.EventIndex)
+			}
+		}
+		seen += len(page.Events)
+		if wantMore := seen < eventCount; page.HasMore != wantMore {
+			t.Fatalf("offset %d: has_more = %v after %d events", offset, page.HasMore, seen)
+		}
+		if !page.HasMore {
+			break
+		}
+	}
+	if seen != eventCount {
+		t.Fatalf("pages returned %d events; want %d", seen, eventCount)
+	}
+
+	past, err := repo.GetEventsByTransactionIDPage(ctx, txID, 10, eventCount)
+	if err != nil || len(past.Events) != 0 || past.HasMore || past.Total != eventCount {
+		t.Fatalf("page past the end = %+v, %v", past, err)
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"math/big"
 	"strconv"
 	"strings"
 	"time"
 
+	"flowscan-clone/internal/ufix64"
+
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type TxMetricsBackfillConfig struct {
@@ -102,7 +106,7 @@ type txMetricKey struct {
 type txMetric struct {
 	Count           int
 	GasUsed         uint64
-	FeeAmount       float64
+	FeeAmount       *big.Int // 1e-8 FLOW units, summed exactly
 	InclusionEffort float64
 	ExecutionEffort float64
 }
@@ -136,7 +140,7 @@ func (r *Repository) loadTxMetrics(ctx context.Context, from, to int64) (map[txM
 
 	// Step 2: fee data — only fetch payload for fee events (~14% of rows, ~5% of payload bytes).
 	feeRows, err := r.db.Query(ctx, `
-		SELECT block_height, encode(transaction_id, 'hex') AS transaction_id, payload
+		SELECT block_height, encode(transaction_id, 'hex') AS transaction_id, payload, payload_compressed
 		FROM raw.events
 		WHERE block_height BETWEEN $1 AND $2
 		  AND LOWER(type) LIKE '%flowfees.feesdeducted%'`, from, to)
@@ -148,16 +152,24 @@ func (r *Repository) loadTxMetrics(ctx context.Context, from, to int64) (map[txM
 	for feeRows.Next() {
 		var height int64
 		var txID string
-		var payload []byte
-		if err := feeRows.Scan(&height, &txID, &payload); err != nil {
+		var payload, compressed []byte
+		if err := feeRows.Scan(&height, &txID, &payload, &compressed); err != nil {
 			return nil, err
 		}
+		if len(compressed) > 0 {
+			if payload, err = decodeEventPayload(compressed); err != nil {
+				return nil, err
+			}
+		}
 		key := txMetricKey{Height: height, ID: txID}
 		m := metrics[key]
 		var obj interface{}
 		if err := json.Unmarshal(payload, &obj); err == nil {
-			if fee, ok := findNumericField(obj, map[string]bool{"amount": true}); ok {
-				m.FeeAmount += fee
+			if fee, ok := feeAmount(obj); ok {
+				if m.FeeAmount == nil {
+					m.FeeAmount = new(big.Int)
+				}
+				m.FeeAmount.Add(m.FeeAmount, fee)
 			}
 			if inc, ok := findNumericField(obj, map[string]bool{"inclusioneffort": true}); ok {
 				m.InclusionEffort = inc
@@ -211,7 +223,8 @@ func (r *Repository) applyTxMetrics(ctx context.Context, metrics map[txMetricKey
 
 	rows := make([][]interface{}, 0, len(metrics))
 	for k, m := range metrics {
-		rows = append(rows, []interface{}{k.Height, hexToBytes(k.ID), m.Count, int64(m.GasUsed), m.FeeAmount, m.FeeAmount, m.InclusionEffort, m.ExecutionEffort})
+		fee := feeNumeric(m.FeeAmount)
+		rows = append(rows, []interface{}{k.Height, hexToBytes(k.ID), m.Count, int64(m.GasUsed), fee, fee, m.InclusionEffort, m.ExecutionEffort})
 	}
 
 	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"tmp_tx_metrics"}, []string{"block_height", "transaction_id", "event_count", "gas_used", "fee", "fee_amount", "inclusion_effort", "execution_effort"}, pgx.CopyFromRows(rows)); err != nil {
@@ -255,20 +268,33 @@ func executionEffortToGas(executionEffort float64) uint64 {
 	return uint64(gas + 0.5)
 }
 
-func parseFeeAmount(payload []byte) (float64, bool) {
+func parseFeeAmount(payload []byte) (*big.Int, bool) {
 	var obj interface{}
 	if err := json.Unmarshal(payload, &obj); err != nil {
-		return 0, false
+		return nil, false
 	}
+	return feeAmount(obj)
+}
+
+// feeAmount returns the exact amount of a decoded FeesDeducted payload.
+func feeAmount(obj interface{}) (*big.Int, bool) {
 	raw, ok := extractAmount(obj)
 	if !ok || raw == "" {
-		return 0, false
+		return nil, false
 	}
-	f, err := strconv.ParseFloat(raw, 64)
+	v, err := ufix64.Parse(raw)
 	if err != nil {
-		return 0, false
+		return nil, false
+	}
+	return v, true
+}
+
+// feeNumeric converts a fee in 1e-8 units to an exact NUMERIC; nil is 0.
+func feeNumeric(v *big.Int) pgtype.Numeric {
+	if v == nil {
+		return pgtype.Numeric{Int: new(big.Int), Valid: true}
 	}
-	return f, true
+	return pgtype.Numeric{Int: v, Exp: -ufix64.Decimals, Valid: true}
 }
 
 func extractAmount(v interface{}) (string, bool) {
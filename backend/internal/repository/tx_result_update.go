@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// UpdateTransactionResult overwrites the execution outcome of an indexed
+// transaction, e.g. after re-checking a tip transaction against the access
+// node. The gas in app.tx_metrics, which reads prefer, is kept in step. It
+// returns ErrNotFound when the transaction row does not exist.
+func (r *Repository) UpdateTransactionResult(ctx context.Context, id string, blockHeight uint64, status, errorMessage string, gasUsed uint64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("update transaction result %s: %w", id, err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE raw.transactions
+		SET status = $3, error_message = NULLIF($4, ''), gas_used = $5
+		WHERE id = $1 AND block_height = $2`,
+		hexToBytes(id), blockHeight, status, errorMessage, int64(gasUsed))
+	if err != nil {
+		return fmt.Errorf("update transaction result %s: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("update transaction result %s: %w", id, ErrNotFound)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE app.tx_metrics
+		SET gas_used = $3, updated_at = NOW()
+		WHERE transaction_id = $1 AND block_height = $2`,
+		hexToBytes(id), blockHeight, int64(gasUsed)); err != nil {
+		return fmt.Errorf("update transaction metrics %s: %w", id, err)
+	}
+	return tx.Commit(ctx)
+}
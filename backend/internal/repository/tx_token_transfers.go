@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// TxTokenTransfer is one FT or NFT movement within a transaction. Amount is
+// set for FT transfers, TokenID for NFT transfers.
+type TxTokenTransfer struct {
+	Type         string `json:"type"` // "ft" or "nft"
+	Token        string `json:"token"`
+	ContractName string `json:"contract_name"`
+	FromAddress  string `json:"from_address"`
+	ToAddress    string `json:"to_address"`
+	Amount       string `json:"amount,omitempty"`
+	TokenID      string `json:"token_id,omitempty"`
+	EventIndex   int    `json:"event_index"`
+}
+
+// mergeTxTokenTransfers combines a transaction's FT and NFT transfers in event
+// order.
+func mergeTxTokenTransfers(ft []FTTransferRow, nft []NFTTransferRow) []TxTokenTransfer {
+	out := make([]TxTokenTransfer, 0, len(ft)+len(nft))
+	for _, t := range ft {
+		out = append(out, TxTokenTransfer{
+			Type:         "ft",
+			Token:        t.Token,
+			ContractName: t.ContractName,
+			FromAddress:  t.FromAddress,
+			ToAddress:    t.ToAddress,
+			Amount:       t.Amount,
+			EventIndex:   t.EventIndex,
+		})
+	}
+	for _, t := range nft {
+		out = append(out, TxTokenTransfer{
+			Type:         "nft",
+			Token:        t.Token,
+			ContractName: t.ContractName,
+			FromAddress:  t.FromAddress,
+			ToAddress:    t.ToAddress,
+			TokenID:      t.TokenID,
+			EventIndex:   t.EventIndex,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].EventIndex < out[j].EventIndex })
+	return out
+}
+
+// GetTokenTransfersByTransaction returns every FT and NFT transfer in txID,
+// ordered by event index, along with the transaction's block height. The height
+// is resolved via raw.tx_lookup so both transfer queries prune to a single
+// partition. Returns ErrNotFound when the transaction is not indexed.
+func (r *Repository) GetTokenTransfersByTransaction(ctx context.Context, txID string) (uint64, []TxTokenTransfer, error) {
+	txBytes := hexToBytes(txID)
+	if txBytes == nil {
+		return 0, nil, fmt.Errorf("transaction %s: %w", txID, ErrNotFound)
+	}
+	var height uint64
+	if err := r.queryRow(ctx, "tx_lookup", "SELECT block_height FROM raw.tx_lookup WHERE id = $1", txBytes).Scan(&height); err != nil {
+		return 0, nil, wrapDBErr(err, "transaction "+txID)
+	}
+
+	rows, err := r.query(ctx, "tx_ft_transfers", `
+		SELECT COALESCE('A.' || encode(token_contract_address, 'hex') || '.' || NULLIF(contract_name, ''), encode(token_contract_address, 'hex')) AS token,
+		       COALESCE(contract_name, '') AS contract_name,
+		       COALESCE(encode(from_address, 'hex'), '') AS from_address,
+		       COALESCE(encode(to_address, 'hex'), '') AS to_address,
+		       COALESCE(amount::text, '0') AS amount,
+		       event_index
+		FROM app.ft_transfers
+		WHERE transaction_id = $1 AND block_height = $2
+		  AND contract_name NOT IN ('FungibleToken', 'NonFungibleToken')
+		ORDER BY event_index`, txBytes, height)
+	if err != nil {
+		return 0, nil, fmt.Errorf("get ft transfers by tx: %w", err)
+	}
+	var ft []FTTransferRow
+	for rows.Next() {
+		var t FTTransferRow
+		if err := rows.Scan(&t.Token, &t.ContractName, &t.FromAddress, &t.ToAddress, &t.Amount, &t.EventIndex); err != nil {
+			rows.Close()
+			return 0, nil, err
+		}
+		ft = append(ft, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	rows, err = r.query(ctx, "tx_nft_transfers", `
+		SELECT COALESCE('A.' || encode(token_contract_address, 'hex') || '.' || NULLIF(contract_name, ''), encode(token_contract_address, 'hex')) AS token,
+		       COALESCE(contract_name, '') AS contract_name,
+		       COALESCE(encode(from_address, 'hex'), '') AS from_address,
+		       COALESCE(encode(to_address, 'hex'), '') AS to_address,
+		       COALESCE(token_id, '') AS token_id,
+		       event_index
+		FROM app.nft_transfers
+		WHERE transaction_id = $1 AND block_height = $2
+		  AND contract_name NOT IN ('FungibleToken', 'NonFungibleToken')
+		ORDER BY event_index`, txBytes, height)
+	if err != nil {
+		return 0, nil, fmt.Errorf("get nft transfers by tx: %w", err)
+	}
+	defer rows.Close()
+	var nft []NFTTransferRow
+	for rows.Next() {
+		var t NFTTransferRow
+		if err := rows.Scan(&t.Token, &t.ContractName, &t.FromAddress, &t.ToAddress, &t.TokenID, &t.EventIndex); err != nil {
+			return 0, nil, err
+		}
+		nft = append(nft, t)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+	return height, mergeTxTokenTransfers(ft, nft), nil
+}
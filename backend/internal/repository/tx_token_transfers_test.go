@@ -0,0 +1,39 @@
+package repository
+
+import "testing"
+
+func TestMergeTxTokenTransfersInterleavesByEventIndex(t *testing.T) {
+	t.Parallel()
+
+	ft := []FTTransferRow{
+		{Token: "A.1654653399040a61.FlowToken", ContractName: "FlowToken", FromAddress: "aa", ToAddress: "bb", Amount: "1.5", EventIndex: 1},
+		{Token: "A.b19436aae4d94622.FiatToken", ContractName: "FiatToken", FromAddress: "bb", ToAddress: "cc", Amount: "20", EventIndex: 6},
+		{Token: "A.1654653399040a61.FlowToken", ContractName: "FlowToken", FromAddress: "aa", ToAddress: "f919ee77447b7497", Amount: "0.001", EventIndex: 9},
+	}
+	nft := []NFTTransferRow{
+		{Token: "A.0b2a3299cc857e29.TopShot", ContractName: "TopShot", FromAddress: "bb", ToAddress: "aa", TokenID: "42", EventIndex: 3},
+		{Token: "A.0b2a3299cc857e29.TopShot", ContractName: "TopShot", FromAddress: "bb", ToAddress: "aa", TokenID: "43", EventIndex: 5},
+	}
+
+	got := mergeTxTokenTransfers(ft, nft)
+	if len(got) != 5 {
+		t.Fatalf("expected 5 transfers, got %d", len(got))
+	}
+	wantTypes := []string{"ft", "nft", "nft", "ft", "ft"}
+	wantIdx := []int{1, 3, 5, 6, 9}
+	for i := range got {
+		if got[i].Type != wantTypes[i] || got[i].EventIndex != wantIdx[i] {
+			t.Fatalf("position %d = %+v, want %s at event %d", i, got[i], wantTypes[i], wantIdx[i])
+		}
+	}
+	if got[0].Amount != "1.5" || got[0].TokenID != "" {
+		t.Fatalf("ft transfer = %+v", got[0])
+	}
+	if got[2].TokenID != "43" || got[2].Amount != "" {
+		t.Fatalf("nft transfer = %+v", got[2])
+	}
+
+	if empty := mergeTxTokenTransfers(nil, nil); len(empty) != 0 {
+		t.Fatalf("expected no transfers, got %+v", empty)
+	}
+}
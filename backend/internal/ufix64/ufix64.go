@@ -0,0 +1,77 @@
+// Package ufix64 parses and formats Cadence fixed-point amounts exactly.
+//
+// UFix64 and Fix64 values carry 8 decimal places. Parsing them into float64
+// rounds anything above 2^53 raw units (about 90 million FLOW), so totals such
+// as token supplies and summed fees came out wrong. Here an amount is held as
+// a *big.Int of 1e-8 units, which also lets sums grow past the UFix64 maximum
+// (184467440737.09551615) without overflowing.
+package ufix64
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimals is the number of fractional digits of a UFix64.
+const Decimals = 8
+
+var scale = big.NewInt(100_000_000)
+
+// Parse returns s in 1e-8 units. It accepts plain decimals ("12", "0.5",
+// "-3.25") and exponent forms produced by float formatting ("1e-05"), and
+// fails for anything with more than 8 significant decimals.
+func Parse(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s, "/_") {
+		return nil, fmt.Errorf("ufix64: invalid amount %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("ufix64: invalid amount %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(scale))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("ufix64: %q has more than %d decimals", s, Decimals)
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// Format renders v (in 1e-8 units) the way Cadence prints UFix64 values:
+// always 8 decimals, e.g. "1.50000000".
+func Format(v *big.Int) string {
+	if v == nil {
+		return "0.00000000"
+	}
+	q, m := new(big.Int).QuoRem(new(big.Int).Abs(v), scale, new(big.Int))
+	sign := ""
+	if v.Sign() < 0 {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%s.%08s", sign, q.String(), m.String())
+}
+
+// Normalize re-formats s exactly, reporting false when it is not a valid
+// amount.
+func Normalize(s string) (string, bool) {
+	v, err := Parse(s)
+	if err != nil {
+		return "", false
+	}
+	return Format(v), true
+}
+
+// Sum adds amounts exactly. Unparseable values are skipped and counted in
+// skipped so callers can decide whether a partial total is acceptable.
+func Sum(values ...string) (total *big.Int, skipped int) {
+	total = new(big.Int)
+	for _, s := range values {
+		v, err := Parse(s)
+		if err != nil {
+			skipped++
+			continue
+		}
+		total.Add(total, v)
+	}
+	return total, skipped
+}
@@ -0,0 +1,66 @@
+package ufix64
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+const maxUFix64 = "184467440737.09551615"
+
+func TestMaxUFix64RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	v, err := Parse(maxUFix64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := new(big.Int).SetUint64(math.MaxUint64); v.Cmp(want) != 0 {
+		t.Fatalf("Parse(%s) = %s; want %s", maxUFix64, v, want)
+	}
+	if got := Format(v); got != maxUFix64 {
+		t.Fatalf("Format = %s; want %s", got, maxUFix64)
+	}
+	// float64 cannot hold it; this is the display bug being fixed.
+	f, _ := strconv.ParseFloat(maxUFix64, 64)
+	if strconv.FormatFloat(f, 'f', 8, 64) == maxUFix64 {
+		t.Fatal("float64 unexpectedly round-tripped the max UFix64")
+	}
+}
+
+func TestSumPastUFix64Max(t *testing.T) {
+	t.Parallel()
+
+	total, skipped := Sum(maxUFix64, maxUFix64, "0.00000002", "bogus")
+	if skipped != 1 {
+		t.Fatalf("skipped = %d; want 1", skipped)
+	}
+	if got := Format(total); got != "368934881474.19103232" {
+		t.Fatalf("sum = %s", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		in, want string
+		ok       bool
+	}{
+		{"1", "1.00000000", true},
+		{"0.5", "0.50000000", true},
+		{" 12.34500000 ", "12.34500000", true},
+		{"1e-05", "0.00001000", true},
+		{"-3.25", "-3.25000000", true},
+		{"0.000000001", "", false},
+		{"1/3", "", false},
+		{"", "", false},
+		{"abc", "", false},
+	} {
+		got, ok := Normalize(tc.in)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("Normalize(%q) = %q, %v; want %q, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
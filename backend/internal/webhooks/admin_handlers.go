@@ -29,6 +29,10 @@ func (ah *AdminHandlers) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/webhook/users/{id}/subscriptions", ah.handleListUserSubscriptions).Methods("GET", "OPTIONS")
 	r.HandleFunc("/webhook/users/{id}/logs", ah.handleListUserLogs).Methods("GET", "OPTIONS")
 	r.HandleFunc("/webhook/stats", ah.handleGlobalStats).Methods("GET", "OPTIONS")
+	r.HandleFunc("/webhook/endpoints", ah.handleListEndpointHealth).Methods("GET", "OPTIONS")
+	r.HandleFunc("/webhook/endpoints/{id}", ah.handleSetEndpointActive).Methods("PATCH", "OPTIONS")
+	r.HandleFunc("/webhook/dead-letters", ah.handleListDeadLetters).Methods("GET", "OPTIONS")
+	r.HandleFunc("/webhook/dead-letters/{id}", ah.handleDeleteDeadLetter).Methods("DELETE", "OPTIONS")
 }
 
 // --- Handlers ---
@@ -174,3 +178,87 @@ func (ah *AdminHandlers) handleGlobalStats(w http.ResponseWriter, r *http.Reques
 
 	writeJSON(w, http.StatusOK, stats)
 }
+
+// handleListEndpointHealth lists endpoints with their delivery failure state.
+// ?failing=true limits it to failing or disabled endpoints.
+func (ah *AdminHandlers) handleListEndpointHealth(w http.ResponseWriter, r *http.Request) {
+	limit, offset := parsePagination(r)
+	failingOnly := r.URL.Query().Get("failing") == "true"
+
+	eps, err := ah.store.ListEndpointHealth(r.Context(), failingOnly, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list endpoints")
+		return
+	}
+	if eps == nil {
+		eps = []EndpointHealth{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"items": eps,
+		"count": len(eps),
+	})
+}
+
+// handleSetEndpointActive re-enables (clearing its failure count) or disables an endpoint.
+func (ah *AdminHandlers) handleSetEndpointActive(w http.ResponseWriter, r *http.Request) {
+	endpointID := mux.Vars(r)["id"]
+
+	var body struct {
+		IsActive *bool  `json:"is_active"`
+		Reason   string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if body.IsActive == nil {
+		writeError(w, http.StatusBadRequest, "is_active is required")
+		return
+	}
+
+	if err := ah.store.SetEndpointActive(r.Context(), endpointID, *body.IsActive, body.Reason); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to update endpoint")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"updated":     true,
+		"endpoint_id": endpointID,
+		"is_active":   *body.IsActive,
+	})
+}
+
+// handleListDeadLetters lists deliveries that failed after all retries,
+// optionally for one ?endpoint_id=.
+func (ah *AdminHandlers) handleListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	limit, offset := parsePagination(r)
+
+	dls, err := ah.store.ListDeadLetters(r.Context(), r.URL.Query().Get("endpoint_id"), limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list dead letters")
+		return
+	}
+	if dls == nil {
+		dls = []DeadLetter{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"items": dls,
+		"count": len(dls),
+	})
+}
+
+func (ah *AdminHandlers) handleDeleteDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := ah.store.DeleteDeadLetter(r.Context(), id); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to delete dead letter")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"deleted": true,
+		"id":      id,
+	})
+}
@@ -0,0 +1,83 @@
+package webhooks
+
+import (
+	"context"
+	"sync"
+
+	"flowscan-clone/internal/eventbus"
+)
+
+// deliveryJob is one event bound for one subscription's endpoint.
+type deliveryJob struct {
+	sub     Subscription
+	evt     eventbus.Event
+	attempt int // 1-based
+}
+
+// deliveryQueue runs deliveries off the Orchestrator loop. Each endpoint has
+// its own FIFO drained by one goroutine at a time, so a slow endpoint only
+// delays its own events; at most `workers` deliveries are in flight overall.
+type deliveryQueue struct {
+	mu       sync.Mutex
+	pending  map[string][]deliveryJob // endpoint id -> queued jobs; present while draining
+	slots    chan struct{}
+	maxQueue int
+	run      func(context.Context, deliveryJob)
+}
+
+func newDeliveryQueue(workers, maxQueue int, run func(context.Context, deliveryJob)) *deliveryQueue {
+	if workers < 1 {
+		workers = 1
+	}
+	if maxQueue < 1 {
+		maxQueue = 1
+	}
+	return &deliveryQueue{
+		pending:  make(map[string][]deliveryJob),
+		slots:    make(chan struct{}, workers),
+		maxQueue: maxQueue,
+		run:      run,
+	}
+}
+
+// push queues job behind its endpoint's earlier jobs. It reports false when
+// that endpoint already has maxQueue jobs waiting.
+func (q *deliveryQueue) push(ctx context.Context, job deliveryJob) bool {
+	ep := job.sub.EndpointID
+	q.mu.Lock()
+	jobs, draining := q.pending[ep]
+	if len(jobs) >= q.maxQueue {
+		q.mu.Unlock()
+		return false
+	}
+	q.pending[ep] = append(jobs, job)
+	q.mu.Unlock()
+	if !draining {
+		go q.drain(ctx, ep)
+	}
+	return true
+}
+
+// drain delivers ep's jobs in order and exits once its queue is empty.
+func (q *deliveryQueue) drain(ctx context.Context, ep string) {
+	for {
+		q.mu.Lock()
+		jobs := q.pending[ep]
+		if len(jobs) == 0 || ctx.Err() != nil {
+			delete(q.pending, ep)
+			q.mu.Unlock()
+			return
+		}
+		job := jobs[0]
+		q.pending[ep] = jobs[1:]
+		q.mu.Unlock()
+
+		select {
+		case q.slots <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		q.run(ctx, job)
+		<-q.slots
+	}
+}
@@ -0,0 +1,60 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDeliveryQueueIsolatesSlowEndpoints(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	delivered := make(chan deliveryJob, 16)
+	q := newDeliveryQueue(2, 3, func(_ context.Context, job deliveryJob) {
+		if job.sub.EndpointID == "slow" && job.attempt == 1 {
+			started <- struct{}{}
+			<-release
+		}
+		delivered <- job
+	})
+	job := func(ep string, n int) deliveryJob {
+		return deliveryJob{sub: Subscription{EndpointID: ep}, attempt: n}
+	}
+
+	if !q.push(ctx, job("slow", 1)) {
+		t.Fatal("push to an empty queue failed")
+	}
+	<-started
+	// The slow endpoint holds one slot; its backlog is capped at maxQueue.
+	for i := 2; i <= 4; i++ {
+		if !q.push(ctx, job("slow", i)) {
+			t.Fatalf("push slow %d within maxQueue failed", i)
+		}
+	}
+	if q.push(ctx, job("slow", 5)) {
+		t.Fatal("push past maxQueue succeeded")
+	}
+
+	// Other endpoints are delivered, in order, while it is stuck.
+	for i := 1; i <= 3; i++ {
+		if !q.push(ctx, job("fast", i)) {
+			t.Fatalf("push fast %d failed", i)
+		}
+	}
+	for i := 1; i <= 3; i++ {
+		if got := <-delivered; got.sub.EndpointID != "fast" || got.attempt != i {
+			t.Fatalf("delivered %s/%d; want fast/%d", got.sub.EndpointID, got.attempt, i)
+		}
+	}
+
+	close(release)
+	for i := 1; i <= 4; i++ {
+		if got := <-delivered; got.sub.EndpointID != "slow" || got.attempt != i {
+			t.Fatalf("delivered %s/%d; want slow/%d", got.sub.EndpointID, got.attempt, i)
+		}
+	}
+}
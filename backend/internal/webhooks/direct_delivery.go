@@ -8,14 +8,18 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // DirectDelivery implements WebhookDelivery by POSTing directly to endpoint URLs
 // stored in the database. This is used when Svix is not configured.
+// Each call makes one attempt per endpoint; the Orchestrator retries.
 type DirectDelivery struct {
 	store  *Store
 	client *http.Client
@@ -50,7 +54,7 @@ func (d *DirectDelivery) SendMessage(ctx context.Context, appID, eventType strin
 			return fmt.Errorf("get endpoint %s: %w", epID, err)
 		}
 		if !ep.IsActive {
-			return fmt.Errorf("endpoint %s is not active", epID)
+			return fmt.Errorf("endpoint %s: %w", epID, ErrEndpointInactive)
 		}
 		return d.deliverToURL(ctx, ep.URL, eventType, payload, ep.SigningSecret)
 	}
@@ -128,34 +132,66 @@ func normalizePayload(payload map[string]interface{}) map[string]interface{} {
 	return out
 }
 
+// postToURL makes one delivery attempt. A failure at the endpoint is
+// returned as a *DeliveryError.
 func (d *DirectDelivery) postToURL(ctx context.Context, url string, body []byte, eventType, signingSecret string) error {
+	// HMAC-SHA256 sign the body if a signing secret is configured
+	var signature string
+	if signingSecret != "" {
+		signature = "sha256=" + signPayload(body, signingSecret)
+	}
+
+	status, err := d.postOnce(ctx, url, body, eventType, signature)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err // shutting down, not the endpoint's fault
+		}
+		return &DeliveryError{StatusCode: status, Err: err}
+	}
+	log.Printf("[direct_delivery] delivered to %s: %d", url, status)
+	return nil
+}
+
+// postOnce makes a single delivery attempt. status is 0 for transport errors.
+func (d *DirectDelivery) postOnce(ctx context.Context, url string, body []byte, eventType, signature string) (int, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return 0, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-FlowIndex-Event", eventType)
-
-	// HMAC-SHA256 sign the body if a signing secret is configured
-	if signingSecret != "" {
-		mac := hmac.New(sha256.New, []byte(signingSecret))
-		mac.Write(body)
-		sig := hex.EncodeToString(mac.Sum(nil))
-		req.Header.Set("X-FlowIndex-Signature", "sha256="+sig)
+	if signature != "" {
+		req.Header.Set("X-FlowIndex-Signature", signature)
 	}
 
 	resp, err := d.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("POST %s: %w", url, err)
+		return 0, fmt.Errorf("POST %s: %w", url, err)
 	}
 	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("POST %s returned %d", url, resp.StatusCode)
+		return resp.StatusCode, fmt.Errorf("POST %s returned %d", url, resp.StatusCode)
 	}
+	return resp.StatusCode, nil
+}
 
-	log.Printf("[direct_delivery] delivered to %s: %d", url, resp.StatusCode)
-	return nil
+// signPayload returns the hex HMAC-SHA256 of body under secret, as sent in
+// the X-FlowIndex-Signature header (prefixed with "sha256=").
+func signPayload(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func envInt(key string, def int) int {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return def
 }
 
 // --- Platform detection ---
@@ -373,12 +409,15 @@ func (d *DirectDelivery) deliverTelegram(ctx context.Context, url, eventType str
 
 	resp, err := d.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("telegram POST: %w", err)
+		if ctx.Err() != nil {
+			return err
+		}
+		return &DeliveryError{Err: fmt.Errorf("telegram POST: %w", err)}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("telegram API returned %d", resp.StatusCode)
+		return &DeliveryError{StatusCode: resp.StatusCode, Err: fmt.Errorf("telegram API returned %d", resp.StatusCode)}
 	}
 
 	log.Printf("[direct_delivery] delivered to telegram chat %s: %d", chatID, resp.StatusCode)
@@ -0,0 +1,110 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestDirectDelivery() *DirectDelivery {
+	return &DirectDelivery{client: http.DefaultClient}
+}
+
+func TestDirectDeliverySigns(t *testing.T) {
+	t.Parallel()
+
+	const secret = "whsec_test"
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); r.Header.Get("X-FlowIndex-Signature") != want {
+			t.Errorf("signature = %q, want %q", r.Header.Get("X-FlowIndex-Signature"), want)
+		}
+		if r.Header.Get("X-FlowIndex-Event") != "ft.transfer" {
+			t.Errorf("event header = %q", r.Header.Get("X-FlowIndex-Event"))
+		}
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	if err := newTestDirectDelivery().postToURL(context.Background(), srv.URL, []byte(`{"amount":"1.0"}`), "ft.transfer", secret); err != nil {
+		t.Fatalf("postToURL: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("attempts = %d, want 1", got)
+	}
+}
+
+func TestDirectDeliveryAttributesFailures(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	d := newTestDirectDelivery()
+
+	// A non-2xx response is the endpoint's failure, made in one attempt.
+	err := d.postToURL(context.Background(), srv.URL, []byte(`{}`), "ft.transfer", "")
+	var de *DeliveryError
+	if !errors.As(err, &de) || de.StatusCode != http.StatusServiceUnavailable || !de.retryable() {
+		t.Fatalf("err = %v, want a retryable DeliveryError with status 503", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("attempts = %d, want 1", got)
+	}
+
+	// So is a URL nothing is listening on.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	err = d.postToURL(context.Background(), closed.URL, []byte(`{}`), "ft.transfer", "")
+	if !errors.As(err, &de) || de.StatusCode != 0 {
+		t.Fatalf("err = %v, want a DeliveryError for the unreachable endpoint", err)
+	}
+
+	// Our own shutdown is not.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = d.postToURL(ctx, srv.URL, []byte(`{}`), "ft.transfer", "")
+	if err == nil || errors.As(err, &de) {
+		t.Fatalf("err = %v, want a plain error for a cancelled context", err)
+	}
+}
+
+func TestOrchestratorRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	o := &Orchestrator{maxAttempts: 3, retryBackoff: 100 * time.Millisecond}
+	cases := []struct {
+		name    string
+		err     error
+		attempt int
+		delay   time.Duration
+		retry   bool
+	}{
+		{"5xx first attempt", &DeliveryError{StatusCode: 502}, 1, 100 * time.Millisecond, true},
+		{"transport error second attempt", &DeliveryError{}, 2, 200 * time.Millisecond, true},
+		{"out of attempts", &DeliveryError{StatusCode: 502}, 3, 0, false},
+		{"client error", &DeliveryError{StatusCode: 400}, 1, 0, false},
+		{"our own error", errors.New("get endpoint: connection refused"), 1, 0, false},
+	}
+	for _, tc := range cases {
+		delay, retry := o.retryAfter(tc.err, tc.attempt)
+		if delay != tc.delay || retry != tc.retry {
+			t.Errorf("%s: retryAfter = %s, %v; want %s, %v", tc.name, delay, retry, tc.delay, tc.retry)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrEndpointInactive is returned by deliveries to a disabled endpoint. Such
+// events are dropped rather than dead-lettered.
+var ErrEndpointInactive = errors.New("endpoint is not active")
+
+// DeliveryError is a delivery attempt that failed at the endpoint: a non-2xx
+// response or a transport error reaching its URL. Only these failures count
+// toward disabling the endpoint; errors on our side are returned as-is.
+type DeliveryError struct {
+	StatusCode int // HTTP status, 0 for transport errors
+	Err        error
+}
+
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("delivery failed: %v", e.Err)
+}
+
+func (e *DeliveryError) Unwrap() error { return e.Err }
+
+// retryable reports whether the attempt may succeed later: transport
+// errors, 429 and 5xx.
+func (e *DeliveryError) retryable() bool {
+	return e.StatusCode == 0 || e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// DeadLetter is a delivery that still failed after all retries.
+type DeadLetter struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id,omitempty"`
+	EndpointID     string          `json:"endpoint_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Error          string          `json:"error"`
+	Attempts       int             `json:"attempts"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// EndpointHealth is an endpoint's delivery failure state.
+type EndpointHealth struct {
+	ID                  string     `json:"id"`
+	UserID              string     `json:"user_id"`
+	URL                 string     `json:"url"`
+	IsActive            bool       `json:"is_active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+	DeadLetters         int        `json:"dead_letters"`
+}
+
+func (s *Store) InsertDeadLetter(ctx context.Context, dl *DeadLetter) error {
+	var subID interface{}
+	if dl.SubscriptionID != "" {
+		subID = dl.SubscriptionID
+	}
+	return s.pool.QueryRow(ctx,
+		`INSERT INTO public.webhook_dead_letters (subscription_id, endpoint_id, event_type, payload, error, attempts)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, created_at`,
+		subID, dl.EndpointID, dl.EventType, dl.Payload, dl.Error, dl.Attempts,
+	).Scan(&dl.ID, &dl.CreatedAt)
+}
+
+// ListDeadLetters lists dead letters newest first, optionally for one endpoint.
+func (s *Store) ListDeadLetters(ctx context.Context, endpointID string, limit, offset int) ([]DeadLetter, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, COALESCE(subscription_id::text, ''), endpoint_id, event_type, payload, error, attempts, created_at
+		 FROM public.webhook_dead_letters
+		 WHERE ($1 = '' OR endpoint_id::text = $1)
+		 ORDER BY created_at DESC LIMIT $2 OFFSET $3`,
+		endpointID, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []DeadLetter
+	for rows.Next() {
+		var dl DeadLetter
+		if err := rows.Scan(&dl.ID, &dl.SubscriptionID, &dl.EndpointID, &dl.EventType, &dl.Payload, &dl.Error, &dl.Attempts, &dl.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, dl)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) DeleteDeadLetter(ctx context.Context, id string) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM public.webhook_dead_letters WHERE id = $1`, id)
+	return err
+}
+
+// RecordEndpointFailure bumps the endpoint's consecutive failure count and
+// deactivates it once the count reaches disableAfter (0 = never). It reports
+// whether this call disabled the endpoint.
+func (s *Store) RecordEndpointFailure(ctx context.Context, id string, disableAfter int) (bool, error) {
+	var disabled bool
+	err := s.pool.QueryRow(ctx,
+		`UPDATE public.endpoints SET
+			consecutive_failures = consecutive_failures + 1,
+			last_failure_at = now(),
+			is_active = CASE WHEN $2 > 0 AND consecutive_failures + 1 >= $2 THEN false ELSE is_active END,
+			disabled_reason = CASE WHEN $2 > 0 AND consecutive_failures + 1 >= $2 AND is_active
+				THEN format('disabled after %s consecutive delivery failures', consecutive_failures + 1)
+				ELSE disabled_reason END
+		 WHERE id = $1
+		 RETURNING $2 > 0 AND consecutive_failures = $2`,
+		id, disableAfter,
+	).Scan(&disabled)
+	return disabled, err
+}
+
+// RecordEndpointSuccess clears the consecutive failure count.
+func (s *Store) RecordEndpointSuccess(ctx context.Context, id string) error {
+	_, err := s.pool.Exec(ctx,
+		`UPDATE public.endpoints SET consecutive_failures = 0 WHERE id = $1 AND consecutive_failures > 0`, id)
+	return err
+}
+
+// SetEndpointActive enables or disables an endpoint. Enabling also clears the
+// failure count and disabled reason.
+func (s *Store) SetEndpointActive(ctx context.Context, id string, active bool, reason string) error {
+	tag, err := s.pool.Exec(ctx,
+		`UPDATE public.endpoints SET
+			is_active = $2,
+			consecutive_failures = CASE WHEN $2 THEN 0 ELSE consecutive_failures END,
+			disabled_reason = CASE WHEN $2 THEN NULL ELSE NULLIF($3, '') END
+		 WHERE id = $1`,
+		id, active, reason,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("endpoint %s not found", id)
+	}
+	return nil
+}
+
+// ListEndpointHealth lists endpoints with their failure state, most failing
+// first. failingOnly limits it to endpoints that are disabled or failing.
+func (s *Store) ListEndpointHealth(ctx context.Context, failingOnly bool, limit, offset int) ([]EndpointHealth, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT e.id, e.user_id, e.url, e.is_active, e.consecutive_failures, e.last_failure_at,
+		        COALESCE(e.disabled_reason, ''),
+		        (SELECT COUNT(*) FROM public.webhook_dead_letters d WHERE d.endpoint_id = e.id)
+		 FROM public.endpoints e
+		 WHERE NOT $1 OR e.consecutive_failures > 0 OR NOT e.is_active
+		 ORDER BY e.consecutive_failures DESC, e.created_at DESC
+		 LIMIT $2 OFFSET $3`,
+		failingOnly, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []EndpointHealth
+	for rows.Next() {
+		var h EndpointHealth
+		if err := rows.Scan(&h.ID, &h.UserID, &h.URL, &h.IsActive, &h.ConsecutiveFailures, &h.LastFailureAt, &h.DisabledReason, &h.DeadLetters); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
@@ -2,6 +2,7 @@ package webhooks
 
 import (
 	"context"
+	"fmt"
 	"log"
 )
 
@@ -53,6 +54,9 @@ func (h *HybridDelivery) SendMessage(ctx context.Context, appID, eventType strin
 		if err != nil {
 			return h.direct.SendMessage(ctx, appID, eventType, payload)
 		}
+		if !ep.IsActive {
+			return fmt.Errorf("endpoint %s: %w", epID, ErrEndpointInactive)
+		}
 
 		if isDiscordWebhook(ep.URL) || isSlackWebhook(ep.URL) || isTelegramEndpoint(ep.URL) {
 			// Discord/Slack/Telegram → DirectDelivery for rich formatting
@@ -3,6 +3,7 @@ package webhooks
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"time"
 
@@ -17,13 +18,22 @@ import (
 //  3. Evaluates conditions via the matcher registry
 //  4. Delivers webhooks via the delivery backend (Svix or noop)
 //  5. Logs deliveries in the store
+//
+// Deliveries run on a per-endpoint deliveryQueue, so a slow endpoint never
+// holds up the event loop. Failures at the endpoint are retried with
+// exponential backoff; deliveries that still fail are dead-lettered, and an
+// endpoint is disabled after disableAfter consecutive failures of its own.
 type Orchestrator struct {
-	bus      *eventbus.Bus
-	cache    *SubscriptionCache
-	registry *matcher.Registry
-	delivery WebhookDelivery
-	store    *Store
-	events   chan eventbus.Event
+	bus          *eventbus.Bus
+	cache        *SubscriptionCache
+	registry     *matcher.Registry
+	delivery     WebhookDelivery
+	store        *Store
+	events       chan eventbus.Event
+	queue        *deliveryQueue
+	maxAttempts  int           // WEBHOOK_MAX_ATTEMPTS
+	retryBackoff time.Duration // WEBHOOK_RETRY_BACKOFF_MS, doubled per attempt
+	disableAfter int           // WEBHOOK_DISABLE_AFTER_FAILURES, 0 = never
 }
 
 // NewOrchestrator creates an Orchestrator and subscribes to all event types
@@ -45,7 +55,16 @@ func NewOrchestrator(
 		delivery: delivery,
 		store:    store,
 		events:   ch,
+
+		maxAttempts:  envInt("WEBHOOK_MAX_ATTEMPTS", 4),
+		retryBackoff: time.Duration(envInt("WEBHOOK_RETRY_BACKOFF_MS", 500)) * time.Millisecond,
+		disableAfter: envInt("WEBHOOK_DISABLE_AFTER_FAILURES", 20),
 	}
+	o.queue = newDeliveryQueue(
+		envInt("WEBHOOK_DELIVERY_WORKERS", 16),
+		envInt("WEBHOOK_ENDPOINT_QUEUE", 256),
+		o.deliver,
+	)
 
 	// Subscribe to every event type the registry knows about.
 	for _, et := range registry.EventTypes() {
@@ -105,46 +124,124 @@ func (o *Orchestrator) processEvent(ctx context.Context, evt eventbus.Event) {
 			}
 		}
 
-		o.deliver(ctx, sub, evt)
+		o.enqueue(ctx, deliveryJob{sub: sub, evt: evt, attempt: 1})
 		deliveredEndpoints[sub.EndpointID] = true
 	}
 }
 
-// deliver sends a webhook for the matched subscription and logs the result.
-func (o *Orchestrator) deliver(ctx context.Context, sub Subscription, evt eventbus.Event) {
-	payload := map[string]interface{}{
-		"event_type":   evt.Type,
-		"block_height": evt.Height,
-		"timestamp":    evt.Timestamp.UTC().Format(time.RFC3339),
-		"data":         evt.Data,
-		"_endpoint_id": sub.EndpointID, // used by DirectDelivery for targeted delivery
+// enqueue hands job to its endpoint's queue. When the endpoint is too far
+// behind the job is dead-lettered; that does not count against the endpoint.
+func (o *Orchestrator) enqueue(ctx context.Context, job deliveryJob) {
+	if o.queue.push(ctx, job) {
+		return
 	}
+	log.Printf("[orchestrator] endpoint %s queue full, dead-lettering: sub=%s type=%s", job.sub.EndpointID, job.sub.ID, job.evt.Type)
+	payloadJSON, _ := json.Marshal(deliveryPayload(job))
+	o.deadLetter(ctx, job, payloadJSON, errEndpointQueueFull)
+}
 
-	// Use the user ID as the Svix application ID (one app per user).
-	appID := sub.UserID
+var errEndpointQueueFull = errors.New("endpoint delivery queue full")
+
+func deliveryPayload(job deliveryJob) map[string]interface{} {
+	return map[string]interface{}{
+		"event_type":   job.evt.Type,
+		"block_height": job.evt.Height,
+		"timestamp":    job.evt.Timestamp.UTC().Format(time.RFC3339),
+		"data":         job.evt.Data,
+		"_endpoint_id": job.sub.EndpointID, // used by DirectDelivery for targeted delivery
+	}
+}
+
+// deliver makes one delivery attempt, scheduling a retry or recording the
+// final outcome.
+func (o *Orchestrator) deliver(ctx context.Context, job deliveryJob) {
+	payload := deliveryPayload(job)
 
-	err := o.delivery.SendMessage(ctx, appID, evt.Type, payload)
+	// Use the user ID as the Svix application ID (one app per user).
+	err := o.delivery.SendMessage(ctx, job.sub.UserID, job.evt.Type, payload)
+	if errors.Is(err, ErrEndpointInactive) {
+		return // disabled endpoints drop events until re-enabled
+	}
+	if delay, ok := o.retryAfter(err, job.attempt); ok && ctx.Err() == nil {
+		log.Printf("[orchestrator] attempt %d/%d failed: sub=%s type=%s err=%v; retrying in %s",
+			job.attempt, o.maxAttempts, job.sub.ID, job.evt.Type, err, delay)
+		job.attempt++
+		time.AfterFunc(delay, func() { o.enqueue(ctx, job) })
+		return
+	}
 
 	statusCode := 200
 	svixMsgID := ""
 	if err != nil {
 		statusCode = 0
-		log.Printf("[orchestrator] delivery failed: sub=%s type=%s err=%v", sub.ID, evt.Type, err)
+		var dErr *DeliveryError
+		if errors.As(err, &dErr) {
+			statusCode = dErr.StatusCode
+		}
+		log.Printf("[orchestrator] delivery failed: sub=%s type=%s attempts=%d err=%v", job.sub.ID, job.evt.Type, job.attempt, err)
 	}
 
 	// Log the delivery attempt.
 	payloadJSON, _ := json.Marshal(payload)
+	if err != nil {
+		o.recordFailure(ctx, job, payloadJSON, err)
+	} else if recErr := o.store.RecordEndpointSuccess(ctx, job.sub.EndpointID); recErr != nil {
+		log.Printf("[orchestrator] failed to reset endpoint failures: ep=%s err=%v", job.sub.EndpointID, recErr)
+	}
 	dlLog := &DeliveryLog{
-		SubscriptionID: sub.ID,
-		EndpointID:     sub.EndpointID,
-		EventType:      evt.Type,
+		SubscriptionID: job.sub.ID,
+		EndpointID:     job.sub.EndpointID,
+		EventType:      job.evt.Type,
 		Payload:        payloadJSON,
 		StatusCode:     statusCode,
 		SvixMsgID:      svixMsgID,
 	}
 
 	if logErr := o.store.InsertDeliveryLog(ctx, dlLog); logErr != nil {
-		log.Printf("[orchestrator] failed to log delivery: sub=%s err=%v", sub.ID, logErr)
+		log.Printf("[orchestrator] failed to log delivery: sub=%s err=%v", job.sub.ID, logErr)
+	}
+}
+
+// retryAfter reports whether a failed attempt should be retried, and after
+// how long. Only retryable failures at the endpoint are retried.
+func (o *Orchestrator) retryAfter(err error, attempt int) (time.Duration, bool) {
+	var dErr *DeliveryError
+	if !errors.As(err, &dErr) || !dErr.retryable() || attempt >= o.maxAttempts {
+		return 0, false
+	}
+	return o.retryBackoff << (attempt - 1), true
+}
+
+// recordFailure dead-letters a failed delivery. Failures at the endpoint also
+// count against it, disabling the endpoint once it keeps failing.
+func (o *Orchestrator) recordFailure(ctx context.Context, job deliveryJob, payloadJSON []byte, deliveryErr error) {
+	o.deadLetter(ctx, job, payloadJSON, deliveryErr)
+
+	var dErr *DeliveryError
+	if !errors.As(deliveryErr, &dErr) {
+		return
+	}
+	disabled, err := o.store.RecordEndpointFailure(ctx, job.sub.EndpointID, o.disableAfter)
+	if err != nil {
+		log.Printf("[orchestrator] failed to record endpoint failure: ep=%s err=%v", job.sub.EndpointID, err)
+		return
+	}
+	if disabled {
+		log.Printf("[orchestrator] endpoint %s disabled after %d consecutive failures", job.sub.EndpointID, o.disableAfter)
+	}
+}
+
+func (o *Orchestrator) deadLetter(ctx context.Context, job deliveryJob, payloadJSON []byte, deliveryErr error) {
+	dl := &DeadLetter{
+		SubscriptionID: job.sub.ID,
+		EndpointID:     job.sub.EndpointID,
+		EventType:      job.evt.Type,
+		Payload:        payloadJSON,
+		Error:          deliveryErr.Error(),
+		Attempts:       job.attempt,
+	}
+	if err := o.store.InsertDeadLetter(ctx, dl); err != nil {
+		log.Printf("[orchestrator] failed to dead-letter delivery: sub=%s err=%v", job.sub.ID, err)
 	}
 }
 
@@ -27,6 +27,10 @@ func (p *WebhookProcessor) Name() string {
 	return "webhook_processor"
 }
 
+func (p *WebhookProcessor) SchemaVersion() int {
+	return 1
+}
+
 func (p *WebhookProcessor) ProcessRange(ctx context.Context, fromHeight, toHeight uint64) error {
 	published := 0
 	var ts time.Time
@@ -154,6 +154,10 @@ func main() {
 	historyClient := connectFlowClientWithRetry("FLOW_HISTORIC_ACCESS_NODES_EFFECTIVE", historicNodesRaw, "historic")
 	defer historyClient.Close()
 
+	// Scripts stored by hash only (TX_SCRIPT_STORE_MAX_BYTES) are loaded on demand
+	// through the spork-aware history pool.
+	repo.SetScriptSource(historyClient)
+
 	// 3. Services
 	// Config Parsing Helpers
 	getEnvInt := func(key string, defaultVal int) int {
@@ -186,6 +190,7 @@ func main() {
 	latestBatch := getEnvInt("LATEST_BATCH_SIZE", 1)    // Real-time
 	historyBatch := getEnvInt("HISTORY_BATCH_SIZE", 20) // Throughput
 	maxReorgDepth := getEnvUint("MAX_REORG_DEPTH", 1000)
+	headLag := getEnvUint("INGEST_HEAD_LAG", 0)              // forward ingester stays this many sealed blocks behind the tip
 	metaWorkerRange := getEnvUint("META_WORKER_RANGE", 1000) // used as default for head backfill
 	// Queue-based async worker configs (block-range workers replaced by live_deriver)
 	nftItemMetadataWorkerRange := getEnvUint("NFT_ITEM_METADATA_WORKER_RANGE", 1000)
@@ -228,6 +233,7 @@ func main() {
 	enableDailyStatsWorker := os.Getenv("ENABLE_DAILY_STATS_WORKER") != "false"
 	enableAnalyticsDeriverWorker := os.Getenv("ENABLE_ANALYTICS_DERIVER_WORKER") != "false"
 	enableDefiWorker := os.Getenv("ENABLE_DEFI_WORKER") != "false"
+	enableNFTSalesWorker := os.Getenv("ENABLE_NFT_SALES_WORKER") != "false"
 	enableScheduledWorker := os.Getenv("ENABLE_SCHEDULED_WORKER") != "false"
 	enableNFTItemMetadataWorker := os.Getenv("ENABLE_NFT_ITEM_METADATA_WORKER") != "false"
 	enableNFTReconciler := os.Getenv("ENABLE_NFT_RECONCILER") != "false"
@@ -249,6 +255,7 @@ func main() {
 		enableDailyStatsWorker = false
 		enableAnalyticsDeriverWorker = false
 		enableDefiWorker = false
+		enableNFTSalesWorker = false
 		enableScheduledWorker = false
 		enableNFTItemMetadataWorker = false
 		enableNFTReconciler = false
@@ -298,16 +305,23 @@ func main() {
 		if enableDefiWorker {
 			processors = append(processors, ingester.NewDefiWorker(repo))
 		}
+		if enableNFTSalesWorker {
+			processors = append(processors, ingester.NewNFTSalesWorker(repo))
+		}
 		if enableScheduledWorker {
 			processors = append(processors, ingester.NewScheduledWorker(repo))
 		}
-		// NOTE: daily_stats_worker and analytics_deriver_worker are intentionally
-		// excluded from live_deriver. They call RefreshDailyStatsRange which does a
-		// full table scan on raw.transactions per affected date — far too heavy for
-		// real-time 10-block chunks (120s timeout). Daily stats are maintained by:
+		// NOTE: analytics_deriver_worker is intentionally excluded from live_deriver;
+		// RefreshAnalyticsDailyMetricsRange still recounts whole days by DATE().
+		// daily_stats_worker resolves day bounds via raw.block_lookup and is cheap
+		// enough for live chunks, but stays opt-in (ENABLE_LIVE_DAILY_STATS=true).
+		// Otherwise daily stats are maintained by:
 		//   1. Startup full scan (ENABLE_DAILY_STATS=true)
 		//   2. Periodic 30-day refresh (same goroutine)
 		//   3. Standalone daily_stats_worker in history deriver for backfill
+		if enableDailyStatsWorker && os.Getenv("ENABLE_LIVE_DAILY_STATS") == "true" {
+			processors = append(processors, ingester.NewDailyStatsWorker(repo))
+		}
 		// Phase 2 processors (depend on token_worker output):
 		if enableFTHoldingsWorker {
 			processors = append(processors, ingester.NewFTHoldingsWorker(repo))
@@ -362,6 +376,7 @@ func main() {
 		{"tx_metrics_worker", enableTxMetricsWorker, func() ingester.Processor { return ingester.NewTxMetricsWorker(repo) }},
 		{"staking_worker", enableStakingWorker, func() ingester.Processor { return ingester.NewStakingWorker(repo) }},
 		{"defi_worker", enableDefiWorker, func() ingester.Processor { return ingester.NewDefiWorker(repo) }},
+		{"nft_sales_worker", enableNFTSalesWorker, func() ingester.Processor { return ingester.NewNFTSalesWorker(repo) }},
 		{"scheduled_worker", enableScheduledWorker, func() ingester.Processor { return ingester.NewScheduledWorker(repo) }},
 		// NOTE: daily_stats_worker and analytics_deriver_worker are NOT in the deriver.
 		// They do full table scans on raw.transactions per affected date — too heavy for
@@ -371,6 +386,36 @@ func main() {
 		{"daily_balance_worker", enableDailyBalanceWorker, func() ingester.Processor { return ingester.NewDailyBalanceWorker(repo) }},
 	}
 
+	// Reset checkpoints of processors whose SchemaVersion() was bumped so their
+	// derived output is rebuilt. Opt-in to avoid surprise full re-derives.
+	var versioned []ingester.Processor
+	for _, p := range allHistProcs {
+		if p.enabled {
+			versioned = append(versioned, p.create())
+		}
+	}
+	if len(versioned) > 0 {
+		autoReset := os.Getenv("PROCESSOR_SCHEMA_AUTO_RESET") == "true"
+		// History deriver checkpoint prefixes whose per-processor UP lanes
+		// must be rewound too (see NewHistoryDeriver and History Deriver 2 below).
+		var historyPrefixes []string
+		if enableHistoryDerivers {
+			historyPrefixes = append(historyPrefixes, "history_deriver")
+			if os.Getenv("HISTORY_DERIVER2_ENABLED") == "true" {
+				hd2Prefix := os.Getenv("HISTORY_DERIVER2_CHECKPOINT")
+				if hd2Prefix == "" {
+					hd2Prefix = "history_deriver_2"
+				}
+				historyPrefixes = append(historyPrefixes, hd2Prefix)
+			}
+		}
+		if reset, err := ingester.ReconcileProcessorSchemaVersions(context.Background(), repo, versioned, historyPrefixes, autoReset); err != nil {
+			log.Printf("Warning: processor schema version check failed: %v", err)
+		} else if len(reset) > 0 {
+			log.Printf("Processor schema versions bumped, processors reset: %v", reset)
+		}
+	}
+
 	var historyDeriver *ingester.HistoryDeriver
 	var onHistoryIndexedRange ingester.RangeCallback
 	if enableHistoryDerivers {
@@ -421,6 +466,23 @@ func main() {
 		historyServiceName = "history_ingester"
 	}
 
+	// Only use the bulk block APIs; never walk collection guarantees.
+	skipCollections := os.Getenv("INGEST_SKIP_COLLECTIONS") == "true"
+	// Trust bulk tx results as returned; by default results whose event
+	// indices show missing events are re-fetched one at a time.
+	skipEventRecheck := os.Getenv("INGEST_SKIP_EVENT_RECHECK") == "true"
+	// Persist collection membership to raw.collections (one RPC per guarantee).
+	storeCollections := strings.ToLower(strings.TrimSpace(os.Getenv("STORE_COLLECTIONS"))) == "true"
+
+	// Pause ingester batch writes while more than DB_BACKPRESSURE_THRESHOLD_PCT
+	// of the DB pool is in use, up to DB_BACKPRESSURE_MAX_DELAY_MS per batch
+	// (threshold 0 disables).
+	var dbBackpressure *ingester.Backpressure
+	if pct := getEnvInt("DB_BACKPRESSURE_THRESHOLD_PCT", 80); pct > 0 {
+		dbBackpressure = ingester.NewBackpressure(repo.PoolUsage, float64(pct)/100,
+			time.Duration(getEnvInt("DB_BACKPRESSURE_MAX_DELAY_MS", 2000))*time.Millisecond)
+	}
+
 	forwardIngester := ingester.NewService(flowClient, repo, ingester.Config{
 		ServiceName:       forwardServiceName,
 		BatchSize:         latestBatch,
@@ -428,21 +490,30 @@ func main() {
 		StartBlock:        startBlock,
 		Mode:              "forward",
 		MaxReorgDepth:     maxReorgDepth,
+		HeadLag:           headLag,
 		OnNewBlock:        api.BroadcastNewBlock,
 		OnNewTransactions: api.MakeBroadcastNewTransactions(repo),
 		OnIndexedRange:    onIndexedRange,
+		SkipCollections:   skipCollections,
+		SkipEventRecheck:  skipEventRecheck,
+		StoreCollections:  storeCollections,
+		Backpressure:      dbBackpressure,
 	})
 
 	// Backward Ingester (History Backfill)
 	backwardIngester := ingester.NewService(historyClient, repo, ingester.Config{
-		ServiceName:    historyServiceName,
-		BatchSize:      historyBatch,
-		WorkerCount:    historyWorkers,
-		StartBlock:     startBlock,
-		StopHeight:     historyStopHeight,
-		Mode:           "backward",
-		MaxReorgDepth:  maxReorgDepth,
-		OnIndexedRange: onHistoryIndexedRange,
+		ServiceName:      historyServiceName,
+		BatchSize:        historyBatch,
+		WorkerCount:      historyWorkers,
+		StartBlock:       startBlock,
+		StopHeight:       historyStopHeight,
+		Mode:             "backward",
+		MaxReorgDepth:    maxReorgDepth,
+		OnIndexedRange:   onHistoryIndexedRange,
+		SkipCollections:  skipCollections,
+		SkipEventRecheck: skipEventRecheck,
+		StoreCollections: storeCollections,
+		Backpressure:     dbBackpressure,
 	})
 
 	// Block-range async workers are DISABLED (方案A): live_deriver processes all
@@ -549,7 +620,10 @@ func main() {
 
 	var committer *ingester.CheckpointCommitter
 	if len(workerTypes) > 0 {
-		committer = ingester.NewCheckpointCommitter(repo, workerTypes)
+		// Alert when a worker's oldest outstanding lease is older than
+		// LEASE_STUCK_ALERT_SEC (0 disables the alert).
+		committer = ingester.NewCheckpointCommitter(repo, workerTypes,
+			time.Duration(getEnvInt("LEASE_STUCK_ALERT_SEC", 1800))*time.Second)
 	}
 
 	// --- Webhook Notification System ---
@@ -643,6 +717,15 @@ func main() {
 		}
 	}
 
+	// Ingester watchdog: restart an ingester loop whose checkpoint has not moved
+	// for INGESTER_WATCHDOG_STALL_SEC while the chain head has (0 disables).
+	var watchdog *ingester.Watchdog
+	if stallSec := getEnvInt("INGESTER_WATCHDOG_STALL_SEC", 600); stallSec > 0 {
+		watchdog = ingester.NewWatchdog(repo, flowClient.GetLatestBlockHeight,
+			time.Duration(stallSec)*time.Second,
+			time.Duration(getEnvInt("INGESTER_WATCHDOG_INTERVAL_SEC", 60))*time.Second)
+	}
+
 	api.BuildCommit = BuildCommit
 	backfillProgress := api.NewBackfillProgress()
 
@@ -652,6 +735,15 @@ func main() {
 		},
 		api.WithHistoryClient(historyClient),
 	}
+	if watchdog != nil {
+		serverOpts = append(serverOpts, api.WithIngesterWatchdog(watchdog))
+	}
+	if committer != nil {
+		serverOpts = append(serverOpts, api.WithCheckpointCommitter(committer))
+	}
+	if dbBackpressure != nil {
+		serverOpts = append(serverOpts, api.WithDBBackpressure(dbBackpressure))
+	}
 	if webhookHandlersOpt != nil {
 		serverOpts = append(serverOpts, webhookHandlersOpt)
 	}
@@ -665,6 +757,9 @@ func main() {
 		serverOpts = append(serverOpts, tierRPSResolverOpt)
 	}
 	apiServer := api.NewServer(repo, flowClient, apiPort, startBlock, serverOpts...)
+	if historyDeriver != nil {
+		apiServer.RegisterHistoryDeriver(historyDeriver)
+	}
 
 	// 4. Run
 	ctx, cancel := context.WithCancel(context.Background())
@@ -746,6 +841,7 @@ func main() {
 			DisableDown:      os.Getenv("HISTORY_DERIVER2_DISABLE_DOWN") == "true",
 			CeilingHeight:    hd2Ceiling,
 		})
+		apiServer.RegisterHistoryDeriver(hd2)
 		hd2.Start(ctx)
 		log.Printf("History Deriver 2 started (prefix=%s chunk=%d concurrency=%d ceiling=%d)", hd2Prefix, hd2Chunk, hd2Concurrency, hd2Ceiling)
 	}
@@ -773,6 +869,10 @@ func main() {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			if watchdog != nil {
+				watchdog.Supervise(ctx, forwardServiceName, forwardIngester.Start)
+				return
+			}
 			forwardIngester.Start(ctx)
 		}()
 	} else {
@@ -785,12 +885,20 @@ func main() {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			if watchdog != nil {
+				watchdog.Supervise(ctx, historyServiceName, backwardIngester.Start)
+				return
+			}
 			backwardIngester.Start(ctx)
 		}()
 	} else {
 		log.Println("History Ingester is DISABLED (ENABLE_HISTORY_INGESTER=false)")
 	}
 
+	if watchdog != nil && (enableForward || enableHistory) {
+		go watchdog.Run(ctx)
+	}
+
 	// Start Async Workers (queue-based only — block-range workers replaced by live_deriver)
 	if enableNFTItemMetadataWorker {
 		for _, worker := range nftItemMetadataWorkers {
@@ -1075,89 +1183,31 @@ func main() {
 	// 1. Load existing prices from DB into in-memory cache.
 	// 2. Backfill from CoinGecko (FLOW), CryptoCompare (all market_symbol), DeFi Llama (all coingecko_id).
 	// 3. Reload cache after backfill.
+	// Both can be re-run on demand through /admin/price-cache.
 	enablePriceFeed := os.Getenv("ENABLE_PRICE_FEED") != "false"
 	if enablePriceFeed {
-		// Load existing prices into the in-memory cache immediately.
-		loadPriceCacheFromDB(ctx, repo, apiServer.PriceCache())
-
-		go func() {
-			// CoinGecko backfill for FLOW (dense hourly data)
-			earliest, err := repo.GetEarliestMarketPrice(ctx, "FLOW", "USD")
-			needsBackfill := err != nil || earliest.AsOf.After(time.Now().AddDate(0, 0, -30))
-			if needsBackfill {
-				log.Println("[price_backfill] Fetching 365 days of FLOW/USD history from CoinGecko...")
-				ctxFetch, cancel := context.WithTimeout(ctx, 30*time.Second)
-				history, err := market.FetchFlowPriceHistory(ctxFetch, 365)
-				cancel()
-				if err != nil {
-					log.Printf("[price_backfill] CoinGecko error: %v", err)
-				} else {
-					prices := make([]repository.MarketPrice, len(history))
-					for i, q := range history {
-						prices[i] = repository.MarketPrice{
-							Asset: q.Asset, Currency: q.Currency, Price: q.Price,
-							MarketCap: q.MarketCap, Source: q.Source, AsOf: q.AsOf,
-						}
-					}
-					inserted, err := repo.BulkInsertMarketPrices(ctx, prices)
-					if err != nil {
-						log.Printf("[price_backfill] CoinGecko insert error (%d inserted): %v", inserted, err)
-					} else {
-						log.Printf("[price_backfill] CoinGecko: %d new prices (of %d fetched)", inserted, len(history))
-					}
-				}
-			}
-
-			// CryptoCompare backfill for all tokens with market_symbol
-			symbols := getMarketSymbols(ctx, repo)
-			for _, sym := range symbols {
-				ctxCC, cancelCC := context.WithTimeout(ctx, 30*time.Second)
-				history, err := market.FetchDailyPriceHistory(ctxCC, sym, 2000)
-				cancelCC()
-				if err != nil {
-					log.Printf("[price_backfill] CryptoCompare %s: %v", sym, err)
-					continue
-				}
-				prices := make([]repository.MarketPrice, len(history))
-				for i, q := range history {
-					prices[i] = repository.MarketPrice{
-						Asset: strings.ToUpper(q.Asset), Currency: "USD",
-						Price: q.Price, Source: q.Source, AsOf: q.AsOf,
-					}
-				}
-				inserted, _ := repo.BulkInsertMarketPrices(ctx, prices)
-				if inserted > 0 {
-					log.Printf("[price_backfill] CryptoCompare %s: %d new prices", sym, inserted)
-				}
+		// Latest prices older than PRICE_STALE_AFTER_HOURS (per source:
+		// PRICE_STALE_AFTER_HOURS_<SOURCE>) are flagged stale; 0 disables.
+		apiServer.PriceCache().SetStaleAfter("", time.Duration(getEnvInt("PRICE_STALE_AFTER_HOURS", 48))*time.Hour)
+		for _, source := range []string{"coingecko", "defillama", "geckoterminal", "cryptocompare"} {
+			key := "PRICE_STALE_AFTER_HOURS_" + strings.ToUpper(source)
+			if os.Getenv(key) != "" {
+				apiServer.PriceCache().SetStaleAfter(source, time.Duration(getEnvInt(key, 48))*time.Hour)
 			}
+		}
 
-			// DeFi Llama backfill for all tokens with coingecko_id
-			cgMap, _ := repo.GetCoingeckoToMarketSymbolMap(ctx)
-			for cgID, marketSym := range cgMap {
-				ctxDL, cancelDL := context.WithTimeout(ctx, 2*time.Minute)
-				history, err := market.FetchDefiLlamaPriceHistory(ctxDL, cgID)
-				cancelDL()
-				if err != nil {
-					log.Printf("[price_backfill] DeFiLlama %s: %v", cgID, err)
-					continue
-				}
-				prices := make([]repository.MarketPrice, len(history))
-				for i, q := range history {
-					prices[i] = repository.MarketPrice{
-						Asset: strings.ToUpper(marketSym), Currency: "USD",
-						Price: q.Price, Source: q.Source, AsOf: q.AsOf,
-					}
-				}
-				inserted, _ := repo.BulkInsertMarketPrices(ctx, prices)
-				if inserted > 0 {
-					log.Printf("[price_backfill] DeFiLlama %s (%s): %d new prices", cgID, marketSym, inserted)
-				}
-			}
+		// Load existing prices into the in-memory cache immediately.
+		loadPriceCacheFromDB(ctx, repo, apiServer.PriceCache())
+		apiServer.SetPriceCacheHooks(api.PriceCacheHooks{
+			Reload: func(ctx context.Context) error {
+				return loadPriceCacheFromDB(ctx, repo, apiServer.PriceCache())
+			},
+			Backfill: func(ctx context.Context) {
+				backfillPriceHistory(ctx, repo, apiServer.PriceCache())
+			},
+		})
 
-			// Reload cache after backfill
-			loadPriceCacheFromDB(ctx, repo, apiServer.PriceCache())
-			log.Println("[price_backfill] Cache reloaded after backfill")
-		}()
+		go backfillPriceHistory(ctx, repo, apiServer.PriceCache())
 	}
 
 	// Start Market Price Poller (Runs every N mins)
@@ -1266,6 +1316,7 @@ func main() {
 					apiServer.PriceCache().Append(asset, []market.DailyPrice{
 						{Date: q.AsOf.UTC().Truncate(24 * time.Hour), Price: q.Price},
 					})
+					apiServer.PriceCache().Observe(asset, q)
 					stored++
 				}
 				log.Printf("[price_poller] Updated %d/%d token prices (sources: coingecko=%d, defillama+gt=%d)",
@@ -1353,6 +1404,91 @@ func main() {
 		log.Println("Lookup Repair is DISABLED (ENABLE_LOOKUP_REPAIR=false)")
 	}
 
+	// Address activity compaction (off by default): trims app.address_transactions
+	// of the busiest addresses to their newest ADDRESS_ACTIVITY_KEEP transactions.
+	// Counts in app.address_stats are kept; the API flags compacted lists.
+	if keep := getEnvInt("ADDRESS_ACTIVITY_KEEP", 0); keep > 0 {
+		maxAddresses := getEnvInt("ADDRESS_ACTIVITY_COMPACT_MAX_ADDRESSES", 100)
+		intervalMin := getEnvInt("ADDRESS_ACTIVITY_COMPACT_INTERVAL_MIN", 60)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ticker := time.NewTicker(time.Duration(intervalMin) * time.Minute)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					compacted, err := repo.CompactAddressActivity(ctx, keep, maxAddresses)
+					if err != nil {
+						log.Printf("Address activity compaction failed: %v", err)
+					}
+					for _, c := range compacted {
+						log.Printf("Address activity compaction: %s trimmed %d rows below height %d", c.Address, c.DeletedRows, c.CompactedBelowHeight)
+					}
+				}
+			}
+		}()
+	}
+
+	// raw.events partition retention (off by default). Partitions older than
+	// EVENTS_RETENTION_MONTHS are only detached once the history deriver and
+	// every enabled worker have covered their heights, and only with
+	// EVENTS_RETENTION_CONFIRM=true;
+	// otherwise the job reports what it would do. EVENTS_RETENTION_DROP=true
+	// drops detached partitions instead of leaving them for archival.
+	if retentionMonths := getEnvInt("EVENTS_RETENTION_MONTHS", 0); retentionMonths > 0 {
+		var retentionWorkers []string
+		for _, p := range allHistProcs {
+			if p.enabled {
+				retentionWorkers = append(retentionWorkers, p.name)
+			}
+		}
+		retention := ingester.NewEventRetention(repo, ingester.EventRetentionConfig{
+			Months:           retentionMonths,
+			Interval:         time.Duration(getEnvInt("EVENTS_RETENTION_INTERVAL_HOURS", 24)) * time.Hour,
+			Drop:             os.Getenv("EVENTS_RETENTION_DROP") == "true",
+			Confirm:          os.Getenv("EVENTS_RETENTION_CONFIRM") == "true",
+			DryRun:           os.Getenv("EVENTS_RETENTION_DRY_RUN") == "true",
+			CheckpointPrefix: os.Getenv("EVENTS_RETENTION_DERIVER_CHECKPOINT"),
+			Workers:          retentionWorkers,
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			retention.Start(ctx)
+		}()
+	}
+
+	// app.ft_holdings reconciliation (off by default): replays each token's
+	// transfers every FT_RECONCILE_INTERVAL_HOURS and corrects drifted holders,
+	// or only logs them with FT_RECONCILE_DRY_RUN=true. FT_RECONCILE_TOKENS
+	// limits it to a comma-separated list of A.<address>.<name> identifiers.
+	if intervalHours := getEnvInt("FT_RECONCILE_INTERVAL_HOURS", 0); intervalHours > 0 {
+		var tokens []string
+		for _, token := range strings.Split(os.Getenv("FT_RECONCILE_TOKENS"), ",") {
+			if token = strings.TrimSpace(token); token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+		reconciler := ingester.NewFTHoldingsReconciler(repo, ingester.FTHoldingsReconcilerConfig{
+			Interval: time.Duration(intervalHours) * time.Hour,
+			Tokens:   tokens,
+			DryRun:   os.Getenv("FT_RECONCILE_DRY_RUN") == "true",
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reconciler.Start(ctx)
+		}()
+	}
+
 	// Block until shutdown signal. Workers are in the WaitGroup but the
 	// API server also needs to stay alive even with zero workers (API-only mode).
 	<-sigChan
@@ -1412,11 +1548,93 @@ func connectFlowClientWithRetry(envKey string, fallback string, label string) *f
 	}
 }
 
-func loadPriceCacheFromDB(ctx context.Context, repo *repository.Repository, cache *market.PriceCache) {
+// backfillPriceHistory fetches daily price history from CoinGecko (FLOW),
+// CryptoCompare (all market_symbol) and DeFi Llama (all coingecko_id), stores
+// it and reloads the cache. Also run on demand via POST /admin/price-cache/backfill.
+func backfillPriceHistory(ctx context.Context, repo *repository.Repository, cache *market.PriceCache) {
+	// CoinGecko backfill for FLOW (dense hourly data)
+	earliest, err := repo.GetEarliestMarketPrice(ctx, "FLOW", "USD")
+	needsBackfill := err != nil || earliest.AsOf.After(time.Now().AddDate(0, 0, -30))
+	if needsBackfill {
+		log.Println("[price_backfill] Fetching 365 days of FLOW/USD history from CoinGecko...")
+		ctxFetch, cancel := context.WithTimeout(ctx, 30*time.Second)
+		history, err := market.FetchFlowPriceHistory(ctxFetch, 365)
+		cancel()
+		if err != nil {
+			log.Printf("[price_backfill] CoinGecko error: %v", err)
+		} else {
+			prices := make([]repository.MarketPrice, len(history))
+			for i, q := range history {
+				prices[i] = repository.MarketPrice{
+					Asset: q.Asset, Currency: q.Currency, Price: q.Price,
+					MarketCap: q.MarketCap, Source: q.Source, AsOf: q.AsOf,
+				}
+			}
+			inserted, err := repo.BulkInsertMarketPrices(ctx, prices)
+			if err != nil {
+				log.Printf("[price_backfill] CoinGecko insert error (%d inserted): %v", inserted, err)
+			} else {
+				log.Printf("[price_backfill] CoinGecko: %d new prices (of %d fetched)", inserted, len(history))
+			}
+		}
+	}
+
+	// CryptoCompare backfill for all tokens with market_symbol
+	symbols := getMarketSymbols(ctx, repo)
+	for _, sym := range symbols {
+		ctxCC, cancelCC := context.WithTimeout(ctx, 30*time.Second)
+		history, err := market.FetchDailyPriceHistory(ctxCC, sym, 2000)
+		cancelCC()
+		if err != nil {
+			log.Printf("[price_backfill] CryptoCompare %s: %v", sym, err)
+			continue
+		}
+		prices := make([]repository.MarketPrice, len(history))
+		for i, q := range history {
+			prices[i] = repository.MarketPrice{
+				Asset: strings.ToUpper(q.Asset), Currency: "USD",
+				Price: q.Price, Source: q.Source, AsOf: q.AsOf,
+			}
+		}
+		inserted, _ := repo.BulkInsertMarketPrices(ctx, prices)
+		if inserted > 0 {
+			log.Printf("[price_backfill] CryptoCompare %s: %d new prices", sym, inserted)
+		}
+	}
+
+	// DeFi Llama backfill for all tokens with coingecko_id
+	cgMap, _ := repo.GetCoingeckoToMarketSymbolMap(ctx)
+	for cgID, marketSym := range cgMap {
+		ctxDL, cancelDL := context.WithTimeout(ctx, 2*time.Minute)
+		history, err := market.FetchDefiLlamaPriceHistory(ctxDL, cgID)
+		cancelDL()
+		if err != nil {
+			log.Printf("[price_backfill] DeFiLlama %s: %v", cgID, err)
+			continue
+		}
+		prices := make([]repository.MarketPrice, len(history))
+		for i, q := range history {
+			prices[i] = repository.MarketPrice{
+				Asset: strings.ToUpper(marketSym), Currency: "USD",
+				Price: q.Price, Source: q.Source, AsOf: q.AsOf,
+			}
+		}
+		inserted, _ := repo.BulkInsertMarketPrices(ctx, prices)
+		if inserted > 0 {
+			log.Printf("[price_backfill] DeFiLlama %s (%s): %d new prices", cgID, marketSym, inserted)
+		}
+	}
+
+	// Reload cache after backfill
+	loadPriceCacheFromDB(ctx, repo, cache)
+	log.Println("[price_backfill] Cache reloaded after backfill")
+}
+
+func loadPriceCacheFromDB(ctx context.Context, repo *repository.Repository, cache *market.PriceCache) error {
 	assets, err := repo.GetDistinctPriceAssets(ctx)
 	if err != nil {
 		log.Printf("[price_cache] Failed to get assets: %v", err)
-		return
+		return err
 	}
 	for _, asset := range assets {
 		prices, err := repo.GetMarketPriceHistory(ctx, asset, "USD", 8760)
@@ -1429,6 +1647,10 @@ func loadPriceCacheFromDB(ctx context.Context, repo *repository.Repository, cach
 			daily[i] = market.DailyPrice{Date: p.AsOf.UTC().Truncate(24 * time.Hour), Price: p.Price}
 		}
 		cache.Load(asset, daily)
+		if n := len(prices); n > 0 {
+			last := prices[n-1]
+			cache.Observe(asset, market.PriceQuote{Asset: asset, Currency: last.Currency, Price: last.Price, Source: last.Source, AsOf: last.AsOf})
+		}
 		log.Printf("[price_cache] Loaded %d prices for %s", len(daily), asset)
 	}
 	// Stablecoins always $1
@@ -1436,8 +1658,10 @@ func loadPriceCacheFromDB(ctx context.Context, repo *repository.Repository, cach
 		if _, ok := cache.GetLatestPrice(stable); !ok {
 			today := time.Now().UTC().Truncate(24 * time.Hour)
 			cache.Load(stable, []market.DailyPrice{{Date: today, Price: 1.0}})
+			cache.Observe(stable, market.PriceQuote{Asset: stable, Currency: "USD", Price: 1.0, Source: market.SourceStatic, AsOf: today})
 		}
 	}
+	return nil
 }
 
 func getMarketSymbols(ctx context.Context, repo *repository.Repository) []string {
@@ -31,6 +31,24 @@ CREATE TABLE IF NOT EXISTS app.indexing_checkpoints (
     updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
+-- 1.1.a Backward ingester completion markers, one per (service, height range)
+-- so several history instances covering different spork ranges report separately.
+CREATE TABLE IF NOT EXISTS app.backfill_completions (
+    service_name TEXT NOT NULL,
+    start_height BIGINT NOT NULL,    -- the instance's START_BLOCK (0 = from its first checkpoint)
+    floor_height BIGINT NOT NULL,    -- lowest height indexed
+    reason       TEXT NOT NULL,      -- genesis, stop_height, spork_root
+    completed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (service_name, start_height, floor_height)
+);
+
+-- 1.1.b Processor output schema versions (bumped in code to force re-derivation)
+CREATE TABLE IF NOT EXISTS app.processor_schema_versions (
+    processor_name TEXT PRIMARY KEY,
+    version        INT NOT NULL,
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
 -- 1.2 Work leasing (coordinated concurrency)
 -- Lease a height range [from_height, to_height) for a worker instance.
 CREATE TABLE IF NOT EXISTS app.worker_leases (
@@ -79,6 +97,22 @@ CREATE INDEX IF NOT EXISTS idx_indexing_errors_active
 CREATE INDEX IF NOT EXISTS idx_indexing_errors_dedupe
   ON raw.indexing_errors(worker_name, block_height, transaction_id, error_hash);
 
+-- Incremented by repair_indexing_anomalies on every failed repair attempt.
+ALTER TABLE raw.indexing_errors ADD COLUMN IF NOT EXISTS repair_attempts INT NOT NULL DEFAULT 0;
+
+-- 1.4 Block reorg audit log: written by SaveBatch when an upsert replaces the
+-- stored block id at a height, so the overwritten id is not lost.
+CREATE TABLE IF NOT EXISTS app.block_reorgs (
+    id          BIGSERIAL PRIMARY KEY,
+    height      BIGINT NOT NULL,
+    old_id      BYTEA NOT NULL,
+    new_id      BYTEA NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_block_reorgs_detected
+  ON app.block_reorgs (detected_at DESC);
+
 -- ─────────────────────────────────────────────────────────────────────────────
 -- 2) Partition helper (on-demand partition creation)
 -- ─────────────────────────────────────────────────────────────────────────────
@@ -176,6 +210,7 @@ CREATE TABLE IF NOT EXISTS raw.block_lookup (
     timestamp TIMESTAMPTZ
 );
 CREATE INDEX IF NOT EXISTS idx_block_lookup_height ON raw.block_lookup(height);
+CREATE INDEX IF NOT EXISTS idx_block_lookup_timestamp ON raw.block_lookup(timestamp);
 
 -- 3.2 Transactions (5M partitions)
 -- NOTE: PK must include partition key to enforce uniqueness across partitions.
@@ -213,6 +248,9 @@ ALTER TABLE IF EXISTS raw.transactions
   ADD COLUMN IF NOT EXISTS proposer_key_index INT;
 ALTER TABLE IF EXISTS raw.transactions
   ADD COLUMN IF NOT EXISTS proposer_sequence_number BIGINT;
+-- Per-signer key usage decoded from the envelope: [{address, key_index, roles, kind, sequence_number?}]
+ALTER TABLE IF EXISTS raw.transactions
+  ADD COLUMN IF NOT EXISTS signers JSONB;
 
 -- 3.2.a Tx lookup for fast "by tx id" queries
 CREATE TABLE IF NOT EXISTS raw.tx_lookup (
@@ -222,6 +260,14 @@ CREATE TABLE IF NOT EXISTS raw.tx_lookup (
     timestamp    TIMESTAMPTZ
 );
 CREATE INDEX IF NOT EXISTS idx_tx_lookup_height ON raw.tx_lookup(block_height);
+
+-- Collections (only written with STORE_COLLECTIONS=true; one RPC per collection guarantee)
+CREATE TABLE IF NOT EXISTS raw.collections (
+    id              BYTEA PRIMARY KEY,
+    block_height    BIGINT NOT NULL,
+    transaction_ids BYTEA[] NOT NULL DEFAULT '{}'
+);
+CREATE INDEX IF NOT EXISTS idx_collections_height ON raw.collections(block_height);
 ALTER TABLE IF EXISTS raw.tx_lookup
   DROP COLUMN IF EXISTS evm_hash;
 
@@ -229,6 +275,10 @@ ALTER TABLE IF EXISTS raw.tx_lookup
 CREATE INDEX IF NOT EXISTS idx_transactions_pagination
   ON raw.transactions (block_height DESC, transaction_index DESC, id DESC);
 
+-- Script usage counts (/flow/script/{hash}, /flow/script/popular)
+CREATE INDEX IF NOT EXISTS idx_transactions_script_hash
+  ON raw.transactions (script_hash) WHERE script_hash IS NOT NULL;
+
 -- 3.3 Events (10M partitions - high volume)
 CREATE TABLE IF NOT EXISTS raw.events (
     block_height      BIGINT NOT NULL,
@@ -246,6 +296,12 @@ CREATE TABLE IF NOT EXISTS raw.events (
     PRIMARY KEY (block_height, transaction_id, event_index)
 ) PARTITION BY RANGE (block_height);
 
+-- Compressed payload written instead of payload when EVENT_PAYLOAD_COMPRESSION
+-- is set: a format byte (0 = plain JSON, 1 = gzip, 2 = zstd) followed by the
+-- encoded JSON-CDC. Rows keep exactly one of the two columns populated.
+ALTER TABLE IF EXISTS raw.events
+  ADD COLUMN IF NOT EXISTS payload_compressed BYTEA;
+
 -- Note: avoid heavy secondary indexes on raw.events in early phase.
 
 
@@ -297,6 +353,8 @@ CREATE INDEX IF NOT EXISTS idx_nft_transfers_to    ON app.nft_transfers(to_addre
 CREATE INDEX IF NOT EXISTS idx_nft_transfers_token ON app.nft_transfers(token_contract_address);
 CREATE INDEX IF NOT EXISTS idx_nft_transfers_height ON app.nft_transfers(block_height DESC, event_index DESC);
 CREATE INDEX IF NOT EXISTS idx_nft_transfers_token_id ON app.nft_transfers(token_id);
+-- Per-item provenance lookups (GetNFTTransferHistory).
+CREATE INDEX IF NOT EXISTS idx_nft_transfers_contract_token_id ON app.nft_transfers(token_contract_address, token_id, block_height, event_index);
 
 ALTER TABLE IF EXISTS app.evm_transactions
   ADD COLUMN IF NOT EXISTS event_index INT;
@@ -404,6 +462,29 @@ CREATE TABLE IF NOT EXISTS app.evm_tx_hashes (
 CREATE INDEX IF NOT EXISTS idx_evm_tx_hashes_hash ON app.evm_tx_hashes(evm_hash);
 CREATE INDEX IF NOT EXISTS idx_evm_tx_hashes_tx ON app.evm_tx_hashes(transaction_id);
 
+-- 4.4 EVM logs, decoded from EVM.TransactionExecuted (one row per log; log_index
+-- is the position within the EVM transaction).
+CREATE TABLE IF NOT EXISTS app.evm_logs (
+    evm_hash          BYTEA NOT NULL,
+    log_index         INT NOT NULL,
+    block_height      BIGINT NOT NULL,
+    transaction_id    BYTEA NOT NULL,
+    address           BYTEA NOT NULL,
+    topic0            BYTEA,
+    topic1            BYTEA,
+    topic2            BYTEA,
+    topic3            BYTEA,
+    data              BYTEA,
+    timestamp         TIMESTAMPTZ NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (evm_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_evm_logs_address ON app.evm_logs(address, block_height DESC);
+CREATE INDEX IF NOT EXISTS idx_evm_logs_topic0 ON app.evm_logs(topic0, block_height DESC);
+CREATE INDEX IF NOT EXISTS idx_evm_logs_height ON app.evm_logs(block_height);
+
 -- ─────────────────────────────────────────────────────────────────────────────
 -- 5) STATE TABLES (App DB)
 -- ─────────────────────────────────────────────────────────────────────────────
@@ -473,6 +554,19 @@ CREATE TABLE IF NOT EXISTS app.contract_versions (
 CREATE INDEX IF NOT EXISTS idx_contract_versions_dedup
   ON app.contract_versions (address, name, block_height);
 
+-- 5.2c Contract Changes: one row per AccountContractAdded/Updated/Removed event,
+-- the source for the contract timeline (contract_versions has no removals).
+CREATE TABLE IF NOT EXISTS app.contract_changes (
+    address        BYTEA NOT NULL,
+    name           TEXT NOT NULL,
+    block_height   BIGINT NOT NULL,
+    transaction_id BYTEA NOT NULL,
+    event_index    INT NOT NULL,
+    change         TEXT NOT NULL,       -- added, updated, removed
+    timestamp      TIMESTAMPTZ,
+    PRIMARY KEY (address, name, block_height, transaction_id, event_index)
+);
+
 -- 5.3 Address Stats
 CREATE TABLE IF NOT EXISTS app.address_stats (
     address            BYTEA PRIMARY KEY,
@@ -483,6 +577,18 @@ CREATE TABLE IF NOT EXISTS app.address_stats (
     updated_at         TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
+-- Per-role tallies, bumped only for newly inserted app.address_transactions rows
+-- so reprocessing a range does not double count.
+ALTER TABLE app.address_stats ADD COLUMN IF NOT EXISTS payer_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE app.address_stats ADD COLUMN IF NOT EXISTS proposer_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE app.address_stats ADD COLUMN IF NOT EXISTS authorizer_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE app.address_stats ADD COLUMN IF NOT EXISTS transfer_sent_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE app.address_stats ADD COLUMN IF NOT EXISTS transfer_received_count BIGINT NOT NULL DEFAULT 0;
+-- Set by CompactAddressActivity: app.address_transactions rows below this height
+-- were trimmed for the address; the counters above still include them.
+ALTER TABLE app.address_stats ADD COLUMN IF NOT EXISTS compacted_below_height BIGINT;
+ALTER TABLE app.address_stats ADD COLUMN IF NOT EXISTS compacted_at TIMESTAMPTZ;
+
 -- ─────────────────────────────────────────────────────────────────────────────
 -- 6) Initial partitions (keep minimal; partition manager should extend later)
 -- ─────────────────────────────────────────────────────────────────────────────
@@ -532,6 +638,17 @@ ALTER TABLE IF EXISTS app.daily_stats
   ADD COLUMN IF NOT EXISTS ft_transfer_count BIGINT DEFAULT 0;
 ALTER TABLE IF EXISTS app.daily_stats
   ADD COLUMN IF NOT EXISTS nft_transfer_count BIGINT DEFAULT 0;
+ALTER TABLE IF EXISTS app.daily_stats
+  ADD COLUMN IF NOT EXISTS accounts_created BIGINT DEFAULT 0; -- flow.AccountCreated events
+
+-- Events per type per UTC day (refreshed alongside daily_stats), backing /insights/events/top.
+CREATE TABLE IF NOT EXISTS app.daily_event_type_counts (
+    date       DATE NOT NULL,
+    event_type TEXT NOT NULL,
+    count      BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (date, event_type)
+);
 
 CREATE TABLE IF NOT EXISTS analytics.daily_metrics (
     date                 DATE PRIMARY KEY,
@@ -707,6 +824,11 @@ ALTER TABLE IF EXISTS app.nft_ownership
   DROP CONSTRAINT IF EXISTS nft_ownership_pkey;
 ALTER TABLE IF EXISTS app.nft_ownership
   ADD CONSTRAINT nft_ownership_pkey PRIMARY KEY (contract_address, contract_name, nft_id);
+-- Keyset pages of one owner's items in a collection (numeric-aware nft_id order,
+-- see nftIDOrderKey in internal/repository/nft_owner_items.go).
+CREATE INDEX IF NOT EXISTS idx_nft_ownership_owner_items
+  ON app.nft_ownership (owner, contract_address, contract_name,
+    (nft_id !~ '^[0-9]+$'), (CASE WHEN nft_id ~ '^[0-9]+$' THEN length(nft_id) ELSE 0 END), nft_id);
 
 CREATE TABLE IF NOT EXISTS app.tx_contracts (
     transaction_id      BYTEA NOT NULL,
@@ -718,6 +840,17 @@ CREATE TABLE IF NOT EXISTS app.tx_contracts (
 );
 ALTER TABLE app.tx_contracts ADD COLUMN IF NOT EXISTS block_height BIGINT;
 CREATE INDEX IF NOT EXISTS idx_tx_contracts_ident_height ON app.tx_contracts (contract_identifier, block_height DESC);
+CREATE INDEX IF NOT EXISTS idx_tx_contracts_height ON app.tx_contracts (block_height);
+
+-- Transactions importing each contract per UTC day (refreshed alongside daily_stats),
+-- backing /insights/contracts/top.
+CREATE TABLE IF NOT EXISTS app.daily_contract_tx_counts (
+    date                DATE NOT NULL,
+    contract_identifier TEXT NOT NULL,
+    tx_count            BIGINT NOT NULL DEFAULT 0,
+    updated_at          TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (date, contract_identifier)
+);
 
 CREATE TABLE IF NOT EXISTS app.script_imports (
     script_hash         VARCHAR(64) NOT NULL,
@@ -817,6 +950,57 @@ GROUP BY contract_address, contract_name;
 CREATE UNIQUE INDEX IF NOT EXISTS idx_nft_collection_stats_pk ON app.nft_collection_stats (contract_address, contract_name);
 CREATE INDEX IF NOT EXISTS idx_nft_collection_stats_holders ON app.nft_collection_stats (holder_count DESC, contract_address ASC);
 
+-- Running NFT mint/burn counts per collection (maintained by UpsertNFTTransfers)
+CREATE TABLE IF NOT EXISTS app.nft_collection_supply (
+    contract_address BYTEA NOT NULL,
+    contract_name    TEXT NOT NULL,
+    minted_count     BIGINT NOT NULL DEFAULT 0,
+    burned_count     BIGINT NOT NULL DEFAULT 0,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (contract_address, contract_name)
+);
+
+-- NFT marketplace listings and sales (maintained by nft_sales_worker).
+-- A completion processed before its listing leaves a placeholder row with only
+-- status/completed_height set; the listing event fills in the rest.
+CREATE TABLE IF NOT EXISTS app.nft_listings (
+    marketplace        TEXT NOT NULL,
+    listing_id         TEXT NOT NULL,
+    seller             BYTEA,
+    collection_address BYTEA,
+    collection_name    TEXT NOT NULL DEFAULT '',
+    nft_id             TEXT,
+    price              NUMERIC(78,18),
+    payment_token      TEXT,
+    status             TEXT NOT NULL DEFAULT 'available',
+    block_height       BIGINT NOT NULL,
+    transaction_id     BYTEA,
+    timestamp          TIMESTAMPTZ,
+    completed_height   BIGINT,
+    PRIMARY KEY (marketplace, listing_id)
+);
+CREATE INDEX IF NOT EXISTS idx_nft_listings_floor
+  ON app.nft_listings (collection_address, collection_name, price) WHERE status = 'available';
+
+CREATE TABLE IF NOT EXISTS app.nft_sales (
+    block_height       BIGINT NOT NULL,
+    transaction_id     BYTEA NOT NULL,
+    event_index        INT NOT NULL,
+    marketplace        TEXT NOT NULL,
+    listing_id         TEXT NOT NULL,
+    collection_address BYTEA NOT NULL,
+    collection_name    TEXT NOT NULL DEFAULT '',
+    nft_id             TEXT NOT NULL,
+    price              NUMERIC(78,18) NOT NULL DEFAULT 0,
+    payment_token      TEXT,
+    seller             BYTEA,
+    buyer              BYTEA,
+    timestamp          TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (block_height, transaction_id, event_index)
+);
+CREATE INDEX IF NOT EXISTS idx_nft_sales_collection
+  ON app.nft_sales (collection_address, collection_name, timestamp DESC);
+
 -- Account labels/tags (whale, service account, etc.)
 CREATE TABLE IF NOT EXISTS app.account_labels (
     address    TEXT NOT NULL,
@@ -1016,6 +1200,12 @@ ON CONFLICT (contract_address, contract_name) DO NOTHING;
 ALTER TABLE app.ft_tokens ADD COLUMN IF NOT EXISTS market_symbol TEXT;
 ALTER TABLE app.ft_tokens ADD COLUMN IF NOT EXISTS coingecko_id TEXT;
 
+-- Token directory search (?q=): case-insensitive prefix match on symbol/name
+CREATE INDEX IF NOT EXISTS idx_ft_tokens_symbol_prefix
+  ON app.ft_tokens (lower(symbol) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_ft_tokens_name_prefix
+  ON app.ft_tokens (lower(name) text_pattern_ops);
+
 -- Seed known market symbols (idempotent)
 UPDATE app.ft_tokens SET market_symbol = 'FLOW',    coingecko_id = 'flow'                WHERE contract_name = 'FlowToken'   AND (market_symbol IS NULL OR market_symbol = '');
 UPDATE app.ft_tokens SET market_symbol = 'USDC',    coingecko_id = 'usd-coin'            WHERE symbol IN ('USDC.e', 'USDC')  AND (market_symbol IS NULL OR market_symbol = '');
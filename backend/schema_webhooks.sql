@@ -155,6 +155,25 @@ ALTER TABLE public.endpoints ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DE
 -- Migration: add signing_secret for HMAC webhook verification
 ALTER TABLE public.endpoints ADD COLUMN IF NOT EXISTS signing_secret TEXT;
 
+-- Migration: delivery failure tracking (endpoints are disabled after repeated failures)
+ALTER TABLE public.endpoints ADD COLUMN IF NOT EXISTS consecutive_failures INT NOT NULL DEFAULT 0;
+ALTER TABLE public.endpoints ADD COLUMN IF NOT EXISTS last_failure_at TIMESTAMPTZ;
+ALTER TABLE public.endpoints ADD COLUMN IF NOT EXISTS disabled_reason TEXT;
+
+-- Deliveries that still failed after all retries.
+CREATE TABLE IF NOT EXISTS public.webhook_dead_letters (
+    id              UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    subscription_id UUID REFERENCES public.subscriptions(id) ON DELETE SET NULL,
+    endpoint_id     UUID REFERENCES public.endpoints(id) ON DELETE CASCADE,
+    event_type      TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    error           TEXT NOT NULL,
+    attempts        INT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_endpoint ON public.webhook_dead_letters(endpoint_id, created_at DESC);
+ALTER TABLE public.webhook_dead_letters ENABLE ROW LEVEL SECURITY;
+
 -- Backfill: ensure every existing user has a personal team + active membership.
 WITH users_without_active_team AS (
     SELECT u.id, u.email
@@ -210,6 +229,9 @@ CREATE POLICY users_own_subscriptions ON public.subscriptions FOR ALL USING (aut
 CREATE POLICY users_own_logs ON public.delivery_logs FOR ALL USING (
     endpoint_id IN (SELECT id FROM public.endpoints WHERE user_id = auth.uid())
 );
+CREATE POLICY users_own_dead_letters ON public.webhook_dead_letters FOR ALL USING (
+    endpoint_id IN (SELECT id FROM public.endpoints WHERE user_id = auth.uid())
+);
 
 CREATE POLICY users_own_teams ON public.teams FOR SELECT USING (
     id IN (
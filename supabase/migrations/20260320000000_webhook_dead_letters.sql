@@ -0,0 +1,24 @@
+-- Webhook delivery failure tracking: consecutive failure counter on endpoints
+-- (used to auto-disable them) and a dead-letter table for exhausted retries.
+ALTER TABLE public.endpoints ADD COLUMN IF NOT EXISTS consecutive_failures INT NOT NULL DEFAULT 0;
+ALTER TABLE public.endpoints ADD COLUMN IF NOT EXISTS last_failure_at TIMESTAMPTZ;
+ALTER TABLE public.endpoints ADD COLUMN IF NOT EXISTS disabled_reason TEXT;
+
+-- Deliveries that still failed after all retries.
+CREATE TABLE IF NOT EXISTS public.webhook_dead_letters (
+    id              UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    subscription_id UUID REFERENCES public.subscriptions(id) ON DELETE SET NULL,
+    endpoint_id     UUID REFERENCES public.endpoints(id) ON DELETE CASCADE,
+    event_type      TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    error           TEXT NOT NULL,
+    attempts        INT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_endpoint ON public.webhook_dead_letters(endpoint_id, created_at DESC);
+ALTER TABLE public.webhook_dead_letters ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS users_own_dead_letters ON public.webhook_dead_letters;
+CREATE POLICY users_own_dead_letters ON public.webhook_dead_letters FOR ALL USING (
+    endpoint_id IN (SELECT id FROM public.endpoints WHERE user_id = auth.uid())
+);